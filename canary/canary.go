@@ -132,6 +132,7 @@ func (c *canaryImpl) startWorker() error {
 	canaryWorker := worker.New(c.canaryClient.Client, taskListName, options)
 	registerBatch(canaryWorker)
 	registerCancellation(canaryWorker)
+	registerChaos(canaryWorker)
 	registerConcurrentExec(canaryWorker)
 	registerCron(canaryWorker)
 	registerEcho(canaryWorker)
@@ -177,7 +178,7 @@ func (c *canaryImpl) createDomain() error {
 	name := c.canaryDomain
 	desc := "Domain for running cadence canary workflows"
 	owner := "canary"
-	return c.canaryClient.createDomain(name, desc, owner, enums.ArchivalStatusDisabled)
+	return c.canaryClient.createDomain(name, desc, owner, enums.ArchivalStatusDisabled, chaosFailoverClusters)
 }
 
 func (c *canaryImpl) createArchivalDomain() error {
@@ -185,7 +186,7 @@ func (c *canaryImpl) createArchivalDomain() error {
 	desc := "Domain used by cadence canary workflows to verify archival"
 	owner := "canary"
 	archivalStatus := enums.ArchivalStatusEnabled
-	return c.archivalClient.createDomain(name, desc, owner, archivalStatus)
+	return c.archivalClient.createDomain(name, desc, owner, archivalStatus, nil)
 }
 
 // Override worker options to create large number of pollers to improve the chances of activities getting sync matched
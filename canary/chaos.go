@@ -0,0 +1,358 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package canary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.temporal.io/temporal"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/workflowservice"
+	"go.temporal.io/temporal/activity"
+	"go.temporal.io/temporal/workflow"
+	"go.uber.org/zap"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+)
+
+const (
+	chaosSignalName       = "chaos.signal"
+	chaosSignalCount      = 10
+	chaosSignalInterval   = time.Second
+	chaosActivityCount    = 10
+	chaosTimerInterval    = 2 * time.Second
+	chaosFaultCount       = 3
+	chaosFaultInterval    = 5 * time.Second
+	chaosFailoverDuration = 10 * time.Second
+)
+
+var (
+	// chaosFailoverClusters are the clusters of the canary domain, the domain
+	// is registered active in the first one, set by enableChaosScenarios
+	chaosFailoverClusters []string
+
+	errChaosUnexpectedResult      = errors.New("unexpected chaos activity result")
+	errChaosSignalOutOfOrder      = errors.New("chaos signal received out of order")
+	errChaosFailoverNotConfigured = errors.New("chaos failover clusters are not configured")
+)
+
+func registerChaos(r registrar) {
+	registerWorkflow(r, chaosShardMovementWorkflow, wfTypeChaosShardMovement)
+	registerWorkflow(r, chaosTaskListUnloadWorkflow, wfTypeChaosTaskListUnload)
+	registerWorkflow(r, chaosFailoverWorkflow, wfTypeChaosFailover)
+	registerActivity(r, chaosWorkActivity, activityTypeChaosWork)
+	registerActivity(r, chaosSignalActivity, activityTypeChaosSignal)
+	registerActivity(r, chaosVerifyActivity, activityTypeChaosVerify)
+	registerActivity(r, closeShardActivity, activityTypeCloseShard)
+	registerActivity(r, unloadTaskListActivity, activityTypeUnloadTaskList)
+	registerActivity(r, failoverActivity, activityTypeFailover)
+}
+
+// enableChaosScenarios adds the chaos scenarios to the sanity workflow,
+// the failover scenario is only added if failover clusters are configured
+func enableChaosScenarios(cfg *Chaos) {
+	sanityChildWFList = append(sanityChildWFList, wfTypeChaosShardMovement, wfTypeChaosTaskListUnload)
+	if len(cfg.FailoverClusters) > 1 {
+		chaosFailoverClusters = cfg.FailoverClusters
+		sanityChildWFList = append(sanityChildWFList, wfTypeChaosFailover)
+	}
+}
+
+// chaosShardMovementWorkflow runs the chaos scenario while the history shard
+// of the workflow is closed, which moves the shard to another host or reloads it
+func chaosShardMovementWorkflow(ctx workflow.Context, scheduledTimeNanos int64, domain string) error {
+	return runChaosScenario(ctx, wfTypeChaosShardMovement, activityTypeCloseShard, scheduledTimeNanos, domain)
+}
+
+// chaosTaskListUnloadWorkflow runs the chaos scenario while the decision
+// and activity task lists of the canary are unloaded from matching
+func chaosTaskListUnloadWorkflow(ctx workflow.Context, scheduledTimeNanos int64, domain string) error {
+	return runChaosScenario(ctx, wfTypeChaosTaskListUnload, activityTypeUnloadTaskList, scheduledTimeNanos, domain)
+}
+
+// chaosFailoverWorkflow runs the chaos scenario while the domain
+// is failed over to another cluster and back
+func chaosFailoverWorkflow(ctx workflow.Context, scheduledTimeNanos int64, domain string) error {
+	return runChaosScenario(ctx, wfTypeChaosFailover, activityTypeFailover, scheduledTimeNanos, domain)
+}
+
+// runChaosScenario executes activities, timers and signals while the fault activity
+// injects faults, then verifies that every activity completed and every timer fired
+// exactly once and that the signals are received in the order they are sent
+func runChaosScenario(ctx workflow.Context, wfType string, faultActivityType string, scheduledTimeNanos int64, domain string) error {
+	profile, err := beginWorkflow(ctx, wfType, scheduledTimeNanos)
+	if err != nil {
+		return err
+	}
+
+	execInfo := workflow.GetInfo(ctx).WorkflowExecution
+	aCtx := workflow.WithActivityOptions(ctx, newChaosActivityOptions())
+	faultFuture := workflow.ExecuteActivity(aCtx, faultActivityType, workflow.Now(ctx).UnixNano(), domain, execInfo)
+
+	// signals are not retried as a retry would resend them out of order
+	sCtx := workflow.WithActivityOptions(ctx, newActivityOptions())
+	signalFuture := workflow.ExecuteActivity(sCtx, activityTypeChaosSignal, workflow.Now(ctx).UnixNano(), execInfo)
+
+	receiveFuture, receiveSettable := workflow.NewFuture(ctx)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		receiveSettable.Set(nil, receiveChaosSignals(ctx))
+	})
+
+	for i := 0; i < chaosActivityCount; i++ {
+		var result int
+		err = workflow.ExecuteActivity(aCtx, activityTypeChaosWork, workflow.Now(ctx).UnixNano(), i).Get(ctx, &result)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("chaos work activity failed", zap.Error(err))
+			return profile.end(err)
+		}
+		if result != i {
+			workflow.GetLogger(ctx).Error("unexpected chaos work activity result", zap.Int("expected", i), zap.Int("result", result))
+			return profile.end(errChaosUnexpectedResult)
+		}
+		if err = workflow.Sleep(ctx, chaosTimerInterval); err != nil {
+			return profile.end(err)
+		}
+	}
+
+	for _, future := range []workflow.Future{faultFuture, signalFuture, receiveFuture} {
+		if err = future.Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("chaos scenario failed", zap.String("scenario", wfType), zap.Error(err))
+			return profile.end(err)
+		}
+	}
+
+	err = workflow.ExecuteActivity(aCtx, activityTypeChaosVerify, workflow.Now(ctx).UnixNano(), execInfo).Get(ctx, nil)
+	return profile.end(err)
+}
+
+// newChaosActivityOptions builds activityOptions which retry the activities
+// failed or timed out because of the injected faults
+func newChaosActivityOptions() workflow.ActivityOptions {
+	opts := newActivityOptions()
+	opts.RetryPolicy = &temporal.RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2,
+		MaximumInterval:    10 * time.Second,
+		ExpirationInterval: opts.ScheduleToCloseTimeout,
+		MaximumAttempts:    5,
+	}
+	return opts
+}
+
+// receiveChaosSignals receives the chaos signals and checks
+// that they are received in the order they are sent
+func receiveChaosSignals(ctx workflow.Context) error {
+	sigCh := workflow.GetSignalChannel(ctx, chaosSignalName)
+	for expected := 1; expected <= chaosSignalCount; expected++ {
+		var seq int
+		sigCh.Receive(ctx, &seq)
+		if seq != expected {
+			workflow.GetLogger(ctx).Error("chaos signal received out of order", zap.Int("expected", expected), zap.Int("received", seq))
+			return errChaosSignalOutOfOrder
+		}
+	}
+	return nil
+}
+
+// chaosWorkActivity returns its input so that the workflow can check the result
+func chaosWorkActivity(ctx context.Context, scheduledTimeNanos int64, input int) (int, error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeChaosWork, scheduledTimeNanos)
+	defer recordActivityEnd(scope, sw, nil)
+	return input, nil
+}
+
+// chaosSignalActivity sends sequence numbered signals to the workflow
+func chaosSignalActivity(ctx context.Context, scheduledTimeNanos int64, execInfo workflow.Execution) (err error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeChaosSignal, scheduledTimeNanos)
+	defer func() { recordActivityEnd(scope, sw, err) }()
+
+	client := getActivityContext(ctx).cadence
+	for seq := 1; seq <= chaosSignalCount; seq++ {
+		err = client.SignalWorkflow(context.Background(), execInfo.ID, execInfo.RunID, chaosSignalName, seq)
+		if err != nil {
+			return err
+		}
+		time.Sleep(chaosSignalInterval)
+	}
+	return nil
+}
+
+// chaosVerifyActivity verifies the history of the workflow
+func chaosVerifyActivity(ctx context.Context, scheduledTimeNanos int64, execInfo workflow.Execution) (err error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeChaosVerify, scheduledTimeNanos)
+	defer func() { recordActivityEnd(scope, sw, err) }()
+
+	client := getActivityContext(ctx).cadence
+	events, err := getMyHistory(client, execInfo, scope)
+	if err != nil {
+		return err
+	}
+	return verifyChaosHistory(events, chaosSignalCount)
+}
+
+// closeShardActivity closes the history shard of the workflow repeatedly
+func closeShardActivity(ctx context.Context, scheduledTimeNanos int64, domain string, execInfo workflow.Execution) (err error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeCloseShard, scheduledTimeNanos)
+	defer func() { recordActivityEnd(scope, sw, err) }()
+
+	admin := getActivityContext(ctx).cadence.Admin
+	for i := 0; i < chaosFaultCount; i++ {
+		var resp *adminservice.DescribeWorkflowExecutionResponse
+		resp, err = admin.DescribeWorkflowExecution(context.Background(), &adminservice.DescribeWorkflowExecutionRequest{
+			Domain:    domain,
+			Execution: &commonproto.WorkflowExecution{WorkflowId: execInfo.ID, RunId: execInfo.RunID},
+		})
+		if err != nil {
+			return err
+		}
+		var shardID int
+		shardID, err = strconv.Atoi(resp.GetShardId())
+		if err != nil {
+			return err
+		}
+		if _, err = admin.CloseShard(context.Background(), &adminservice.CloseShardRequest{ShardID: int32(shardID)}); err != nil {
+			return err
+		}
+		time.Sleep(chaosFaultInterval)
+	}
+	return nil
+}
+
+// unloadTaskListActivity unloads the decision and activity task lists of the canary repeatedly
+func unloadTaskListActivity(ctx context.Context, scheduledTimeNanos int64, domain string, execInfo workflow.Execution) (err error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeUnloadTaskList, scheduledTimeNanos)
+	defer func() { recordActivityEnd(scope, sw, err) }()
+
+	admin := getActivityContext(ctx).cadence.Admin
+	for i := 0; i < chaosFaultCount; i++ {
+		for _, taskListType := range []enums.TaskListType{enums.TaskListTypeDecision, enums.TaskListTypeActivity} {
+			_, err = admin.UnloadTaskList(context.Background(), &adminservice.UnloadTaskListRequest{
+				Domain:       domain,
+				TaskList:     &commonproto.TaskList{Name: taskListName},
+				TaskListType: taskListType,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		time.Sleep(chaosFaultInterval)
+	}
+	return nil
+}
+
+// failoverActivity fails the domain over to the next cluster and back to the
+// cluster the domain is registered active in
+func failoverActivity(ctx context.Context, scheduledTimeNanos int64, domain string, execInfo workflow.Execution) (err error) {
+	scope := activity.GetMetricsScope(ctx)
+	scope, sw := recordActivityStart(scope, activityTypeFailover, scheduledTimeNanos)
+	defer func() { recordActivityEnd(scope, sw, err) }()
+
+	if len(chaosFailoverClusters) < 2 {
+		return errChaosFailoverNotConfigured
+	}
+	client := getActivityContext(ctx).cadence
+	resp, err := client.Describe(context.Background(), domain)
+	if err != nil {
+		return err
+	}
+	activeCluster := resp.GetReplicationConfiguration().GetActiveClusterName()
+	if err = failoverDomain(client, domain, nextCluster(chaosFailoverClusters, activeCluster)); err != nil {
+		return err
+	}
+	time.Sleep(chaosFailoverDuration)
+	return failoverDomain(client, domain, chaosFailoverClusters[0])
+}
+
+func failoverDomain(client cadenceClient, domain string, cluster string) error {
+	return client.Update(context.Background(), &workflowservice.UpdateDomainRequest{
+		Name: domain,
+		ReplicationConfiguration: &commonproto.DomainReplicationConfiguration{
+			ActiveClusterName: cluster,
+		},
+	})
+}
+
+// nextCluster returns the cluster after the active cluster in the list
+func nextCluster(clusters []string, activeCluster string) string {
+	for i, cluster := range clusters {
+		if cluster == activeCluster {
+			return clusters[(i+1)%len(clusters)]
+		}
+	}
+	return clusters[0]
+}
+
+// verifyChaosHistory checks that every activity completed at most once, every timer
+// fired exactly once and that the expected number of chaos signals are received
+func verifyChaosHistory(events []*commonproto.HistoryEvent, signalCount int) error {
+	completedActivities := make(map[int64]int)
+	startedTimers := make(map[string]int)
+	firedTimers := make(map[string]int)
+	signals := 0
+	for _, event := range events {
+		switch event.GetEventType() {
+		case enums.EventTypeActivityTaskCompleted:
+			scheduledID := event.GetActivityTaskCompletedEventAttributes().GetScheduledEventId()
+			completedActivities[scheduledID]++
+			if completedActivities[scheduledID] > 1 {
+				return fmt.Errorf("activity scheduled by event %v completed more than once", scheduledID)
+			}
+		case enums.EventTypeTimerStarted:
+			timerID := event.GetTimerStartedEventAttributes().GetTimerId()
+			startedTimers[timerID]++
+			if startedTimers[timerID] > 1 {
+				return fmt.Errorf("timer %v started more than once", timerID)
+			}
+		case enums.EventTypeTimerFired:
+			timerID := event.GetTimerFiredEventAttributes().GetTimerId()
+			if startedTimers[timerID] == 0 {
+				return fmt.Errorf("timer %v fired without being started", timerID)
+			}
+			firedTimers[timerID]++
+			if firedTimers[timerID] > 1 {
+				return fmt.Errorf("timer %v fired more than once", timerID)
+			}
+		case enums.EventTypeWorkflowExecutionSignaled:
+			if event.GetWorkflowExecutionSignaledEventAttributes().GetSignalName() == chaosSignalName {
+				signals++
+			}
+		}
+	}
+	for timerID := range startedTimers {
+		if firedTimers[timerID] == 0 {
+			return fmt.Errorf("timer %v did not fire", timerID)
+		}
+	}
+	if signals != signalCount {
+		return fmt.Errorf("received %v chaos signals, expected %v", signals, signalCount)
+	}
+	return nil
+}
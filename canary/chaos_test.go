@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package canary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+)
+
+func TestVerifyChaosHistory(t *testing.T) {
+	require.NoError(t, verifyChaosHistory(newChaosHistory(), 2))
+
+	history := append(newChaosHistory(), activityCompletedEvent(5))
+	require.Error(t, verifyChaosHistory(history, 2))
+
+	history = append(newChaosHistory(), timerFiredEvent("1"))
+	require.Error(t, verifyChaosHistory(history, 2))
+
+	history = append(newChaosHistory(), timerStartedEvent("2"))
+	require.Error(t, verifyChaosHistory(history, 2))
+
+	history = append(newChaosHistory(), signaledEvent(chaosSignalName))
+	require.Error(t, verifyChaosHistory(history, 2))
+
+	history = append(newChaosHistory(), signaledEvent("other"))
+	require.NoError(t, verifyChaosHistory(history, 2))
+}
+
+func TestNextCluster(t *testing.T) {
+	clusters := []string{"active", "standby", "other"}
+	require.Equal(t, "standby", nextCluster(clusters, "active"))
+	require.Equal(t, "other", nextCluster(clusters, "standby"))
+	require.Equal(t, "active", nextCluster(clusters, "other"))
+	require.Equal(t, "active", nextCluster(clusters, "unknown"))
+}
+
+func newChaosHistory() []*commonproto.HistoryEvent {
+	return []*commonproto.HistoryEvent{
+		signaledEvent(chaosSignalName),
+		activityCompletedEvent(5),
+		timerStartedEvent("1"),
+		signaledEvent(chaosSignalName),
+		activityCompletedEvent(9),
+		timerFiredEvent("1"),
+	}
+}
+
+func activityCompletedEvent(scheduledID int64) *commonproto.HistoryEvent {
+	return &commonproto.HistoryEvent{
+		EventType: enums.EventTypeActivityTaskCompleted,
+		Attributes: &commonproto.HistoryEvent_ActivityTaskCompletedEventAttributes{
+			ActivityTaskCompletedEventAttributes: &commonproto.ActivityTaskCompletedEventAttributes{ScheduledEventId: scheduledID},
+		},
+	}
+}
+
+func timerStartedEvent(timerID string) *commonproto.HistoryEvent {
+	return &commonproto.HistoryEvent{
+		EventType: enums.EventTypeTimerStarted,
+		Attributes: &commonproto.HistoryEvent_TimerStartedEventAttributes{
+			TimerStartedEventAttributes: &commonproto.TimerStartedEventAttributes{TimerId: timerID},
+		},
+	}
+}
+
+func timerFiredEvent(timerID string) *commonproto.HistoryEvent {
+	return &commonproto.HistoryEvent{
+		EventType: enums.EventTypeTimerFired,
+		Attributes: &commonproto.HistoryEvent_TimerFiredEventAttributes{
+			TimerFiredEventAttributes: &commonproto.TimerFiredEventAttributes{TimerId: timerID},
+		},
+	}
+}
+
+func signaledEvent(signalName string) *commonproto.HistoryEvent {
+	return &commonproto.HistoryEvent{
+		EventType: enums.EventTypeWorkflowExecutionSignaled,
+		Attributes: &commonproto.HistoryEvent_WorkflowExecutionSignaledEventAttributes{
+			WorkflowExecutionSignaledEventAttributes: &commonproto.WorkflowExecutionSignaledEventAttributes{SignalName: signalName},
+		},
+	}
+}
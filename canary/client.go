@@ -25,12 +25,15 @@ import (
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 	"go.temporal.io/temporal/activity"
 	"go.temporal.io/temporal/client"
 	"go.temporal.io/temporal/workflow"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 )
 
 // cadenceClient is an abstraction on top of
@@ -43,11 +46,14 @@ type cadenceClient struct {
 	client.DomainClient
 	// this is the service needed to start the workers
 	Service workflowservice.WorkflowServiceClient
+	// Admin is the admin service used by the chaos scenarios
+	Admin adminservice.AdminServiceClient
 }
 
 // createDomain creates a cadence domain with the given name and description
-// if the domain already exist, this method silently returns success
-func (client *cadenceClient) createDomain(name string, desc string, owner string, archivalStatus enums.ArchivalStatus) error {
+// if the domain already exist, this method silently returns success. The domain
+// is a global domain active in the first cluster if clusters are given
+func (client *cadenceClient) createDomain(name string, desc string, owner string, archivalStatus enums.ArchivalStatus, clusters []string) error {
 	emitMetric := true
 	retention := int32(workflowRetentionDays)
 	if archivalStatus == enums.ArchivalStatusEnabled {
@@ -61,6 +67,13 @@ func (client *cadenceClient) createDomain(name string, desc string, owner string
 		EmitMetric:                             emitMetric,
 		HistoryArchivalStatus:                  archivalStatus,
 	}
+	if len(clusters) > 0 {
+		req.IsGlobalDomain = true
+		req.ActiveClusterName = clusters[0]
+		for _, cluster := range clusters {
+			req.Clusters = append(req.Clusters, &commonproto.ClusterReplicationConfiguration{ClusterName: cluster})
+		}
+	}
 	err := client.Register(context.Background(), req)
 	if err != nil {
 		if _, ok := err.(*serviceerror.DomainAlreadyExists); !ok {
@@ -101,6 +114,7 @@ func newCadenceClient(domain string, runtime *RuntimeContext) (cadenceClient, er
 		Client:       cclient,
 		DomainClient: domainClient,
 		Service:      runtime.service,
+		Admin:        runtime.admin,
 	}, nil
 }
 
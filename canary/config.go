@@ -22,11 +22,13 @@ package canary
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/uber-go/tally"
 	"go.temporal.io/temporal-proto/workflowservice"
 	"go.uber.org/zap"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/common/service/config"
 )
 
@@ -64,6 +66,65 @@ type (
 	Canary struct {
 		Domains  []string `yaml:"domains"`
 		Excludes []string `yaml:"excludes"`
+		// Load turns the canary into a load generator, the canary exits with
+		// an error if the SLO is violated. Optional, the canary runs forever if not set.
+		Load *Load `yaml:"load"`
+		// Chaos adds the chaos scenarios to the sanity workflow. Optional
+		Chaos *Chaos `yaml:"chaos"`
+	}
+
+	// Chaos contains the configuration for the chaos scenarios, which close the
+	// history shard, unload the task lists and fail over the domain while running
+	Chaos struct {
+		// FailoverClusters are the clusters of the canary domains, the domains are registered
+		// as global domains active in the first cluster. The failover scenario only runs if set
+		FailoverClusters []string `yaml:"failoverClusters"`
+	}
+
+	// Load contains the configuration for generating load
+	Load struct {
+		// Duration is the total duration of the load, including the ramp
+		Duration time.Duration `yaml:"duration"`
+		// Concurrency is the max number of open workflows started by the load generator, 0 means no limit
+		Concurrency int `yaml:"concurrency"`
+		// Ramp is the list of stages the rate goes through, the rate changes linearly from the previous
+		// stage (or 0) to the percent of the stage and stays at the percent of the last stage afterwards
+		Ramp []RampStage `yaml:"ramp"`
+		// Scenarios are the workflows to start and their target rate
+		Scenarios []LoadScenario `yaml:"scenarios"`
+		// SLO is the default service level objective of the scenarios
+		SLO SLO `yaml:"slo"`
+		// ReportFile is the path of the json report, the report is only logged if not set
+		ReportFile string `yaml:"reportFile"`
+	}
+
+	// RampStage is a stage of the load ramp
+	RampStage struct {
+		Duration time.Duration `yaml:"duration"`
+		// Percent is the percent of the target rate at the end of the stage
+		Percent float64 `yaml:"percent"`
+	}
+
+	// LoadScenario is a canary workflow started by the load generator
+	LoadScenario struct {
+		// Name is the workflow type, one of the sanity child workflows e.g. workflow.echo
+		Name string `yaml:"name"`
+		// RPS is the target rate of workflow starts per second
+		RPS float64 `yaml:"rps"`
+		// Concurrency is the max number of open workflows of this scenario, 0 means no limit
+		Concurrency int `yaml:"concurrency"`
+		// SLO overrides the default service level objective. Optional
+		SLO *SLO `yaml:"slo"`
+	}
+
+	// SLO is the service level objective of a load scenario, zero values are not checked
+	SLO struct {
+		// SuccessRate is the min ratio of successful workflows to started workflows
+		SuccessRate float64 `yaml:"successRate"`
+		// LatencyP50, LatencyP95 and LatencyP99 are the max start-to-close latency percentiles
+		LatencyP50 time.Duration `yaml:"latencyP50"`
+		LatencyP95 time.Duration `yaml:"latencyP95"`
+		LatencyP99 time.Duration `yaml:"latencyP99"`
 	}
 
 	// Cadence contains the configuration for cadence service
@@ -78,6 +139,57 @@ func (c *Config) Validate() error {
 	if len(c.Canary.Domains) == 0 {
 		return fmt.Errorf("missing value for domains property")
 	}
+	if c.Canary.Chaos != nil {
+		if err := c.Canary.Chaos.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Canary.Load != nil {
+		return c.Canary.Load.Validate()
+	}
+	return nil
+}
+
+// Validate validates chaos configuration
+func (c *Chaos) Validate() error {
+	if len(c.FailoverClusters) == 1 {
+		return fmt.Errorf("failover clusters must contain at least two clusters")
+	}
+	return nil
+}
+
+// Validate validates load configuration
+func (l *Load) Validate() error {
+	if l.Duration <= 0 {
+		return fmt.Errorf("load duration must be positive")
+	}
+	if l.Concurrency < 0 {
+		return fmt.Errorf("load concurrency must not be negative")
+	}
+	var rampDuration time.Duration
+	for _, stage := range l.Ramp {
+		if stage.Duration < 0 || stage.Percent < 0 {
+			return fmt.Errorf("load ramp stage duration and percent must not be negative")
+		}
+		rampDuration += stage.Duration
+	}
+	if rampDuration > l.Duration {
+		return fmt.Errorf("load ramp duration %v exceeds load duration %v", rampDuration, l.Duration)
+	}
+	if len(l.Scenarios) == 0 {
+		return fmt.Errorf("missing value for load scenarios property")
+	}
+	for _, scenario := range l.Scenarios {
+		if !isStringInList(scenario.Name, sanityChildWFList) {
+			return fmt.Errorf("unknown load scenario %v, must be one of %v", scenario.Name, sanityChildWFList)
+		}
+		if scenario.RPS <= 0 {
+			return fmt.Errorf("rps of load scenario %v must be positive", scenario.Name)
+		}
+		if scenario.Concurrency < 0 {
+			return fmt.Errorf("concurrency of load scenario %v must not be negative", scenario.Name)
+		}
+	}
 	return nil
 }
 
@@ -88,6 +200,7 @@ type RuntimeContext struct {
 	metrics  tally.Scope
 	hostPort string
 	service  workflowservice.WorkflowServiceClient
+	admin    adminservice.AdminServiceClient
 }
 
 // NewRuntimeContext builds a runtime context from the config
@@ -96,11 +209,13 @@ func NewRuntimeContext(
 	scope tally.Scope,
 	hostPort string,
 	service workflowservice.WorkflowServiceClient,
+	admin adminservice.AdminServiceClient,
 ) *RuntimeContext {
 	return &RuntimeContext{
 		logger:   logger,
 		metrics:  scope,
 		hostPort: hostPort,
 		service:  service,
+		admin:    admin,
 	}
 }
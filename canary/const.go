@@ -82,6 +82,9 @@ const (
 	wfTypeBatch                = "workflow.batch"
 	wfTypeBatchParent          = "workflow.batch.parent"
 	wfTypeBatchChild           = "workflow.batch.child"
+	wfTypeChaosShardMovement   = "workflow.chaos.shard-movement"
+	wfTypeChaosTaskListUnload  = "workflow.chaos.tasklist-unload"
+	wfTypeChaosFailover        = "workflow.chaos.failover"
 
 	activityTypeEcho               = "activity.echo"
 	activityTypeCron               = "activity.cron"
@@ -104,4 +107,10 @@ const (
 	activityTypeLargeResult        = "activity.largeResult"
 	activityTypeVerifyBatch        = "activity.batch.verify"
 	activityTypeStartBatch         = "activity.batch.start.batch"
+	activityTypeChaosWork          = "activity.chaos.work"
+	activityTypeChaosSignal        = "activity.chaos.signal"
+	activityTypeChaosVerify        = "activity.chaos.verify"
+	activityTypeCloseShard         = "activity.chaos.close-shard"
+	activityTypeUnloadTaskList     = "activity.chaos.unload-tasklist"
+	activityTypeFailover           = "activity.chaos.failover"
 )
@@ -0,0 +1,223 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uber-go/tally"
+	"go.temporal.io/temporal-proto/enums"
+	"go.uber.org/zap"
+)
+
+const (
+	// loadIdleInterval is how often the rate is checked while the ramp is at zero
+	loadIdleInterval = 100 * time.Millisecond
+	// loadMaxScheduleLag is how far the generator may fall behind its schedule
+	// before it skips the missed starts instead of bursting to catch up
+	loadMaxScheduleLag = time.Second
+)
+
+type (
+	// loadGenerator starts the workflows of the load scenarios in one domain
+	// at the configured rate and measures their start-to-close latency
+	loadGenerator struct {
+		client      cadenceClient
+		domain      string
+		config      *Load
+		runtime     *RuntimeContext
+		concurrency chan struct{}
+	}
+)
+
+func newLoadGenerator(domain string, config *Load, rc *RuntimeContext) (*loadGenerator, error) {
+	client, err := newCadenceClient(domain, rc)
+	if err != nil {
+		return nil, err
+	}
+	return &loadGenerator{
+		client:      client,
+		domain:      domain,
+		config:      config,
+		runtime:     rc,
+		concurrency: newConcurrencyLimit(config.Concurrency),
+	}, nil
+}
+
+// run generates load for the configured duration, waits for the started workflows
+// to close and returns the results of all scenarios
+func (g *loadGenerator) run() ([]scenarioReport, error) {
+	desc := "Domain for running cadence canary workflows"
+	if err := g.client.createDomain(g.domain, desc, "canary", enums.ArchivalStatusDisabled, nil); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	stats := make([]*scenarioStats, len(g.config.Scenarios))
+	var wg sync.WaitGroup
+	for i, scenario := range g.config.Scenarios {
+		stats[i] = newScenarioStats()
+		wg.Add(1)
+		go func(scenario LoadScenario, stats *scenarioStats) {
+			defer wg.Done()
+			g.runScenario(start, scenario, stats)
+		}(scenario, stats[i])
+	}
+	wg.Wait()
+
+	elapsed := g.config.Duration
+	reports := make([]scenarioReport, 0, len(g.config.Scenarios))
+	for i, scenario := range g.config.Scenarios {
+		slo := g.config.SLO
+		if scenario.SLO != nil {
+			slo = *scenario.SLO
+		}
+		reports = append(reports, stats[i].report(g.domain, scenario, slo, elapsed))
+	}
+	return reports, nil
+}
+
+// runScenario starts the workflows of a scenario following the ramp until the end of the load
+func (g *loadGenerator) runScenario(start time.Time, scenario LoadScenario, stats *scenarioStats) {
+	scope := g.runtime.metrics.Tagged(map[string]string{"operation": scenario.Name})
+	limit := newConcurrencyLimit(scenario.Concurrency)
+	end := start.Add(g.config.Duration)
+
+	var inflight sync.WaitGroup
+	next := start
+	for {
+		now := time.Now()
+		rate := scenario.RPS * rampFactor(g.config.Ramp, now.Sub(start))
+		if rate <= 0 {
+			time.Sleep(loadIdleInterval)
+			next = time.Now()
+			if !next.Before(end) {
+				break
+			}
+			continue
+		}
+
+		next = next.Add(time.Duration(float64(time.Second) / rate))
+		if wait := next.Sub(now); wait > 0 {
+			time.Sleep(wait)
+		} else if -wait > loadMaxScheduleLag {
+			next = now
+		}
+		if !time.Now().Before(end) {
+			break
+		}
+
+		if !acquireConcurrency(g.concurrency) {
+			recordLoadThrottled(scope, stats)
+			continue
+		}
+		if !acquireConcurrency(limit) {
+			releaseConcurrency(g.concurrency)
+			recordLoadThrottled(scope, stats)
+			continue
+		}
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			defer releaseConcurrency(g.concurrency)
+			defer releaseConcurrency(limit)
+			g.executeWorkflow(scenario.Name, scope, stats)
+		}()
+	}
+	inflight.Wait()
+}
+
+// executeWorkflow starts a scenario workflow and waits for it to close
+func (g *loadGenerator) executeWorkflow(wfType string, scope tally.Scope, stats *scenarioStats) {
+	ctx, cancel := context.WithTimeout(context.Background(), childWorkflowTimeout)
+	defer cancel()
+
+	opts := newWorkflowOptions(concat(wfType, "load."+uuid.New().String()), childWorkflowTimeout)
+	startTime := time.Now()
+	run, err := g.client.ExecuteWorkflow(ctx, opts, wfType, startTime.UnixNano(), g.domain)
+	recordLoadWorkflowStart(scope, stats, err)
+	if err != nil {
+		g.runtime.logger.Warn("load generator failed to start workflow", zap.String("wfType", wfType), zap.Error(err))
+		return
+	}
+
+	err = run.Get(ctx, nil)
+	recordLoadWorkflowEnd(scope, stats, time.Since(startTime), err)
+}
+
+// newConcurrencyLimit returns a semaphore with the given size, nil means no limit
+func newConcurrencyLimit(size int) chan struct{} {
+	if size <= 0 {
+		return nil
+	}
+	return make(chan struct{}, size)
+}
+
+func acquireConcurrency(limit chan struct{}) bool {
+	if limit == nil {
+		return true
+	}
+	select {
+	case limit <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func releaseConcurrency(limit chan struct{}) {
+	if limit != nil {
+		<-limit
+	}
+}
+
+// writeLoadReport logs the SLO report and writes it to the report file if configured
+func writeLoadReport(logger *zap.Logger, report *loadReport, reportFile string) error {
+	for _, r := range report.Scenarios {
+		logger.Info("load scenario result",
+			zap.String("domain", r.Domain),
+			zap.String("scenario", r.Name),
+			zap.Float64("achievedRps", r.AchievedRPS),
+			zap.Float64("successRate", r.SuccessRate),
+			zap.String("latencyP50", r.LatencyP50),
+			zap.String("latencyP95", r.LatencyP95),
+			zap.String("latencyP99", r.LatencyP99),
+			zap.Bool("passed", r.Passed),
+			zap.Strings("violations", r.Violations))
+	}
+	if reportFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(reportFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write load report: %v", err)
+	}
+	return nil
+}
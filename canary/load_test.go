@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package canary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRampFactor(t *testing.T) {
+	require.Equal(t, 1.0, rampFactor(nil, 0))
+
+	ramp := []RampStage{
+		{Duration: 10 * time.Second, Percent: 50},
+		{Duration: 0, Percent: 80},
+		{Duration: 20 * time.Second, Percent: 100},
+	}
+	require.Equal(t, 0.0, rampFactor(ramp, 0))
+	require.InDelta(t, 0.25, rampFactor(ramp, 5*time.Second), 0.0001)
+	require.InDelta(t, 0.8, rampFactor(ramp, 10*time.Second), 0.0001)
+	require.InDelta(t, 0.9, rampFactor(ramp, 20*time.Second), 0.0001)
+	require.Equal(t, 1.0, rampFactor(ramp, time.Minute))
+
+	ramp = []RampStage{{Duration: 10 * time.Second, Percent: 50}}
+	require.InDelta(t, 0.5, rampFactor(ramp, 10*time.Second), 0.0001)
+	require.InDelta(t, 0.5, rampFactor(ramp, time.Minute), 0.0001)
+}
+
+func TestPercentile(t *testing.T) {
+	require.Equal(t, time.Duration(0), percentile(nil, 0.5))
+
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	require.Equal(t, 50*time.Millisecond, percentile(latencies, 0.5))
+	require.Equal(t, 99*time.Millisecond, percentile(latencies, 0.99))
+	require.Equal(t, 100*time.Millisecond, percentile(latencies, 1))
+}
+
+func TestScenarioStatsReport(t *testing.T) {
+	stats := newScenarioStats()
+	for i := 1; i <= 10; i++ {
+		stats.recordStart()
+		stats.recordEnd(time.Duration(i)*time.Second, i != 10)
+	}
+	stats.recordThrottled()
+
+	scenario := LoadScenario{Name: wfTypeEcho, RPS: 1}
+	report := stats.report("canary", scenario, SLO{SuccessRate: 0.9, LatencyP99: 10 * time.Second}, 10*time.Second)
+	require.True(t, report.Passed)
+	require.Equal(t, int64(10), report.Started)
+	require.Equal(t, int64(9), report.Succeeded)
+	require.Equal(t, int64(1), report.Failed)
+	require.Equal(t, int64(1), report.Throttled)
+	require.InDelta(t, 0.9, report.SuccessRate, 0.0001)
+	require.InDelta(t, 1.0, report.AchievedRPS, 0.0001)
+	require.Equal(t, "5s", report.LatencyP50)
+
+	stats.recordStartFailure()
+	report = stats.report("canary", scenario, SLO{SuccessRate: 0.9, LatencyP50: time.Second}, 10*time.Second)
+	require.False(t, report.Passed)
+	require.Len(t, report.Violations, 2)
+
+	report = newScenarioStats().report("canary", scenario, SLO{}, 10*time.Second)
+	require.False(t, report.Passed)
+}
+
+func TestLoadValidate(t *testing.T) {
+	load := &Load{
+		Duration:  time.Minute,
+		Ramp:      []RampStage{{Duration: 30 * time.Second, Percent: 100}},
+		Scenarios: []LoadScenario{{Name: wfTypeEcho, RPS: 10}},
+	}
+	require.NoError(t, load.Validate())
+
+	load.Ramp = []RampStage{{Duration: 2 * time.Minute, Percent: 100}}
+	require.Error(t, load.Validate())
+
+	load.Ramp = nil
+	load.Scenarios = []LoadScenario{{Name: "workflow.unknown", RPS: 10}}
+	require.Error(t, load.Validate())
+
+	load.Scenarios = []LoadScenario{{Name: wfTypeEcho}}
+	require.Error(t, load.Validate())
+}
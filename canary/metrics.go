@@ -47,6 +47,11 @@ const (
 	getWorkflowHistoryFailureCount    = "get-workflow-history.failures"
 	errTimeoutCount                   = "errors.timeout"
 	errIncompatibleVersion            = "errors.incompatibleversion"
+	loadStartedCount                  = "load.started"
+	loadSucceededCount                = "load.succeeded"
+	loadFailedCount                   = "load.failed"
+	loadStartFailureCount             = "load.startworkflow.failures"
+	loadThrottledCount                = "load.throttled"
 )
 
 // latency metrics go here
@@ -59,6 +64,7 @@ const (
 	listArchivedWorkflowsLatency = "latency.list-archived-workflows"
 	getWorkflowHistoryLatency    = "latency.get-workflow-history"
 	timerDriftLatency            = "latency.timer-drift"
+	loadLatency                  = "latency.load.start-to-close"
 )
 
 // workflowMetricsProfile is the state that's needed to
@@ -133,3 +139,33 @@ func recordActivityEnd(scope tally.Scope, sw tally.Stopwatch, err error) {
 	}
 	scope.Counter(successCount).Inc(1)
 }
+
+// recordLoadWorkflowStart emits metrics when the load generator starts a workflow
+func recordLoadWorkflowStart(scope tally.Scope, stats *scenarioStats, err error) {
+	if err != nil {
+		scope.Counter(loadStartFailureCount).Inc(1)
+		stats.recordStartFailure()
+		return
+	}
+	scope.Counter(loadStartedCount).Inc(1)
+	stats.recordStart()
+}
+
+// recordLoadWorkflowEnd emits metrics when a workflow started by the load generator is closed,
+// the start-to-close latency is also kept by stats to compute the SLO report
+func recordLoadWorkflowEnd(scope tally.Scope, stats *scenarioStats, elapsed time.Duration, err error) {
+	scope.Timer(loadLatency).Record(elapsed)
+	if err != nil {
+		scope.Counter(loadFailedCount).Inc(1)
+		stats.recordEnd(elapsed, false)
+		return
+	}
+	scope.Counter(loadSucceededCount).Inc(1)
+	stats.recordEnd(elapsed, true)
+}
+
+// recordLoadThrottled emits metrics when the load generator skips a start due to the concurrency limit
+func recordLoadThrottled(scope tally.Scope, stats *scenarioStats) {
+	scope.Counter(loadThrottledCount).Inc(1)
+	stats.recordThrottled()
+}
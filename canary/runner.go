@@ -21,16 +21,21 @@
 package canary
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.temporal.io/temporal-proto/workflowservice"
 	"go.uber.org/zap"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
 	"github.com/temporalio/temporal/common/rpc"
 )
 
+var errSLOViolated = errors.New("load SLO violated")
+
 type canaryRunner struct {
 	*RuntimeContext
 	config *Canary
@@ -56,6 +61,7 @@ func NewCanaryRunner(cfg *Config) (Runnable, error) {
 		metricsScope,
 		cfg.Cadence.HostNameAndPort,
 		workflowservice.NewWorkflowServiceClient(connection),
+		adminservice.NewAdminServiceClient(connection),
 	)
 
 	return &canaryRunner{
@@ -67,6 +73,9 @@ func NewCanaryRunner(cfg *Config) (Runnable, error) {
 // Run runs the canaries
 func (r *canaryRunner) Run() error {
 	r.metrics.Counter("restarts").Inc(1)
+	if r.config.Chaos != nil {
+		enableChaosScenarios(r.config.Chaos)
+	}
 	if len(r.config.Excludes) != 0 {
 		updateSanityChildWFList(r.config.Excludes)
 	}
@@ -81,7 +90,59 @@ func (r *canaryRunner) Run() error {
 		r.logger.Info("starting canary", zap.String("domain", d))
 		r.execute(canary, &wg)
 	}
+	if r.config.Load != nil {
+		return r.runLoad()
+	}
+	wg.Wait()
+	return nil
+}
+
+// runLoad generates load in every domain while the canaries are running and
+// returns errSLOViolated if any scenario doesn't meet its SLO
+func (r *canaryRunner) runLoad() error {
+	generators := make([]*loadGenerator, 0, len(r.config.Domains))
+	for _, d := range r.config.Domains {
+		generator, err := newLoadGenerator(d, r.config.Load, r.RuntimeContext)
+		if err != nil {
+			return err
+		}
+		generators = append(generators, generator)
+	}
+
+	report := &loadReport{StartTime: time.Now(), Passed: true}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var loadErr error
+	for _, generator := range generators {
+		r.logger.Info("starting load generator", zap.String("domain", generator.domain))
+		wg.Add(1)
+		go func(generator *loadGenerator) {
+			defer wg.Done()
+			scenarios, err := generator.run()
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				loadErr = err
+				return
+			}
+			report.Scenarios = append(report.Scenarios, scenarios...)
+		}(generator)
+	}
 	wg.Wait()
+	if loadErr != nil {
+		return loadErr
+	}
+
+	report.EndTime = time.Now()
+	for _, scenario := range report.Scenarios {
+		report.Passed = report.Passed && scenario.Passed
+	}
+	if err := writeLoadReport(r.logger, report, r.config.Load.ReportFile); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return errSLOViolated
+	}
 	return nil
 }
 
@@ -0,0 +1,189 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package canary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// scenarioStats keeps the results of the workflows of one load scenario
+	scenarioStats struct {
+		sync.Mutex
+		started       int64
+		startFailures int64
+		throttled     int64
+		succeeded     int64
+		failed        int64
+		latencies     []time.Duration
+	}
+
+	// loadReport is the SLO report written at the end of a load run
+	loadReport struct {
+		StartTime time.Time        `json:"startTime"`
+		EndTime   time.Time        `json:"endTime"`
+		Passed    bool             `json:"passed"`
+		Scenarios []scenarioReport `json:"scenarios"`
+	}
+
+	// scenarioReport is the result of one load scenario in one domain
+	scenarioReport struct {
+		Domain        string   `json:"domain"`
+		Name          string   `json:"name"`
+		TargetRPS     float64  `json:"targetRps"`
+		AchievedRPS   float64  `json:"achievedRps"`
+		Started       int64    `json:"started"`
+		StartFailures int64    `json:"startFailures"`
+		Throttled     int64    `json:"throttled"`
+		Succeeded     int64    `json:"succeeded"`
+		Failed        int64    `json:"failed"`
+		SuccessRate   float64  `json:"successRate"`
+		LatencyP50    string   `json:"latencyP50"`
+		LatencyP95    string   `json:"latencyP95"`
+		LatencyP99    string   `json:"latencyP99"`
+		LatencyMax    string   `json:"latencyMax"`
+		Passed        bool     `json:"passed"`
+		Violations    []string `json:"violations,omitempty"`
+	}
+)
+
+func newScenarioStats() *scenarioStats {
+	return &scenarioStats{}
+}
+
+func (s *scenarioStats) recordStart() {
+	s.Lock()
+	defer s.Unlock()
+	s.started++
+}
+
+func (s *scenarioStats) recordStartFailure() {
+	s.Lock()
+	defer s.Unlock()
+	s.startFailures++
+}
+
+func (s *scenarioStats) recordThrottled() {
+	s.Lock()
+	defer s.Unlock()
+	s.throttled++
+}
+
+func (s *scenarioStats) recordEnd(elapsed time.Duration, success bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.latencies = append(s.latencies, elapsed)
+	if success {
+		s.succeeded++
+	} else {
+		s.failed++
+	}
+}
+
+// report computes the result of the scenario and checks it against the SLO
+func (s *scenarioStats) report(domain string, scenario LoadScenario, slo SLO, elapsed time.Duration) scenarioReport {
+	s.Lock()
+	defer s.Unlock()
+
+	latencies := make([]time.Duration, len(s.latencies))
+	copy(latencies, s.latencies)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	r := scenarioReport{
+		Domain:        domain,
+		Name:          scenario.Name,
+		TargetRPS:     scenario.RPS,
+		Started:       s.started,
+		StartFailures: s.startFailures,
+		Throttled:     s.throttled,
+		Succeeded:     s.succeeded,
+		Failed:        s.failed,
+		LatencyP50:    percentile(latencies, 0.50).String(),
+		LatencyP95:    percentile(latencies, 0.95).String(),
+		LatencyP99:    percentile(latencies, 0.99).String(),
+		LatencyMax:    percentile(latencies, 1).String(),
+	}
+	if elapsed > 0 {
+		r.AchievedRPS = float64(s.started) / elapsed.Seconds()
+	}
+	// start failures count as failed workflows, throttled starts were never attempted
+	attempted := s.started + s.startFailures
+	if attempted > 0 {
+		r.SuccessRate = float64(s.succeeded) / float64(attempted)
+	}
+
+	if attempted == 0 {
+		r.Violations = append(r.Violations, "no workflow was started")
+	}
+	if r.SuccessRate < slo.SuccessRate {
+		r.Violations = append(r.Violations, fmt.Sprintf("success rate %.4f is below %.4f", r.SuccessRate, slo.SuccessRate))
+	}
+	checkLatency := func(name string, p float64, max time.Duration) {
+		if max <= 0 {
+			return
+		}
+		if value := percentile(latencies, p); value > max {
+			r.Violations = append(r.Violations, fmt.Sprintf("latency %v %v exceeds %v", name, value, max))
+		}
+	}
+	checkLatency("p50", 0.50, slo.LatencyP50)
+	checkLatency("p95", 0.95, slo.LatencyP95)
+	checkLatency("p99", 0.99, slo.LatencyP99)
+	r.Passed = len(r.Violations) == 0
+	return r
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies, p is in (0, 1]
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// rampFactor returns the fraction of the target rate at the given time since the start of the load.
+// After the ramp the rate stays at the percent of the last stage, without a ramp it is the full rate.
+func rampFactor(ramp []RampStage, elapsed time.Duration) float64 {
+	if len(ramp) == 0 {
+		return 1
+	}
+	from := 0.0
+	for _, stage := range ramp {
+		to := stage.Percent / 100
+		if elapsed < stage.Duration {
+			return from + (to-from)*float64(elapsed)/float64(stage.Duration)
+		}
+		elapsed -= stage.Duration
+		from = to
+	}
+	return from
+}
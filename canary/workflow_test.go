@@ -55,6 +55,7 @@ func (s *workflowTestSuite) SetupTest() {
 	registerHistoryArchival(s.env)
 	registerBatch(s.env)
 	registerCancellation(s.env)
+	registerChaos(s.env)
 	registerConcurrentExec(s.env)
 	registerCron(s.env)
 	registerEcho(s.env)
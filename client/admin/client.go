@@ -180,6 +180,18 @@ func (c *clientImpl) GetReplicationMessages(
 	return client.GetReplicationMessages(ctx, request, opts...)
 }
 
+func (c *clientImpl) StreamReplicationMessages(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamReplicationMessagesClient, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	// replication streams are long-lived, so no timeout is applied
+	return client.StreamReplicationMessages(ctx, opts...)
+}
+
 func (c *clientImpl) GetDomainReplicationMessages(
 	ctx context.Context,
 	request *adminservice.GetDomainReplicationMessagesRequest,
@@ -279,6 +291,160 @@ func (c *clientImpl) RefreshWorkflowTasks(
 	return client.RefreshWorkflowTasks(ctx, request, opts...)
 }
 
+func (c *clientImpl) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.RehydrateWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.RehydrateWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) StartDomainHandover(
+	ctx context.Context,
+	request *adminservice.StartDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainHandoverResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.StartDomainHandover(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeDomainHandover(
+	ctx context.Context,
+	request *adminservice.DescribeDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeDomainHandoverResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.DescribeDomainHandover(ctx, request, opts...)
+}
+
+func (c *clientImpl) UnloadTaskList(
+	ctx context.Context,
+	request *adminservice.UnloadTaskListRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UnloadTaskListResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UnloadTaskList(ctx, request, opts...)
+}
+
+func (c *clientImpl) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ExportWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ImportWorkflowExecution(ctx, request, opts...)
+}
+
+func (c *clientImpl) StartDomainMigration(
+	ctx context.Context,
+	request *adminservice.StartDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainMigrationResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.StartDomainMigration(ctx, request, opts...)
+}
+
+func (c *clientImpl) EndDomainMigration(
+	ctx context.Context,
+	request *adminservice.EndDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.EndDomainMigrationResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.EndDomainMigration(ctx, request, opts...)
+}
+
+func (c *clientImpl) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ReadTaskDLQMessagesResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ReadTaskDLQMessages(ctx, request, opts...)
+}
+
+func (c *clientImpl) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.PurgeTaskDLQMessagesResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.PurgeTaskDLQMessages(ctx, request, opts...)
+}
+
+func (c *clientImpl) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MergeTaskDLQMessagesResponse, error) {
+	client, err := c.getRandomClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.MergeTaskDLQMessages(ctx, request, opts...)
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(parent, c.timeout)
 }
@@ -205,6 +205,19 @@ func (c *metricClient) GetReplicationMessages(
 	return resp, err
 }
 
+func (c *metricClient) StreamReplicationMessages(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamReplicationMessagesClient, error) {
+	c.metricsClient.IncCounter(metrics.FrontendClientStreamReplicationMessagesScope, metrics.ClientRequests)
+
+	stream, err := c.client.StreamReplicationMessages(ctx, opts...)
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.FrontendClientStreamReplicationMessagesScope, metrics.ClientFailures)
+	}
+	return stream, err
+}
+
 func (c *metricClient) GetDomainReplicationMessages(
 	ctx context.Context,
 	request *adminservice.GetDomainReplicationMessagesRequest,
@@ -323,3 +336,190 @@ func (c *metricClient) RefreshWorkflowTasks(
 	}
 	return resp, err
 }
+
+func (c *metricClient) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.RehydrateWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientRehydrateWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientRehydrateWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.RehydrateWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientRehydrateWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) StartDomainHandover(
+	ctx context.Context,
+	request *adminservice.StartDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainHandoverResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientStartDomainHandoverScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientStartDomainHandoverScope, metrics.ClientLatency)
+	resp, err := c.client.StartDomainHandover(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientStartDomainHandoverScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeDomainHandover(
+	ctx context.Context,
+	request *adminservice.DescribeDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeDomainHandoverResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeDomainHandoverScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeDomainHandoverScope, metrics.ClientLatency)
+	resp, err := c.client.DescribeDomainHandover(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeDomainHandoverScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) UnloadTaskList(
+	ctx context.Context,
+	request *adminservice.UnloadTaskListRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UnloadTaskListResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientUnloadTaskListScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientUnloadTaskListScope, metrics.ClientLatency)
+	resp, err := c.client.UnloadTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientUnloadTaskListScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.ExportWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientExportWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.ImportWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientImportWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) StartDomainMigration(
+	ctx context.Context,
+	request *adminservice.StartDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainMigrationResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientStartDomainMigrationScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientStartDomainMigrationScope, metrics.ClientLatency)
+	resp, err := c.client.StartDomainMigration(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientStartDomainMigrationScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) EndDomainMigration(
+	ctx context.Context,
+	request *adminservice.EndDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.EndDomainMigrationResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientEndDomainMigrationScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientEndDomainMigrationScope, metrics.ClientLatency)
+	resp, err := c.client.EndDomainMigration(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientEndDomainMigrationScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ReadTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientReadTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientReadTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.ReadTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientReadTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.PurgeTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientPurgeTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientPurgeTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.PurgeTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientPurgeTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MergeTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.AdminClientMergeTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.AdminClientMergeTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.MergeTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientMergeTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
@@ -189,6 +189,20 @@ func (c *retryableClient) GetReplicationMessages(
 	return resp, err
 }
 
+func (c *retryableClient) StreamReplicationMessages(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (adminservice.AdminService_StreamReplicationMessagesClient, error) {
+	var stream adminservice.AdminService_StreamReplicationMessagesClient
+	op := func() error {
+		var err error
+		stream, err = c.client.StreamReplicationMessages(ctx, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return stream, err
+}
+
 func (c *retryableClient) GetDomainReplicationMessages(
 	ctx context.Context,
 	request *adminservice.GetDomainReplicationMessagesRequest,
@@ -297,3 +311,179 @@ func (c *retryableClient) RefreshWorkflowTasks(
 	err := backoff.Retry(op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.RehydrateWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.RehydrateWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.RehydrateWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) StartDomainHandover(
+	ctx context.Context,
+	request *adminservice.StartDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainHandoverResponse, error) {
+
+	var resp *adminservice.StartDomainHandoverResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.StartDomainHandover(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeDomainHandover(
+	ctx context.Context,
+	request *adminservice.DescribeDomainHandoverRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeDomainHandoverResponse, error) {
+
+	var resp *adminservice.DescribeDomainHandoverResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeDomainHandover(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) UnloadTaskList(
+	ctx context.Context,
+	request *adminservice.UnloadTaskListRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UnloadTaskListResponse, error) {
+
+	var resp *adminservice.UnloadTaskListResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.UnloadTaskList(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ExportWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.ExportWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ExportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ImportWorkflowExecutionResponse, error) {
+
+	var resp *adminservice.ImportWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ImportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) StartDomainMigration(
+	ctx context.Context,
+	request *adminservice.StartDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartDomainMigrationResponse, error) {
+
+	var resp *adminservice.StartDomainMigrationResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.StartDomainMigration(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) EndDomainMigration(
+	ctx context.Context,
+	request *adminservice.EndDomainMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.EndDomainMigrationResponse, error) {
+
+	var resp *adminservice.EndDomainMigrationResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.EndDomainMigration(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ReadTaskDLQMessagesResponse, error) {
+
+	var resp *adminservice.ReadTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ReadTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.PurgeTaskDLQMessagesResponse, error) {
+
+	var resp *adminservice.PurgeTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.PurgeTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.MergeTaskDLQMessagesResponse, error) {
+
+	var resp *adminservice.MergeTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.MergeTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
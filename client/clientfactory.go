@@ -37,6 +37,7 @@ import (
 	"github.com/temporalio/temporal/common/membership"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 const (
@@ -60,12 +61,12 @@ type (
 	DomainIDToNameFunc func(string) (string, error)
 
 	rpcClientFactory struct {
-		rpcFactory            common.RPCFactory
-		monitor               membership.Monitor
-		metricsClient         metrics.Client
-		dynConfig             *dynamicconfig.Collection
-		numberOfHistoryShards int
-		logger                log.Logger
+		rpcFactory         common.RPCFactory
+		monitor            membership.Monitor
+		metricsClient      metrics.Client
+		dynConfig          *dynamicconfig.Collection
+		historyShardRouter sharding.Router
+		logger             log.Logger
 	}
 )
 
@@ -75,16 +76,16 @@ func NewRPCClientFactory(
 	monitor membership.Monitor,
 	metricsClient metrics.Client,
 	dc *dynamicconfig.Collection,
-	numberOfHistoryShards int,
+	historyShardRouter sharding.Router,
 	logger log.Logger,
 ) Factory {
 	return &rpcClientFactory{
-		rpcFactory:            rpcFactory,
-		monitor:               monitor,
-		metricsClient:         metricsClient,
-		dynConfig:             dc,
-		numberOfHistoryShards: numberOfHistoryShards,
-		logger:                logger,
+		rpcFactory:         rpcFactory,
+		monitor:            monitor,
+		metricsClient:      metricsClient,
+		dynConfig:          dc,
+		historyShardRouter: historyShardRouter,
+		logger:             logger,
 	}
 }
 
@@ -119,7 +120,7 @@ func (cf *rpcClientFactory) NewHistoryClientWithTimeout(timeout time.Duration) (
 		return historyservice.NewHistoryServiceClient(connection), nil
 	}
 
-	client := history.NewClient(cf.numberOfHistoryShards, timeout, common.NewClientCache(keyResolver, clientProvider), cf.logger)
+	client := history.NewClient(cf.historyShardRouter, timeout, common.NewClientCache(keyResolver, clientProvider), cf.logger)
 	if cf.metricsClient != nil {
 		client = history.NewMetricClient(client, cf.metricsClient)
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 var _ Client = (*clientImpl)(nil)
@@ -43,7 +44,7 @@ const (
 )
 
 type clientImpl struct {
-	numberOfShards  int
+	shardRouter     sharding.Router
 	tokenSerializer common.TaskTokenSerializer
 	timeout         time.Duration
 	clients         common.ClientCache
@@ -52,13 +53,13 @@ type clientImpl struct {
 
 // NewClient creates a new history service gRPC client
 func NewClient(
-	numberOfShards int,
+	shardRouter sharding.Router,
 	timeout time.Duration,
 	clients common.ClientCache,
 	logger log.Logger,
 ) Client {
 	return &clientImpl{
-		numberOfShards:  numberOfShards,
+		shardRouter:     shardRouter,
 		tokenSerializer: common.NewProtoTaskTokenSerializer(),
 		timeout:         timeout,
 		clients:         clients,
@@ -922,6 +923,75 @@ func (c *clientImpl) GetReplicationMessages(
 	return response, nil
 }
 
+func (c *clientImpl) StreamReplicationMessages(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (historyservice.HistoryService_StreamReplicationMessagesClient, error) {
+	shardID, ok := ShardIDFromContext(ctx)
+	if !ok {
+		return nil, serviceerror.NewInvalidArgument("Shard ID is not set on replication stream context.")
+	}
+	client, err := c.getClientForShardID(int(shardID))
+	if err != nil {
+		return nil, err
+	}
+	// replication streams are long-lived, so no timeout is applied
+	return client.StreamReplicationMessages(ctx, opts...)
+}
+
+func (c *clientImpl) GetDomainHandoverStatus(
+	ctx context.Context,
+	request *historyservice.GetDomainHandoverStatusRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.GetDomainHandoverStatusResponse, error) {
+	requestsByClient := make(map[historyservice.HistoryServiceClient]*historyservice.GetDomainHandoverStatusRequest)
+
+	for _, shardID := range request.ShardIDs {
+		client, err := c.getClientForShardID(int(shardID))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := requestsByClient[client]; !ok {
+			requestsByClient[client] = &historyservice.GetDomainHandoverStatusRequest{
+				DomainUUID: request.DomainUUID,
+			}
+		}
+
+		req := requestsByClient[client]
+		req.ShardIDs = append(req.ShardIDs, shardID)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(requestsByClient))
+	respChan := make(chan *historyservice.GetDomainHandoverStatusResponse, len(requestsByClient))
+	for client, req := range requestsByClient {
+		go func(client historyservice.HistoryServiceClient, request *historyservice.GetDomainHandoverStatusRequest) {
+			defer wg.Done()
+
+			ctx, cancel := c.createContext(ctx)
+			defer cancel()
+			resp, err := client.GetDomainHandoverStatus(ctx, request, opts...)
+			if err != nil {
+				c.logger.Warn("Failed to get domain handover status from client", tag.Error(err))
+				// the shards of an unreachable host cannot be considered drained
+				resp = &historyservice.GetDomainHandoverStatusResponse{PendingShardIDs: request.ShardIDs}
+			}
+			respChan <- resp
+		}(client, req)
+	}
+
+	wg.Wait()
+	close(respChan)
+
+	response := &historyservice.GetDomainHandoverStatusResponse{}
+	for resp := range respChan {
+		response.PendingShardIDs = append(response.PendingShardIDs, resp.PendingShardIDs...)
+	}
+
+	return response, nil
+}
+
 func (c *clientImpl) GetDLQReplicationMessages(
 	ctx context.Context,
 	request *historyservice.GetDLQReplicationMessagesRequest,
@@ -1027,12 +1097,99 @@ func (c *clientImpl) RefreshWorkflowTasks(
 	return response, nil
 }
 
+func (c *clientImpl) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+	client, err := c.getClientForWorkflowID(request.GetRequest().GetExecution().GetWorkflowId())
+	if err != nil {
+		return nil, err
+	}
+	var response *historyservice.RehydrateWorkflowExecutionResponse
+	op := func(ctx context.Context, client historyservice.HistoryServiceClient) error {
+		var err error
+		ctx, cancel := c.createContext(ctx)
+		defer cancel()
+		response, err = client.RehydrateWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err = c.executeWithRedirect(ctx, client, op)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *clientImpl) ImportWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ImportWorkflowExecutionResponse, error) {
+	client, err := c.getClientForWorkflowID(request.GetRequest().GetExecution().GetWorkflowId())
+	if err != nil {
+		return nil, err
+	}
+	var response *historyservice.ImportWorkflowExecutionResponse
+	op := func(ctx context.Context, client historyservice.HistoryServiceClient) error {
+		var err error
+		ctx, cancel := c.createContext(ctx)
+		defer cancel()
+		response, err = client.ImportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+	err = c.executeWithRedirect(ctx, client, op)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *clientImpl) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ReadTaskDLQMessagesResponse, error) {
+
+	client, err := c.getClientForShardID(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		return nil, err
+	}
+	return client.ReadTaskDLQMessages(ctx, request, opts...)
+}
+
+func (c *clientImpl) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.PurgeTaskDLQMessagesResponse, error) {
+
+	client, err := c.getClientForShardID(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		return nil, err
+	}
+	return client.PurgeTaskDLQMessages(ctx, request, opts...)
+}
+
+func (c *clientImpl) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.MergeTaskDLQMessagesResponse, error) {
+
+	client, err := c.getClientForShardID(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		return nil, err
+	}
+	return client.MergeTaskDLQMessages(ctx, request, opts...)
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(parent, c.timeout)
 }
 
 func (c *clientImpl) getClientForWorkflowID(workflowID string) (historyservice.HistoryServiceClient, error) {
-	key := common.WorkflowIDToHistoryShard(workflowID, c.numberOfShards)
+	key := c.shardRouter.GetShardID(workflowID)
 	return c.getClientForShardID(key)
 }
 
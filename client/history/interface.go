@@ -21,10 +21,25 @@
 package history
 
 import (
+	"context"
+
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 )
 
+type shardIDContextKey struct{}
+
 // Client is the interface exposed by history service client
 type Client interface {
 	historyservice.HistoryServiceClient
 }
+
+// WithShardID returns a context which routes a replication stream to the history host owning the shard
+func WithShardID(ctx context.Context, shardID int32) context.Context {
+	return context.WithValue(ctx, shardIDContextKey{}, shardID)
+}
+
+// ShardIDFromContext returns the shard ID set by WithShardID
+func ShardIDFromContext(ctx context.Context) (int32, bool) {
+	shardID, ok := ctx.Value(shardIDContextKey{}).(int32)
+	return shardID, ok
+}
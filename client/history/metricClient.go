@@ -539,6 +539,36 @@ func (c *metricClient) GetReplicationMessages(
 	return resp, err
 }
 
+func (c *metricClient) StreamReplicationMessages(
+	context context.Context,
+	opts ...grpc.CallOption) (historyservice.HistoryService_StreamReplicationMessagesClient, error) {
+	c.metricsClient.IncCounter(metrics.HistoryClientStreamReplicationMessagesScope, metrics.ClientRequests)
+
+	stream, err := c.client.StreamReplicationMessages(context, opts...)
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientStreamReplicationMessagesScope, metrics.ClientFailures)
+	}
+
+	return stream, err
+}
+
+func (c *metricClient) GetDomainHandoverStatus(
+	context context.Context,
+	request *historyservice.GetDomainHandoverStatusRequest,
+	opts ...grpc.CallOption) (*historyservice.GetDomainHandoverStatusResponse, error) {
+	c.metricsClient.IncCounter(metrics.HistoryClientGetDomainHandoverStatusScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientGetDomainHandoverStatusScope, metrics.ClientLatency)
+	resp, err := c.client.GetDomainHandoverStatus(context, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientGetDomainHandoverStatusScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
 func (c *metricClient) GetDLQReplicationMessages(
 	context context.Context,
 	request *historyservice.GetDLQReplicationMessagesRequest,
@@ -656,3 +686,88 @@ func (c *metricClient) RefreshWorkflowTasks(
 	}
 	return resp, err
 }
+
+func (c *metricClient) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientRehydrateWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientRehydrateWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.RehydrateWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientRehydrateWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ImportWorkflowExecutionResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientImportWorkflowExecutionScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientImportWorkflowExecutionScope, metrics.ClientLatency)
+	resp, err := c.client.ImportWorkflowExecution(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientImportWorkflowExecutionScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ReadTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientReadTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientReadTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.ReadTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientReadTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.PurgeTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientPurgeTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientPurgeTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.PurgeTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientPurgeTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.MergeTaskDLQMessagesResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.HistoryClientMergeTaskDLQMessagesScope, metrics.ClientRequests)
+	sw := c.metricsClient.StartTimer(metrics.HistoryClientMergeTaskDLQMessagesScope, metrics.ClientLatency)
+	resp, err := c.client.MergeTaskDLQMessages(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.HistoryClientMergeTaskDLQMessagesScope, metrics.ClientFailures)
+	}
+	return resp, err
+}
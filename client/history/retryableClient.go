@@ -541,6 +541,35 @@ func (c *retryableClient) GetReplicationMessages(
 	return resp, err
 }
 
+func (c *retryableClient) StreamReplicationMessages(
+	ctx context.Context,
+	opts ...grpc.CallOption) (historyservice.HistoryService_StreamReplicationMessagesClient, error) {
+	var stream historyservice.HistoryService_StreamReplicationMessagesClient
+	op := func() error {
+		var err error
+		stream, err = c.client.StreamReplicationMessages(ctx, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return stream, err
+}
+
+func (c *retryableClient) GetDomainHandoverStatus(
+	ctx context.Context,
+	request *historyservice.GetDomainHandoverStatusRequest,
+	opts ...grpc.CallOption) (*historyservice.GetDomainHandoverStatusResponse, error) {
+	var resp *historyservice.GetDomainHandoverStatusResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.GetDomainHandoverStatus(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) GetDLQReplicationMessages(
 	ctx context.Context,
 	request *historyservice.GetDLQReplicationMessagesRequest,
@@ -654,3 +683,88 @@ func (c *retryableClient) RefreshWorkflowTasks(
 	err := backoff.Retry(op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.RehydrateWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+
+	var resp *historyservice.RehydrateWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.RehydrateWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ImportWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.ImportWorkflowExecutionRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ImportWorkflowExecutionResponse, error) {
+
+	var resp *historyservice.ImportWorkflowExecutionResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ImportWorkflowExecution(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.ReadTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.ReadTaskDLQMessagesResponse, error) {
+
+	var resp *historyservice.ReadTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ReadTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.PurgeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.PurgeTaskDLQMessagesResponse, error) {
+
+	var resp *historyservice.PurgeTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.PurgeTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.MergeTaskDLQMessagesRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.MergeTaskDLQMessagesResponse, error) {
+
+	var resp *historyservice.MergeTaskDLQMessagesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.MergeTaskDLQMessages(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
@@ -199,6 +199,38 @@ func (c *clientImpl) ListTaskListPartitions(ctx context.Context, request *matchi
 	return client.ListTaskListPartitions(ctx, request, opts...)
 }
 
+func (c *clientImpl) UnloadTaskList(ctx context.Context, request *matchingservice.UnloadTaskListRequest, opts ...grpc.CallOption) (*matchingservice.UnloadTaskListResponse, error) {
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UnloadTaskList(ctx, request, opts...)
+}
+
+func (c *clientImpl) AcquireActivityTaskSlot(ctx context.Context, request *matchingservice.AcquireActivityTaskSlotRequest, opts ...grpc.CallOption) (*matchingservice.AcquireActivityTaskSlotResponse, error) {
+	// the slots are tracked by the root partition, so no partition is picked here
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.AcquireActivityTaskSlot(ctx, request, opts...)
+}
+
+func (c *clientImpl) ReleaseActivityTaskSlot(ctx context.Context, request *matchingservice.ReleaseActivityTaskSlotRequest, opts ...grpc.CallOption) (*matchingservice.ReleaseActivityTaskSlotResponse, error) {
+	// the slots are tracked by the root partition, so no partition is picked here
+	client, err := c.getClientForTasklist(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ReleaseActivityTaskSlot(ctx, request, opts...)
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(parent, c.timeout)
 }
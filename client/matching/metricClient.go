@@ -242,6 +242,60 @@ func (c *metricClient) ListTaskListPartitions(
 	return resp, err
 }
 
+func (c *metricClient) UnloadTaskList(
+	ctx context.Context,
+	request *matchingservice.UnloadTaskListRequest,
+	opts ...grpc.CallOption) (*matchingservice.UnloadTaskListResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientUnloadTaskListScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientUnloadTaskListScope, metrics.ClientLatency)
+	resp, err := c.client.UnloadTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientUnloadTaskListScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
+func (c *metricClient) AcquireActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.AcquireActivityTaskSlotRequest,
+	opts ...grpc.CallOption) (*matchingservice.AcquireActivityTaskSlotResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientAcquireActivityTaskSlotScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientAcquireActivityTaskSlotScope, metrics.ClientLatency)
+	resp, err := c.client.AcquireActivityTaskSlot(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientAcquireActivityTaskSlotScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
+func (c *metricClient) ReleaseActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.ReleaseActivityTaskSlotRequest,
+	opts ...grpc.CallOption) (*matchingservice.ReleaseActivityTaskSlotResponse, error) {
+
+	c.metricsClient.IncCounter(metrics.MatchingClientReleaseActivityTaskSlotScope, metrics.ClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientReleaseActivityTaskSlotScope, metrics.ClientLatency)
+	resp, err := c.client.ReleaseActivityTaskSlot(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientReleaseActivityTaskSlotScope, metrics.ClientFailures)
+	}
+
+	return resp, err
+}
+
 func (c *metricClient) emitForwardedFromStats(scope int, forwardedFrom string, taskList *commonproto.TaskList) {
 	if taskList == nil {
 		return
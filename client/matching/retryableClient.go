@@ -189,3 +189,51 @@ func (c *retryableClient) ListTaskListPartitions(
 	err := backoff.Retry(op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) UnloadTaskList(
+	ctx context.Context,
+	request *matchingservice.UnloadTaskListRequest,
+	opts ...grpc.CallOption) (*matchingservice.UnloadTaskListResponse, error) {
+
+	var resp *matchingservice.UnloadTaskListResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.UnloadTaskList(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) AcquireActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.AcquireActivityTaskSlotRequest,
+	opts ...grpc.CallOption) (*matchingservice.AcquireActivityTaskSlotResponse, error) {
+
+	var resp *matchingservice.AcquireActivityTaskSlotResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.AcquireActivityTaskSlot(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) ReleaseActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.ReleaseActivityTaskSlotRequest,
+	opts ...grpc.CallOption) (*matchingservice.ReleaseActivityTaskSlotResponse, error) {
+
+	var resp *matchingservice.ReleaseActivityTaskSlotResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ReleaseActivityTaskSlot(ctx, request, opts...)
+		return err
+	}
+
+	err := backoff.Retry(op, c.policy, c.isRetryable)
+	return resp, err
+}
@@ -293,7 +293,14 @@ func immutableClusterMetadataInitialization(
 			clusterMetadata.CurrentClusterName = resp.PersistedImmutableData.ClusterName
 		}
 
-		var persistedShardCount = int(resp.PersistedImmutableData.HistoryShardCount)
+		// the history shards may have been split since the cluster was initialized,
+		// the routing falls back to the immutable shard count otherwise
+		routingResp, err := clusterMetadataManager.GetHistoryShardRouting()
+		if err != nil {
+			log.Fatalf("Error while fetching history shard routing: %v", err)
+		}
+
+		var persistedShardCount = int(routingResp.Routing.ShardCount)
 		if persistenceConfig.NumHistoryShards != persistedShardCount {
 			logImmutableMismatch(logger,
 				"Persistence.NumHistoryShards",
@@ -22,6 +22,8 @@ package cache
 
 import (
 	"time"
+
+	"github.com/temporalio/temporal/common/metrics"
 )
 
 // A Cache is a generalized interface to a cache.  See cache.LRU for a specific
@@ -49,6 +51,14 @@ type Cache interface {
 
 	// Size returns the number of entries currently stored in the Cache
 	Size() int
+
+	// SizeInBytes returns the total size of the entries currently stored in the Cache,
+	// as reported by GetCacheItemSizeFunc. It is always 0 if no size function is set
+	SizeInBytes() uint64
+
+	// Purge removes all the entries from the Cache and refunds their size to the
+	// memory budget. The Cache no longer charges the budget afterwards
+	Purge()
 }
 
 // Options control the behavior of the cache
@@ -66,6 +76,18 @@ type Options struct {
 	// RemovedFunc is an optional function called when an element
 	// is scheduled for deletion
 	RemovedFunc RemovedFunc
+
+	// GetCacheItemSizeFunc is an optional function returning the size in bytes
+	// of a cached value. When set, the cache keeps track of the bytes it holds
+	GetCacheItemSizeFunc GetCacheItemSizeFunc
+
+	// MemoryBudget is an optional byte budget, possibly shared with other caches.
+	// When the budget is exceeded, the least recently used unpinned entries of
+	// all the caches sharing it are evicted. It requires GetCacheItemSizeFunc to be set
+	MemoryBudget *MemoryBudget
+
+	// MetricsScope is an optional scope used to emit hit, miss and eviction metrics
+	MetricsScope metrics.Scope
 }
 
 // RemovedFunc is a type for notifying applications when an item is
@@ -74,6 +96,9 @@ type Options struct {
 // deletion, Cache calls go f(i)
 type RemovedFunc func(interface{})
 
+// GetCacheItemSizeFunc returns the size in bytes of a cached value
+type GetCacheItemSizeFunc func(interface{}) uint64
+
 // Iterator represents the interface for cache iterators
 type Iterator interface {
 	// Close closes the iterator
@@ -23,6 +23,7 @@
 package cache
 
 import (
+	"fmt"
 	"hash/fnv"
 	"sort"
 	"strconv"
@@ -130,6 +131,9 @@ type (
 		failoverVersion             int64
 		isGlobalDomain              bool
 		failoverNotificationVersion int64
+		handoverClusterName         string
+		failoverEndTime             int64
+		migrationClusterName        string
 		notificationVersion         int64
 		initialized                 bool
 	}
@@ -198,6 +202,22 @@ func NewGlobalDomainCacheEntryForTest(
 	}
 }
 
+// NewGlobalDomainCacheEntryInHandoverForTest returns an entry with test data of a domain which is
+// being gracefully failed over to the handover cluster
+func NewGlobalDomainCacheEntryInHandoverForTest(
+	info *persistence.DomainInfo,
+	config *persistence.DomainConfig,
+	repConfig *persistence.DomainReplicationConfig,
+	failoverVersion int64,
+	handoverClusterName string,
+	clusterMetadata cluster.Metadata,
+) *DomainCacheEntry {
+
+	entry := NewGlobalDomainCacheEntryForTest(info, config, repConfig, failoverVersion, clusterMetadata)
+	entry.handoverClusterName = handoverClusterName
+	return entry
+}
+
 // NewLocalDomainCacheEntryForTest returns an entry with test data
 func NewLocalDomainCacheEntryForTest(
 	info *persistence.DomainInfo,
@@ -537,6 +557,9 @@ func (c *domainCache) updateIDToDomainCache(
 	entry.failoverVersion = record.failoverVersion
 	entry.isGlobalDomain = record.isGlobalDomain
 	entry.failoverNotificationVersion = record.failoverNotificationVersion
+	entry.handoverClusterName = record.handoverClusterName
+	entry.failoverEndTime = record.failoverEndTime
+	entry.migrationClusterName = record.migrationClusterName
 	entry.notificationVersion = record.notificationVersion
 	entry.initialized = record.initialized
 
@@ -665,6 +688,9 @@ func (c *domainCache) buildEntryFromRecord(
 	newEntry.failoverVersion = record.FailoverVersion
 	newEntry.isGlobalDomain = record.IsGlobalDomain
 	newEntry.failoverNotificationVersion = record.FailoverNotificationVersion
+	newEntry.handoverClusterName = record.HandoverClusterName
+	newEntry.failoverEndTime = record.FailoverEndTime
+	newEntry.migrationClusterName = record.MigrationClusterName
 	newEntry.notificationVersion = record.NotificationVersion
 	newEntry.initialized = true
 	return newEntry
@@ -713,6 +739,9 @@ func (entry *DomainCacheEntry) duplicate() *DomainCacheEntry {
 	result.failoverVersion = entry.failoverVersion
 	result.isGlobalDomain = entry.isGlobalDomain
 	result.failoverNotificationVersion = entry.failoverNotificationVersion
+	result.handoverClusterName = entry.handoverClusterName
+	result.failoverEndTime = entry.failoverEndTime
+	result.migrationClusterName = entry.migrationClusterName
 	result.notificationVersion = entry.notificationVersion
 	result.initialized = entry.initialized
 	return result
@@ -753,6 +782,32 @@ func (entry *DomainCacheEntry) GetFailoverNotificationVersion() int64 {
 	return entry.failoverNotificationVersion
 }
 
+// GetHandoverClusterName return the cluster the domain is being gracefully failed over to, empty if there is no handover
+func (entry *DomainCacheEntry) GetHandoverClusterName() string {
+	return entry.handoverClusterName
+}
+
+// GetFailoverEndTime return the time in nanoseconds after which an ongoing handover completes regardless of replication
+func (entry *DomainCacheEntry) GetFailoverEndTime() int64 {
+	return entry.failoverEndTime
+}
+
+// IsDomainInHandover return whether the domain is being gracefully failed over to another cluster
+func (entry *DomainCacheEntry) IsDomainInHandover() bool {
+	return entry.isGlobalDomain && entry.handoverClusterName != ""
+}
+
+// GetMigrationClusterName return the cluster a local domain is migrated to or from, empty if there is no migration,
+// it is the target cluster in the source cluster and the source cluster in the target cluster
+func (entry *DomainCacheEntry) GetMigrationClusterName() string {
+	return entry.migrationClusterName
+}
+
+// IsDomainInMigration return whether the local domain is locked while it is migrated to or from another cluster
+func (entry *DomainCacheEntry) IsDomainInMigration() bool {
+	return !entry.isGlobalDomain && entry.migrationClusterName != ""
+}
+
 // GetNotificationVersion return the global notification version of when domain changed
 func (entry *DomainCacheEntry) GetNotificationVersion() int64 {
 	return entry.notificationVersion
@@ -790,6 +845,34 @@ func (entry *DomainCacheEntry) GetDomainNotActiveErr() error {
 	)
 }
 
+// GetDomainHandoverErr return err if domain is active but draining for a graceful failover, nil otherwise.
+// Callers are expected to retry, the domain becomes active in the handover cluster once replication caught up.
+func (entry *DomainCacheEntry) GetDomainHandoverErr() error {
+	if !entry.IsDomainActive() || !entry.IsDomainInHandover() {
+		return nil
+	}
+	return serviceerror.NewUnavailable(fmt.Sprintf(
+		"Domain: %v is being failed over from cluster: %v to cluster: %v.",
+		entry.info.Name,
+		entry.replicationConfig.ActiveClusterName,
+		entry.handoverClusterName,
+	))
+}
+
+// GetDomainMigrationErr return err if the domain is locked for a migration, nil otherwise. Neither requests nor
+// tasks may change the workflows of the domain until the migration ends, the domain is then served by the target
+// cluster, or again by the source cluster if the migration was aborted.
+func (entry *DomainCacheEntry) GetDomainMigrationErr() error {
+	if !entry.IsDomainInMigration() {
+		return nil
+	}
+	return serviceerror.NewUnavailable(fmt.Sprintf(
+		"Domain: %v is locked while it is migrated between this cluster and cluster: %v.",
+		entry.info.Name,
+		entry.migrationClusterName,
+	))
+}
+
 // Len return length
 func (t DomainCacheEntries) Len() int {
 	return len(t)
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally"
 	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log"
@@ -555,6 +556,8 @@ func (s *domainCacheSuite) buildEntryFromRecord(record *persistence.GetDomainRes
 	newEntry.failoverVersion = record.FailoverVersion
 	newEntry.isGlobalDomain = record.IsGlobalDomain
 	newEntry.failoverNotificationVersion = record.FailoverNotificationVersion
+	newEntry.handoverClusterName = record.HandoverClusterName
+	newEntry.failoverEndTime = record.FailoverEndTime
 	newEntry.notificationVersion = record.NotificationVersion
 	newEntry.initialized = true
 	return newEntry
@@ -643,3 +646,56 @@ func Test_IsSampledForLongerRetention(t *testing.T) {
 	d.info.Data[SampleRateKey] = "invalid-value"
 	require.False(t, d.IsSampledForLongerRetention(wid))
 }
+
+func Test_GetDomainHandoverErr(t *testing.T) {
+	d := &DomainCacheEntry{
+		clusterMetadata: cluster.GetTestClusterMetadata(true, true),
+		info:            &persistence.DomainInfo{Name: "some random domain name"},
+		isGlobalDomain:  true,
+		replicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+				{ClusterName: cluster.TestAlternativeClusterName},
+			},
+		},
+	}
+	require.False(t, d.IsDomainInHandover())
+	require.NoError(t, d.GetDomainHandoverErr())
+
+	d.handoverClusterName = cluster.TestAlternativeClusterName
+	require.True(t, d.IsDomainInHandover())
+	require.IsType(t, &serviceerror.Unavailable{}, d.GetDomainHandoverErr())
+
+	// writes to a domain not active in the current cluster are rejected as domain not active instead
+	d.replicationConfig.ActiveClusterName = cluster.TestAlternativeClusterName
+	require.NoError(t, d.GetDomainHandoverErr())
+}
+
+func Test_GetDomainMigrationErr(t *testing.T) {
+	d := &DomainCacheEntry{
+		clusterMetadata: cluster.GetTestClusterMetadata(true, true),
+		info:            &persistence.DomainInfo{Name: "some random domain name"},
+		isGlobalDomain:  false,
+		replicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: cluster.TestCurrentClusterName,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: cluster.TestCurrentClusterName},
+			},
+		},
+	}
+	require.False(t, d.IsDomainInMigration())
+	require.NoError(t, d.GetDomainMigrationErr())
+
+	d.migrationClusterName = cluster.TestAlternativeClusterName
+	require.True(t, d.IsDomainInMigration())
+	require.False(t, d.IsDomainInHandover())
+	require.NoError(t, d.GetDomainHandoverErr())
+	require.IsType(t, &serviceerror.Unavailable{}, d.GetDomainMigrationErr())
+
+	// the handover cluster of a local domain does not lock it
+	d.migrationClusterName = ""
+	d.handoverClusterName = cluster.TestAlternativeClusterName
+	require.False(t, d.IsDomainInMigration())
+	require.NoError(t, d.GetDomainMigrationErr())
+}
@@ -25,6 +25,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/temporalio/temporal/common/metrics"
 )
 
 var (
@@ -42,6 +44,10 @@ type (
 		ttl      time.Duration
 		pin      bool
 		rmFunc   RemovedFunc
+		sizeFunc GetCacheItemSizeFunc
+		budget   *MemoryBudget
+		scope    metrics.Scope
+		currSize uint64
 	}
 
 	iteratorImpl struct {
@@ -55,6 +61,9 @@ type (
 		createTime time.Time
 		value      interface{}
 		refCount   int
+		size       uint64
+		// accessSeq orders the accesses to the entries of all the caches sharing a memory budget
+		accessSeq int64
 	}
 )
 
@@ -91,7 +100,7 @@ func (it *iteratorImpl) prepareNext() {
 		entry := it.nextItem.Value.(*entryImpl)
 		if it.lru.isEntryExpired(entry, it.createTime) {
 			nextItem := it.nextItem.Next()
-			it.lru.deleteInternal(it.nextItem)
+			it.lru.evictInternal(it.nextItem, metrics.CacheEvictedExpiredCounter)
 			it.nextItem = nextItem
 		} else {
 			return
@@ -130,15 +139,25 @@ func New(maxSize int, opts *Options) Cache {
 	if opts == nil {
 		opts = &Options{}
 	}
+	if opts.MemoryBudget != nil && opts.GetCacheItemSizeFunc == nil {
+		panic("Cannot use MemoryBudget without GetCacheItemSizeFunc")
+	}
 
-	return &lru{
+	c := &lru{
 		byAccess: list.New(),
 		byKey:    make(map[interface{}]*list.Element, opts.InitialCapacity),
 		ttl:      opts.TTL,
 		maxSize:  maxSize,
 		pin:      opts.Pin,
 		rmFunc:   opts.RemovedFunc,
+		sizeFunc: opts.GetCacheItemSizeFunc,
+		budget:   opts.MemoryBudget,
+		scope:    opts.MetricsScope,
 	}
+	if c.budget != nil {
+		c.budget.register(c)
+	}
+	return c
 }
 
 // NewLRU creates a new LRU cache of the given size, setting initial capacity
@@ -162,6 +181,7 @@ func (c *lru) Get(key interface{}) interface{} {
 
 	element := c.byKey[key]
 	if element == nil {
+		c.incCounter(metrics.CacheMissCounter)
 		return nil
 	}
 
@@ -169,14 +189,17 @@ func (c *lru) Get(key interface{}) interface{} {
 
 	if c.isEntryExpired(entry, time.Now()) {
 		// Entry has expired
-		c.deleteInternal(element)
+		c.evictInternal(element, metrics.CacheEvictedExpiredCounter)
+		c.incCounter(metrics.CacheMissCounter)
 		return nil
 	}
 
+	c.incCounter(metrics.CacheHitCounter)
 	if c.pin {
 		entry.refCount++
 	}
 	c.byAccess.MoveToFront(element)
+	c.touchInternal(entry)
 	return entry.value
 }
 
@@ -186,12 +209,14 @@ func (c *lru) Put(key interface{}, value interface{}) interface{} {
 		panic("Cannot use Put API in Pin mode. Use Delete and PutIfNotExist if necessary")
 	}
 	val, _ := c.putInternal(key, value, true)
+	c.evictForBudget()
 	return val
 }
 
 // PutIfNotExist puts a value associated with a given key if it does not exist
 func (c *lru) PutIfNotExist(key interface{}, value interface{}) (interface{}, error) {
 	existing, err := c.putInternal(key, value, false)
+	c.evictForBudget()
 	if err != nil {
 		return nil, err
 	}
@@ -216,16 +241,24 @@ func (c *lru) Delete(key interface{}) {
 }
 
 // Release decrements the ref count of a pinned element.
+// The size of the element is refreshed, since the element may have been modified while pinned
 func (c *lru) Release(key interface{}) {
 	c.mut.Lock()
-	defer c.mut.Unlock()
 
 	elt, ok := c.byKey[key]
 	if !ok {
+		c.mut.Unlock()
 		return
 	}
 	entry := elt.Value.(*entryImpl)
 	entry.refCount--
+
+	if c.sizeFunc != nil {
+		c.resizeInternal(entry)
+	}
+	c.mut.Unlock()
+
+	c.evictForBudget()
 }
 
 // Size returns the number of entries currently in the lru, useful if cache is not full
@@ -236,6 +269,29 @@ func (c *lru) Size() int {
 	return len(c.byKey)
 }
 
+// SizeInBytes returns the total size of the entries currently in the lru
+func (c *lru) SizeInBytes() uint64 {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	return c.currSize
+}
+
+// Purge removes all the entries from the lru and detaches it from its memory budget
+func (c *lru) Purge() {
+	c.mut.Lock()
+	for element := c.byAccess.Front(); element != nil; element = c.byAccess.Front() {
+		c.deleteInternal(element)
+	}
+	budget := c.budget
+	c.budget = nil
+	c.mut.Unlock()
+
+	if budget != nil {
+		budget.unregister(c)
+	}
+}
+
 // Put puts a new value associated with a given key, returning the existing value (if present)
 // allowUpdate flag is used to control overwrite behavior if the value exists
 func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool) (interface{}, error) {
@@ -247,7 +303,7 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 		entry := elt.Value.(*entryImpl)
 		if c.isEntryExpired(entry, time.Now()) {
 			// Entry has expired
-			c.deleteInternal(elt)
+			c.evictInternal(elt, metrics.CacheEvictedExpiredCounter)
 		} else {
 			existing := entry.value
 			if allowUpdate {
@@ -255,9 +311,13 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 				if c.ttl != 0 {
 					entry.createTime = time.Now()
 				}
+				if c.sizeFunc != nil {
+					c.resizeInternal(entry)
+				}
 			}
 
 			c.byAccess.MoveToFront(elt)
+			c.touchInternal(entry)
 			if c.pin {
 				entry.refCount++
 			}
@@ -278,6 +338,11 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 		entry.createTime = time.Now()
 	}
 
+	if c.sizeFunc != nil {
+		c.resizeInternal(entry)
+	}
+	c.touchInternal(entry)
+
 	c.byKey[key] = c.byAccess.PushFront(entry)
 	if len(c.byKey) == c.maxSize {
 		oldest := c.byAccess.Back().Value.(*entryImpl)
@@ -289,18 +354,100 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 			return nil, ErrCacheFull
 		}
 
-		c.deleteInternal(c.byAccess.Back())
+		c.evictInternal(c.byAccess.Back(), metrics.CacheEvictedCapacityCounter)
 	}
 
 	return nil, nil
 }
 
+// evictForBudget starts evicting the least recently used unpinned entries of all the caches
+// sharing the memory budget, if it is exceeded. It must be called without holding the lock of the lru
+func (c *lru) evictForBudget() {
+	c.mut.Lock()
+	budget := c.budget
+	c.mut.Unlock()
+
+	if budget != nil && budget.Exceeded() {
+		budget.evict()
+	}
+}
+
+// oldestEvictableAccessSeq returns the access sequence of the least recently used unpinned entry
+func (c *lru) oldestEvictableAccessSeq() (int64, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	element := c.oldestEvictableInternal()
+	if element == nil {
+		return 0, false
+	}
+	return element.Value.(*entryImpl).accessSeq, true
+}
+
+// evictOldest evicts the least recently used unpinned entry, it returns false if there is none
+func (c *lru) evictOldest() bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	element := c.oldestEvictableInternal()
+	if element == nil {
+		return false
+	}
+	c.evictInternal(element, metrics.CacheEvictedMemoryCounter)
+	return true
+}
+
+func (c *lru) oldestEvictableInternal() *list.Element {
+	for element := c.byAccess.Back(); element != nil; element = element.Prev() {
+		if element.Value.(*entryImpl).refCount == 0 {
+			return element
+		}
+	}
+	return nil
+}
+
+func (c *lru) touchInternal(entry *entryImpl) {
+	if c.budget != nil {
+		entry.accessSeq = c.budget.nextAccessSeq()
+	}
+}
+
+// resizeInternal refreshes the size of the entry and charges the difference
+func (c *lru) resizeInternal(entry *entryImpl) {
+	size := c.sizeFunc(entry.value)
+	c.currSize = c.currSize - entry.size + size
+	if c.budget != nil {
+		if size >= entry.size {
+			c.budget.charge(size - entry.size)
+		} else {
+			c.budget.refund(entry.size - size)
+		}
+	}
+	entry.size = size
+}
+
+func (c *lru) evictInternal(element *list.Element, counter int) {
+	c.incCounter(counter)
+	c.deleteInternal(element)
+}
+
 func (c *lru) deleteInternal(element *list.Element) {
 	entry := c.byAccess.Remove(element).(*entryImpl)
 	if c.rmFunc != nil {
 		go c.rmFunc(entry.value)
 	}
 	delete(c.byKey, entry.key)
+
+	c.currSize -= entry.size
+	if c.budget != nil {
+		c.budget.refund(entry.size)
+	}
+}
+
+func (c *lru) incCounter(counter int) {
+	if c.scope != nil {
+		c.scope.IncCounter(counter)
+	}
 }
 
 func (c *lru) isEntryExpired(entry *entryImpl, currentTime time.Time) bool {
@@ -26,6 +26,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
 type keyType struct {
@@ -264,3 +266,109 @@ func TestIterator(t *testing.T) {
 	it.Close()
 	assert.Equal(t, expected, actual)
 }
+
+func TestLRUWithSizeFunc(t *testing.T) {
+	cache := New(5, &Options{
+		GetCacheItemSizeFunc: func(value interface{}) uint64 {
+			return uint64(len(value.(string)))
+		},
+	})
+
+	cache.Put("A", "foo")
+	cache.Put("B", "barbaz")
+	assert.Equal(t, uint64(9), cache.SizeInBytes())
+
+	cache.Put("A", "f")
+	assert.Equal(t, uint64(7), cache.SizeInBytes())
+
+	cache.Delete("B")
+	assert.Equal(t, uint64(1), cache.SizeInBytes())
+}
+
+func TestLRUWithMemoryBudget(t *testing.T) {
+	budget := NewMemoryBudget(dynamicconfig.GetIntPropertyFn(10), nil)
+	sizeFunc := func(value interface{}) uint64 {
+		return uint64(len(value.(string)))
+	}
+	cache1 := New(5, &Options{GetCacheItemSizeFunc: sizeFunc, MemoryBudget: budget})
+	cache2 := New(5, &Options{GetCacheItemSizeFunc: sizeFunc, MemoryBudget: budget})
+
+	cache1.Put("A", "aaaa")
+	cache1.Put("B", "bbbb")
+	cache2.Put("C", "cc")
+	assert.Equal(t, uint64(10), budget.UsedBytes())
+	assert.False(t, budget.Exceeded())
+
+	// the least recently used entry across the caches sharing the budget is evicted first
+	cache1.Get("A")
+	cache2.Put("D", "dd")
+	budget.evictWG.Wait()
+	assert.Equal(t, uint64(8), budget.UsedBytes())
+	assert.Equal(t, 1, cache1.Size())
+	assert.Nil(t, cache1.Get("B"))
+	assert.Equal(t, "aaaa", cache1.Get("A"))
+	assert.Equal(t, "cc", cache2.Get("C"))
+	assert.Equal(t, "dd", cache2.Get("D"))
+
+	cache1.Put("E", "eeee")
+	budget.evictWG.Wait()
+	assert.Equal(t, uint64(8), budget.UsedBytes())
+	assert.Nil(t, cache1.Get("A"))
+	assert.Equal(t, 2, cache2.Size())
+}
+
+func TestLRUWithMemoryBudget_Purge(t *testing.T) {
+	budget := NewMemoryBudget(dynamicconfig.GetIntPropertyFn(10), nil)
+	sizeFunc := func(value interface{}) uint64 {
+		return uint64(len(value.(string)))
+	}
+	cache1 := New(5, &Options{GetCacheItemSizeFunc: sizeFunc, MemoryBudget: budget})
+	cache2 := New(5, &Options{GetCacheItemSizeFunc: sizeFunc, MemoryBudget: budget})
+
+	cache1.Put("A", "aaaa")
+	cache2.Put("B", "bb")
+	assert.Equal(t, uint64(6), budget.UsedBytes())
+
+	cache1.Purge()
+	assert.Equal(t, 0, cache1.Size())
+	assert.Equal(t, uint64(0), cache1.SizeInBytes())
+	assert.Equal(t, uint64(2), budget.UsedBytes())
+
+	// a purged cache no longer charges the budget
+	cache1.Put("C", "cccc")
+	assert.Equal(t, uint64(2), budget.UsedBytes())
+
+	cache2.Purge()
+	assert.Equal(t, uint64(0), budget.UsedBytes())
+}
+
+func TestLRUWithMemoryBudget_Pin(t *testing.T) {
+	budget := NewMemoryBudget(dynamicconfig.GetIntPropertyFn(4), nil)
+	values := map[string]*[]byte{}
+	cache := New(5, &Options{
+		Pin: true,
+		GetCacheItemSizeFunc: func(value interface{}) uint64 {
+			return uint64(len(*value.(*[]byte)))
+		},
+		MemoryBudget: budget,
+	})
+
+	values["A"] = &[]byte{1, 2}
+	values["B"] = &[]byte{1, 2}
+	_, err := cache.PutIfNotExist("A", values["A"])
+	assert.NoError(t, err)
+	_, err = cache.PutIfNotExist("B", values["B"])
+	assert.NoError(t, err)
+
+	// the pinned entry grows, the budget is exceeded but nothing can be evicted yet
+	*values["A"] = append(*values["A"], 3, 4, 5)
+	cache.Release("B")
+	assert.Equal(t, uint64(4), budget.UsedBytes())
+
+	// once released, the size is refreshed and the least recently used A is evicted
+	cache.Release("A")
+	budget.evictWG.Wait()
+	assert.Equal(t, uint64(2), budget.UsedBytes())
+	assert.Equal(t, 1, cache.Size())
+	assert.Nil(t, cache.Get("A"))
+}
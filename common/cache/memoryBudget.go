@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	// MemoryBudget is a byte budget which can be shared by several caches, e.g. the
+	// caches of all the shards owned by a host. Every cache charges the size of its
+	// entries to the budget and, when the budget is exceeded, the least recently used
+	// unpinned entries across all the caches are evicted in the background. A budget is
+	// best effort: eviction lags behind the charges and pinned entries are never evicted,
+	// so the used bytes can temporarily exceed the limit.
+	MemoryBudget struct {
+		maxBytes     dynamicconfig.IntPropertyFn
+		metricsScope metrics.Scope
+		usedBytes    int64
+		accessSeq    int64
+		// evicting is 1 while an eviction goroutine is running, accessed atomically
+		evicting int32
+		evictWG  sync.WaitGroup
+
+		// evictLock is always acquired before the lock of a cache
+		evictLock sync.Mutex
+		caches    map[*lru]struct{}
+	}
+)
+
+// NewMemoryBudget creates a new memory budget, a limit of 0 or less means unlimited
+func NewMemoryBudget(
+	maxBytes dynamicconfig.IntPropertyFn,
+	metricsScope metrics.Scope,
+) *MemoryBudget {
+
+	return &MemoryBudget{
+		maxBytes:     maxBytes,
+		metricsScope: metricsScope,
+		caches:       make(map[*lru]struct{}),
+	}
+}
+
+// UsedBytes returns the number of bytes currently charged to the budget
+func (b *MemoryBudget) UsedBytes() uint64 {
+	return uint64(atomic.LoadInt64(&b.usedBytes))
+}
+
+// Exceeded returns true if the bytes charged to the budget exceed its limit
+func (b *MemoryBudget) Exceeded() bool {
+	maxBytes := b.maxBytes()
+	return maxBytes > 0 && atomic.LoadInt64(&b.usedBytes) > int64(maxBytes)
+}
+
+// evict starts evicting in the background, unless an eviction is already running, so that
+// the requests charging the budget never walk the caches sharing it
+func (b *MemoryBudget) evict() {
+	if !atomic.CompareAndSwapInt32(&b.evicting, 0, 1) {
+		return
+	}
+
+	b.evictWG.Add(1)
+	go func() {
+		defer b.evictWG.Done()
+
+		for {
+			drained := b.evictUntilWithinLimit()
+			atomic.StoreInt32(&b.evicting, 0)
+			// the budget may have been exceeded again after the last check but before the
+			// flag was cleared, in which case the request charging it did not start an eviction
+			if drained || !b.Exceeded() || !atomic.CompareAndSwapInt32(&b.evicting, 0, 1) {
+				return
+			}
+		}
+	}()
+}
+
+// evictUntilWithinLimit evicts the least recently used unpinned entries of all the caches
+// sharing the budget, until the budget is no longer exceeded or there is nothing left to
+// evict, in which case it returns true
+func (b *MemoryBudget) evictUntilWithinLimit() bool {
+	b.evictLock.Lock()
+	defer b.evictLock.Unlock()
+
+	for b.Exceeded() {
+		var oldest *lru
+		oldestAccessSeq := int64(math.MaxInt64)
+		for c := range b.caches {
+			if accessSeq, ok := c.oldestEvictableAccessSeq(); ok && accessSeq < oldestAccessSeq {
+				oldest = c
+				oldestAccessSeq = accessSeq
+			}
+		}
+		if oldest == nil || !oldest.evictOldest() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *MemoryBudget) register(c *lru) {
+	b.evictLock.Lock()
+	defer b.evictLock.Unlock()
+
+	b.caches[c] = struct{}{}
+}
+
+func (b *MemoryBudget) unregister(c *lru) {
+	b.evictLock.Lock()
+	defer b.evictLock.Unlock()
+
+	delete(b.caches, c)
+}
+
+func (b *MemoryBudget) nextAccessSeq() int64 {
+	return atomic.AddInt64(&b.accessSeq, 1)
+}
+
+func (b *MemoryBudget) charge(size uint64) {
+	b.update(atomic.AddInt64(&b.usedBytes, int64(size)))
+}
+
+func (b *MemoryBudget) refund(size uint64) {
+	b.update(atomic.AddInt64(&b.usedBytes, -int64(size)))
+}
+
+func (b *MemoryBudget) update(usedBytes int64) {
+	if b.metricsScope != nil {
+		b.metricsScope.UpdateGauge(metrics.CacheSizeBytesGauge, float64(usedBytes))
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+const (
+	// SignalScheme is the URL scheme of callbacks which signal another workflow,
+	// e.g. temporal://<domain>/<workflowID>[/<runID>]?signal=<signalName>
+	SignalScheme = "temporal"
+	// SignalNameParam is the query parameter holding the signal name of a signal callback
+	SignalNameParam = "signal"
+
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+)
+
+type (
+	// DeliveryError is returned when a callback endpoint rejected the completion
+	DeliveryError struct {
+		Message   string
+		Retryable bool
+	}
+
+	// Payload is the completion notification delivered to a callback.
+	// HTTP callbacks receive it as the POST body, signal callbacks as the signal input.
+	Payload struct {
+		Domain          string          `json:"domain"`
+		WorkflowID      string          `json:"workflowId"`
+		RunID           string          `json:"runId"`
+		CloseStatus     string          `json:"closeStatus"`
+		CompletionEvent json.RawMessage `json:"completionEvent,omitempty"`
+	}
+)
+
+// Error returns string message.
+func (e *DeliveryError) Error() string {
+	return e.Message
+}
+
+// IsRetryableError returns false if delivery failed permanently and should not be retried.
+func IsRetryableError(err error) bool {
+	if deliveryErr, ok := err.(*DeliveryError); ok {
+		return deliveryErr.Retryable
+	}
+	return true
+}
+
+// PostHTTP delivers the payload to an http(s) callback as a JSON POST. Any 2xx response is
+// a successful delivery, 4xx responses other than 408 and 429 are permanent failures.
+func PostHTTP(
+	ctx context.Context,
+	client *http.Client,
+	callbackURL string,
+	payload []byte,
+) error {
+
+	request, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return &DeliveryError{Message: err.Error()}
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, 64*1024))
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return nil
+	case response.StatusCode == http.StatusRequestTimeout || response.StatusCode == http.StatusTooManyRequests:
+		return &DeliveryError{Message: response.Status, Retryable: true}
+	case response.StatusCode >= 400 && response.StatusCode < 500:
+		return &DeliveryError{Message: response.Status}
+	default:
+		return &DeliveryError{Message: response.Status, Retryable: true}
+	}
+}
+
+// Parse converts a raw completion callback into its persisted form. Supported callbacks are
+// http(s) URLs, which receive the completion as a JSON POST, and temporal:// URLs,
+// which signal another workflow with the completion.
+func Parse(raw string) (*persistenceblobs.CompletionCallbackInfo, error) {
+	if raw == "" {
+		return nil, serviceerror.NewInvalidArgument("Completion callback is not set on request.")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid completion callback %q: %v.", raw, err))
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case schemeHTTP, schemeHTTPS:
+		if u.Host == "" {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Completion callback %q has no host.", raw))
+		}
+		return &persistenceblobs.CompletionCallbackInfo{Url: u.String()}, nil
+
+	case SignalScheme:
+		return parseSignalCallback(raw, u)
+
+	default:
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Completion callback %q has unsupported scheme %q.", raw, u.Scheme))
+	}
+}
+
+// ParseAll parses all given callbacks, rejecting requests with more than maxCount callbacks.
+func ParseAll(raws []string, maxCount int) ([]*persistenceblobs.CompletionCallbackInfo, error) {
+	if len(raws) == 0 {
+		return nil, nil
+	}
+	if len(raws) > maxCount {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Too many completion callbacks: %v, limit: %v.", len(raws), maxCount))
+	}
+
+	infos := make([]*persistenceblobs.CompletionCallbackInfo, 0, len(raws))
+	for _, raw := range raws {
+		info, err := Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// CopyAsPending returns a copy of the given callbacks with their delivery state reset.
+func CopyAsPending(infos []*persistenceblobs.CompletionCallbackInfo) []*persistenceblobs.CompletionCallbackInfo {
+	if len(infos) == 0 {
+		return nil
+	}
+	copies := make([]*persistenceblobs.CompletionCallbackInfo, 0, len(infos))
+	for _, info := range infos {
+		copies = append(copies, &persistenceblobs.CompletionCallbackInfo{
+			Url:              info.GetUrl(),
+			SignalDomain:     info.GetSignalDomain(),
+			SignalWorkflowID: info.GetSignalWorkflowID(),
+			SignalRunID:      info.GetSignalRunID(),
+			SignalName:       info.GetSignalName(),
+		})
+	}
+	return copies
+}
+
+// Targets returns the destinations of the given callbacks, which ParseAll converts back into callbacks.
+func Targets(infos []*persistenceblobs.CompletionCallbackInfo) []string {
+	if len(infos) == 0 {
+		return nil
+	}
+	targets := make([]string, 0, len(infos))
+	for _, info := range infos {
+		targets = append(targets, Target(info))
+	}
+	return targets
+}
+
+// CheckAllowed returns an error if the callback calls a URL whose host is not in the comma separated
+// list of allowed hosts. Entries of form *.example.com allow all subdomains of example.com and
+// the entry * allows all hosts. Signal callbacks do not leave the cluster and are always allowed.
+func CheckAllowed(info *persistenceblobs.CompletionCallbackInfo, allowedHosts string) error {
+	if IsSignal(info) {
+		return nil
+	}
+	u, err := url.Parse(info.GetUrl())
+	if err != nil {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid completion callback %q: %v.", info.GetUrl(), err))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range strings.Split(allowedHosts, ",") {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		switch {
+		case allowed == "":
+			continue
+		case allowed == "*", allowed == host:
+			return nil
+		case strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]):
+			return nil
+		}
+	}
+	return serviceerror.NewInvalidArgument(fmt.Sprintf("Completion callback host %q is not allowed.", host))
+}
+
+// IsSignal returns true if the callback signals another workflow instead of calling a URL.
+func IsSignal(info *persistenceblobs.CompletionCallbackInfo) bool {
+	return info.GetUrl() == "" && info.GetSignalWorkflowID() != ""
+}
+
+// Target returns a human readable description of the callback destination.
+func Target(info *persistenceblobs.CompletionCallbackInfo) string {
+	if !IsSignal(info) {
+		return info.GetUrl()
+	}
+	target := &url.URL{
+		Scheme:   SignalScheme,
+		Host:     info.GetSignalDomain(),
+		Path:     "/" + info.GetSignalWorkflowID(),
+		RawQuery: url.Values{SignalNameParam: []string{info.GetSignalName()}}.Encode(),
+	}
+	if info.GetSignalRunID() != "" {
+		target.Path += "/" + info.GetSignalRunID()
+	}
+	return target.String()
+}
+
+func parseSignalCallback(
+	raw string,
+	u *url.URL,
+) (*persistenceblobs.CompletionCallbackInfo, error) {
+
+	domain := u.Host
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if domain == "" || len(segments) == 0 || segments[0] == "" || len(segments) > 2 {
+		return nil, serviceerror.NewInvalidArgument(
+			fmt.Sprintf("Completion callback %q must be of form %v://<domain>/<workflowID>[/<runID>]?%v=<name>.", raw, SignalScheme, SignalNameParam),
+		)
+	}
+	signalName := u.Query().Get(SignalNameParam)
+	if signalName == "" {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Completion callback %q has no signal name.", raw))
+	}
+
+	info := &persistenceblobs.CompletionCallbackInfo{
+		SignalDomain:     domain,
+		SignalWorkflowID: segments[0],
+		SignalName:       signalName,
+	}
+	if len(segments) == 2 {
+		info.SignalRunID = segments[1]
+	}
+	return info, nil
+}
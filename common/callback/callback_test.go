@@ -0,0 +1,179 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package callback
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+type (
+	callbackSuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestCallbackSuite(t *testing.T) {
+	suite.Run(t, new(callbackSuite))
+}
+
+func (s *callbackSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *callbackSuite) TestParse_HTTP() {
+	info, err := Parse("https://example.com/hooks/done?token=abc")
+	s.NoError(err)
+	s.Equal(&persistenceblobs.CompletionCallbackInfo{Url: "https://example.com/hooks/done?token=abc"}, info)
+	s.False(IsSignal(info))
+	s.Equal("https://example.com/hooks/done?token=abc", Target(info))
+
+	_, err = Parse("http:///no-host")
+	s.Error(err)
+}
+
+func (s *callbackSuite) TestParse_Signal() {
+	info, err := Parse("temporal://some-domain/some-workflow?signal=done")
+	s.NoError(err)
+	s.Equal(&persistenceblobs.CompletionCallbackInfo{
+		SignalDomain:     "some-domain",
+		SignalWorkflowID: "some-workflow",
+		SignalName:       "done",
+	}, info)
+	s.True(IsSignal(info))
+	s.Equal("temporal://some-domain/some-workflow?signal=done", Target(info))
+
+	info, err = Parse("temporal://some-domain/some-workflow/some-run?signal=done")
+	s.NoError(err)
+	s.Equal("some-run", info.GetSignalRunID())
+	s.Equal("temporal://some-domain/some-workflow/some-run?signal=done", Target(info))
+}
+
+func (s *callbackSuite) TestParse_Invalid() {
+	for _, raw := range []string{
+		"",
+		"ftp://example.com/done",
+		"temporal://some-domain/some-workflow",
+		"temporal://some-domain?signal=done",
+		"temporal://some-domain/some-workflow/some-run/extra?signal=done",
+		"://broken",
+	} {
+		_, err := Parse(raw)
+		s.Error(err, raw)
+	}
+}
+
+func (s *callbackSuite) TestParseAll() {
+	infos, err := ParseAll(nil, 1)
+	s.NoError(err)
+	s.Nil(infos)
+
+	infos, err = ParseAll([]string{"https://example.com/a", "temporal://d/w?signal=s"}, 2)
+	s.NoError(err)
+	s.Len(infos, 2)
+
+	_, err = ParseAll([]string{"https://example.com/a", "https://example.com/b"}, 1)
+	s.Error(err)
+
+	_, err = ParseAll([]string{"https://example.com/a", "ftp://example.com/b"}, 2)
+	s.Error(err)
+}
+
+func (s *callbackSuite) TestCopyAsPending() {
+	s.Nil(CopyAsPending(nil))
+
+	infos := []*persistenceblobs.CompletionCallbackInfo{{
+		Url:                       "https://example.com/a",
+		State:                     2,
+		Attempt:                   3,
+		LastFailure:               "some failure",
+		LastAttemptTimestampNanos: 123,
+	}}
+	copies := CopyAsPending(infos)
+	s.Equal([]*persistenceblobs.CompletionCallbackInfo{{Url: "https://example.com/a"}}, copies)
+	s.Equal(int32(3), infos[0].Attempt)
+}
+
+func (s *callbackSuite) TestPostHTTP() {
+	status := http.StatusOK
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.Equal("application/json", r.Header.Get("Content-Type"))
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	err := PostHTTP(context.Background(), server.Client(), server.URL, []byte(`{"domain":"d"}`))
+	s.NoError(err)
+	s.Equal(`{"domain":"d"}`, string(body))
+
+	status = http.StatusBadRequest
+	err = PostHTTP(context.Background(), server.Client(), server.URL, nil)
+	s.Error(err)
+	s.False(IsRetryableError(err))
+
+	status = http.StatusTooManyRequests
+	err = PostHTTP(context.Background(), server.Client(), server.URL, nil)
+	s.Error(err)
+	s.True(IsRetryableError(err))
+
+	status = http.StatusServiceUnavailable
+	err = PostHTTP(context.Background(), server.Client(), server.URL, nil)
+	s.Error(err)
+	s.True(IsRetryableError(err))
+}
+
+func (s *callbackSuite) TestTargets_RoundTrip() {
+	infos, err := ParseAll([]string{
+		"https://example.com/hooks/done",
+		"temporal://some-domain/some-workflow/some-run?signal=done",
+	}, 2)
+	s.NoError(err)
+
+	parsed, err := ParseAll(Targets(infos), 2)
+	s.NoError(err)
+	s.Equal(infos, parsed)
+	s.Nil(Targets(nil))
+}
+
+func (s *callbackSuite) TestCheckAllowed() {
+	httpCallback := &persistenceblobs.CompletionCallbackInfo{Url: "https://hooks.Example.com:8443/done"}
+	s.NoError(CheckAllowed(httpCallback, "hooks.example.com"))
+	s.NoError(CheckAllowed(httpCallback, "other.com, *.example.com"))
+	s.NoError(CheckAllowed(httpCallback, "*"))
+	s.Error(CheckAllowed(httpCallback, ""))
+	s.Error(CheckAllowed(httpCallback, "example.com"))
+	s.Error(CheckAllowed(&persistenceblobs.CompletionCallbackInfo{Url: "http://evil-example.com/done"}, "*.example.com"))
+
+	signalCallback := &persistenceblobs.CompletionCallbackInfo{SignalDomain: "some-domain", SignalWorkflowID: "some-workflow", SignalName: "done"}
+	s.NoError(CheckAllowed(signalCallback, ""))
+}
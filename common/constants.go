@@ -91,6 +91,8 @@ const (
 const (
 	// VisibilityAppName is used to find kafka topics and ES indexName for visibility
 	VisibilityAppName = "visibility"
+	// HistoryStreamAppName is the default kafka application of the history event stream
+	HistoryStreamAppName = "history-stream"
 )
 
 // This was flagged by salus as potentially hardcoded credentials. This is a false positive by the scanner and should be
@@ -145,3 +147,6 @@ const (
 	// AdvancedVisibilityWritingModeDual means write to both normal visibility and advanced visibility store
 	AdvancedVisibilityWritingModeDual = "dual"
 )
+
+// TaskListPartitionPrefix is the required naming prefix for any task list partition other than partition 0
+const TaskListPartitionPrefix = "/__temporal_sys/"
@@ -32,4 +32,13 @@ var (
 	errCannotDoDomainFailoverAndUpdate = serviceerror.NewInvalidArgument("Cannot set active cluster to current cluster when other parameters are set.")
 	errInvalidRetentionPeriod          = serviceerror.NewInvalidArgument("A valid retention period is not set on request.")
 	errInvalidArchivalConfig           = serviceerror.NewInvalidArgument("Invalid to enable archival without specifying a uri.")
+	errHandoverOfLocalDomain           = serviceerror.NewInvalidArgument("Cannot gracefully fail over a local domain.")
+	errHandoverNotFromActiveCluster    = serviceerror.NewInvalidArgument("Graceful failover has to be started in the active cluster of the domain.")
+	errHandoverToActiveCluster         = serviceerror.NewInvalidArgument("Domain is already active in the handover cluster.")
+	errDomainAlreadyInHandover         = serviceerror.NewInvalidArgument("Domain is already being gracefully failed over.")
+	errInvalidHandoverTimeout          = serviceerror.NewInvalidArgument("A positive graceful failover timeout is required.")
+	errMigrationOfGlobalDomain         = serviceerror.NewInvalidArgument("Cannot migrate a global domain, fail it over instead.")
+	errMigrationToCurrentCluster       = serviceerror.NewInvalidArgument("Cannot migrate a domain to the current cluster.")
+	errDomainAlreadyInMigration        = serviceerror.NewInvalidArgument("Domain is already being migrated to another cluster.")
+	errDomainNotInMigration            = serviceerror.NewInvalidArgument("Domain is not being migrated.")
 )
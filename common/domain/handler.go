@@ -67,6 +67,22 @@ type (
 			ctx context.Context,
 			updateRequest *workflowservice.UpdateDomainRequest,
 		) (*workflowservice.UpdateDomainResponse, error)
+		StartDomainHandover(
+			ctx context.Context,
+			name string,
+			handoverClusterName string,
+			timeout time.Duration,
+		) error
+		StartDomainMigration(
+			ctx context.Context,
+			name string,
+			clusterName string,
+		) error
+		EndDomainMigration(
+			ctx context.Context,
+			name string,
+			deprecate bool,
+		) error
 	}
 
 	// HandlerImpl is the domain operation handler implementation
@@ -363,6 +379,9 @@ func (d *HandlerImpl) UpdateDomain(
 	configVersion := getResponse.ConfigVersion
 	failoverVersion := getResponse.FailoverVersion
 	failoverNotificationVersion := getResponse.FailoverNotificationVersion
+	handoverClusterName := getResponse.HandoverClusterName
+	failoverEndTime := getResponse.FailoverEndTime
+	migrationClusterName := getResponse.MigrationClusterName
 	isGlobalDomain := getResponse.IsGlobalDomain
 
 	currentHistoryArchivalState := &ArchivalState{
@@ -524,6 +543,9 @@ func (d *HandlerImpl) UpdateDomain(
 				failoverVersion,
 			)
 			failoverNotificationVersion = notificationVersion
+			// the failover completes or overrides any ongoing graceful failover
+			handoverClusterName = ""
+			failoverEndTime = 0
 		}
 
 		updateReq := &persistence.UpdateDomainRequest{
@@ -533,6 +555,9 @@ func (d *HandlerImpl) UpdateDomain(
 			ConfigVersion:               configVersion,
 			FailoverVersion:             failoverVersion,
 			FailoverNotificationVersion: failoverNotificationVersion,
+			HandoverClusterName:         handoverClusterName,
+			FailoverEndTime:             failoverEndTime,
+			MigrationClusterName:        migrationClusterName,
 			NotificationVersion:         notificationVersion,
 		}
 		err = d.metadataMgr.UpdateDomain(updateReq)
@@ -566,6 +591,205 @@ func (d *HandlerImpl) UpdateDomain(
 	return response, nil
 }
 
+// StartDomainHandover starts a graceful failover of a global domain to the handover cluster.
+// The current cluster rejects new writes to the domain from now on, and the domain is failed over
+// once the handover cluster caught up with replication or the timeout has passed.
+func (d *HandlerImpl) StartDomainHandover(
+	_ context.Context,
+	name string,
+	handoverClusterName string,
+	timeout time.Duration,
+) error {
+
+	// must get the metadata (notificationVersion) first
+	// this version can be regarded as the lock on the v2 domain table
+	metadata, err := d.metadataMgr.GetMetadata()
+	if err != nil {
+		return err
+	}
+	notificationVersion := metadata.NotificationVersion
+	getResponse, err := d.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: name})
+	if err != nil {
+		return err
+	}
+
+	if !getResponse.IsGlobalDomain {
+		return errHandoverOfLocalDomain
+	}
+	if getResponse.ReplicationConfig.ActiveClusterName != d.clusterMetadata.GetCurrentClusterName() {
+		return errHandoverNotFromActiveCluster
+	}
+	if getResponse.ReplicationConfig.ActiveClusterName == handoverClusterName {
+		return errHandoverToActiveCluster
+	}
+	if getResponse.HandoverClusterName != "" {
+		return errDomainAlreadyInHandover
+	}
+	if timeout <= 0 {
+		return errInvalidHandoverTimeout
+	}
+	if err := d.domainAttrValidator.validateDomainReplicationConfigForGlobalDomain(
+		&persistence.DomainReplicationConfig{
+			ActiveClusterName: handoverClusterName,
+			Clusters:          getResponse.ReplicationConfig.Clusters,
+		},
+	); err != nil {
+		return err
+	}
+
+	failoverEndTime := time.Now().Add(timeout)
+	if err := d.metadataMgr.UpdateDomain(&persistence.UpdateDomainRequest{
+		Info:                        getResponse.Info,
+		Config:                      getResponse.Config,
+		ReplicationConfig:           getResponse.ReplicationConfig,
+		ConfigVersion:               getResponse.ConfigVersion,
+		FailoverVersion:             getResponse.FailoverVersion,
+		FailoverNotificationVersion: getResponse.FailoverNotificationVersion,
+		HandoverClusterName:         handoverClusterName,
+		FailoverEndTime:             failoverEndTime.UnixNano(),
+		MigrationClusterName:        getResponse.MigrationClusterName,
+		NotificationVersion:         notificationVersion,
+	}); err != nil {
+		return err
+	}
+
+	d.logger.Info("Domain handover started",
+		tag.WorkflowDomainName(getResponse.Info.Name),
+		tag.WorkflowDomainID(getResponse.Info.ID),
+		tag.ClusterName(handoverClusterName),
+		tag.Timestamp(failoverEndTime),
+	)
+	return nil
+}
+
+// StartDomainMigration locks a local domain while it is migrated between the current cluster and the given cluster.
+// New writes to the domain are rejected until the migration ends. The lock is taken in the source cluster before
+// the final copy of the workflows and in the target cluster as soon as the domain is copied, starting the migration
+// again for the same cluster is a no-op, so that an interrupted migration can be resumed.
+func (d *HandlerImpl) StartDomainMigration(
+	_ context.Context,
+	name string,
+	clusterName string,
+) error {
+
+	// must get the metadata (notificationVersion) first
+	// this version can be regarded as the lock on the v2 domain table
+	metadata, err := d.metadataMgr.GetMetadata()
+	if err != nil {
+		return err
+	}
+	notificationVersion := metadata.NotificationVersion
+	getResponse, err := d.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: name})
+	if err != nil {
+		return err
+	}
+
+	if getResponse.IsGlobalDomain {
+		return errMigrationOfGlobalDomain
+	}
+	if clusterName == d.clusterMetadata.GetCurrentClusterName() {
+		return errMigrationToCurrentCluster
+	}
+	if err := d.domainAttrValidator.validateClusterName(clusterName); err != nil {
+		return err
+	}
+	switch getResponse.MigrationClusterName {
+	case "":
+	case clusterName:
+		return nil
+	default:
+		return errDomainAlreadyInMigration
+	}
+
+	if err := d.metadataMgr.UpdateDomain(&persistence.UpdateDomainRequest{
+		Info:                        getResponse.Info,
+		Config:                      getResponse.Config,
+		ReplicationConfig:           getResponse.ReplicationConfig,
+		ConfigVersion:               getResponse.ConfigVersion,
+		FailoverVersion:             getResponse.FailoverVersion,
+		FailoverNotificationVersion: getResponse.FailoverNotificationVersion,
+		HandoverClusterName:         getResponse.HandoverClusterName,
+		FailoverEndTime:             getResponse.FailoverEndTime,
+		MigrationClusterName:        clusterName,
+		NotificationVersion:         notificationVersion,
+	}); err != nil {
+		return err
+	}
+
+	d.logger.Info("Domain migration started",
+		tag.WorkflowDomainName(getResponse.Info.Name),
+		tag.WorkflowDomainID(getResponse.Info.ID),
+		tag.ClusterName(clusterName),
+	)
+	return nil
+}
+
+// EndDomainMigration ends the migration of a locked local domain. The source cluster deprecates the domain once
+// the migration completed and keeps it locked, since its copies of the running workflows are stale from now on.
+// Otherwise the domain is unlocked, which aborts the migration in the source cluster, or makes the target cluster,
+// which replicated the workflows while the domain was active in the source cluster, the active cluster.
+func (d *HandlerImpl) EndDomainMigration(
+	_ context.Context,
+	name string,
+	deprecate bool,
+) error {
+
+	// must get the metadata (notificationVersion) first
+	// this version can be regarded as the lock on the v2 domain table
+	metadata, err := d.metadataMgr.GetMetadata()
+	if err != nil {
+		return err
+	}
+	notificationVersion := metadata.NotificationVersion
+	getResponse, err := d.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: name})
+	if err != nil {
+		return err
+	}
+
+	if getResponse.IsGlobalDomain || getResponse.MigrationClusterName == "" {
+		return errDomainNotInMigration
+	}
+
+	migrationClusterName := getResponse.MigrationClusterName
+	configVersion := getResponse.ConfigVersion
+	failoverNotificationVersion := getResponse.FailoverNotificationVersion
+	currentCluster := d.clusterMetadata.GetCurrentClusterName()
+	if deprecate {
+		getResponse.Info.Status = persistence.DomainStatusDeprecated
+		configVersion++
+	} else {
+		migrationClusterName = ""
+		if getResponse.ReplicationConfig.ActiveClusterName != currentCluster {
+			// the target cluster takes over the domain, its replicated tasks are failed over like those of a global domain
+			getResponse.ReplicationConfig.ActiveClusterName = currentCluster
+			getResponse.ReplicationConfig.Clusters = persistence.GetOrUseDefaultClusters(currentCluster, nil)
+			configVersion++
+			failoverNotificationVersion = notificationVersion
+		}
+	}
+	if err := d.metadataMgr.UpdateDomain(&persistence.UpdateDomainRequest{
+		Info:                        getResponse.Info,
+		Config:                      getResponse.Config,
+		ReplicationConfig:           getResponse.ReplicationConfig,
+		ConfigVersion:               configVersion,
+		FailoverVersion:             getResponse.FailoverVersion,
+		FailoverNotificationVersion: failoverNotificationVersion,
+		HandoverClusterName:         getResponse.HandoverClusterName,
+		FailoverEndTime:             getResponse.FailoverEndTime,
+		MigrationClusterName:        migrationClusterName,
+		NotificationVersion:         notificationVersion,
+	}); err != nil {
+		return err
+	}
+
+	d.logger.Info("Domain migration ended",
+		tag.WorkflowDomainName(getResponse.Info.Name),
+		tag.WorkflowDomainID(getResponse.Info.ID),
+		tag.ClusterName(getResponse.MigrationClusterName),
+	)
+	return nil
+}
+
 // DeprecateDomain deprecates a domain
 func (d *HandlerImpl) DeprecateDomain(
 	ctx context.Context,
@@ -601,6 +825,9 @@ func (d *HandlerImpl) DeprecateDomain(
 		ConfigVersion:               getResponse.ConfigVersion,
 		FailoverVersion:             getResponse.FailoverVersion,
 		FailoverNotificationVersion: getResponse.FailoverNotificationVersion,
+		HandoverClusterName:         getResponse.HandoverClusterName,
+		FailoverEndTime:             getResponse.FailoverEndTime,
+		MigrationClusterName:        getResponse.MigrationClusterName,
 		NotificationVersion:         notificationVersion,
 	}
 	err = d.metadataMgr.UpdateDomain(updateReq)
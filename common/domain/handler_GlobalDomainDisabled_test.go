@@ -27,6 +27,7 @@ import (
 	"testing"
 
 	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
 	commonproto "go.temporal.io/temporal-proto/common"
@@ -39,7 +40,6 @@ import (
 	"github.com/temporalio/temporal/common/archiver/provider"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
-	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	persistencetests "github.com/temporalio/temporal/common/persistence/persistence-tests"
 	"github.com/temporalio/temporal/common/service/config"
@@ -54,7 +54,8 @@ type (
 		minRetentionDays     int
 		maxBadBinaryCount    int
 		metadataMgr          persistence.MetadataManager
-		mockProducer         *mocks.KafkaProducer
+		controller           *gomock.Controller
+		mockReplicationQueue *persistence.MockDomainReplicationQueue
 		mockDomainReplicator Replicator
 		archivalMetadata     archiver.ArchivalMetadata
 		mockArchiverProvider *provider.MockArchiverProvider
@@ -89,8 +90,9 @@ func (s *domainHandlerGlobalDomainDisabledSuite) SetupTest() {
 	s.minRetentionDays = 1
 	s.maxBadBinaryCount = 10
 	s.metadataMgr = s.TestBase.MetadataManager
-	s.mockProducer = &mocks.KafkaProducer{}
-	s.mockDomainReplicator = NewDomainReplicator(s.mockProducer, logger)
+	s.controller = gomock.NewController(s.T())
+	s.mockReplicationQueue = persistence.NewMockDomainReplicationQueue(s.controller)
+	s.mockDomainReplicator = NewDomainReplicator(s.mockReplicationQueue, logger)
 	s.archivalMetadata = archiver.NewArchivalMetadata(
 		dcCollection,
 		"",
@@ -113,7 +115,7 @@ func (s *domainHandlerGlobalDomainDisabledSuite) SetupTest() {
 }
 
 func (s *domainHandlerGlobalDomainDisabledSuite) TearDownTest() {
-	s.mockProducer.AssertExpectations(s.T())
+	s.controller.Finish()
 	s.mockArchiverProvider.AssertExpectations(s.T())
 }
 
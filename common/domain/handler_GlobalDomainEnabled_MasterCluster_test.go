@@ -25,10 +25,11 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
@@ -40,7 +41,6 @@ import (
 	"github.com/temporalio/temporal/common/archiver/provider"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
-	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	persistencetests "github.com/temporalio/temporal/common/persistence/persistence-tests"
 	"github.com/temporalio/temporal/common/service/config"
@@ -55,7 +55,8 @@ type (
 		minRetentionDays     int
 		maxBadBinaryCount    int
 		metadataMgr          persistence.MetadataManager
-		mockProducer         *mocks.KafkaProducer
+		controller           *gomock.Controller
+		mockReplicationQueue *persistence.MockDomainReplicationQueue
 		mockDomainReplicator Replicator
 		archivalMetadata     archiver.ArchivalMetadata
 		mockArchiverProvider *provider.MockArchiverProvider
@@ -90,8 +91,9 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) SetupTest() {
 	s.minRetentionDays = 1
 	s.maxBadBinaryCount = 10
 	s.metadataMgr = s.TestBase.MetadataManager
-	s.mockProducer = &mocks.KafkaProducer{}
-	s.mockDomainReplicator = NewDomainReplicator(s.mockProducer, logger)
+	s.controller = gomock.NewController(s.T())
+	s.mockReplicationQueue = persistence.NewMockDomainReplicationQueue(s.controller)
+	s.mockDomainReplicator = NewDomainReplicator(s.mockReplicationQueue, logger)
 	s.archivalMetadata = archiver.NewArchivalMetadata(
 		dcCollection,
 		"",
@@ -114,7 +116,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) SetupTest() {
 }
 
 func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TearDownTest() {
-	s.mockProducer.AssertExpectations(s.T())
+	s.controller.Finish()
 	s.mockArchiverProvider.AssertExpectations(s.T())
 }
 
@@ -456,7 +458,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestRegisterGetDoma
 		})
 	}
 
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Once()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(1)
 
 	retention := int32(1)
 	registerResp, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
@@ -520,7 +522,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestRegisterGetDoma
 	data := map[string]string{"some random key": "some random value"}
 	isGlobalDomain := true
 
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Once()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(1)
 
 	registerResp, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
 		Name:                                   domainName,
@@ -589,7 +591,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestUpdateGetDomain
 	s.True(len(clusters) > 1)
 	isGlobalDomain := true
 
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Twice()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(2)
 
 	registerResp, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
 		Name:                                   domainName,
@@ -675,7 +677,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestUpdateGetDomain
 	s.True(len(clusters) > 1)
 	isGlobalDomain := true
 
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Twice()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(2)
 
 	registerResp, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
 		Name:                                   domainName,
@@ -787,7 +789,7 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestUpdateGetDomain
 	s.True(len(clusters) > 1)
 	isGlobalDomain := true
 
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Twice()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(2)
 
 	registerResp, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
 		Name:                                   domainName,
@@ -863,6 +865,159 @@ func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestUpdateGetDomain
 	)
 }
 
+func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestStartDomainHandover() {
+	domainName := s.getRandomDomainName()
+	activeClusterName := s.ClusterMetadata.GetCurrentClusterName()
+	handoverClusterName := ""
+	clusters := []*commonproto.ClusterReplicationConfiguration{}
+	for clusterName := range s.ClusterMetadata.GetAllClusterInfo() {
+		if clusterName != activeClusterName {
+			handoverClusterName = clusterName
+		}
+		clusters = append(clusters, &commonproto.ClusterReplicationConfiguration{
+			ClusterName: clusterName,
+		})
+	}
+	s.True(len(handoverClusterName) > 0)
+
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(2)
+
+	_, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
+		Name:                                   domainName,
+		WorkflowExecutionRetentionPeriodInDays: 1,
+		Clusters:                               clusters,
+		ActiveClusterName:                      activeClusterName,
+		IsGlobalDomain:                         true,
+	})
+	s.NoError(err)
+
+	err = s.handler.StartDomainHandover(context.Background(), domainName, activeClusterName, time.Minute)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	err = s.handler.StartDomainHandover(context.Background(), domainName, handoverClusterName, 0)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+
+	err = s.handler.StartDomainHandover(context.Background(), domainName, handoverClusterName, time.Minute)
+	s.NoError(err)
+	resp, err := s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Equal(activeClusterName, resp.ReplicationConfig.ActiveClusterName)
+	s.Equal(handoverClusterName, resp.HandoverClusterName)
+	s.True(resp.FailoverEndTime > time.Now().UnixNano())
+
+	err = s.handler.StartDomainHandover(context.Background(), domainName, handoverClusterName, time.Minute)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+
+	// the failover to the handover cluster completes the handover
+	_, err = s.handler.UpdateDomain(context.Background(), &workflowservice.UpdateDomainRequest{
+		Name: domainName,
+		ReplicationConfiguration: &commonproto.DomainReplicationConfiguration{
+			ActiveClusterName: handoverClusterName,
+		},
+	})
+	s.NoError(err)
+	resp, err = s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Equal(handoverClusterName, resp.ReplicationConfig.ActiveClusterName)
+	s.Empty(resp.HandoverClusterName)
+	s.Zero(resp.FailoverEndTime)
+}
+
+func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestStartEndDomainMigration() {
+	domainName := s.getRandomDomainName()
+	currentClusterName := s.ClusterMetadata.GetCurrentClusterName()
+	targetClusterName := ""
+	for clusterName := range s.ClusterMetadata.GetAllClusterInfo() {
+		if clusterName != currentClusterName {
+			targetClusterName = clusterName
+		}
+	}
+	s.True(len(targetClusterName) > 0)
+
+	_, err := s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
+		Name:                                   domainName,
+		WorkflowExecutionRetentionPeriodInDays: 1,
+		IsGlobalDomain:                         false,
+	})
+	s.NoError(err)
+
+	err = s.handler.EndDomainMigration(context.Background(), domainName, false)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	err = s.handler.StartDomainMigration(context.Background(), domainName, currentClusterName)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+
+	err = s.handler.StartDomainMigration(context.Background(), domainName, targetClusterName)
+	s.NoError(err)
+	resp, err := s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Equal(targetClusterName, resp.MigrationClusterName)
+	s.Empty(resp.HandoverClusterName)
+
+	// starting the same migration again is a no-op
+	err = s.handler.StartDomainMigration(context.Background(), domainName, targetClusterName)
+	s.NoError(err)
+
+	// aborting the migration unlocks the domain
+	err = s.handler.EndDomainMigration(context.Background(), domainName, false)
+	s.NoError(err)
+	resp, err = s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Empty(resp.MigrationClusterName)
+	s.Equal(persistence.DomainStatusRegistered, resp.Info.Status)
+
+	// completing the migration deprecates the domain and keeps it locked
+	err = s.handler.StartDomainMigration(context.Background(), domainName, targetClusterName)
+	s.NoError(err)
+	err = s.handler.EndDomainMigration(context.Background(), domainName, true)
+	s.NoError(err)
+	resp, err = s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Equal(targetClusterName, resp.MigrationClusterName)
+	s.Equal(persistence.DomainStatusDeprecated, resp.Info.Status)
+}
+
+func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) TestEndDomainMigration_TargetTakesOver() {
+	domainName := s.getRandomDomainName()
+	currentClusterName := s.ClusterMetadata.GetCurrentClusterName()
+	sourceClusterName := ""
+	for clusterName := range s.ClusterMetadata.GetAllClusterInfo() {
+		if clusterName != currentClusterName {
+			sourceClusterName = clusterName
+		}
+	}
+	s.True(len(sourceClusterName) > 0)
+
+	// the copy of the domain is active in the source cluster while the workflows are replicated
+	_, err := s.MetadataManager.CreateDomain(&persistence.CreateDomainRequest{
+		Info: &persistence.DomainInfo{
+			ID:     uuid.New(),
+			Name:   domainName,
+			Status: persistence.DomainStatusRegistered,
+		},
+		Config: &persistence.DomainConfig{Retention: 1},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: sourceClusterName,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: currentClusterName},
+				{ClusterName: sourceClusterName},
+			},
+		},
+		IsGlobalDomain:  false,
+		FailoverVersion: common.EmptyVersion,
+	})
+	s.NoError(err)
+	err = s.handler.StartDomainMigration(context.Background(), domainName, sourceClusterName)
+	s.NoError(err)
+
+	err = s.handler.EndDomainMigration(context.Background(), domainName, false)
+	s.NoError(err)
+	resp, err := s.MetadataManager.GetDomain(&persistence.GetDomainRequest{Name: domainName})
+	s.NoError(err)
+	s.Empty(resp.MigrationClusterName)
+	s.Equal(currentClusterName, resp.ReplicationConfig.ActiveClusterName)
+	s.Equal(persistence.GetOrUseDefaultClusters(currentClusterName, nil), resp.ReplicationConfig.Clusters)
+	s.True(resp.FailoverNotificationVersion > persistence.InitialFailoverNotificationVersion)
+}
+
 func (s *domainHandlerGlobalDomainEnabledMasterClusterSuite) getRandomDomainName() string {
 	return "domain" + uuid.New()
 }
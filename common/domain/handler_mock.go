@@ -30,6 +30,7 @@ package domain
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	workflowservice "go.temporal.io/temporal-proto/workflowservice"
@@ -132,3 +133,45 @@ func (mr *MockHandlerMockRecorder) UpdateDomain(ctx, updateRequest interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDomain", reflect.TypeOf((*MockHandler)(nil).UpdateDomain), ctx, updateRequest)
 }
+
+// StartDomainHandover mocks base method
+func (m *MockHandler) StartDomainHandover(ctx context.Context, name, handoverClusterName string, timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDomainHandover", ctx, name, handoverClusterName, timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartDomainHandover indicates an expected call of StartDomainHandover
+func (mr *MockHandlerMockRecorder) StartDomainHandover(ctx, name, handoverClusterName, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDomainHandover", reflect.TypeOf((*MockHandler)(nil).StartDomainHandover), ctx, name, handoverClusterName, timeout)
+}
+
+// StartDomainMigration mocks base method
+func (m *MockHandler) StartDomainMigration(ctx context.Context, name, clusterName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDomainMigration", ctx, name, clusterName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartDomainMigration indicates an expected call of StartDomainMigration
+func (mr *MockHandlerMockRecorder) StartDomainMigration(ctx, name, clusterName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDomainMigration", reflect.TypeOf((*MockHandler)(nil).StartDomainMigration), ctx, name, clusterName)
+}
+
+// EndDomainMigration mocks base method
+func (m *MockHandler) EndDomainMigration(ctx context.Context, name string, deprecate bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndDomainMigration", ctx, name, deprecate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EndDomainMigration indicates an expected call of EndDomainMigration
+func (mr *MockHandlerMockRecorder) EndDomainMigration(ctx, name, deprecate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndDomainMigration", reflect.TypeOf((*MockHandler)(nil).EndDomainMigration), ctx, name, deprecate)
+}
@@ -28,9 +28,9 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
@@ -41,7 +41,6 @@ import (
 	"github.com/temporalio/temporal/common/archiver/provider"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
-	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	persistencetests "github.com/temporalio/temporal/common/persistence/persistence-tests"
 	"github.com/temporalio/temporal/common/service/config"
@@ -56,7 +55,8 @@ type (
 		minRetentionDays     int
 		maxBadBinaryCount    int
 		metadataMgr          persistence.MetadataManager
-		mockProducer         *mocks.KafkaProducer
+		controller           *gomock.Controller
+		mockReplicationQueue *persistence.MockDomainReplicationQueue
 		mockDomainReplicator Replicator
 		archivalMetadata     archiver.ArchivalMetadata
 		mockArchiverProvider *provider.MockArchiverProvider
@@ -93,8 +93,9 @@ func (s *domainHandlerCommonSuite) SetupTest() {
 	s.minRetentionDays = 1
 	s.maxBadBinaryCount = 10
 	s.metadataMgr = s.TestBase.MetadataManager
-	s.mockProducer = &mocks.KafkaProducer{}
-	s.mockDomainReplicator = NewDomainReplicator(s.mockProducer, logger)
+	s.controller = gomock.NewController(s.T())
+	s.mockReplicationQueue = persistence.NewMockDomainReplicationQueue(s.controller)
+	s.mockDomainReplicator = NewDomainReplicator(s.mockReplicationQueue, logger)
 	s.archivalMetadata = archiver.NewArchivalMetadata(
 		dcCollection,
 		"",
@@ -117,7 +118,7 @@ func (s *domainHandlerCommonSuite) SetupTest() {
 }
 
 func (s *domainHandlerCommonSuite) TearDownTest() {
-	s.mockProducer.AssertExpectations(s.T())
+	s.controller.Finish()
 	s.mockArchiverProvider.AssertExpectations(s.T())
 }
 
@@ -301,7 +302,7 @@ func (s *domainHandlerCommonSuite) TestListDomain() {
 			ClusterName: clusterName,
 		})
 	}
-	s.mockProducer.On("Publish", mock.Anything).Return(nil).Once()
+	s.mockReplicationQueue.EXPECT().Publish(gomock.Any()).Return(nil).Times(1)
 	registerResp, err = s.handler.RegisterDomain(context.Background(), &workflowservice.RegisterDomainRequest{
 		Name:                                   domainName2,
 		Description:                            description2,
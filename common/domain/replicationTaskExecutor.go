@@ -217,6 +217,9 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ta
 		ConfigVersion:               resp.ConfigVersion,
 		FailoverVersion:             resp.FailoverVersion,
 		FailoverNotificationVersion: resp.FailoverNotificationVersion,
+		HandoverClusterName:         resp.HandoverClusterName,
+		FailoverEndTime:             resp.FailoverEndTime,
+		MigrationClusterName:        resp.MigrationClusterName,
 		NotificationVersion:         notificationVersion,
 	}
 
@@ -249,6 +252,9 @@ func (h *domainReplicationTaskExecutorImpl) handleDomainUpdateReplicationTask(ta
 		request.ReplicationConfig.ActiveClusterName = task.ReplicationConfig.GetActiveClusterName()
 		request.FailoverVersion = task.GetFailoverVersion()
 		request.FailoverNotificationVersion = notificationVersion
+		// a failover performed by another cluster supersedes any local graceful failover
+		request.HandoverClusterName = ""
+		request.FailoverEndTime = 0
 	}
 
 	if !recordUpdated {
@@ -28,7 +28,6 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
-	"github.com/temporalio/temporal/common/messaging"
 	"github.com/temporalio/temporal/common/persistence"
 )
 
@@ -43,16 +42,16 @@ type (
 	}
 
 	domainReplicatorImpl struct {
-		replicationMessageSink messaging.Producer
-		logger                 log.Logger
+		replicationQueue persistence.DomainReplicationQueue
+		logger           log.Logger
 	}
 )
 
 // NewDomainReplicator create a new instance of domain replicator
-func NewDomainReplicator(replicationMessageSink messaging.Producer, logger log.Logger) Replicator {
+func NewDomainReplicator(replicationQueue persistence.DomainReplicationQueue, logger log.Logger) Replicator {
 	return &domainReplicatorImpl{
-		replicationMessageSink: replicationMessageSink,
-		logger:                 logger,
+		replicationQueue: replicationQueue,
+		logger:           logger,
 	}
 }
 
@@ -99,7 +98,7 @@ func (domainReplicator *domainReplicatorImpl) HandleTransmissionTask(domainOpera
 		FailoverVersion: failoverVersion,
 	}
 
-	return domainReplicator.replicationMessageSink.Publish(
+	return domainReplicator.replicationQueue.Publish(
 		&replication.ReplicationTask{
 			TaskType: taskType,
 			Attributes: &replication.ReplicationTask_DomainTaskAttributes{
@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
 	commonproto "go.temporal.io/temporal-proto/common"
@@ -31,15 +32,15 @@ import (
 
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
-	"github.com/temporalio/temporal/common/mocks"
 	p "github.com/temporalio/temporal/common/persistence"
 )
 
 type (
 	transmissionTaskSuite struct {
 		suite.Suite
-		domainReplicator *domainReplicatorImpl
-		kafkaProducer    *mocks.KafkaProducer
+		controller             *gomock.Controller
+		domainReplicator       *domainReplicatorImpl
+		domainReplicationQueue *p.MockDomainReplicationQueue
 	}
 )
 
@@ -56,15 +57,16 @@ func (s *transmissionTaskSuite) TearDownSuite() {
 }
 
 func (s *transmissionTaskSuite) SetupTest() {
-	s.kafkaProducer = &mocks.KafkaProducer{}
+	s.controller = gomock.NewController(s.T())
+	s.domainReplicationQueue = p.NewMockDomainReplicationQueue(s.controller)
 	s.domainReplicator = NewDomainReplicator(
-		s.kafkaProducer,
+		s.domainReplicationQueue,
 		loggerimpl.NewDevelopmentForTest(s.Suite),
 	).(*domainReplicatorImpl)
 }
 
 func (s *transmissionTaskSuite) TearDownTest() {
-	s.kafkaProducer.AssertExpectations(s.T())
+	s.controller.Finish()
 }
 
 func (s *transmissionTaskSuite) TestHandleTransmissionTask_RegisterDomainTask_IsGlobalDomain() {
@@ -118,7 +120,7 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_RegisterDomainTask_Is
 	}
 	isGlobalDomain := true
 
-	s.kafkaProducer.On("Publish", &replication.ReplicationTask{
+	s.domainReplicationQueue.EXPECT().Publish(&replication.ReplicationTask{
 		TaskType: taskType,
 		Attributes: &replication.ReplicationTask_DomainTaskAttributes{
 			DomainTaskAttributes: &replication.DomainTaskAttributes{
@@ -148,7 +150,7 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_RegisterDomainTask_Is
 				FailoverVersion: failoverVersion,
 			},
 		},
-	}).Return(nil).Once()
+	}).Return(nil).Times(1)
 
 	err := s.domainReplicator.HandleTransmissionTask(domainOperation, info, config, replicationConfig, configVersion, failoverVersion, isGlobalDomain)
 	s.Nil(err)
@@ -258,7 +260,7 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_UpdateDomainTask_IsGl
 	}
 	isGlobalDomain := true
 
-	s.kafkaProducer.On("Publish", &replication.ReplicationTask{
+	s.domainReplicationQueue.EXPECT().Publish(&replication.ReplicationTask{
 		TaskType: taskType,
 		Attributes: &replication.ReplicationTask_DomainTaskAttributes{
 			DomainTaskAttributes: &replication.DomainTaskAttributes{
@@ -287,7 +289,7 @@ func (s *transmissionTaskSuite) TestHandleTransmissionTask_UpdateDomainTask_IsGl
 				ConfigVersion:   configVersion,
 				FailoverVersion: failoverVersion},
 		},
-	}).Return(nil).Once()
+	}).Return(nil).Times(1)
 
 	err := s.domainReplicator.HandleTransmissionTask(domainOperation, info, config, replicationConfig, configVersion, failoverVersion, isGlobalDomain)
 	s.Nil(err)
@@ -31,15 +31,23 @@ type (
 	// Client is a wrapper around ElasticSearch client library.
 	// It simplifies the interface and enables mocking. We intentionally let implementation details of the elastic library
 	// bleed through, as the main purpose is testability not abstraction.
+	// Requests and results use the types of the v6 elastic library regardless of the ElasticSearch version,
+	// other versions convert from and to them.
 	Client interface {
 		Search(ctx context.Context, p *SearchParameters) (*elastic.SearchResult, error)
 		SearchWithDSL(ctx context.Context, index, query string) (*elastic.SearchResult, error)
 		Scroll(ctx context.Context, scrollID string) (*elastic.SearchResult, ScrollService, error)
 		ScrollFirstPage(ctx context.Context, index, query string) (*elastic.SearchResult, ScrollService, error)
 		Count(ctx context.Context, index, query string) (int64, error)
-		RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (*elastic.BulkProcessor, error)
+		RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error)
 		PutMapping(ctx context.Context, index, root, key, valueType string) error
 		CreateIndex(ctx context.Context, index string) error
+		IndexExists(ctx context.Context, index string) (bool, error)
+		PutIndexTemplate(ctx context.Context, name, bodyString string) error
+		// GetMapping returns the typeless mapping of the index
+		GetMapping(ctx context.Context, index string) (map[string]interface{}, error)
+		// PutMappingBody updates the mapping of the index with the given typeless mapping body
+		PutMappingBody(ctx context.Context, index string, body map[string]interface{}) error
 	}
 
 	// ScrollService is a interface for elastic.ScrollService
@@ -47,6 +55,16 @@ type (
 		Clear(ctx context.Context) error
 	}
 
+	// BulkProcessor is a interface for elastic.BulkProcessor
+	BulkProcessor interface {
+		Start(ctx context.Context) error
+		Stop() error
+		Close() error
+		Stats() elastic.BulkProcessorStats
+		Add(request elastic.BulkableRequest)
+		Flush() error
+	}
+
 	// SearchParameters holds all required and optional parameters for executing a search
 	SearchParameters struct {
 		Index       string
@@ -68,101 +86,19 @@ type (
 		BeforeFunc    elastic.BulkBeforeFunc
 		AfterFunc     elastic.BulkAfterFunc
 	}
-
-	// elasticWrapper implements Client
-	elasticWrapper struct {
-		client *elastic.Client
-	}
-
-	scrollServiceImpl struct {
-		scrollService *elastic.ScrollService
-	}
 )
 
-var _ Client = (*elasticWrapper)(nil)
-
 // NewClient create a ES client
 func NewClient(config *Config) (Client, error) {
-	client, err := elastic.NewClient(
-		elastic.SetURL(config.URL.String()),
-		elastic.SetRetrier(elastic.NewBackoffRetrier(elastic.NewExponentialBackoff(128*time.Millisecond, 513*time.Millisecond))),
-		elastic.SetDecoder(&elastic.NumberDecoder{}), // critical to ensure decode of int64 won't lose precise
-	)
-	if err != nil {
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
-	return NewWrapperClient(client), nil
-}
-
-// NewWrapperClient returns a new implementation of Client
-func NewWrapperClient(esClient *elastic.Client) Client {
-	return &elasticWrapper{client: esClient}
-}
-
-func (c *elasticWrapper) Search(ctx context.Context, p *SearchParameters) (*elastic.SearchResult, error) {
-	searchService := c.client.Search(p.Index).
-		Query(p.Query).
-		From(p.From).
-		SortBy(p.Sorter...)
-
-	if p.PageSize != 0 {
-		searchService.Size(p.PageSize)
-	}
-
-	if len(p.SearchAfter) != 0 {
-		searchService.SearchAfter(p.SearchAfter...)
+	switch config.GetVersion() {
+	case VersionV7:
+		return newClientV7(config)
+	default:
+		return newClientV6(config)
 	}
-
-	return searchService.Do(ctx)
-}
-
-func (c *elasticWrapper) SearchWithDSL(ctx context.Context, index, query string) (*elastic.SearchResult, error) {
-	return c.client.Search(index).Source(query).Do(ctx)
-}
-
-func (c *elasticWrapper) Scroll(ctx context.Context, scrollID string) (
-	*elastic.SearchResult, ScrollService, error) {
-
-	scrollService := elastic.NewScrollService(c.client)
-	result, err := scrollService.ScrollId(scrollID).Do(ctx)
-	return result, &scrollServiceImpl{scrollService}, err
-}
-
-func (c *elasticWrapper) ScrollFirstPage(ctx context.Context, index, query string) (
-	*elastic.SearchResult, ScrollService, error) {
-
-	scrollService := elastic.NewScrollService(c.client)
-	result, err := scrollService.Index(index).Body(query).Do(ctx)
-	return result, &scrollServiceImpl{scrollService}, err
-}
-
-func (c *elasticWrapper) Count(ctx context.Context, index, query string) (int64, error) {
-	return c.client.Count(index).BodyString(query).Do(ctx)
-}
-
-func (c *elasticWrapper) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (*elastic.BulkProcessor, error) {
-	return c.client.BulkProcessor().
-		Name(p.Name).
-		Workers(p.NumOfWorkers).
-		BulkActions(p.BulkActions).
-		BulkSize(p.BulkSize).
-		FlushInterval(p.FlushInterval).
-		Backoff(p.Backoff).
-		Before(p.BeforeFunc).
-		After(p.AfterFunc).
-		Do(ctx)
-}
-
-// root is for nested object like Attr property for search attributes.
-func (c *elasticWrapper) PutMapping(ctx context.Context, index, root, key, valueType string) error {
-	body := buildPutMappingBody(root, key, valueType)
-	_, err := c.client.PutMapping().Index(index).Type("_doc").BodyJson(body).Do(ctx)
-	return err
-}
-
-func (c *elasticWrapper) CreateIndex(ctx context.Context, index string) error {
-	_, err := c.client.CreateIndex(index).Do(ctx)
-	return err
 }
 
 func buildPutMappingBody(root, key, valueType string) map[string]interface{} {
@@ -186,7 +122,3 @@ func buildPutMappingBody(root, key, valueType string) map[string]interface{} {
 	}
 	return body
 }
-
-func (s *scrollServiceImpl) Clear(ctx context.Context) error {
-	return s.scrollService.Clear(ctx)
-}
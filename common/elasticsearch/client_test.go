@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/olivere/elastic"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,3 +49,29 @@ func Test_BuildPutMappingBody(t *testing.T) {
 		require.Equal(t, test.expected, fmt.Sprintf("%v", buildPutMappingBody(test.root, k, v)))
 	}
 }
+
+func Test_WithTrackTotalHits(t *testing.T) {
+	query, err := withTrackTotalHits(`{"query":{"range":{"StartTime":{"gte":1583264455000000001}}},"from":0,"size":10}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"from":0,"query":{"range":{"StartTime":{"gte":1583264455000000001}}},"size":10,"track_total_hits":true}`, query)
+
+	_, err = withTrackTotalHits("not a json")
+	require.Error(t, err)
+}
+
+func Test_TypelessBulkableRequest(t *testing.T) {
+	request := elastic.NewBulkIndexRequest().
+		Index("test-index").
+		Type("_doc").
+		Id("wid~rid").
+		VersionType("external").
+		Version(2).
+		Doc(map[string]interface{}{"WorkflowID": "wid"})
+
+	lines, err := (&typelessBulkableRequest{request: request}).Source()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`{"index":{"_id":"wid~rid","_index":"test-index","version":2,"version_type":"external"}}`,
+		`{"WorkflowID":"wid"}`,
+	}, lines)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/olivere/elastic"
+)
+
+const (
+	// docTypeV6 is the single mapping type of ElasticSearch v6 indices
+	docTypeV6 = "_doc"
+)
+
+type (
+	// elasticWrapper implements Client for ElasticSearch v6
+	elasticWrapper struct {
+		client *elastic.Client
+	}
+
+	scrollServiceImpl struct {
+		scrollService *elastic.ScrollService
+	}
+)
+
+var _ Client = (*elasticWrapper)(nil)
+
+func newClientV6(config *Config) (Client, error) {
+	client, err := elastic.NewClient(
+		elastic.SetURL(config.URL.String()),
+		elastic.SetRetrier(elastic.NewBackoffRetrier(elastic.NewExponentialBackoff(128*time.Millisecond, 513*time.Millisecond))),
+		elastic.SetDecoder(&elastic.NumberDecoder{}), // critical to ensure decode of int64 won't lose precise
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewWrapperClient(client), nil
+}
+
+// NewWrapperClient returns a new implementation of Client for ElasticSearch v6
+func NewWrapperClient(esClient *elastic.Client) Client {
+	return &elasticWrapper{client: esClient}
+}
+
+func (c *elasticWrapper) Search(ctx context.Context, p *SearchParameters) (*elastic.SearchResult, error) {
+	searchService := c.client.Search(p.Index).
+		Query(p.Query).
+		From(p.From).
+		SortBy(p.Sorter...)
+
+	if p.PageSize != 0 {
+		searchService.Size(p.PageSize)
+	}
+
+	if len(p.SearchAfter) != 0 {
+		searchService.SearchAfter(p.SearchAfter...)
+	}
+
+	return searchService.Do(ctx)
+}
+
+func (c *elasticWrapper) SearchWithDSL(ctx context.Context, index, query string) (*elastic.SearchResult, error) {
+	return c.client.Search(index).Source(query).Do(ctx)
+}
+
+func (c *elasticWrapper) Scroll(ctx context.Context, scrollID string) (
+	*elastic.SearchResult, ScrollService, error) {
+
+	scrollService := elastic.NewScrollService(c.client)
+	result, err := scrollService.ScrollId(scrollID).Do(ctx)
+	return result, &scrollServiceImpl{scrollService}, err
+}
+
+func (c *elasticWrapper) ScrollFirstPage(ctx context.Context, index, query string) (
+	*elastic.SearchResult, ScrollService, error) {
+
+	scrollService := elastic.NewScrollService(c.client)
+	result, err := scrollService.Index(index).Body(query).Do(ctx)
+	return result, &scrollServiceImpl{scrollService}, err
+}
+
+func (c *elasticWrapper) Count(ctx context.Context, index, query string) (int64, error) {
+	return c.client.Count(index).BodyString(query).Do(ctx)
+}
+
+func (c *elasticWrapper) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error) {
+	return c.client.BulkProcessor().
+		Name(p.Name).
+		Workers(p.NumOfWorkers).
+		BulkActions(p.BulkActions).
+		BulkSize(p.BulkSize).
+		FlushInterval(p.FlushInterval).
+		Backoff(p.Backoff).
+		Before(p.BeforeFunc).
+		After(p.AfterFunc).
+		Do(ctx)
+}
+
+// root is for nested object like Attr property for search attributes.
+func (c *elasticWrapper) PutMapping(ctx context.Context, index, root, key, valueType string) error {
+	return c.PutMappingBody(ctx, index, buildPutMappingBody(root, key, valueType))
+}
+
+func (c *elasticWrapper) CreateIndex(ctx context.Context, index string) error {
+	_, err := c.client.CreateIndex(index).Do(ctx)
+	return err
+}
+
+func (c *elasticWrapper) IndexExists(ctx context.Context, index string) (bool, error) {
+	return c.client.IndexExists(index).Do(ctx)
+}
+
+func (c *elasticWrapper) PutIndexTemplate(ctx context.Context, name, bodyString string) error {
+	_, err := c.client.IndexPutTemplate(name).BodyString(bodyString).Do(ctx)
+	return err
+}
+
+func (c *elasticWrapper) GetMapping(ctx context.Context, index string) (map[string]interface{}, error) {
+	resp, err := c.client.GetMapping().Index(index).Type(docTypeV6).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// v6 response is keyed by index and then by mapping type
+	mappings := getNestedMap(resp, index, "mappings", docTypeV6)
+	if mappings == nil {
+		return map[string]interface{}{}, nil
+	}
+	return mappings, nil
+}
+
+func (c *elasticWrapper) PutMappingBody(ctx context.Context, index string, body map[string]interface{}) error {
+	_, err := c.client.PutMapping().Index(index).Type(docTypeV6).BodyJson(body).Do(ctx)
+	return err
+}
+
+func (s *scrollServiceImpl) Clear(ctx context.Context) error {
+	return s.scrollService.Clear(ctx)
+}
+
+// getNestedMap walks down nested maps following the keys, it returns nil if any of the keys is missing
+func getNestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	elastic7 "github.com/olivere/elastic/v7"
+)
+
+type (
+	// elasticWrapperV7 implements Client for ElasticSearch v7,
+	// it converts requests and results from and to the types of the v6 elastic library
+	elasticWrapperV7 struct {
+		client *elastic7.Client
+	}
+
+	scrollServiceImplV7 struct {
+		scrollService *elastic7.ScrollService
+	}
+
+	bulkProcessorImplV7 struct {
+		processor *elastic7.BulkProcessor
+	}
+
+	// typelessBulkableRequest strips the mapping type from the action metadata of a v6 bulk request,
+	// mapping types are deprecated in ElasticSearch v7
+	typelessBulkableRequest struct {
+		request elastic.BulkableRequest
+	}
+)
+
+var _ Client = (*elasticWrapperV7)(nil)
+var _ BulkProcessor = (*bulkProcessorImplV7)(nil)
+
+func newClientV7(config *Config) (Client, error) {
+	client, err := elastic7.NewClient(
+		elastic7.SetURL(config.URL.String()),
+		elastic7.SetRetrier(elastic7.NewBackoffRetrier(elastic7.NewExponentialBackoff(128*time.Millisecond, 513*time.Millisecond))),
+		elastic7.SetDecoder(&elastic7.NumberDecoder{}), // critical to ensure decode of int64 won't lose precise
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &elasticWrapperV7{client: client}, nil
+}
+
+func (c *elasticWrapperV7) Search(ctx context.Context, p *SearchParameters) (*elastic.SearchResult, error) {
+	sorters := make([]elastic7.Sorter, 0, len(p.Sorter))
+	for _, sorter := range p.Sorter {
+		sorters = append(sorters, sorter)
+	}
+	searchService := c.client.Search(p.Index).
+		Query(p.Query).
+		From(p.From).
+		SortBy(sorters...).
+		TrackTotalHits(true)
+
+	if p.PageSize != 0 {
+		searchService.Size(p.PageSize)
+	}
+
+	if len(p.SearchAfter) != 0 {
+		searchService.SearchAfter(p.SearchAfter...)
+	}
+
+	result, err := searchService.Do(ctx)
+	return convertSearchResultV7(result), convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) SearchWithDSL(ctx context.Context, index, query string) (*elastic.SearchResult, error) {
+	query, err := withTrackTotalHits(query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.client.Search(index).Source(query).Do(ctx)
+	return convertSearchResultV7(result), convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) Scroll(ctx context.Context, scrollID string) (
+	*elastic.SearchResult, ScrollService, error) {
+
+	scrollService := elastic7.NewScrollService(c.client)
+	result, err := scrollService.ScrollId(scrollID).Do(ctx)
+	return convertSearchResultV7(result), &scrollServiceImplV7{scrollService}, convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) ScrollFirstPage(ctx context.Context, index, query string) (
+	*elastic.SearchResult, ScrollService, error) {
+
+	scrollService := elastic7.NewScrollService(c.client)
+	result, err := scrollService.Index(index).Body(query).Do(ctx)
+	return convertSearchResultV7(result), &scrollServiceImplV7{scrollService}, convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) Count(ctx context.Context, index, query string) (int64, error) {
+	count, err := c.client.Count(index).BodyString(query).Do(ctx)
+	return count, convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) RunBulkProcessor(ctx context.Context, p *BulkProcessorParameters) (BulkProcessor, error) {
+	beforeFunc := func(executionID int64, requests []elastic7.BulkableRequest) {
+		if p.BeforeFunc != nil {
+			p.BeforeFunc(executionID, convertBulkableRequestsV7(requests))
+		}
+	}
+	afterFunc := func(executionID int64, requests []elastic7.BulkableRequest, response *elastic7.BulkResponse, err error) {
+		if p.AfterFunc != nil {
+			p.AfterFunc(executionID, convertBulkableRequestsV7(requests), convertBulkResponseV7(response), convertErrorV7(err))
+		}
+	}
+
+	processor, err := c.client.BulkProcessor().
+		Name(p.Name).
+		Workers(p.NumOfWorkers).
+		BulkActions(p.BulkActions).
+		BulkSize(p.BulkSize).
+		FlushInterval(p.FlushInterval).
+		Backoff(p.Backoff).
+		Before(beforeFunc).
+		After(afterFunc).
+		Do(ctx)
+	if err != nil {
+		return nil, convertErrorV7(err)
+	}
+	return &bulkProcessorImplV7{processor: processor}, nil
+}
+
+// root is for nested object like Attr property for search attributes.
+func (c *elasticWrapperV7) PutMapping(ctx context.Context, index, root, key, valueType string) error {
+	return c.PutMappingBody(ctx, index, buildPutMappingBody(root, key, valueType))
+}
+
+func (c *elasticWrapperV7) CreateIndex(ctx context.Context, index string) error {
+	_, err := c.client.CreateIndex(index).Do(ctx)
+	return convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) IndexExists(ctx context.Context, index string) (bool, error) {
+	exists, err := c.client.IndexExists(index).Do(ctx)
+	return exists, convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) PutIndexTemplate(ctx context.Context, name, bodyString string) error {
+	_, err := c.client.IndexPutTemplate(name).BodyString(bodyString).Do(ctx)
+	return convertErrorV7(err)
+}
+
+func (c *elasticWrapperV7) GetMapping(ctx context.Context, index string) (map[string]interface{}, error) {
+	resp, err := c.client.GetMapping().Index(index).Do(ctx)
+	if err != nil {
+		return nil, convertErrorV7(err)
+	}
+	mappings := getNestedMap(resp, index, "mappings")
+	if mappings == nil {
+		return map[string]interface{}{}, nil
+	}
+	return mappings, nil
+}
+
+func (c *elasticWrapperV7) PutMappingBody(ctx context.Context, index string, body map[string]interface{}) error {
+	_, err := c.client.PutMapping().Index(index).BodyJson(body).Do(ctx)
+	return convertErrorV7(err)
+}
+
+func (s *scrollServiceImplV7) Clear(ctx context.Context) error {
+	return convertErrorV7(s.scrollService.Clear(ctx))
+}
+
+func (p *bulkProcessorImplV7) Start(ctx context.Context) error {
+	return convertErrorV7(p.processor.Start(ctx))
+}
+
+func (p *bulkProcessorImplV7) Stop() error {
+	return convertErrorV7(p.processor.Stop())
+}
+
+func (p *bulkProcessorImplV7) Close() error {
+	return convertErrorV7(p.processor.Close())
+}
+
+func (p *bulkProcessorImplV7) Stats() elastic.BulkProcessorStats {
+	stats := p.processor.Stats()
+	result := elastic.BulkProcessorStats{
+		Flushed:   stats.Flushed,
+		Committed: stats.Committed,
+		Indexed:   stats.Indexed,
+		Created:   stats.Created,
+		Updated:   stats.Updated,
+		Deleted:   stats.Deleted,
+		Succeeded: stats.Succeeded,
+		Failed:    stats.Failed,
+	}
+	for _, worker := range stats.Workers {
+		result.Workers = append(result.Workers, &elastic.BulkProcessorWorkerStats{
+			Queued:       worker.Queued,
+			LastDuration: worker.LastDuration,
+		})
+	}
+	return result
+}
+
+func (p *bulkProcessorImplV7) Add(request elastic.BulkableRequest) {
+	p.processor.Add(&typelessBulkableRequest{request: request})
+}
+
+func (p *bulkProcessorImplV7) Flush() error {
+	return convertErrorV7(p.processor.Flush())
+}
+
+func (r *typelessBulkableRequest) String() string {
+	return r.request.String()
+}
+
+func (r *typelessBulkableRequest) Source() ([]string, error) {
+	lines, err := r.request.Source()
+	if err != nil || len(lines) == 0 {
+		return lines, err
+	}
+
+	// the first line is the action metadata, e.g. {"index":{"_index":"idx","_type":"_doc","_id":"id"}}
+	var action map[string]map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(lines[0]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&action); err != nil {
+		return nil, err
+	}
+	for _, metadata := range action {
+		delete(metadata, "_type")
+	}
+	metadataLine, err := json.Marshal(action)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(lines))
+	result[0] = string(metadataLine)
+	copy(result[1:], lines[1:])
+	return result, nil
+}
+
+// withTrackTotalHits makes ElasticSearch v7 count all hits of the query instead of the first 10000 only
+func withTrackTotalHits(query string) (string, error) {
+	var body map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(query))
+	decoder.UseNumber() // keep int64 values such as timestamps precise
+	if err := decoder.Decode(&body); err != nil {
+		return "", err
+	}
+	body["track_total_hits"] = true
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func convertBulkableRequestsV7(requests []elastic7.BulkableRequest) []elastic.BulkableRequest {
+	result := make([]elastic.BulkableRequest, 0, len(requests))
+	for _, request := range requests {
+		if typeless, ok := request.(*typelessBulkableRequest); ok {
+			result = append(result, typeless.request)
+		} else {
+			result = append(result, request)
+		}
+	}
+	return result
+}
+
+func convertSearchResultV7(result *elastic7.SearchResult) *elastic.SearchResult {
+	if result == nil {
+		return nil
+	}
+
+	converted := &elastic.SearchResult{
+		TookInMillis: result.TookInMillis,
+		ScrollId:     result.ScrollId,
+		TimedOut:     result.TimedOut,
+	}
+	if result.Aggregations != nil {
+		converted.Aggregations = make(elastic.Aggregations, len(result.Aggregations))
+		for name, aggregation := range result.Aggregations {
+			aggregation := aggregation
+			converted.Aggregations[name] = &aggregation
+		}
+	}
+	if result.Hits != nil {
+		converted.Hits = &elastic.SearchHits{
+			MaxScore: result.Hits.MaxScore,
+		}
+		if result.Hits.TotalHits != nil {
+			converted.Hits.TotalHits = result.Hits.TotalHits.Value
+		}
+		for _, hit := range result.Hits.Hits {
+			convertedHit := &elastic.SearchHit{
+				Score:   hit.Score,
+				Index:   hit.Index,
+				Type:    hit.Type,
+				Id:      hit.Id,
+				Routing: hit.Routing,
+				Version: hit.Version,
+				Sort:    hit.Sort,
+				Fields:  hit.Fields,
+			}
+			if hit.Source != nil {
+				source := hit.Source
+				convertedHit.Source = &source
+			}
+			converted.Hits.Hits = append(converted.Hits.Hits, convertedHit)
+		}
+	}
+	return converted
+}
+
+func convertBulkResponseV7(response *elastic7.BulkResponse) *elastic.BulkResponse {
+	if response == nil {
+		return nil
+	}
+
+	// bulk response items of both versions share the same json representation
+	var converted elastic.BulkResponse
+	if err := convertViaJSON(response, &converted); err != nil {
+		return &elastic.BulkResponse{Took: response.Took, Errors: response.Errors}
+	}
+	return &converted
+}
+
+func convertErrorV7(err error) error {
+	esErr, ok := err.(*elastic7.Error)
+	if !ok {
+		return err
+	}
+
+	converted := &elastic.Error{Status: esErr.Status}
+	if esErr.Details != nil {
+		converted.Details = &elastic.ErrorDetails{}
+		if err := convertViaJSON(esErr.Details, converted.Details); err != nil {
+			converted.Details = &elastic.ErrorDetails{Type: esErr.Details.Type, Reason: esErr.Details.Reason}
+		}
+	}
+	return converted
+}
+
+func convertViaJSON(from interface{}, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(to)
+}
@@ -21,16 +21,25 @@
 package elasticsearch
 
 import (
+	"fmt"
 	"net/url"
 
 	"github.com/temporalio/temporal/common"
 )
 
+// Supported ElasticSearch versions
+const (
+	VersionV6 = "v6"
+	VersionV7 = "v7"
+)
+
 // Config for connecting to ElasticSearch
 type (
 	Config struct {
 		URL     url.URL           `yaml:url`     //nolint:govet
 		Indices map[string]string `yaml:indices` //nolint:govet
+		// Version is the major version of the ElasticSearch cluster, v6 if not set
+		Version string `yaml:version` //nolint:govet
 	}
 )
 
@@ -38,3 +47,21 @@ type (
 func (cfg *Config) GetVisibilityIndex() string {
 	return cfg.Indices[common.VisibilityAppName]
 }
+
+// GetVersion returns the major version of the ElasticSearch cluster
+func (cfg *Config) GetVersion() string {
+	if cfg.Version == "" {
+		return VersionV6
+	}
+	return cfg.Version
+}
+
+// Validate validates the ElasticSearch config
+func (cfg *Config) Validate() error {
+	switch cfg.GetVersion() {
+	case VersionV6, VersionV7:
+		return nil
+	default:
+		return fmt.Errorf("unsupported ElasticSearch version: %v, supported versions are %v and %v", cfg.Version, VersionV6, VersionV7)
+	}
+}
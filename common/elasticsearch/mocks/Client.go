@@ -70,6 +70,64 @@ func (_m *Client) CreateIndex(ctx context.Context, index string) error {
 	return r0
 }
 
+// GetMapping provides a mock function with given fields: ctx, index
+func (_m *Client) GetMapping(ctx context.Context, index string) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, index)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]interface{}); ok {
+		r0 = rf(ctx, index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IndexExists provides a mock function with given fields: ctx, index
+func (_m *Client) IndexExists(ctx context.Context, index string) (bool, error) {
+	ret := _m.Called(ctx, index)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, index)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutIndexTemplate provides a mock function with given fields: ctx, name, bodyString
+func (_m *Client) PutIndexTemplate(ctx context.Context, name string, bodyString string) error {
+	ret := _m.Called(ctx, name, bodyString)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, bodyString)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PutMapping provides a mock function with given fields: ctx, index, root, key, valueType
 func (_m *Client) PutMapping(ctx context.Context, index string, root string, key string, valueType string) error {
 	ret := _m.Called(ctx, index, root, key, valueType)
@@ -84,16 +142,30 @@ func (_m *Client) PutMapping(ctx context.Context, index string, root string, key
 	return r0
 }
 
+// PutMappingBody provides a mock function with given fields: ctx, index, body
+func (_m *Client) PutMappingBody(ctx context.Context, index string, body map[string]interface{}) error {
+	ret := _m.Called(ctx, index, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) error); ok {
+		r0 = rf(ctx, index, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RunBulkProcessor provides a mock function with given fields: ctx, p
-func (_m *Client) RunBulkProcessor(ctx context.Context, p *elasticsearch.BulkProcessorParameters) (*elastic.BulkProcessor, error) {
+func (_m *Client) RunBulkProcessor(ctx context.Context, p *elasticsearch.BulkProcessorParameters) (elasticsearch.BulkProcessor, error) {
 	ret := _m.Called(ctx, p)
 
-	var r0 *elastic.BulkProcessor
-	if rf, ok := ret.Get(0).(func(context.Context, *elasticsearch.BulkProcessorParameters) *elastic.BulkProcessor); ok {
+	var r0 elasticsearch.BulkProcessor
+	if rf, ok := ret.Get(0).(func(context.Context, *elasticsearch.BulkProcessorParameters) elasticsearch.BulkProcessor); ok {
 		r0 = rf(ctx, p)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*elastic.BulkProcessor)
+			r0 = ret.Get(0).(elasticsearch.BulkProcessor)
 		}
 	}
 
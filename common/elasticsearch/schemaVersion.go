@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"context"
+)
+
+const (
+	// schemaVersionMetaKey is the key of the schema version in the _meta field of the index mapping
+	schemaVersionMetaKey = "schema_version"
+)
+
+// GetSchemaVersion returns the schema version recorded in the mapping of the index,
+// it returns empty string if the mapping has no schema version
+func GetSchemaVersion(ctx context.Context, client Client, index string) (string, error) {
+	mapping, err := client.GetMapping(ctx, index)
+	if err != nil {
+		return "", err
+	}
+	meta := getNestedMap(mapping, "_meta")
+	if meta == nil {
+		return "", nil
+	}
+	version, _ := meta[schemaVersionMetaKey].(string)
+	return version, nil
+}
+
+// PutSchemaVersion records the schema version in the mapping of the index
+func PutSchemaVersion(ctx context.Context, client Client, index, version string) error {
+	return client.PutMappingBody(ctx, index, map[string]interface{}{
+		"_meta": map[string]interface{}{
+			schemaVersionMetaKey: version,
+		},
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mappingClient records the mapping bodies put to it and returns them from GetMapping
+type mappingClient struct {
+	Client
+	mock.Mock
+}
+
+func (c *mappingClient) GetMapping(ctx context.Context, index string) (map[string]interface{}, error) {
+	args := c.Called(ctx, index)
+	mapping, _ := args.Get(0).(map[string]interface{})
+	return mapping, args.Error(1)
+}
+
+func (c *mappingClient) PutMappingBody(ctx context.Context, index string, body map[string]interface{}) error {
+	return c.Called(ctx, index, body).Error(0)
+}
+
+func Test_GetSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	client := &mappingClient{}
+	client.On("GetMapping", ctx, "versioned").Return(map[string]interface{}{
+		"_meta":      map[string]interface{}{schemaVersionMetaKey: "0.2"},
+		"properties": map[string]interface{}{},
+	}, nil)
+	client.On("GetMapping", ctx, "unversioned").Return(map[string]interface{}{
+		"properties": map[string]interface{}{},
+	}, nil)
+	client.On("GetMapping", ctx, "missing").Return(nil, errors.New("index not found"))
+
+	version, err := GetSchemaVersion(ctx, client, "versioned")
+	require.NoError(t, err)
+	require.Equal(t, "0.2", version)
+
+	version, err = GetSchemaVersion(ctx, client, "unversioned")
+	require.NoError(t, err)
+	require.Equal(t, "", version)
+
+	_, err = GetSchemaVersion(ctx, client, "missing")
+	require.Error(t, err)
+}
+
+func Test_PutSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	client := &mappingClient{}
+	client.On("PutMappingBody", ctx, "index", map[string]interface{}{
+		"_meta": map[string]interface{}{schemaVersionMetaKey: "0.3"},
+	}).Return(nil).Once()
+
+	require.NoError(t, PutSchemaVersion(ctx, client, "index", "0.3"))
+	client.AssertExpectations(t)
+}
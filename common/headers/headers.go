@@ -22,6 +22,7 @@ package headers
 
 import (
 	"context"
+	"strconv"
 
 	"google.golang.org/grpc/metadata"
 )
@@ -44,6 +45,67 @@ const (
 	// ClientImplHeaderName refers to the name of the
 	// header that contains the client implementation
 	ClientImplHeaderName = "temporal-sdk-name"
+
+	// The headers below carry request and response fields of the
+	// frontend API which the workflowservice messages of the
+	// go.temporal.io/temporal-proto module do not have yet. Only
+	// the frontend reads and writes them, the internal services
+	// get the fields in their own requests and responses. They
+	// are to be replaced by fields of the workflowservice messages
+	// once the API module has them.
+
+	// CompletionCallbackHeaderName refers to the name of the
+	// header that carries the completion callbacks of a
+	// StartWorkflowExecution request, one callback per value
+	CompletionCallbackHeaderName = "temporal-completion-callback"
+
+	// EagerActivityTasksHeaderName refers to the name of the
+	// header that carries the max number of activity tasks the
+	// worker is willing to receive eagerly in the
+	// RespondDecisionTaskCompleted response
+	EagerActivityTasksHeaderName = "temporal-eager-activity-tasks"
+
+	// EagerActivityTaskHeaderName refers to the name of the
+	// binary response header that carries the activity tasks
+	// started eagerly, one serialized PollForActivityTaskResponse
+	// per value
+	EagerActivityTaskHeaderName = "temporal-eager-activity-task-bin"
+
+	// ActivityConcurrencyHeaderName refers to the name of the
+	// binary response header of DescribeTaskList that carries
+	// the activity concurrency of the task list, one serialized
+	// ActivityConcurrencyInfo per activity type
+	ActivityConcurrencyHeaderName = "temporal-activity-concurrency-bin"
+
+	// HistoryEventTypeHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that restricts the
+	// returned events to the given event types, one type per value
+	HistoryEventTypeHeaderName = "temporal-history-event-type"
+
+	// HistoryMinEventIDHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that carries the
+	// smallest event ID to return
+	HistoryMinEventIDHeaderName = "temporal-history-min-event-id"
+
+	// HistoryMaxEventIDHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that carries the
+	// largest event ID to return
+	HistoryMaxEventIDHeaderName = "temporal-history-max-event-id"
+
+	// HistoryStartTimeHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that carries the
+	// earliest event time to return, in RFC3339 format
+	HistoryStartTimeHeaderName = "temporal-history-start-time"
+
+	// HistoryEndTimeHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that carries the
+	// latest event time to return, in RFC3339 format
+	HistoryEndTimeHeaderName = "temporal-history-end-time"
+
+	// HistoryOmitPayloadsHeaderName refers to the name of the
+	// header of GetWorkflowExecutionHistory that asks to clear
+	// the payloads (inputs, results, details) of returned events
+	HistoryOmitPayloadsHeaderName = "temporal-history-omit-payloads"
 )
 
 // GetValues returns header values for passed header names.
@@ -60,6 +122,27 @@ func GetValues(ctx context.Context, headerNames ...string) []string {
 	return headerValues
 }
 
+// GetCompletionCallbacks returns all completion callbacks attached to the incoming request.
+func GetCompletionCallbacks(ctx context.Context) []string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		return md.Get(CompletionCallbackHeaderName)
+	}
+	return nil
+}
+
+// GetMaxEagerActivityTasks returns the max number of eager activity tasks requested by the worker, 0 if not set.
+func GetMaxEagerActivityTasks(ctx context.Context) int {
+	value := GetValues(ctx, EagerActivityTasksHeaderName)[0]
+	if value == "" {
+		return 0
+	}
+	maxTasks, err := strconv.Atoi(value)
+	if err != nil || maxTasks < 0 {
+		return 0
+	}
+	return maxTasks
+}
+
 // PropagateVersions propagates version headers from incoming context to outgoing context.
 // It copies all version headers to outgoing context only if they are exist in incoming context
 // and doesn't exist in outgoing context already.
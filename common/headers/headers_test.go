@@ -123,3 +123,31 @@ func (s *HeadersSuite) TestPropagateHeaders_EmptyIncomingContext() {
 	s.Equal("21.04.16", md.Get(FeatureVersionHeaderName)[0])
 	s.Equal("28.08.14", md.Get(ClientImplHeaderName)[0])
 }
+
+func (s *HeadersSuite) TestGetCompletionCallbacks() {
+	ctx := context.Background()
+	s.Empty(GetCompletionCallbacks(ctx))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		CompletionCallbackHeaderName, "https://example.com/done",
+		CompletionCallbackHeaderName, "temporal://other-domain/other-workflow?signal=done",
+	))
+	s.Equal([]string{
+		"https://example.com/done",
+		"temporal://other-domain/other-workflow?signal=done",
+	}, GetCompletionCallbacks(ctx))
+}
+
+func (s *HeadersSuite) TestGetMaxEagerActivityTasks() {
+	ctx := context.Background()
+	s.Equal(0, GetMaxEagerActivityTasks(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(EagerActivityTasksHeaderName, "3"))
+	s.Equal(3, GetMaxEagerActivityTasks(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(EagerActivityTasksHeaderName, "-1"))
+	s.Equal(0, GetMaxEagerActivityTasks(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(EagerActivityTasksHeaderName, "some"))
+	s.Equal(0, GetMaxEagerActivityTasks(ctx))
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package historyfilter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/temporalio/temporal/.gen/proto/token"
+	"github.com/temporalio/temporal/common/headers"
+)
+
+const eventTypePrefix = "EventType"
+
+var (
+	// PayloadFields are the event attribute fields carrying user payloads, which are all their bytes fields
+	PayloadFields = []string{
+		"Input",
+		"Result",
+		"Details",
+		"FailureDetails",
+		"ContinuedFailureDetails",
+		"LastFailureDetails",
+		"LastCompletionResult",
+		"ExecutionContext",
+		"Control",
+	}
+	// MetadataFields are the event attribute fields carrying user metadata
+	MetadataFields = []string{
+		"Header",
+		"Memo",
+		"SearchAttributes",
+	}
+)
+
+// FromIncomingContext builds the history event filter from the headers of the incoming request,
+// it returns nil if the request carries no filter headers.
+func FromIncomingContext(ctx context.Context) (*token.HistoryEventFilter, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	filter := &token.HistoryEventFilter{}
+	for _, value := range md.Get(headers.HistoryEventTypeHeaderName) {
+		for _, name := range strings.Split(value, ",") {
+			eventType, err := ParseEventType(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			filter.EventTypes = append(filter.EventTypes, eventType)
+		}
+	}
+
+	var err error
+	if filter.MinEventId, err = parseEventID(md, headers.HistoryMinEventIDHeaderName); err != nil {
+		return nil, err
+	}
+	if filter.MaxEventId, err = parseEventID(md, headers.HistoryMaxEventIDHeaderName); err != nil {
+		return nil, err
+	}
+	if filter.MaxEventId > 0 && filter.MinEventId > filter.MaxEventId {
+		return nil, serviceerror.NewInvalidArgument("Min event ID is larger than max event ID.")
+	}
+	if filter.StartTime, err = parseTime(md, headers.HistoryStartTimeHeaderName); err != nil {
+		return nil, err
+	}
+	if filter.EndTime, err = parseTime(md, headers.HistoryEndTimeHeaderName); err != nil {
+		return nil, err
+	}
+	if filter.EndTime > 0 && filter.StartTime > filter.EndTime {
+		return nil, serviceerror.NewInvalidArgument("Start time is later than end time.")
+	}
+	if values := md.Get(headers.HistoryOmitPayloadsHeaderName); len(values) > 0 {
+		if filter.OmitPayloads, err = strconv.ParseBool(values[0]); err != nil {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid %v header: %v.", headers.HistoryOmitPayloadsHeaderName, values[0]))
+		}
+	}
+
+	if IsEmpty(filter) {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// AppendToOutgoingContext attaches the filter to the headers of the outgoing request.
+func AppendToOutgoingContext(ctx context.Context, filter *token.HistoryEventFilter) context.Context {
+	if IsEmpty(filter) {
+		return ctx
+	}
+
+	var kv []string
+	for _, eventType := range filter.EventTypes {
+		kv = append(kv, headers.HistoryEventTypeHeaderName, eventType.String())
+	}
+	if filter.MinEventId > 0 {
+		kv = append(kv, headers.HistoryMinEventIDHeaderName, strconv.FormatInt(filter.MinEventId, 10))
+	}
+	if filter.MaxEventId > 0 {
+		kv = append(kv, headers.HistoryMaxEventIDHeaderName, strconv.FormatInt(filter.MaxEventId, 10))
+	}
+	if filter.StartTime > 0 {
+		kv = append(kv, headers.HistoryStartTimeHeaderName, time.Unix(0, filter.StartTime).UTC().Format(time.RFC3339Nano))
+	}
+	if filter.EndTime > 0 {
+		kv = append(kv, headers.HistoryEndTimeHeaderName, time.Unix(0, filter.EndTime).UTC().Format(time.RFC3339Nano))
+	}
+	if filter.OmitPayloads {
+		kv = append(kv, headers.HistoryOmitPayloadsHeaderName, "true")
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// IsEmpty returns true if the filter does not restrict or project any event.
+func IsEmpty(filter *token.HistoryEventFilter) bool {
+	return filter == nil ||
+		(len(filter.EventTypes) == 0 &&
+			filter.MinEventId == 0 &&
+			filter.MaxEventId == 0 &&
+			filter.StartTime == 0 &&
+			filter.EndTime == 0 &&
+			!filter.OmitPayloads)
+}
+
+// IsPastMaxEventID returns true if the events from nextEventID onwards are all beyond the max event ID of the filter.
+func IsPastMaxEventID(filter *token.HistoryEventFilter, nextEventID int64) bool {
+	return filter != nil && filter.MaxEventId > 0 && nextEventID > filter.MaxEventId
+}
+
+// Match returns true if the event is accepted by the filter.
+func Match(filter *token.HistoryEventFilter, event *commonproto.HistoryEvent) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.MinEventId > 0 && event.GetEventId() < filter.MinEventId {
+		return false
+	}
+	if filter.MaxEventId > 0 && event.GetEventId() > filter.MaxEventId {
+		return false
+	}
+	if filter.StartTime > 0 && event.GetTimestamp() < filter.StartTime {
+		return false
+	}
+	if filter.EndTime > 0 && event.GetTimestamp() > filter.EndTime {
+		return false
+	}
+	if len(filter.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range filter.EventTypes {
+		if event.GetEventType() == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the events accepted by the filter, their payloads are cleared in place
+// if the filter omits payloads.
+func Apply(filter *token.HistoryEventFilter, events []*commonproto.HistoryEvent) []*commonproto.HistoryEvent {
+	if filter == nil {
+		return events
+	}
+
+	result := make([]*commonproto.HistoryEvent, 0, len(events))
+	for _, event := range events {
+		if Match(filter, event) {
+			result = append(result, event)
+		}
+	}
+	if filter.OmitPayloads {
+		ClearFields(result, PayloadFields)
+	}
+	return result
+}
+
+// ClearFields clears the named fields of the event attributes in place.
+func ClearFields(events []*commonproto.HistoryEvent, fields []string) {
+	for _, event := range events {
+		if event.Attributes == nil {
+			continue
+		}
+		// Attributes is a oneof wrapper holding a pointer to the attributes of the event type
+		wrapper := reflect.ValueOf(event.Attributes).Elem()
+		if wrapper.Kind() != reflect.Struct || wrapper.NumField() != 1 || wrapper.Field(0).IsNil() {
+			continue
+		}
+		attributes := wrapper.Field(0).Elem()
+		for _, name := range fields {
+			if field := attributes.FieldByName(name); field.IsValid() && field.CanSet() {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+	}
+}
+
+// ParseEventType parses an event type name, with or without the EventType prefix.
+func ParseEventType(name string) (enums.EventType, error) {
+	if value, ok := enums.EventType_value[name]; ok {
+		return enums.EventType(value), nil
+	}
+	if value, ok := enums.EventType_value[eventTypePrefix+name]; ok {
+		return enums.EventType(value), nil
+	}
+	return 0, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid %v header: unknown event type %v.", headers.HistoryEventTypeHeaderName, name))
+}
+
+func parseEventID(md metadata.MD, headerName string) (int64, error) {
+	values := md.Get(headerName)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	eventID, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || eventID < 0 {
+		return 0, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid %v header: %v.", headerName, values[0]))
+	}
+	return eventID, nil
+}
+
+func parseTime(md metadata.MD, headerName string) (int64, error) {
+	values := md.Get(headerName)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, values[0])
+	if err != nil {
+		return 0, serviceerror.NewInvalidArgument(fmt.Sprintf("Invalid %v header: %v.", headerName, values[0]))
+	}
+	return t.UnixNano(), nil
+}
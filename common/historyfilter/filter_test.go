@@ -0,0 +1,233 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package historyfilter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/temporalio/temporal/.gen/proto/token"
+	"github.com/temporalio/temporal/common/headers"
+)
+
+type (
+	filterSuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestFilterSuite(t *testing.T) {
+	suite.Run(t, new(filterSuite))
+}
+
+func (s *filterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *filterSuite) TestFromIncomingContext_NoHeaders() {
+	filter, err := FromIncomingContext(context.Background())
+	s.NoError(err)
+	s.Nil(filter)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(headers.LibraryVersionHeaderName, "0.20.0"))
+	filter, err = FromIncomingContext(ctx)
+	s.NoError(err)
+	s.Nil(filter)
+}
+
+func (s *filterSuite) TestFromIncomingContext() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		headers.HistoryEventTypeHeaderName, "EventTypeActivityTaskScheduled",
+		headers.HistoryEventTypeHeaderName, "ActivityTaskCompleted, ActivityTaskFailed",
+		headers.HistoryMinEventIDHeaderName, "5",
+		headers.HistoryMaxEventIDHeaderName, "100",
+		headers.HistoryStartTimeHeaderName, "2020-03-01T00:00:00Z",
+		headers.HistoryEndTimeHeaderName, "2020-03-02T00:00:00.5Z",
+		headers.HistoryOmitPayloadsHeaderName, "true",
+	))
+	filter, err := FromIncomingContext(ctx)
+	s.NoError(err)
+	s.Equal(&token.HistoryEventFilter{
+		EventTypes: []enums.EventType{
+			enums.EventTypeActivityTaskScheduled,
+			enums.EventTypeActivityTaskCompleted,
+			enums.EventTypeActivityTaskFailed,
+		},
+		MinEventId:   5,
+		MaxEventId:   100,
+		StartTime:    time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+		EndTime:      time.Date(2020, 3, 2, 0, 0, 0, 5e8, time.UTC).UnixNano(),
+		OmitPayloads: true,
+	}, filter)
+}
+
+func (s *filterSuite) TestFromIncomingContext_Invalid() {
+	for _, kv := range [][]string{
+		{headers.HistoryEventTypeHeaderName, "NoSuchEvent"},
+		{headers.HistoryMinEventIDHeaderName, "abc"},
+		{headers.HistoryMaxEventIDHeaderName, "-1"},
+		{headers.HistoryMinEventIDHeaderName, "10", headers.HistoryMaxEventIDHeaderName, "5"},
+		{headers.HistoryStartTimeHeaderName, "yesterday"},
+		{headers.HistoryStartTimeHeaderName, "2020-03-02T00:00:00Z", headers.HistoryEndTimeHeaderName, "2020-03-01T00:00:00Z"},
+		{headers.HistoryOmitPayloadsHeaderName, "maybe"},
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+		_, err := FromIncomingContext(ctx)
+		s.Error(err, kv)
+	}
+}
+
+func (s *filterSuite) TestAppendToOutgoingContext() {
+	filter := &token.HistoryEventFilter{
+		EventTypes:   []enums.EventType{enums.EventTypeTimerStarted, enums.EventTypeTimerFired},
+		MinEventId:   3,
+		MaxEventId:   30,
+		StartTime:    time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+		EndTime:      time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC).UnixNano(),
+		OmitPayloads: true,
+	}
+	ctx := AppendToOutgoingContext(context.Background(), filter)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	s.True(ok)
+
+	result, err := FromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+	s.NoError(err)
+	s.Equal(filter, result)
+
+	ctx = AppendToOutgoingContext(context.Background(), &token.HistoryEventFilter{})
+	_, ok = metadata.FromOutgoingContext(ctx)
+	s.False(ok)
+}
+
+func (s *filterSuite) TestIsPastMaxEventID() {
+	s.False(IsPastMaxEventID(nil, 20))
+	s.False(IsPastMaxEventID(&token.HistoryEventFilter{MaxEventId: 30}, 20))
+	s.True(IsPastMaxEventID(&token.HistoryEventFilter{MaxEventId: 30}, 31))
+}
+
+func (s *filterSuite) TestApply() {
+	events := s.newEvents()
+	s.Equal(events, Apply(nil, events))
+
+	result := Apply(&token.HistoryEventFilter{
+		EventTypes: []enums.EventType{enums.EventTypeActivityTaskScheduled, enums.EventTypeActivityTaskCompleted},
+	}, s.newEvents())
+	s.Len(result, 2)
+	s.Equal(int64(2), result[0].GetEventId())
+	s.Equal(int64(3), result[1].GetEventId())
+	s.Equal([]byte("result"), result[1].GetActivityTaskCompletedEventAttributes().GetResult())
+
+	result = Apply(&token.HistoryEventFilter{MinEventId: 2, MaxEventId: 2}, s.newEvents())
+	s.Len(result, 1)
+	s.Equal(int64(2), result[0].GetEventId())
+
+	result = Apply(&token.HistoryEventFilter{StartTime: 200, EndTime: 300}, s.newEvents())
+	s.Len(result, 2)
+	s.Equal(int64(2), result[0].GetEventId())
+	s.Equal(int64(3), result[1].GetEventId())
+}
+
+func (s *filterSuite) TestApply_OmitPayloads() {
+	result := Apply(&token.HistoryEventFilter{OmitPayloads: true}, s.newEvents())
+	s.Len(result, 3)
+
+	started := result[0].GetWorkflowExecutionStartedEventAttributes()
+	s.Nil(started.GetInput())
+	s.Equal("some-workflow-type", started.GetWorkflowType().GetName())
+	s.NotNil(started.GetMemo())
+	s.Nil(result[1].GetActivityTaskScheduledEventAttributes().GetInput())
+	s.Equal("some-activity", result[1].GetActivityTaskScheduledEventAttributes().GetActivityId())
+	s.Nil(result[2].GetActivityTaskCompletedEventAttributes().GetResult())
+}
+
+func (s *filterSuite) TestClearFields() {
+	events := s.newEvents()
+	ClearFields(events, MetadataFields)
+
+	started := events[0].GetWorkflowExecutionStartedEventAttributes()
+	s.Nil(started.GetMemo())
+	s.Equal([]byte("input"), started.GetInput())
+}
+
+func (s *filterSuite) TestPayloadFields() {
+	bytesType := reflect.TypeOf([]byte(nil))
+	for _, oneofWrapper := range (&commonproto.HistoryEvent{}).XXX_OneofWrappers() {
+		// set every bytes field of the attributes of the event type
+		wrapper := reflect.New(reflect.TypeOf(oneofWrapper).Elem())
+		attributes := reflect.New(wrapper.Elem().Field(0).Type().Elem())
+		wrapper.Elem().Field(0).Set(attributes)
+		for i := 0; i < attributes.Elem().NumField(); i++ {
+			if field := attributes.Elem().Field(i); field.Type() == bytesType {
+				field.SetBytes([]byte("payload"))
+			}
+		}
+		event := &commonproto.HistoryEvent{}
+		reflect.ValueOf(event).Elem().FieldByName("Attributes").Set(wrapper)
+
+		ClearFields([]*commonproto.HistoryEvent{event}, PayloadFields)
+		for i := 0; i < attributes.Elem().NumField(); i++ {
+			if field := attributes.Elem().Field(i); field.Type() == bytesType {
+				s.Nil(field.Bytes(), "%v.%v is not a payload field", attributes.Elem().Type().Name(), attributes.Elem().Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func (s *filterSuite) newEvents() []*commonproto.HistoryEvent {
+	return []*commonproto.HistoryEvent{
+		{
+			EventId:   1,
+			Timestamp: 100,
+			EventType: enums.EventTypeWorkflowExecutionStarted,
+			Attributes: &commonproto.HistoryEvent_WorkflowExecutionStartedEventAttributes{WorkflowExecutionStartedEventAttributes: &commonproto.WorkflowExecutionStartedEventAttributes{
+				WorkflowType: &commonproto.WorkflowType{Name: "some-workflow-type"},
+				Input:        []byte("input"),
+				Memo:         &commonproto.Memo{Fields: map[string][]byte{"key": []byte("value")}},
+			}},
+		},
+		{
+			EventId:   2,
+			Timestamp: 200,
+			EventType: enums.EventTypeActivityTaskScheduled,
+			Attributes: &commonproto.HistoryEvent_ActivityTaskScheduledEventAttributes{ActivityTaskScheduledEventAttributes: &commonproto.ActivityTaskScheduledEventAttributes{
+				ActivityId: "some-activity",
+				Input:      []byte("input"),
+			}},
+		},
+		{
+			EventId:   3,
+			Timestamp: 300,
+			EventType: enums.EventTypeActivityTaskCompleted,
+			Attributes: &commonproto.HistoryEvent_ActivityTaskCompletedEventAttributes{ActivityTaskCompletedEventAttributes: &commonproto.ActivityTaskCompletedEventAttributes{
+				Result: []byte("result"),
+			}},
+		},
+	}
+}
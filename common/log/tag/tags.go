@@ -188,6 +188,11 @@ func WorkflowDomainIDs(domainIDs interface{}) Tag {
 	return newObjectTag("wf-domain-ids", domainIDs)
 }
 
+// WorkflowStuckReason returns tag for the diagnosis of a stuck workflow
+func WorkflowStuckReason(reason string) Tag {
+	return newStringTag("wf-stuck-reason", reason)
+}
+
 // history event ID related
 
 // WorkflowEventID returns tag for WorkflowEventID
@@ -106,6 +106,7 @@ var (
 	ComponentTimerBuilder             = component("timer-builder")
 	ComponentReplicatorQueue          = component("replicator-queue-processor")
 	ComponentShardController          = component("shard-controller")
+	ComponentShardRouter              = component("shard-router")
 	ComponentShard                    = component("shard")
 	ComponentShardItem                = component("shard-item")
 	ComponentShardEngine              = component("shard-engine")
@@ -119,6 +120,8 @@ var (
 	ComponentESVisibilityManager      = component("es-visibility-manager")
 	ComponentArchiver                 = component("archiver")
 	ComponentBatcher                  = component("batcher")
+	ComponentDomainMigrator           = component("domain-migrator")
+	ComponentResharder                = component("resharder")
 	ComponentWorker                   = component("worker")
 	ComponentServiceResolver          = component("service-resolver")
 	ComponentMetadataInitializer      = component("metadata-initializer")
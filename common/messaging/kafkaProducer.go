@@ -27,6 +27,7 @@ import (
 	"github.com/Shopify/sarama"
 	"go.temporal.io/temporal-proto/enums"
 
+	"github.com/temporalio/temporal/.gen/proto/historystream"
 	"github.com/temporalio/temporal/.gen/proto/indexer"
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common/codec"
@@ -149,6 +150,19 @@ func (p *kafkaProducer) getProducerMessage(message interface{}) (*sarama.Produce
 			Value: sarama.ByteEncoder(payload),
 		}
 		return msg, nil
+	case *historystream.Message:
+		payload, err := p.serializeProto(message)
+		if err != nil {
+			return nil, err
+		}
+		// Use runID as the partition key so all history events of a run are dispatched to the same
+		// Kafka partition in the order they are published
+		msg := &sarama.ProducerMessage{
+			Topic: p.topic,
+			Key:   sarama.StringEncoder(message.GetRunID()),
+			Value: sarama.ByteEncoder(payload),
+		}
+		return msg, nil
 	default:
 		return nil, errors.New("unknown producer message type")
 	}
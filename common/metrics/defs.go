@@ -152,12 +152,20 @@ const (
 	PersistenceDeleteTaskScope
 	// PersistenceGetCurrentExecutionScope tracks GetCurrentExecution calls made by service to persistence layer
 	PersistenceGetCurrentExecutionScope
+	// PersistenceListConcreteExecutionsScope tracks ListConcreteExecutions calls made by service to persistence layer
+	PersistenceListConcreteExecutionsScope
 	// PersistenceGetTransferTasksScope tracks GetTransferTasks calls made by service to persistence layer
 	PersistenceGetTransferTasksScope
 	// PersistenceCompleteTransferTaskScope tracks CompleteTransferTasks calls made by service to persistence layer
 	PersistenceCompleteTransferTaskScope
 	// PersistenceRangeCompleteTransferTaskScope tracks CompleteTransferTasks calls made by service to persistence layer
 	PersistenceRangeCompleteTransferTaskScope
+	// PersistencePutTransferTaskToDLQScope tracks PutTransferTaskToDLQ calls made by service to persistence layer
+	PersistencePutTransferTaskToDLQScope
+	// PersistenceGetTransferTasksFromDLQScope tracks GetTransferTasksFromDLQ calls made by service to persistence layer
+	PersistenceGetTransferTasksFromDLQScope
+	// PersistenceRangeDeleteTransferTaskFromDLQScope tracks RangeDeleteTransferTaskFromDLQ calls made by service to persistence layer
+	PersistenceRangeDeleteTransferTaskFromDLQScope
 	// PersistenceGetReplicationTasksScope tracks GetReplicationTasks calls made by service to persistence layer
 	PersistenceGetReplicationTasksScope
 	// PersistenceCompleteReplicationTaskScope tracks CompleteReplicationTasks calls made by service to persistence layer
@@ -178,6 +186,12 @@ const (
 	PersistenceCompleteTimerTaskScope
 	// PersistenceRangeCompleteTimerTaskScope tracks CompleteTimerTasks calls made by service to persistence layer
 	PersistenceRangeCompleteTimerTaskScope
+	// PersistencePutTimerTaskToDLQScope tracks PutTimerTaskToDLQ calls made by service to persistence layer
+	PersistencePutTimerTaskToDLQScope
+	// PersistenceGetTimerTasksFromDLQScope tracks GetTimerTasksFromDLQ calls made by service to persistence layer
+	PersistenceGetTimerTasksFromDLQScope
+	// PersistenceRangeDeleteTimerTaskFromDLQScope tracks RangeDeleteTimerTaskFromDLQ calls made by service to persistence layer
+	PersistenceRangeDeleteTimerTaskFromDLQScope
 	// PersistenceCreateTaskScope tracks CreateTask calls made by service to persistence layer
 	PersistenceCreateTaskScope
 	// PersistenceGetTasksScope tracks GetTasks calls made by service to persistence layer
@@ -270,6 +284,10 @@ const (
 	PersistenceInitImmutableClusterMetadataScope
 	// PersistenceGetImmutableClusterMetadataScope tracks GetImmutableClusterMetadata calls made by service to persistence layer
 	PersistenceGetImmutableClusterMetadataScope
+	// PersistenceGetHistoryShardRoutingScope tracks GetHistoryShardRouting calls made by service to persistence layer
+	PersistenceGetHistoryShardRoutingScope
+	// PersistenceUpdateHistoryShardRoutingScope tracks UpdateHistoryShardRouting calls made by service to persistence layer
+	PersistenceUpdateHistoryShardRoutingScope
 	// PersistenceUpsertClusterMembershipScope tracks UpsertClusterMembership calls made by service to persistence layer
 	PersistenceUpsertClusterMembershipScope
 	// PersistencePruneClusterMembershipScope tracks PruneClusterMembership calls made by service to persistence layer
@@ -330,8 +348,12 @@ const (
 	HistoryClientSyncActivityScope
 	// HistoryClientGetReplicationTasksScope tracks RPC calls to history service
 	HistoryClientGetReplicationTasksScope
+	// HistoryClientStreamReplicationMessagesScope tracks RPC calls to history service
+	HistoryClientStreamReplicationMessagesScope
 	// HistoryClientGetDLQReplicationTasksScope tracks RPC calls to history service
 	HistoryClientGetDLQReplicationTasksScope
+	// HistoryClientGetDomainHandoverStatusScope tracks RPC calls to history service
+	HistoryClientGetDomainHandoverStatusScope
 	// HistoryClientQueryWorkflowScope tracks RPC calls to history service
 	HistoryClientQueryWorkflowScope
 	// HistoryClientReapplyEventsScope tracks RPC calls to history service
@@ -344,6 +366,16 @@ const (
 	HistoryClientMergeDLQMessagesScope
 	// HistoryClientRefreshWorkflowTasksScope tracks RPC calls to history service
 	HistoryClientRefreshWorkflowTasksScope
+	// HistoryClientRehydrateWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientRehydrateWorkflowExecutionScope
+	// HistoryClientImportWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientImportWorkflowExecutionScope
+	// HistoryClientReadTaskDLQMessagesScope tracks RPC calls to history service
+	HistoryClientReadTaskDLQMessagesScope
+	// HistoryClientPurgeTaskDLQMessagesScope tracks RPC calls to history service
+	HistoryClientPurgeTaskDLQMessagesScope
+	// HistoryClientMergeTaskDLQMessagesScope tracks RPC calls to history service
+	HistoryClientMergeTaskDLQMessagesScope
 	// MatchingClientPollForDecisionTaskScope tracks RPC calls to matching service
 	MatchingClientPollForDecisionTaskScope
 	// MatchingClientPollForActivityTaskScope tracks RPC calls to matching service
@@ -362,6 +394,12 @@ const (
 	MatchingClientDescribeTaskListScope
 	// MatchingClientListTaskListPartitionsScope tracks RPC calls to matching service
 	MatchingClientListTaskListPartitionsScope
+	// MatchingClientUnloadTaskListScope tracks RPC calls to matching service
+	MatchingClientUnloadTaskListScope
+	// MatchingClientAcquireActivityTaskSlotScope tracks RPC calls to matching service
+	MatchingClientAcquireActivityTaskSlotScope
+	// MatchingClientReleaseActivityTaskSlotScope tracks RPC calls to matching service
+	MatchingClientReleaseActivityTaskSlotScope
 	// FrontendClientDeprecateDomainScope tracks RPC calls to frontend service
 	FrontendClientDeprecateDomainScope
 	// FrontendClientDescribeDomainScope tracks RPC calls to frontend service
@@ -440,6 +478,8 @@ const (
 	FrontendClientGetSearchAttributesScope
 	// FrontendClientGetReplicationTasksScope tracks RPC calls to frontend service
 	FrontendClientGetReplicationTasksScope
+	// FrontendClientStreamReplicationMessagesScope tracks RPC calls to frontend service
+	FrontendClientStreamReplicationMessagesScope
 	// FrontendClientGetDomainReplicationTasksScope tracks RPC calls to frontend service
 	FrontendClientGetDomainReplicationTasksScope
 	// FrontendClientGetDLQReplicationTasksScope tracks RPC calls to frontend service
@@ -472,6 +512,28 @@ const (
 	AdminClientMergeDLQMessagesScope
 	// AdminClientRefreshWorkflowTasksScope tracks RPC calls to admin service
 	AdminClientRefreshWorkflowTasksScope
+	// AdminClientStartDomainHandoverScope tracks RPC calls to admin service
+	AdminClientStartDomainHandoverScope
+	// AdminClientDescribeDomainHandoverScope tracks RPC calls to admin service
+	AdminClientDescribeDomainHandoverScope
+	// AdminClientUnloadTaskListScope tracks RPC calls to admin service
+	AdminClientUnloadTaskListScope
+	// AdminClientRehydrateWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientRehydrateWorkflowExecutionScope
+	// AdminClientExportWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientExportWorkflowExecutionScope
+	// AdminClientImportWorkflowExecutionScope tracks RPC calls to admin service
+	AdminClientImportWorkflowExecutionScope
+	// AdminClientStartDomainMigrationScope tracks RPC calls to admin service
+	AdminClientStartDomainMigrationScope
+	// AdminClientEndDomainMigrationScope tracks RPC calls to admin service
+	AdminClientEndDomainMigrationScope
+	// AdminClientReadTaskDLQMessagesScope tracks RPC calls to admin service
+	AdminClientReadTaskDLQMessagesScope
+	// AdminClientPurgeTaskDLQMessagesScope tracks RPC calls to admin service
+	AdminClientPurgeTaskDLQMessagesScope
+	// AdminClientMergeTaskDLQMessagesScope tracks RPC calls to admin service
+	AdminClientMergeTaskDLQMessagesScope
 	// DCRedirectionDeprecateDomainScope tracks RPC calls for dc redirection
 	DCRedirectionDeprecateDomainScope
 	// DCRedirectionDescribeDomainScope tracks RPC calls for dc redirection
@@ -581,6 +643,8 @@ const (
 	PersistenceCompleteForkBranchScope
 	// PersistenceGetHistoryTreeScope tracks GetHistoryTree calls made by service to persistence layer
 	PersistenceGetHistoryTreeScope
+	// PersistenceMoveHistoryTreeScope tracks MoveHistoryTree calls made by service to persistence layer
+	PersistenceMoveHistoryTreeScope
 	// PersistenceGetAllHistoryTreeBranchesScope tracks GetHistoryTree calls made by service to persistence layer
 	PersistenceGetAllHistoryTreeBranchesScope
 	// PersistenceDomainReplicationQueueScope is the metrics scope for domain replication queue
@@ -665,6 +729,8 @@ const (
 	AdminGetWorkflowExecutionRawHistoryV2Scope
 	// AdminGetReplicationMessagesScope is the metric scope for admin.GetReplicationMessages
 	AdminGetReplicationMessagesScope
+	// AdminStreamReplicationMessagesScope is the metric scope for admin.StreamReplicationMessages
+	AdminStreamReplicationMessagesScope
 	// AdminGetDomainReplicationMessagesScope is the metric scope for admin.GetDomainReplicationMessages
 	AdminGetDomainReplicationMessagesScope
 	// AdminGetDLQReplicationMessagesScope is the metric scope for admin.GetDLQReplicationMessages
@@ -673,10 +739,32 @@ const (
 	AdminReapplyEventsScope
 	// AdminRefreshWorkflowTasksScope is the metric scope for admin.RefreshWorkflowTasks
 	AdminRefreshWorkflowTasksScope
+	// AdminRehydrateWorkflowExecutionScope is the metric scope for admin.RehydrateWorkflowExecution
+	AdminRehydrateWorkflowExecutionScope
+	// AdminExportWorkflowExecutionScope is the metric scope for admin.ExportWorkflowExecution
+	AdminExportWorkflowExecutionScope
+	// AdminImportWorkflowExecutionScope is the metric scope for admin.ImportWorkflowExecution
+	AdminImportWorkflowExecutionScope
+	// AdminStartDomainMigrationScope is the metric scope for admin.StartDomainMigration
+	AdminStartDomainMigrationScope
+	// AdminEndDomainMigrationScope is the metric scope for admin.EndDomainMigration
+	AdminEndDomainMigrationScope
+	// AdminReadTaskDLQMessagesScope is the metric scope for admin.ReadTaskDLQMessages
+	AdminReadTaskDLQMessagesScope
+	// AdminPurgeTaskDLQMessagesScope is the metric scope for admin.PurgeTaskDLQMessages
+	AdminPurgeTaskDLQMessagesScope
+	// AdminMergeTaskDLQMessagesScope is the metric scope for admin.MergeTaskDLQMessages
+	AdminMergeTaskDLQMessagesScope
 	// AdminRemoveTaskScope is the metric scope for admin.AdminRemoveTaskScope
 	AdminRemoveTaskScope
 	//AdminCloseShardTaskScope is the metric scope for admin.AdminRemoveTaskScope
 	AdminCloseShardTaskScope
+	// AdminStartDomainHandoverScope is the metric scope for admin.StartDomainHandover
+	AdminStartDomainHandoverScope
+	// AdminDescribeDomainHandoverScope is the metric scope for admin.DescribeDomainHandover
+	AdminDescribeDomainHandoverScope
+	// AdminUnloadTaskListScope is the metric scope for admin.UnloadTaskList
+	AdminUnloadTaskListScope
 	//AdminReadDLQMessagesScope is the metric scope for admin.AdminReadDLQMessagesScope
 	AdminReadDLQMessagesScope
 	//AdminPurgeDLQMessagesScope is the metric scope for admin.AdminPurgeDLQMessagesScope
@@ -827,8 +915,12 @@ const (
 	HistoryDescribeMutableStateScope
 	// GetReplicationMessages tracks GetReplicationMessages API calls received by service
 	HistoryGetReplicationMessagesScope
+	// HistoryStreamReplicationMessagesScope tracks StreamReplicationMessages API calls received by service
+	HistoryStreamReplicationMessagesScope
 	// HistoryGetDLQReplicationMessagesScope tracks GetReplicationMessages API calls received by service
 	HistoryGetDLQReplicationMessagesScope
+	// HistoryGetDomainHandoverStatusScope tracks GetDomainHandoverStatus API calls received by service
+	HistoryGetDomainHandoverStatusScope
 	// HistoryReadDLQMessagesScope tracks ReadDLQMessages API calls received by service
 	HistoryReadDLQMessagesScope
 	// HistoryPurgeDLQMessagesScope tracks PurgeDLQMessages API calls received by service
@@ -841,6 +933,16 @@ const (
 	HistoryReapplyEventsScope
 	// HistoryRefreshWorkflowTasksScope is the scope used by refresh workflow tasks API
 	HistoryRefreshWorkflowTasksScope
+	// HistoryRehydrateWorkflowExecutionScope is the scope used by rehydrate workflow execution API
+	HistoryRehydrateWorkflowExecutionScope
+	// HistoryImportWorkflowExecutionScope is the scope used by import workflow execution API
+	HistoryImportWorkflowExecutionScope
+	// HistoryReadTaskDLQMessagesScope tracks ReadTaskDLQMessages API calls received by service
+	HistoryReadTaskDLQMessagesScope
+	// HistoryPurgeTaskDLQMessagesScope tracks PurgeTaskDLQMessages API calls received by service
+	HistoryPurgeTaskDLQMessagesScope
+	// HistoryMergeTaskDLQMessagesScope tracks MergeTaskDLQMessages API calls received by service
+	HistoryMergeTaskDLQMessagesScope
 	// TaskPriorityAssignerScope is the scope used by all metric emitted by task priority assigner
 	TaskPriorityAssignerScope
 	// TransferQueueProcessorScope is the scope used by all metric emitted by transfer queue processor
@@ -849,6 +951,8 @@ const (
 	TransferActiveQueueProcessorScope
 	// TransferStandbyQueueProcessorScope is the scope used by all metric emitted by transfer queue processor
 	TransferStandbyQueueProcessorScope
+	// TransferSplitQueueProcessorScope is the scope used by all metric emitted by the transfer queues of split domains
+	TransferSplitQueueProcessorScope
 	// TransferActiveTaskActivityScope is the scope used for activity task processing by transfer queue processor
 	TransferActiveTaskActivityScope
 	// TransferActiveTaskDecisionScope is the scope used for decision task processing by transfer queue processor
@@ -867,6 +971,10 @@ const (
 	TransferActiveTaskResetWorkflowScope
 	// TransferActiveTaskUpsertWorkflowSearchAttributesScope is the scope used for upsert search attributes processing by transfer queue processor
 	TransferActiveTaskUpsertWorkflowSearchAttributesScope
+	// TransferActiveTaskCompletionCallbackScope is the scope used for completion callback processing by transfer queue processor
+	TransferActiveTaskCompletionCallbackScope
+	// TransferActiveTaskHistoryStreamScope is the scope used for history stream processing by transfer queue processor
+	TransferActiveTaskHistoryStreamScope
 	// TransferStandbyTaskResetWorkflowScope is the scope used for record workflow started task processing by transfer queue processor
 	TransferStandbyTaskResetWorkflowScope
 	// TransferStandbyTaskActivityScope is the scope used for activity task processing by transfer queue processor
@@ -885,12 +993,18 @@ const (
 	TransferStandbyTaskRecordWorkflowStartedScope
 	// TransferStandbyTaskUpsertWorkflowSearchAttributesScope is the scope used for upsert search attributes processing by transfer queue processor
 	TransferStandbyTaskUpsertWorkflowSearchAttributesScope
+	// TransferStandbyTaskCompletionCallbackScope is the scope used for completion callback processing by transfer queue processor
+	TransferStandbyTaskCompletionCallbackScope
+	// TransferStandbyTaskHistoryStreamScope is the scope used for history stream processing by transfer queue processor
+	TransferStandbyTaskHistoryStreamScope
 	// TimerQueueProcessorScope is the scope used by all metric emitted by timer queue processor
 	TimerQueueProcessorScope
 	// TimerActiveQueueProcessorScope is the scope used by all metric emitted by timer queue processor
 	TimerActiveQueueProcessorScope
 	// TimerQueueProcessorScope is the scope used by all metric emitted by timer queue processor
 	TimerStandbyQueueProcessorScope
+	// TimerSplitQueueProcessorScope is the scope used by all metric emitted by the timer queues of split domains
+	TimerSplitQueueProcessorScope
 	// TimerActiveTaskActivityTimeoutScope is the scope used by metric emitted by timer queue processor for processing activity timeouts
 	TimerActiveTaskActivityTimeoutScope
 	// TimerActiveTaskDecisionTimeoutScope is the scope used by metric emitted by timer queue processor for processing decision timeouts
@@ -949,6 +1063,10 @@ const (
 	EventsCacheDeleteEventScope
 	// EventsCacheGetFromStoreScope is the scope used by events cache
 	EventsCacheGetFromStoreScope
+	// HistoryCacheStatsScope is the scope used by history cache for hit, miss, eviction and size stats
+	HistoryCacheStatsScope
+	// EventsCacheStatsScope is the scope used by events cache for hit, miss, eviction and size stats
+	EventsCacheStatsScope
 	// ExecutionSizeStatsScope is the scope used for emiting workflow execution size related stats
 	ExecutionSizeStatsScope
 	// ExecutionCountStatsScope is the scope used for emiting workflow execution count related stats
@@ -973,6 +1091,10 @@ const (
 	ReplicationTaskCleanupScope
 	// ReplicationDLQStatsScope is scope used by all metrics emitted related to replication DLQ
 	ReplicationDLQStatsScope
+	// TransferTaskDLQStatsScope is scope used by all metrics emitted related to the transfer task DLQ
+	TransferTaskDLQStatsScope
+	// TimerTaskDLQStatsScope is scope used by all metrics emitted related to the timer task DLQ
+	TimerTaskDLQStatsScope
 
 	NumHistoryScopes
 )
@@ -999,6 +1121,12 @@ const (
 	MatchingDescribeTaskListScope
 	// MatchingListTaskListPartitionsScope tracks ListTaskListPartitions API calls received by service
 	MatchingListTaskListPartitionsScope
+	// MatchingUnloadTaskListScope tracks UnloadTaskList API calls received by service
+	MatchingUnloadTaskListScope
+	// MatchingAcquireActivityTaskSlotScope tracks AcquireActivityTaskSlot API calls received by service
+	MatchingAcquireActivityTaskSlotScope
+	// MatchingReleaseActivityTaskSlotScope tracks ReleaseActivityTaskSlot API calls received by service
+	MatchingReleaseActivityTaskSlotScope
 
 	NumMatchingScopes
 )
@@ -1045,6 +1173,12 @@ const (
 	HistoryScavengerScope
 	// ParentClosePolicyProcessorScope is scope used by all metrics emitted by worker.ParentClosePolicyProcessor
 	ParentClosePolicyProcessorScope
+	// DomainHandoverScope is scope used by all metrics emitted by worker.failover.HandoverProcessor
+	DomainHandoverScope
+	// DomainMigrationScope is scope used by all metrics emitted by worker.migration module
+	DomainMigrationScope
+	// StuckWorkflowScannerScope is scope used by all metrics emitted by worker.stuck.Detector module
+	StuckWorkflowScannerScope
 
 	NumWorkerScopes
 )
@@ -1065,9 +1199,13 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceDeleteCurrentWorkflowExecutionScope:           {operation: "DeleteCurrentWorkflowExecution"},
 		PersistenceDeleteTaskScope:                               {operation: "PersistenceDelete"},
 		PersistenceGetCurrentExecutionScope:                      {operation: "GetCurrentExecution"},
+		PersistenceListConcreteExecutionsScope:                   {operation: "ListConcreteExecutions"},
 		PersistenceGetTransferTasksScope:                         {operation: "GetTransferTasks"},
 		PersistenceCompleteTransferTaskScope:                     {operation: "CompleteTransferTask"},
 		PersistenceRangeCompleteTransferTaskScope:                {operation: "RangeCompleteTransferTask"},
+		PersistencePutTransferTaskToDLQScope:                     {operation: "PutTransferTaskToDLQ"},
+		PersistenceGetTransferTasksFromDLQScope:                  {operation: "GetTransferTasksFromDLQ"},
+		PersistenceRangeDeleteTransferTaskFromDLQScope:           {operation: "RangeDeleteTransferTaskFromDLQ"},
 		PersistenceGetReplicationTasksScope:                      {operation: "GetReplicationTasks"},
 		PersistenceCompleteReplicationTaskScope:                  {operation: "CompleteReplicationTask"},
 		PersistenceRangeCompleteReplicationTaskScope:             {operation: "RangeCompleteReplicationTask"},
@@ -1078,6 +1216,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceGetTimerIndexTasksScope:                       {operation: "GetTimerIndexTasks"},
 		PersistenceCompleteTimerTaskScope:                        {operation: "CompleteTimerTask"},
 		PersistenceRangeCompleteTimerTaskScope:                   {operation: "RangeCompleteTimerTask"},
+		PersistencePutTimerTaskToDLQScope:                        {operation: "PutTimerTaskToDLQ"},
+		PersistenceGetTimerTasksFromDLQScope:                     {operation: "GetTimerTasksFromDLQ"},
+		PersistenceRangeDeleteTimerTaskFromDLQScope:              {operation: "RangeDeleteTimerTaskFromDLQ"},
 		PersistenceCreateTaskScope:                               {operation: "CreateTask"},
 		PersistenceGetTasksScope:                                 {operation: "GetTasks"},
 		PersistenceCompleteTaskScope:                             {operation: "CompleteTask"},
@@ -1117,6 +1258,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceDeleteHistoryBranchScope:                      {operation: "DeleteHistoryBranch"},
 		PersistenceCompleteForkBranchScope:                       {operation: "CompleteForkBranch"},
 		PersistenceGetHistoryTreeScope:                           {operation: "GetHistoryTree"},
+		PersistenceMoveHistoryTreeScope:                          {operation: "MoveHistoryTree"},
 		PersistenceGetAllHistoryTreeBranchesScope:                {operation: "GetAllHistoryTreeBranches"},
 		PersistenceEnqueueMessageScope:                           {operation: "EnqueueMessage"},
 		PersistenceEnqueueMessageToDLQScope:                      {operation: "EnqueueMessageToDLQ"},
@@ -1132,6 +1274,8 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceDomainReplicationQueueScope:                   {operation: "DomainReplicationQueue"},
 		PersistenceInitImmutableClusterMetadataScope:             {operation: "InitializeImmutableClusterMetadata"},
 		PersistenceGetImmutableClusterMetadataScope:              {operation: "GetImmutableClusterMetadata"},
+		PersistenceGetHistoryShardRoutingScope:                   {operation: "GetHistoryShardRouting"},
+		PersistenceUpdateHistoryShardRoutingScope:                {operation: "UpdateHistoryShardRouting"},
 		PersistencePruneClusterMembershipScope:                   {operation: "PruneClusterMembership"},
 		PersistenceGetClusterMembersScope:                        {operation: "GetClusterMembership"},
 		PersistenceUpsertClusterMembershipScope:                  {operation: "UpsertClusterMembership"},
@@ -1165,13 +1309,20 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryClientSyncShardStatusScope:                     {operation: "HistoryClientSyncShardStatusScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientSyncActivityScope:                        {operation: "HistoryClientSyncActivityScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientGetReplicationTasksScope:                 {operation: "HistoryClientGetReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientStreamReplicationMessagesScope:           {operation: "HistoryClientStreamReplicationMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientGetDLQReplicationTasksScope:              {operation: "HistoryClientGetDLQReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientGetDomainHandoverStatusScope:             {operation: "HistoryClientGetDomainHandoverStatus", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientQueryWorkflowScope:                       {operation: "HistoryClientQueryWorkflowScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientReapplyEventsScope:                       {operation: "HistoryClientReapplyEventsScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientReadDLQMessagesScope:                     {operation: "HistoryClientReadDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientPurgeDLQMessagesScope:                    {operation: "HistoryClientPurgeDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientMergeDLQMessagesScope:                    {operation: "HistoryClientMergeDLQMessagesScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		HistoryClientRefreshWorkflowTasksScope:                {operation: "HistoryClientRefreshWorkflowTasksScope", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientRehydrateWorkflowExecutionScope:          {operation: "HistoryClientRehydrateWorkflowExecution", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientImportWorkflowExecutionScope:             {operation: "HistoryClientImportWorkflowExecution", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientReadTaskDLQMessagesScope:                 {operation: "HistoryClientReadTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientPurgeTaskDLQMessagesScope:                {operation: "HistoryClientPurgeTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
+		HistoryClientMergeTaskDLQMessagesScope:                {operation: "HistoryClientMergeTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: HistoryRoleTagValue}},
 		MatchingClientPollForDecisionTaskScope:                {operation: "MatchingClientPollForDecisionTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientPollForActivityTaskScope:                {operation: "MatchingClientPollForActivityTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientAddActivityTaskScope:                    {operation: "MatchingClientAddActivityTask", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
@@ -1181,6 +1332,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		MatchingClientCancelOutstandingPollScope:              {operation: "MatchingClientCancelOutstandingPoll", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientDescribeTaskListScope:                   {operation: "MatchingClientDescribeTaskList", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		MatchingClientListTaskListPartitionsScope:             {operation: "MatchingClientListTaskListPartitions", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientUnloadTaskListScope:                     {operation: "MatchingClientUnloadTaskList", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientAcquireActivityTaskSlotScope:            {operation: "MatchingClientAcquireActivityTaskSlot", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
+		MatchingClientReleaseActivityTaskSlotScope:            {operation: "MatchingClientReleaseActivityTaskSlot", tags: map[string]string{ServiceRoleTagName: MatchingRoleTagValue}},
 		FrontendClientDeprecateDomainScope:                    {operation: "FrontendClientDeprecateDomain", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientDescribeDomainScope:                     {operation: "FrontendClientDescribeDomain", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientDescribeTaskListScope:                   {operation: "FrontendClientDescribeTaskList", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
@@ -1220,6 +1374,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		FrontendClientCountWorkflowExecutionsScope:            {operation: "FrontendClientCountWorkflowExecutions", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientGetSearchAttributesScope:                {operation: "FrontendClientGetSearchAttributes", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientGetReplicationTasksScope:                {operation: "FrontendClientGetReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
+		FrontendClientStreamReplicationMessagesScope:          {operation: "FrontendClientStreamReplicationMessagesScope", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientGetDomainReplicationTasksScope:          {operation: "FrontendClientGetDomainReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientGetDLQReplicationTasksScope:             {operation: "FrontendClientGetDLQReplicationTasksScope", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
 		FrontendClientReapplyEventsScope:                      {operation: "FrontendClientReapplyEventsScope", tags: map[string]string{ServiceRoleTagName: FrontendRoleTagValue}},
@@ -1232,6 +1387,17 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminClientGetWorkflowExecutionRawHistoryV2Scope:      {operation: "AdminClientGetWorkflowExecutionRawHistoryV2", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientDescribeClusterScope:                       {operation: "AdminClientDescribeCluster", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientRefreshWorkflowTasksScope:                  {operation: "AdminClientRefreshWorkflowTasks", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientStartDomainHandoverScope:                   {operation: "AdminClientStartDomainHandover", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientDescribeDomainHandoverScope:                {operation: "AdminClientDescribeDomainHandover", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientUnloadTaskListScope:                        {operation: "AdminClientUnloadTaskList", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientRehydrateWorkflowExecutionScope:            {operation: "AdminClientRehydrateWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientExportWorkflowExecutionScope:               {operation: "AdminClientExportWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientImportWorkflowExecutionScope:               {operation: "AdminClientImportWorkflowExecution", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientStartDomainMigrationScope:                  {operation: "AdminClientStartDomainMigration", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientEndDomainMigrationScope:                    {operation: "AdminClientEndDomainMigration", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientReadTaskDLQMessagesScope:                   {operation: "AdminClientReadTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientPurgeTaskDLQMessagesScope:                  {operation: "AdminClientPurgeTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
+		AdminClientMergeTaskDLQMessagesScope:                  {operation: "AdminClientMergeTaskDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientCloseShardScope:                            {operation: "AdminClientCloseShard", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientReadDLQMessagesScope:                       {operation: "AdminClientReadDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
 		AdminClientPurgeDLQMessagesScope:                      {operation: "AdminClientPurgeDLQMessages", tags: map[string]string{ServiceRoleTagName: AdminRoleTagValue}},
@@ -1320,6 +1486,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		// Admin API scope co-locates with with frontend
 		AdminRemoveTaskScope:                       {operation: "AdminRemoveTask"},
 		AdminCloseShardTaskScope:                   {operation: "AdminCloseShardTask"},
+		AdminStartDomainHandoverScope:              {operation: "AdminStartDomainHandover"},
+		AdminDescribeDomainHandoverScope:           {operation: "AdminDescribeDomainHandover"},
+		AdminUnloadTaskListScope:                   {operation: "AdminUnloadTaskList"},
 		AdminReadDLQMessagesScope:                  {operation: "AdminReadDLQMessages"},
 		AdminPurgeDLQMessagesScope:                 {operation: "AdminPurgeDLQMessages"},
 		AdminMergeDLQMessagesScope:                 {operation: "AdminMergeDLQMessages"},
@@ -1329,10 +1498,19 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminGetWorkflowExecutionRawHistoryScope:   {operation: "GetWorkflowExecutionRawHistory"},
 		AdminGetWorkflowExecutionRawHistoryV2Scope: {operation: "GetWorkflowExecutionRawHistoryV2"},
 		AdminGetReplicationMessagesScope:           {operation: "GetReplicationMessages"},
+		AdminStreamReplicationMessagesScope:        {operation: "StreamReplicationMessages"},
 		AdminGetDomainReplicationMessagesScope:     {operation: "GetDomainReplicationMessages"},
 		AdminGetDLQReplicationMessagesScope:        {operation: "AdminGetDLQReplicationMessages"},
 		AdminReapplyEventsScope:                    {operation: "ReapplyEvents"},
 		AdminRefreshWorkflowTasksScope:             {operation: "RefreshWorkflowTasks"},
+		AdminRehydrateWorkflowExecutionScope:       {operation: "RehydrateWorkflowExecution"},
+		AdminExportWorkflowExecutionScope:          {operation: "ExportWorkflowExecution"},
+		AdminImportWorkflowExecutionScope:          {operation: "ImportWorkflowExecution"},
+		AdminStartDomainMigrationScope:             {operation: "AdminStartDomainMigration"},
+		AdminEndDomainMigrationScope:               {operation: "AdminEndDomainMigration"},
+		AdminReadTaskDLQMessagesScope:              {operation: "AdminReadTaskDLQMessages"},
+		AdminPurgeTaskDLQMessagesScope:             {operation: "AdminPurgeTaskDLQMessages"},
+		AdminMergeTaskDLQMessagesScope:             {operation: "AdminMergeTaskDLQMessages"},
 
 		FrontendStartWorkflowExecutionScope:             {operation: "StartWorkflowExecution"},
 		FrontendPollForDecisionTaskScope:                {operation: "PollForDecisionTask"},
@@ -1406,17 +1584,25 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistorySyncActivityScope:                               {operation: "SyncActivity"},
 		HistoryDescribeMutableStateScope:                       {operation: "DescribeMutableState"},
 		HistoryGetReplicationMessagesScope:                     {operation: "GetReplicationMessages"},
+		HistoryStreamReplicationMessagesScope:                  {operation: "StreamReplicationMessages"},
 		HistoryGetDLQReplicationMessagesScope:                  {operation: "GetDLQReplicationMessages"},
+		HistoryGetDomainHandoverStatusScope:                    {operation: "GetDomainHandoverStatus"},
 		HistoryReadDLQMessagesScope:                            {operation: "ReadDLQMessages"},
 		HistoryPurgeDLQMessagesScope:                           {operation: "PurgeDLQMessages"},
 		HistoryMergeDLQMessagesScope:                           {operation: "MergeDLQMessages"},
 		HistoryShardControllerScope:                            {operation: "ShardController"},
 		HistoryReapplyEventsScope:                              {operation: "EventReapplication"},
 		HistoryRefreshWorkflowTasksScope:                       {operation: "RefreshWorkflowTasks"},
+		HistoryRehydrateWorkflowExecutionScope:                 {operation: "RehydrateWorkflowExecution"},
+		HistoryImportWorkflowExecutionScope:                    {operation: "ImportWorkflowExecution"},
+		HistoryReadTaskDLQMessagesScope:                        {operation: "ReadTaskDLQMessages"},
+		HistoryPurgeTaskDLQMessagesScope:                       {operation: "PurgeTaskDLQMessages"},
+		HistoryMergeTaskDLQMessagesScope:                       {operation: "MergeTaskDLQMessages"},
 		TaskPriorityAssignerScope:                              {operation: "TaskPriorityAssigner"},
 		TransferQueueProcessorScope:                            {operation: "TransferQueueProcessor"},
 		TransferActiveQueueProcessorScope:                      {operation: "TransferActiveQueueProcessor"},
 		TransferStandbyQueueProcessorScope:                     {operation: "TransferStandbyQueueProcessor"},
+		TransferSplitQueueProcessorScope:                       {operation: "TransferSplitQueueProcessor"},
 		TransferActiveTaskActivityScope:                        {operation: "TransferActiveTaskActivity"},
 		TransferActiveTaskDecisionScope:                        {operation: "TransferActiveTaskDecision"},
 		TransferActiveTaskCloseExecutionScope:                  {operation: "TransferActiveTaskCloseExecution"},
@@ -1426,6 +1612,8 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		TransferActiveTaskRecordWorkflowStartedScope:           {operation: "TransferActiveTaskRecordWorkflowStarted"},
 		TransferActiveTaskResetWorkflowScope:                   {operation: "TransferActiveTaskResetWorkflow"},
 		TransferActiveTaskUpsertWorkflowSearchAttributesScope:  {operation: "TransferActiveTaskUpsertWorkflowSearchAttributes"},
+		TransferActiveTaskCompletionCallbackScope:              {operation: "TransferActiveTaskCompletionCallback"},
+		TransferActiveTaskHistoryStreamScope:                   {operation: "TransferActiveTaskHistoryStream"},
 		TransferStandbyTaskActivityScope:                       {operation: "TransferStandbyTaskActivity"},
 		TransferStandbyTaskDecisionScope:                       {operation: "TransferStandbyTaskDecision"},
 		TransferStandbyTaskCloseExecutionScope:                 {operation: "TransferStandbyTaskCloseExecution"},
@@ -1435,9 +1623,12 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		TransferStandbyTaskRecordWorkflowStartedScope:          {operation: "TransferStandbyTaskRecordWorkflowStarted"},
 		TransferStandbyTaskResetWorkflowScope:                  {operation: "TransferStandbyTaskResetWorkflow"},
 		TransferStandbyTaskUpsertWorkflowSearchAttributesScope: {operation: "TransferStandbyTaskUpsertWorkflowSearchAttributes"},
+		TransferStandbyTaskCompletionCallbackScope:             {operation: "TransferStandbyTaskCompletionCallback"},
+		TransferStandbyTaskHistoryStreamScope:                  {operation: "TransferStandbyTaskHistoryStream"},
 		TimerQueueProcessorScope:                               {operation: "TimerQueueProcessor"},
 		TimerActiveQueueProcessorScope:                         {operation: "TimerActiveQueueProcessor"},
 		TimerStandbyQueueProcessorScope:                        {operation: "TimerStandbyQueueProcessor"},
+		TimerSplitQueueProcessorScope:                          {operation: "TimerSplitQueueProcessor"},
 		TimerActiveTaskActivityTimeoutScope:                    {operation: "TimerActiveTaskActivityTimeout"},
 		TimerActiveTaskDecisionTimeoutScope:                    {operation: "TimerActiveTaskDecisionTimeout"},
 		TimerActiveTaskUserTimerScope:                          {operation: "TimerActiveTaskUserTimer"},
@@ -1467,6 +1658,8 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		EventsCachePutEventScope:                               {operation: "EventsCachePutEvent", tags: map[string]string{CacheTypeTagName: EventsCacheTypeTagValue}},
 		EventsCacheDeleteEventScope:                            {operation: "EventsCacheDeleteEvent", tags: map[string]string{CacheTypeTagName: EventsCacheTypeTagValue}},
 		EventsCacheGetFromStoreScope:                           {operation: "EventsCacheGetFromStore", tags: map[string]string{CacheTypeTagName: EventsCacheTypeTagValue}},
+		HistoryCacheStatsScope:                                 {operation: "HistoryCacheStats", tags: map[string]string{CacheTypeTagName: MutableStateCacheTypeTagValue}},
+		EventsCacheStatsScope:                                  {operation: "EventsCacheStats", tags: map[string]string{CacheTypeTagName: EventsCacheTypeTagValue}},
 		ExecutionSizeStatsScope:                                {operation: "ExecutionStats", tags: map[string]string{StatsTypeTagName: SizeStatsTypeTagValue}},
 		ExecutionCountStatsScope:                               {operation: "ExecutionStats", tags: map[string]string{StatsTypeTagName: CountStatsTypeTagValue}},
 		SessionSizeStatsScope:                                  {operation: "SessionStats", tags: map[string]string{StatsTypeTagName: SizeStatsTypeTagValue}},
@@ -1476,6 +1669,8 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		ReplicationTaskFetcherScope:                            {operation: "ReplicationTaskFetcher"},
 		ReplicationTaskCleanupScope:                            {operation: "ReplicationTaskCleanup"},
 		ReplicationDLQStatsScope:                               {operation: "ReplicationDLQStats"},
+		TransferTaskDLQStatsScope:                              {operation: "TransferTaskDLQStats"},
+		TimerTaskDLQStatsScope:                                 {operation: "TimerTaskDLQStats"},
 	},
 	// Matching Scope Names
 	Matching: {
@@ -1489,6 +1684,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		MatchingCancelOutstandingPollScope:     {operation: "CancelOutstandingPoll"},
 		MatchingDescribeTaskListScope:          {operation: "DescribeTaskList"},
 		MatchingListTaskListPartitionsScope:    {operation: "ListTaskListPartitions"},
+		MatchingUnloadTaskListScope:            {operation: "UnloadTaskList"},
+		MatchingAcquireActivityTaskSlotScope:   {operation: "AcquireActivityTaskSlot"},
+		MatchingReleaseActivityTaskSlotScope:   {operation: "ReleaseActivityTaskSlot"},
 	},
 	// Worker Scope Names
 	Worker: {
@@ -1512,6 +1710,9 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		HistoryScavengerScope:                  {operation: "historyscavenger"},
 		BatcherScope:                           {operation: "batcher"},
 		ParentClosePolicyProcessorScope:        {operation: "ParentClosePolicyProcessor"},
+		DomainHandoverScope:                    {operation: "DomainHandover"},
+		DomainMigrationScope:                   {operation: "DomainMigration"},
+		StuckWorkflowScannerScope:              {operation: "stuckworkflowscanner"},
 	},
 }
 
@@ -1639,6 +1840,12 @@ const (
 	ActivityE2ELatency
 	AckLevelUpdateCounter
 	AckLevelUpdateFailedCounter
+	SplitQueueCreatedCounter
+	SplitQueueMergedCounter
+	SplitQueueLimitExceededCounter
+	TaskDLQEnqueuedCounter
+	TaskDLQEnqueueFailedCounter
+	TaskDLQSizeGauge
 	DecisionTypeScheduleActivityCounter
 	DecisionTypeCompleteWorkflowCounter
 	DecisionTypeFailWorkflowCounter
@@ -1667,6 +1874,8 @@ const (
 	ScheduleToCloseTimeoutCounter
 	NewTimerCounter
 	NewTimerNotifyCounter
+	InMemoryTimerScheduledCounter
+	InMemoryTimerFiredCounter
 	AcquireShardsCounter
 	AcquireShardsLatency
 	ShardClosedCounter
@@ -1697,6 +1906,7 @@ const (
 	CompleteDecisionWithStickyEnabledCounter
 	CompleteDecisionWithStickyDisabledCounter
 	DecisionHeartbeatTimeoutCounter
+	EagerActivityDispatchCounter
 	HistoryEventNotificationQueueingLatency
 	HistoryEventNotificationFanoutLatency
 	HistoryEventNotificationInFlightMessageGauge
@@ -1713,6 +1923,11 @@ const (
 	CacheFailures
 	CacheLatency
 	CacheMissCounter
+	CacheHitCounter
+	CacheEvictedCapacityCounter
+	CacheEvictedMemoryCounter
+	CacheEvictedExpiredCounter
+	CacheSizeBytesGauge
 	AcquireLockFailedCounter
 	WorkflowContextCleared
 	MutableStateSize
@@ -1784,6 +1999,11 @@ const (
 	ReplicationTaskCleanupFailure
 	MutableStateChecksumMismatch
 	MutableStateChecksumInvalidated
+	CompletionCallbackDeliveredCounter
+	CompletionCallbackFailedCounter
+	ReplicationStreamMessagesSent
+	ReplicationStreamFailures
+	HistoryStreamEventsPublishedCounter
 
 	NumHistoryMetrics
 )
@@ -1799,6 +2019,7 @@ const (
 	RespondQueryTaskFailedCounter
 	SyncThrottleCounter
 	BufferThrottleCounter
+	ConcurrencyLimitedCounter
 	SyncMatchLatency
 	AsyncMatchLatency
 	ExpiredTasksCounter
@@ -1866,6 +2087,10 @@ const (
 	TaskListDeletedCount
 	TaskListOutstandingCount
 	ExecutionsOutstandingCount
+	ExecutionsCheckedCount
+	ExecutionsCorruptedCount
+	ExecutionsFixedCount
+	ExecutionsCheckErrorCount
 	StartedCount
 	StoppedCount
 	ExecutorTasksDeferredCount
@@ -1878,6 +2103,17 @@ const (
 	ParentClosePolicyProcessorSuccess
 	ParentClosePolicyProcessorFailures
 	DomainReplicationEnqueueDLQCount
+	DomainHandoverCompletedCount
+	DomainHandoverTimedOutCount
+	DomainHandoverFailures
+	DomainMigrationCopiedCount
+	DomainMigrationSkippedCount
+	DomainMigrationFailures
+	StuckWorkflowsCheckedCount
+	StuckWorkflowsCheckErrorCount
+	StuckWorkflowsDecisionTaskFailingCount
+	StuckWorkflowsActivityNoPollerCount
+	StuckWorkflowsNoPendingWorkCount
 
 	NumWorkerMetrics
 )
@@ -1987,6 +2223,12 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ActivityE2ELatency:                                {metricName: "activity_end_to_end_latency", metricType: Timer},
 		AckLevelUpdateCounter:                             {metricName: "ack_level_update", metricType: Counter},
 		AckLevelUpdateFailedCounter:                       {metricName: "ack_level_update_failed", metricType: Counter},
+		SplitQueueCreatedCounter:                          {metricName: "split_queue_created", metricType: Counter},
+		SplitQueueMergedCounter:                           {metricName: "split_queue_merged", metricType: Counter},
+		SplitQueueLimitExceededCounter:                    {metricName: "split_queue_limit_exceeded", metricType: Counter},
+		TaskDLQEnqueuedCounter:                            {metricName: "task_dlq_enqueued", metricType: Counter},
+		TaskDLQEnqueueFailedCounter:                       {metricName: "task_dlq_enqueue_failed", metricType: Counter},
+		TaskDLQSizeGauge:                                  {metricName: "task_dlq_size", metricType: Gauge},
 		DecisionTypeScheduleActivityCounter:               {metricName: "schedule_activity_decision", metricType: Counter},
 		DecisionTypeCompleteWorkflowCounter:               {metricName: "complete_workflow_decision", metricType: Counter},
 		DecisionTypeFailWorkflowCounter:                   {metricName: "fail_workflow_decision", metricType: Counter},
@@ -2015,6 +2257,8 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ScheduleToCloseTimeoutCounter:                     {metricName: "schedule_to_close_timeout", metricType: Counter},
 		NewTimerCounter:                                   {metricName: "new_timer", metricType: Counter},
 		NewTimerNotifyCounter:                             {metricName: "new_timer_notifications", metricType: Counter},
+		InMemoryTimerScheduledCounter:                     {metricName: "in_memory_timer_scheduled", metricType: Counter},
+		InMemoryTimerFiredCounter:                         {metricName: "in_memory_timer_fired", metricType: Counter},
 		AcquireShardsCounter:                              {metricName: "acquire_shards_count", metricType: Counter},
 		AcquireShardsLatency:                              {metricName: "acquire_shards_latency", metricType: Timer},
 		ShardClosedCounter:                                {metricName: "shard_closed_count", metricType: Counter},
@@ -2045,6 +2289,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		CompleteDecisionWithStickyEnabledCounter:          {metricName: "complete_decision_sticky_enabled_count", metricType: Counter},
 		CompleteDecisionWithStickyDisabledCounter:         {metricName: "complete_decision_sticky_disabled_count", metricType: Counter},
 		DecisionHeartbeatTimeoutCounter:                   {metricName: "decision_heartbeat_timeout_count", metricType: Counter},
+		EagerActivityDispatchCounter:                      {metricName: "eager_activity_dispatch_count", metricType: Counter},
 		HistoryEventNotificationQueueingLatency:           {metricName: "history_event_notification_queueing_latency", metricType: Timer},
 		HistoryEventNotificationFanoutLatency:             {metricName: "history_event_notification_fanout_latency", metricType: Timer},
 		HistoryEventNotificationInFlightMessageGauge:      {metricName: "history_event_notification_inflight_message_gauge", metricType: Gauge},
@@ -2061,6 +2306,11 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		CacheFailures:                                     {metricName: "cache_errors", metricType: Counter},
 		CacheLatency:                                      {metricName: "cache_latency", metricType: Timer},
 		CacheMissCounter:                                  {metricName: "cache_miss", metricType: Counter},
+		CacheHitCounter:                                   {metricName: "cache_hit", metricType: Counter},
+		CacheEvictedCapacityCounter:                       {metricName: "cache_evicted_capacity", metricType: Counter},
+		CacheEvictedMemoryCounter:                         {metricName: "cache_evicted_memory", metricType: Counter},
+		CacheEvictedExpiredCounter:                        {metricName: "cache_evicted_expired", metricType: Counter},
+		CacheSizeBytesGauge:                               {metricName: "cache_size_bytes", metricType: Gauge},
 		AcquireLockFailedCounter:                          {metricName: "acquire_lock_failed", metricType: Counter},
 		WorkflowContextCleared:                            {metricName: "workflow_context_cleared", metricType: Counter},
 		MutableStateSize:                                  {metricName: "mutable_state_size", metricType: Timer},
@@ -2132,6 +2382,11 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ReplicationTaskCleanupFailure:                     {metricName: "replication_task_cleanup_failed", metricType: Counter},
 		MutableStateChecksumMismatch:                      {metricName: "mutable_state_checksum_mismatch", metricType: Counter},
 		MutableStateChecksumInvalidated:                   {metricName: "mutable_state_checksum_invalidated", metricType: Counter},
+		CompletionCallbackDeliveredCounter:                {metricName: "completion_callback_delivered", metricType: Counter},
+		CompletionCallbackFailedCounter:                   {metricName: "completion_callback_failed", metricType: Counter},
+		ReplicationStreamMessagesSent:                     {metricName: "replication_stream_messages_sent", metricType: Counter},
+		ReplicationStreamFailures:                         {metricName: "replication_stream_failures", metricType: Counter},
+		HistoryStreamEventsPublishedCounter:               {metricName: "history_stream_events_published", metricType: Counter},
 	},
 	Matching: {
 		PollSuccessCounter:            {metricName: "poll_success"},
@@ -2143,6 +2398,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		RespondQueryTaskFailedCounter: {metricName: "respond_query_failed"},
 		SyncThrottleCounter:           {metricName: "sync_throttle_count"},
 		BufferThrottleCounter:         {metricName: "buffer_throttle_count"},
+		ConcurrencyLimitedCounter:     {metricName: "activity_concurrency_limited"},
 		ExpiredTasksCounter:           {metricName: "tasks_expired"},
 		ForwardedCounter:              {metricName: "forwarded"},
 		ForwardTaskCalls:              {metricName: "forward_task_calls"},
@@ -2206,6 +2462,10 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		TaskListDeletedCount:                          {metricName: "tasklist_deleted", metricType: Gauge},
 		TaskListOutstandingCount:                      {metricName: "tasklist_outstanding", metricType: Gauge},
 		ExecutionsOutstandingCount:                    {metricName: "executions_outstanding", metricType: Gauge},
+		ExecutionsCheckedCount:                        {metricName: "executions_checked", metricType: Gauge},
+		ExecutionsCorruptedCount:                      {metricName: "executions_corrupted", metricType: Gauge},
+		ExecutionsFixedCount:                          {metricName: "executions_fixed", metricType: Gauge},
+		ExecutionsCheckErrorCount:                     {metricName: "executions_check_errors", metricType: Gauge},
 		StartedCount:                                  {metricName: "started", metricType: Counter},
 		StoppedCount:                                  {metricName: "stopped", metricType: Counter},
 		ExecutorTasksDeferredCount:                    {metricName: "executor_deferred", metricType: Counter},
@@ -2218,6 +2478,17 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ParentClosePolicyProcessorSuccess:             {metricName: "parent_close_policy_processor_requests", metricType: Counter},
 		ParentClosePolicyProcessorFailures:            {metricName: "parent_close_policy_processor_errors", metricType: Counter},
 		DomainReplicationEnqueueDLQCount:              {metricName: "domain_replication_dlq_enqueue_requests", metricType: Counter},
+		DomainHandoverCompletedCount:                  {metricName: "domain_handover_completed", metricType: Counter},
+		DomainHandoverTimedOutCount:                   {metricName: "domain_handover_timed_out", metricType: Counter},
+		DomainHandoverFailures:                        {metricName: "domain_handover_errors", metricType: Counter},
+		DomainMigrationCopiedCount:                    {metricName: "domain_migration_copied", metricType: Counter},
+		DomainMigrationSkippedCount:                   {metricName: "domain_migration_skipped", metricType: Counter},
+		DomainMigrationFailures:                       {metricName: "domain_migration_errors", metricType: Counter},
+		StuckWorkflowsCheckedCount:                    {metricName: "stuck_workflows_checked", metricType: Gauge},
+		StuckWorkflowsCheckErrorCount:                 {metricName: "stuck_workflows_check_errors", metricType: Gauge},
+		StuckWorkflowsDecisionTaskFailingCount:        {metricName: "stuck_workflows_decision_task_failing", metricType: Gauge},
+		StuckWorkflowsActivityNoPollerCount:           {metricName: "stuck_workflows_activity_no_poller", metricType: Gauge},
+		StuckWorkflowsNoPendingWorkCount:              {metricName: "stuck_workflows_no_pending_work", metricType: Gauge},
 	},
 }
 
@@ -146,6 +146,35 @@ func (mr *MockClusterMetadataManagerMockRecorder) GetImmutableClusterMetadata()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImmutableClusterMetadata", reflect.TypeOf((*MockClusterMetadataManager)(nil).GetImmutableClusterMetadata))
 }
 
+// GetHistoryShardRouting mocks base method
+func (m *MockClusterMetadataManager) GetHistoryShardRouting() (*persistence.GetHistoryShardRoutingResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistoryShardRouting")
+	ret0, _ := ret[0].(*persistence.GetHistoryShardRoutingResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistoryShardRouting indicates an expected call of GetHistoryShardRouting
+func (mr *MockClusterMetadataManagerMockRecorder) GetHistoryShardRouting() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryShardRouting", reflect.TypeOf((*MockClusterMetadataManager)(nil).GetHistoryShardRouting))
+}
+
+// UpdateHistoryShardRouting mocks base method
+func (m *MockClusterMetadataManager) UpdateHistoryShardRouting(request *persistence.UpdateHistoryShardRoutingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHistoryShardRouting", request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHistoryShardRouting indicates an expected call of UpdateHistoryShardRouting
+func (mr *MockClusterMetadataManagerMockRecorder) UpdateHistoryShardRouting(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHistoryShardRouting", reflect.TypeOf((*MockClusterMetadataManager)(nil).UpdateHistoryShardRouting), request)
+}
+
 // GetClusterMembers mocks base method
 func (m *MockClusterMetadataManager) GetClusterMembers(request *persistence.GetClusterMembersRequest) (*persistence.GetClusterMembersResponse, error) {
 	m.ctrl.T.Helper()
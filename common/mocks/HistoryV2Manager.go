@@ -190,6 +190,18 @@ func (_m *HistoryV2Manager) GetHistoryTree(request *persistence.GetHistoryTreeRe
 	return r0, r1
 }
 
+// MoveHistoryTree provides a mock function with given fields: request
+func (_m *HistoryV2Manager) MoveHistoryTree(request *persistence.MoveHistoryTreeRequest) error {
+	ret := _m.Called(request)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*persistence.MoveHistoryTreeRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
 func (_m *HistoryV2Manager) GetAllHistoryTreeBranches(request *persistence.GetAllHistoryTreeBranchesRequest) (*persistence.GetAllHistoryTreeBranchesResponse, error) {
 	ret := _m.Called(request)
 	var r0 *persistence.GetAllHistoryTreeBranchesResponse
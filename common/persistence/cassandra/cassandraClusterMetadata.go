@@ -21,6 +21,7 @@
 package cassandra
 
 import (
+	"fmt"
 	"net"
 	"strings"
 	"time"
@@ -51,6 +52,16 @@ VALUES(?, ?, ?) IF NOT EXISTS`
 cluster_metadata 
 WHERE metadata_partition = ?`
 
+	templateGetHistoryShardRouting = `SELECT shard_routing_data, shard_routing_data_encoding, shard_routing_version FROM 
+cluster_metadata 
+WHERE metadata_partition = ?`
+
+	// The version is null until the routing is updated for the first time
+	templateUpdateHistoryShardRouting = `UPDATE cluster_metadata 
+SET shard_routing_data = ?, shard_routing_data_encoding = ?, shard_routing_version = ? 
+WHERE metadata_partition = ? 
+IF shard_routing_version = ?`
+
 	immutablePayloadFieldName = `immutable_data`
 
 	immutableEncodingFieldName = immutablePayloadFieldName + `_encoding`
@@ -100,6 +111,18 @@ func newClusterMetadataInstance(cfg config.Cassandra, logger log.Logger) (p.Clus
 	}, nil
 }
 
+// NewClusterMetadataPersistenceFromSession returns new ClusterMetadataStore
+func NewClusterMetadataPersistenceFromSession(
+	session *gocql.Session,
+	logger log.Logger,
+) p.ClusterMetadataStore {
+
+	return &cassandraClusterMetadata{
+		cassandraStore: &cassandraStore{session: session, logger: logger},
+		logger:         logger,
+	}
+}
+
 // Close releases the resources held by this object
 func (m *cassandraClusterMetadata) Close() {
 	if m.session != nil {
@@ -174,6 +197,49 @@ func (m *cassandraClusterMetadata) GetImmutableClusterMetadata() (*p.InternalGet
 	}, nil
 }
 
+func (m *cassandraClusterMetadata) GetHistoryShardRouting() (*p.InternalGetHistoryShardRoutingResponse, error) {
+	query := m.session.Query(templateGetHistoryShardRouting, constMetadataPartition)
+	var routing []byte
+	var encoding string
+	var version *int64
+	err := query.Scan(&routing, &encoding, &version)
+	if err != nil {
+		return nil, convertCommonErrors("GetHistoryShardRouting", err)
+	}
+
+	resp := &p.InternalGetHistoryShardRoutingResponse{}
+	if version != nil {
+		resp.Version = *version
+	}
+	if len(routing) > 0 {
+		resp.Routing = p.NewDataBlob(routing, common.EncodingType(encoding))
+	}
+	return resp, nil
+}
+
+func (m *cassandraClusterMetadata) UpdateHistoryShardRouting(request *p.InternalUpdateHistoryShardRoutingRequest) error {
+	var previousVersion *int64
+	if request.PreviousVersion > 0 {
+		previousVersion = &request.PreviousVersion
+	}
+	query := m.session.Query(templateUpdateHistoryShardRouting,
+		request.Routing.Data, request.Routing.Encoding, request.PreviousVersion+1,
+		constMetadataPartition, previousVersion)
+
+	previous := make(map[string]interface{})
+	applied, err := query.MapScanCAS(previous)
+	if err != nil {
+		return convertCommonErrors("UpdateHistoryShardRouting", err)
+	}
+	if !applied {
+		return &p.ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update history shard routing. previous_version: %v, columns: (%v)",
+				request.PreviousVersion, previous),
+		}
+	}
+	return nil
+}
+
 func (m *cassandraClusterMetadata) GetClusterMembers(request *p.GetClusterMembersRequest) (*p.GetClusterMembersResponse, error) {
 	var queryString strings.Builder
 	var operands []interface{}
@@ -401,6 +401,10 @@ func (h *cassandraHistoryV2Persistence) GetAllHistoryTreeBranches(
 	request *p.GetAllHistoryTreeBranchesRequest,
 ) (*p.GetAllHistoryTreeBranchesResponse, error) {
 
+	if request.ShardID != nil {
+		return nil, serviceerror.NewInvalidArgument("GetAllHistoryTreeBranches operation failed. Shard filter is not supported by cassandra.")
+	}
+
 	query := h.session.Query(v2templateScanAllTreeBranches)
 
 	iter := query.PageSize(int(request.PageSize)).PageState(request.NextPageToken).Iter()
@@ -447,6 +451,14 @@ func (h *cassandraHistoryV2Persistence) GetAllHistoryTreeBranches(
 	return response, nil
 }
 
+// MoveHistoryTree is a noop, history trees are not partitioned by shard in Cassandra
+func (h *cassandraHistoryV2Persistence) MoveHistoryTree(
+	request *p.MoveHistoryTreeRequest,
+) error {
+
+	return nil
+}
+
 // GetHistoryTree returns all branch information of a tree
 func (h *cassandraHistoryV2Persistence) GetHistoryTree(
 	request *p.GetHistoryTreeRequest,
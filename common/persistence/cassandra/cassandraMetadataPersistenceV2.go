@@ -91,6 +91,9 @@ const (
 		`config_version, ` +
 		`failover_version, ` +
 		`failover_notification_version, ` +
+		`handover_cluster_name, ` +
+		`failover_end_time, ` +
+		`migration_cluster_name, ` +
 		`notification_version ` +
 		`FROM domains_by_name_v2 ` +
 		`WHERE domains_partition = ? ` +
@@ -103,6 +106,9 @@ const (
 		`config_version = ? ,` +
 		`failover_version = ? ,` +
 		`failover_notification_version = ? , ` +
+		`handover_cluster_name = ? , ` +
+		`failover_end_time = ? , ` +
+		`migration_cluster_name = ? , ` +
 		`notification_version = ? ` +
 		`WHERE domains_partition = ? ` +
 		`and name = ?`
@@ -133,6 +139,9 @@ const (
 		`config_version, ` +
 		`failover_version, ` +
 		`failover_notification_version, ` +
+		`handover_cluster_name, ` +
+		`failover_end_time, ` +
+		`migration_cluster_name, ` +
 		`notification_version ` +
 		`FROM domains_by_name_v2 ` +
 		`WHERE domains_partition = ? `
@@ -279,6 +288,9 @@ func (m *cassandraMetadataPersistenceV2) UpdateDomain(request *p.InternalUpdateD
 		request.ConfigVersion,
 		request.FailoverVersion,
 		request.FailoverNotificationVersion,
+		request.HandoverClusterName,
+		request.FailoverEndTime,
+		request.MigrationClusterName,
 		request.NotificationVersion,
 		constDomainPartition,
 		request.Info.Name,
@@ -311,6 +323,9 @@ func (m *cassandraMetadataPersistenceV2) GetDomain(request *p.GetDomainRequest)
 	replicationConfig := &p.DomainReplicationConfig{}
 	var replicationClusters []map[string]interface{}
 	var failoverNotificationVersion int64
+	var handoverClusterName string
+	var failoverEndTime int64
+	var migrationClusterName string
 	var notificationVersion int64
 	var failoverVersion int64
 	var configVersion int64
@@ -369,6 +384,9 @@ func (m *cassandraMetadataPersistenceV2) GetDomain(request *p.GetDomainRequest)
 		&configVersion,
 		&failoverVersion,
 		&failoverNotificationVersion,
+		&handoverClusterName,
+		&failoverEndTime,
+		&migrationClusterName,
 		&notificationVersion,
 	)
 
@@ -392,6 +410,9 @@ func (m *cassandraMetadataPersistenceV2) GetDomain(request *p.GetDomainRequest)
 		ConfigVersion:               configVersion,
 		FailoverVersion:             failoverVersion,
 		FailoverNotificationVersion: failoverNotificationVersion,
+		HandoverClusterName:         handoverClusterName,
+		FailoverEndTime:             failoverEndTime,
+		MigrationClusterName:        migrationClusterName,
 		NotificationVersion:         notificationVersion,
 	}, nil
 }
@@ -439,6 +460,9 @@ func (m *cassandraMetadataPersistenceV2) ListDomains(request *p.ListDomainsReque
 		&domain.ConfigVersion,
 		&domain.FailoverVersion,
 		&domain.FailoverNotificationVersion,
+		&domain.HandoverClusterName,
+		&domain.FailoverEndTime,
+		&domain.MigrationClusterName,
 		&domain.NotificationVersion,
 	) {
 		if name != domainMetadataRecordName {
@@ -48,7 +48,7 @@ import (
 // Where x is any hexadecimal value, E represents the entity type valid values are:
 // E = {DomainID = 1, WorkflowID = 2, RunID = 3}
 // R represents row type in executions table, valid values are:
-// R = {Shard = 1, Execution = 2, Transfer = 3, Timer = 4, Replication = 5}
+// R = {Shard = 1, Execution = 2, Transfer = 3, Timer = 4, Replication = 5, ReplicationDLQ = 6, TransferDLQ = 7, TimerDLQ = 8}
 const (
 	cassandraProtoVersion = 4
 	defaultSessionTimeout = 10 * time.Second
@@ -76,6 +76,14 @@ const (
 	// Row Constants for Replication Task DLQ Row. Source cluster name will be used as WorkflowID.
 	rowTypeDLQDomainID = "10000000-6000-f000-f000-000000000000"
 	rowTypeDLQRunID    = "30000000-6000-f000-f000-000000000000"
+	// Row Constants for Transfer Task DLQ Row
+	rowTypeTransferDLQDomainID   = "10000000-7000-f000-f000-000000000000"
+	rowTypeTransferDLQWorkflowID = "20000000-7000-f000-f000-000000000000"
+	rowTypeTransferDLQRunID      = "30000000-7000-f000-f000-000000000000"
+	// Row Constants for Timer Task DLQ Row. Timer tasks in the DLQ are ordered by task ID.
+	rowTypeTimerDLQDomainID   = "10000000-8000-f000-f000-000000000000"
+	rowTypeTimerDLQWorkflowID = "20000000-8000-f000-f000-000000000000"
+	rowTypeTimerDLQRunID      = "30000000-8000-f000-f000-000000000000"
 	// Special TaskId constants
 	rowTypeExecutionTaskID = int64(-10)
 	rowTypeShardTaskID     = int64(-11)
@@ -92,6 +100,8 @@ const (
 	rowTypeTimerTask
 	rowTypeReplicationTask
 	rowTypeDLQ
+	rowTypeTransferDLQ
+	rowTypeTimerDLQ
 )
 
 const (
@@ -339,6 +349,11 @@ workflow_state = ? ` +
 		`and visibility_ts = ? ` +
 		`and task_id = ?`
 
+	templateListExecutionsQuery = `SELECT domain_id, workflow_id, run_id ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ?`
+
 	templateCheckWorkflowExecutionQuery = `UPDATE executions ` +
 		`SET next_event_id = ? ` +
 		`WHERE shard_id = ? ` +
@@ -637,6 +652,17 @@ workflow_state = ? ` +
 		`and task_id > ? ` +
 		`and task_id <= ?`
 
+	templateGetTimerDLQTasksQuery = `SELECT timer, timer_encoding ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id > ? ` +
+		`and task_id <= ?`
+
 	templateCompleteTransferTaskQuery = `DELETE FROM executions ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
@@ -2041,6 +2067,45 @@ func (d *cassandraPersistence) GetCurrentExecution(request *p.GetCurrentExecutio
 	}, nil
 }
 
+func (d *cassandraPersistence) ListConcreteExecutions(
+	request *p.ListConcreteExecutionsRequest,
+) (*p.ListConcreteExecutionsResponse, error) {
+
+	query := d.session.Query(templateListExecutionsQuery,
+		d.shardID,
+		rowTypeExecution,
+	).PageSize(request.PageSize).PageState(request.NextPageToken)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, serviceerror.NewInternal("ListConcreteExecutions operation failed.  Not able to create query iterator.")
+	}
+
+	response := &p.ListConcreteExecutionsResponse{}
+	var domainID, runID gocql.UUID
+	var workflowID string
+	for iter.Scan(&domainID, &workflowID, &runID) {
+		// the current execution rows share the row type with the concrete executions
+		if runID.String() == permanentRunID {
+			continue
+		}
+		response.Executions = append(response.Executions, &p.ConcreteExecution{
+			DomainID:   domainID.String(),
+			WorkflowID: workflowID,
+			RunID:      runID.String(),
+		})
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors("ListConcreteExecutions", err)
+	}
+
+	return response, nil
+}
+
 func (d *cassandraPersistence) GetTransferTasks(request *p.GetTransferTasksRequest) (*p.GetTransferTasksResponse, error) {
 
 	// Reading transfer tasks need to be quorum level consistent, otherwise we could loose task
@@ -2083,6 +2148,100 @@ func (d *cassandraPersistence) GetTransferTasks(request *p.GetTransferTasksReque
 	return response, nil
 }
 
+func (d *cassandraPersistence) PutTransferTaskToDLQ(request *p.PutTransferTaskToDLQRequest) error {
+	task := request.TaskInfo
+	datablob, err := serialization.TransferTaskInfoToBlob(task)
+	if err != nil {
+		return convertCommonErrors("PutTransferTaskToDLQ", err)
+	}
+
+	query := d.session.Query(templateCreateTransferTaskQuery,
+		d.shardID,
+		rowTypeTransferDLQ,
+		rowTypeTransferDLQDomainID,
+		rowTypeTransferDLQWorkflowID,
+		rowTypeTransferDLQRunID,
+		datablob.Data,
+		datablob.Encoding,
+		defaultVisibilityTimestamp,
+		task.GetTaskID())
+
+	err = query.Exec()
+	if err != nil {
+		return convertCommonErrors("PutTransferTaskToDLQ", err)
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) GetTransferTasksFromDLQ(
+	request *p.GetTransferTasksFromDLQRequest,
+) (*p.GetTransferTasksFromDLQResponse, error) {
+
+	query := d.session.Query(templateGetTransferTasksQuery,
+		d.shardID,
+		rowTypeTransferDLQ,
+		rowTypeTransferDLQDomainID,
+		rowTypeTransferDLQWorkflowID,
+		rowTypeTransferDLQRunID,
+		defaultVisibilityTimestamp,
+		request.ReadLevel,
+		request.MaxReadLevel,
+	).PageSize(request.BatchSize).PageState(request.NextPageToken)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, serviceerror.NewInternal("GetTransferTasksFromDLQ operation failed.  Not able to create query iterator.")
+	}
+
+	response := &p.GetTransferTasksFromDLQResponse{}
+	var data []byte
+	var encoding string
+
+	for iter.Scan(&data, &encoding) {
+		t, err := serialization.TransferTaskInfoFromBlob(data, encoding)
+		if err != nil {
+			return nil, convertCommonErrors("GetTransferTasksFromDLQ", err)
+		}
+
+		response.Tasks = append(response.Tasks, t)
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors("GetTransferTasksFromDLQ", err)
+	}
+
+	return response, nil
+}
+
+func (d *cassandraPersistence) RangeDeleteTransferTaskFromDLQ(
+	request *p.RangeDeleteTransferTaskFromDLQRequest,
+) error {
+
+	query := d.session.Query(templateRangeCompleteTransferTaskQuery,
+		d.shardID,
+		rowTypeTransferDLQ,
+		rowTypeTransferDLQDomainID,
+		rowTypeTransferDLQWorkflowID,
+		rowTypeTransferDLQRunID,
+		defaultVisibilityTimestamp,
+		request.ExclusiveBeginTaskID,
+		request.InclusiveEndTaskID,
+	)
+
+	err := query.Exec()
+	if err != nil {
+		if isThrottlingError(err) {
+			return serviceerror.NewResourceExhausted(fmt.Sprintf("RangeDeleteTransferTaskFromDLQ operation failed. Error: %v", err))
+		}
+		return serviceerror.NewInternal(fmt.Sprintf("RangeDeleteTransferTaskFromDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
 func (d *cassandraPersistence) GetReplicationTasks(
 	request *p.GetReplicationTasksRequest,
 ) (*p.GetReplicationTasksResponse, error) {
@@ -2270,6 +2429,101 @@ func (d *cassandraPersistence) RangeCompleteTimerTask(request *p.RangeCompleteTi
 	return nil
 }
 
+func (d *cassandraPersistence) PutTimerTaskToDLQ(request *p.PutTimerTaskToDLQRequest) error {
+	task := request.TaskInfo
+	datablob, err := serialization.TimerTaskInfoToBlob(task)
+	if err != nil {
+		return convertCommonErrors("PutTimerTaskToDLQ", err)
+	}
+
+	// timer tasks in the DLQ use the default visibility timestamp, so that they are ordered by task ID
+	query := d.session.Query(templateCreateTimerTaskQuery,
+		d.shardID,
+		rowTypeTimerDLQ,
+		rowTypeTimerDLQDomainID,
+		rowTypeTimerDLQWorkflowID,
+		rowTypeTimerDLQRunID,
+		datablob.Data,
+		datablob.Encoding,
+		defaultVisibilityTimestamp,
+		task.GetTaskID())
+
+	err = query.Exec()
+	if err != nil {
+		return convertCommonErrors("PutTimerTaskToDLQ", err)
+	}
+
+	return nil
+}
+
+func (d *cassandraPersistence) GetTimerTasksFromDLQ(
+	request *p.GetTimerTasksFromDLQRequest,
+) (*p.GetTimerTasksFromDLQResponse, error) {
+
+	query := d.session.Query(templateGetTimerDLQTasksQuery,
+		d.shardID,
+		rowTypeTimerDLQ,
+		rowTypeTimerDLQDomainID,
+		rowTypeTimerDLQWorkflowID,
+		rowTypeTimerDLQRunID,
+		defaultVisibilityTimestamp,
+		request.ReadLevel,
+		request.MaxReadLevel,
+	).PageSize(request.BatchSize).PageState(request.NextPageToken)
+
+	iter := query.Iter()
+	if iter == nil {
+		return nil, serviceerror.NewInternal("GetTimerTasksFromDLQ operation failed.  Not able to create query iterator.")
+	}
+
+	response := &p.GetTimerTasksFromDLQResponse{}
+	var data []byte
+	var encoding string
+
+	for iter.Scan(&data, &encoding) {
+		t, err := serialization.TimerTaskInfoFromBlob(data, encoding)
+		if err != nil {
+			return nil, convertCommonErrors("GetTimerTasksFromDLQ", err)
+		}
+
+		response.Tasks = append(response.Tasks, t)
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		return nil, convertCommonErrors("GetTimerTasksFromDLQ", err)
+	}
+
+	return response, nil
+}
+
+func (d *cassandraPersistence) RangeDeleteTimerTaskFromDLQ(
+	request *p.RangeDeleteTimerTaskFromDLQRequest,
+) error {
+
+	query := d.session.Query(templateRangeCompleteTransferTaskQuery,
+		d.shardID,
+		rowTypeTimerDLQ,
+		rowTypeTimerDLQDomainID,
+		rowTypeTimerDLQWorkflowID,
+		rowTypeTimerDLQRunID,
+		defaultVisibilityTimestamp,
+		request.ExclusiveBeginTaskID,
+		request.InclusiveEndTaskID,
+	)
+
+	err := query.Exec()
+	if err != nil {
+		if isThrottlingError(err) {
+			return serviceerror.NewResourceExhausted(fmt.Sprintf("RangeDeleteTimerTaskFromDLQ operation failed. Error: %v", err))
+		}
+		return serviceerror.NewInternal(fmt.Sprintf("RangeDeleteTimerTaskFromDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
 // From TaskManager interface
 func (d *cassandraPersistence) LeaseTaskList(request *p.LeaseTaskListRequest) (*p.LeaseTaskListResponse, error) {
 	if len(request.TaskList) == 0 {
@@ -747,6 +747,12 @@ func createTransferTasks(
 			targetWorkflowID = task.(*p.StartChildExecutionTask).TargetWorkflowID
 			scheduleID = task.(*p.StartChildExecutionTask).InitiatedID
 
+		case p.TransferTaskTypeCompletionCallback:
+			scheduleID = task.(*p.CompletionCallbackTask).CallbackID
+
+		case p.TransferTaskTypeHistoryStream:
+			scheduleID = task.(*p.HistoryStreamTask).LastEventID
+
 		case p.TransferTaskTypeCloseExecution,
 			p.TransferTaskTypeRecordWorkflowStarted,
 			p.TransferTaskTypeResetWorkflow,
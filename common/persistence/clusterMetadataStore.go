@@ -22,6 +22,7 @@ package persistence
 import (
 	"errors"
 
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
 )
@@ -106,6 +107,45 @@ func (m *clusterMetadataManagerImpl) GetImmutableClusterMetadata() (*GetImmutabl
 	return &GetImmutableClusterMetadataResponse{*icm}, nil
 }
 
+func (m *clusterMetadataManagerImpl) GetHistoryShardRouting() (*GetHistoryShardRoutingResponse, error) {
+	resp, err := m.persistence.GetHistoryShardRouting()
+	if err != nil {
+		return nil, err
+	}
+
+	routing, err := m.serializer.DeserializeHistoryShardRouting(resp.Routing)
+	if err != nil {
+		return nil, err
+	}
+	if routing == nil {
+		// the shards were never split, all of them are routed by the initial shard count
+		icm, err := m.GetImmutableClusterMetadata()
+		if err != nil {
+			return nil, err
+		}
+		routing = &pblobs.HistoryShardRouting{
+			ShardCount: icm.HistoryShardCount,
+		}
+	}
+
+	return &GetHistoryShardRoutingResponse{
+		Routing: routing,
+		Version: resp.Version,
+	}, nil
+}
+
+func (m *clusterMetadataManagerImpl) UpdateHistoryShardRouting(request *UpdateHistoryShardRoutingRequest) error {
+	routing, err := m.serializer.SerializeHistoryShardRouting(request.Routing, clusterMetadataEncoding)
+	if err != nil {
+		return err
+	}
+
+	return m.persistence.UpdateHistoryShardRouting(&InternalUpdateHistoryShardRoutingRequest{
+		Routing:         routing,
+		PreviousVersion: request.PreviousVersion,
+	})
+}
+
 func (m *clusterMetadataManagerImpl) GetClusterMembers(request *GetClusterMembersRequest) (*GetClusterMembersResponse, error) {
 	return m.persistence.GetClusterMembers(request)
 }
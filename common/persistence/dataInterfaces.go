@@ -160,6 +160,15 @@ const (
 	TransferTaskTypeRecordWorkflowStarted
 	TransferTaskTypeResetWorkflow
 	TransferTaskTypeUpsertWorkflowSearchAttributes
+	TransferTaskTypeCompletionCallback
+	TransferTaskTypeHistoryStream
+)
+
+// Completion callback delivery states
+const (
+	CompletionCallbackStatePending int32 = iota
+	CompletionCallbackStateSucceeded
+	CompletionCallbackStateFailed
 )
 
 // Types of replication tasks
@@ -332,6 +341,10 @@ type (
 		// Cron
 		CronSchedule      string
 		ExpirationSeconds int32
+		// callbacks to be delivered once the workflow is closed
+		CompletionCallbacks []*pblobs.CompletionCallbackInfo
+		// ID of the first history event not yet published to the history stream
+		HistoryStreamNextEventID int64
 	}
 
 	// ExecutionStats is the statistics about workflow execution
@@ -471,6 +484,24 @@ type (
 		Version int64
 	}
 
+	// CompletionCallbackTask identifies a transfer task for delivering a workflow completion callback
+	CompletionCallbackTask struct {
+		VisibilityTimestamp time.Time
+		TaskID              int64
+		// CallbackID is the index of the callback in WorkflowExecutionInfo.CompletionCallbacks
+		CallbackID int64
+		Version    int64
+	}
+
+	// HistoryStreamTask identifies a transfer task for publishing history events to the history stream
+	HistoryStreamTask struct {
+		VisibilityTimestamp time.Time
+		TaskID              int64
+		// LastEventID is the ID of the last event to be published
+		LastEventID int64
+		Version     int64
+	}
+
 	// StartChildExecutionTask identifies a transfer task for starting child execution
 	StartChildExecutionTask struct {
 		VisibilityTimestamp time.Time
@@ -694,6 +725,25 @@ type (
 		LastWriteVersion int64
 	}
 
+	// ListConcreteExecutionsRequest is used to list the workflow executions of a shard
+	ListConcreteExecutionsRequest struct {
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// ListConcreteExecutionsResponse is the response to ListConcreteExecutionsRequest
+	ListConcreteExecutionsResponse struct {
+		Executions    []*ConcreteExecution
+		NextPageToken []byte
+	}
+
+	// ConcreteExecution identifies a workflow execution, the current execution is not a concrete execution
+	ConcreteExecution struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+	}
+
 	// UpdateWorkflowExecutionRequest is used to update a workflow execution
 	UpdateWorkflowExecutionRequest struct {
 		RangeID int64
@@ -918,6 +968,50 @@ type (
 	// GetReplicationTasksFromDLQResponse is the response for GetReplicationTasksFromDLQ
 	GetReplicationTasksFromDLQResponse = GetReplicationTasksResponse
 
+	// PutTransferTaskToDLQRequest is used to put a transfer task to dlq
+	PutTransferTaskToDLQRequest struct {
+		TaskInfo *pblobs.TransferTaskInfo
+	}
+
+	// GetTransferTasksFromDLQRequest is used to get transfer tasks from dlq
+	GetTransferTasksFromDLQRequest struct {
+		GetTransferTasksRequest
+	}
+
+	// GetTransferTasksFromDLQResponse is the response for GetTransferTasksFromDLQ
+	GetTransferTasksFromDLQResponse = GetTransferTasksResponse
+
+	// RangeDeleteTransferTaskFromDLQRequest is used to delete transfer tasks from DLQ
+	RangeDeleteTransferTaskFromDLQRequest struct {
+		ExclusiveBeginTaskID int64
+		InclusiveEndTaskID   int64
+	}
+
+	// PutTimerTaskToDLQRequest is used to put a timer task to dlq
+	PutTimerTaskToDLQRequest struct {
+		TaskInfo *pblobs.TimerTaskInfo
+	}
+
+	// GetTimerTasksFromDLQRequest is used to get timer tasks from dlq, timer tasks in the dlq are ordered by task ID
+	GetTimerTasksFromDLQRequest struct {
+		ReadLevel     int64
+		MaxReadLevel  int64
+		BatchSize     int
+		NextPageToken []byte
+	}
+
+	// GetTimerTasksFromDLQResponse is the response for GetTimerTasksFromDLQ
+	GetTimerTasksFromDLQResponse struct {
+		Tasks         []*pblobs.TimerTaskInfo
+		NextPageToken []byte
+	}
+
+	// RangeDeleteTimerTaskFromDLQRequest is used to delete timer tasks from DLQ
+	RangeDeleteTimerTaskFromDLQRequest struct {
+		ExclusiveBeginTaskID int64
+		InclusiveEndTaskID   int64
+	}
+
 	// RangeCompleteTimerTaskRequest is used to complete a range of tasks in the timer task queue
 	RangeCompleteTimerTaskRequest struct {
 		InclusiveBeginTimestamp time.Time
@@ -1096,6 +1190,9 @@ type (
 		ConfigVersion               int64
 		FailoverVersion             int64
 		FailoverNotificationVersion int64
+		HandoverClusterName         string
+		FailoverEndTime             int64
+		MigrationClusterName        string
 		NotificationVersion         int64
 	}
 
@@ -1107,6 +1204,9 @@ type (
 		ConfigVersion               int64
 		FailoverVersion             int64
 		FailoverNotificationVersion int64
+		HandoverClusterName         string
+		FailoverEndTime             int64
+		MigrationClusterName        string
 		NotificationVersion         int64
 	}
 
@@ -1320,6 +1420,16 @@ type (
 		BranchToken []byte
 	}
 
+	// MoveHistoryTreeRequest is used to move all branches of a tree to another shard
+	MoveHistoryTreeRequest struct {
+		// A UUID of a tree
+		TreeID primitives.UUID
+		// The shard the tree is currently stored in
+		ShardID int
+		// The shard the tree is moved to
+		TargetShardID int
+	}
+
 	// HistoryBranchDetail contains detailed information of a branch
 	HistoryBranchDetail struct {
 		TreeID   string
@@ -1340,6 +1450,9 @@ type (
 		NextPageToken []byte
 		// maximum number of branches returned per page
 		PageSize int
+		// optional, restricts the branches to the ones of a history shard
+		// only supported by SQL stores, which partition history by shard
+		ShardID *int
 	}
 
 	// GetAllHistoryTreeBranchesResponse is a response to GetAllHistoryTreeBranches
@@ -1368,6 +1481,20 @@ type (
 		pblobs.ImmutableClusterMetadata
 	}
 
+	// GetHistoryShardRoutingResponse is the response to GetHistoryShardRouting
+	GetHistoryShardRoutingResponse struct {
+		Routing *pblobs.HistoryShardRouting
+		// Version is zero until the routing is updated for the first time
+		Version int64
+	}
+
+	// UpdateHistoryShardRoutingRequest is used to update the history shard routing,
+	// the update fails with a ConditionFailedError if the version changed in between
+	UpdateHistoryShardRoutingRequest struct {
+		Routing         *pblobs.HistoryShardRouting
+		PreviousVersion int64
+	}
+
 	// GetClusterMembersRequest is the response to GetClusterMembers
 	GetClusterMembersRequest struct {
 		LastHeartbeatWithin time.Duration
@@ -1439,11 +1566,15 @@ type (
 		DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error
 		DeleteCurrentWorkflowExecution(request *DeleteCurrentWorkflowExecutionRequest) error
 		GetCurrentExecution(request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		ListConcreteExecutions(request *ListConcreteExecutionsRequest) (*ListConcreteExecutionsResponse, error)
 
 		// Transfer task related methods
 		GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(request *CompleteTransferTaskRequest) error
 		RangeCompleteTransferTask(request *RangeCompleteTransferTaskRequest) error
+		PutTransferTaskToDLQ(request *PutTransferTaskToDLQRequest) error
+		GetTransferTasksFromDLQ(request *GetTransferTasksFromDLQRequest) (*GetTransferTasksFromDLQResponse, error)
+		RangeDeleteTransferTaskFromDLQ(request *RangeDeleteTransferTaskFromDLQRequest) error
 
 		// Replication task related methods
 		GetReplicationTasks(request *GetReplicationTasksRequest) (*GetReplicationTasksResponse, error)
@@ -1458,6 +1589,9 @@ type (
 		GetTimerIndexTasks(request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error)
 		CompleteTimerTask(request *CompleteTimerTaskRequest) error
 		RangeCompleteTimerTask(request *RangeCompleteTimerTaskRequest) error
+		PutTimerTaskToDLQ(request *PutTimerTaskToDLQRequest) error
+		GetTimerTasksFromDLQ(request *GetTimerTasksFromDLQRequest) (*GetTimerTasksFromDLQResponse, error)
+		RangeDeleteTimerTaskFromDLQ(request *RangeDeleteTimerTaskFromDLQRequest) error
 
 		// Remove Task due to corrupted data
 		DeleteTask(request *DeleteTaskRequest) error
@@ -1519,6 +1653,8 @@ type (
 		GetHistoryTree(request *GetHistoryTreeRequest) (*GetHistoryTreeResponse, error)
 		// GetAllHistoryTreeBranches returns all branches of all trees
 		GetAllHistoryTreeBranches(request *GetAllHistoryTreeBranchesRequest) (*GetAllHistoryTreeBranchesResponse, error)
+		// MoveHistoryTree moves all branches of a tree to another shard, it is used when history shards are split
+		MoveHistoryTree(request *MoveHistoryTreeRequest) error
 	}
 
 	// MetadataManager is used to manage metadata CRUD for domain entities
@@ -1540,6 +1676,8 @@ type (
 		GetName() string
 		InitializeImmutableClusterMetadata(request *InitializeImmutableClusterMetadataRequest) (*InitializeImmutableClusterMetadataResponse, error)
 		GetImmutableClusterMetadata() (*GetImmutableClusterMetadataResponse, error)
+		GetHistoryShardRouting() (*GetHistoryShardRoutingResponse, error)
+		UpdateHistoryShardRouting(request *UpdateHistoryShardRoutingRequest) error
 		GetClusterMembers(request *GetClusterMembersRequest) (*GetClusterMembersResponse, error)
 		UpsertClusterMembership(request *UpsertClusterMembershipRequest) error
 		PruneClusterMembership(request *PruneClusterMembershipRequest) error
@@ -2114,6 +2252,76 @@ func (u *UpsertWorkflowSearchAttributesTask) SetVisibilityTimestamp(timestamp ti
 	u.VisibilityTimestamp = timestamp
 }
 
+// GetType returns the type of the completion callback transfer task
+func (u *CompletionCallbackTask) GetType() int {
+	return TransferTaskTypeCompletionCallback
+}
+
+// GetVersion returns the version of the completion callback transfer task
+func (u *CompletionCallbackTask) GetVersion() int64 {
+	return u.Version
+}
+
+// SetVersion returns the version of the completion callback transfer task
+func (u *CompletionCallbackTask) SetVersion(version int64) {
+	u.Version = version
+}
+
+// GetTaskID returns the sequence ID of the completion callback transfer task.
+func (u *CompletionCallbackTask) GetTaskID() int64 {
+	return u.TaskID
+}
+
+// SetTaskID sets the sequence ID of the completion callback transfer task.
+func (u *CompletionCallbackTask) SetTaskID(id int64) {
+	u.TaskID = id
+}
+
+// GetVisibilityTimestamp get the visibility timestamp
+func (u *CompletionCallbackTask) GetVisibilityTimestamp() time.Time {
+	return u.VisibilityTimestamp
+}
+
+// SetVisibilityTimestamp set the visibility timestamp
+func (u *CompletionCallbackTask) SetVisibilityTimestamp(timestamp time.Time) {
+	u.VisibilityTimestamp = timestamp
+}
+
+// GetType returns the type of the history stream transfer task
+func (u *HistoryStreamTask) GetType() int {
+	return TransferTaskTypeHistoryStream
+}
+
+// GetVersion returns the version of the history stream transfer task
+func (u *HistoryStreamTask) GetVersion() int64 {
+	return u.Version
+}
+
+// SetVersion returns the version of the history stream transfer task
+func (u *HistoryStreamTask) SetVersion(version int64) {
+	u.Version = version
+}
+
+// GetTaskID returns the sequence ID of the history stream transfer task.
+func (u *HistoryStreamTask) GetTaskID() int64 {
+	return u.TaskID
+}
+
+// SetTaskID sets the sequence ID of the history stream transfer task.
+func (u *HistoryStreamTask) SetTaskID(id int64) {
+	u.TaskID = id
+}
+
+// GetVisibilityTimestamp get the visibility timestamp
+func (u *HistoryStreamTask) GetVisibilityTimestamp() time.Time {
+	return u.VisibilityTimestamp
+}
+
+// SetVisibilityTimestamp set the visibility timestamp
+func (u *HistoryStreamTask) SetVisibilityTimestamp(timestamp time.Time) {
+	u.VisibilityTimestamp = timestamp
+}
+
 // GetType returns the type of the start child transfer task
 func (u *StartChildExecutionTask) GetType() int {
 	return TransferTaskTypeStartChildExecution
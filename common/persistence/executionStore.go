@@ -180,6 +180,8 @@ func (m *executionManagerImpl) DeserializeExecutionInfo(
 		AutoResetPoints:                    autoResetPoints,
 		SearchAttributes:                   info.SearchAttributes,
 		Memo:                               info.Memo,
+		CompletionCallbacks:                info.CompletionCallbacks,
+		HistoryStreamNextEventID:           info.HistoryStreamNextEventID,
 	}
 	newStats := &ExecutionStats{
 		HistorySize: info.HistorySize,
@@ -500,6 +502,8 @@ func (m *executionManagerImpl) SerializeExecutionInfo(
 		ExpirationSeconds:                  info.ExpirationSeconds,
 		Memo:                               info.Memo,
 		SearchAttributes:                   info.SearchAttributes,
+		CompletionCallbacks:                info.CompletionCallbacks,
+		HistoryStreamNextEventID:           info.HistoryStreamNextEventID,
 
 		// attributes which are not related to mutable state
 		HistorySize: stats.HistorySize,
@@ -788,6 +792,12 @@ func (m *executionManagerImpl) GetCurrentExecution(
 	return m.persistence.GetCurrentExecution(request)
 }
 
+func (m *executionManagerImpl) ListConcreteExecutions(
+	request *ListConcreteExecutionsRequest,
+) (*ListConcreteExecutionsResponse, error) {
+	return m.persistence.ListConcreteExecutions(request)
+}
+
 // Transfer task related methods
 func (m *executionManagerImpl) GetTransferTasks(
 	request *GetTransferTasksRequest,
@@ -807,6 +817,24 @@ func (m *executionManagerImpl) RangeCompleteTransferTask(
 	return m.persistence.RangeCompleteTransferTask(request)
 }
 
+func (m *executionManagerImpl) PutTransferTaskToDLQ(
+	request *PutTransferTaskToDLQRequest,
+) error {
+	return m.persistence.PutTransferTaskToDLQ(request)
+}
+
+func (m *executionManagerImpl) GetTransferTasksFromDLQ(
+	request *GetTransferTasksFromDLQRequest,
+) (*GetTransferTasksFromDLQResponse, error) {
+	return m.persistence.GetTransferTasksFromDLQ(request)
+}
+
+func (m *executionManagerImpl) RangeDeleteTransferTaskFromDLQ(
+	request *RangeDeleteTransferTaskFromDLQRequest,
+) error {
+	return m.persistence.RangeDeleteTransferTaskFromDLQ(request)
+}
+
 // Replication task related methods
 func (m *executionManagerImpl) GetReplicationTasks(
 	request *GetReplicationTasksRequest,
@@ -869,6 +897,24 @@ func (m *executionManagerImpl) RangeCompleteTimerTask(
 	return m.persistence.RangeCompleteTimerTask(request)
 }
 
+func (m *executionManagerImpl) PutTimerTaskToDLQ(
+	request *PutTimerTaskToDLQRequest,
+) error {
+	return m.persistence.PutTimerTaskToDLQ(request)
+}
+
+func (m *executionManagerImpl) GetTimerTasksFromDLQ(
+	request *GetTimerTasksFromDLQRequest,
+) (*GetTimerTasksFromDLQResponse, error) {
+	return m.persistence.GetTimerTasksFromDLQ(request)
+}
+
+func (m *executionManagerImpl) RangeDeleteTimerTaskFromDLQ(
+	request *RangeDeleteTimerTaskFromDLQRequest,
+) error {
+	return m.persistence.RangeDeleteTimerTaskFromDLQ(request)
+}
+
 func (m *executionManagerImpl) Close() {
 	m.persistence.Close()
 }
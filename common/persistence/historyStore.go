@@ -155,6 +155,17 @@ func (m *historyV2ManagerImpl) GetHistoryTree(
 	return m.persistence.GetHistoryTree(request)
 }
 
+// MoveHistoryTree moves all branches of a tree to another shard
+func (m *historyV2ManagerImpl) MoveHistoryTree(
+	request *MoveHistoryTreeRequest,
+) error {
+
+	if request.ShardID == request.TargetShardID {
+		return nil
+	}
+	return m.persistence.MoveHistoryTree(request)
+}
+
 // AppendHistoryNodes add(or override) a node to a history branch
 func (m *historyV2ManagerImpl) AppendHistoryNodes(
 	request *AppendHistoryNodesRequest,
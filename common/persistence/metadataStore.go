@@ -39,7 +39,7 @@ type (
 
 var _ MetadataManager = (*metadataManagerImpl)(nil)
 
-//NewMetadataManagerImpl returns new MetadataManager
+// NewMetadataManagerImpl returns new MetadataManager
 func NewMetadataManagerImpl(persistence MetadataStore, logger log.Logger) MetadataManager {
 	return &metadataManagerImpl{
 		serializer:  NewPayloadSerializer(),
@@ -86,6 +86,9 @@ func (m *metadataManagerImpl) GetDomain(request *GetDomainRequest) (*GetDomainRe
 		ConfigVersion:               resp.ConfigVersion,
 		FailoverVersion:             resp.FailoverVersion,
 		FailoverNotificationVersion: resp.FailoverNotificationVersion,
+		HandoverClusterName:         resp.HandoverClusterName,
+		FailoverEndTime:             resp.FailoverEndTime,
+		MigrationClusterName:        resp.MigrationClusterName,
 		NotificationVersion:         resp.NotificationVersion,
 	}, nil
 }
@@ -102,6 +105,9 @@ func (m *metadataManagerImpl) UpdateDomain(request *UpdateDomainRequest) error {
 		ConfigVersion:               request.ConfigVersion,
 		FailoverVersion:             request.FailoverVersion,
 		FailoverNotificationVersion: request.FailoverNotificationVersion,
+		HandoverClusterName:         request.HandoverClusterName,
+		FailoverEndTime:             request.FailoverEndTime,
+		MigrationClusterName:        request.MigrationClusterName,
 		NotificationVersion:         request.NotificationVersion,
 	})
 }
@@ -133,6 +139,9 @@ func (m *metadataManagerImpl) ListDomains(request *ListDomainsRequest) (*ListDom
 			ConfigVersion:               d.ConfigVersion,
 			FailoverVersion:             d.FailoverVersion,
 			FailoverNotificationVersion: d.FailoverNotificationVersion,
+			HandoverClusterName:         d.HandoverClusterName,
+			FailoverEndTime:             d.FailoverEndTime,
+			MigrationClusterName:        d.MigrationClusterName,
 			NotificationVersion:         d.NotificationVersion,
 		})
 	}
@@ -43,8 +43,17 @@ func ValidateCreateWorkflowModeState(
 	}
 
 	switch mode {
-	case CreateWorkflowModeBrandNew,
-		CreateWorkflowModeWorkflowIDReuse,
+	case CreateWorkflowModeBrandNew:
+		// a closed workflow can become the current one when it is copied as a whole
+		if workflowState == WorkflowStateZombie {
+			return newInvalidCreateWorkflowMode(
+				mode,
+				workflowState,
+			)
+		}
+		return nil
+
+	case CreateWorkflowModeWorkflowIDReuse,
 		CreateWorkflowModeContinueAsNew:
 		if workflowState == WorkflowStateZombie ||
 			workflowState == WorkflowStateCompleted {
@@ -57,8 +66,7 @@ func ValidateCreateWorkflowModeState(
 
 	case CreateWorkflowModeZombie:
 		if workflowState == WorkflowStateCreated ||
-			workflowState == WorkflowStateRunning ||
-			workflowState == WorkflowStateCompleted {
+			workflowState == WorkflowStateRunning {
 			return newInvalidCreateWorkflowMode(
 				mode,
 				workflowState,
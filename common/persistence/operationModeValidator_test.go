@@ -63,7 +63,6 @@ func (s *validateOperationWorkflowModeStateSuite) TestCreateMode_UpdateCurrent()
 	}
 
 	creatModes := []CreateWorkflowMode{
-		CreateWorkflowModeBrandNew,
 		CreateWorkflowModeWorkflowIDReuse,
 		CreateWorkflowModeContinueAsNew,
 	}
@@ -81,12 +80,32 @@ func (s *validateOperationWorkflowModeStateSuite) TestCreateMode_UpdateCurrent()
 	}
 }
 
+func (s *validateOperationWorkflowModeStateSuite) TestCreateMode_BrandNew() {
+
+	stateToError := map[int]bool{
+		WorkflowStateCreated:   false,
+		WorkflowStateRunning:   false,
+		WorkflowStateCompleted: false,
+		WorkflowStateZombie:    true,
+	}
+
+	for state, expectError := range stateToError {
+		testSnapshot := s.newTestWorkflowSnapshot(state)
+		err := ValidateCreateWorkflowModeState(CreateWorkflowModeBrandNew, testSnapshot)
+		if !expectError {
+			s.NoError(err, err)
+		} else {
+			s.Error(err, err)
+		}
+	}
+}
+
 func (s *validateOperationWorkflowModeStateSuite) TestCreateMode_BypassCurrent() {
 
 	stateToError := map[int]bool{
 		WorkflowStateCreated:   true,
 		WorkflowStateRunning:   true,
-		WorkflowStateCompleted: true,
+		WorkflowStateCompleted: false,
 		WorkflowStateZombie:    false,
 	}
 
@@ -324,4 +324,32 @@ func (s *ClusterMetadataManagerSuite) TestInitImmutableMetadataReadWrite() {
 	s.True(getResp != nil)
 	s.Equal(getResp.ClusterName, clusterNameToPersist)
 	s.Equal(getResp.HistoryShardCount, historyShardsToPersist)
+
+	// Case 5 - History shard routing defaults to the persisted shard count
+	routingResp, err := s.ClusterMetadataManager.GetHistoryShardRouting()
+	s.Nil(err)
+	s.Equal(historyShardsToPersist, routingResp.Routing.ShardCount)
+	s.Equal(int64(0), routingResp.Version)
+
+	// Case 6 - History shard routing is updated only from the latest version
+	newRouting := &persistenceblobs.HistoryShardRouting{
+		ShardCount:       historyShardsToPersist,
+		TargetShardCount: historyShardsToPersist * 2,
+		SplitShardIDs:    []int32{1, 2},
+	}
+	err = s.ClusterMetadataManager.UpdateHistoryShardRouting(&p.UpdateHistoryShardRoutingRequest{
+		Routing:         newRouting,
+		PreviousVersion: routingResp.Version,
+	})
+	s.Nil(err)
+	err = s.ClusterMetadataManager.UpdateHistoryShardRouting(&p.UpdateHistoryShardRoutingRequest{
+		Routing:         &persistenceblobs.HistoryShardRouting{ShardCount: int32(77)},
+		PreviousVersion: routingResp.Version,
+	})
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	routingResp, err = s.ClusterMetadataManager.GetHistoryShardRouting()
+	s.Nil(err)
+	s.Equal(int64(1), routingResp.Version)
+	s.Equal(newRouting, routingResp.Routing)
 }
@@ -258,14 +258,18 @@ func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionStateCloseStatus() {
 	req.NewWorkflowSnapshot.ExecutionInfo.WorkflowID = workflowExecutionStatusCompleted.GetWorkflowId()
 	req.NewWorkflowSnapshot.ExecutionInfo.RunID = workflowExecutionStatusCompleted.GetRunId()
 	req.NewWorkflowSnapshot.ExecutionInfo.State = p.WorkflowStateCompleted
-	for _, invalidCloseStatus := range invalidCloseStatuses {
-		req.NewWorkflowSnapshot.ExecutionInfo.CloseStatus = enums.WorkflowExecutionCloseStatus(invalidCloseStatus)
-		_, err := s.ExecutionManager.CreateWorkflowExecution(req)
-		s.IsType(&serviceerror.Internal{}, err)
-	}
 	req.NewWorkflowSnapshot.ExecutionInfo.CloseStatus = p.WorkflowCloseStatusRunning
 	_, err = s.ExecutionManager.CreateWorkflowExecution(req)
 	s.IsType(&serviceerror.Internal{}, err)
+	// workflows copied as a whole, e.g. rehydrated or imported ones, are created as closed
+	req.NewWorkflowSnapshot.ExecutionInfo.CloseStatus = p.WorkflowCloseStatusTimedOut
+	_, err = s.ExecutionManager.CreateWorkflowExecution(req)
+	s.Nil(err)
+	info, err = s.GetWorkflowExecutionInfo(domainID, workflowExecutionStatusCompleted)
+	s.Nil(err)
+	s.Equal(p.WorkflowStateCompleted, info.ExecutionInfo.State)
+	s.EqualValues(p.WorkflowCloseStatusTimedOut, info.ExecutionInfo.CloseStatus)
+	s.assertChecksumsEqual(csum, info.Checksum)
 
 	// for zombie workflow creation, we must use existing workflow ID which got created
 	// since we do not allow creation of zombie workflow without current record
@@ -368,6 +372,25 @@ func (s *ExecutionManagerSuite) TestCreateWorkflowExecutionWithZombieState() {
 	s.Equal(p.WorkflowStateZombie, info.ExecutionInfo.State)
 	s.EqualValues(p.WorkflowCloseStatusRunning, info.ExecutionInfo.CloseStatus)
 	s.assertChecksumsEqual(csum, info.Checksum)
+
+	workflowExecutionClosed := commonproto.WorkflowExecution{
+		WorkflowId: workflowID,
+		RunId:      uuid.New(),
+	}
+	req.NewWorkflowSnapshot.ExecutionInfo.RunID = workflowExecutionClosed.GetRunId()
+	req.Mode = p.CreateWorkflowModeZombie
+	req.NewWorkflowSnapshot.ExecutionInfo.State = p.WorkflowStateCompleted
+	req.NewWorkflowSnapshot.ExecutionInfo.CloseStatus = p.WorkflowCloseStatusCompleted
+	_, err = s.ExecutionManager.CreateWorkflowExecution(req)
+	s.Nil(err)
+	// a closed workflow created bypassing the current record leaves the current run alone
+	currentRunID, err = s.GetCurrentWorkflowRunID(domainID, workflowExecutionRunning.GetWorkflowId())
+	s.Nil(err)
+	s.Equal(workflowExecutionRunning.GetRunId(), currentRunID)
+	info, err = s.GetWorkflowExecutionInfo(domainID, workflowExecutionClosed)
+	s.Nil(err)
+	s.Equal(p.WorkflowStateCompleted, info.ExecutionInfo.State)
+	s.EqualValues(p.WorkflowCloseStatusCompleted, info.ExecutionInfo.CloseStatus)
 }
 
 // TestUpdateWorkflowExecutionStateCloseStatus test
@@ -1894,6 +1917,44 @@ func (s *ExecutionManagerSuite) TestGetCurrentWorkflow() {
 	s.Empty(task1, "Expected empty task identifier.")
 }
 
+// TestListConcreteExecutions test
+func (s *ExecutionManagerSuite) TestListConcreteExecutions() {
+	domainID := "8f6e5c6b-d5f5-4b53-9c42-0e8f1b7e4a31"
+	executions := []commonproto.WorkflowExecution{
+		{WorkflowId: "list-concrete-executions-test-1", RunId: "0d1b4ef0-9c7e-4b3c-8f8d-2b6b1a0b5c01"},
+		{WorkflowId: "list-concrete-executions-test-2", RunId: "0d1b4ef0-9c7e-4b3c-8f8d-2b6b1a0b5c02"},
+	}
+	for _, execution := range executions {
+		_, err := s.CreateWorkflowExecution(domainID, execution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+		s.NoError(err)
+	}
+
+	listed := make(map[string]string)
+	var pageToken []byte
+	for {
+		response, err := s.ExecutionManager.ListConcreteExecutions(&p.ListConcreteExecutionsRequest{
+			PageSize:      1,
+			NextPageToken: pageToken,
+		})
+		s.NoError(err)
+		s.True(len(response.Executions) <= 1)
+		for _, execution := range response.Executions {
+			if execution.DomainID == domainID {
+				listed[execution.WorkflowID] = execution.RunID
+			}
+		}
+		pageToken = response.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	s.Equal(len(executions), len(listed))
+	for _, execution := range executions {
+		s.Equal(execution.GetRunId(), listed[execution.GetWorkflowId()])
+	}
+}
+
 // TestTransferTasksThroughUpdate test
 func (s *ExecutionManagerSuite) TestTransferTasksThroughUpdate() {
 	domainID := "b785a8ba-bd7d-4760-bb05-41b115f3e10a"
@@ -5298,6 +5359,73 @@ func (s *ExecutionManagerSuite) TestReplicationDLQ() {
 	s.Len(resp.Tasks, 0)
 }
 
+// TestTransferTaskDLQ test
+func (s *ExecutionManagerSuite) TestTransferTaskDLQ() {
+	for taskID := int64(1); taskID <= 3; taskID++ {
+		err := s.PutTransferTaskToDLQ(&pblobs.TransferTaskInfo{
+			DomainID:   primitives.MustParseUUID(uuid.New()),
+			WorkflowID: uuid.New(),
+			RunID:      primitives.MustParseUUID(uuid.New()),
+			TaskID:     taskID,
+			TaskType:   p.TransferTaskTypeDecisionTask,
+		})
+		s.NoError(err)
+	}
+
+	resp, err := s.GetTransferTasksFromDLQ(0, 3, 2, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 2)
+	s.Equal(int64(1), resp.Tasks[0].GetTaskID())
+	s.Equal(int64(2), resp.Tasks[1].GetTaskID())
+	s.NotEmpty(resp.NextPageToken)
+	resp, err = s.GetTransferTasksFromDLQ(0, 3, 2, resp.NextPageToken)
+	s.NoError(err)
+	s.Len(resp.Tasks, 1)
+	s.Equal(int64(3), resp.Tasks[0].GetTaskID())
+
+	err = s.RangeDeleteTransferTaskFromDLQ(0, 2)
+	s.NoError(err)
+	resp, err = s.GetTransferTasksFromDLQ(0, 3, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 1)
+	s.Equal(int64(3), resp.Tasks[0].GetTaskID())
+	err = s.RangeDeleteTransferTaskFromDLQ(0, 3)
+	s.NoError(err)
+	resp, err = s.GetTransferTasksFromDLQ(0, 3, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 0)
+}
+
+// TestTimerTaskDLQ test
+func (s *ExecutionManagerSuite) TestTimerTaskDLQ() {
+	now := time.Now()
+	for taskID := int64(1); taskID <= 2; taskID++ {
+		protoTime, err := types.TimestampProto(now.Add(time.Duration(taskID) * time.Second))
+		s.NoError(err)
+		err = s.PutTimerTaskToDLQ(&pblobs.TimerTaskInfo{
+			DomainID:            primitives.MustParseUUID(uuid.New()),
+			WorkflowID:          uuid.New(),
+			RunID:               primitives.MustParseUUID(uuid.New()),
+			TaskID:              taskID,
+			TaskType:            p.TaskTypeUserTimer,
+			VisibilityTimestamp: protoTime,
+		})
+		s.NoError(err)
+	}
+
+	resp, err := s.GetTimerTasksFromDLQ(0, 2, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 2)
+	s.Equal(int64(1), resp.Tasks[0].GetTaskID())
+	s.Equal(int64(2), resp.Tasks[1].GetTaskID())
+
+	err = s.RangeDeleteTimerTaskFromDLQ(0, 2)
+	s.NoError(err)
+	resp, err = s.GetTimerTasksFromDLQ(0, 2, 10, nil)
+	s.NoError(err)
+	s.Len(resp.Tasks, 0)
+}
+
 func copyWorkflowExecutionInfo(sourceInfo *p.WorkflowExecutionInfo) *p.WorkflowExecutionInfo {
 	return &p.WorkflowExecutionInfo{
 		DomainID:                    sourceInfo.DomainID,
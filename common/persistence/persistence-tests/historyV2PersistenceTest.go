@@ -117,11 +117,6 @@ func (s *HistoryV2PersistenceSuite) TestGenUUIDs() {
 
 // TestScanAllTrees test
 func (s *HistoryV2PersistenceSuite) TestScanAllTrees() {
-	// TODO https://github.com/temporalio/temporal/issues/2458
-	if s.HistoryV2Mgr.GetName() != "cassandra" {
-		return
-	}
-
 	resp, err := s.HistoryV2Mgr.GetAllHistoryTreeBranches(&p.GetAllHistoryTreeBranchesRequest{
 		PageSize: 1,
 	})
@@ -170,6 +165,14 @@ func (s *HistoryV2PersistenceSuite) TestScanAllTrees() {
 	}
 
 	s.Equal(0, len(trees))
+
+	if s.HistoryV2Mgr.GetName() == "cassandra" {
+		return
+	}
+	shardID := int(s.ShardInfo.ShardID)
+	s.Equal(totalTrees, s.countAllTreeBranches(&shardID, pgSize))
+	otherShardID := shardID + 1
+	s.Equal(0, s.countAllTreeBranches(&otherShardID, pgSize))
 }
 
 // TestReadBranchByPagination test
@@ -844,3 +847,21 @@ func (s *HistoryV2PersistenceSuite) fork(forkBranch []byte, forkNodeID int64) ([
 	err := backoff.Retry(op, historyTestRetryPolicy, isConditionFail)
 	return bi, err
 }
+
+func (s *HistoryV2PersistenceSuite) countAllTreeBranches(shardID *int, pageSize int) int {
+	count := 0
+	var pgToken []byte
+	for {
+		resp, err := s.HistoryV2Mgr.GetAllHistoryTreeBranches(&p.GetAllHistoryTreeBranchesRequest{
+			PageSize:      pageSize,
+			NextPageToken: pgToken,
+			ShardID:       shardID,
+		})
+		s.Nil(err)
+		count += len(resp.Branches)
+		if len(resp.NextPageToken) == 0 {
+			return count
+		}
+		pgToken = resp.NextPageToken
+	}
+}
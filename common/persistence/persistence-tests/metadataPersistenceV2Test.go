@@ -29,6 +29,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -766,6 +767,69 @@ func (m *MetadataPersistenceSuiteV2) TestUpdateDomain() {
 	m.Equal(notificationVersion, resp5.NotificationVersion)
 }
 
+// TestUpdateDomainHandover test
+func (m *MetadataPersistenceSuiteV2) TestUpdateDomainHandover() {
+	id := uuid.New()
+	name := "update-domain-handover-test-name"
+	clusterActive := "some random active cluster name"
+	clusterStandby := "some random standby cluster name"
+	clusters := []*p.ClusterReplicationConfig{
+		{
+			ClusterName: clusterActive,
+		},
+		{
+			ClusterName: clusterStandby,
+		},
+	}
+
+	_, err := m.CreateDomain(
+		&p.DomainInfo{
+			ID:     id,
+			Name:   name,
+			Status: p.DomainStatusRegistered,
+			Data:   map[string]string{},
+		},
+		&p.DomainConfig{
+			Retention: 1,
+		},
+		&p.DomainReplicationConfig{
+			ActiveClusterName: clusterActive,
+			Clusters:          clusters,
+		},
+		true,
+		0,
+		0,
+	)
+	m.NoError(err)
+
+	resp1, err := m.GetDomain(id, "")
+	m.NoError(err)
+	m.Empty(resp1.HandoverClusterName)
+	m.Zero(resp1.FailoverEndTime)
+
+	metadata, err := m.MetadataManager.GetMetadata()
+	m.NoError(err)
+	failoverEndTime := time.Now().Add(time.Minute).UnixNano()
+	err = m.MetadataManager.UpdateDomain(&p.UpdateDomainRequest{
+		Info:                        resp1.Info,
+		Config:                      resp1.Config,
+		ReplicationConfig:           resp1.ReplicationConfig,
+		ConfigVersion:               resp1.ConfigVersion,
+		FailoverVersion:             resp1.FailoverVersion,
+		FailoverNotificationVersion: resp1.FailoverNotificationVersion,
+		HandoverClusterName:         clusterStandby,
+		FailoverEndTime:             failoverEndTime,
+		NotificationVersion:         metadata.NotificationVersion,
+	})
+	m.NoError(err)
+
+	resp2, err := m.GetDomain("", name)
+	m.NoError(err)
+	m.Equal(clusterActive, resp2.ReplicationConfig.ActiveClusterName)
+	m.Equal(clusterStandby, resp2.HandoverClusterName)
+	m.Equal(failoverEndTime, resp2.FailoverEndTime)
+}
+
 // TestDeleteDomain test
 func (m *MetadataPersistenceSuiteV2) TestDeleteDomain() {
 	id := uuid.New()
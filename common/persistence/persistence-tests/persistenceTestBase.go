@@ -1160,6 +1160,84 @@ func (s *TestBase) RangeDeleteReplicationTaskFromDLQ(
 	})
 }
 
+// PutTransferTaskToDLQ is a utility method to insert a transfer task info into the DLQ
+func (s *TestBase) PutTransferTaskToDLQ(
+	taskInfo *pblobs.TransferTaskInfo,
+) error {
+
+	return s.ExecutionManager.PutTransferTaskToDLQ(&p.PutTransferTaskToDLQRequest{
+		TaskInfo: taskInfo,
+	})
+}
+
+// GetTransferTasksFromDLQ is a utility method to read transfer task info from the DLQ
+func (s *TestBase) GetTransferTasksFromDLQ(
+	readLevel int64,
+	maxReadLevel int64,
+	pageSize int,
+	pageToken []byte,
+) (*p.GetTransferTasksFromDLQResponse, error) {
+
+	return s.ExecutionManager.GetTransferTasksFromDLQ(&p.GetTransferTasksFromDLQRequest{
+		GetTransferTasksRequest: p.GetTransferTasksRequest{
+			ReadLevel:     readLevel,
+			MaxReadLevel:  maxReadLevel,
+			BatchSize:     pageSize,
+			NextPageToken: pageToken,
+		},
+	})
+}
+
+// RangeDeleteTransferTaskFromDLQ is a utility method to delete transfer task info from the DLQ
+func (s *TestBase) RangeDeleteTransferTaskFromDLQ(
+	beginTaskID int64,
+	endTaskID int64,
+) error {
+
+	return s.ExecutionManager.RangeDeleteTransferTaskFromDLQ(&p.RangeDeleteTransferTaskFromDLQRequest{
+		ExclusiveBeginTaskID: beginTaskID,
+		InclusiveEndTaskID:   endTaskID,
+	})
+}
+
+// PutTimerTaskToDLQ is a utility method to insert a timer task info into the DLQ
+func (s *TestBase) PutTimerTaskToDLQ(
+	taskInfo *pblobs.TimerTaskInfo,
+) error {
+
+	return s.ExecutionManager.PutTimerTaskToDLQ(&p.PutTimerTaskToDLQRequest{
+		TaskInfo: taskInfo,
+	})
+}
+
+// GetTimerTasksFromDLQ is a utility method to read timer task info from the DLQ
+func (s *TestBase) GetTimerTasksFromDLQ(
+	readLevel int64,
+	maxReadLevel int64,
+	pageSize int,
+	pageToken []byte,
+) (*p.GetTimerTasksFromDLQResponse, error) {
+
+	return s.ExecutionManager.GetTimerTasksFromDLQ(&p.GetTimerTasksFromDLQRequest{
+		ReadLevel:     readLevel,
+		MaxReadLevel:  maxReadLevel,
+		BatchSize:     pageSize,
+		NextPageToken: pageToken,
+	})
+}
+
+// RangeDeleteTimerTaskFromDLQ is a utility method to delete timer task info from the DLQ
+func (s *TestBase) RangeDeleteTimerTaskFromDLQ(
+	beginTaskID int64,
+	endTaskID int64,
+) error {
+
+	return s.ExecutionManager.RangeDeleteTimerTaskFromDLQ(&p.RangeDeleteTimerTaskFromDLQRequest{
+		ExclusiveBeginTaskID: beginTaskID,
+		InclusiveEndTaskID:   endTaskID,
+	})
+}
+
 // CompleteTransferTask is a utility method to complete a transfer task
 func (s *TestBase) CompleteTransferTask(taskID int64) error {
 
@@ -75,6 +75,9 @@ type (
 		// Initialize immutable metadata for the cluster. Takes no action if already initialized.
 		InitializeImmutableClusterMetadata(request *InternalInitializeImmutableClusterMetadataRequest) (*InternalInitializeImmutableClusterMetadataResponse, error)
 		GetImmutableClusterMetadata() (*InternalGetImmutableClusterMetadataResponse, error)
+		// History shard routing APIs
+		GetHistoryShardRouting() (*InternalGetHistoryShardRoutingResponse, error)
+		UpdateHistoryShardRouting(request *InternalUpdateHistoryShardRoutingRequest) error
 		// Membership APIs
 		GetClusterMembers(request *GetClusterMembersRequest) (*GetClusterMembersResponse, error)
 		UpsertClusterMembership(request *UpsertClusterMembershipRequest) error
@@ -96,11 +99,15 @@ type (
 		DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error
 		DeleteCurrentWorkflowExecution(request *DeleteCurrentWorkflowExecutionRequest) error
 		GetCurrentExecution(request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		ListConcreteExecutions(request *ListConcreteExecutionsRequest) (*ListConcreteExecutionsResponse, error)
 
 		// Transfer task related methods
 		GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(request *CompleteTransferTaskRequest) error
 		RangeCompleteTransferTask(request *RangeCompleteTransferTaskRequest) error
+		PutTransferTaskToDLQ(request *PutTransferTaskToDLQRequest) error
+		GetTransferTasksFromDLQ(request *GetTransferTasksFromDLQRequest) (*GetTransferTasksFromDLQResponse, error)
+		RangeDeleteTransferTaskFromDLQ(request *RangeDeleteTransferTaskFromDLQRequest) error
 
 		// Replication task related methods
 		GetReplicationTasks(request *GetReplicationTasksRequest) (*GetReplicationTasksResponse, error)
@@ -115,6 +122,9 @@ type (
 		GetTimerIndexTasks(request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse, error)
 		CompleteTimerTask(request *CompleteTimerTaskRequest) error
 		RangeCompleteTimerTask(request *RangeCompleteTimerTaskRequest) error
+		PutTimerTaskToDLQ(request *PutTimerTaskToDLQRequest) error
+		GetTimerTasksFromDLQ(request *GetTimerTasksFromDLQRequest) (*GetTimerTasksFromDLQResponse, error)
+		RangeDeleteTimerTaskFromDLQ(request *RangeDeleteTimerTaskFromDLQRequest) error
 
 		// Remove corrupted task
 		DeleteTask(request *DeleteTaskRequest) error
@@ -140,6 +150,8 @@ type (
 		GetHistoryTree(request *GetHistoryTreeRequest) (*GetHistoryTreeResponse, error)
 		// GetAllHistoryTreeBranches returns all branches of all trees
 		GetAllHistoryTreeBranches(request *GetAllHistoryTreeBranchesRequest) (*GetAllHistoryTreeBranchesResponse, error)
+		// MoveHistoryTree moves all branches of a tree to another shard
+		MoveHistoryTree(request *MoveHistoryTreeRequest) error
 	}
 
 	// VisibilityStore is the store interface for visibility
@@ -259,6 +271,10 @@ type (
 		ExpirationSeconds  int32
 		Memo               map[string][]byte
 		SearchAttributes   map[string][]byte
+		// callbacks to be delivered once the workflow is closed
+		CompletionCallbacks []*persistenceblobs.CompletionCallbackInfo
+		// ID of the first history event not yet published to the history stream
+		HistoryStreamNextEventID int64
 
 		// attributes which are not related to mutable state at all
 		HistorySize int64
@@ -655,6 +671,9 @@ type (
 		ConfigVersion               int64
 		FailoverVersion             int64
 		FailoverNotificationVersion int64
+		HandoverClusterName         string
+		FailoverEndTime             int64
+		MigrationClusterName        string
 		NotificationVersion         int64
 	}
 
@@ -666,6 +685,9 @@ type (
 		ConfigVersion               int64
 		FailoverVersion             int64
 		FailoverNotificationVersion int64
+		HandoverClusterName         string
+		FailoverEndTime             int64
+		MigrationClusterName        string
 		NotificationVersion         int64
 	}
 
@@ -696,6 +718,19 @@ type (
 		ImmutableClusterMetadata *serialization.DataBlob
 	}
 
+	// InternalGetHistoryShardRoutingResponse is the response to GetHistoryShardRouting
+	InternalGetHistoryShardRoutingResponse struct {
+		// Serialized HistoryShardRouting, nil if the routing was never updated
+		Routing *serialization.DataBlob
+		Version int64
+	}
+
+	// InternalUpdateHistoryShardRoutingRequest is the request to UpdateHistoryShardRouting
+	InternalUpdateHistoryShardRoutingRequest struct {
+		Routing         *serialization.DataBlob
+		PreviousVersion int64
+	}
+
 	// InternalUpsertClusterMembershipRequest is the request to UpsertClusterMembership
 	InternalUpsertClusterMembershipRequest struct {
 		ClusterMember
@@ -798,6 +833,8 @@ func InternalWorkflowExecutionInfoToProto(executionInfo *InternalWorkflowExecuti
 		AutoResetPointsEncoding:                 executionInfo.AutoResetPoints.GetEncoding().String(),
 		SearchAttributes:                        executionInfo.SearchAttributes,
 		Memo:                                    executionInfo.Memo,
+		CompletionCallbacks:                     executionInfo.CompletionCallbacks,
+		HistoryStreamNextEventID:                executionInfo.HistoryStreamNextEventID,
 	}
 
 	if !executionInfo.ExpirationTime.IsZero() {
@@ -889,6 +926,8 @@ func ProtoWorkflowExecutionToPartialInternalExecution(info *persistenceblobs.Wor
 		NonRetriableErrors:                 info.GetRetryNonRetryableErrors(),
 		SearchAttributes:                   info.GetSearchAttributes(),
 		Memo:                               info.GetMemo(),
+		CompletionCallbacks:                info.GetCompletionCallbacks(),
+		HistoryStreamNextEventID:           info.GetHistoryStreamNextEventID(),
 	}
 
 	if info.GetRetryExpirationTimeNanos() != 0 {
@@ -347,6 +347,20 @@ func (p *workflowExecutionPersistenceClient) GetCurrentExecution(request *GetCur
 	return response, err
 }
 
+func (p *workflowExecutionPersistenceClient) ListConcreteExecutions(request *ListConcreteExecutionsRequest) (*ListConcreteExecutionsResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceListConcreteExecutionsScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceListConcreteExecutionsScope, metrics.PersistenceLatency)
+	response, err := p.persistence.ListConcreteExecutions(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceListConcreteExecutionsScope, err)
+	}
+
+	return response, err
+}
+
 func (p *workflowExecutionPersistenceClient) GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error) {
 	p.metricClient.IncCounter(metrics.PersistenceGetTransferTasksScope, metrics.PersistenceRequests)
 
@@ -403,6 +417,54 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTransferTask(request *
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) PutTransferTaskToDLQ(
+	request *PutTransferTaskToDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistencePutTransferTaskToDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistencePutTransferTaskToDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.PutTransferTaskToDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistencePutTransferTaskToDLQScope, err)
+	}
+
+	return err
+}
+
+func (p *workflowExecutionPersistenceClient) GetTransferTasksFromDLQ(
+	request *GetTransferTasksFromDLQRequest,
+) (*GetTransferTasksFromDLQResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetTransferTasksFromDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetTransferTasksFromDLQScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetTransferTasksFromDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetTransferTasksFromDLQScope, err)
+	}
+
+	return response, err
+}
+
+func (p *workflowExecutionPersistenceClient) RangeDeleteTransferTaskFromDLQ(
+	request *RangeDeleteTransferTaskFromDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceRangeDeleteTransferTaskFromDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceRangeDeleteTransferTaskFromDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.RangeDeleteTransferTaskFromDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceRangeDeleteTransferTaskFromDLQScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) CompleteReplicationTask(request *CompleteReplicationTaskRequest) error {
 	p.metricClient.IncCounter(metrics.PersistenceCompleteReplicationTaskScope, metrics.PersistenceRequests)
 
@@ -537,6 +599,54 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTimerTask(request *Ran
 	return err
 }
 
+func (p *workflowExecutionPersistenceClient) PutTimerTaskToDLQ(
+	request *PutTimerTaskToDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistencePutTimerTaskToDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistencePutTimerTaskToDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.PutTimerTaskToDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistencePutTimerTaskToDLQScope, err)
+	}
+
+	return err
+}
+
+func (p *workflowExecutionPersistenceClient) GetTimerTasksFromDLQ(
+	request *GetTimerTasksFromDLQRequest,
+) (*GetTimerTasksFromDLQResponse, error) {
+	p.metricClient.IncCounter(metrics.PersistenceGetTimerTasksFromDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceGetTimerTasksFromDLQScope, metrics.PersistenceLatency)
+	response, err := p.persistence.GetTimerTasksFromDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceGetTimerTasksFromDLQScope, err)
+	}
+
+	return response, err
+}
+
+func (p *workflowExecutionPersistenceClient) RangeDeleteTimerTaskFromDLQ(
+	request *RangeDeleteTimerTaskFromDLQRequest,
+) error {
+	p.metricClient.IncCounter(metrics.PersistenceRangeDeleteTimerTaskFromDLQScope, metrics.PersistenceRequests)
+
+	sw := p.metricClient.StartTimer(metrics.PersistenceRangeDeleteTimerTaskFromDLQScope, metrics.PersistenceLatency)
+	err := p.persistence.RangeDeleteTimerTaskFromDLQ(request)
+	sw.Stop()
+
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceRangeDeleteTimerTaskFromDLQScope, err)
+	}
+
+	return err
+}
+
 func (p *workflowExecutionPersistenceClient) DeleteTask(request *DeleteTaskRequest) error {
 	p.metricClient.IncCounter(metrics.PersistenceDeleteTaskScope, metrics.PersistenceRequests)
 	sw := p.metricClient.StartTimer(metrics.PersistenceDeleteTaskScope, metrics.PersistenceLatency)
@@ -1171,6 +1281,18 @@ func (p *historyV2PersistenceClient) GetHistoryTree(request *GetHistoryTreeReque
 	return response, err
 }
 
+// MoveHistoryTree moves all branches of a tree to another shard
+func (p *historyV2PersistenceClient) MoveHistoryTree(request *MoveHistoryTreeRequest) error {
+	p.metricClient.IncCounter(metrics.PersistenceMoveHistoryTreeScope, metrics.PersistenceRequests)
+	sw := p.metricClient.StartTimer(metrics.PersistenceMoveHistoryTreeScope, metrics.PersistenceLatency)
+	err := p.persistence.MoveHistoryTree(request)
+	sw.Stop()
+	if err != nil {
+		p.updateErrorMetric(metrics.PersistenceMoveHistoryTreeScope, err)
+	}
+	return err
+}
+
 func (p *historyV2PersistenceClient) updateErrorMetric(scope int, err error) {
 	switch err.(type) {
 	case *serviceerror.NotFound:
@@ -1366,6 +1488,34 @@ func (c *clusterMetadataPersistenceClient) GetImmutableClusterMetadata() (*GetIm
 	return result, err
 }
 
+func (c *clusterMetadataPersistenceClient) GetHistoryShardRouting() (*GetHistoryShardRoutingResponse, error) {
+	c.metricClient.IncCounter(metrics.PersistenceGetHistoryShardRoutingScope, metrics.PersistenceRequests)
+
+	sw := c.metricClient.StartTimer(metrics.PersistenceGetHistoryShardRoutingScope, metrics.PersistenceLatency)
+	result, err := c.persistence.GetHistoryShardRouting()
+	sw.Stop()
+
+	if err != nil {
+		c.metricClient.IncCounter(metrics.PersistenceGetHistoryShardRoutingScope, metrics.PersistenceFailures)
+	}
+
+	return result, err
+}
+
+func (c *clusterMetadataPersistenceClient) UpdateHistoryShardRouting(request *UpdateHistoryShardRoutingRequest) error {
+	c.metricClient.IncCounter(metrics.PersistenceUpdateHistoryShardRoutingScope, metrics.PersistenceRequests)
+
+	sw := c.metricClient.StartTimer(metrics.PersistenceUpdateHistoryShardRoutingScope, metrics.PersistenceLatency)
+	err := c.persistence.UpdateHistoryShardRouting(request)
+	sw.Stop()
+
+	if err != nil {
+		c.metricClient.IncCounter(metrics.PersistenceUpdateHistoryShardRoutingScope, metrics.PersistenceFailures)
+	}
+
+	return err
+}
+
 func (c *clusterMetadataPersistenceClient) GetName() string {
 	return c.persistence.GetName()
 }
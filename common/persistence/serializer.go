@@ -65,6 +65,10 @@ type (
 		// serialize/deserialize immutable cluster metadata
 		SerializeImmutableClusterMetadata(icm *persistenceblobs.ImmutableClusterMetadata, encodingType common.EncodingType) (*serialization.DataBlob, error)
 		DeserializeImmutableClusterMetadata(data *serialization.DataBlob) (*persistenceblobs.ImmutableClusterMetadata, error)
+
+		// serialize/deserialize history shard routing
+		SerializeHistoryShardRouting(routing *persistenceblobs.HistoryShardRouting, encodingType common.EncodingType) (*serialization.DataBlob, error)
+		DeserializeHistoryShardRouting(data *serialization.DataBlob) (*persistenceblobs.HistoryShardRouting, error)
 	}
 
 	// CadenceSerializationError is an error type for cadence serialization
@@ -336,6 +340,39 @@ func (t *serializerImpl) DeserializeImmutableClusterMetadata(data *serialization
 	return event, err
 }
 
+func (t *serializerImpl) SerializeHistoryShardRouting(routing *persistenceblobs.HistoryShardRouting, encodingType common.EncodingType) (*serialization.DataBlob, error) {
+	if routing == nil {
+		routing = &persistenceblobs.HistoryShardRouting{}
+	}
+	return t.serialize(routing, encodingType)
+}
+
+func (t *serializerImpl) DeserializeHistoryShardRouting(data *serialization.DataBlob) (*persistenceblobs.HistoryShardRouting, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if len(data.Data) == 0 {
+		return nil, nil
+	}
+
+	routing := &persistenceblobs.HistoryShardRouting{}
+	var err error
+	switch data.Encoding {
+	case common.EncodingTypeJSON:
+		err = codec.NewJSONPBEncoder().Decode(data.Data, routing)
+	case common.EncodingTypeProto3, common.EncodingTypeThriftRW:
+		err = proto.Unmarshal(data.Data, routing)
+	default:
+		return nil, NewCadenceDeserializationError("DeserializeHistoryShardRouting invalid encoding")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return routing, err
+}
+
 func (t *serializerImpl) serializeProto(p serialization.ProtoMarshal, encodingType common.EncodingType) (*serialization.DataBlob, error) {
 	if p == nil {
 		return nil, nil
@@ -21,6 +21,7 @@ package sql
 
 import (
 	"encoding/binary"
+	"fmt"
 	"net"
 	"time"
 
@@ -90,6 +91,46 @@ func (s *sqlClusterMetadataManager) GetImmutableClusterMetadata() (*p.InternalGe
 	}, nil
 }
 
+func (s *sqlClusterMetadataManager) GetHistoryShardRouting() (*p.InternalGetHistoryShardRoutingResponse, error) {
+	row, err := s.db.GetClusterMetadataShardRouting()
+
+	if err != nil {
+		return nil, convertCommonErrors("GetHistoryShardRouting", err)
+	}
+
+	resp := &p.InternalGetHistoryShardRoutingResponse{
+		Version: row.ShardRoutingVersion,
+	}
+	if len(row.ShardRoutingData) > 0 {
+		resp.Routing = p.NewDataBlob(row.ShardRoutingData, common.EncodingType(row.ShardRoutingDataEncoding))
+	}
+	return resp, nil
+}
+
+func (s *sqlClusterMetadataManager) UpdateHistoryShardRouting(request *p.InternalUpdateHistoryShardRoutingRequest) error {
+	result, err := s.db.UpdateClusterMetadataShardRouting(&sqlplugin.ClusterMetadataShardRoutingRow{
+		ShardRoutingData:         request.Routing.Data,
+		ShardRoutingDataEncoding: string(request.Routing.Encoding),
+		ShardRoutingVersion:      request.PreviousVersion + 1,
+	}, request.PreviousVersion)
+
+	if err != nil {
+		return convertCommonErrors("UpdateHistoryShardRouting", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("UpdateHistoryShardRouting operation failed. Failed to check number of rows updated. Error: %v", err))
+	}
+	if rowsAffected != 1 {
+		return &p.ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update history shard routing. previous_version: %v", request.PreviousVersion),
+		}
+	}
+
+	return nil
+}
+
 func (s *sqlClusterMetadataManager) GetClusterMembers(request *p.GetClusterMembersRequest) (*p.GetClusterMembersResponse, error) {
 	pageToken := uint64(0)
 	if len(request.NextPageToken) > 0 {
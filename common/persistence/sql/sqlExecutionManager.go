@@ -719,12 +719,78 @@ func (m *sqlExecutionManager) GetCurrentExecution(
 	}, nil
 }
 
+type executionPageToken struct {
+	DomainID   primitives.UUID
+	WorkflowID string
+	RunID      primitives.UUID
+}
+
+func (t *executionPageToken) serialize() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *executionPageToken) deserialize(payload []byte) error {
+	return json.Unmarshal(payload, t)
+}
+
+func (m *sqlExecutionManager) ListConcreteExecutions(
+	request *p.ListConcreteExecutionsRequest,
+) (*p.ListConcreteExecutionsResponse, error) {
+
+	// the ids must not be nil, nil is NULL which doesn't compare to any id
+	pageToken := &executionPageToken{DomainID: primitives.UUID{}, RunID: primitives.UUID{}}
+	if len(request.NextPageToken) > 0 {
+		if err := pageToken.deserialize(request.NextPageToken); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("error deserializing executionPageToken: %v", err))
+		}
+	}
+
+	rows, err := m.db.RangeSelectFromExecutions(&sqlplugin.ExecutionsFilter{
+		ShardID:    m.shardID,
+		DomainID:   pageToken.DomainID,
+		WorkflowID: pageToken.WorkflowID,
+		RunID:      pageToken.RunID,
+		PageSize:   common.IntPtr(request.PageSize),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("ListConcreteExecutions operation failed. Select failed. Error: %v", err))
+	}
+
+	resp := &p.ListConcreteExecutionsResponse{}
+	for _, row := range rows {
+		resp.Executions = append(resp.Executions, &p.ConcreteExecution{
+			DomainID:   row.DomainID.String(),
+			WorkflowID: row.WorkflowID,
+			RunID:      row.RunID.String(),
+		})
+	}
+	if len(rows) == request.PageSize {
+		lastRow := rows[len(rows)-1]
+		pageToken = &executionPageToken{DomainID: lastRow.DomainID, WorkflowID: lastRow.WorkflowID, RunID: lastRow.RunID}
+		if resp.NextPageToken, err = pageToken.serialize(); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("ListConcreteExecutions operation failed. Failed to serialize page token: %v", err))
+		}
+	}
+	return resp, nil
+}
+
 func (m *sqlExecutionManager) GetTransferTasks(
 	request *p.GetTransferTasksRequest,
 ) (*p.GetTransferTasksResponse, error) {
 
+	readLevel := request.ReadLevel
+	if len(request.NextPageToken) > 0 {
+		var err error
+		if readLevel, err = deserializePageToken(request.NextPageToken); err != nil {
+			return nil, err
+		}
+	}
 	rows, err := m.db.SelectFromTransferTasks(&sqlplugin.TransferTasksFilter{
-		ShardID: m.shardID, MinTaskID: &request.ReadLevel, MaxTaskID: &request.MaxReadLevel})
+		ShardID:   m.shardID,
+		MinTaskID: &readLevel,
+		MaxTaskID: &request.MaxReadLevel,
+		PageSize:  request.BatchSize,
+	})
 	if err != nil {
 		if err != sql.ErrNoRows {
 			return nil, serviceerror.NewInternal(fmt.Sprintf("GetTransferTasks operation failed. Select failed. Error: %v", err))
@@ -738,6 +804,9 @@ func (m *sqlExecutionManager) GetTransferTasks(
 		}
 		resp.Tasks[i] = info
 	}
+	if request.BatchSize > 0 && len(rows) == request.BatchSize {
+		resp.NextPageToken = serializePageToken(rows[len(rows)-1].TaskID)
+	}
 
 	return resp, nil
 }
@@ -768,6 +837,67 @@ func (m *sqlExecutionManager) RangeCompleteTransferTask(
 	return nil
 }
 
+func (m *sqlExecutionManager) PutTransferTaskToDLQ(
+	request *p.PutTransferTaskToDLQRequest,
+) error {
+
+	blob, err := serialization.TransferTaskInfoToBlob(request.TaskInfo)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.InsertIntoTransferTasksDLQ(&sqlplugin.TaskDLQRow{
+		ShardID:      m.shardID,
+		TaskID:       request.TaskInfo.GetTaskID(),
+		Data:         blob.Data,
+		DataEncoding: string(blob.Encoding),
+	})
+	// Tasks are immutable. So it's fine if we already persisted it before.
+	if err != nil && !m.db.IsDupEntryError(err) {
+		return serviceerror.NewInternal(fmt.Sprintf("PutTransferTaskToDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
+func (m *sqlExecutionManager) GetTransferTasksFromDLQ(
+	request *p.GetTransferTasksFromDLQRequest,
+) (*p.GetTransferTasksFromDLQResponse, error) {
+
+	filter, err := m.getTaskDLQFilter(request.ReadLevel, request.MaxReadLevel, request.BatchSize, request.NextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.db.SelectFromTransferTasksDLQ(filter)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("GetTransferTasksFromDLQ operation failed. Select failed. Error: %v", err))
+	}
+
+	resp := &p.GetTransferTasksFromDLQResponse{Tasks: make([]*persistenceblobs.TransferTaskInfo, len(rows))}
+	for i, row := range rows {
+		info, err := serialization.TransferTaskInfoFromBlob(row.Data, row.DataEncoding)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks[i] = info
+	}
+	resp.NextPageToken = getTaskDLQNextPageToken(rows, filter.PageSize)
+	return resp, nil
+}
+
+func (m *sqlExecutionManager) RangeDeleteTransferTaskFromDLQ(
+	request *p.RangeDeleteTransferTaskFromDLQRequest,
+) error {
+
+	if _, err := m.db.RangeDeleteFromTransferTasksDLQ(&sqlplugin.TaskDLQFilter{
+		ShardID:   m.shardID,
+		MinTaskID: request.ExclusiveBeginTaskID,
+		MaxTaskID: request.InclusiveEndTaskID,
+	}); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("RangeDeleteTransferTaskFromDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
 func (m *sqlExecutionManager) GetReplicationTasks(
 	request *p.GetReplicationTasksRequest,
 ) (*p.GetReplicationTasksResponse, error) {
@@ -1033,6 +1163,99 @@ func (m *sqlExecutionManager) RangeCompleteTimerTask(
 	return nil
 }
 
+func (m *sqlExecutionManager) PutTimerTaskToDLQ(
+	request *p.PutTimerTaskToDLQRequest,
+) error {
+
+	blob, err := serialization.TimerTaskInfoToBlob(request.TaskInfo)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.InsertIntoTimerTasksDLQ(&sqlplugin.TaskDLQRow{
+		ShardID:      m.shardID,
+		TaskID:       request.TaskInfo.GetTaskID(),
+		Data:         blob.Data,
+		DataEncoding: string(blob.Encoding),
+	})
+	// Tasks are immutable. So it's fine if we already persisted it before.
+	if err != nil && !m.db.IsDupEntryError(err) {
+		return serviceerror.NewInternal(fmt.Sprintf("PutTimerTaskToDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
+func (m *sqlExecutionManager) GetTimerTasksFromDLQ(
+	request *p.GetTimerTasksFromDLQRequest,
+) (*p.GetTimerTasksFromDLQResponse, error) {
+
+	filter, err := m.getTaskDLQFilter(request.ReadLevel, request.MaxReadLevel, request.BatchSize, request.NextPageToken)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.db.SelectFromTimerTasksDLQ(filter)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("GetTimerTasksFromDLQ operation failed. Select failed. Error: %v", err))
+	}
+
+	resp := &p.GetTimerTasksFromDLQResponse{Tasks: make([]*persistenceblobs.TimerTaskInfo, len(rows))}
+	for i, row := range rows {
+		info, err := serialization.TimerTaskInfoFromBlob(row.Data, row.DataEncoding)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks[i] = info
+	}
+	resp.NextPageToken = getTaskDLQNextPageToken(rows, filter.PageSize)
+	return resp, nil
+}
+
+func (m *sqlExecutionManager) RangeDeleteTimerTaskFromDLQ(
+	request *p.RangeDeleteTimerTaskFromDLQRequest,
+) error {
+
+	if _, err := m.db.RangeDeleteFromTimerTasksDLQ(&sqlplugin.TaskDLQFilter{
+		ShardID:   m.shardID,
+		MinTaskID: request.ExclusiveBeginTaskID,
+		MaxTaskID: request.InclusiveEndTaskID,
+	}); err != nil {
+		return serviceerror.NewInternal(fmt.Sprintf("RangeDeleteTimerTaskFromDLQ operation failed. Error: %v", err))
+	}
+	return nil
+}
+
+func (m *sqlExecutionManager) getTaskDLQFilter(
+	readLevel int64,
+	maxReadLevel int64,
+	batchSize int,
+	nextPageToken []byte,
+) (*sqlplugin.TaskDLQFilter, error) {
+
+	if len(nextPageToken) > 0 {
+		var err error
+		if readLevel, err = deserializePageToken(nextPageToken); err != nil {
+			return nil, err
+		}
+	}
+	return &sqlplugin.TaskDLQFilter{
+		ShardID:   m.shardID,
+		MinTaskID: readLevel,
+		MaxTaskID: maxReadLevel,
+		PageSize:  batchSize,
+	}, nil
+}
+
+func getTaskDLQNextPageToken(
+	rows []sqlplugin.TaskDLQRow,
+	pageSize int,
+) []byte {
+
+	if len(rows) == 0 || len(rows) < pageSize {
+		return nil
+	}
+	return serializePageToken(rows[len(rows)-1].TaskID)
+}
+
 func (m *sqlExecutionManager) PutReplicationTaskToDLQ(request *p.PutReplicationTaskToDLQRequest) error {
 	replicationTask := request.TaskInfo
 	blob, err := serialization.ReplicationTaskInfoToBlob(replicationTask)
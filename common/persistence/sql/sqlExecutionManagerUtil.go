@@ -707,6 +707,12 @@ func createTransferTasks(
 			info.TargetWorkflowID = task.(*p.StartChildExecutionTask).TargetWorkflowID
 			info.ScheduleID = task.(*p.StartChildExecutionTask).InitiatedID
 
+		case p.TransferTaskTypeCompletionCallback:
+			info.ScheduleID = task.(*p.CompletionCallbackTask).CallbackID
+
+		case p.TransferTaskTypeHistoryStream:
+			info.ScheduleID = task.(*p.HistoryStreamTask).LastEventID
+
 		case p.TransferTaskTypeCloseExecution,
 			p.TransferTaskTypeRecordWorkflowStarted,
 			p.TransferTaskTypeResetWorkflow,
@@ -22,7 +22,9 @@ package sql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/gogo/protobuf/types"
 	"go.temporal.io/temporal-proto/serviceerror"
@@ -36,10 +38,26 @@ import (
 	"github.com/temporalio/temporal/common/primitives"
 )
 
+const moveHistoryNodesPageSize = 1000
+
 type sqlHistoryV2Manager struct {
 	sqlStore
 }
 
+type historyTreePageToken struct {
+	ShardID  int
+	TreeID   primitives.UUID
+	BranchID primitives.UUID
+}
+
+func (t *historyTreePageToken) serialize() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *historyTreePageToken) deserialize(payload []byte) error {
+	return json.Unmarshal(payload, t)
+}
+
 // newHistoryV2Persistence creates an instance of HistoryManager
 func newHistoryV2Persistence(
 	db sqlplugin.DB,
@@ -422,9 +440,57 @@ func (m *sqlHistoryV2Manager) GetAllHistoryTreeBranches(
 	request *p.GetAllHistoryTreeBranchesRequest,
 ) (*p.GetAllHistoryTreeBranchesResponse, error) {
 
-	// TODO https://github.com/uber/cadence/issues/2458
-	// Implement it when we need
-	panic("not implemented yet")
+	// the ids must not be nil, nil is NULL which doesn't compare to any id
+	pageToken := &historyTreePageToken{TreeID: primitives.UUID{}, BranchID: primitives.UUID{}}
+	maxShardID := math.MaxInt32
+	if request.ShardID != nil {
+		pageToken.ShardID = *request.ShardID
+		maxShardID = *request.ShardID
+	}
+	if len(request.NextPageToken) > 0 {
+		if err := pageToken.deserialize(request.NextPageToken); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("error deserializing historyTreePageToken: %v", err))
+		}
+	}
+
+	rows, err := m.db.RangeSelectFromHistoryTree(&sqlplugin.HistoryTreeFilter{
+		ShardID:    pageToken.ShardID,
+		TreeID:     pageToken.TreeID,
+		BranchID:   &pageToken.BranchID,
+		MaxShardID: common.IntPtr(maxShardID),
+		PageSize:   common.IntPtr(request.PageSize),
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches operation failed. Select failed. Error: %v", err))
+	}
+
+	resp := &p.GetAllHistoryTreeBranchesResponse{
+		Branches: make([]p.HistoryBranchDetail, 0, len(rows)),
+	}
+	for _, row := range rows {
+		treeInfo, err := serialization.HistoryTreeInfoFromBlob(row.Data, row.DataEncoding)
+		if err != nil {
+			return nil, err
+		}
+		forkTime, err := types.TimestampFromProto(treeInfo.ForkTime)
+		if err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches operation failed. Invalid fork time. Error: %v", err))
+		}
+		resp.Branches = append(resp.Branches, p.HistoryBranchDetail{
+			TreeID:   row.TreeID.String(),
+			BranchID: row.BranchID.String(),
+			ForkTime: forkTime,
+			Info:     treeInfo.Info,
+		})
+	}
+	if len(rows) == request.PageSize {
+		lastRow := rows[len(rows)-1]
+		pageToken = &historyTreePageToken{ShardID: lastRow.ShardID, TreeID: lastRow.TreeID, BranchID: lastRow.BranchID}
+		if resp.NextPageToken, err = pageToken.serialize(); err != nil {
+			return nil, serviceerror.NewInternal(fmt.Sprintf("GetAllHistoryTreeBranches operation failed. Failed to serialize page token: %v", err))
+		}
+	}
+	return resp, nil
 }
 
 // GetHistoryTree returns all branch information of a tree
@@ -455,3 +521,110 @@ func (m *sqlHistoryV2Manager) GetHistoryTree(
 		Branches: branches,
 	}, nil
 }
+
+// MoveHistoryTree moves all branches and nodes of a tree to another shard
+func (m *sqlHistoryV2Manager) MoveHistoryTree(
+	request *p.MoveHistoryTreeRequest,
+) error {
+
+	treeID := request.TreeID
+	return m.txExecute("MoveHistoryTree", func(tx sqlplugin.Tx) error {
+		treeRows, err := tx.SelectFromHistoryTree(&sqlplugin.HistoryTreeFilter{
+			TreeID:  treeID,
+			ShardID: request.ShardID,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		// nodes of a forked branch are stored under the branches of its ancestors,
+		// which may be deleted already while their nodes are still referenced
+		branchIDs := make(map[string]primitives.UUID)
+		for _, row := range treeRows {
+			treeInfo, err := serialization.HistoryTreeInfoFromBlob(row.Data, row.DataEncoding)
+			if err != nil {
+				return err
+			}
+			branchIDs[row.BranchID.String()] = row.BranchID
+			for _, ancestor := range treeInfo.BranchInfo.Ancestors {
+				branchIDs[primitives.UUIDString(ancestor.BranchID)] = ancestor.BranchID
+			}
+
+			row.ShardID = request.TargetShardID
+			row.TreeID = treeID
+			if _, err := tx.InsertIntoHistoryTree(&row); err != nil {
+				return err
+			}
+			if _, err := tx.DeleteFromHistoryTree(&sqlplugin.HistoryTreeFilter{
+				TreeID:   treeID,
+				BranchID: primitives.UUIDPtr(row.BranchID),
+				ShardID:  request.ShardID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, branchID := range branchIDs {
+			if err := m.moveHistoryNodes(tx, treeID, branchID, request.ShardID, request.TargetShardID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *sqlHistoryV2Manager) moveHistoryNodes(
+	tx sqlplugin.Tx,
+	treeID primitives.UUID,
+	branchID primitives.UUID,
+	shardID int,
+	targetShardID int,
+) error {
+
+	minNodeID := common.FirstEventID
+	maxNodeID := int64(math.MaxInt64)
+	pageSize := moveHistoryNodesPageSize
+	for {
+		rows, err := tx.SelectFromHistoryNode(&sqlplugin.HistoryNodeFilter{
+			TreeID:    treeID,
+			BranchID:  branchID,
+			ShardID:   shardID,
+			MinNodeID: &minNodeID,
+			MaxNodeID: &maxNodeID,
+			PageSize:  &pageSize,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		// all transactions of a node must be read in the same page, so a full page is cut
+		// before its last node unless the page contains a single node
+		lastNodeID := rows[len(rows)-1].NodeID
+		if len(rows) == pageSize && rows[0].NodeID != lastNodeID {
+			for rows[len(rows)-1].NodeID == lastNodeID {
+				rows = rows[:len(rows)-1]
+			}
+		}
+
+		for _, row := range rows {
+			row.ShardID = targetShardID
+			row.TreeID = treeID
+			row.BranchID = branchID
+			if _, err := tx.InsertIntoHistoryNode(&row); err != nil {
+				return err
+			}
+		}
+		minNodeID = rows[len(rows)-1].NodeID + 1
+	}
+
+	_, err := tx.DeleteFromHistoryNode(&sqlplugin.HistoryNodeFilter{
+		TreeID:    treeID,
+		BranchID:  branchID,
+		ShardID:   shardID,
+		MinNodeID: common.Int64Ptr(common.FirstEventID),
+	})
+	return err
+}
@@ -230,6 +230,9 @@ func (m *sqlMetadataManagerV2) domainRowToGetDomainResponse(row *sqlplugin.Domai
 		ConfigVersion:               domainInfo.GetConfigVersion(),
 		NotificationVersion:         domainInfo.GetNotificationVersion(),
 		FailoverNotificationVersion: domainInfo.GetFailoverNotificationVersion(),
+		HandoverClusterName:         domainInfo.GetHandoverClusterName(),
+		FailoverEndTime:             domainInfo.GetFailoverEndTime(),
+		MigrationClusterName:        domainInfo.GetMigrationClusterName(),
 	}, nil
 }
 
@@ -264,6 +267,9 @@ func (m *sqlMetadataManagerV2) UpdateDomain(request *persistence.InternalUpdateD
 		FailoverVersion:             request.FailoverVersion,
 		NotificationVersion:         request.NotificationVersion,
 		FailoverNotificationVersion: request.FailoverNotificationVersion,
+		HandoverClusterName:         request.HandoverClusterName,
+		FailoverEndTime:             request.FailoverEndTime,
+		MigrationClusterName:        request.MigrationClusterName,
 		BadBinaries:                 badBinaries,
 		BadBinariesEncoding:         badBinariesEncoding,
 	}
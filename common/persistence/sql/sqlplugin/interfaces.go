@@ -44,6 +44,13 @@ type (
 		ImmutableDataEncoding string
 	}
 
+	// ClusterMetadataShardRoutingRow represents the history shard routing columns in the cluster_metadata table
+	ClusterMetadataShardRoutingRow struct {
+		ShardRoutingData         []byte
+		ShardRoutingDataEncoding string
+		ShardRoutingVersion      int64
+	}
+
 	// ClusterMembershipRow represents a row in the cluster_membership table
 	ClusterMembershipRow struct {
 		Role           persistence.ServiceType
@@ -129,6 +136,8 @@ type (
 		TaskID    *int64
 		MinTaskID *int64
 		MaxTaskID *int64
+		// PageSize limits the number of selected rows, 0 means no limit
+		PageSize int
 	}
 
 	// ExecutionsRow represents a row in executions table
@@ -154,6 +163,7 @@ type (
 		DomainID   primitives.UUID
 		WorkflowID string
 		RunID      primitives.UUID
+		PageSize   *int
 	}
 
 	// CurrentExecutionsRow represents a row in current_executions table
@@ -281,6 +291,23 @@ type (
 		SourceClusterName string
 	}
 
+	// TaskDLQRow represents a row in transfer_tasks_dlq or timer_tasks_dlq table
+	TaskDLQRow struct {
+		ShardID      int
+		TaskID       int64
+		Data         []byte
+		DataEncoding string
+	}
+
+	// TaskDLQFilter contains the column names within transfer_tasks_dlq or timer_tasks_dlq table
+	// that can be used to filter results through a WHERE clause
+	TaskDLQFilter struct {
+		ShardID   int
+		MinTaskID int64
+		MaxTaskID int64
+		PageSize  int
+	}
+
 	// TimerTasksRow represents a row in timer_tasks table
 	TimerTasksRow struct {
 		ShardID             int
@@ -365,6 +392,9 @@ type (
 		ShardID  int
 		TreeID   primitives.UUID
 		BranchID *primitives.UUID
+		// Inclusive
+		MaxShardID *int
+		PageSize   *int
 	}
 
 	// ActivityInfoMapsRow represents a row in activity_info_maps table
@@ -535,10 +565,23 @@ type (
 		Data      []byte
 	}
 
+	// SchemaUpdateHistoryRow represents a row in schema_update_history table
+	SchemaUpdateHistoryRow struct {
+		UpdateTime  time.Time
+		OldVersion  string
+		NewVersion  string
+		ManifestMD5 string `db:"manifest_md5"`
+		Description string
+	}
+
 	// tableCRUD defines the API for interacting with the database tables
 	tableCRUD interface {
 		InsertIfNotExistsIntoClusterMetadata(row *ClusterMetadataRow) (sql.Result, error)
 		GetClusterMetadata() (*ClusterMetadataRow, error)
+		GetClusterMetadataShardRouting() (*ClusterMetadataShardRoutingRow, error)
+		// UpdateClusterMetadataShardRouting updates the routing only if its version is still previousVersion,
+		// the version of the row is expected to be previousVersion+1
+		UpdateClusterMetadataShardRouting(row *ClusterMetadataShardRoutingRow, previousVersion int64) (sql.Result, error)
 		GetClusterMembers(filter *ClusterMembershipFilter) ([]ClusterMembershipRow, error)
 		UpsertClusterMembership(row *ClusterMembershipRow) (sql.Result, error)
 		PruneClusterMembership(filter *PruneClusterMembershipFilter) (sql.Result, error)
@@ -592,11 +635,17 @@ type (
 		DeleteFromHistoryNode(filter *HistoryNodeFilter) (sql.Result, error)
 		InsertIntoHistoryTree(row *HistoryTreeRow) (sql.Result, error)
 		SelectFromHistoryTree(filter *HistoryTreeFilter) ([]HistoryTreeRow, error)
+		// RangeSelectFromHistoryTree returns the rows after the shard_id, tree_id and branch_id of the filter
+		// up to MaxShardID in ascending order, at most PageSize rows are returned
+		RangeSelectFromHistoryTree(filter *HistoryTreeFilter) ([]HistoryTreeRow, error)
 		DeleteFromHistoryTree(filter *HistoryTreeFilter) (sql.Result, error)
 
 		InsertIntoExecutions(row *ExecutionsRow) (sql.Result, error)
 		UpdateExecutions(row *ExecutionsRow) (sql.Result, error)
 		SelectFromExecutions(filter *ExecutionsFilter) (*ExecutionsRow, error)
+		// RangeSelectFromExecutions returns the ids of the rows of a shard after the domain_id, workflow_id and run_id
+		// of the filter in ascending order, at most PageSize rows are returned
+		RangeSelectFromExecutions(filter *ExecutionsFilter) ([]ExecutionsRow, error)
 		DeleteFromExecutions(filter *ExecutionsFilter) (sql.Result, error)
 		ReadLockExecutions(filter *ExecutionsFilter) (int, error)
 		WriteLockExecutions(filter *ExecutionsFilter) (int, error)
@@ -660,6 +709,22 @@ type (
 		// RangeDeleteMessageFromReplicationTasksDLQ deletes one or more rows from replication_tasks_dlq table
 		// Required filter params - {sourceClusterName, shardID, taskID, inclusiveTaskID}
 		RangeDeleteMessageFromReplicationTasksDLQ(filter *ReplicationTasksDLQFilter) (sql.Result, error)
+		// InsertIntoTransferTasksDLQ puts the transfer task into DLQ
+		InsertIntoTransferTasksDLQ(row *TaskDLQRow) (sql.Result, error)
+		// SelectFromTransferTasksDLQ returns one or more rows from transfer_tasks_dlq table
+		// Required filter params - {shardID, minTaskID, maxTaskID, pageSize}
+		SelectFromTransferTasksDLQ(filter *TaskDLQFilter) ([]TaskDLQRow, error)
+		// RangeDeleteFromTransferTasksDLQ deletes one or more rows from transfer_tasks_dlq table
+		// Required filter params - {shardID, minTaskID, maxTaskID}
+		RangeDeleteFromTransferTasksDLQ(filter *TaskDLQFilter) (sql.Result, error)
+		// InsertIntoTimerTasksDLQ puts the timer task into DLQ
+		InsertIntoTimerTasksDLQ(row *TaskDLQRow) (sql.Result, error)
+		// SelectFromTimerTasksDLQ returns one or more rows from timer_tasks_dlq table
+		// Required filter params - {shardID, minTaskID, maxTaskID, pageSize}
+		SelectFromTimerTasksDLQ(filter *TaskDLQFilter) ([]TaskDLQRow, error)
+		// RangeDeleteFromTimerTasksDLQ deletes one or more rows from timer_tasks_dlq table
+		// Required filter params - {shardID, minTaskID, maxTaskID}
+		RangeDeleteFromTimerTasksDLQ(filter *TaskDLQFilter) (sql.Result, error)
 
 		ReplaceIntoActivityInfoMaps(rows []ActivityInfoMapsRow) (sql.Result, error)
 		// SelectFromActivityInfoMaps returns one or more rows from activity_info_maps
@@ -755,6 +820,7 @@ type (
 		ReadSchemaVersion(database string) (string, error)
 		UpdateSchemaVersion(database string, newVersion string, minCompatibleVersion string) error
 		WriteSchemaUpdateLog(oldVersion string, newVersion string, manifestMD5 string, desc string) error
+		ReadSchemaUpdateLog() ([]SchemaUpdateHistoryRow, error)
 		ListTables(database string) ([]string, error)
 		DropTable(table string) error
 		DropAllTables(database string) error
@@ -23,6 +23,8 @@ package mysql
 import (
 	"fmt"
 	"time"
+
+	"github.com/temporalio/temporal/common/persistence/sql/sqlplugin"
 )
 
 const (
@@ -32,6 +34,8 @@ const (
 
 	writeSchemaUpdateHistoryQuery = `INSERT into schema_update_history(year, month, update_time, old_version, new_version, manifest_md5, description) VALUES(?,?,?,?,?,?,?)`
 
+	readSchemaUpdateHistoryQuery = `SELECT update_time, old_version, new_version, manifest_md5, description FROM schema_update_history`
+
 	createSchemaVersionTableQuery = `CREATE TABLE schema_version(db_name VARCHAR(255) not null PRIMARY KEY, ` +
 		`creation_time DATETIME(6), ` +
 		`curr_version VARCHAR(64), ` +
@@ -83,6 +87,13 @@ func (mdb *db) WriteSchemaUpdateLog(oldVersion string, newVersion string, manife
 	return mdb.Exec(writeSchemaUpdateHistoryQuery, now.Year(), int(now.Month()), now, oldVersion, newVersion, manifestMD5, desc)
 }
 
+// ReadSchemaUpdateLog returns all entries of the schema update history table
+func (mdb *db) ReadSchemaUpdateLog() ([]sqlplugin.SchemaUpdateHistoryRow, error) {
+	var rows []sqlplugin.SchemaUpdateHistoryRow
+	err := mdb.db.Select(&rows, readSchemaUpdateHistoryQuery)
+	return rows, err
+}
+
 // Exec executes a sql statement
 func (mdb *db) Exec(stmt string, args ...interface{}) error {
 	_, err := mdb.db.Exec(stmt, args...)
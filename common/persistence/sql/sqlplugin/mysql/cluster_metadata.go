@@ -40,6 +40,13 @@ VALUES(?, ?, ?)`
 	getImmutableClusterMetadataQry = `SELECT immutable_data, immutable_data_encoding FROM 
 cluster_metadata WHERE metadata_partition = ?`
 
+	getClusterMetadataShardRoutingQry = `SELECT shard_routing_data, COALESCE(shard_routing_data_encoding, '') AS shard_routing_data_encoding, shard_routing_version FROM 
+cluster_metadata WHERE metadata_partition = ?`
+
+	updateClusterMetadataShardRoutingQry = `UPDATE cluster_metadata 
+SET shard_routing_data = ?, shard_routing_data_encoding = ?, shard_routing_version = ? 
+WHERE metadata_partition = ? AND shard_routing_version = ?`
+
 	// ****** CLUSTER_MEMBERSHIP TABLE ******
 	templateUpsertActiveClusterMembership = `REPLACE INTO
 cluster_membership (host_id, rpc_address, rpc_port, role, session_start, last_heartbeat, record_expiry)
@@ -83,6 +90,24 @@ func (mdb *db) GetClusterMetadata() (*sqlplugin.ClusterMetadataRow, error) {
 	return &row, err
 }
 
+func (mdb *db) GetClusterMetadataShardRouting() (*sqlplugin.ClusterMetadataShardRoutingRow, error) {
+	var row sqlplugin.ClusterMetadataShardRoutingRow
+	err := mdb.conn.Get(&row, getClusterMetadataShardRoutingQry, constMetadataPartition)
+	if err != nil {
+		return nil, err
+	}
+	return &row, err
+}
+
+func (mdb *db) UpdateClusterMetadataShardRouting(row *sqlplugin.ClusterMetadataShardRoutingRow, previousVersion int64) (sql.Result, error) {
+	return mdb.conn.Exec(updateClusterMetadataShardRoutingQry,
+		row.ShardRoutingData,
+		row.ShardRoutingDataEncoding,
+		row.ShardRoutingVersion,
+		constMetadataPartition,
+		previousVersion)
+}
+
 func (mdb *db) UpsertClusterMembership(row *sqlplugin.ClusterMembershipRow) (sql.Result, error) {
 	return mdb.conn.Exec(templateUpsertActiveClusterMembership,
 		row.HostID,
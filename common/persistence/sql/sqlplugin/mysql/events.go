@@ -44,6 +44,10 @@ const (
 
 	getHistoryTreeQuery = `SELECT branch_id, data, data_encoding FROM history_tree WHERE shard_id = ? AND tree_id = ? `
 
+	rangeGetHistoryTreeQuery = `SELECT shard_id, tree_id, branch_id, data, data_encoding FROM history_tree ` +
+		`WHERE (shard_id, tree_id, branch_id) > (?, ?, ?) AND shard_id <= ? ` +
+		`ORDER BY shard_id, tree_id, branch_id LIMIT ?`
+
 	deleteHistoryTreeQuery = `DELETE FROM history_tree WHERE shard_id = ? AND tree_id = ? AND branch_id = ? `
 )
 
@@ -87,6 +91,17 @@ func (mdb *db) SelectFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) ([]sql
 	return rows, err
 }
 
+// RangeSelectFromHistoryTree reads one or more rows from history_tree table
+func (mdb *db) RangeSelectFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) ([]sqlplugin.HistoryTreeRow, error) {
+	var rows []sqlplugin.HistoryTreeRow
+	err := mdb.conn.Select(&rows, rangeGetHistoryTreeQuery,
+		filter.ShardID, filter.TreeID, *filter.BranchID, *filter.MaxShardID, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // DeleteFromHistoryTree deletes one or more rows from history_tree table
 func (mdb *db) DeleteFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) (sql.Result, error) {
 	return mdb.conn.Exec(deleteHistoryTreeQuery, filter.ShardID, filter.TreeID, *filter.BranchID)
@@ -39,6 +39,9 @@ const (
 	getExecutionQuery = `SELECT ` + executionsColumns + ` FROM executions
  WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
 
+	rangeGetExecutionsQuery = `SELECT shard_id, domain_id, workflow_id, run_id FROM executions
+ WHERE shard_id = ? AND (domain_id, workflow_id, run_id) > (?, ?, ?) ORDER BY domain_id, workflow_id, run_id LIMIT ?`
+
 	deleteExecutionQuery = `DELETE FROM executions 
  WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
 
@@ -82,6 +85,9 @@ workflow_id = :workflow_id
 	getTransferTasksQuery = `SELECT task_id, data, data_encoding 
  FROM transfer_tasks WHERE shard_id = ? AND task_id > ? AND task_id <= ? ORDER BY shard_id, task_id`
 
+	getTransferTasksPageQuery = `SELECT task_id, data, data_encoding 
+ FROM transfer_tasks WHERE shard_id = ? AND task_id > ? AND task_id <= ? ORDER BY shard_id, task_id LIMIT ?`
+
 	createTransferTasksQuery = `INSERT INTO transfer_tasks(shard_id, task_id, data, data_encoding) 
  VALUES(:shard_id, :task_id, :data, :data_encoding)`
 
@@ -152,6 +158,28 @@ VALUES     (:source_cluster_name,
 		AND shard_id = ? 
 		AND task_id > ?
 		AND task_id <= ?`
+
+	insertTransferTaskDLQQuery = `INSERT INTO transfer_tasks_dlq (shard_id, task_id, data, data_encoding)
+VALUES (:shard_id, :task_id, :data, :data_encoding)`
+
+	getTransferTasksDLQQuery = `SELECT task_id, data, data_encoding FROM transfer_tasks_dlq WHERE
+shard_id = ? AND
+task_id > ? AND
+task_id <= ?
+ORDER BY task_id LIMIT ?`
+
+	rangeDeleteTransferTaskFromDLQQuery = `DELETE FROM transfer_tasks_dlq WHERE shard_id = ? AND task_id > ? AND task_id <= ?`
+
+	insertTimerTaskDLQQuery = `INSERT INTO timer_tasks_dlq (shard_id, task_id, data, data_encoding)
+VALUES (:shard_id, :task_id, :data, :data_encoding)`
+
+	getTimerTasksDLQQuery = `SELECT task_id, data, data_encoding FROM timer_tasks_dlq WHERE
+shard_id = ? AND
+task_id > ? AND
+task_id <= ?
+ORDER BY task_id LIMIT ?`
+
+	rangeDeleteTimerTaskFromDLQQuery = `DELETE FROM timer_tasks_dlq WHERE shard_id = ? AND task_id > ? AND task_id <= ?`
 )
 
 // InsertIntoExecutions inserts a row into executions table
@@ -174,6 +202,17 @@ func (mdb *db) SelectFromExecutions(filter *sqlplugin.ExecutionsFilter) (*sqlplu
 	return &row, err
 }
 
+// RangeSelectFromExecutions reads one or more rows from executions table
+func (mdb *db) RangeSelectFromExecutions(filter *sqlplugin.ExecutionsFilter) ([]sqlplugin.ExecutionsRow, error) {
+	var rows []sqlplugin.ExecutionsRow
+	err := mdb.conn.Select(&rows, rangeGetExecutionsQuery,
+		filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // DeleteFromExecutions deletes a single row from executions table
 func (mdb *db) DeleteFromExecutions(filter *sqlplugin.ExecutionsFilter) (sql.Result, error) {
 	return mdb.conn.Exec(deleteExecutionQuery, filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID)
@@ -238,7 +277,12 @@ func (mdb *db) InsertIntoTransferTasks(rows []sqlplugin.TransferTasksRow) (sql.R
 // SelectFromTransferTasks reads one or more rows from transfer_tasks table
 func (mdb *db) SelectFromTransferTasks(filter *sqlplugin.TransferTasksFilter) ([]sqlplugin.TransferTasksRow, error) {
 	var rows []sqlplugin.TransferTasksRow
-	err := mdb.conn.Select(&rows, getTransferTasksQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID)
+	var err error
+	if filter.PageSize > 0 {
+		err = mdb.conn.Select(&rows, getTransferTasksPageQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID, filter.PageSize)
+	} else {
+		err = mdb.conn.Select(&rows, getTransferTasksQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -377,3 +421,47 @@ func (mdb *db) RangeDeleteMessageFromReplicationTasksDLQ(
 		filter.InclusiveEndTaskID,
 	)
 }
+
+// InsertIntoTransferTasksDLQ inserts a row into transfer_tasks_dlq table
+func (mdb *db) InsertIntoTransferTasksDLQ(row *sqlplugin.TaskDLQRow) (sql.Result, error) {
+	return mdb.conn.NamedExec(insertTransferTaskDLQQuery, row)
+}
+
+// SelectFromTransferTasksDLQ reads one or more rows from transfer_tasks_dlq table
+func (mdb *db) SelectFromTransferTasksDLQ(filter *sqlplugin.TaskDLQFilter) ([]sqlplugin.TaskDLQRow, error) {
+	var rows []sqlplugin.TaskDLQRow
+	err := mdb.conn.Select(
+		&rows, getTransferTasksDLQQuery,
+		filter.ShardID,
+		filter.MinTaskID,
+		filter.MaxTaskID,
+		filter.PageSize)
+	return rows, err
+}
+
+// RangeDeleteFromTransferTasksDLQ deletes one or more rows from transfer_tasks_dlq table
+func (mdb *db) RangeDeleteFromTransferTasksDLQ(filter *sqlplugin.TaskDLQFilter) (sql.Result, error) {
+	return mdb.conn.Exec(rangeDeleteTransferTaskFromDLQQuery, filter.ShardID, filter.MinTaskID, filter.MaxTaskID)
+}
+
+// InsertIntoTimerTasksDLQ inserts a row into timer_tasks_dlq table
+func (mdb *db) InsertIntoTimerTasksDLQ(row *sqlplugin.TaskDLQRow) (sql.Result, error) {
+	return mdb.conn.NamedExec(insertTimerTaskDLQQuery, row)
+}
+
+// SelectFromTimerTasksDLQ reads one or more rows from timer_tasks_dlq table
+func (mdb *db) SelectFromTimerTasksDLQ(filter *sqlplugin.TaskDLQFilter) ([]sqlplugin.TaskDLQRow, error) {
+	var rows []sqlplugin.TaskDLQRow
+	err := mdb.conn.Select(
+		&rows, getTimerTasksDLQQuery,
+		filter.ShardID,
+		filter.MinTaskID,
+		filter.MaxTaskID,
+		filter.PageSize)
+	return rows, err
+}
+
+// RangeDeleteFromTimerTasksDLQ deletes one or more rows from timer_tasks_dlq table
+func (mdb *db) RangeDeleteFromTimerTasksDLQ(filter *sqlplugin.TaskDLQFilter) (sql.Result, error) {
+	return mdb.conn.Exec(rangeDeleteTimerTaskFromDLQQuery, filter.ShardID, filter.MinTaskID, filter.MaxTaskID)
+}
@@ -23,6 +23,8 @@ package postgres
 import (
 	"fmt"
 	"time"
+
+	"github.com/temporalio/temporal/common/persistence/sql/sqlplugin"
 )
 
 const (
@@ -36,6 +38,8 @@ const (
 
 	writeSchemaUpdateHistoryQuery = `INSERT into schema_update_history(year, month, update_time, old_version, new_version, manifest_md5, description) VALUES($1,$2,$3,$4,$5,$6,$7)`
 
+	readSchemaUpdateHistoryQuery = `SELECT update_time, old_version, new_version, manifest_md5, description FROM schema_update_history`
+
 	createSchemaVersionTableQuery = `CREATE TABLE schema_version(db_name VARCHAR(255) not null PRIMARY KEY, ` +
 		`creation_time TIMESTAMP, ` +
 		`curr_version VARCHAR(64), ` +
@@ -89,6 +93,13 @@ func (pdb *db) WriteSchemaUpdateLog(oldVersion string, newVersion string, manife
 	return pdb.Exec(writeSchemaUpdateHistoryQuery, now.Year(), int(now.Month()), now, oldVersion, newVersion, manifestMD5, desc)
 }
 
+// ReadSchemaUpdateLog returns all entries of the schema update history table
+func (pdb *db) ReadSchemaUpdateLog() ([]sqlplugin.SchemaUpdateHistoryRow, error) {
+	var rows []sqlplugin.SchemaUpdateHistoryRow
+	err := pdb.db.Select(&rows, readSchemaUpdateHistoryQuery)
+	return rows, err
+}
+
 // Exec executes a sql statement
 func (pdb *db) Exec(stmt string, args ...interface{}) error {
 	_, err := pdb.db.Exec(stmt, args...)
@@ -43,6 +43,13 @@ ON CONFLICT DO NOTHING`
 	getImmutableClusterMetadataQry = `SELECT immutable_data, immutable_data_encoding FROM 
 cluster_metadata WHERE metadata_partition = $1`
 
+	getClusterMetadataShardRoutingQry = `SELECT shard_routing_data, COALESCE(shard_routing_data_encoding, '') AS shard_routing_data_encoding, shard_routing_version FROM 
+cluster_metadata WHERE metadata_partition = $1`
+
+	updateClusterMetadataShardRoutingQry = `UPDATE cluster_metadata 
+SET shard_routing_data = $1, shard_routing_data_encoding = $2, shard_routing_version = $3 
+WHERE metadata_partition = $4 AND shard_routing_version = $5`
+
 	// ****** CLUSTER_MEMBERSHIP TABLE ******
 	templateUpsertActiveClusterMembership = `INSERT INTO
 cluster_membership (host_id, rpc_address, rpc_port, role, session_start, last_heartbeat, record_expiry)
@@ -90,6 +97,24 @@ func (pdb *db) GetClusterMetadata() (*sqlplugin.ClusterMetadataRow, error) {
 	return &row, err
 }
 
+func (pdb *db) GetClusterMetadataShardRouting() (*sqlplugin.ClusterMetadataShardRoutingRow, error) {
+	var row sqlplugin.ClusterMetadataShardRoutingRow
+	err := pdb.conn.Get(&row, getClusterMetadataShardRoutingQry, constMetadataPartition)
+	if err != nil {
+		return nil, err
+	}
+	return &row, err
+}
+
+func (pdb *db) UpdateClusterMetadataShardRouting(row *sqlplugin.ClusterMetadataShardRoutingRow, previousVersion int64) (sql.Result, error) {
+	return pdb.conn.Exec(updateClusterMetadataShardRoutingQry,
+		row.ShardRoutingData,
+		row.ShardRoutingDataEncoding,
+		row.ShardRoutingVersion,
+		constMetadataPartition,
+		previousVersion)
+}
+
 func (pdb *db) UpsertClusterMembership(row *sqlplugin.ClusterMembershipRow) (sql.Result, error) {
 	return pdb.conn.Exec(templateUpsertActiveClusterMembership,
 		row.HostID,
@@ -44,6 +44,10 @@ const (
 
 	getHistoryTreeQuery = `SELECT branch_id, data, data_encoding FROM history_tree WHERE shard_id = $1 AND tree_id = $2 `
 
+	rangeGetHistoryTreeQuery = `SELECT shard_id, tree_id, branch_id, data, data_encoding FROM history_tree ` +
+		`WHERE (shard_id, tree_id, branch_id) > ($1, $2, $3) AND shard_id <= $4 ` +
+		`ORDER BY shard_id, tree_id, branch_id LIMIT $5`
+
 	deleteHistoryTreeQuery = `DELETE FROM history_tree WHERE shard_id = $1 AND tree_id = $2 AND branch_id = $3 `
 )
 
@@ -87,6 +91,17 @@ func (pdb *db) SelectFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) ([]sql
 	return rows, err
 }
 
+// RangeSelectFromHistoryTree reads one or more rows from history_tree table
+func (pdb *db) RangeSelectFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) ([]sqlplugin.HistoryTreeRow, error) {
+	var rows []sqlplugin.HistoryTreeRow
+	err := pdb.conn.Select(&rows, rangeGetHistoryTreeQuery,
+		filter.ShardID, filter.TreeID, *filter.BranchID, *filter.MaxShardID, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // DeleteFromHistoryTree deletes one or more rows from history_tree table
 func (pdb *db) DeleteFromHistoryTree(filter *sqlplugin.HistoryTreeFilter) (sql.Result, error) {
 	return pdb.conn.Exec(deleteHistoryTreeQuery, filter.ShardID, filter.TreeID, *filter.BranchID)
@@ -39,6 +39,9 @@ const (
 	getExecutionQuery = `SELECT ` + executionsColumns + ` FROM executions
  WHERE shard_id = $1 AND domain_id = $2 AND workflow_id = $3 AND run_id = $4`
 
+	rangeGetExecutionsQuery = `SELECT shard_id, domain_id, workflow_id, run_id FROM executions
+ WHERE shard_id = $1 AND (domain_id, workflow_id, run_id) > ($2, $3, $4) ORDER BY domain_id, workflow_id, run_id LIMIT $5`
+
 	deleteExecutionQuery = `DELETE FROM executions 
  WHERE shard_id = $1 AND domain_id = $2 AND workflow_id = $3 AND run_id = $4`
 
@@ -82,6 +85,9 @@ workflow_id = :workflow_id
 	getTransferTasksQuery = `SELECT task_id, data, data_encoding 
  FROM transfer_tasks WHERE shard_id = $1 AND task_id > $2 AND task_id <= $3 ORDER BY shard_id, task_id`
 
+	getTransferTasksPageQuery = `SELECT task_id, data, data_encoding 
+ FROM transfer_tasks WHERE shard_id = $1 AND task_id > $2 AND task_id <= $3 ORDER BY shard_id, task_id LIMIT $4`
+
 	createTransferTasksQuery = `INSERT INTO transfer_tasks(shard_id, task_id, data, data_encoding) 
  VALUES(:shard_id, :task_id, :data, :data_encoding)`
 
@@ -151,6 +157,28 @@ VALUES     (:source_cluster_name,
 		AND shard_id = $2 
 		AND task_id > $3
 		AND task_id <= $4`
+
+	insertTransferTaskDLQQuery = `INSERT INTO transfer_tasks_dlq (shard_id, task_id, data, data_encoding)
+VALUES (:shard_id, :task_id, :data, :data_encoding)`
+
+	getTransferTasksDLQQuery = `SELECT task_id, data, data_encoding FROM transfer_tasks_dlq WHERE
+shard_id = $1 AND
+task_id > $2 AND
+task_id <= $3
+ORDER BY task_id LIMIT $4`
+
+	rangeDeleteTransferTaskFromDLQQuery = `DELETE FROM transfer_tasks_dlq WHERE shard_id = $1 AND task_id > $2 AND task_id <= $3`
+
+	insertTimerTaskDLQQuery = `INSERT INTO timer_tasks_dlq (shard_id, task_id, data, data_encoding)
+VALUES (:shard_id, :task_id, :data, :data_encoding)`
+
+	getTimerTasksDLQQuery = `SELECT task_id, data, data_encoding FROM timer_tasks_dlq WHERE
+shard_id = $1 AND
+task_id > $2 AND
+task_id <= $3
+ORDER BY task_id LIMIT $4`
+
+	rangeDeleteTimerTaskFromDLQQuery = `DELETE FROM timer_tasks_dlq WHERE shard_id = $1 AND task_id > $2 AND task_id <= $3`
 )
 
 // InsertIntoExecutions inserts a row into executions table
@@ -173,6 +201,17 @@ func (pdb *db) SelectFromExecutions(filter *sqlplugin.ExecutionsFilter) (*sqlplu
 	return &row, err
 }
 
+// RangeSelectFromExecutions reads one or more rows from executions table
+func (pdb *db) RangeSelectFromExecutions(filter *sqlplugin.ExecutionsFilter) ([]sqlplugin.ExecutionsRow, error) {
+	var rows []sqlplugin.ExecutionsRow
+	err := pdb.conn.Select(&rows, rangeGetExecutionsQuery,
+		filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID, *filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // DeleteFromExecutions deletes a single row from executions table
 func (pdb *db) DeleteFromExecutions(filter *sqlplugin.ExecutionsFilter) (sql.Result, error) {
 	return pdb.conn.Exec(deleteExecutionQuery, filter.ShardID, filter.DomainID, filter.WorkflowID, filter.RunID)
@@ -237,7 +276,12 @@ func (pdb *db) InsertIntoTransferTasks(rows []sqlplugin.TransferTasksRow) (sql.R
 // SelectFromTransferTasks reads one or more rows from transfer_tasks table
 func (pdb *db) SelectFromTransferTasks(filter *sqlplugin.TransferTasksFilter) ([]sqlplugin.TransferTasksRow, error) {
 	var rows []sqlplugin.TransferTasksRow
-	err := pdb.conn.Select(&rows, getTransferTasksQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID)
+	var err error
+	if filter.PageSize > 0 {
+		err = pdb.conn.Select(&rows, getTransferTasksPageQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID, filter.PageSize)
+	} else {
+		err = pdb.conn.Select(&rows, getTransferTasksQuery, filter.ShardID, *filter.MinTaskID, *filter.MaxTaskID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -376,3 +420,47 @@ func (pdb *db) RangeDeleteMessageFromReplicationTasksDLQ(
 		filter.InclusiveEndTaskID,
 	)
 }
+
+// InsertIntoTransferTasksDLQ inserts a row into transfer_tasks_dlq table
+func (pdb *db) InsertIntoTransferTasksDLQ(row *sqlplugin.TaskDLQRow) (sql.Result, error) {
+	return pdb.conn.NamedExec(insertTransferTaskDLQQuery, row)
+}
+
+// SelectFromTransferTasksDLQ reads one or more rows from transfer_tasks_dlq table
+func (pdb *db) SelectFromTransferTasksDLQ(filter *sqlplugin.TaskDLQFilter) ([]sqlplugin.TaskDLQRow, error) {
+	var rows []sqlplugin.TaskDLQRow
+	err := pdb.conn.Select(
+		&rows, getTransferTasksDLQQuery,
+		filter.ShardID,
+		filter.MinTaskID,
+		filter.MaxTaskID,
+		filter.PageSize)
+	return rows, err
+}
+
+// RangeDeleteFromTransferTasksDLQ deletes one or more rows from transfer_tasks_dlq table
+func (pdb *db) RangeDeleteFromTransferTasksDLQ(filter *sqlplugin.TaskDLQFilter) (sql.Result, error) {
+	return pdb.conn.Exec(rangeDeleteTransferTaskFromDLQQuery, filter.ShardID, filter.MinTaskID, filter.MaxTaskID)
+}
+
+// InsertIntoTimerTasksDLQ inserts a row into timer_tasks_dlq table
+func (pdb *db) InsertIntoTimerTasksDLQ(row *sqlplugin.TaskDLQRow) (sql.Result, error) {
+	return pdb.conn.NamedExec(insertTimerTaskDLQQuery, row)
+}
+
+// SelectFromTimerTasksDLQ reads one or more rows from timer_tasks_dlq table
+func (pdb *db) SelectFromTimerTasksDLQ(filter *sqlplugin.TaskDLQFilter) ([]sqlplugin.TaskDLQRow, error) {
+	var rows []sqlplugin.TaskDLQRow
+	err := pdb.conn.Select(
+		&rows, getTimerTasksDLQQuery,
+		filter.ShardID,
+		filter.MinTaskID,
+		filter.MaxTaskID,
+		filter.PageSize)
+	return rows, err
+}
+
+// RangeDeleteFromTimerTasksDLQ deletes one or more rows from timer_tasks_dlq table
+func (pdb *db) RangeDeleteFromTimerTasksDLQ(filter *sqlplugin.TaskDLQFilter) (sql.Result, error) {
+	return pdb.conn.Exec(rangeDeleteTimerTaskFromDLQQuery, filter.ShardID, filter.MinTaskID, filter.MaxTaskID)
+}
@@ -60,8 +60,9 @@ func ValidateCreateWorkflowStateCloseStatus(
 		return err
 	}
 
-	// validate workflow state & close status
-	if state == WorkflowStateCompleted || closeStatus != WorkflowCloseStatusRunning {
+	// validate workflow state & close status, a workflow can be created as closed
+	// when it is copied as a whole, e.g. when it is rehydrated, imported or moved by a reshard
+	if (state == WorkflowStateCompleted) != (closeStatus != WorkflowCloseStatusRunning) {
 		return serviceerror.NewInternal(fmt.Sprintf("Create workflow with invalid state: %v or close status: %v", state, closeStatus))
 	}
 	return nil
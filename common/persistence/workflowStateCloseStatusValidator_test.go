@@ -89,7 +89,6 @@ func (s *workflowStateCloseStatusSuite) TestCreateWorkflowStateCloseStatus_Workf
 
 func (s *workflowStateCloseStatusSuite) TestCreateWorkflowStateCloseStatus_WorkflowStateCompleted() {
 	closeStatuses := []int{
-		WorkflowCloseStatusRunning,
 		WorkflowCloseStatusCompleted,
 		WorkflowCloseStatusFailed,
 		WorkflowCloseStatusCanceled,
@@ -98,8 +97,10 @@ func (s *workflowStateCloseStatusSuite) TestCreateWorkflowStateCloseStatus_Workf
 		WorkflowCloseStatusTimedOut,
 	}
 
+	s.NotNil(ValidateCreateWorkflowStateCloseStatus(WorkflowStateCompleted, WorkflowCloseStatusRunning))
+
 	for _, closeStatus := range closeStatuses {
-		s.NotNil(ValidateCreateWorkflowStateCloseStatus(WorkflowStateCompleted, enums.WorkflowExecutionCloseStatus(closeStatus)))
+		s.Nil(ValidateCreateWorkflowStateCloseStatus(WorkflowStateCompleted, enums.WorkflowExecutionCloseStatus(closeStatus)))
 	}
 }
 
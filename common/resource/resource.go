@@ -42,6 +42,7 @@ import (
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
 	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
@@ -56,6 +57,7 @@ type (
 		GetHostInfo() *membership.HostInfo
 		GetArchivalMetadata() archiver.ArchivalMetadata
 		GetClusterMetadata() cluster.Metadata
+		GetHistoryShardRouter() sharding.Router
 
 		// other common resources
 
@@ -51,6 +51,7 @@ import (
 	"github.com/temporalio/temporal/common/persistence"
 	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
@@ -68,12 +69,13 @@ type (
 
 		// static infos
 
-		numShards       int
-		serviceName     string
-		hostName        string
-		hostInfo        *membership.HostInfo
-		metricsScope    tally.Scope
-		clusterMetadata cluster.Metadata
+		numShards          int
+		historyShardRouter sharding.Router
+		serviceName        string
+		hostName           string
+		hostInfo           *membership.HostInfo
+		metricsScope       tally.Scope
+		clusterMetadata    cluster.Metadata
 
 		// other common resources
 
@@ -172,6 +174,12 @@ func New(
 		return nil, err
 	}
 
+	historyShardRouter := sharding.NewRouter(
+		persistenceBean.GetClusterMetadataManager(),
+		numShards,
+		logger,
+	)
+
 	dynamicCollection := dynamicconfig.NewCollection(params.DynamicConfig, logger)
 	clientBean, err := client.NewClientBean(
 		client.NewRPCClientFactory(
@@ -179,7 +187,7 @@ func New(
 			membershipMonitor,
 			params.MetricsClient,
 			dynamicCollection,
-			numShards,
+			historyShardRouter,
 			logger,
 		),
 		params.ClusterMetadata,
@@ -273,11 +281,12 @@ func New(
 
 		// static infos
 
-		numShards:       numShards,
-		serviceName:     params.Name,
-		hostName:        hostName,
-		metricsScope:    params.MetricScope,
-		clusterMetadata: params.ClusterMetadata,
+		numShards:          numShards,
+		historyShardRouter: historyShardRouter,
+		serviceName:        params.Name,
+		hostName:           hostName,
+		metricsScope:       params.MetricScope,
+		clusterMetadata:    params.ClusterMetadata,
 
 		// other common resources
 
@@ -352,6 +361,7 @@ func (h *Impl) Start() {
 
 	h.membershipMonitor.Start()
 	h.domainCache.Start()
+	h.historyShardRouter.Start()
 
 	hostInfo, err := h.membershipMonitor.WhoAmI()
 	if err != nil {
@@ -376,6 +386,7 @@ func (h *Impl) Stop() {
 		return
 	}
 
+	h.historyShardRouter.Stop()
 	h.domainCache.Stop()
 	h.membershipMonitor.Stop()
 	h.ringpopChannel.Close()
@@ -407,6 +418,11 @@ func (h *Impl) GetClusterMetadata() cluster.Metadata {
 	return h.clusterMetadata
 }
 
+// GetHistoryShardRouter return history shard router
+func (h *Impl) GetHistoryShardRouter() sharding.Router {
+	return h.historyShardRouter
+}
+
 // other common resources
 
 // GetDomainCache return domain cache
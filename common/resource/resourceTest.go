@@ -53,13 +53,15 @@ import (
 	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
 	// Test is the test implementation used for testing
 	Test struct {
-		MetricsScope    tally.Scope
-		ClusterMetadata *cluster.MockMetadata
+		MetricsScope       tally.Scope
+		ClusterMetadata    *cluster.MockMetadata
+		HistoryShardRouter sharding.Router
 
 		// other common resources
 
@@ -168,8 +170,9 @@ func NewTest(
 	scope := tally.NewTestScope("test", nil)
 
 	return &Test{
-		MetricsScope:    scope,
-		ClusterMetadata: cluster.NewMockMetadata(controller),
+		MetricsScope:       scope,
+		ClusterMetadata:    cluster.NewMockMetadata(controller),
+		HistoryShardRouter: sharding.NewStaticRouter(1),
 
 		// other common resources
 
@@ -247,6 +250,11 @@ func (s *Test) GetClusterMetadata() cluster.Metadata {
 	return s.ClusterMetadata
 }
 
+// GetHistoryShardRouter for testing
+func (s *Test) GetHistoryShardRouter() sharding.Router {
+	return s.HistoryShardRouter
+}
+
 // other common resources
 
 // GetDomainCache for testing
@@ -42,14 +42,21 @@ const (
 // https://github.com/grpc/grpc/blob/master/doc/naming.md.
 // e.g. to use dns resolver, a "dns:///" prefix should be applied to the target.
 func Dial(hostName string) (*grpc.ClientConn, error) {
-	return grpc.Dial(hostName,
-		grpc.WithInsecure(),
+	return DialWithOptions(hostName, grpc.WithInsecure())
+}
+
+// DialWithOptions creates a client connection to the given target with default options
+// followed by the given options. The options must include the transport security option,
+// e.g. grpc.WithInsecure() or grpc.WithTransportCredentials().
+func DialWithOptions(hostName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOptions := []grpc.DialOption{
 		grpc.WithChainUnaryInterceptor(
 			versionHeadersInterceptor,
 			errorInterceptor),
 		grpc.WithDefaultServiceConfig(DefaultServiceConfig),
 		grpc.WithDisableServiceConfig(),
-	)
+	}
+	return grpc.Dial(hostName, append(dialOptions, opts...)...)
 }
 
 func errorInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
@@ -55,6 +55,17 @@ func (c *Persistence) Validate() error {
 			ds.SQL.NumShards = 1
 		}
 	}
+	if c.IsAdvancedVisibilityConfigExist() {
+		ds, ok := c.DataStores[c.AdvancedVisibilityStore]
+		if !ok {
+			return fmt.Errorf("persistence config: missing config for datastore %v", c.AdvancedVisibilityStore)
+		}
+		if ds.ElasticSearch != nil {
+			if err := ds.ElasticSearch.Validate(); err != nil {
+				return fmt.Errorf("persistence config: datastore %v: %v", c.AdvancedVisibilityStore, err)
+			}
+		}
+	}
 	return nil
 }
 
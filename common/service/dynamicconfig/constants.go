@@ -67,8 +67,11 @@ var keys = map[Key]string{
 	MaxDecisionStartToCloseSeconds:      "system.maxDecisionStartToCloseSeconds",
 	DisallowQuery:                       "system.disallowQuery",
 	EnableBatcher:                       "worker.enableBatcher",
+	EnableDomainMigrator:                "worker.enableDomainMigrator",
+	EnableHistoryResharder:              "worker.enableHistoryResharder",
 	EnableParentClosePolicyWorker:       "system.enableParentClosePolicyWorker",
 	EnableStickyQuery:                   "system.enableStickyQuery",
+	CompletionCallbackAllowedHosts:      "system.completionCallbackAllowedHosts",
 
 	// size limit
 	BlobSizeLimitError:     "limit.blobSize.error",
@@ -100,6 +103,7 @@ var keys = map[Key]string{
 	VisibilityArchivalQueryMaxPageSize:    "frontend.visibilityArchivalQueryMaxPageSize",
 	VisibilityArchivalQueryMaxRangeInDays: "frontend.visibilityArchivalQueryMaxRangeInDays",
 	VisibilityArchivalQueryMaxQPS:         "frontend.visibilityArchivalQueryMaxQPS",
+	MaxCompletionCallbacks:                "frontend.maxCompletionCallbacks",
 
 	// matching settings
 	MatchingRPS:                             "matching.rps",
@@ -121,6 +125,7 @@ var keys = map[Key]string{
 	MatchingForwarderMaxOutstandingTasks:    "matching.forwarderMaxOutstandingTasks",
 	MatchingForwarderMaxRatePerSecond:       "matching.forwarderMaxRatePerSecond",
 	MatchingForwarderMaxChildrenPerNode:     "matching.forwarderMaxChildrenPerNode",
+	MatchingActivityConcurrencyLimits:       "matching.activityConcurrencyLimits",
 
 	// history settings
 	HistoryRPS:                                            "history.rps",
@@ -132,9 +137,11 @@ var keys = map[Key]string{
 	HistoryMaxAutoResetPoints:                             "history.historyMaxAutoResetPoints",
 	HistoryCacheMaxSize:                                   "history.cacheMaxSize",
 	HistoryCacheTTL:                                       "history.cacheTTL",
+	HistoryCacheMaxSizeInBytes:                            "history.cacheMaxSizeInBytes",
 	EventsCacheInitialSize:                                "history.eventsCacheInitialSize",
 	EventsCacheMaxSize:                                    "history.eventsCacheMaxSize",
 	EventsCacheTTL:                                        "history.eventsCacheTTL",
+	EventsCacheMaxSizeInBytes:                             "history.eventsCacheMaxSizeInBytes",
 	AcquireShardInterval:                                  "history.acquireShardInterval",
 	AcquireShardConcurrency:                               "history.acquireShardConcurrency",
 	StandbyClusterDelay:                                   "history.standbyClusterDelay",
@@ -157,6 +164,8 @@ var keys = map[Key]string{
 	TimerProcessorMaxTimeShift:                            "history.timerProcessorMaxTimeShift",
 	TimerProcessorHistoryArchivalSizeLimit:                "history.timerProcessorHistoryArchivalSizeLimit",
 	TimerProcessorArchivalTimeLimit:                       "history.TimerProcessorArchivalTimeLimit",
+	TimerProcessorEnableInMemoryTimers:                    "history.timerProcessorEnableInMemoryTimers",
+	TimerProcessorInMemoryTimerHorizon:                    "history.timerProcessorInMemoryTimerHorizon",
 	TransferTaskBatchSize:                                 "history.transferTaskBatchSize",
 	TransferProcessorFailoverMaxPollRPS:                   "history.transferProcessorFailoverMaxPollRPS",
 	TransferProcessorMaxPollRPS:                           "history.transferProcessorMaxPollRPS",
@@ -170,6 +179,15 @@ var keys = map[Key]string{
 	TransferProcessorUpdateAckIntervalJitterCoefficient:   "history.transferProcessorUpdateAckIntervalJitterCoefficient",
 	TransferProcessorCompleteTransferInterval:             "history.transferProcessorCompleteTransferInterval",
 	TransferProcessorVisibilityArchivalTimeLimit:          "history.transferProcessorVisibilityArchivalTimeLimit",
+	QueueProcessorEnableSplit:                             "history.queueProcessorEnableSplit",
+	QueueProcessorSplitAttemptThreshold:                   "history.queueProcessorSplitAttemptThreshold",
+	QueueProcessorMaxSplitQueues:                          "history.queueProcessorMaxSplitQueues",
+	QueueProcessorSplitQueueMaxPollRPS:                    "history.queueProcessorSplitQueueMaxPollRPS",
+	QueueProcessorSplitQueueWorkerCount:                   "history.queueProcessorSplitQueueWorkerCount",
+	QueueProcessorSplitQueueMergeInterval:                 "history.queueProcessorSplitQueueMergeInterval",
+	TaskDLQEnabled:                                        "history.taskDLQEnabled",
+	TaskDLQMaxRetryCount:                                  "history.taskDLQMaxRetryCount",
+	TaskDLQSizeReportInterval:                             "history.taskDLQSizeReportInterval",
 	ReplicatorTaskBatchSize:                               "history.replicatorTaskBatchSize",
 	ReplicatorTaskWorkerCount:                             "history.replicatorTaskWorkerCount",
 	ReplicatorTaskMaxRetryCount:                           "history.replicatorTaskMaxRetryCount",
@@ -196,15 +214,15 @@ var keys = map[Key]string{
 	HistoryThrottledLogRPS:                                "history.throttledLogRPS",
 	StickyTTL:                                             "history.stickyTTL",
 	DecisionHeartbeatTimeout:                              "history.decisionHeartbeatTimeout",
+	EagerActivityDispatchLimit:                            "history.eagerActivityDispatchLimit",
 	ParentClosePolicyThreshold:                            "history.parentClosePolicyThreshold",
 	NumParentClosePolicySystemWorkflows:                   "history.numParentClosePolicySystemWorkflows",
-	ReplicationTaskFetcherParallelism:                     "history.ReplicationTaskFetcherParallelism",
-	ReplicationTaskFetcherAggregationInterval:             "history.ReplicationTaskFetcherAggregationInterval",
-	ReplicationTaskFetcherTimerJitterCoefficient:          "history.ReplicationTaskFetcherTimerJitterCoefficient",
+	ReplicationStreamMaxInFlightMessages:                  "history.ReplicationStreamMaxInFlightMessages",
+	ReplicationStreamHeartbeatInterval:                    "history.ReplicationStreamHeartbeatInterval",
+	ReplicationStreamIdleTimeout:                          "history.ReplicationStreamIdleTimeout",
 	ReplicationTaskFetcherErrorRetryWait:                  "history.ReplicationTaskFetcherErrorRetryWait",
 	ReplicationTaskProcessorErrorRetryWait:                "history.ReplicationTaskProcessorErrorRetryWait",
 	ReplicationTaskProcessorErrorRetryMaxAttempts:         "history.ReplicationTaskProcessorErrorRetryMaxAttempts",
-	ReplicationTaskProcessorNoTaskInitialWait:             "history.ReplicationTaskProcessorNoTaskInitialWait",
 	ReplicationTaskProcessorCleanupInterval:               "history.ReplicationTaskProcessorCleanupInterval",
 	ReplicationTaskProcessorCleanupJitterCoefficient:      "history.ReplicationTaskProcessorCleanupJitterCoefficient",
 	EnableConsistentQuery:                                 "history.EnableConsistentQuery",
@@ -213,6 +231,13 @@ var keys = map[Key]string{
 	MutableStateChecksumGenProbability:                    "history.mutableStateChecksumGenProbability",
 	MutableStateChecksumVerifyProbability:                 "history.mutableStateChecksumVerifyProbability",
 	MutableStateChecksumInvalidateBefore:                  "history.mutableStateChecksumInvalidateBefore",
+	CompletionCallbackMaxAttempts:                         "history.completionCallbackMaxAttempts",
+	CompletionCallbackTimeout:                             "history.completionCallbackTimeout",
+	RehydratedWorkflowTTL:                                 "history.rehydratedWorkflowTTL",
+	HistoryStreamEnabled:                                  "history.historyStreamEnabled",
+	HistoryStreamKafkaApplication:                         "history.historyStreamKafkaApplication",
+	HistoryStreamRedaction:                                "history.historyStreamRedaction",
+	HistoryStreamMaxBatchesPerMessage:                     "history.historyStreamMaxBatchesPerMessage",
 
 	WorkerPersistenceMaxQPS:                         "worker.persistenceMaxQPS",
 	WorkerReplicatorMetaTaskConcurrency:             "worker.replicatorMetaTaskConcurrency",
@@ -240,7 +265,14 @@ var keys = map[Key]string{
 	ScannerPersistenceMaxQPS:                        "worker.scannerPersistenceMaxQPS",
 	TaskListScannerEnabled:                          "worker.taskListScannerEnabled",
 	HistoryScannerEnabled:                           "worker.historyScannerEnabled",
+	HistoryScannerDryRun:                            "worker.historyScannerDryRun",
 	ExecutionsScannerEnabled:                        "worker.executionsScannerEnabled",
+	StuckWorkflowScannerEnabled:                     "worker.stuckWorkflowScannerEnabled",
+	StuckWorkflowDecisionAttempts:                   "worker.stuckWorkflowDecisionAttempts",
+	StuckWorkflowScheduleToStartThreshold:           "worker.stuckWorkflowScheduleToStartThreshold",
+	StuckWorkflowIdleThreshold:                      "worker.stuckWorkflowIdleThreshold",
+	StuckWorkflowMaxReported:                        "worker.stuckWorkflowMaxReported",
+	WorkerDomainHandoverCheckInterval:               "worker.domainHandoverCheckInterval",
 }
 
 const (
@@ -297,6 +329,9 @@ const (
 	MaxDecisionStartToCloseSeconds
 	// DisallowQuery is the key to disallow query for a domain
 	DisallowQuery
+	// CompletionCallbackAllowedHosts is the comma separated list of hosts which http completion callbacks
+	// of a domain may call, entries of form *.example.com allow all subdomains
+	CompletionCallbackAllowedHosts
 
 	// BlobSizeLimitError is the per event blob size limit
 	BlobSizeLimitError
@@ -355,6 +390,8 @@ const (
 	VisibilityArchivalQueryMaxRangeInDays
 	// VisibilityArchivalQueryMaxQPS is the timeout for a visibility archival query
 	VisibilityArchivalQueryMaxQPS
+	// MaxCompletionCallbacks is the max number of completion callbacks a workflow can be started with
+	MaxCompletionCallbacks
 
 	// key for matching
 
@@ -396,6 +433,8 @@ const (
 	MatchingForwarderMaxRatePerSecond
 	// MatchingForwarderMaxChildrenPerNode is the max number of children per node in the task list partition tree
 	MatchingForwarderMaxChildrenPerNode
+	// MatchingActivityConcurrencyLimits is the max number of concurrently running activities of a task list across all its partitions, keyed by activity type
+	MatchingActivityConcurrencyLimits
 
 	// key for history
 
@@ -415,12 +454,16 @@ const (
 	HistoryCacheMaxSize
 	// HistoryCacheTTL is TTL of history cache
 	HistoryCacheTTL
+	// HistoryCacheMaxSizeInBytes is the max size in bytes of the history caches of all shards on a host, 0 means unlimited
+	HistoryCacheMaxSizeInBytes
 	// EventsCacheInitialSize is initial size of events cache
 	EventsCacheInitialSize
 	// EventsCacheMaxSize is max size of events cache
 	EventsCacheMaxSize
 	// EventsCacheTTL is TTL of events cache
 	EventsCacheTTL
+	// EventsCacheMaxSizeInBytes is the max size in bytes of the events caches of all shards on a host, 0 means unlimited
+	EventsCacheMaxSizeInBytes
 	// AcquireShardInterval is interval that timer used to acquire shard
 	AcquireShardInterval
 	// AcquireShardConcurrency is number of goroutines that can be used to acquire shards in the shard controller.
@@ -467,6 +510,11 @@ const (
 	TimerProcessorHistoryArchivalSizeLimit
 	// TimerProcessorArchivalTimeLimit is the upper time limit for inline history archival
 	TimerProcessorArchivalTimeLimit
+	// TimerProcessorEnableInMemoryTimers decides whether new timers due within the in-memory timer horizon
+	// are fired from memory by the active timer processor, instead of being loaded from persistence
+	TimerProcessorEnableInMemoryTimers
+	// TimerProcessorInMemoryTimerHorizon is the max duration until a new timer is due, for the timer to be fired from memory
+	TimerProcessorInMemoryTimerHorizon
 	// TransferTaskBatchSize is batch size for transferQueueProcessor
 	TransferTaskBatchSize
 	// TransferProcessorFailoverMaxPollRPS is max poll rate per second for transferQueueProcessor
@@ -493,6 +541,24 @@ const (
 	TransferProcessorCompleteTransferInterval
 	// TransferProcessorVisibilityArchivalTimeLimit is the upper time limit for archiving visibility records
 	TransferProcessorVisibilityArchivalTimeLimit
+	// QueueProcessorEnableSplit indicates whether transfer and timer queue processors split failing domains into their own processing queue
+	QueueProcessorEnableSplit
+	// QueueProcessorSplitAttemptThreshold is the number of failed attempts of a task after which its domain is split into its own processing queue
+	QueueProcessorSplitAttemptThreshold
+	// QueueProcessorMaxSplitQueues is the max number of split processing queues per shard and queue processor
+	QueueProcessorMaxSplitQueues
+	// QueueProcessorSplitQueueMaxPollRPS is max poll rate per second for the processing queue of a split domain
+	QueueProcessorSplitQueueMaxPollRPS
+	// QueueProcessorSplitQueueWorkerCount is number of worker for the processing queue of a split domain
+	QueueProcessorSplitQueueWorkerCount
+	// QueueProcessorSplitQueueMergeInterval is the duration without failures after which a split domain is merged back into the main processing queue
+	QueueProcessorSplitQueueMergeInterval
+	// TaskDLQEnabled is whether transfer and timer tasks are moved to the DLQ of the shard once they run out of retries
+	TaskDLQEnabled
+	// TaskDLQMaxRetryCount is the number of failed attempts of a transfer or timer task after which it is moved to the DLQ
+	TaskDLQMaxRetryCount
+	// TaskDLQSizeReportInterval is the interval at which the size of the transfer and timer task DLQ of a shard is sampled and reported
+	TaskDLQSizeReportInterval
 	// ReplicatorTaskBatchSize is batch size for ReplicatorProcessor
 	ReplicatorTaskBatchSize
 	// ReplicatorTaskWorkerCount is number of worker for ReplicatorProcessor
@@ -553,6 +619,8 @@ const (
 	StickyTTL
 	// DecisionHeartbeatTimeout for decision heartbeat
 	DecisionHeartbeatTimeout
+	// EagerActivityDispatchLimit is the max number of activities started eagerly on decision completion, 0 disables it
+	EagerActivityDispatchLimit
 
 	// key for worker
 
@@ -608,29 +676,48 @@ const (
 	TaskListScannerEnabled
 	// HistoryScannerEnabled indicates if history scanner should be started as part of worker.Scanner
 	HistoryScannerEnabled
+	// HistoryScannerDryRun indicates if history scanner should only report the garbage history branches without deleting them,
+	// it is enabled by default on SQL clusters
+	HistoryScannerDryRun
 	// ExecutionsScannerEnabled indicates if executions scanner should be started as part of worker.Scanner
 	ExecutionsScannerEnabled
+	// StuckWorkflowScannerEnabled indicates if stuck workflow scanner should be started as part of worker.Scanner
+	StuckWorkflowScannerEnabled
+	// StuckWorkflowDecisionAttempts is the decision task attempt from which a workflow is reported as stuck
+	StuckWorkflowDecisionAttempts
+	// StuckWorkflowScheduleToStartThreshold is how long an activity may wait on a task list without pollers
+	// before its workflow is reported as stuck
+	StuckWorkflowScheduleToStartThreshold
+	// StuckWorkflowIdleThreshold is how long an open workflow without pending work may stay unchanged
+	// before it is reported as stuck
+	StuckWorkflowIdleThreshold
+	// StuckWorkflowMaxReported is the max number of stuck workflows kept in the scanner report of a domain
+	StuckWorkflowMaxReported
+	// WorkerDomainHandoverCheckInterval is the interval at which the worker checks whether domain handovers have drained
+	WorkerDomainHandoverCheckInterval
 	// EnableBatcher decides whether start batcher in our worker
 	EnableBatcher
+	// EnableDomainMigrator decides whether start the workers migrating local domains from other clusters into this cluster
+	EnableDomainMigrator
+	// EnableHistoryResharder decides whether start the workers splitting history shards
+	EnableHistoryResharder
 	// EnableParentClosePolicyWorker decides whether or not enable system workers for processing parent close policy task
 	EnableParentClosePolicyWorker
 	// EnableStickyQuery indicates if sticky query should be enabled per domain
 	EnableStickyQuery
 
-	//ReplicationTaskFetcherParallelism determines how many go routines we spin up for fetching tasks
-	ReplicationTaskFetcherParallelism
-	// ReplicationTaskFetcherAggregationInterval determines how frequently the fetch requests are sent
-	ReplicationTaskFetcherAggregationInterval
-	// ReplicationTaskFetcherTimerJitterCoefficient is the jitter for fetcher timer
-	ReplicationTaskFetcherTimerJitterCoefficient
-	// ReplicationTaskFetcherErrorRetryWait is the wait time when fetcher encounters error
+	// ReplicationStreamMaxInFlightMessages is the max number of unacknowledged messages pushed over a replication stream
+	ReplicationStreamMaxInFlightMessages
+	// ReplicationStreamHeartbeatInterval is how often an idle replication stream pushes the shard status
+	ReplicationStreamHeartbeatInterval
+	// ReplicationStreamIdleTimeout is how long a replication stream is kept open without requests from its shard
+	ReplicationStreamIdleTimeout
+	// ReplicationTaskFetcherErrorRetryWait is the wait time before a broken replication stream is reopened
 	ReplicationTaskFetcherErrorRetryWait
 	// ReplicationTaskProcessorErrorRetryWait is the initial retry wait when we see errors in applying replication tasks
 	ReplicationTaskProcessorErrorRetryWait
 	// ReplicationTaskProcessorErrorRetryMaxAttempts is the max retry attempts for applying replication tasks
 	ReplicationTaskProcessorErrorRetryMaxAttempts
-	// ReplicationTaskProcessorNoTaskInitialWait is the wait time when not ask is returned
-	ReplicationTaskProcessorNoTaskInitialWait
 	// ReplicationTaskProcessorCleanupInterval determines how frequently the cleanup replication queue
 	ReplicationTaskProcessorCleanupInterval
 	// ReplicationTaskProcessorCleanupJitterCoefficient is the jitter for cleanup timer
@@ -647,6 +734,21 @@ const (
 	MutableStateChecksumVerifyProbability
 	// MutableStateChecksumInvalidateBefore is the epoch timestamp before which all checksums are to be discarded
 	MutableStateChecksumInvalidateBefore
+	// CompletionCallbackMaxAttempts is the max number of delivery attempts of a completion callback before it is given up
+	CompletionCallbackMaxAttempts
+	// CompletionCallbackTimeout is the timeout of a single completion callback delivery attempt
+	CompletionCallbackTimeout
+	// RehydratedWorkflowTTL is the duration after which a workflow rehydrated from the archive is deleted again
+	RehydratedWorkflowTTL
+	// HistoryStreamEnabled is whether history events of a domain are published to the history stream
+	HistoryStreamEnabled
+	// HistoryStreamKafkaApplication is the kafka application the history events of a domain are published to
+	HistoryStreamKafkaApplication
+	// HistoryStreamRedaction is the redaction applied to the history events of a domain before they are published,
+	// one of none, payloads or all
+	HistoryStreamRedaction
+	// HistoryStreamMaxBatchesPerMessage is the max number of history event batches published in a single message
+	HistoryStreamMaxBatchesPerMessage
 
 	// lastKeyForTest must be the last one in this const group for testing purpose
 	lastKeyForTest
@@ -0,0 +1,251 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"sync/atomic"
+	"time"
+
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+const (
+	// RouterRefreshInterval is the interval the routers reload the history shard routing
+	RouterRefreshInterval = 10 * time.Second
+)
+
+type (
+	// Router maps workflowIDs to history shards. History shards are split online by a factor of
+	// the current shard count, one parent shard at a time: the workflows of a parent shard P are
+	// moved to the shards P + j*shardCount which they hash to under the target shard count, the
+	// workflows of the parents which are not split yet keep being routed by the current shard count.
+	Router interface {
+		common.Daemon
+
+		// GetShardID returns the shard serving the workflowID
+		GetShardID(workflowID string) int
+		// GetShardIDs returns all shards which are currently serving workflows
+		GetShardIDs() []int
+		// NumberOfShards returns the number of shards which are currently serving workflows
+		NumberOfShards() int
+	}
+
+	router struct {
+		status             int32
+		shutdownChan       chan struct{}
+		clusterMetadataMgr persistence.ClusterMetadataManager
+		table              atomic.Value
+		logger             log.Logger
+	}
+
+	staticRouter struct {
+		table *routingTable
+	}
+
+	// routingTable is the immutable view of a HistoryShardRouting
+	routingTable struct {
+		shardCount       int
+		targetShardCount int
+		splitShards      map[int]struct{}
+		shardIDs         []int
+	}
+)
+
+var _ Router = (*router)(nil)
+var _ Router = (*staticRouter)(nil)
+
+// NewRouter returns a Router which follows the history shard routing persisted in the cluster metadata,
+// it routes by numberOfShards until the persisted routing is loaded
+func NewRouter(
+	clusterMetadataMgr persistence.ClusterMetadataManager,
+	numberOfShards int,
+	logger log.Logger,
+) Router {
+
+	r := &router{
+		status:             common.DaemonStatusInitialized,
+		shutdownChan:       make(chan struct{}),
+		clusterMetadataMgr: clusterMetadataMgr,
+		logger:             logger.WithTags(tag.ComponentShardRouter),
+	}
+	r.table.Store(newRoutingTable(&pblobs.HistoryShardRouting{ShardCount: int32(numberOfShards)}))
+	return r
+}
+
+// NewStaticRouter returns a Router which always routes by numberOfShards
+func NewStaticRouter(
+	numberOfShards int,
+) Router {
+
+	return &staticRouter{
+		table: newRoutingTable(&pblobs.HistoryShardRouting{ShardCount: int32(numberOfShards)}),
+	}
+}
+
+// NewStaticRouterFromRouting returns a Router which always routes by the given history shard routing
+func NewStaticRouterFromRouting(
+	routing *pblobs.HistoryShardRouting,
+) Router {
+
+	return &staticRouter{
+		table: newRoutingTable(routing),
+	}
+}
+
+// GetShardID returns the shard serving the workflowID under the given routing
+func GetShardID(
+	workflowID string,
+	routing *pblobs.HistoryShardRouting,
+) int {
+
+	return newRoutingTable(routing).getShardID(workflowID)
+}
+
+// GetChildShardIDs returns the shards the parent shard is split into when the shards are split
+// from shardCount to targetShardCount, the first child is the parent shard itself
+func GetChildShardIDs(
+	parentShardID int,
+	shardCount int,
+	targetShardCount int,
+) []int {
+
+	var shardIDs []int
+	for shardID := parentShardID; shardID < targetShardCount; shardID += shardCount {
+		shardIDs = append(shardIDs, shardID)
+	}
+	return shardIDs
+}
+
+func (r *router) Start() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	if err := r.refresh(); err != nil {
+		r.logger.Error("Unable to load history shard routing", tag.Error(err))
+	}
+	go r.refreshLoop()
+}
+
+func (r *router) Stop() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+	close(r.shutdownChan)
+}
+
+func (r *router) GetShardID(workflowID string) int {
+	return r.getTable().getShardID(workflowID)
+}
+
+func (r *router) GetShardIDs() []int {
+	return r.getTable().shardIDs
+}
+
+func (r *router) NumberOfShards() int {
+	return len(r.getTable().shardIDs)
+}
+
+func (r *router) getTable() *routingTable {
+	return r.table.Load().(*routingTable)
+}
+
+func (r *router) refreshLoop() {
+	timer := time.NewTicker(RouterRefreshInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-r.shutdownChan:
+			return
+		case <-timer.C:
+			if err := r.refresh(); err != nil {
+				r.logger.Error("Error refreshing history shard routing", tag.Error(err))
+			}
+		}
+	}
+}
+
+func (r *router) refresh() error {
+	resp, err := r.clusterMetadataMgr.GetHistoryShardRouting()
+	if err != nil {
+		return err
+	}
+
+	current := r.getTable()
+	table := newRoutingTable(resp.Routing)
+	if len(table.shardIDs) != len(current.shardIDs) {
+		r.logger.Info("History shard routing changed", tag.Number(int64(len(table.shardIDs))))
+	}
+	r.table.Store(table)
+	return nil
+}
+
+func (r *staticRouter) Start() {}
+
+func (r *staticRouter) Stop() {}
+
+func (r *staticRouter) GetShardID(workflowID string) int {
+	return r.table.getShardID(workflowID)
+}
+
+func (r *staticRouter) GetShardIDs() []int {
+	return r.table.shardIDs
+}
+
+func (r *staticRouter) NumberOfShards() int {
+	return len(r.table.shardIDs)
+}
+
+func newRoutingTable(
+	routing *pblobs.HistoryShardRouting,
+) *routingTable {
+
+	table := &routingTable{
+		shardCount:       int(routing.GetShardCount()),
+		targetShardCount: int(routing.GetTargetShardCount()),
+		splitShards:      make(map[int]struct{}),
+	}
+	for shardID := 0; shardID < table.shardCount; shardID++ {
+		table.shardIDs = append(table.shardIDs, shardID)
+	}
+	if table.targetShardCount <= table.shardCount {
+		return table
+	}
+	for _, parentShardID := range routing.GetSplitShardIDs() {
+		table.splitShards[int(parentShardID)] = struct{}{}
+		table.shardIDs = append(table.shardIDs, GetChildShardIDs(int(parentShardID), table.shardCount, table.targetShardCount)[1:]...)
+	}
+	return table
+}
+
+func (t *routingTable) getShardID(workflowID string) int {
+	shardID := common.WorkflowIDToHistoryShard(workflowID, t.shardCount)
+	if _, ok := t.splitShards[shardID]; ok {
+		// the workflow hashes to shardID + j*shardCount under the target shard count
+		return common.WorkflowIDToHistoryShard(workflowID, t.targetShardCount)
+	}
+	return shardID
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+)
+
+type (
+	routerSuite struct {
+		suite.Suite
+		*require.Assertions
+	}
+)
+
+func TestRouterSuite(t *testing.T) {
+	s := new(routerSuite)
+	suite.Run(t, s)
+}
+
+func (s *routerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *routerSuite) TestStaticRouter() {
+	router := NewStaticRouter(4)
+	s.Equal(4, router.NumberOfShards())
+	s.Equal([]int{0, 1, 2, 3}, router.GetShardIDs())
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("wid-%v", i)
+		s.Equal(common.WorkflowIDToHistoryShard(workflowID, 4), router.GetShardID(workflowID))
+	}
+}
+
+func (s *routerSuite) TestStaticRouterFromRouting() {
+	router := NewStaticRouterFromRouting(&pblobs.HistoryShardRouting{
+		ShardCount:       2,
+		TargetShardCount: 4,
+		SplitShardIDs:    []int32{0},
+	})
+	s.Equal(3, router.NumberOfShards())
+	s.Equal([]int{0, 1, 2}, router.GetShardIDs())
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("wid-%v", i)
+		if parentShardID := common.WorkflowIDToHistoryShard(workflowID, 2); parentShardID == 1 {
+			s.Equal(1, router.GetShardID(workflowID))
+		} else {
+			s.Equal(common.WorkflowIDToHistoryShard(workflowID, 4), router.GetShardID(workflowID))
+		}
+	}
+}
+
+func (s *routerSuite) TestGetChildShardIDs() {
+	s.Equal([]int{1, 5, 9}, GetChildShardIDs(1, 4, 12))
+	s.Equal([]int{3}, GetChildShardIDs(3, 4, 4))
+}
+
+func (s *routerSuite) TestRoutingTable_SplitInProgress() {
+	table := newRoutingTable(&pblobs.HistoryShardRouting{
+		ShardCount:       4,
+		TargetShardCount: 8,
+		SplitShardIDs:    []int32{1},
+	})
+	s.Equal([]int{0, 1, 2, 3, 5}, table.shardIDs)
+
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("wid-%v", i)
+		parentShardID := common.WorkflowIDToHistoryShard(workflowID, 4)
+		shardID := table.getShardID(workflowID)
+		if parentShardID == 1 {
+			s.Contains([]int{1, 5}, shardID)
+			s.Equal(common.WorkflowIDToHistoryShard(workflowID, 8), shardID)
+		} else {
+			s.Equal(parentShardID, shardID)
+		}
+	}
+}
+
+func (s *routerSuite) TestRoutingTable_SplitCompleted() {
+	table := newRoutingTable(&pblobs.HistoryShardRouting{
+		ShardCount: 8,
+	})
+	s.Len(table.shardIDs, 8)
+
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("wid-%v", i)
+		s.Equal(common.WorkflowIDToHistoryShard(workflowID, 8), table.getShardID(workflowID))
+	}
+}
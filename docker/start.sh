@@ -5,6 +5,7 @@ set -x
 DB="${DB:-cassandra}"
 ENABLE_ES="${ENABLE_ES:-false}"
 ES_PORT="${ES_PORT:-9200}"
+ES_VERSION="${ES_VERSION:-v6}"
 RF=${RF:-1}
 DEFAULT_DOMAIN_NAME="${DEFAULT_DOMAIN_NAME:-default}"
 DEFAULT_DOMAIN_RETENTION=${DEFAULT_DOMAIN_RETENTION:-1}
@@ -56,12 +57,9 @@ setup_postgres_schema() {
 
 
 setup_es_template() {
-    SCHEMA_FILE=$TEMPORAL_HOME/schema/elasticsearch/visibility/index_template.json
+    SCHEMA_DIR=$TEMPORAL_HOME/schema/elasticsearch/$ES_VERSION/visibility/versioned
     server=`echo $ES_SEEDS | awk -F ',' '{print $1}'`
-    URL="http://$server:$ES_PORT/_template/temporal-visibility-template"
-    curl -X PUT $URL -H 'Content-Type: application/json' --data-binary "@$SCHEMA_FILE"
-    URL="http://$server:$ES_PORT/temporal-visibility-dev"
-    curl -X PUT $URL
+    temporal-es-tool --url "http://$server:$ES_PORT" --es-version $ES_VERSION --index temporal-visibility-dev update-schema -d $SCHEMA_DIR
 }
 
 setup_schema() {
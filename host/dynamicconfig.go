@@ -40,7 +40,7 @@ var (
 		dynamicconfig.ReplicationTaskProcessorErrorRetryMaxAttempts: 1,
 		dynamicconfig.AdvancedVisibilityWritingMode:                 common.AdvancedVisibilityWritingModeOff,
 		dynamicconfig.DecisionHeartbeatTimeout:                      5 * time.Second,
-		dynamicconfig.ReplicationTaskFetcherAggregationInterval:     200 * time.Millisecond,
+		dynamicconfig.ReplicationStreamHeartbeatInterval:            200 * time.Millisecond,
 		dynamicconfig.ReplicationTaskFetcherErrorRetryWait:          50 * time.Millisecond,
 		dynamicconfig.ReplicationTaskProcessorErrorRetryWait:        time.Millisecond,
 		dynamicconfig.EnableConsistentQueryByDomain:                 true,
@@ -118,6 +118,24 @@ CREATE TABLE replication_tasks_dlq (
   PRIMARY KEY (source_cluster_name, shard_id, task_id)
 );
 
+CREATE TABLE transfer_tasks_dlq (
+  shard_id INT NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, task_id)
+);
+
+CREATE TABLE timer_tasks_dlq (
+  shard_id INT NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, task_id)
+);
+
 CREATE TABLE timer_tasks (
   shard_id INT NOT NULL,
   visibility_timestamp DATETIME(6) NOT NULL,
@@ -260,6 +278,9 @@ CREATE TABLE cluster_metadata (
   metadata_partition        INT NOT NULL,
   immutable_data            BLOB NOT NULL,
   immutable_data_encoding   VARCHAR(16) NOT NULL,
+  shard_routing_data        BLOB,
+  shard_routing_data_encoding VARCHAR(16),
+  shard_routing_version     BIGINT NOT NULL DEFAULT 0,
   PRIMARY KEY(metadata_partition)
 );
 
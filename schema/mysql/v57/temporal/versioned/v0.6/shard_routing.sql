@@ -0,0 +1,3 @@
+ALTER TABLE cluster_metadata ADD shard_routing_data BLOB;
+ALTER TABLE cluster_metadata ADD shard_routing_data_encoding VARCHAR(16);
+ALTER TABLE cluster_metadata ADD shard_routing_version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE cluster_metadata DROP COLUMN shard_routing_data;
+ALTER TABLE cluster_metadata DROP COLUMN shard_routing_data_encoding;
+ALTER TABLE cluster_metadata DROP COLUMN shard_routing_version;
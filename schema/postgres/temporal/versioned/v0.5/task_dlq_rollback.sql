@@ -0,0 +1,2 @@
+DROP TABLE transfer_tasks_dlq;
+DROP TABLE timer_tasks_dlq;
@@ -23,6 +23,7 @@ package frontend
 import (
 	"context"
 	"errors"
+	"io"
 	"strconv"
 	"time"
 
@@ -34,8 +35,10 @@ import (
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/.gen/proto/token"
+	historyclient "github.com/temporalio/temporal/client/history"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/definition"
@@ -60,10 +63,10 @@ type (
 	AdminHandler struct {
 		resource.Resource
 
-		numberOfHistoryShards int
-		params                *resource.BootstrapParams
-		config                *Config
-		domainDLQHandler      domain.DLQMessageHandler
+		params           *resource.BootstrapParams
+		config           *Config
+		domainDLQHandler domain.DLQMessageHandler
+		domainHandler    domain.Handler
 	}
 )
 
@@ -85,15 +88,24 @@ func NewAdminHandler(
 		resource.GetLogger(),
 	)
 	return &AdminHandler{
-		Resource:              resource,
-		numberOfHistoryShards: params.PersistenceConfig.NumHistoryShards,
-		params:                params,
-		config:                config,
+		Resource: resource,
+		params:   params,
+		config:   config,
 		domainDLQHandler: domain.NewDLQMessageHandler(
 			domainReplicationTaskExecutor,
 			resource.GetDomainReplicationQueue(),
 			resource.GetLogger(),
 		),
+		domainHandler: domain.NewHandler(
+			config.MinRetentionDays(),
+			config.MaxBadBinaries,
+			resource.GetLogger(),
+			resource.GetMetadataManager(),
+			resource.GetClusterMetadata(),
+			domain.NewDomainReplicator(resource.GetDomainReplicationQueue(), resource.GetLogger()),
+			resource.GetArchivalMetadata(),
+			resource.GetArchiverProvider(),
+		),
 	}
 }
 
@@ -187,7 +199,7 @@ func (adh *AdminHandler) DescribeWorkflowExecution(ctx context.Context, request
 		return nil, adh.error(err, scope)
 	}
 
-	shardID := common.WorkflowIDToHistoryShard(request.Execution.WorkflowId, adh.numberOfHistoryShards)
+	shardID := adh.GetHistoryShardRouter().GetShardID(request.Execution.WorkflowId)
 	shardIDstr := string(shardID)
 	shardIDForOutput := strconv.Itoa(shardID)
 
@@ -280,6 +292,7 @@ func (adh *AdminHandler) DescribeHistoryHost(ctx context.Context, request *admin
 		DomainCache:           resp.GetDomainCache(),
 		ShardControllerStatus: resp.GetShardControllerStatus(),
 		Address:               resp.GetAddress(),
+		SplitQueues:           resp.GetSplitQueues(),
 	}, err
 }
 
@@ -381,7 +394,7 @@ func (adh *AdminHandler) GetWorkflowExecutionRawHistory(ctx context.Context, req
 
 	// TODO need to deal with transient decision if to be used by client getting history
 	var historyBatches []*commonproto.History
-	shardID := common.WorkflowIDToHistoryShard(execution.GetWorkflowId(), adh.numberOfHistoryShards)
+	shardID := adh.GetHistoryShardRouter().GetShardID(execution.GetWorkflowId())
 	_, historyBatches, continuationToken.PersistenceToken, size, err = history.PaginateHistory(
 		adh.GetHistoryManager(),
 		true, // this means that we are getting history by batch
@@ -514,10 +527,7 @@ func (adh *AdminHandler) GetWorkflowExecutionRawHistoryV2(ctx context.Context, r
 		}, nil
 	}
 	pageSize := int(request.GetMaximumPageSize())
-	shardID := common.WorkflowIDToHistoryShard(
-		execution.GetWorkflowId(),
-		adh.numberOfHistoryShards,
-	)
+	shardID := adh.GetHistoryShardRouter().GetShardID(execution.GetWorkflowId())
 	rawHistoryResponse, err := adh.GetHistoryManager().ReadRawHistoryBranch(&persistence.ReadHistoryBranchRequest{
 		BranchToken: targetVersionHistory.GetBranchToken(),
 		// GetWorkflowExecutionRawHistoryV2 is exclusive exclusive.
@@ -651,6 +661,66 @@ func (adh *AdminHandler) GetReplicationMessages(ctx context.Context, request *ad
 	return &adminservice.GetReplicationMessagesResponse{MessagesByShard: resp.GetMessagesByShard()}, nil
 }
 
+// StreamReplicationMessages proxies a replication stream of a remote cluster to the history host owning the shard.
+func (adh *AdminHandler) StreamReplicationMessages(server adminservice.AdminService_StreamReplicationMessagesServer) (retError error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &retError)
+
+	scope, sw := adh.startRequestProfile(metrics.AdminStreamReplicationMessagesScope)
+	defer sw.Stop()
+
+	request, err := server.Recv()
+	if err != nil {
+		return err
+	}
+	if request.GetToken() == nil {
+		return adh.error(errReplicationTokenNotSet, scope)
+	}
+	if request.GetClusterName() == "" {
+		return adh.error(errClusterNameNotSet, scope)
+	}
+
+	ctx, cancel := context.WithCancel(historyclient.WithShardID(server.Context(), request.GetToken().GetShardID()))
+	defer cancel()
+	historyStream, err := adh.GetHistoryClient().StreamReplicationMessages(ctx)
+	if err != nil {
+		return adh.error(err, scope)
+	}
+
+	// forward the requests of the remote cluster, starting with the one that opened the stream
+	go func(request *adminservice.StreamReplicationMessagesRequest) {
+		for {
+			if err := historyStream.Send(&historyservice.StreamReplicationMessagesRequest{
+				Token:       request.GetToken(),
+				ClusterName: request.GetClusterName(),
+			}); err != nil {
+				return
+			}
+
+			var err error
+			request, err = server.Recv()
+			if err != nil {
+				_ = historyStream.CloseSend()
+				return
+			}
+		}
+	}(request)
+
+	for {
+		response, err := historyStream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return adh.error(err, scope)
+		}
+		if err := server.Send(&adminservice.StreamReplicationMessagesResponse{
+			Messages: response.GetMessages(),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
 // GetDomainReplicationMessages returns new domain replication tasks since last retrieved task ID.
 func (adh *AdminHandler) GetDomainReplicationMessages(ctx context.Context, request *adminservice.GetDomainReplicationMessagesRequest) (_ *adminservice.GetDomainReplicationMessagesResponse, retError error) {
 	defer log.CapturePanicGRPC(adh.GetLogger(), &retError)
@@ -978,6 +1048,427 @@ func (adh *AdminHandler) RefreshWorkflowTasks(
 	return &adminservice.RefreshWorkflowTasksResponse{}, nil
 }
 
+// RehydrateWorkflowExecution restores an archived workflow execution as a closed workflow
+func (adh *AdminHandler) RehydrateWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.RehydrateWorkflowExecutionRequest,
+) (_ *adminservice.RehydrateWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminRehydrateWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.Execution.GetRunId() == "" {
+		return nil, adh.error(errRunIDNotSet, scope)
+	}
+	if !adh.GetArchivalMetadata().GetHistoryConfig().ReadEnabled() {
+		return nil, adh.error(errClusterIsNotConfiguredForReadingArchivalHistory, scope)
+	}
+	domainEntry, err := adh.GetDomainCache().GetDomain(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if domainEntry.GetConfig().HistoryArchivalURI == "" {
+		return nil, adh.error(errDomainIsNotConfiguredForHistoryArchival, scope)
+	}
+
+	resp, err := adh.GetHistoryClient().RehydrateWorkflowExecution(ctx, &historyservice.RehydrateWorkflowExecutionRequest{
+		DomainUUID: domainEntry.GetInfo().ID,
+		Request:    request,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.RehydrateWorkflowExecutionResponse{
+		ExpirationTime: resp.GetExpirationTime(),
+	}, nil
+}
+
+// StartDomainHandover starts a graceful failover of a domain to the handover cluster
+func (adh *AdminHandler) StartDomainHandover(
+	ctx context.Context,
+	request *adminservice.StartDomainHandoverRequest,
+) (_ *adminservice.StartDomainHandoverResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminStartDomainHandoverScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if request.GetHandoverClusterName() == "" {
+		return nil, adh.error(errClusterNameNotSet, scope)
+	}
+
+	if err := adh.domainHandler.StartDomainHandover(
+		ctx,
+		request.GetDomain(),
+		request.GetHandoverClusterName(),
+		time.Duration(request.GetTimeoutInSeconds())*time.Second,
+	); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.StartDomainHandoverResponse{}, nil
+}
+
+// DescribeDomainHandover returns the progress of the graceful failover of a domain
+func (adh *AdminHandler) DescribeDomainHandover(
+	ctx context.Context,
+	request *adminservice.DescribeDomainHandoverRequest,
+) (_ *adminservice.DescribeDomainHandoverResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminDescribeDomainHandoverScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+
+	// read the domain from persistence, the domain cache may not have observed the handover yet
+	domainResponse, err := adh.GetMetadataManager().GetDomain(&persistence.GetDomainRequest{Name: request.GetDomain()})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	response := &adminservice.DescribeDomainHandoverResponse{
+		ActiveClusterName:   domainResponse.ReplicationConfig.ActiveClusterName,
+		HandoverClusterName: domainResponse.HandoverClusterName,
+		FailoverEndTime:     domainResponse.FailoverEndTime,
+		NumberOfShards:      int32(adh.GetHistoryShardRouter().NumberOfShards()),
+	}
+	if domainResponse.HandoverClusterName == "" {
+		return response, nil
+	}
+
+	var shardIDs []int32
+	for _, shardID := range adh.GetHistoryShardRouter().GetShardIDs() {
+		shardIDs = append(shardIDs, int32(shardID))
+	}
+	statusResponse, err := adh.GetHistoryClient().GetDomainHandoverStatus(ctx, &historyservice.GetDomainHandoverStatusRequest{
+		DomainUUID: domainResponse.Info.ID,
+		ShardIDs:   shardIDs,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	response.PendingShardIDs = statusResponse.GetPendingShardIDs()
+	return response, nil
+}
+
+// UnloadTaskList unloads a task list from the matching host which owns it
+func (adh *AdminHandler) UnloadTaskList(
+	ctx context.Context,
+	request *adminservice.UnloadTaskListRequest,
+) (_ *adminservice.UnloadTaskListResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminUnloadTaskListScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if request.TaskList.GetName() == "" {
+		return nil, adh.error(errTaskListNotSet, scope)
+	}
+
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	resp, err := adh.GetMatchingClient().UnloadTaskList(ctx, &matchingservice.UnloadTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     request.TaskList,
+		TaskListType: request.GetTaskListType(),
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.UnloadTaskListResponse{Unloaded: resp.GetUnloaded()}, nil
+}
+
+// ExportWorkflowExecution returns a page of the history of a workflow execution, the first page also contains the mutable state
+func (adh *AdminHandler) ExportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ExportWorkflowExecutionRequest,
+) (_ *adminservice.ExportWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminExportWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if len(request.NextPageToken) > 0 && request.Execution.GetRunId() == "" {
+		return nil, adh.error(errRunIDNotSet, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	response := &adminservice.ExportWorkflowExecutionResponse{
+		Execution: request.Execution,
+	}
+	if len(request.NextPageToken) == 0 {
+		// resolve the current run first, so that all pages read the history of the same run
+		mutableStateResp, err := adh.GetHistoryClient().GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+			DomainUUID: domainID,
+			Execution:  request.Execution,
+		})
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		response.Execution = mutableStateResp.GetExecution()
+
+		describeResp, err := adh.GetHistoryClient().DescribeMutableState(ctx, &historyservice.DescribeMutableStateRequest{
+			DomainUUID: domainID,
+			Execution:  response.Execution,
+		})
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		response.MutableState = describeResp.GetMutableStateInDatabase()
+	}
+
+	// the whole history of the current branch, the start version is set to the version of the first event
+	historyResp, err := adh.GetWorkflowExecutionRawHistoryV2(ctx, &adminservice.GetWorkflowExecutionRawHistoryV2Request{
+		Domain:            request.GetDomain(),
+		Execution:         response.Execution,
+		StartEventId:      common.FirstEventID - 1,
+		StartEventVersion: common.EmptyVersion,
+		EndEventId:        common.EmptyEventID,
+		EndEventVersion:   common.EmptyVersion,
+		MaximumPageSize:   request.GetMaximumPageSize(),
+		NextPageToken:     request.NextPageToken,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	response.HistoryBatches = historyResp.GetHistoryBatches()
+	response.NextPageToken = historyResp.GetNextPageToken()
+	return response, nil
+}
+
+// ImportWorkflowExecution imports a page of the history exported from another cluster, the last page creates the workflow execution
+func (adh *AdminHandler) ImportWorkflowExecution(
+	ctx context.Context,
+	request *adminservice.ImportWorkflowExecutionRequest,
+) (_ *adminservice.ImportWorkflowExecutionResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminImportWorkflowExecutionScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if err := validateExecution(request.Execution); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.Execution.GetRunId() == "" {
+		return nil, adh.error(errRunIDNotSet, scope)
+	}
+	if len(request.HistoryBatches) == 0 && (!request.LastPage || len(request.NextPageToken) == 0) {
+		// only the last page of an import which continues a previous page can be empty
+		return nil, adh.error(errHistoryNotSet, scope)
+	}
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	resp, err := adh.GetHistoryClient().ImportWorkflowExecution(ctx, &historyservice.ImportWorkflowExecutionRequest{
+		DomainUUID: domainID,
+		Request:    request,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.ImportWorkflowExecutionResponse{
+		NextPageToken: resp.GetNextPageToken(),
+	}, nil
+}
+
+// StartDomainMigration locks a local domain for the migration to another cluster
+func (adh *AdminHandler) StartDomainMigration(
+	ctx context.Context,
+	request *adminservice.StartDomainMigrationRequest,
+) (_ *adminservice.StartDomainMigrationResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminStartDomainMigrationScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if request.GetTargetClusterName() == "" {
+		return nil, adh.error(errClusterNameNotSet, scope)
+	}
+
+	if err := adh.domainHandler.StartDomainMigration(
+		ctx,
+		request.GetDomain(),
+		request.GetTargetClusterName(),
+	); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.StartDomainMigrationResponse{}, nil
+}
+
+// EndDomainMigration deprecates a migrated domain or unlocks it if the migration was aborted
+func (adh *AdminHandler) EndDomainMigration(
+	ctx context.Context,
+	request *adminservice.EndDomainMigrationRequest,
+) (_ *adminservice.EndDomainMigrationResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminEndDomainMigrationScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+
+	if err := adh.domainHandler.EndDomainMigration(
+		ctx,
+		request.GetDomain(),
+		!request.GetAborted(),
+	); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.EndDomainMigrationResponse{}, nil
+}
+
+// ReadTaskDLQMessages returns transfer or timer tasks from the dead-letter queue of a shard
+func (adh *AdminHandler) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.ReadTaskDLQMessagesRequest,
+) (_ *adminservice.ReadTaskDLQMessagesResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminReadTaskDLQMessagesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskCategory(request.GetTaskCategory()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.GetMaximumPageSize() <= 0 {
+		request.MaximumPageSize = common.ReadDLQMessagesPageSize
+	}
+	if request.GetInclusiveEndMessageID() <= 0 {
+		request.InclusiveEndMessageID = common.EndMessageID
+	}
+
+	resp, err := adh.GetHistoryClient().ReadTaskDLQMessages(ctx, &historyservice.ReadTaskDLQMessagesRequest{
+		Request: request,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.ReadTaskDLQMessagesResponse{
+		Messages:      resp.GetMessages(),
+		NextPageToken: resp.GetNextPageToken(),
+	}, nil
+}
+
+// PurgeTaskDLQMessages deletes transfer or timer tasks from the dead-letter queue of a shard
+func (adh *AdminHandler) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.PurgeTaskDLQMessagesRequest,
+) (_ *adminservice.PurgeTaskDLQMessagesResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminPurgeTaskDLQMessagesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskCategory(request.GetTaskCategory()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.GetInclusiveEndMessageID() <= 0 {
+		request.InclusiveEndMessageID = common.EndMessageID
+	}
+
+	if _, err := adh.GetHistoryClient().PurgeTaskDLQMessages(ctx, &historyservice.PurgeTaskDLQMessagesRequest{
+		Request: request,
+	}); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.PurgeTaskDLQMessagesResponse{}, nil
+}
+
+// MergeTaskDLQMessages executes transfer or timer tasks from the dead-letter queue of a shard again
+func (adh *AdminHandler) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *adminservice.MergeTaskDLQMessagesRequest,
+) (_ *adminservice.MergeTaskDLQMessagesResponse, err error) {
+	defer log.CapturePanicGRPC(adh.GetLogger(), &err)
+	scope, sw := adh.startRequestProfile(metrics.AdminMergeTaskDLQMessagesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := validateTaskCategory(request.GetTaskCategory()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+	if request.GetMaximumPageSize() <= 0 {
+		request.MaximumPageSize = common.ReadDLQMessagesPageSize
+	}
+	if request.GetInclusiveEndMessageID() <= 0 {
+		request.InclusiveEndMessageID = common.EndMessageID
+	}
+
+	resp, err := adh.GetHistoryClient().MergeTaskDLQMessages(ctx, &historyservice.MergeTaskDLQMessagesRequest{
+		Request: request,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return &adminservice.MergeTaskDLQMessagesResponse{
+		NextPageToken: resp.GetNextPageToken(),
+	}, nil
+}
+
+func validateTaskCategory(category string) error {
+	switch category {
+	case "transfer", "timer":
+		return nil
+	default:
+		return errInvalidTaskCategory
+	}
+}
+
 func (adh *AdminHandler) validateGetWorkflowExecutionRawHistoryV2Request(
 	request *adminservice.GetWorkflowExecutionRawHistoryV2Request,
 ) error {
@@ -41,6 +41,7 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservicemock"
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/definition"
@@ -97,6 +98,8 @@ func (s *adminHandlerSuite) SetupTest() {
 	}
 	config := &Config{
 		EnableAdminProtection: dynamicconfig.GetBoolPropertyFn(false),
+		MinRetentionDays:      dynamicconfig.GetIntPropertyFn(1),
+		MaxBadBinaries:        dynamicconfig.GetIntPropertyFilteredByDomain(10),
 	}
 	s.handler = NewAdminHandler(s.mockResource, params, config)
 	s.handler.Start()
@@ -206,6 +209,25 @@ func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_FailedOnInvali
 	s.Error(err)
 }
 
+func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_FailedOnInvalidEventQueryRange() {
+	ctx := context.Background()
+	_, err := s.handler.GetWorkflowExecutionRawHistoryV2(ctx,
+		&adminservice.GetWorkflowExecutionRawHistoryV2Request{
+			Domain: s.domainName,
+			Execution: &commonproto.WorkflowExecution{
+				WorkflowId: "workflowID",
+				RunId:      uuid.New(),
+			},
+			StartEventId:      common.EmptyEventID,
+			StartEventVersion: common.EmptyVersion,
+			EndEventId:        common.EmptyEventID,
+			EndEventVersion:   common.EmptyVersion,
+			MaximumPageSize:   1,
+			NextPageToken:     nil,
+		})
+	s.Equal(errInvalidEventQueryRange, err)
+}
+
 func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_FailedOnDomainCache() {
 	ctx := context.Background()
 	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return("", fmt.Errorf("test")).Times(1)
@@ -226,6 +248,144 @@ func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2_FailedOnDomain
 	s.Error(err)
 }
 
+func (s *adminHandlerSuite) Test_StartDomainHandover_FailedOnMissingCluster() {
+	_, err := s.handler.StartDomainHandover(context.Background(), &adminservice.StartDomainHandoverRequest{
+		Domain:           s.domainName,
+		TimeoutInSeconds: 60,
+	})
+	s.Equal(errClusterNameNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_StartDomainMigration_FailedOnMissingCluster() {
+	_, err := s.handler.StartDomainMigration(context.Background(), &adminservice.StartDomainMigrationRequest{
+		Domain: s.domainName,
+	})
+	s.Equal(errClusterNameNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeDomainHandover() {
+	s.mockResource.MetadataMgr.On("GetDomain", &persistence.GetDomainRequest{Name: s.domainName}).Return(&persistence.GetDomainResponse{
+		Info: &persistence.DomainInfo{ID: s.domainID, Name: s.domainName},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: "active",
+		},
+		HandoverClusterName: "standby",
+		FailoverEndTime:     123,
+	}, nil).Once()
+	s.mockHistoryClient.EXPECT().GetDomainHandoverStatus(gomock.Any(), &historyservice.GetDomainHandoverStatusRequest{
+		DomainUUID: s.domainID,
+		ShardIDs:   []int32{0},
+	}).Return(&historyservice.GetDomainHandoverStatusResponse{PendingShardIDs: []int32{0}}, nil).Times(1)
+
+	resp, err := s.handler.DescribeDomainHandover(context.Background(), &adminservice.DescribeDomainHandoverRequest{
+		Domain: s.domainName,
+	})
+	s.NoError(err)
+	s.Equal(&adminservice.DescribeDomainHandoverResponse{
+		ActiveClusterName:   "active",
+		HandoverClusterName: "standby",
+		FailoverEndTime:     123,
+		NumberOfShards:      1,
+		PendingShardIDs:     []int32{0},
+	}, resp)
+}
+
+func (s *adminHandlerSuite) Test_UnloadTaskList_FailedOnMissingTaskList() {
+	_, err := s.handler.UnloadTaskList(context.Background(), &adminservice.UnloadTaskListRequest{
+		Domain: s.domainName,
+	})
+	s.Equal(errTaskListNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_UnloadTaskList() {
+	taskList := &commonproto.TaskList{Name: "some random task list"}
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().UnloadTaskList(gomock.Any(), &matchingservice.UnloadTaskListRequest{
+		DomainUUID:   s.domainID,
+		TaskList:     taskList,
+		TaskListType: enums.TaskListTypeActivity,
+	}).Return(&matchingservice.UnloadTaskListResponse{Unloaded: true}, nil).Times(1)
+
+	resp, err := s.handler.UnloadTaskList(context.Background(), &adminservice.UnloadTaskListRequest{
+		Domain:       s.domainName,
+		TaskList:     taskList,
+		TaskListType: enums.TaskListTypeActivity,
+	})
+	s.NoError(err)
+	s.True(resp.GetUnloaded())
+}
+
+func (s *adminHandlerSuite) Test_RehydrateWorkflowExecution_FailedOnMissingRunID() {
+	_, err := s.handler.RehydrateWorkflowExecution(context.Background(), &adminservice.RehydrateWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+		},
+	})
+	s.Equal(errRunIDNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_ExportWorkflowExecution_FailedOnMissingRunIDWithPageToken() {
+	_, err := s.handler.ExportWorkflowExecution(context.Background(), &adminservice.ExportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+		},
+		NextPageToken: []byte{1},
+	})
+	s.Equal(errRunIDNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_ImportWorkflowExecution_FailedOnMissingRunID() {
+	_, err := s.handler.ImportWorkflowExecution(context.Background(), &adminservice.ImportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+		},
+	})
+	s.Equal(errRunIDNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_ImportWorkflowExecution_FailedOnMissingHistory() {
+	_, err := s.handler.ImportWorkflowExecution(context.Background(), &adminservice.ImportWorkflowExecutionRequest{
+		Domain: s.domainName,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID",
+			RunId:      uuid.New(),
+		},
+	})
+	s.Equal(errHistoryNotSet, err)
+}
+
+func (s *adminHandlerSuite) Test_ReadTaskDLQMessages_FailedOnInvalidTaskCategory() {
+	_, err := s.handler.ReadTaskDLQMessages(context.Background(), &adminservice.ReadTaskDLQMessagesRequest{
+		ShardID:      0,
+		TaskCategory: "replication",
+	})
+	s.Equal(errInvalidTaskCategory, err)
+}
+
+func (s *adminHandlerSuite) Test_ReadTaskDLQMessages() {
+	messages := []*adminservice.TaskDLQMessage{{TaskID: 2, TaskType: 1}}
+	s.mockHistoryClient.EXPECT().ReadTaskDLQMessages(gomock.Any(), &historyservice.ReadTaskDLQMessagesRequest{
+		Request: &adminservice.ReadTaskDLQMessagesRequest{
+			ShardID:                 1,
+			TaskCategory:            "timer",
+			ExclusiveBeginMessageID: 1,
+			InclusiveEndMessageID:   common.EndMessageID,
+			MaximumPageSize:         common.ReadDLQMessagesPageSize,
+		},
+	}).Return(&historyservice.ReadTaskDLQMessagesResponse{Messages: messages}, nil).Times(1)
+
+	resp, err := s.handler.ReadTaskDLQMessages(context.Background(), &adminservice.ReadTaskDLQMessagesRequest{
+		ShardID:                 1,
+		TaskCategory:            "timer",
+		ExclusiveBeginMessageID: 1,
+	})
+	s.NoError(err)
+	s.Equal(messages, resp.GetMessages())
+}
+
 func (s *adminHandlerSuite) Test_GetWorkflowExecutionRawHistoryV2() {
 	ctx := context.Background()
 	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).AnyTimes()
@@ -131,6 +131,11 @@ func (adh *AdminNilCheckHandler) GetReplicationMessages(ctx context.Context, req
 	return resp, err
 }
 
+// StreamReplicationMessages ...
+func (adh *AdminNilCheckHandler) StreamReplicationMessages(server adminservice.AdminService_StreamReplicationMessagesServer) error {
+	return adh.parentHandler.StreamReplicationMessages(server)
+}
+
 // GetDomainReplicationMessages ...
 func (adh *AdminNilCheckHandler) GetDomainReplicationMessages(ctx context.Context, request *adminservice.GetDomainReplicationMessagesRequest) (_ *adminservice.GetDomainReplicationMessagesResponse, retError error) {
 	resp, err := adh.parentHandler.GetDomainReplicationMessages(ctx, request)
@@ -193,3 +198,102 @@ func (adh *AdminNilCheckHandler) RefreshWorkflowTasks(ctx context.Context, reque
 	}
 	return resp, err
 }
+
+// RehydrateWorkflowExecution restores an archived workflow execution as a closed workflow
+func (adh *AdminNilCheckHandler) RehydrateWorkflowExecution(ctx context.Context, request *adminservice.RehydrateWorkflowExecutionRequest) (*adminservice.RehydrateWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.RehydrateWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.RehydrateWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// StartDomainHandover starts a graceful failover of a domain
+func (adh *AdminNilCheckHandler) StartDomainHandover(ctx context.Context, request *adminservice.StartDomainHandoverRequest) (*adminservice.StartDomainHandoverResponse, error) {
+	resp, err := adh.parentHandler.StartDomainHandover(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.StartDomainHandoverResponse{}
+	}
+	return resp, err
+}
+
+// DescribeDomainHandover returns the progress of the graceful failover of a domain
+func (adh *AdminNilCheckHandler) DescribeDomainHandover(ctx context.Context, request *adminservice.DescribeDomainHandoverRequest) (*adminservice.DescribeDomainHandoverResponse, error) {
+	resp, err := adh.parentHandler.DescribeDomainHandover(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.DescribeDomainHandoverResponse{}
+	}
+	return resp, err
+}
+
+// UnloadTaskList unloads a task list from the matching host which owns it
+func (adh *AdminNilCheckHandler) UnloadTaskList(ctx context.Context, request *adminservice.UnloadTaskListRequest) (*adminservice.UnloadTaskListResponse, error) {
+	resp, err := adh.parentHandler.UnloadTaskList(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.UnloadTaskListResponse{}
+	}
+	return resp, err
+}
+
+// ExportWorkflowExecution returns a page of the history of a workflow execution
+func (adh *AdminNilCheckHandler) ExportWorkflowExecution(ctx context.Context, request *adminservice.ExportWorkflowExecutionRequest) (*adminservice.ExportWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.ExportWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ExportWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// ImportWorkflowExecution creates a workflow execution from the history exported from another cluster
+func (adh *AdminNilCheckHandler) ImportWorkflowExecution(ctx context.Context, request *adminservice.ImportWorkflowExecutionRequest) (*adminservice.ImportWorkflowExecutionResponse, error) {
+	resp, err := adh.parentHandler.ImportWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ImportWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+// StartDomainMigration locks a local domain for the migration to another cluster
+func (adh *AdminNilCheckHandler) StartDomainMigration(ctx context.Context, request *adminservice.StartDomainMigrationRequest) (*adminservice.StartDomainMigrationResponse, error) {
+	resp, err := adh.parentHandler.StartDomainMigration(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.StartDomainMigrationResponse{}
+	}
+	return resp, err
+}
+
+// EndDomainMigration deprecates a migrated domain or unlocks it if the migration was aborted
+func (adh *AdminNilCheckHandler) EndDomainMigration(ctx context.Context, request *adminservice.EndDomainMigrationRequest) (*adminservice.EndDomainMigrationResponse, error) {
+	resp, err := adh.parentHandler.EndDomainMigration(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.EndDomainMigrationResponse{}
+	}
+	return resp, err
+}
+
+// ReadTaskDLQMessages returns transfer or timer tasks from the dead-letter queue of a shard
+func (adh *AdminNilCheckHandler) ReadTaskDLQMessages(ctx context.Context, request *adminservice.ReadTaskDLQMessagesRequest) (*adminservice.ReadTaskDLQMessagesResponse, error) {
+	resp, err := adh.parentHandler.ReadTaskDLQMessages(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.ReadTaskDLQMessagesResponse{}
+	}
+	return resp, err
+}
+
+// PurgeTaskDLQMessages deletes transfer or timer tasks from the dead-letter queue of a shard
+func (adh *AdminNilCheckHandler) PurgeTaskDLQMessages(ctx context.Context, request *adminservice.PurgeTaskDLQMessagesRequest) (*adminservice.PurgeTaskDLQMessagesResponse, error) {
+	resp, err := adh.parentHandler.PurgeTaskDLQMessages(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.PurgeTaskDLQMessagesResponse{}
+	}
+	return resp, err
+}
+
+// MergeTaskDLQMessages executes transfer or timer tasks from the dead-letter queue of a shard again
+func (adh *AdminNilCheckHandler) MergeTaskDLQMessages(ctx context.Context, request *adminservice.MergeTaskDLQMessagesRequest) (*adminservice.MergeTaskDLQMessagesResponse, error) {
+	resp, err := adh.parentHandler.MergeTaskDLQMessages(ctx, request)
+	if resp == nil && err == nil {
+		resp = &adminservice.MergeTaskDLQMessagesResponse{}
+	}
+	return resp, err
+}
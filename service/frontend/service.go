@@ -24,7 +24,6 @@ import (
 	"context"
 	"sync/atomic"
 
-	"github.com/stretchr/testify/mock"
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 	"google.golang.org/grpc"
@@ -36,8 +35,6 @@ import (
 	"github.com/temporalio/temporal/common/domain"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
-	"github.com/temporalio/temporal/common/messaging"
-	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
 	espersistence "github.com/temporalio/temporal/common/persistence/elasticsearch"
@@ -92,6 +89,11 @@ type Config struct {
 
 	// VisibilityArchival system protection
 	VisibilityArchivalQueryMaxPageSize dynamicconfig.IntPropertyFn
+
+	// MaxCompletionCallbacks is the max number of completion callbacks per StartWorkflowExecution request
+	MaxCompletionCallbacks dynamicconfig.IntPropertyFnWithDomainFilter
+	// CompletionCallbackAllowedHosts is the list of hosts http completion callbacks may call
+	CompletionCallbackAllowedHosts dynamicconfig.StringPropertyFnWithDomainFilter
 }
 
 // NewConfig returns new service config with default values
@@ -127,6 +129,8 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, enableReadFro
 		MinRetentionDays:                    dc.GetIntProperty(dynamicconfig.MinRetentionDays, domain.MinRetentionDays),
 		VisibilityArchivalQueryMaxPageSize:  dc.GetIntProperty(dynamicconfig.VisibilityArchivalQueryMaxPageSize, 10000),
 		DisallowQuery:                       dc.GetBoolPropertyFnWithDomainFilter(dynamicconfig.DisallowQuery, false),
+		MaxCompletionCallbacks:              dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaxCompletionCallbacks, 8),
+		CompletionCallbackAllowedHosts:      dc.GetStringPropertyFnWithDomainFilter(dynamicconfig.CompletionCallbackAllowedHosts, ""),
 	}
 }
 
@@ -211,28 +215,9 @@ func (s *Service) Start() {
 	logger := s.GetLogger()
 	logger.Info("frontend starting")
 
-	var replicationMessageSink messaging.Producer
-	clusterMetadata := s.GetClusterMetadata()
-	if clusterMetadata.IsGlobalDomainEnabled() {
-		consumerConfig := clusterMetadata.GetReplicationConsumerConfig()
-		if consumerConfig != nil && consumerConfig.Type == config.ReplicationConsumerTypeRPC {
-			replicationMessageSink = s.GetDomainReplicationQueue()
-		} else {
-			var err error
-			replicationMessageSink, err = s.GetMessagingClient().NewProducerWithClusterName(
-				s.GetClusterMetadata().GetCurrentClusterName())
-			if err != nil {
-				logger.Fatal("Creating replicationMessageSink producer failed", tag.Error(err))
-			}
-		}
-	} else {
-		replicationMessageSink = &mocks.KafkaProducer{}
-		replicationMessageSink.(*mocks.KafkaProducer).On("Publish", mock.Anything).Return(nil)
-	}
-
 	s.server = grpc.NewServer(grpc.UnaryInterceptor(interceptor))
 
-	wfHandler := NewWorkflowHandler(s, s.config, replicationMessageSink)
+	wfHandler := NewWorkflowHandler(s, s.config, s.GetDomainReplicationQueue())
 	dcRedirectionHandler := NewDCRedirectionHandler(wfHandler, s.params.DCRedirectionPolicy)
 	accessControlledWorkflowHandler := NewAccessControlledHandlerImpl(dcRedirectionHandler, s.params.Authorizer)
 	workflowNilCheckHandler := NewWorkflowNilCheckHandler(accessControlledWorkflowHandler)
@@ -90,3 +90,17 @@ func deserializeHistoryToken(bytes []byte) (*token.HistoryContinuation, error) {
 	err := token.Unmarshal(bytes)
 	return token, err
 }
+
+func serializeArchivedHistoryToken(token *token.ArchivedHistoryContinuation) ([]byte, error) {
+	if token == nil {
+		return nil, nil
+	}
+
+	return token.Marshal()
+}
+
+func deserializeArchivedHistoryToken(bytes []byte) (*token.ArchivedHistoryContinuation, error) {
+	token := &token.ArchivedHistoryContinuation{}
+	err := token.Unmarshal(bytes)
+	return token, err
+}
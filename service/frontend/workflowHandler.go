@@ -30,6 +30,8 @@ import (
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
@@ -38,12 +40,13 @@ import (
 	"github.com/temporalio/temporal/common/archiver"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/domain"
 	"github.com/temporalio/temporal/common/elasticsearch/validator"
 	"github.com/temporalio/temporal/common/headers"
+	"github.com/temporalio/temporal/common/historyfilter"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
-	"github.com/temporalio/temporal/common/messaging"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/primitives"
@@ -66,6 +69,11 @@ type (
 	}
 )
 
+const (
+	// maxFilteredHistoryReads bounds the history pages read to fill a page of filtered history
+	maxFilteredHistoryReads = 10
+)
+
 var (
 	_                          workflowservice.WorkflowServiceServer = (*WorkflowHandler)(nil)
 	frontendServiceRetryPolicy                                       = common.CreateFrontendServiceRetryPolicy()
@@ -75,7 +83,7 @@ var (
 func NewWorkflowHandler(
 	resource resource.Resource,
 	config *Config,
-	replicationMessageSink messaging.Producer,
+	domainReplicationQueue persistence.DomainReplicationQueue,
 ) *WorkflowHandler {
 	handler := &WorkflowHandler{
 		Resource:        resource,
@@ -96,7 +104,7 @@ func NewWorkflowHandler(
 			resource.GetLogger(),
 			resource.GetMetadataManager(),
 			resource.GetClusterMetadata(),
-			domain.NewDomainReplicator(replicationMessageSink, resource.GetLogger()),
+			domain.NewDomainReplicator(domainReplicationQueue, resource.GetLogger()),
 			resource.GetArchivalMetadata(),
 			resource.GetArchiverProvider(),
 		),
@@ -347,6 +355,17 @@ func (wh *WorkflowHandler) StartWorkflowExecution(ctx context.Context, request *
 		return nil, wh.error(err, scope)
 	}
 
+	completionCallbacks := headers.GetCompletionCallbacks(ctx)
+	parsedCompletionCallbacks, err := callback.ParseAll(completionCallbacks, wh.config.MaxCompletionCallbacks(domainName))
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+	for _, completionCallback := range parsedCompletionCallbacks {
+		if err := callback.CheckAllowed(completionCallback, wh.config.CompletionCallbackAllowedHosts(domainName)); err != nil {
+			return nil, wh.error(err, scope)
+		}
+	}
+
 	wh.GetLogger().Debug("Start workflow execution request domain", tag.WorkflowDomainName(domainName))
 	domainID, err := wh.GetDomainCache().GetDomainID(domainName)
 	if err != nil {
@@ -376,7 +395,9 @@ func (wh *WorkflowHandler) StartWorkflowExecution(ctx context.Context, request *
 	}
 
 	wh.GetLogger().Debug("Start workflow execution request domainID", tag.WorkflowDomainID(domainID))
-	resp, err := wh.GetHistoryClient().StartWorkflowExecution(ctx, common.CreateHistoryStartWorkflowRequest(domainID, request))
+	histRequest := common.CreateHistoryStartWorkflowRequest(domainID, request)
+	histRequest.CompletionCallbacks = completionCallbacks
+	resp, err := wh.GetHistoryClient().StartWorkflowExecution(ctx, histRequest)
 
 	if err != nil {
 		return nil, wh.error(err, scope)
@@ -430,10 +451,15 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 		request.MaximumPageSize = common.GetHistoryMaxPageSize
 	}
 
+	filter, err := historyfilter.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
 	enableArchivalRead := wh.GetArchivalMetadata().GetHistoryConfig().ReadEnabled()
 	historyArchived := wh.historyArchived(ctx, request, domainID)
 	if enableArchivalRead && historyArchived {
-		return wh.getArchivedHistory(ctx, request, domainID, scope)
+		return wh.getArchivedHistory(ctx, request, domainID, filter, scope)
 	}
 
 	// this function return the following 5 things,
@@ -490,6 +516,8 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 		}
 
 		execution.RunId = continuationToken.GetRunId()
+		// the filter of the first page applies to all pages
+		filter = continuationToken.Filter
 
 		// we need to update the current next event ID and whether workflow is running
 		if len(continuationToken.PersistenceToken) == 0 && isLongPoll && continuationToken.IsWorkflowRunning {
@@ -523,6 +551,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 		continuationToken.NextEventId = nextEventID
 		continuationToken.IsWorkflowRunning = isWorkflowRunning
 		continuationToken.PersistenceToken = nil
+		continuationToken.Filter = filter
 	}
 
 	history := &commonproto.History{}
@@ -544,7 +573,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 				return nil, wh.error(err, scope)
 			}
 			// since getHistory func will not return empty history, so the below is safe
-			history.Events = history.Events[len(history.Events)-1 : len(history.Events)]
+			history.Events = historyfilter.Apply(filter, history.Events[len(history.Events)-1:len(history.Events)])
 			continuationToken = nil
 		} else if isLongPoll {
 			// set the persistence token to be nil so next time we will query history for updates
@@ -553,15 +582,35 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 			continuationToken = nil
 		}
 	} else {
+		if filter != nil && len(continuationToken.PersistenceToken) == 0 && continuationToken.FirstEventId < continuationToken.NextEventId {
+			// skip the history outside of the event ID range of the filter
+			firstEventID, nextEventID, err := wh.clampHistoryEventIDRange(
+				*execution,
+				continuationToken.BranchToken,
+				continuationToken.FirstEventId,
+				continuationToken.NextEventId,
+				filter,
+			)
+			if err != nil {
+				return nil, wh.error(err, scope)
+			}
+			if nextEventID < continuationToken.NextEventId {
+				// the transient decision events are beyond the max event ID of the filter
+				continuationToken.TransientDecision = nil
+			}
+			continuationToken.FirstEventId = firstEventID
+			continuationToken.NextEventId = nextEventID
+		}
+
 		// return all events
 		if continuationToken.FirstEventId >= continuationToken.NextEventId {
 			// currently there is no new event
 			history.Events = []*commonproto.HistoryEvent{}
-			if !isWorkflowRunning {
+			if !isWorkflowRunning || !isLongPoll {
 				continuationToken = nil
 			}
 		} else {
-			history, continuationToken.PersistenceToken, err = wh.getHistory(
+			history, continuationToken.PersistenceToken, err = wh.getFilteredHistory(
 				scope,
 				domainID,
 				*execution,
@@ -571,6 +620,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 				continuationToken.PersistenceToken,
 				continuationToken.TransientDecision,
 				continuationToken.BranchToken,
+				filter,
 			)
 			if err != nil {
 				return nil, wh.error(err, scope)
@@ -578,7 +628,8 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(ctx context.Context, requ
 
 			// here, for long pull on history events, we need to intercept the paging token from cassandra
 			// and do something clever
-			if len(continuationToken.PersistenceToken) == 0 && (!continuationToken.IsWorkflowRunning || !isLongPoll) {
+			if len(continuationToken.PersistenceToken) == 0 &&
+				(!continuationToken.IsWorkflowRunning || !isLongPoll || historyfilter.IsPastMaxEventID(filter, continuationToken.NextEventId)) {
 				// meaning, there is no more history to be returned
 				continuationToken = nil
 			}
@@ -742,9 +793,10 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(ctx context.Context, req
 	defer sw.Stop()
 
 	histResp, err := wh.GetHistoryClient().RespondDecisionTaskCompleted(ctx, &historyservice.RespondDecisionTaskCompletedRequest{
-		DomainUUID:      domainId,
-		CompleteRequest: request},
-	)
+		DomainUUID:            domainId,
+		CompleteRequest:       request,
+		MaxEagerActivityTasks: int32(headers.GetMaxEagerActivityTasks(ctx)),
+	})
 	if err != nil {
 		return nil, wh.error(err, scope)
 	}
@@ -776,9 +828,139 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(ctx context.Context, req
 		completedResp.DecisionTask = newDecisionTask
 	}
 
+	if scheduleIDs := histResp.GetEagerActivityScheduleIds(); len(scheduleIDs) > 0 {
+		wh.returnEagerActivityTasks(ctx, domainEntry, taskToken, request.GetIdentity(), scheduleIDs)
+	}
+
 	return completedResp, nil
 }
 
+// returnEagerActivityTasks starts the activities the worker asked to receive together with the completion of
+// its decision, and returns them in the response header. The activities are only started once they can be
+// returned, those which fail to start, e.g. because they were dispatched through matching already, are left
+// to the normal dispatch through matching.
+func (wh *WorkflowHandler) returnEagerActivityTasks(
+	ctx context.Context,
+	domainEntry *cache.DomainCacheEntry,
+	decisionToken *token.Task,
+	identity string,
+	scheduleIDs []int64,
+) {
+
+	if grpc.ServerTransportStreamFromContext(ctx) == nil {
+		// the activity tasks cannot be returned without a response header
+		return
+	}
+
+	execution := &commonproto.WorkflowExecution{
+		WorkflowId: decisionToken.GetWorkflowId(),
+		RunId:      primitives.UUIDString(decisionToken.GetRunId()),
+	}
+	pairs := make([]string, 0, 2*len(scheduleIDs))
+	for _, scheduleID := range scheduleIDs {
+		resp, err := wh.GetHistoryClient().RecordActivityTaskStarted(ctx, &historyservice.RecordActivityTaskStartedRequest{
+			DomainUUID:        domainEntry.GetInfo().ID,
+			WorkflowExecution: execution,
+			ScheduleId:        scheduleID,
+			RequestId:         uuid.New(),
+			PollRequest: &workflowservice.PollForActivityTaskRequest{
+				Domain:   domainEntry.GetInfo().Name,
+				Identity: identity,
+			},
+		})
+		if err != nil {
+			switch err.(type) {
+			case *serviceerror.NotFound, *serviceerror.EventAlreadyStarted:
+			default:
+				wh.GetLogger().Warn("Failed to start eager activity task.",
+					tag.WorkflowDomainID(domainEntry.GetInfo().ID), tag.WorkflowID(execution.GetWorkflowId()),
+					tag.WorkflowScheduleID(scheduleID), tag.Error(err))
+			}
+			continue
+		}
+
+		task, err := wh.createEagerActivityTask(decisionToken, execution, scheduleID, resp)
+		if err == nil {
+			var blob []byte
+			if blob, err = task.Marshal(); err == nil {
+				pairs = append(pairs, headers.EagerActivityTaskHeaderName, string(blob))
+				continue
+			}
+		}
+		// the activity is started already, it is retried once its start to close timeout fires
+		wh.GetLogger().Warn("Failed to return eager activity task.",
+			tag.WorkflowDomainID(domainEntry.GetInfo().ID), tag.WorkflowID(execution.GetWorkflowId()),
+			tag.WorkflowScheduleID(scheduleID), tag.Error(err))
+	}
+
+	if len(pairs) == 0 {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(pairs...)); err != nil {
+		wh.GetLogger().Warn("Failed to return eager activity tasks.",
+			tag.WorkflowDomainID(domainEntry.GetInfo().ID), tag.WorkflowID(execution.GetWorkflowId()), tag.Error(err))
+	}
+}
+
+func (wh *WorkflowHandler) createEagerActivityTask(
+	decisionToken *token.Task,
+	execution *commonproto.WorkflowExecution,
+	scheduleID int64,
+	historyResponse *historyservice.RecordActivityTaskStartedResponse,
+) (*workflowservice.PollForActivityTaskResponse, error) {
+
+	scheduledEvent := historyResponse.GetScheduledEvent()
+	attributes := scheduledEvent.GetActivityTaskScheduledEventAttributes()
+	taskToken, err := wh.tokenSerializer.Serialize(&token.Task{
+		DomainId:        decisionToken.GetDomainId(),
+		WorkflowId:      decisionToken.GetWorkflowId(),
+		RunId:           decisionToken.GetRunId(),
+		ScheduleId:      scheduleID,
+		ScheduleAttempt: historyResponse.GetAttempt(),
+		ActivityId:      attributes.GetActivityId(),
+		ActivityType:    attributes.GetActivityType().GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflowservice.PollForActivityTaskResponse{
+		TaskToken:                       taskToken,
+		WorkflowExecution:               execution,
+		ActivityId:                      attributes.GetActivityId(),
+		ActivityType:                    attributes.GetActivityType(),
+		Header:                          attributes.GetHeader(),
+		Input:                           attributes.GetInput(),
+		ScheduledTimestamp:              scheduledEvent.GetTimestamp(),
+		ScheduleToCloseTimeoutSeconds:   attributes.GetScheduleToCloseTimeoutSeconds(),
+		StartedTimestamp:                historyResponse.GetStartedTimestamp(),
+		StartToCloseTimeoutSeconds:      attributes.GetStartToCloseTimeoutSeconds(),
+		HeartbeatTimeoutSeconds:         attributes.GetHeartbeatTimeoutSeconds(),
+		Attempt:                         int32(historyResponse.GetAttempt()),
+		ScheduledTimestampOfThisAttempt: historyResponse.GetScheduledTimestampOfThisAttempt(),
+		HeartbeatDetails:                historyResponse.GetHeartbeatDetails(),
+		WorkflowType:                    historyResponse.GetWorkflowType(),
+		WorkflowDomain:                  historyResponse.GetWorkflowDomain(),
+	}, nil
+}
+
+// setActivityConcurrency returns the activity concurrency of the task list in the
+// response header as the public DescribeTaskListResponse has no field for it.
+func (wh *WorkflowHandler) setActivityConcurrency(
+	ctx context.Context,
+	activityConcurrency []*matchingservice.ActivityConcurrencyInfo,
+) error {
+	pairs := make([]string, 0, 2*len(activityConcurrency))
+	for _, info := range activityConcurrency {
+		blob, err := info.Marshal()
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, headers.ActivityConcurrencyHeaderName, string(blob))
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(pairs...))
+}
+
 // RespondDecisionTaskFailed is called by application worker to indicate failure.  This results in
 // DecisionTaskFailedEvent written to the history and a new DecisionTask created.  This API can be used by client to
 // either clear sticky tasklist or report any panics during DecisionTask processing.  Cadence will only append first
@@ -2811,6 +2993,13 @@ func (wh *WorkflowHandler) DescribeTaskList(ctx context.Context, request *workfl
 		return nil, wh.error(err, scope)
 	}
 
+	if len(matchingResponse.ActivityConcurrency) > 0 {
+		if err := wh.setActivityConcurrency(ctx, matchingResponse.ActivityConcurrency); err != nil {
+			wh.GetLogger().Warn("Failed to return activity concurrency.",
+				tag.WorkflowDomainID(domainID), tag.WorkflowTaskListName(request.TaskList.GetName()), tag.Error(err))
+		}
+	}
+
 	return &workflowservice.DescribeTaskListResponse{
 		Pollers:        matchingResponse.Pollers,
 		TaskListStatus: matchingResponse.TaskListStatus,
@@ -3225,7 +3414,7 @@ func (wh *WorkflowHandler) getRawHistory(
 	branchToken []byte,
 ) ([]*commonproto.DataBlob, []byte, error) {
 	var rawHistory []*commonproto.DataBlob
-	shardID := common.WorkflowIDToHistoryShard(execution.GetWorkflowId(), wh.config.NumHistoryShards)
+	shardID := wh.GetHistoryShardRouter().GetShardID(execution.GetWorkflowId())
 
 	resp, err := wh.GetHistoryManager().ReadRawHistoryBranch(&persistence.ReadHistoryBranchRequest{
 		BranchToken:   branchToken,
@@ -3301,7 +3490,7 @@ func (wh *WorkflowHandler) getHistory(
 	var size int
 
 	isFirstPage := len(nextPageToken) == 0
-	shardID := common.WorkflowIDToHistoryShard(execution.GetWorkflowId(), wh.config.NumHistoryShards)
+	shardID := wh.GetHistoryShardRouter().GetShardID(execution.GetWorkflowId())
 	var err error
 	var historyEvents []*commonproto.HistoryEvent
 	historyEvents, size, nextPageToken, err = persistence.ReadFullPageV2Events(wh.GetHistoryManager(), &persistence.ReadHistoryBranchRequest{
@@ -3353,6 +3542,123 @@ func (wh *WorkflowHandler) getHistory(
 	return executionHistory, nextPageToken, nil
 }
 
+// getFilteredHistory reads history pages until a page worth of events accepted by the filter is collected,
+// the history is exhausted or maxFilteredHistoryReads pages were read.
+func (wh *WorkflowHandler) getFilteredHistory(
+	scope metrics.Scope,
+	domainID string,
+	execution commonproto.WorkflowExecution,
+	firstEventID, nextEventID int64,
+	pageSize int32,
+	nextPageToken []byte,
+	transientDecision *commonproto.TransientDecisionInfo,
+	branchToken []byte,
+	filter *token.HistoryEventFilter,
+) (*commonproto.History, []byte, error) {
+
+	if filter == nil {
+		return wh.getHistory(scope, domainID, execution, firstEventID, nextEventID, pageSize, nextPageToken, transientDecision, branchToken)
+	}
+
+	filteredHistory := &commonproto.History{Events: []*commonproto.HistoryEvent{}}
+	for reads := 0; reads < maxFilteredHistoryReads; reads++ {
+		history, pageToken, err := wh.getHistory(scope, domainID, execution, firstEventID, nextEventID, pageSize, nextPageToken, transientDecision, branchToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		nextPageToken = pageToken
+		filteredHistory.Events = append(filteredHistory.Events, historyfilter.Apply(filter, history.Events)...)
+
+		if len(history.Events) > 0 && historyfilter.IsPastMaxEventID(filter, history.Events[len(history.Events)-1].GetEventId()+1) {
+			// the remaining events are all beyond the max event ID of the filter
+			return filteredHistory, nil, nil
+		}
+		if len(filteredHistory.Events) >= int(pageSize) || len(nextPageToken) == 0 {
+			break
+		}
+	}
+	return filteredHistory, nextPageToken, nil
+}
+
+// clampHistoryEventIDRange narrows the event ID range [firstEventID, nextEventID) to the batches holding
+// the events in the event ID range of the filter, so the history outside of it is not read.
+func (wh *WorkflowHandler) clampHistoryEventIDRange(
+	execution commonproto.WorkflowExecution,
+	branchToken []byte,
+	firstEventID, nextEventID int64,
+	filter *token.HistoryEventFilter,
+) (int64, int64, error) {
+
+	shardID := wh.GetHistoryShardRouter().GetShardID(execution.GetWorkflowId())
+	if filter.MaxEventId > 0 && filter.MaxEventId+1 < nextEventID {
+		// the batch holding the max event ID ends right before the first batch after it
+		batchFirstEventIDs, err := wh.readHistoryBatchFirstEventIDs(shardID, branchToken, filter.MaxEventId+1, nextEventID, 1)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(batchFirstEventIDs) > 0 {
+			nextEventID = batchFirstEventIDs[0]
+		}
+	}
+
+	if filter.MinEventId >= nextEventID {
+		return nextEventID, nextEventID, nil
+	}
+	if filter.MinEventId > firstEventID {
+		// look back for the first event of the batch holding the min event ID, batches are usually small
+		for lookBack := int64(1); ; lookBack *= 2 {
+			minEventID := common.MaxInt64(firstEventID, filter.MinEventId-lookBack+1)
+			batchFirstEventIDs, err := wh.readHistoryBatchFirstEventIDs(shardID, branchToken, minEventID, filter.MinEventId+1, int(lookBack))
+			if err != nil {
+				return 0, 0, err
+			}
+			if len(batchFirstEventIDs) > 0 {
+				firstEventID = batchFirstEventIDs[len(batchFirstEventIDs)-1]
+				break
+			}
+			if minEventID == firstEventID {
+				break
+			}
+		}
+	}
+	return firstEventID, nextEventID, nil
+}
+
+// readHistoryBatchFirstEventIDs returns the first event IDs of the history batches starting in [minEventID, maxEventID),
+// at most one page of batches is read if pageSize is 1.
+func (wh *WorkflowHandler) readHistoryBatchFirstEventIDs(
+	shardID int,
+	branchToken []byte,
+	minEventID, maxEventID int64,
+	pageSize int,
+) ([]int64, error) {
+
+	var batchFirstEventIDs []int64
+	request := &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  minEventID,
+		MaxEventID:  maxEventID,
+		PageSize:    pageSize,
+		ShardID:     common.IntPtr(shardID),
+	}
+	for {
+		response, err := wh.GetHistoryManager().ReadHistoryBranchByBatch(request)
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return batchFirstEventIDs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, batch := range response.History {
+			batchFirstEventIDs = append(batchFirstEventIDs, batch.Events[0].GetEventId())
+		}
+		if len(response.NextPageToken) == 0 || pageSize == 1 {
+			return batchFirstEventIDs, nil
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+}
+
 func (wh *WorkflowHandler) validateTransientDecisionEvents(
 	expectedNextEventID int64,
 	decision *commonproto.TransientDecisionInfo,
@@ -3637,8 +3943,20 @@ func (wh *WorkflowHandler) getArchivedHistory(
 	ctx context.Context,
 	request *workflowservice.GetWorkflowExecutionHistoryRequest,
 	domainID string,
+	filter *token.HistoryEventFilter,
 	scope metrics.Scope,
 ) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+	var archiverToken []byte
+	if request.NextPageToken != nil {
+		continuationToken, err := deserializeArchivedHistoryToken(request.NextPageToken)
+		if err != nil {
+			return nil, wh.error(errInvalidNextPageToken, scope)
+		}
+		archiverToken = continuationToken.ArchiverToken
+		// the filter of the first page applies to all pages
+		filter = continuationToken.Filter
+	}
+
 	entry, err := wh.GetDomainCache().GetDomainByID(domainID)
 	if err != nil {
 		return nil, wh.error(err, scope)
@@ -3666,7 +3984,7 @@ func (wh *WorkflowHandler) getArchivedHistory(
 		DomainID:      domainID,
 		WorkflowID:    request.GetExecution().GetWorkflowId(),
 		RunID:         request.GetExecution().GetRunId(),
-		NextPageToken: request.GetNextPageToken(),
+		NextPageToken: archiverToken,
 		PageSize:      int(request.GetMaximumPageSize()),
 	})
 	if err != nil {
@@ -3677,9 +3995,23 @@ func (wh *WorkflowHandler) getArchivedHistory(
 	for _, batch := range resp.HistoryBatches {
 		history.Events = append(history.Events, batch.Events...)
 	}
+
+	var continuationToken *token.ArchivedHistoryContinuation
+	if len(resp.NextPageToken) > 0 &&
+		(len(history.Events) == 0 || !historyfilter.IsPastMaxEventID(filter, history.Events[len(history.Events)-1].GetEventId()+1)) {
+		continuationToken = &token.ArchivedHistoryContinuation{
+			ArchiverToken: resp.NextPageToken,
+			Filter:        filter,
+		}
+	}
+	nextToken, err := serializeArchivedHistoryToken(continuationToken)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+	history.Events = historyfilter.Apply(filter, history.Events)
 	return &workflowservice.GetWorkflowExecutionHistoryResponse{
 		History:       history,
-		NextPageToken: resp.NextPageToken,
+		NextPageToken: nextToken,
 		Archived:      true,
 	}, nil
 }
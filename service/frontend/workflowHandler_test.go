@@ -37,6 +37,7 @@ import (
 	"go.temporal.io/temporal-proto/workflowservice"
 
 	"github.com/temporalio/temporal/.gen/proto/historyservicemock"
+	"github.com/temporalio/temporal/.gen/proto/token"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/archiver"
 	"github.com/temporalio/temporal/common/archiver/provider"
@@ -129,7 +130,7 @@ func (s *workflowHandlerSuite) TearDownTest() {
 }
 
 func (s *workflowHandlerSuite) getWorkflowHandler(config *Config) *WorkflowHandler {
-	return NewWorkflowHandler(s.mockResource, config, s.mockProducer)
+	return NewWorkflowHandler(s.mockResource, config, s.mockResource.DomainReplicationQueue)
 }
 
 func (s *workflowHandlerSuite) TestDisableListVisibilityByFilter() {
@@ -865,7 +866,7 @@ func (s *workflowHandlerSuite) TestGetArchivedHistory_Failure_DomainCacheEntryEr
 
 	wh := s.getWorkflowHandler(s.newConfig())
 
-	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, metrics.NoopScope(metrics.Frontend))
+	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, nil, metrics.NoopScope(metrics.Frontend))
 	s.Nil(resp)
 	s.Error(err)
 }
@@ -885,7 +886,7 @@ func (s *workflowHandlerSuite) TestGetArchivedHistory_Failure_ArchivalURIEmpty()
 
 	wh := s.getWorkflowHandler(s.newConfig())
 
-	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, metrics.NoopScope(metrics.Frontend))
+	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, nil, metrics.NoopScope(metrics.Frontend))
 	s.Nil(resp)
 	s.Error(err)
 }
@@ -905,7 +906,7 @@ func (s *workflowHandlerSuite) TestGetArchivedHistory_Failure_InvalidURI() {
 
 	wh := s.getWorkflowHandler(s.newConfig())
 
-	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, metrics.NoopScope(metrics.Frontend))
+	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, nil, metrics.NoopScope(metrics.Frontend))
 	s.Nil(resp)
 	s.Error(err)
 }
@@ -948,12 +949,59 @@ func (s *workflowHandlerSuite) TestGetArchivedHistory_Success_GetFirstPage() {
 
 	wh := s.getWorkflowHandler(s.newConfig())
 
-	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, metrics.NoopScope(metrics.Frontend))
+	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nil), s.testDomainID, nil, metrics.NoopScope(metrics.Frontend))
 	s.NoError(err)
 	s.NotNil(resp)
 	s.NotNil(resp.History)
 	s.Equal(history, resp.History)
-	s.Equal(nextPageToken, resp.NextPageToken)
+	continuationToken, err := deserializeArchivedHistoryToken(resp.NextPageToken)
+	s.NoError(err)
+	s.Equal(nextPageToken, continuationToken.ArchiverToken)
+	s.True(resp.GetArchived())
+}
+
+func (s *workflowHandlerSuite) TestGetArchivedHistory_Success_FilterFromNextPageToken() {
+	domainEntry := cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{Name: "test-domain"},
+		&persistence.DomainConfig{
+			HistoryArchivalStatus:    enums.ArchivalStatusEnabled,
+			HistoryArchivalURI:       testHistoryArchivalURI,
+			VisibilityArchivalStatus: enums.ArchivalStatusDisabled,
+			VisibilityArchivalURI:    "",
+		},
+		"",
+		nil)
+	s.mockDomainCache.EXPECT().GetDomainByID(gomock.Any()).Return(domainEntry, nil).AnyTimes()
+
+	archiverToken := []byte{'1', '2', '3'}
+	historyBatch := &commonproto.History{
+		Events: []*commonproto.HistoryEvent{
+			{EventId: 3},
+			{EventId: 4},
+			{EventId: 5},
+		},
+	}
+	s.mockHistoryArchiver.On("Get", mock.Anything, mock.Anything, mock.MatchedBy(func(request *archiver.GetHistoryRequest) bool {
+		return string(request.NextPageToken) == string(archiverToken)
+	})).Return(&archiver.GetHistoryResponse{
+		NextPageToken:  []byte{'4', '5', '6'},
+		HistoryBatches: []*commonproto.History{historyBatch},
+	}, nil)
+	s.mockArchiverProvider.On("GetHistoryArchiver", mock.Anything, mock.Anything).Return(s.mockHistoryArchiver, nil)
+
+	nextPageToken, err := serializeArchivedHistoryToken(&token.ArchivedHistoryContinuation{
+		ArchiverToken: archiverToken,
+		Filter:        &token.HistoryEventFilter{MaxEventId: 4},
+	})
+	s.NoError(err)
+
+	wh := s.getWorkflowHandler(s.newConfig())
+
+	resp, err := wh.getArchivedHistory(context.Background(), getHistoryRequest(nextPageToken), s.testDomainID, nil, metrics.NoopScope(metrics.Frontend))
+	s.NoError(err)
+	s.Equal([]*commonproto.HistoryEvent{{EventId: 3}, {EventId: 4}}, resp.History.Events)
+	// the remaining events are all beyond the max event ID of the filter
+	s.Nil(resp.NextPageToken)
 	s.True(resp.GetArchived())
 }
 
@@ -995,6 +1043,74 @@ func (s *workflowHandlerSuite) TestGetHistory() {
 	s.Equal([]byte{}, token)
 }
 
+func (s *workflowHandlerSuite) TestClampHistoryEventIDRange() {
+	branchToken := []byte{1}
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wid",
+		RunId:      "rid",
+	}
+	shardID := common.WorkflowIDToHistoryShard(we.WorkflowId, numHistoryShards)
+	batch := func(firstEventID, nextEventID int64) *commonproto.History {
+		history := &commonproto.History{}
+		for eventID := firstEventID; eventID < nextEventID; eventID++ {
+			history.Events = append(history.Events, &commonproto.HistoryEvent{EventId: eventID})
+		}
+		return history
+	}
+	// batches [1-2] [3-6] [7-7] [8-10], the filter selects events 5 to 7
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  8,
+		MaxEventID:  11,
+		PageSize:    1,
+		ShardID:     common.IntPtr(shardID),
+	}).Return(&persistence.ReadHistoryBranchByBatchResponse{
+		History:       []*commonproto.History{batch(8, 11)},
+		NextPageToken: []byte{1},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  5,
+		MaxEventID:  6,
+		PageSize:    1,
+		ShardID:     common.IntPtr(shardID),
+	}).Return(nil, serviceerror.NewNotFound("Workflow execution history not found.")).Once()
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  4,
+		MaxEventID:  6,
+		PageSize:    2,
+		ShardID:     common.IntPtr(shardID),
+	}).Return(nil, serviceerror.NewNotFound("Workflow execution history not found.")).Once()
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  2,
+		MaxEventID:  6,
+		PageSize:    4,
+		ShardID:     common.IntPtr(shardID),
+	}).Return(&persistence.ReadHistoryBranchByBatchResponse{
+		History: []*commonproto.History{batch(3, 7)},
+	}, nil).Once()
+
+	wh := s.getWorkflowHandler(s.newConfig())
+
+	firstEventID, nextEventID, err := wh.clampHistoryEventIDRange(we, branchToken, common.FirstEventID, 11, &token.HistoryEventFilter{
+		MinEventId: 5,
+		MaxEventId: 7,
+	})
+	s.NoError(err)
+	s.Equal(int64(3), firstEventID)
+	s.Equal(int64(8), nextEventID)
+
+	// no event is in the event ID range of the filter yet
+	firstEventID, nextEventID, err = wh.clampHistoryEventIDRange(we, branchToken, common.FirstEventID, 11, &token.HistoryEventFilter{
+		MinEventId: 20,
+	})
+	s.NoError(err)
+	s.Equal(int64(11), firstEventID)
+	s.Equal(int64(11), nextEventID)
+}
+
 func (s *workflowHandlerSuite) TestListArchivedVisibility_Failure_InvalidRequest() {
 	wh := s.getWorkflowHandler(s.newConfig())
 
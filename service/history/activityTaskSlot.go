@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/client/matching"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+const (
+	// activityTaskSlotReleaseTimeout is the timeout of releasing the concurrency slots held by activities in matching
+	activityTaskSlotReleaseTimeout = 5 * time.Second
+)
+
+// newReleaseActivityTaskSlotRequest returns the request releasing the concurrency slot held by the activity in
+// matching, or nil if the activity type is not concurrency limited. It has to be called while the activity is
+// still pending since the activity type is read from its scheduled event.
+func newReleaseActivityTaskSlotRequest(
+	shard ShardContext,
+	mutableState mutableState,
+	ai *persistence.ActivityInfo,
+) (*matchingservice.ReleaseActivityTaskSlotRequest, error) {
+
+	domainEntry := mutableState.GetDomainEntry()
+	if ai.DomainID != domainEntry.GetInfo().ID {
+		var err error
+		if domainEntry, err = shard.GetDomainCache().GetDomainByID(ai.DomainID); err != nil {
+			return nil, err
+		}
+	}
+	limits := shard.GetConfig().ActivityConcurrencyLimits(
+		dynamicconfig.DomainFilter(domainEntry.GetInfo().Name),
+		dynamicconfig.TaskListFilter(ai.TaskList),
+	)
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	scheduledEvent, err := mutableState.GetActivityScheduledEvent(ai.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	activityType := scheduledEvent.GetActivityTaskScheduledEventAttributes().GetActivityType().GetName()
+	if _, ok := limits[activityType]; !ok {
+		return nil, nil
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	return &matchingservice.ReleaseActivityTaskSlotRequest{
+		DomainUUID: ai.DomainID,
+		TaskList:   &commonproto.TaskList{Name: ai.TaskList, Kind: enums.TaskListKindNormal},
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: executionInfo.WorkflowID,
+			RunId:      executionInfo.RunID,
+		},
+		ScheduleId:   ai.ScheduleID,
+		ActivityType: activityType,
+	}, nil
+}
+
+// releaseActivityTaskSlots releases the concurrency slots held by activities in matching
+func releaseActivityTaskSlots(
+	matchingClient matching.Client,
+	requests []*matchingservice.ReleaseActivityTaskSlotRequest,
+) error {
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), activityTaskSlotReleaseTimeout)
+	defer cancel()
+	for _, request := range requests {
+		if _, err := matchingClient.ReleaseActivityTaskSlot(ctx, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
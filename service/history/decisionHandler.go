@@ -40,6 +40,7 @@ import (
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
 type (
@@ -366,6 +367,7 @@ Update_History_Loop:
 			failDecision                *failDecisionInfo
 			activityNotStartedCancelled bool
 			continueAsNewBuilder        mutableState
+			scheduledActivities         []*persistence.ActivityInfo
 
 			hasUnhandledEvents bool
 		)
@@ -438,6 +440,8 @@ Update_History_Loop:
 			continueAsNewBuilder = decisionTaskHandler.continueAsNewBuilder
 
 			hasUnhandledEvents = decisionTaskHandler.hasUnhandledEventsBeforeDecisions
+
+			scheduledActivities = decisionTaskHandler.scheduledActivities
 		}
 
 		if failDecision != nil {
@@ -454,6 +458,19 @@ Update_History_Loop:
 			continueAsNewBuilder = nil
 		}
 
+		var eagerActivityScheduleIDs []int64
+		if failDecision == nil && continueAsNewBuilder == nil && !decisionHeartbeatTimeout && msBuilder.IsWorkflowExecutionRunning() {
+			eagerActivityScheduleIDs, err = handler.getEagerActivityScheduleIDs(
+				msBuilder,
+				scheduledActivities,
+				int(req.GetMaxEagerActivityTasks()),
+				domainEntry.GetInfo().Name,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		createNewDecisionTask := msBuilder.IsWorkflowExecutionRunning() && (hasUnhandledEvents || request.GetForceCreateNewDecisionTask() || activityNotStartedCancelled)
 		var newDecisionTaskScheduledID int64
 		if createNewDecisionTask {
@@ -571,6 +588,10 @@ Update_History_Loop:
 			// sticky is always enabled when worker request for new decision task from RespondDecisionTaskCompleted
 			resp.StartedResponse.StickyExecutionEnabled = true
 		}
+		if len(eagerActivityScheduleIDs) > 0 {
+			handler.metricsClient.AddCounter(metrics.HistoryRespondDecisionTaskCompletedScope, metrics.EagerActivityDispatchCounter, int64(len(eagerActivityScheduleIDs)))
+			resp.EagerActivityScheduleIds = eagerActivityScheduleIDs
+		}
 
 		return resp, nil
 	}
@@ -578,6 +599,51 @@ Update_History_Loop:
 	return nil, ErrMaxAttemptsExceeded
 }
 
+// getEagerActivityScheduleIDs returns the activities scheduled by the decision which the worker completing the
+// decision asked to receive right away. The activities are not started here, they are started by the frontend
+// before they are returned to the worker, and are dispatched through matching as usual if they fail to start.
+// Only activities scheduled on the workflow task list are eligible since those are the ones the worker is polling.
+func (handler *decisionHandlerImpl) getEagerActivityScheduleIDs(
+	msBuilder mutableState,
+	scheduledActivities []*persistence.ActivityInfo,
+	maxTasks int,
+	domainName string,
+) ([]int64, error) {
+
+	maxTasks = common.MinInt(maxTasks, handler.config.EagerActivityDispatchLimit(domainName))
+	if maxTasks <= 0 {
+		return nil, nil
+	}
+
+	executionInfo := msBuilder.GetExecutionInfo()
+	concurrencyLimits := handler.config.ActivityConcurrencyLimits(
+		dynamicconfig.DomainFilter(domainName),
+		dynamicconfig.TaskListFilter(executionInfo.TaskList),
+	)
+	var scheduleIDs []int64
+	for _, ai := range scheduledActivities {
+		if len(scheduleIDs) >= maxTasks {
+			break
+		}
+		if ai.DomainID != executionInfo.DomainID || ai.TaskList != executionInfo.TaskList {
+			continue
+		}
+		if len(concurrencyLimits) > 0 {
+			// activities of a concurrency limited type have to be dispatched by matching which holds their slots
+			scheduledEvent, err := msBuilder.GetActivityScheduledEvent(ai.ScheduleID)
+			if err != nil {
+				return nil, err
+			}
+			activityType := scheduledEvent.GetActivityTaskScheduledEventAttributes().GetActivityType().GetName()
+			if _, ok := concurrencyLimits[activityType]; ok {
+				continue
+			}
+		}
+		scheduleIDs = append(scheduleIDs, ai.ScheduleID)
+	}
+	return scheduleIDs, nil
+}
+
 func (handler *decisionHandlerImpl) createRecordDecisionTaskStartedResponse(
 	domainID string,
 	msBuilder mutableState,
@@ -34,6 +34,7 @@ import (
 	"github.com/temporalio/temporal/common/log/loggerimpl"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
 type (
@@ -141,3 +142,56 @@ func (s *DecisionHandlerSuite) assertQueryCounts(queryRegistry queryRegistry, bu
 	s.Len(queryRegistry.getUnblockedIDs(), unblocked)
 	s.Len(queryRegistry.getFailedIDs(), failed)
 }
+
+func TestGetEagerActivityScheduleIDs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	config := NewDynamicConfigForTest()
+	config.EagerActivityDispatchLimit = dynamicconfig.GetIntPropertyFilteredByDomain(2)
+	handler := &decisionHandlerImpl{config: config}
+
+	mockMutableState := NewMockmutableState(controller)
+	mockMutableState.EXPECT().GetExecutionInfo().Return(&persistence.WorkflowExecutionInfo{
+		DomainID: testDomainID,
+		TaskList: "some random tasklist",
+	}).AnyTimes()
+
+	scheduledActivities := []*persistence.ActivityInfo{
+		{ScheduleID: 5, DomainID: testDomainID, TaskList: "some random tasklist"},
+		{ScheduleID: 6, DomainID: testDomainID, TaskList: "some other tasklist"},
+		{ScheduleID: 7, DomainID: testTargetDomainID, TaskList: "some random tasklist"},
+		{ScheduleID: 8, DomainID: testDomainID, TaskList: "some random tasklist"},
+		{ScheduleID: 9, DomainID: testDomainID, TaskList: "some random tasklist"},
+	}
+
+	// the worker did not opt in
+	scheduleIDs, err := handler.getEagerActivityScheduleIDs(mockMutableState, scheduledActivities, 0, testDomainName)
+	require.NoError(t, err)
+	require.Nil(t, scheduleIDs)
+	// the limit of the domain and the worker request are both applied
+	scheduleIDs, err = handler.getEagerActivityScheduleIDs(mockMutableState, scheduledActivities, 1, testDomainName)
+	require.NoError(t, err)
+	require.Equal(t, []int64{5}, scheduleIDs)
+	scheduleIDs, err = handler.getEagerActivityScheduleIDs(mockMutableState, scheduledActivities, 10, testDomainName)
+	require.NoError(t, err)
+	require.Equal(t, []int64{5, 8}, scheduleIDs)
+
+	// activities of a concurrency limited type are left to matching
+	config.ActivityConcurrencyLimits = dynamicconfig.GetMapPropertyFn(map[string]interface{}{"limited-activity-type": 1})
+	for scheduleID, activityType := range map[int64]string{5: "limited-activity-type", 8: "some random activity type", 9: "some random activity type"} {
+		mockMutableState.EXPECT().GetActivityScheduledEvent(scheduleID).Return(&commonproto.HistoryEvent{
+			Attributes: &commonproto.HistoryEvent_ActivityTaskScheduledEventAttributes{ActivityTaskScheduledEventAttributes: &commonproto.ActivityTaskScheduledEventAttributes{
+				ActivityType: &commonproto.ActivityType{Name: activityType},
+			}},
+		}, nil).AnyTimes()
+	}
+	scheduleIDs, err = handler.getEagerActivityScheduleIDs(mockMutableState, scheduledActivities, 10, testDomainName)
+	require.NoError(t, err)
+	require.Equal(t, []int64{8, 9}, scheduleIDs)
+
+	config.EagerActivityDispatchLimit = dynamicconfig.GetIntPropertyFilteredByDomain(0)
+	scheduleIDs, err = handler.getEagerActivityScheduleIDs(mockMutableState, scheduledActivities, 10, testDomainName)
+	require.NoError(t, err)
+	require.Nil(t, scheduleIDs)
+}
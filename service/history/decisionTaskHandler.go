@@ -34,6 +34,7 @@ import (
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
 )
 
 type (
@@ -49,6 +50,7 @@ type (
 		failDecisionInfo                  *failDecisionInfo
 		activityNotStartedCancelled       bool
 		continueAsNewBuilder              mutableState
+		scheduledActivities               []*persistence.ActivityInfo
 		stopProcessing                    bool // should stop processing any more decisions
 		mutableState                      mutableState
 
@@ -217,9 +219,10 @@ func (handler *decisionTaskHandlerImpl) handleDecisionScheduleActivity(
 		return err
 	}
 
-	_, _, err = handler.mutableState.AddActivityTaskScheduledEvent(handler.decisionTaskCompletedID, attr)
+	_, ai, err := handler.mutableState.AddActivityTaskScheduledEvent(handler.decisionTaskCompletedID, attr)
 	switch err.(type) {
 	case nil:
+		handler.scheduledActivities = append(handler.scheduledActivities, ai)
 		return nil
 	case *serviceerror.InvalidArgument:
 		return handler.handlerFailDecision(
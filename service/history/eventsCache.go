@@ -59,6 +59,7 @@ type (
 			runID string,
 			eventID int64,
 		)
+		purge()
 	}
 
 	eventsCacheImpl struct {
@@ -88,25 +89,32 @@ func newEventsCache(shardCtx ShardContext) eventsCache {
 	config := shardCtx.GetConfig()
 	shardID := common.IntPtr(shardCtx.GetShardID())
 	return newEventsCacheWithOptions(config.EventsCacheInitialSize(), config.EventsCacheMaxSize(), config.EventsCacheTTL(),
-		shardCtx.GetHistoryManager(), false, shardCtx.GetLogger(), shardCtx.GetMetricsClient(), shardID)
+		shardCtx.GetEventsCacheBudget(), shardCtx.GetHistoryManager(), false, shardCtx.GetLogger(), shardCtx.GetMetricsClient(), shardID)
 }
 
-func newEventsCacheWithOptions(initialSize, maxSize int, ttl time.Duration,
-	eventsV2Mgr persistence.HistoryManager, disabled bool, logger log.Logger, metrics metrics.Client, shardID *int) *eventsCacheImpl {
+func newEventsCacheWithOptions(initialSize, maxSize int, ttl time.Duration, budget *cache.MemoryBudget,
+	eventsV2Mgr persistence.HistoryManager, disabled bool, logger log.Logger, metricsClient metrics.Client, shardID *int) *eventsCacheImpl {
 	opts := &cache.Options{}
 	opts.InitialCapacity = initialSize
 	opts.TTL = ttl
+	opts.GetCacheItemSizeFunc = getHistoryEventCacheSize
+	opts.MemoryBudget = budget
+	opts.MetricsScope = metricsClient.Scope(metrics.EventsCacheStatsScope)
 
 	return &eventsCacheImpl{
 		Cache:         cache.New(maxSize, opts),
 		eventsV2Mgr:   eventsV2Mgr,
 		disabled:      disabled,
 		logger:        logger.WithTags(tag.ComponentEventsCache),
-		metricsClient: metrics,
+		metricsClient: metricsClient,
 		shardID:       shardID,
 	}
 }
 
+func getHistoryEventCacheSize(value interface{}) uint64 {
+	return uint64(value.(*commonproto.HistoryEvent).Size())
+}
+
 func newEventKey(domainID, workflowID, runID string, eventID int64) eventKey {
 	return eventKey{
 		domainID:   domainID,
@@ -166,6 +174,11 @@ func (e *eventsCacheImpl) deleteEvent(domainID, workflowID, runID string, eventI
 	e.Delete(key)
 }
 
+// purge drops all the cached events and refunds their size to the memory budget of the host
+func (e *eventsCacheImpl) purge() {
+	e.Purge()
+}
+
 func (e *eventsCacheImpl) getHistoryEventFromStore(domainID, workflowID, runID string, firstEventID, eventID int64,
 	branchToken []byte) (*commonproto.HistoryEvent, error) {
 	e.metricsClient.IncCounter(metrics.EventsCacheGetFromStoreScope, metrics.CacheRequests)
@@ -95,3 +95,15 @@ func (mr *MockeventsCacheMockRecorder) deleteEvent(domainID, workflowID, runID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "deleteEvent", reflect.TypeOf((*MockeventsCache)(nil).deleteEvent), domainID, workflowID, runID, eventID)
 }
+
+// purge mocks base method
+func (m *MockeventsCache) purge() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "purge")
+}
+
+// purge indicates an expected call of purge
+func (mr *MockeventsCacheMockRecorder) purge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "purge", reflect.TypeOf((*MockeventsCache)(nil).purge))
+}
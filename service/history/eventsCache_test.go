@@ -80,7 +80,7 @@ func (s *eventsCacheSuite) TearDownTest() {
 
 func (s *eventsCacheSuite) newTestEventsCache() *eventsCacheImpl {
 	shardId := 10
-	return newEventsCacheWithOptions(16, 32, time.Minute, s.mockEventsV2Mgr, false, s.logger,
+	return newEventsCacheWithOptions(16, 32, time.Minute, nil, s.mockEventsV2Mgr, false, s.logger,
 		metrics.NewClient(tally.NoopScope, metrics.History), &shardId)
 }
 
@@ -60,6 +60,7 @@ type (
 		config                  *Config
 		historyEventNotifier    historyEventNotifier
 		publisher               messaging.Producer
+		historyStreamPublisher  *historyStreamPublisher
 		rateLimiter             quotas.Limiter
 		replicationTaskFetchers ReplicationTaskFetchers
 	}
@@ -112,10 +113,12 @@ func (h *Handler) Start() {
 			h.GetLogger().Fatal("Creating kafka producer failed", tag.Error(err))
 		}
 	}
+	h.historyStreamPublisher = newHistoryStreamPublisher(h.GetMessagingClient(), h.GetLogger())
 
 	h.replicationTaskFetchers = NewReplicationTaskFetchers(
 		h.GetLogger(),
 		h.config,
+		h.GetMetricsClient(),
 		h.GetClusterMetadata().GetReplicationConsumerConfig(),
 		h.GetClusterMetadata(),
 		h.GetClientBean())
@@ -127,7 +130,7 @@ func (h *Handler) Start() {
 		h,
 		h.config,
 	)
-	h.historyEventNotifier = newHistoryEventNotifier(h.GetTimeSource(), h.GetMetricsClient(), h.config.GetShardID)
+	h.historyEventNotifier = newHistoryEventNotifier(h.GetTimeSource(), h.GetMetricsClient(), h.GetHistoryShardRouter().GetShardID)
 	// events notifier must starts before controller
 	h.historyEventNotifier.Start()
 	h.controller.Start()
@@ -140,6 +143,7 @@ func (h *Handler) Stop() {
 	h.replicationTaskFetchers.Stop()
 	h.controller.Stop()
 	h.historyEventNotifier.Stop()
+	h.historyStreamPublisher.stop()
 }
 
 // CreateEngine is implementation for HistoryEngineFactory used for creating the engine instance for shard
@@ -154,6 +158,7 @@ func (h *Handler) CreateEngine(
 		h.GetSDKClient(),
 		h.historyEventNotifier,
 		h.publisher,
+		h.historyStreamPublisher,
 		h.config,
 		h.replicationTaskFetchers,
 		h.GetMatchingRawClient(),
@@ -609,6 +614,14 @@ func (h *Handler) DescribeHistoryHost(_ context.Context, _ *historyservice.Descr
 		ShardControllerStatus: status,
 		Address:               h.GetHostInfo().GetAddress(),
 	}
+	for _, shardID := range resp.ShardIDs {
+		engine, err := h.controller.getEngineForShard(int(shardID))
+		if err != nil {
+			// shard is moving to another host
+			continue
+		}
+		resp.SplitQueues = append(resp.SplitQueues, engine.DescribeSplitQueues()...)
+	}
 	return resp, nil
 }
 
@@ -1370,6 +1383,87 @@ func (h *Handler) GetReplicationMessages(ctx context.Context, request *historyse
 	return &historyservice.GetReplicationMessagesResponse{MessagesByShard: messagesByShard}, nil
 }
 
+// StreamReplicationMessages is called by remote peers to receive replication messages of a shard as they are generated
+func (h *Handler) StreamReplicationMessages(server historyservice.HistoryService_StreamReplicationMessagesServer) (retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	h.startWG.Wait()
+
+	scope := metrics.HistoryStreamReplicationMessagesScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+
+	request, err := server.Recv()
+	if err != nil {
+		return err
+	}
+	if request.GetToken() == nil {
+		return h.error(errShardIDNotSet, scope, "", "")
+	}
+	if request.GetClusterName() == "" {
+		return h.error(errSourceClusterNotSet, scope, "", "")
+	}
+
+	engine, err := h.controller.getEngineForShard(int(request.GetToken().GetShardID()))
+	if err != nil {
+		return h.error(err, scope, "", "")
+	}
+
+	h.GetLogger().Debug("Replication stream opened.", tag.ShardID(int(request.GetToken().GetShardID())), tag.ClusterName(request.GetClusterName()))
+	err = engine.StreamReplicationMessages(
+		server.Context(),
+		request.GetClusterName(),
+		request.GetToken(),
+		server,
+	)
+	if err != nil {
+		return h.error(err, scope, "", "")
+	}
+	return nil
+}
+
+// GetDomainHandoverStatus returns the shards which still have replication tasks of a domain in handover
+func (h *Handler) GetDomainHandoverStatus(ctx context.Context, request *historyservice.GetDomainHandoverStatusRequest) (_ *historyservice.GetDomainHandoverStatusResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	h.startWG.Wait()
+
+	scope := metrics.HistoryGetDomainHandoverStatusScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(len(request.ShardIDs))
+	pendingShards := new(sync.Map)
+
+	for _, shardID := range request.ShardIDs {
+		go func(shardID int32) {
+			defer wg.Done()
+
+			engine, err := h.controller.getEngineForShard(int(shardID))
+			if err != nil {
+				h.GetLogger().Warn("History engine not found for shard", tag.Error(err))
+				pendingShards.Store(shardID, struct{}{})
+				return
+			}
+			drained, err := engine.IsDomainHandoverDrained(ctx, request.GetDomainUUID())
+			if err != nil {
+				h.GetLogger().Warn("Failed to get domain handover status for shard", tag.Error(err))
+			}
+			if !drained {
+				pendingShards.Store(shardID, struct{}{})
+			}
+		}(shardID)
+	}
+
+	wg.Wait()
+
+	response := &historyservice.GetDomainHandoverStatusResponse{}
+	pendingShards.Range(func(key, _ interface{}) bool {
+		response.PendingShardIDs = append(response.PendingShardIDs, key.(int32))
+		return true
+	})
+	return response, nil
+}
+
 // GetDLQReplicationMessages is called by remote peers to get replicated messages for DLQ merging
 func (h *Handler) GetDLQReplicationMessages(ctx context.Context, request *historyservice.GetDLQReplicationMessagesRequest) (_ *historyservice.GetDLQReplicationMessagesResponse, retError error) {
 	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
@@ -1552,6 +1646,80 @@ func (h *Handler) MergeDLQMessages(ctx context.Context, request *historyservice.
 	return resp, nil
 }
 
+func (h *Handler) ReadTaskDLQMessages(ctx context.Context, request *historyservice.ReadTaskDLQMessagesRequest) (_ *historyservice.ReadTaskDLQMessagesResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryReadTaskDLQMessagesScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+
+	resp, err := engine.ReadTaskDLQMessages(ctx, request)
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) PurgeTaskDLQMessages(ctx context.Context, request *historyservice.PurgeTaskDLQMessagesRequest) (_ *historyservice.PurgeTaskDLQMessagesResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryPurgeTaskDLQMessagesScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+
+	err = engine.PurgeTaskDLQMessages(ctx, request)
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+	return &historyservice.PurgeTaskDLQMessagesResponse{}, nil
+}
+
+func (h *Handler) MergeTaskDLQMessages(ctx context.Context, request *historyservice.MergeTaskDLQMessagesRequest) (_ *historyservice.MergeTaskDLQMessagesResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryMergeTaskDLQMessagesScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+
+	engine, err := h.controller.getEngineForShard(int(request.GetRequest().GetShardID()))
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+
+	resp, err := engine.MergeTaskDLQMessages(ctx, request)
+	if err != nil {
+		err = h.error(err, scope, "", "")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (h *Handler) RefreshWorkflowTasks(ctx context.Context, request *historyservice.RefreshWorkflowTasksRequest) (_ *historyservice.RefreshWorkflowTasksResponse, retError error) {
 	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
 
@@ -1587,6 +1755,69 @@ func (h *Handler) RefreshWorkflowTasks(ctx context.Context, request *historyserv
 	return &historyservice.RefreshWorkflowTasksResponse{}, nil
 }
 
+// RehydrateWorkflowExecution restores an archived workflow execution as a closed workflow
+func (h *Handler) RehydrateWorkflowExecution(ctx context.Context, request *historyservice.RehydrateWorkflowExecutionRequest) (_ *historyservice.RehydrateWorkflowExecutionResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryRehydrateWorkflowExecutionScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+	domainID := request.GetDomainUUID()
+	execution := request.GetRequest().GetExecution()
+	workflowID := execution.GetWorkflowId()
+	engine, err := h.controller.GetEngine(workflowID)
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	resp, err := engine.RehydrateWorkflowExecution(
+		ctx,
+		domainID,
+		commonproto.WorkflowExecution{
+			WorkflowId: execution.WorkflowId,
+			RunId:      execution.RunId,
+		},
+	)
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ImportWorkflowExecution creates a workflow execution from the history exported from another cluster
+func (h *Handler) ImportWorkflowExecution(ctx context.Context, request *historyservice.ImportWorkflowExecutionRequest) (_ *historyservice.ImportWorkflowExecutionResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+
+	h.startWG.Wait()
+
+	scope := metrics.HistoryImportWorkflowExecutionScope
+	h.GetMetricsClient().IncCounter(scope, metrics.ServiceRequests)
+	sw := h.GetMetricsClient().StartTimer(scope, metrics.ServiceLatency)
+	defer sw.Stop()
+	domainID := request.GetDomainUUID()
+	execution := request.GetRequest().GetExecution()
+	workflowID := execution.GetWorkflowId()
+	engine, err := h.controller.GetEngine(workflowID)
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	resp, err := engine.ImportWorkflowExecution(ctx, request)
+	if err != nil {
+		err = h.error(err, scope, domainID, workflowID)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // convertError is a helper method to convert ShardOwnershipLostError from persistence layer returned by various
 // HistoryEngine API calls to ShardOwnershipLost error return by HistoryService for client to be redirected to the
 // correct shard.
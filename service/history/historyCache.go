@@ -65,6 +65,9 @@ func newHistoryCache(shard ShardContext) *historyCache {
 	opts.InitialCapacity = config.HistoryCacheInitialSize()
 	opts.TTL = config.HistoryCacheTTL()
 	opts.Pin = true
+	opts.GetCacheItemSizeFunc = getWorkflowExecutionContextCacheSize
+	opts.MemoryBudget = shard.GetHistoryCacheBudget()
+	opts.MetricsScope = shard.GetMetricsClient().Scope(metrics.HistoryCacheStatsScope)
 
 	return &historyCache{
 		Cache:            cache.New(config.HistoryCacheMaxSize(), opts),
@@ -76,6 +79,13 @@ func newHistoryCache(shard ShardContext) *historyCache {
 	}
 }
 
+func getWorkflowExecutionContextCacheSize(value interface{}) uint64 {
+	if workflowCtx, ok := value.(*workflowExecutionContextImpl); ok {
+		return uint64(workflowCtx.getMutableStateSize())
+	}
+	return 0
+}
+
 func (c *historyCache) getOrCreateCurrentWorkflowExecution(
 	ctx context.Context,
 	domainID string,
@@ -32,6 +32,8 @@ import (
 	commonproto "go.temporal.io/temporal-proto/common"
 
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
@@ -216,3 +218,46 @@ func (s *historyCacheSuite) TestHistoryCacheConcurrentAccess() {
 	s.Nil(context.(*workflowExecutionContextImpl).mutableState)
 	release(nil)
 }
+
+func (s *historyCacheSuite) TestHistoryCache_ShardUnload_RefundsMemoryBudget() {
+	historyCacheBudget := cache.NewMemoryBudget(dynamicconfig.GetIntPropertyFn(0), nil)
+	eventsCacheBudget := cache.NewMemoryBudget(dynamicconfig.GetIntPropertyFn(0), nil)
+	s.mockShard.shardItem = &historyShardsItem{
+		historyCacheBudget: historyCacheBudget,
+		eventsCacheBudget:  eventsCacheBudget,
+	}
+	s.mockShard.eventsCache = newEventsCache(s.mockShard)
+
+	// load the shard
+	s.cache = newHistoryCache(s.mockShard)
+	domainID := "test_domain_id"
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "wf-cache-test-shard-unload",
+		RunId:      uuid.New(),
+	}
+	context, release, err := s.cache.getOrCreateWorkflowExecutionForBackground(domainID, execution)
+	s.Nil(err)
+	context.(*workflowExecutionContextImpl).updateMutableStateSize(128)
+	release(nil)
+	s.mockShard.GetEventsCache().putEvent(domainID, execution.GetWorkflowId(), execution.GetRunId(), common.FirstEventID,
+		&commonproto.HistoryEvent{EventId: common.FirstEventID, Version: 1})
+	s.Equal(uint64(128), historyCacheBudget.UsedBytes())
+	s.NotZero(eventsCacheBudget.UsedBytes())
+
+	// unload the shard
+	mockTxProcessor := NewMocktransferQueueProcessor(s.controller)
+	mockTimerProcessor := NewMocktimerQueueProcessor(s.controller)
+	mockTxProcessor.EXPECT().Stop().Times(1)
+	mockTimerProcessor.EXPECT().Stop().Times(1)
+	s.mockShard.resource.DomainCache.EXPECT().UnregisterDomainChangeCallback(s.mockShard.GetShardID()).Times(1)
+	engine := &historyEngineImpl{
+		shard:          s.mockShard,
+		historyCache:   s.cache,
+		txProcessor:    mockTxProcessor,
+		timerProcessor: mockTimerProcessor,
+		logger:         s.mockShard.GetLogger(),
+	}
+	engine.Stop()
+	s.Equal(uint64(0), historyCacheBudget.UsedBytes())
+	s.Equal(uint64(0), eventsCacheBudget.UsedBytes())
+}
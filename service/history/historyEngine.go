@@ -38,6 +38,7 @@ import (
 	"go.temporal.io/temporal-proto/workflowservice"
 	sdkclient "go.temporal.io/temporal/client"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/replication"
@@ -102,18 +103,27 @@ type (
 		SyncShardStatus(ctx context.Context, request *historyservice.SyncShardStatusRequest) error
 		SyncActivity(ctx context.Context, request *historyservice.SyncActivityRequest) error
 		GetReplicationMessages(ctx context.Context, pollingCluster string, lastReadMessageID int64) (*replication.ReplicationMessages, error)
+		StreamReplicationMessages(ctx context.Context, pollingCluster string, token *replication.ReplicationToken, stream replicationStreamServer) error
 		GetDLQReplicationMessages(ctx context.Context, taskInfos []*replication.ReplicationTaskInfo) ([]*replication.ReplicationTask, error)
+		IsDomainHandoverDrained(ctx context.Context, domainUUID string) (bool, error)
 		QueryWorkflow(ctx context.Context, request *historyservice.QueryWorkflowRequest) (*historyservice.QueryWorkflowResponse, error)
 		ReapplyEvents(ctx context.Context, domainUUID string, workflowID string, runID string, events []*commonproto.HistoryEvent) error
 		ReadDLQMessages(ctx context.Context, messagesRequest *historyservice.ReadDLQMessagesRequest) (*historyservice.ReadDLQMessagesResponse, error)
 		PurgeDLQMessages(ctx context.Context, messagesRequest *historyservice.PurgeDLQMessagesRequest) error
 		MergeDLQMessages(ctx context.Context, messagesRequest *historyservice.MergeDLQMessagesRequest) (*historyservice.MergeDLQMessagesResponse, error)
+		ReadTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.ReadTaskDLQMessagesRequest) (*historyservice.ReadTaskDLQMessagesResponse, error)
+		PurgeTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.PurgeTaskDLQMessagesRequest) error
+		MergeTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.MergeTaskDLQMessagesRequest) (*historyservice.MergeTaskDLQMessagesResponse, error)
 		RefreshWorkflowTasks(ctx context.Context, domainUUID string, execution commonproto.WorkflowExecution) error
+		RehydrateWorkflowExecution(ctx context.Context, domainUUID string, execution commonproto.WorkflowExecution) (*historyservice.RehydrateWorkflowExecutionResponse, error)
+		ImportWorkflowExecution(ctx context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error)
 
 		NotifyNewHistoryEvent(event *historyEventNotification)
 		NotifyNewTransferTasks(tasks []persistence.Task)
 		NotifyNewReplicationTasks(tasks []persistence.Task)
-		NotifyNewTimerTasks(tasks []persistence.Task)
+		NotifyNewTimerTasks(execution definition.WorkflowIdentifier, tasks []persistence.Task)
+
+		DescribeSplitQueues() []*adminservice.SplitQueueInfo
 	}
 
 	historyEngineImpl struct {
@@ -131,6 +141,7 @@ type (
 		nDCReplicator             nDCHistoryReplicator
 		nDCActivityReplicator     nDCActivityReplicator
 		replicatorProcessor       ReplicatorQueueProcessor
+		replicationStreams        replicationTaskNotifier
 		historyEventNotifier      historyEventNotifier
 		tokenSerializer           common.TaskTokenSerializer
 		historyCache              *historyCache
@@ -141,6 +152,8 @@ type (
 		archivalClient            archiver.Client
 		resetor                   workflowResetor
 		workflowResetter          workflowResetter
+		workflowRehydrator        workflowRehydrator
+		workflowImporter          workflowImporter
 		replicationTaskProcessors []ReplicationTaskProcessor
 		publicClient              sdkclient.Client
 		eventsReapplier           nDCEventsReapplier
@@ -148,6 +161,8 @@ type (
 		rawMatchingClient         matching.Client
 		versionChecker            headers.VersionChecker
 		replicationDLQHandler     replicationDLQHandler
+		taskDLQHandler            taskDLQHandler
+		historyStreamPublisher    *historyStreamPublisher
 	}
 )
 
@@ -210,6 +225,7 @@ func NewEngineWithShardContext(
 	publicClient sdkclient.Client,
 	historyEventNotifier historyEventNotifier,
 	publisher messaging.Producer,
+	historyStreamPublisher *historyStreamPublisher,
 	config *Config,
 	replicationTaskFetchers ReplicationTaskFetchers,
 	rawMatchingClient matching.Client,
@@ -243,10 +259,11 @@ func NewEngineWithShardContext(
 			shard.GetConfig().ArchiveRequestRPS,
 			shard.GetService().GetArchiverProvider(),
 		),
-		publicClient:      publicClient,
-		matchingClient:    matching,
-		rawMatchingClient: rawMatchingClient,
-		versionChecker:    headers.NewVersionChecker(),
+		publicClient:           publicClient,
+		matchingClient:         matching,
+		rawMatchingClient:      rawMatchingClient,
+		versionChecker:         headers.NewVersionChecker(),
+		historyStreamPublisher: historyStreamPublisher,
 	}
 
 	historyEngImpl.txProcessor = newTransferQueueProcessor(shard, historyEngImpl, visibilityMgr, matching, historyClient, logger)
@@ -290,6 +307,16 @@ func NewEngineWithShardContext(
 		historyCache,
 		logger,
 	)
+	historyEngImpl.workflowRehydrator = newWorkflowRehydrator(
+		shard,
+		historyCache,
+		logger,
+	)
+	historyEngImpl.workflowImporter = newWorkflowImporter(
+		shard,
+		historyCache,
+		logger,
+	)
 	historyEngImpl.decisionHandler = newDecisionHandler(historyEngImpl)
 
 	nDCHistoryResender := xdc.NewNDCHistoryResender(
@@ -336,6 +363,7 @@ func NewEngineWithShardContext(
 	historyEngImpl.replicationTaskProcessors = replicationTaskProcessors
 	replicationMessageHandler := newReplicationDLQHandler(shard, replicationTaskExecutor)
 	historyEngImpl.replicationDLQHandler = replicationMessageHandler
+	historyEngImpl.taskDLQHandler = newTaskDLQHandler(shard, historyEngImpl.txProcessor, historyEngImpl.timerProcessor)
 
 	shard.SetEngine(historyEngImpl)
 	return historyEngImpl
@@ -352,6 +380,9 @@ func (e *historyEngineImpl) Start() {
 
 	e.txProcessor.Start()
 	e.timerProcessor.Start()
+	if e.taskDLQHandler != nil {
+		e.taskDLQHandler.Start()
+	}
 
 	clusterMetadata := e.shard.GetClusterMetadata()
 	if e.replicatorProcessor != nil && clusterMetadata.GetReplicationConsumerConfig().Type != config.ReplicationConsumerTypeRPC {
@@ -368,8 +399,14 @@ func (e *historyEngineImpl) Stop() {
 	e.logger.Info("", tag.LifeCycleStopping)
 	defer e.logger.Info("", tag.LifeCycleStopped)
 
+	// end the replication streams of the shard, the remote clusters reopen them against the new owner
+	e.replicationStreams.close()
+
 	e.txProcessor.Stop()
 	e.timerProcessor.Stop()
+	if e.taskDLQHandler != nil {
+		e.taskDLQHandler.Stop()
+	}
 	if e.replicatorProcessor != nil {
 		e.replicatorProcessor.Stop()
 	}
@@ -380,6 +417,10 @@ func (e *historyEngineImpl) Stop() {
 
 	// unset the failover callback
 	e.shard.GetDomainCache().UnregisterDomainChangeCallback(e.shard.GetShardID())
+
+	// return the memory held by the caches of the shard to the budgets shared by the host
+	e.historyCache.Purge()
+	e.shard.GetEventsCache().purge()
 }
 
 func (e *historyEngineImpl) registerDomainFailoverCallback() {
@@ -407,7 +448,8 @@ func (e *historyEngineImpl) registerDomainFailoverCallback() {
 		domainFailoverNotificationVersion := nextDomain.GetFailoverNotificationVersion()
 		domainActiveCluster := nextDomain.GetReplicationConfig().ActiveClusterName
 
-		if nextDomain.IsGlobalDomain() &&
+		// a local domain fails over only at the cutover of a migration into this cluster
+		if (nextDomain.IsGlobalDomain() || domainFailoverNotificationVersion > persistence.InitialFailoverNotificationVersion) &&
 			domainFailoverNotificationVersion >= shardNotificationVersion &&
 			domainActiveCluster == e.currentClusterName {
 			action()
@@ -1254,6 +1296,11 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 		result.WorkflowExecutionInfo.CloseTime = &types.Int64Value{Value: completionEvent.GetTimestamp()}
 	}
 
+	for _, completionCallback := range executionInfo.CompletionCallbacks {
+		callbackCopy := *completionCallback
+		result.CompletionCallbacks = append(result.CompletionCallbacks, &callbackCopy)
+	}
+
 	if len(mutableState.GetPendingActivityInfos()) > 0 {
 		for _, ai := range mutableState.GetPendingActivityInfos() {
 			p := &commonproto.PendingActivityInfo{
@@ -1465,6 +1512,7 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(
 
 	var activityStartedTime time.Time
 	var taskList string
+	var releaseRequest *matchingservice.ReleaseActivityTaskSlotRequest
 	err = e.updateWorkflowExecution(ctx, domainID, workflowExecution, true,
 		func(context workflowExecutionContext, mutableState mutableState) error {
 			if !mutableState.IsWorkflowExecutionRunning() {
@@ -1492,12 +1540,17 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(
 				return ErrActivityTaskNotFound
 			}
 
+			slotRequest, err := newReleaseActivityTaskSlotRequest(e.shard, mutableState, ai)
+			if err != nil {
+				return err
+			}
 			if _, err := mutableState.AddActivityTaskCompletedEvent(scheduleID, ai.StartedID, request); err != nil {
 				// Unable to add ActivityTaskCompleted event to history
 				return serviceerror.NewInternal("Unable to add ActivityTaskCompleted event to history.")
 			}
 			activityStartedTime = ai.StartedTime
 			taskList = ai.TaskList
+			releaseRequest = slotRequest
 			return nil
 		})
 	if err == nil && !activityStartedTime.IsZero() {
@@ -1510,6 +1563,9 @@ func (e *historyEngineImpl) RespondActivityTaskCompleted(
 			)
 		scope.RecordTimer(metrics.ActivityE2ELatency, time.Since(activityStartedTime))
 	}
+	if err == nil && releaseRequest != nil {
+		e.releaseActivityTaskSlots([]*matchingservice.ReleaseActivityTaskSlotRequest{releaseRequest})
+	}
 	return err
 }
 
@@ -1539,6 +1595,7 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(
 
 	var activityStartedTime time.Time
 	var taskList string
+	var releaseRequest *matchingservice.ReleaseActivityTaskSlotRequest
 	err = e.updateWorkflowExecutionWithAction(ctx, domainID, workflowExecution,
 		func(context workflowExecutionContext, mutableState mutableState) (*updateWorkflowAction, error) {
 			if !mutableState.IsWorkflowExecutionRunning() {
@@ -1566,12 +1623,19 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(
 				return nil, ErrActivityTaskNotFound
 			}
 
+			slotRequest, err := newReleaseActivityTaskSlotRequest(e.shard, mutableState, ai)
+			if err != nil {
+				return nil, err
+			}
 			postActions := &updateWorkflowAction{}
 			ok, err := mutableState.RetryActivity(ai, req.FailedRequest.GetReason(), req.FailedRequest.GetDetails())
 			if err != nil {
 				return nil, err
 			}
-			if !ok {
+			if ok {
+				// the retry keeps the slot, it is extended once the next attempt is started
+				slotRequest = nil
+			} else {
 				// no more retry, and we want to record the failure event
 				if _, err := mutableState.AddActivityTaskFailedEvent(scheduleID, ai.StartedID, request); err != nil {
 					// Unable to add ActivityTaskFailed event to history
@@ -1582,6 +1646,7 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(
 
 			activityStartedTime = ai.StartedTime
 			taskList = ai.TaskList
+			releaseRequest = slotRequest
 			return postActions, nil
 		})
 	if err == nil && !activityStartedTime.IsZero() {
@@ -1594,6 +1659,9 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(
 			)
 		scope.RecordTimer(metrics.ActivityE2ELatency, time.Since(activityStartedTime))
 	}
+	if err == nil && releaseRequest != nil {
+		e.releaseActivityTaskSlots([]*matchingservice.ReleaseActivityTaskSlotRequest{releaseRequest})
+	}
 	return err
 }
 
@@ -1623,6 +1691,7 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(
 
 	var activityStartedTime time.Time
 	var taskList string
+	var releaseRequest *matchingservice.ReleaseActivityTaskSlotRequest
 	err = e.updateWorkflowExecution(ctx, domainID, workflowExecution, true,
 		func(context workflowExecutionContext, mutableState mutableState) error {
 			if !mutableState.IsWorkflowExecutionRunning() {
@@ -1650,6 +1719,10 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(
 				return ErrActivityTaskNotFound
 			}
 
+			slotRequest, err := newReleaseActivityTaskSlotRequest(e.shard, mutableState, ai)
+			if err != nil {
+				return err
+			}
 			if _, err := mutableState.AddActivityTaskCanceledEvent(
 				scheduleID,
 				ai.StartedID,
@@ -1662,6 +1735,7 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(
 
 			activityStartedTime = ai.StartedTime
 			taskList = ai.TaskList
+			releaseRequest = slotRequest
 			return nil
 		})
 	if err == nil && !activityStartedTime.IsZero() {
@@ -1674,9 +1748,33 @@ func (e *historyEngineImpl) RespondActivityTaskCanceled(
 			)
 		scope.RecordTimer(metrics.ActivityE2ELatency, time.Since(activityStartedTime))
 	}
+	if err == nil && releaseRequest != nil {
+		e.releaseActivityTaskSlots([]*matchingservice.ReleaseActivityTaskSlotRequest{releaseRequest})
+	}
 	return err
 }
 
+// releaseActivityTaskSlots releases the concurrency slots held in matching by activities which are no longer
+// pending, failures are ignored as matching releases the slots once their lease expires
+func (e *historyEngineImpl) releaseActivityTaskSlots(
+	requests []*matchingservice.ReleaseActivityTaskSlotRequest,
+) {
+
+	if len(requests) == 0 {
+		return
+	}
+
+	go func() {
+		if err := releaseActivityTaskSlots(e.matchingClient, requests); err != nil {
+			e.logger.Warn("Failed to release activity task slots in matching",
+				tag.WorkflowDomainID(requests[0].GetDomainUUID()),
+				tag.WorkflowID(requests[0].GetExecution().GetWorkflowId()),
+				tag.WorkflowRunID(requests[0].GetExecution().GetRunId()),
+				tag.Error(err))
+		}
+	}()
+}
+
 // RecordActivityTaskHeartbeat records an hearbeat for a task.
 // This method can be used for two purposes.
 // - For reporting liveness of the activity.
@@ -2573,20 +2671,26 @@ func (e *historyEngineImpl) NotifyNewReplicationTasks(
 
 	if len(tasks) > 0 {
 		e.replicatorProcessor.notifyNewTask()
+		e.replicationStreams.notify()
 	}
 }
 
 func (e *historyEngineImpl) NotifyNewTimerTasks(
+	execution definition.WorkflowIdentifier,
 	tasks []persistence.Task,
 ) {
 
 	if len(tasks) > 0 {
 		task := tasks[0]
 		clusterName := e.clusterMetadata.ClusterNameForFailoverVersion(task.GetVersion())
-		e.timerProcessor.NotifyNewTimers(clusterName, tasks)
+		e.timerProcessor.NotifyNewWorkflowTimers(clusterName, execution, tasks)
 	}
 }
 
+func (e *historyEngineImpl) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	return append(e.txProcessor.DescribeSplitQueues(), e.timerProcessor.DescribeSplitQueues()...)
+}
+
 func validateStartWorkflowExecutionRequest(
 	request *workflowservice.StartWorkflowExecutionRequest,
 	maxIDLengthLimit int,
@@ -2681,6 +2785,14 @@ func getActiveDomainEntryFromShard(
 	if err = domainEntry.GetDomainNotActiveErr(); err != nil {
 		return nil, err
 	}
+	// reject new writes while the domain drains its replication tasks during a graceful failover
+	if err = domainEntry.GetDomainHandoverErr(); err != nil {
+		return nil, err
+	}
+	// reject new writes while the domain is locked for a migration
+	if err = domainEntry.GetDomainMigrationErr(); err != nil {
+		return nil, err
+	}
 	return domainEntry, nil
 }
 
@@ -2844,6 +2956,64 @@ func (e *historyEngineImpl) GetReplicationMessages(
 	return replicationMessages, nil
 }
 
+func (e *historyEngineImpl) StreamReplicationMessages(
+	ctx context.Context,
+	pollingCluster string,
+	token *replication.ReplicationToken,
+	stream replicationStreamServer,
+) error {
+
+	sender := newReplicationStreamSender(
+		e.shard,
+		e.replicatorProcessor,
+		&e.replicationStreams,
+		pollingCluster,
+		stream,
+		e.logger,
+	)
+	if err := sender.run(ctx, token); err != nil {
+		if _, ok := err.(*persistence.ShardOwnershipLostError); ok {
+			e.logger.Info("Replication stream closed as the shard engine stopped.", tag.ClusterName(pollingCluster))
+			return err
+		}
+		e.logger.Error("Replication stream failed.", tag.ClusterName(pollingCluster), tag.Error(err))
+		e.metricsClient.IncCounter(metrics.HistoryStreamReplicationMessagesScope, metrics.ReplicationStreamFailures)
+		return err
+	}
+	return nil
+}
+
+// IsDomainHandoverDrained returns whether the handover cluster acknowledged all replication tasks
+// of the domain in this shard, after which no new replication tasks can be generated for the domain.
+func (e *historyEngineImpl) IsDomainHandoverDrained(
+	ctx context.Context,
+	domainUUID string,
+) (bool, error) {
+
+	domainID, err := validateDomainUUID(domainUUID)
+	if err != nil {
+		return false, err
+	}
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		return false, err
+	}
+	if !domainEntry.IsDomainInHandover() {
+		// writes are accepted until the domain cache of this host observes the handover
+		return false, nil
+	}
+	if e.replicatorProcessor == nil {
+		return false, serviceerror.NewInternal("Replication is not enabled on history host.")
+	}
+
+	ackLevel := e.shard.GetClusterReplicationLevel(domainEntry.GetHandoverClusterName())
+	hasPendingTasks, err := e.replicatorProcessor.hasPendingDomainTasks(domainID, ackLevel)
+	if err != nil {
+		return false, err
+	}
+	return !hasPendingTasks, nil
+}
+
 func (e *historyEngineImpl) GetDLQReplicationMessages(
 	ctx context.Context,
 	taskInfos []*replication.ReplicationTaskInfo,
@@ -3051,6 +3221,59 @@ func (e *historyEngineImpl) MergeDLQMessages(
 	}, nil
 }
 
+func (e *historyEngineImpl) ReadTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.ReadTaskDLQMessagesRequest,
+) (*historyservice.ReadTaskDLQMessagesResponse, error) {
+
+	messages, token, err := e.taskDLQHandler.readMessages(
+		request.GetRequest().GetTaskCategory(),
+		request.GetRequest().GetExclusiveBeginMessageID(),
+		request.GetRequest().GetInclusiveEndMessageID(),
+		int(request.GetRequest().GetMaximumPageSize()),
+		request.GetRequest().GetNextPageToken(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.ReadTaskDLQMessagesResponse{
+		Messages:      messages,
+		NextPageToken: token,
+	}, nil
+}
+
+func (e *historyEngineImpl) PurgeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.PurgeTaskDLQMessagesRequest,
+) error {
+
+	return e.taskDLQHandler.purgeMessages(
+		request.GetRequest().GetTaskCategory(),
+		request.GetRequest().GetExclusiveBeginMessageID(),
+		request.GetRequest().GetInclusiveEndMessageID(),
+	)
+}
+
+func (e *historyEngineImpl) MergeTaskDLQMessages(
+	ctx context.Context,
+	request *historyservice.MergeTaskDLQMessagesRequest,
+) (*historyservice.MergeTaskDLQMessagesResponse, error) {
+
+	token, err := e.taskDLQHandler.mergeMessages(
+		request.GetRequest().GetTaskCategory(),
+		request.GetRequest().GetExclusiveBeginMessageID(),
+		request.GetRequest().GetInclusiveEndMessageID(),
+		int(request.GetRequest().GetMaximumPageSize()),
+		request.GetRequest().GetNextPageToken(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.MergeTaskDLQMessagesResponse{
+		NextPageToken: token,
+	}, nil
+}
+
 func (e *historyEngineImpl) RefreshWorkflowTasks(
 	ctx context.Context,
 	domainUUID string,
@@ -3099,6 +3322,39 @@ func (e *historyEngineImpl) RefreshWorkflowTasks(
 	return nil
 }
 
+func (e *historyEngineImpl) RehydrateWorkflowExecution(
+	ctx context.Context,
+	domainUUID string,
+	execution commonproto.WorkflowExecution,
+) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+
+	expirationTime, err := e.workflowRehydrator.rehydrate(ctx, domainUUID, execution)
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.RehydrateWorkflowExecutionResponse{
+		ExpirationTime: expirationTime.UnixNano(),
+	}, nil
+}
+
+func (e *historyEngineImpl) ImportWorkflowExecution(
+	ctx context.Context,
+	request *historyservice.ImportWorkflowExecutionRequest,
+) (*historyservice.ImportWorkflowExecutionResponse, error) {
+
+	nextPageToken, err := e.workflowImporter.importWorkflow(
+		ctx,
+		request.GetDomainUUID(),
+		request.GetRequest(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.ImportWorkflowExecutionResponse{
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
 func (e *historyEngineImpl) loadWorkflowOnce(
 	ctx context.Context,
 	domainID string,
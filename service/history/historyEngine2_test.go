@@ -99,6 +99,7 @@ func (s *engine2Suite) SetupTest() {
 	s.mockTxProcessor.EXPECT().NotifyNewTask(gomock.Any(), gomock.Any()).AnyTimes()
 	s.mockReplicationProcessor.EXPECT().notifyNewTask().AnyTimes()
 	s.mockTimerProcessor.EXPECT().NotifyNewTimers(gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockTimerProcessor.EXPECT().NotifyNewWorkflowTimers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 	s.mockShard = newTestShardContext(
 		s.controller,
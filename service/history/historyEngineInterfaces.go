@@ -52,10 +52,18 @@ type (
 			pollingCluster string,
 			lastReadTaskID int64,
 		) (*replication.ReplicationMessages, error)
+		readMessages(
+			ctx context.Context,
+			lastReadTaskID int64,
+		) (*replication.ReplicationMessages, error)
 		getTask(
 			ctx context.Context,
 			taskInfo *replication.ReplicationTaskInfo,
 		) (*replication.ReplicationTask, error)
+		hasPendingDomainTasks(
+			domainID string,
+			readLevel int64,
+		) (bool, error)
 	}
 
 	queueAckMgr interface {
@@ -119,6 +127,7 @@ type (
 		getFinishedChan() <-chan struct{}
 		readTimerTasks() ([]*persistenceblobs.TimerTaskInfo, *persistenceblobs.TimerTaskInfo, bool, error)
 		completeTimerTask(timerTask *persistenceblobs.TimerTaskInfo)
+		loadInMemoryTask(timerTask *persistenceblobs.TimerTaskInfo) bool
 		getAckLevel() timerKey
 		getReadLevel() timerKey
 		updateAckLevel()
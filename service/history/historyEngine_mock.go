@@ -34,8 +34,10 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	common "go.temporal.io/temporal-proto/common"
 
+	adminservice "github.com/temporalio/temporal/.gen/proto/adminservice"
 	historyservice "github.com/temporalio/temporal/.gen/proto/historyservice"
 	replication "github.com/temporalio/temporal/.gen/proto/replication"
+	definition "github.com/temporalio/temporal/common/definition"
 	persistence "github.com/temporalio/temporal/common/persistence"
 )
 
@@ -491,6 +493,35 @@ func (mr *MockEngineMockRecorder) GetReplicationMessages(ctx, pollingCluster, la
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockEngine)(nil).GetReplicationMessages), ctx, pollingCluster, lastReadMessageID)
 }
 
+// StreamReplicationMessages mocks base method
+func (m *MockEngine) StreamReplicationMessages(ctx context.Context, pollingCluster string, token *replication.ReplicationToken, stream replicationStreamServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamReplicationMessages", ctx, pollingCluster, token, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamReplicationMessages indicates an expected call of StreamReplicationMessages
+func (mr *MockEngineMockRecorder) StreamReplicationMessages(ctx, pollingCluster, token, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamReplicationMessages", reflect.TypeOf((*MockEngine)(nil).StreamReplicationMessages), ctx, pollingCluster, token, stream)
+}
+
+// IsDomainHandoverDrained mocks base method
+func (m *MockEngine) IsDomainHandoverDrained(ctx context.Context, domainUUID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDomainHandoverDrained", ctx, domainUUID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDomainHandoverDrained indicates an expected call of IsDomainHandoverDrained
+func (mr *MockEngineMockRecorder) IsDomainHandoverDrained(ctx, domainUUID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDomainHandoverDrained", reflect.TypeOf((*MockEngine)(nil).IsDomainHandoverDrained), ctx, domainUUID)
+}
+
 // GetDLQReplicationMessages mocks base method
 func (m *MockEngine) GetDLQReplicationMessages(ctx context.Context, taskInfos []*replication.ReplicationTaskInfo) ([]*replication.ReplicationTask, error) {
 	m.ctrl.T.Helper()
@@ -579,6 +610,50 @@ func (mr *MockEngineMockRecorder) MergeDLQMessages(ctx, messagesRequest interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQMessages", reflect.TypeOf((*MockEngine)(nil).MergeDLQMessages), ctx, messagesRequest)
 }
 
+// ReadTaskDLQMessages mocks base method
+func (m *MockEngine) ReadTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.ReadTaskDLQMessagesRequest) (*historyservice.ReadTaskDLQMessagesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadTaskDLQMessages", ctx, messagesRequest)
+	ret0, _ := ret[0].(*historyservice.ReadTaskDLQMessagesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadTaskDLQMessages indicates an expected call of ReadTaskDLQMessages
+func (mr *MockEngineMockRecorder) ReadTaskDLQMessages(ctx, messagesRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadTaskDLQMessages", reflect.TypeOf((*MockEngine)(nil).ReadTaskDLQMessages), ctx, messagesRequest)
+}
+
+// PurgeTaskDLQMessages mocks base method
+func (m *MockEngine) PurgeTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.PurgeTaskDLQMessagesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTaskDLQMessages", ctx, messagesRequest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTaskDLQMessages indicates an expected call of PurgeTaskDLQMessages
+func (mr *MockEngineMockRecorder) PurgeTaskDLQMessages(ctx, messagesRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTaskDLQMessages", reflect.TypeOf((*MockEngine)(nil).PurgeTaskDLQMessages), ctx, messagesRequest)
+}
+
+// MergeTaskDLQMessages mocks base method
+func (m *MockEngine) MergeTaskDLQMessages(ctx context.Context, messagesRequest *historyservice.MergeTaskDLQMessagesRequest) (*historyservice.MergeTaskDLQMessagesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTaskDLQMessages", ctx, messagesRequest)
+	ret0, _ := ret[0].(*historyservice.MergeTaskDLQMessagesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTaskDLQMessages indicates an expected call of MergeTaskDLQMessages
+func (mr *MockEngineMockRecorder) MergeTaskDLQMessages(ctx, messagesRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTaskDLQMessages", reflect.TypeOf((*MockEngine)(nil).MergeTaskDLQMessages), ctx, messagesRequest)
+}
+
 // RefreshWorkflowTasks mocks base method
 func (m *MockEngine) RefreshWorkflowTasks(ctx context.Context, domainUUID string, execution common.WorkflowExecution) error {
 	m.ctrl.T.Helper()
@@ -593,6 +668,36 @@ func (mr *MockEngineMockRecorder) RefreshWorkflowTasks(ctx, domainUUID, executio
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshWorkflowTasks", reflect.TypeOf((*MockEngine)(nil).RefreshWorkflowTasks), ctx, domainUUID, execution)
 }
 
+// RehydrateWorkflowExecution mocks base method
+func (m *MockEngine) RehydrateWorkflowExecution(ctx context.Context, domainUUID string, execution common.WorkflowExecution) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RehydrateWorkflowExecution", ctx, domainUUID, execution)
+	ret0, _ := ret[0].(*historyservice.RehydrateWorkflowExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RehydrateWorkflowExecution indicates an expected call of RehydrateWorkflowExecution
+func (mr *MockEngineMockRecorder) RehydrateWorkflowExecution(ctx, domainUUID, execution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehydrateWorkflowExecution", reflect.TypeOf((*MockEngine)(nil).RehydrateWorkflowExecution), ctx, domainUUID, execution)
+}
+
+// ImportWorkflowExecution mocks base method
+func (m *MockEngine) ImportWorkflowExecution(ctx context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportWorkflowExecution", ctx, request)
+	ret0, _ := ret[0].(*historyservice.ImportWorkflowExecutionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportWorkflowExecution indicates an expected call of ImportWorkflowExecution
+func (mr *MockEngineMockRecorder) ImportWorkflowExecution(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportWorkflowExecution", reflect.TypeOf((*MockEngine)(nil).ImportWorkflowExecution), ctx, request)
+}
+
 // NotifyNewHistoryEvent mocks base method
 func (m *MockEngine) NotifyNewHistoryEvent(event *historyEventNotification) {
 	m.ctrl.T.Helper()
@@ -630,13 +735,27 @@ func (mr *MockEngineMockRecorder) NotifyNewReplicationTasks(tasks interface{}) *
 }
 
 // NotifyNewTimerTasks mocks base method
-func (m *MockEngine) NotifyNewTimerTasks(tasks []persistence.Task) {
+func (m *MockEngine) NotifyNewTimerTasks(execution definition.WorkflowIdentifier, tasks []persistence.Task) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "NotifyNewTimerTasks", tasks)
+	m.ctrl.Call(m, "NotifyNewTimerTasks", execution, tasks)
 }
 
 // NotifyNewTimerTasks indicates an expected call of NotifyNewTimerTasks
-func (mr *MockEngineMockRecorder) NotifyNewTimerTasks(tasks interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) NotifyNewTimerTasks(execution, tasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyNewTimerTasks", reflect.TypeOf((*MockEngine)(nil).NotifyNewTimerTasks), execution, tasks)
+}
+
+// DescribeSplitQueues mocks base method
+func (m *MockEngine) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeSplitQueues")
+	ret0, _ := ret[0].([]*adminservice.SplitQueueInfo)
+	return ret0
+}
+
+// DescribeSplitQueues indicates an expected call of DescribeSplitQueues
+func (mr *MockEngineMockRecorder) DescribeSplitQueues() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyNewTimerTasks", reflect.TypeOf((*MockEngine)(nil).NotifyNewTimerTasks), tasks)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSplitQueues", reflect.TypeOf((*MockEngine)(nil).DescribeSplitQueues))
 }
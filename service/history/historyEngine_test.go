@@ -195,6 +195,7 @@ func (s *engineSuite) SetupTest() {
 	s.mockTxProcessor.EXPECT().NotifyNewTask(gomock.Any(), gomock.Any()).AnyTimes()
 	s.mockReplicationProcessor.EXPECT().notifyNewTask().AnyTimes()
 	s.mockTimerProcessor.EXPECT().NotifyNewTimers(gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockTimerProcessor.EXPECT().NotifyNewWorkflowTimers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 	s.mockShard = newTestShardContext(
 		s.controller,
@@ -1748,6 +1749,131 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSingleActivityScheduledDec
 	s.Equal(int32(5), activity1Attributes.HeartbeatTimeoutSeconds)
 }
 
+func (s *engineSuite) TestRespondDecisionTaskCompletedEagerActivities() {
+
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tl := "testTaskList"
+	tt := &token.Task{
+		WorkflowId: "wId",
+		RunId:      primitives.MustParseUUID(we.GetRunId()),
+		ScheduleId: 2,
+	}
+	taskToken, _ := tt.Marshal()
+	identity := "testIdentity"
+	s.mockHistoryEngine.config.EagerActivityDispatchLimit = dynamicconfig.GetIntPropertyFilteredByDomain(10)
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.mockHistoryEngine.shard, s.eventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), we.GetRunId())
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	scheduleActivity := func(activityID string, taskList string) *commonproto.Decision {
+		return &commonproto.Decision{
+			DecisionType: enums.DecisionTypeScheduleActivityTask,
+			Attributes: &commonproto.Decision_ScheduleActivityTaskDecisionAttributes{ScheduleActivityTaskDecisionAttributes: &commonproto.ScheduleActivityTaskDecisionAttributes{
+				ActivityId:                    activityID,
+				ActivityType:                  &commonproto.ActivityType{Name: "activity_type1"},
+				TaskList:                      &commonproto.TaskList{Name: taskList},
+				Input:                         []byte("input"),
+				ScheduleToCloseTimeoutSeconds: 100,
+				ScheduleToStartTimeoutSeconds: 10,
+				StartToCloseTimeoutSeconds:    50,
+				HeartbeatTimeoutSeconds:       5,
+			}},
+		}
+	}
+	decisions := []*commonproto.Decision{
+		scheduleActivity("activity1", tl),
+		scheduleActivity("activity2", "otherTaskList"),
+	}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything).Return(&persistence.AppendHistoryNodesResponse{Size: 0}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	resp, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &historyservice.RespondDecisionTaskCompletedRequest{
+		DomainUUID: testDomainID,
+		CompleteRequest: &workflowservice.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  identity,
+		},
+		MaxEagerActivityTasks: 10,
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	// only the activity on the workflow task list is returned, and it is left to the frontend to start
+	s.Equal([]int64{5}, resp.EagerActivityScheduleIds)
+	executionBuilder := s.getBuilder(testDomainID, we)
+	s.Equal(int64(7), executionBuilder.GetExecutionInfo().NextEventID)
+	for _, scheduleID := range []int64{5, 6} {
+		ai, ok := executionBuilder.GetActivityInfo(scheduleID)
+		s.True(ok)
+		s.Equal(common.EmptyEventID, ai.StartedID)
+	}
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedEagerActivities_NotRequested() {
+
+	we := commonproto.WorkflowExecution{
+		WorkflowId: "wId",
+		RunId:      testRunID,
+	}
+	tl := "testTaskList"
+	tt := &token.Task{
+		WorkflowId: "wId",
+		RunId:      primitives.MustParseUUID(we.GetRunId()),
+		ScheduleId: 2,
+	}
+	taskToken, _ := tt.Marshal()
+	identity := "testIdentity"
+	s.mockHistoryEngine.config.EagerActivityDispatchLimit = dynamicconfig.GetIntPropertyFilteredByDomain(10)
+
+	msBuilder := newMutableStateBuilderWithEventV2(s.mockHistoryEngine.shard, s.eventsCache,
+		loggerimpl.NewDevelopmentForTest(s.Suite), we.GetRunId())
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*commonproto.Decision{{
+		DecisionType: enums.DecisionTypeScheduleActivityTask,
+		Attributes: &commonproto.Decision_ScheduleActivityTaskDecisionAttributes{ScheduleActivityTaskDecisionAttributes: &commonproto.ScheduleActivityTaskDecisionAttributes{
+			ActivityId:                    "activity1",
+			ActivityType:                  &commonproto.ActivityType{Name: "activity_type1"},
+			TaskList:                      &commonproto.TaskList{Name: tl},
+			Input:                         []byte("input"),
+			ScheduleToCloseTimeoutSeconds: 100,
+			ScheduleToStartTimeoutSeconds: 10,
+			StartToCloseTimeoutSeconds:    50,
+			HeartbeatTimeoutSeconds:       5,
+		}},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything).Return(&persistence.AppendHistoryNodesResponse{Size: 0}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	resp, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &historyservice.RespondDecisionTaskCompletedRequest{
+		DomainUUID: testDomainID,
+		CompleteRequest: &workflowservice.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	s.Empty(resp.EagerActivityScheduleIds)
+}
+
 func (s *engineSuite) TestRespondDecisionTaskCompleted_DecisionHeartbeatTimeout() {
 
 	we := commonproto.WorkflowExecution{
@@ -5283,6 +5409,7 @@ func copyWorkflowExecutionInfo(sourceInfo *persistence.WorkflowExecutionInfo) *p
 		LastFirstEventID:                   sourceInfo.LastFirstEventID,
 		LastEventTaskID:                    sourceInfo.LastEventTaskID,
 		NextEventID:                        sourceInfo.NextEventID,
+		HistoryStreamNextEventID:           sourceInfo.HistoryStreamNextEventID,
 		LastProcessedEvent:                 sourceInfo.LastProcessedEvent,
 		StartTimestamp:                     sourceInfo.StartTimestamp,
 		LastUpdatedTimestamp:               sourceInfo.LastUpdatedTimestamp,
@@ -5315,6 +5442,7 @@ func copyWorkflowExecutionInfo(sourceInfo *persistence.WorkflowExecutionInfo) *p
 		NonRetriableErrors:                 sourceInfo.NonRetriableErrors,
 		BranchToken:                        sourceInfo.BranchToken,
 		ExpirationSeconds:                  sourceInfo.ExpirationSeconds,
+		CompletionCallbacks:                sourceInfo.CompletionCallbacks,
 	}
 }
 
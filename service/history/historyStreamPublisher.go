@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/dgryski/go-farm"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/historystream"
+	"github.com/temporalio/temporal/common/historyfilter"
+	"github.com/temporalio/temporal/common/locks"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/messaging"
+)
+
+const (
+	// historyStreamRedactionNone publishes history events as they are
+	historyStreamRedactionNone = "none"
+	// historyStreamRedactionPayloads clears the user payloads (inputs, results, details) of history events
+	historyStreamRedactionPayloads = "payloads"
+	// historyStreamRedactionAll additionally clears headers, memos and search attributes of history events
+	historyStreamRedactionAll = "all"
+
+	historyStreamRunLockShards = 32
+)
+
+var (
+	errHistoryStreamNotConfigured = errors.New("history stream is enabled but kafka is not configured")
+)
+
+type (
+	// historyStreamPublisher publishes history events to the kafka application configured for
+	// their domain, producers are created on first use and shared by all shards of the host.
+	historyStreamPublisher struct {
+		sync.Mutex
+		messagingClient messaging.Client
+		logger          log.Logger
+		producers       map[string]messaging.Producer
+		// runLock serializes the publishing of the events of a run
+		runLock locks.IDMutex
+	}
+
+	// historyStream is the progress of the history stream of a run and what is needed to publish its events
+	historyStream struct {
+		// ID of the first event not yet published
+		firstEventID int64
+		branchToken  []byte
+		workflowType string
+		taskList     string
+	}
+)
+
+func newHistoryStreamPublisher(
+	messagingClient messaging.Client,
+	logger log.Logger,
+) *historyStreamPublisher {
+	return &historyStreamPublisher{
+		messagingClient: messagingClient,
+		logger:          logger,
+		producers:       make(map[string]messaging.Producer),
+		runLock: locks.NewIDMutex(historyStreamRunLockShards, func(key interface{}) uint32 {
+			return farm.Fingerprint32([]byte(key.(string)))
+		}),
+	}
+}
+
+// lockRun is held while the events of the run are published so they are published in order
+func (p *historyStreamPublisher) lockRun(
+	runID string,
+) {
+	p.runLock.LockID(runID)
+}
+
+func (p *historyStreamPublisher) unlockRun(
+	runID string,
+) {
+	p.runLock.UnlockID(runID)
+}
+
+func (p *historyStreamPublisher) publish(
+	application string,
+	message *historystream.Message,
+) error {
+
+	producer, err := p.getProducer(application)
+	if err != nil {
+		return err
+	}
+	return producer.Publish(message)
+}
+
+func (p *historyStreamPublisher) stop() {
+	p.Lock()
+	defer p.Unlock()
+	for application, producer := range p.producers {
+		if closeableProducer, ok := producer.(messaging.CloseableProducer); ok {
+			if err := closeableProducer.Close(); err != nil {
+				p.logger.Warn("Failed to close history stream producer",
+					tag.Value(application), tag.Error(err))
+			}
+		}
+	}
+	p.producers = make(map[string]messaging.Producer)
+}
+
+func (p *historyStreamPublisher) getProducer(
+	application string,
+) (messaging.Producer, error) {
+
+	if p.messagingClient == nil {
+		return nil, errHistoryStreamNotConfigured
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if producer, ok := p.producers[application]; ok {
+		return producer, nil
+	}
+	producer, err := p.messagingClient.NewProducer(application)
+	if err != nil {
+		return nil, err
+	}
+	p.producers[application] = producer
+	return producer, nil
+}
+
+// redactHistoryEvents clears the fields of the event attributes covered by the redaction in place,
+// an unknown redaction is treated as the most restrictive one.
+func redactHistoryEvents(
+	events []*commonproto.HistoryEvent,
+	redaction string,
+) {
+
+	var fields []string
+	switch redaction {
+	case historyStreamRedactionNone, "":
+		return
+	case historyStreamRedactionPayloads:
+		fields = historyfilter.PayloadFields
+	default:
+		fields = append(append(fields, historyfilter.PayloadFields...), historyfilter.MetadataFields...)
+	}
+	historyfilter.ClearFields(events, fields)
+}
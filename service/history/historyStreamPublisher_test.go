@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/historystream"
+	"github.com/temporalio/temporal/common/log"
+)
+
+type (
+	historyStreamPublisherSuite struct {
+		suite.Suite
+	}
+)
+
+func TestHistoryStreamPublisherSuite(t *testing.T) {
+	s := new(historyStreamPublisherSuite)
+	suite.Run(t, s)
+}
+
+func (s *historyStreamPublisherSuite) TestPublish_NotConfigured() {
+	publisher := newHistoryStreamPublisher(nil, log.NewNoop())
+	err := publisher.publish("history-stream", &historystream.Message{})
+	s.Equal(errHistoryStreamNotConfigured, err)
+}
+
+func (s *historyStreamPublisherSuite) TestRedactHistoryEvents_None() {
+	events := s.newEvents()
+	redactHistoryEvents(events, historyStreamRedactionNone)
+	s.Equal(s.newEvents(), events)
+}
+
+func (s *historyStreamPublisherSuite) TestRedactHistoryEvents_Payloads() {
+	events := s.newEvents()
+	redactHistoryEvents(events, historyStreamRedactionPayloads)
+
+	started := events[0].GetWorkflowExecutionStartedEventAttributes()
+	s.Nil(started.Input)
+	s.Equal("workflow-type", started.WorkflowType.GetName())
+	s.NotNil(started.Header)
+	s.NotNil(started.Memo)
+	s.Nil(events[1].GetActivityTaskCompletedEventAttributes().Result)
+	s.Equal("identity", events[1].GetActivityTaskCompletedEventAttributes().Identity)
+	s.Nil(events[2].GetActivityTaskFailedEventAttributes().Details)
+	s.Equal("reason", events[2].GetActivityTaskFailedEventAttributes().Reason)
+	s.Equal(int64(4), events[3].GetEventId())
+}
+
+func (s *historyStreamPublisherSuite) TestRedactHistoryEvents_All() {
+	events := s.newEvents()
+	redactHistoryEvents(events, historyStreamRedactionAll)
+
+	started := events[0].GetWorkflowExecutionStartedEventAttributes()
+	s.Nil(started.Input)
+	s.Nil(started.Header)
+	s.Nil(started.Memo)
+	s.Equal("workflow-type", started.WorkflowType.GetName())
+	s.Nil(events[1].GetActivityTaskCompletedEventAttributes().Result)
+}
+
+func (s *historyStreamPublisherSuite) TestRedactHistoryEvents_UnknownRedaction() {
+	events := s.newEvents()
+	redactHistoryEvents(events, "unknown")
+
+	started := events[0].GetWorkflowExecutionStartedEventAttributes()
+	s.Nil(started.Input)
+	s.Nil(started.Header)
+	s.Nil(started.Memo)
+}
+
+func (s *historyStreamPublisherSuite) newEvents() []*commonproto.HistoryEvent {
+	return []*commonproto.HistoryEvent{
+		{
+			EventId: 1,
+			Attributes: &commonproto.HistoryEvent_WorkflowExecutionStartedEventAttributes{WorkflowExecutionStartedEventAttributes: &commonproto.WorkflowExecutionStartedEventAttributes{
+				WorkflowType: &commonproto.WorkflowType{Name: "workflow-type"},
+				Input:        []byte("input"),
+				Header:       &commonproto.Header{Fields: map[string][]byte{"key": []byte("value")}},
+				Memo:         &commonproto.Memo{Fields: map[string][]byte{"key": []byte("value")}},
+			}},
+		},
+		{
+			EventId: 2,
+			Attributes: &commonproto.HistoryEvent_ActivityTaskCompletedEventAttributes{ActivityTaskCompletedEventAttributes: &commonproto.ActivityTaskCompletedEventAttributes{
+				Result:   []byte("result"),
+				Identity: "identity",
+			}},
+		},
+		{
+			EventId: 3,
+			Attributes: &commonproto.HistoryEvent_ActivityTaskFailedEventAttributes{ActivityTaskFailedEventAttributes: &commonproto.ActivityTaskFailedEventAttributes{
+				Reason:  "reason",
+				Details: []byte("details"),
+			}},
+		},
+		{
+			EventId: 4,
+		},
+	}
+}
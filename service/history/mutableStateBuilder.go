@@ -39,6 +39,7 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/checksum"
 	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/cluster"
@@ -1692,6 +1693,8 @@ func (e *mutableStateBuilder) addWorkflowExecutionStartedEventForContinueAsNew(
 	); err != nil {
 		return nil, err
 	}
+	// completion callbacks are notified when the whole chain of runs completes
+	e.executionInfo.CompletionCallbacks = callback.CopyAsPending(previousExecutionInfo.CompletionCallbacks)
 
 	if err := e.SetHistoryTree(primitives.MustParseUUID(e.GetExecutionInfo().RunID)); err != nil {
 		return nil, err
@@ -1739,6 +1742,12 @@ func (e *mutableStateBuilder) AddWorkflowExecutionStartedEvent(
 		return nil, e.createInternalServerError(opTag)
 	}
 
+	// completion callbacks are validated against the configured limit by frontend
+	completionCallbacks, err := callback.ParseAll(startRequest.GetCompletionCallbacks(), len(startRequest.GetCompletionCallbacks()))
+	if err != nil {
+		return nil, err
+	}
+
 	event := e.hBuilder.AddWorkflowExecutionStartedEvent(startRequest, nil, execution.GetRunId(), execution.GetRunId())
 
 	var parentDomainID string
@@ -1752,6 +1761,7 @@ func (e *mutableStateBuilder) AddWorkflowExecutionStartedEvent(
 		event); err != nil {
 		return nil, err
 	}
+	e.executionInfo.CompletionCallbacks = completionCallbacks
 	// TODO merge active & passive task generation
 	if err := e.taskGenerator.generateWorkflowStartTasks(
 		e.unixNanoToTime(event.GetTimestamp()),
@@ -4167,6 +4177,11 @@ func (e *mutableStateBuilder) prepareEventsAndReplicationTasks(
 		e.syncActivityToReplicationTask(transactionPolicy)...,
 	)
 
+	e.insertTransferTasks = append(
+		e.insertTransferTasks,
+		e.eventsToHistoryStreamTask(transactionPolicy, workflowEventsSeq)...,
+	)
+
 	if transactionPolicy == transactionPolicyPassive && len(e.insertReplicationTasks) > 0 {
 		return nil, serviceerror.NewInternal("should not generate replication task when close transaction as passive")
 	}
@@ -4221,6 +4236,25 @@ func (e *mutableStateBuilder) eventsToReplicationTask(
 	return []persistence.Task{replicationTask}, nil
 }
 
+func (e *mutableStateBuilder) eventsToHistoryStreamTask(
+	transactionPolicy transactionPolicy,
+	workflowEventsSeq []*persistence.WorkflowEvents,
+) []persistence.Task {
+
+	// history events are only published by the active cluster
+	if transactionPolicy == transactionPolicyPassive ||
+		len(workflowEventsSeq) == 0 ||
+		!e.config.HistoryStreamEnabled(e.GetDomainEntry().GetInfo().Name) {
+		return emptyTasks
+	}
+
+	lastEvents := workflowEventsSeq[len(workflowEventsSeq)-1].Events
+	return []persistence.Task{&persistence.HistoryStreamTask{
+		// TaskID is set by shard, VisibilityTimestamp and Version by setTaskInfo
+		LastEventID: lastEvents[len(lastEvents)-1].GetEventId(),
+	}}
+}
+
 func (e *mutableStateBuilder) syncActivityToReplicationTask(
 	transactionPolicy transactionPolicy,
 ) []persistence.Task {
@@ -161,6 +161,21 @@ func (r *mutableStateTaskGeneratorImpl) generateWorkflowCloseTasks(
 		Version:             currentVersion,
 	})
 
+	// completion callbacks are carried over to the new run on continue as new
+	if executionInfo.CloseStatus != persistence.WorkflowCloseStatusContinuedAsNew {
+		for callbackID, completionCallback := range executionInfo.CompletionCallbacks {
+			if completionCallback.GetState() != persistence.CompletionCallbackStatePending {
+				continue
+			}
+			r.mutableState.AddTransferTasks(&persistence.CompletionCallbackTask{
+				// TaskID is set by shard
+				VisibilityTimestamp: now,
+				CallbackID:          int64(callbackID),
+				Version:             currentVersion,
+			})
+		}
+	}
+
 	retentionInDays := defaultWorkflowRetentionInDays
 	domainEntry, err := r.domainCache.GetDomainByID(executionInfo.DomainID)
 	switch err.(type) {
@@ -32,6 +32,7 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
@@ -307,6 +308,12 @@ func (r *nDCHistoryReplicatorImpl) applyStartEvents(
 		)
 		return err
 	}
+	replicateHistoryStreamNextEventID(mutableState, task)
+	completionCallbacks, err := callback.ParseAll(task.getCompletionCallbacks(), len(task.getCompletionCallbacks()))
+	if err != nil {
+		return err
+	}
+	mutableState.GetExecutionInfo().CompletionCallbacks = completionCallbacks
 
 	err = r.transactionMgr.createWorkflow(
 		ctx,
@@ -412,6 +419,7 @@ func (r *nDCHistoryReplicatorImpl) applyNonStartEventsToCurrentBranch(
 		)
 		return err
 	}
+	replicateHistoryStreamNextEventID(mutableState, task)
 
 	targetWorkflow := newNDCWorkflow(
 		ctx,
@@ -705,6 +713,20 @@ func (r *nDCHistoryReplicatorImpl) notify(
 	r.shard.SetCurrentTime(clusterName, now)
 }
 
+// replicateHistoryStreamNextEventID keeps the progress of the history stream of the source cluster, so that
+// the events are not published again once this cluster becomes active
+func replicateHistoryStreamNextEventID(
+	mutableState mutableState,
+	task nDCReplicationTask,
+) {
+
+	executionInfo := mutableState.GetExecutionInfo()
+	executionInfo.HistoryStreamNextEventID = common.MaxInt64(
+		executionInfo.HistoryStreamNextEventID,
+		task.getHistoryStreamNextEventID(),
+	)
+}
+
 func newNDCRetryTaskErrorWithHint(
 	message string,
 	domainID string,
@@ -52,6 +52,8 @@ type (
 		getNewEvents() []*commonproto.HistoryEvent
 		getLogger() log.Logger
 		getVersionHistory() *persistence.VersionHistory
+		getHistoryStreamNextEventID() int64
+		getCompletionCallbacks() []string
 		isWorkflowReset() bool
 
 		splitTask(taskStartTime time.Time) (nDCReplicationTask, nDCReplicationTask, error)
@@ -68,6 +70,10 @@ type (
 		events         []*commonproto.HistoryEvent
 		newEvents      []*commonproto.HistoryEvent
 		versionHistory *persistence.VersionHistory
+		// ID of the first history event not yet published to the history stream by the source cluster
+		historyStreamNextEventID int64
+		// completion callbacks of the run, only set if the events start the run
+		completionCallbacks []string
 
 		startTime time.Time
 		logger    log.Logger
@@ -153,6 +159,9 @@ func newNDCReplicationTask(
 		newEvents:      newEvents,
 		versionHistory: persistence.NewVersionHistoryFromProto(versionHistory),
 
+		historyStreamNextEventID: request.GetHistoryStreamNextEventId(),
+		completionCallbacks:      request.GetCompletionCallbacks(),
+
 		startTime: taskStartTime,
 		logger:    logger,
 	}, nil
@@ -210,6 +219,14 @@ func (t *nDCReplicationTaskImpl) getVersionHistory() *persistence.VersionHistory
 	return t.versionHistory
 }
 
+func (t *nDCReplicationTaskImpl) getHistoryStreamNextEventID() int64 {
+	return t.historyStreamNextEventID
+}
+
+func (t *nDCReplicationTaskImpl) getCompletionCallbacks() []string {
+	return t.completionCallbacks
+}
+
 func (t *nDCReplicationTaskImpl) isWorkflowReset() bool {
 	switch t.getFirstEvent().GetEventType() {
 	case enums.EventTypeDecisionTaskFailed:
@@ -282,6 +299,8 @@ func (t *nDCReplicationTaskImpl) splitTask(
 		events:         newHistoryEvents,
 		newEvents:      []*commonproto.HistoryEvent{},
 		versionHistory: newVersionHistory,
+		// completion callbacks are carried over to the new run on continue as new
+		completionCallbacks: t.completionCallbacks,
 
 		startTime: taskStartTime,
 		logger:    logger,
@@ -32,6 +32,7 @@ import (
 
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/collection"
 	"github.com/temporalio/temporal/common/definition"
@@ -158,6 +159,14 @@ func (r *nDCStateRebuilderImpl) rebuild(
 		return nil, 0, serviceerror.NewInternal(fmt.Sprintf("nDCStateRebuilder unable to rebuild mutable state to event ID: %v, version: %v", baseLastEventID, baseLastEventVersion))
 	}
 
+	if err := r.restoreCompletionCallbacks(
+		baseWorkflowIdentifier,
+		targetWorkflowIdentifier,
+		rebuiltMutableState,
+	); err != nil {
+		return nil, 0, err
+	}
+
 	// close rebuilt mutable state transaction clearing all generated tasks, etc.
 	_, _, err = rebuiltMutableState.CloseTransactionAsSnapshot(now, transactionPolicyPassive)
 	if err != nil {
@@ -174,6 +183,40 @@ func (r *nDCStateRebuilderImpl) rebuild(
 	return rebuiltMutableState, r.rebuiltHistorySize, nil
 }
 
+// completion callbacks are not part of the history, they are restored from the mutable state of the base workflow
+func (r *nDCStateRebuilderImpl) restoreCompletionCallbacks(
+	baseWorkflowIdentifier definition.WorkflowIdentifier,
+	targetWorkflowIdentifier definition.WorkflowIdentifier,
+	rebuiltMutableState mutableState,
+) error {
+
+	resp, err := r.shard.GetExecutionManager().GetWorkflowExecution(
+		&persistence.GetWorkflowExecutionRequest{
+			DomainID: baseWorkflowIdentifier.DomainID,
+			Execution: commonproto.WorkflowExecution{
+				WorkflowId: baseWorkflowIdentifier.WorkflowID,
+				RunId:      baseWorkflowIdentifier.RunID,
+			},
+		},
+	)
+	switch err.(type) {
+	case nil:
+	case *serviceerror.NotFound:
+		// base workflow is already deleted, e.g. restored from the archive
+		return nil
+	default:
+		return err
+	}
+
+	completionCallbacks := resp.State.ExecutionInfo.CompletionCallbacks
+	if baseWorkflowIdentifier != targetWorkflowIdentifier {
+		// a new run delivers all callbacks again
+		completionCallbacks = callback.CopyAsPending(completionCallbacks)
+	}
+	rebuiltMutableState.GetExecutionInfo().CompletionCallbacks = completionCallbacks
+	return nil
+}
+
 func (r *nDCStateRebuilderImpl) initializeBuilders(
 	domainEntry *cache.DomainCacheEntry,
 ) (mutableState, stateBuilder) {
@@ -35,6 +35,7 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/collection"
 	"github.com/temporalio/temporal/common/definition"
@@ -56,6 +57,7 @@ type (
 		mockClusterMetadata *cluster.MockMetadata
 
 		mockHistoryV2Mgr *mocks.HistoryV2Manager
+		mockExecutionMgr *mocks.ExecutionManager
 		logger           log.Logger
 
 		domainID   string
@@ -89,6 +91,7 @@ func (s *nDCStateRebuilderSuite) SetupTest() {
 	)
 
 	s.mockHistoryV2Mgr = s.mockShard.resource.HistoryMgr
+	s.mockExecutionMgr = s.mockShard.resource.ExecutionMgr
 	s.mockDomainCache = s.mockShard.resource.DomainCache
 	s.mockClusterMetadata = s.mockShard.resource.ClusterMetadata
 	s.mockEventsCache = s.mockShard.mockEventsCache
@@ -312,6 +315,21 @@ func (s *nDCStateRebuilderSuite) TestRebuild() {
 	), nil).AnyTimes()
 	s.mockTaskRefresher.EXPECT().refreshTasks(now, gomock.Any()).Return(nil).Times(1)
 
+	completionCallbacks := []*persistenceblobs.CompletionCallbackInfo{{
+		Url:     "https://example.com/hooks/done",
+		State:   persistence.CompletionCallbackStateSucceeded,
+		Attempt: 1,
+	}}
+	s.mockExecutionMgr.On("GetWorkflowExecution", &persistence.GetWorkflowExecutionRequest{
+		DomainID: s.domainID,
+		Execution: commonproto.WorkflowExecution{
+			WorkflowId: s.workflowID,
+			RunId:      s.runID,
+		},
+	}).Return(&persistence.GetWorkflowExecutionResponse{State: &persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{CompletionCallbacks: completionCallbacks},
+	}}, nil).Once()
+
 	rebuildMutableState, rebuiltHistorySize, err := s.nDCStateRebuilder.rebuild(
 		context.Background(),
 		now,
@@ -337,4 +355,6 @@ func (s *nDCStateRebuilderSuite) TestRebuild() {
 		),
 	), rebuildMutableState.GetVersionHistories())
 	s.Equal(rebuildMutableState.GetExecutionInfo().StartTimestamp, now)
+	// the rebuilt mutable state belongs to another run, which delivers the callbacks again
+	s.Equal(callback.CopyAsPending(completionCallbacks), rebuildExecutionInfo.CompletionCallbacks)
 }
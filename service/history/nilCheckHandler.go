@@ -297,6 +297,18 @@ func (h *NilCheckHandler) GetReplicationMessages(ctx context.Context, request *h
 	return resp, err
 }
 
+func (h *NilCheckHandler) StreamReplicationMessages(server historyservice.HistoryService_StreamReplicationMessagesServer) (retError error) {
+	return h.parentHandler.StreamReplicationMessages(server)
+}
+
+func (h *NilCheckHandler) GetDomainHandoverStatus(ctx context.Context, request *historyservice.GetDomainHandoverStatusRequest) (_ *historyservice.GetDomainHandoverStatusResponse, retError error) {
+	resp, err := h.parentHandler.GetDomainHandoverStatus(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.GetDomainHandoverStatusResponse{}
+	}
+	return resp, err
+}
+
 func (h *NilCheckHandler) GetDLQReplicationMessages(ctx context.Context, request *historyservice.GetDLQReplicationMessagesRequest) (_ *historyservice.GetDLQReplicationMessagesResponse, retError error) {
 	resp, err := h.parentHandler.GetDLQReplicationMessages(ctx, request)
 	if resp == nil && err == nil {
@@ -352,3 +364,19 @@ func (h *NilCheckHandler) RefreshWorkflowTasks(ctx context.Context, request *his
 	}
 	return resp, err
 }
+
+func (h *NilCheckHandler) RehydrateWorkflowExecution(ctx context.Context, request *historyservice.RehydrateWorkflowExecutionRequest) (*historyservice.RehydrateWorkflowExecutionResponse, error) {
+	resp, err := h.parentHandler.RehydrateWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.RehydrateWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
+
+func (h *NilCheckHandler) ImportWorkflowExecution(ctx context.Context, request *historyservice.ImportWorkflowExecutionRequest) (*historyservice.ImportWorkflowExecutionResponse, error) {
+	resp, err := h.parentHandler.ImportWorkflowExecution(ctx, request)
+	if resp == nil && err == nil {
+		resp = &historyservice.ImportWorkflowExecutionResponse{}
+	}
+	return resp, err
+}
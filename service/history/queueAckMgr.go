@@ -45,6 +45,8 @@ type (
 		logger        log.Logger
 		metricsClient metrics.Client
 		finishedChan  chan struct{}
+		// canFinishRead is used by split queues to hold the read open until the merge level is reached
+		canFinishRead func() bool
 
 		sync.RWMutex
 		outstandingTasks map[int64]bool
@@ -90,6 +92,13 @@ func newQueueFailoverAckMgr(shard ShardContext, options *QueueProcessorOptions,
 	}
 }
 
+func newQueueSplitAckMgr(shard ShardContext, options *QueueProcessorOptions, processor processor, ackLevel int64, canFinishRead func() bool, logger log.Logger) *queueAckMgrImpl {
+
+	ackMgr := newQueueFailoverAckMgr(shard, options, processor, ackLevel, logger)
+	ackMgr.canFinishRead = canFinishRead
+	return ackMgr
+}
+
 func (a *queueAckMgrImpl) readQueueTasks() ([]queueTaskInfo, bool, error) {
 	a.RLock()
 	readLevel := a.readLevel
@@ -110,7 +119,7 @@ func (a *queueAckMgrImpl) readQueueTasks() ([]queueTaskInfo, bool, error) {
 
 	a.Lock()
 	defer a.Unlock()
-	if a.isFailover && !morePage {
+	if a.isFailover && !morePage && (a.canFinishRead == nil || a.canFinishRead()) {
 		a.isReadFinished = true
 	}
 
@@ -49,6 +49,8 @@ type (
 		UpdateAckIntervalJitterCoefficient dynamicconfig.FloatPropertyFn
 		MaxRetryCount                      dynamicconfig.IntPropertyFn
 		MetricScope                        int
+		// TaskRetryPolicy defaults to the persistence retry policy if not set
+		TaskRetryPolicy backoff.RetryPolicy
 	}
 
 	queueProcessorBase struct {
@@ -91,6 +93,9 @@ func newQueueProcessorBase(
 	taskProcessorOptions := taskProcessorOptions{
 		queueSize:   options.BatchSize(),
 		workerCount: options.WorkerCount(),
+		retryPolicy: options.TaskRetryPolicy,
+		enableDLQ: options.MetricScope == metrics.TransferActiveQueueProcessorScope ||
+			options.MetricScope == metrics.TransferSplitQueueProcessorScope,
 	}
 	taskProcessor := newTaskProcessor(taskProcessorOptions, shard, historyCache, logger)
 	p := &queueProcessorBase{
@@ -0,0 +1,346 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+	"time"
+
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/common/backoff"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+const (
+	splitQueueTypeTransfer = "transfer"
+	splitQueueTypeTimer    = "timer"
+
+	splitQueueTaskRetryInitialInterval = time.Second
+	splitQueueTaskRetryMaxInterval     = time.Minute
+)
+
+type (
+	// splitQueue is the processing queue of a single domain, split out of the main processing queue of a shard
+	splitQueue interface {
+		Start()
+		Stop()
+		// owns returns true if the task of the split domain is processed by the split queue instead of the main queue
+		owns(task queueTaskInfo) bool
+		// merge hands the domain back to the main queue, the split queue keeps processing the tasks up to the
+		// current read level of the main queue, then it shuts down
+		merge()
+		isIdle() bool
+		notifyNewTasks(tasks []persistence.Task)
+		// getAckLevel returns the task ID for the transfer queue and the unix nanos of the visibility timestamp for the timer queue
+		getAckLevel() int64
+		getReadLevel() int64
+	}
+
+	newSplitQueueFn func(domainID string, ackLevel int64) splitQueue
+
+	splitQueueItem struct {
+		queue           splitQueue
+		splitTime       time.Time
+		lastFailureTime time.Time
+		merging         bool
+	}
+
+	// queueSplitter moves the domains with failing tasks out of the main processing queue of a shard into their own
+	// processing queue, with separate ack level, poll rate and retry backoff. This way a single failing domain holds back
+	// neither the ack level nor the processing of the other domains on the shard. Once a split domain processed its tasks
+	// without failures for a while, it is merged back into the main queue.
+	queueSplitter struct {
+		queueType     string
+		scope         int
+		shard         ShardContext
+		config        *Config
+		metricsClient metrics.Client
+		logger        log.Logger
+		newSplitQueue newSplitQueueFn
+
+		sync.RWMutex
+		splitQueues map[string]*splitQueueItem
+		isStopped   bool
+	}
+)
+
+func newQueueSplitter(
+	queueType string,
+	scope int,
+	shard ShardContext,
+	newSplitQueue newSplitQueueFn,
+	logger log.Logger,
+) *queueSplitter {
+
+	return &queueSplitter{
+		queueType:     queueType,
+		scope:         scope,
+		shard:         shard,
+		config:        shard.GetConfig(),
+		metricsClient: shard.GetMetricsClient(),
+		logger:        logger,
+		newSplitQueue: newSplitQueue,
+		splitQueues:   make(map[string]*splitQueueItem),
+	}
+}
+
+func newSplitQueueTaskRetryPolicy() backoff.RetryPolicy {
+	policy := backoff.NewExponentialRetryPolicy(splitQueueTaskRetryInitialInterval)
+	policy.SetMaximumInterval(splitQueueTaskRetryMaxInterval)
+	policy.SetExpirationInterval(backoff.NoInterval)
+	return policy
+}
+
+func (s *queueSplitter) stop() {
+	s.Lock()
+	s.isStopped = true
+	splitQueues := s.splitQueues
+	s.splitQueues = make(map[string]*splitQueueItem)
+	s.Unlock()
+
+	for _, item := range splitQueues {
+		item.queue.Stop()
+	}
+}
+
+// restore recreates the split queues persisted with the ack level of the main queue, when the shard is loaded
+func (s *queueSplitter) restore(
+	ackLevels map[string]int64,
+) {
+
+	if !s.config.QueueProcessorEnableSplit() {
+		// the persisted ack level of the main queue covers the tasks of the split domains
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.isStopped {
+		return
+	}
+	now := s.shard.GetTimeSource().Now()
+	for domainID, ackLevel := range ackLevels {
+		if _, ok := s.splitQueues[domainID]; ok {
+			continue
+		}
+		if len(s.splitQueues) >= s.config.QueueProcessorMaxSplitQueues() {
+			s.metricsClient.IncCounter(s.scope, metrics.SplitQueueLimitExceededCounter)
+			return
+		}
+
+		item := &splitQueueItem{
+			queue:           s.newSplitQueue(domainID, ackLevel),
+			splitTime:       now,
+			lastFailureTime: now,
+		}
+		s.splitQueues[domainID] = item
+		item.queue.Start()
+
+		s.logger.Info("Split processing queue of domain is restored.",
+			tag.WorkflowDomainID(domainID),
+			tag.AckLevel(ackLevel))
+	}
+}
+
+// isOwnedBySplitQueue returns true if the task has to be skipped by the main queue
+func (s *queueSplitter) isOwnedBySplitQueue(
+	task queueTaskInfo,
+) bool {
+
+	s.RLock()
+	defer s.RUnlock()
+	item, ok := s.splitQueues[primitives.UUIDString(task.GetDomainID())]
+	return ok && item.queue.owns(task)
+}
+
+// reportFailure splits the domain of a task of the main queue into its own processing queue,
+// once the task failed often enough
+func (s *queueSplitter) reportFailure(
+	task queueTaskInfo,
+	attempt int,
+	err error,
+	ackLevel int64,
+) {
+
+	if !s.config.QueueProcessorEnableSplit() || attempt < s.config.QueueProcessorSplitAttemptThreshold() || !isSplitQueueFailure(err) {
+		return
+	}
+
+	domainID := primitives.UUIDString(task.GetDomainID())
+	s.Lock()
+	defer s.Unlock()
+
+	if s.isStopped {
+		return
+	}
+	if item, ok := s.splitQueues[domainID]; ok {
+		// the domain is merging back into the main queue
+		item.lastFailureTime = s.shard.GetTimeSource().Now()
+		return
+	}
+	if len(s.splitQueues) >= s.config.QueueProcessorMaxSplitQueues() {
+		s.metricsClient.IncCounter(s.scope, metrics.SplitQueueLimitExceededCounter)
+		return
+	}
+
+	now := s.shard.GetTimeSource().Now()
+	item := &splitQueueItem{
+		queue:           s.newSplitQueue(domainID, ackLevel),
+		splitTime:       now,
+		lastFailureTime: now,
+	}
+	s.splitQueues[domainID] = item
+	item.queue.Start()
+
+	s.metricsClient.IncCounter(s.scope, metrics.SplitQueueCreatedCounter)
+	s.logger.Warn("Domain is split into its own processing queue.",
+		tag.WorkflowDomainID(domainID),
+		tag.AckLevel(ackLevel),
+		tag.Error(err))
+}
+
+// reportSplitQueueFailure delays merging the domain back into the main queue
+func (s *queueSplitter) reportSplitQueueFailure(
+	domainID string,
+	err error,
+) {
+
+	if !isSplitQueueFailure(err) {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if item, ok := s.splitQueues[domainID]; ok {
+		item.lastFailureTime = s.shard.GetTimeSource().Now()
+	}
+}
+
+// removeSplitQueue is called by the split queue once all its tasks are processed after merging
+func (s *queueSplitter) removeSplitQueue(
+	domainID string,
+) {
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.splitQueues[domainID]; !ok {
+		return
+	}
+	delete(s.splitQueues, domainID)
+
+	s.metricsClient.IncCounter(s.scope, metrics.SplitQueueMergedCounter)
+	s.logger.Info("Domain is merged back into the main processing queue.", tag.WorkflowDomainID(domainID))
+}
+
+// mergeHealthySplitQueues merges back the split domains which caught up, and have not failed for the merge interval
+func (s *queueSplitter) mergeHealthySplitQueues() {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.shard.GetTimeSource().Now()
+	mergeInterval := s.config.QueueProcessorSplitQueueMergeInterval()
+	for domainID, item := range s.splitQueues {
+		if item.merging || now.Sub(item.lastFailureTime) < mergeInterval || !item.queue.isIdle() {
+			continue
+		}
+
+		item.merging = true
+		item.queue.merge()
+		s.logger.Info("Merging domain back into the main processing queue.", tag.WorkflowDomainID(domainID))
+	}
+}
+
+// getMinAckLevel returns the lower of the given ack level of the main queue and the ack levels of the split queues
+func (s *queueSplitter) getMinAckLevel(
+	ackLevel int64,
+) int64 {
+
+	s.RLock()
+	defer s.RUnlock()
+	for _, item := range s.splitQueues {
+		if splitAckLevel := item.queue.getAckLevel(); splitAckLevel < ackLevel {
+			ackLevel = splitAckLevel
+		}
+	}
+	return ackLevel
+}
+
+// getAckLevels returns the ack levels of the split queues by domain ID, they are persisted with the ack level of the main queue
+func (s *queueSplitter) getAckLevels() map[string]int64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	ackLevels := make(map[string]int64, len(s.splitQueues))
+	for domainID, item := range s.splitQueues {
+		ackLevels[domainID] = item.queue.getAckLevel()
+	}
+	return ackLevels
+}
+
+func (s *queueSplitter) notifyNewTasks(
+	tasks []persistence.Task,
+) {
+
+	s.RLock()
+	defer s.RUnlock()
+	for _, item := range s.splitQueues {
+		item.queue.notifyNewTasks(tasks)
+	}
+}
+
+func (s *queueSplitter) describe() []*adminservice.SplitQueueInfo {
+	s.RLock()
+	defer s.RUnlock()
+
+	infos := make([]*adminservice.SplitQueueInfo, 0, len(s.splitQueues))
+	for domainID, item := range s.splitQueues {
+		infos = append(infos, &adminservice.SplitQueueInfo{
+			ShardID:         int32(s.shard.GetShardID()),
+			QueueType:       s.queueType,
+			DomainID:        domainID,
+			AckLevel:        item.queue.getAckLevel(),
+			ReadLevel:       item.queue.getReadLevel(),
+			SplitTime:       item.splitTime.UnixNano(),
+			LastFailureTime: item.lastFailureTime.UnixNano(),
+			Merging:         item.merging,
+		})
+	}
+	return infos
+}
+
+// isSplitQueueFailure returns false for the errors which do not indicate a failing task
+func isSplitQueueFailure(
+	err error,
+) bool {
+
+	switch err.(type) {
+	case nil, *serviceerror.NotFound, *persistence.CurrentWorkflowConditionFailedError:
+		return false
+	}
+	return err != ErrTaskDiscarded && err != ErrTaskRetry
+}
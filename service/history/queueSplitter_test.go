@@ -0,0 +1,246 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	queueSplitterSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller *gomock.Controller
+		mockShard  *shardContextTest
+
+		config      *Config
+		splitQueues map[string]*testSplitQueue
+		splitter    *queueSplitter
+	}
+
+	testSplitQueue struct {
+		domainID string
+		ackLevel int64
+		started  bool
+		stopped  bool
+		merged   bool
+		idle     bool
+		notified int
+	}
+)
+
+func TestQueueSplitterSuite(t *testing.T) {
+	s := new(queueSplitterSuite)
+	suite.Run(t, s)
+}
+
+func (s *queueSplitterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.config = NewDynamicConfigForTest()
+	s.config.QueueProcessorEnableSplit = dynamicconfig.GetBoolPropertyFn(true)
+	s.config.QueueProcessorSplitAttemptThreshold = dynamicconfig.GetIntPropertyFn(3)
+	s.config.QueueProcessorMaxSplitQueues = dynamicconfig.GetIntPropertyFn(2)
+	s.config.QueueProcessorSplitQueueMergeInterval = dynamicconfig.GetDurationPropertyFn(time.Minute)
+
+	s.controller = gomock.NewController(s.T())
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:          1,
+				RangeID:          1,
+				TransferAckLevel: 0,
+			}},
+		s.config,
+	)
+
+	s.splitQueues = make(map[string]*testSplitQueue)
+	s.splitter = newQueueSplitter(
+		splitQueueTypeTransfer,
+		metrics.TransferActiveQueueProcessorScope,
+		s.mockShard,
+		func(domainID string, ackLevel int64) splitQueue {
+			queue := &testSplitQueue{domainID: domainID, ackLevel: ackLevel}
+			s.splitQueues[domainID] = queue
+			return queue
+		},
+		s.mockShard.GetLogger(),
+	)
+}
+
+func (s *queueSplitterSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *queueSplitterSuite) TestReportFailure_SplitsDomain() {
+	domainID := "deadbeef-0123-4567-890a-bcdef0123456"
+	task := s.newTask(domainID, 10)
+	err := errors.New("some random error")
+
+	s.splitter.reportFailure(task, 2, err, 5)
+	s.Empty(s.splitQueues)
+	s.False(s.splitter.isOwnedBySplitQueue(task))
+
+	s.splitter.reportFailure(task, 3, err, 5)
+	s.Len(s.splitQueues, 1)
+	s.True(s.splitQueues[domainID].started)
+	s.Equal(int64(5), s.splitQueues[domainID].ackLevel)
+	s.True(s.splitter.isOwnedBySplitQueue(task))
+	s.False(s.splitter.isOwnedBySplitQueue(s.newTask("deadbeef-0123-4567-890a-bcdef0123457", 11)))
+
+	// failures of an already split domain do not create another split queue
+	s.splitter.reportFailure(task, 4, err, 5)
+	s.Len(s.splitQueues, 1)
+}
+
+func (s *queueSplitterSuite) TestReportFailure_IgnoredErrors() {
+	task := s.newTask("deadbeef-0123-4567-890a-bcdef0123456", 10)
+	for _, err := range []error{
+		serviceerror.NewNotFound("not found"),
+		ErrTaskRetry,
+		ErrTaskDiscarded,
+		&persistence.CurrentWorkflowConditionFailedError{},
+	} {
+		s.splitter.reportFailure(task, 10, err, 5)
+	}
+	s.Empty(s.splitQueues)
+
+	s.config.QueueProcessorEnableSplit = dynamicconfig.GetBoolPropertyFn(false)
+	s.splitter.reportFailure(task, 10, errors.New("some random error"), 5)
+	s.Empty(s.splitQueues)
+}
+
+func (s *queueSplitterSuite) TestReportFailure_LimitExceeded() {
+	err := errors.New("some random error")
+	s.splitter.reportFailure(s.newTask("deadbeef-0123-4567-890a-bcdef0123451", 1), 3, err, 0)
+	s.splitter.reportFailure(s.newTask("deadbeef-0123-4567-890a-bcdef0123452", 2), 3, err, 0)
+	s.splitter.reportFailure(s.newTask("deadbeef-0123-4567-890a-bcdef0123453", 3), 3, err, 0)
+	s.Len(s.splitQueues, 2)
+	s.Len(s.splitter.describe(), 2)
+}
+
+func (s *queueSplitterSuite) TestMergeHealthySplitQueues() {
+	domainID := "deadbeef-0123-4567-890a-bcdef0123456"
+	s.splitter.reportFailure(s.newTask(domainID, 10), 3, errors.New("some random error"), 5)
+	queue := s.splitQueues[domainID]
+
+	// recently failed
+	queue.idle = true
+	s.splitter.mergeHealthySplitQueues()
+	s.False(queue.merged)
+
+	// healthy, but not caught up
+	s.splitter.splitQueues[domainID].lastFailureTime = time.Now().Add(-2 * time.Minute)
+	queue.idle = false
+	s.splitter.mergeHealthySplitQueues()
+	s.False(queue.merged)
+
+	queue.idle = true
+	s.splitter.mergeHealthySplitQueues()
+	s.True(queue.merged)
+	s.True(s.splitter.describe()[0].GetMerging())
+
+	s.splitter.removeSplitQueue(domainID)
+	s.Empty(s.splitter.describe())
+}
+
+func (s *queueSplitterSuite) TestGetMinAckLevel() {
+	err := errors.New("some random error")
+	s.splitter.reportFailure(s.newTask("deadbeef-0123-4567-890a-bcdef0123451", 10), 3, err, 7)
+	s.splitter.reportFailure(s.newTask("deadbeef-0123-4567-890a-bcdef0123452", 10), 3, err, 4)
+
+	s.Equal(int64(4), s.splitter.getMinAckLevel(9))
+	s.Equal(int64(3), s.splitter.getMinAckLevel(3))
+
+	s.splitter.notifyNewTasks([]persistence.Task{&persistence.ActivityTask{}})
+	s.splitter.stop()
+	for _, queue := range s.splitQueues {
+		s.Equal(1, queue.notified)
+		s.True(queue.stopped)
+	}
+	s.Equal(int64(9), s.splitter.getMinAckLevel(9))
+}
+
+func (s *queueSplitterSuite) TestRestore() {
+	s.splitter.restore(map[string]int64{
+		"deadbeef-0123-4567-890a-bcdef0123451": 7,
+		"deadbeef-0123-4567-890a-bcdef0123452": 4,
+		"deadbeef-0123-4567-890a-bcdef0123453": 3,
+	})
+	// restoring is limited like splitting
+	s.Len(s.splitQueues, 2)
+	ackLevels := s.splitter.getAckLevels()
+	s.Len(ackLevels, 2)
+	for domainID, queue := range s.splitQueues {
+		s.True(queue.started)
+		s.Equal(queue.ackLevel, ackLevels[domainID])
+	}
+
+	// restored queues are not merged right away
+	for _, queue := range s.splitQueues {
+		queue.idle = true
+	}
+	s.splitter.mergeHealthySplitQueues()
+	for _, queue := range s.splitQueues {
+		s.False(queue.merged)
+	}
+}
+
+func (s *queueSplitterSuite) TestRestore_SplitDisabled() {
+	s.config.QueueProcessorEnableSplit = dynamicconfig.GetBoolPropertyFn(false)
+	s.splitter.restore(map[string]int64{"deadbeef-0123-4567-890a-bcdef0123451": 7})
+	s.Empty(s.splitQueues)
+	s.Empty(s.splitter.getAckLevels())
+}
+
+func (s *queueSplitterSuite) newTask(domainID string, taskID int64) queueTaskInfo {
+	return &persistenceblobs.TransferTaskInfo{
+		DomainID:            primitives.MustParseUUID(domainID),
+		TaskID:              taskID,
+		VisibilityTimestamp: types.TimestampNow(),
+	}
+}
+
+func (q *testSplitQueue) Start()                                  { q.started = true }
+func (q *testSplitQueue) Stop()                                   { q.stopped = true }
+func (q *testSplitQueue) owns(task queueTaskInfo) bool            { return !q.merged }
+func (q *testSplitQueue) merge()                                  { q.merged = true }
+func (q *testSplitQueue) isIdle() bool                            { return q.idle }
+func (q *testSplitQueue) notifyNewTasks(tasks []persistence.Task) { q.notified++ }
+func (q *testSplitQueue) getAckLevel() int64                      { return q.ackLevel }
+func (q *testSplitQueue) getReadLevel() int64                     { return q.ackLevel }
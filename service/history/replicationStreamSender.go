@@ -0,0 +1,288 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/replication"
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	// replicationStreamServer is the server side of a replication stream opened by a remote cluster.
+	replicationStreamServer interface {
+		Send(*historyservice.StreamReplicationMessagesResponse) error
+		Recv() (*historyservice.StreamReplicationMessagesRequest, error)
+	}
+
+	// replicationTaskNotifier wakes up the replication streams of a shard when new replication tasks are written,
+	// and ends them when the shard engine stops. The zero value is ready to use.
+	replicationTaskNotifier struct {
+		sync.Mutex
+		subscribers map[chan struct{}]struct{}
+		closed      bool
+		closedCh    chan struct{}
+	}
+
+	// replicationStreamSender pushes the replication tasks of a shard to a remote cluster.
+	// At most ReplicationStreamMaxInFlightMessages messages are sent without being acknowledged,
+	// and the replication level of the remote cluster only moves on acknowledgement.
+	replicationStreamSender struct {
+		shard               ShardContext
+		replicatorProcessor ReplicatorQueueProcessor
+		notifier            *replicationTaskNotifier
+		pollingCluster      string
+		stream              replicationStreamServer
+		config              *Config
+		timeSource          clock.TimeSource
+		metricsClient       metrics.Client
+		logger              log.Logger
+	}
+)
+
+func (n *replicationTaskNotifier) subscribe() chan struct{} {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.subscribers == nil {
+		n.subscribers = make(map[chan struct{}]struct{})
+	}
+	notifyCh := make(chan struct{}, 1)
+	n.subscribers[notifyCh] = struct{}{}
+	return notifyCh
+}
+
+func (n *replicationTaskNotifier) unsubscribe(notifyCh chan struct{}) {
+	n.Lock()
+	defer n.Unlock()
+
+	delete(n.subscribers, notifyCh)
+}
+
+// done returns a channel which is closed once the shard engine owning the notifier stops.
+func (n *replicationTaskNotifier) done() <-chan struct{} {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.closedCh == nil {
+		n.closedCh = make(chan struct{})
+	}
+	return n.closedCh
+}
+
+func (n *replicationTaskNotifier) close() {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.closed {
+		return
+	}
+	n.closed = true
+	if n.closedCh == nil {
+		n.closedCh = make(chan struct{})
+	}
+	close(n.closedCh)
+}
+
+func (n *replicationTaskNotifier) notify() {
+	n.Lock()
+	defer n.Unlock()
+
+	for notifyCh := range n.subscribers {
+		select {
+		case notifyCh <- struct{}{}:
+		default:
+			// subscriber already has a pending notification
+		}
+	}
+}
+
+func newReplicationStreamSender(
+	shard ShardContext,
+	replicatorProcessor ReplicatorQueueProcessor,
+	notifier *replicationTaskNotifier,
+	pollingCluster string,
+	stream replicationStreamServer,
+	logger log.Logger,
+) *replicationStreamSender {
+
+	return &replicationStreamSender{
+		shard:               shard,
+		replicatorProcessor: replicatorProcessor,
+		notifier:            notifier,
+		pollingCluster:      pollingCluster,
+		stream:              stream,
+		config:              shard.GetConfig(),
+		timeSource:          shard.GetTimeSource(),
+		metricsClient:       shard.GetMetricsClient(),
+		logger:              logger.WithTags(tag.ClusterName(pollingCluster)),
+	}
+}
+
+// run sends replication messages starting after the given token until the stream is closed.
+// Once the shard engine stops, run fails with ShardOwnershipLostError, so that the remote cluster
+// opens the stream again against the new owner of the shard.
+func (s *replicationStreamSender) run(
+	ctx context.Context,
+	token *replication.ReplicationToken,
+) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	notifyCh := s.notifier.subscribe()
+	defer s.notifier.unsubscribe(notifyCh)
+	doneCh := s.notifier.done()
+
+	ackCh := make(chan *replication.ReplicationToken)
+	recvErrCh := make(chan error, 1)
+	go s.receiveAcks(ctx, ackCh, recvErrCh)
+
+	s.updateReplicationLevel(token.GetLastProcessedMessageId())
+	lastRetrievedMessageID := token.GetLastRetrievedMessageId()
+	if lastRetrievedMessageID == emptyMessageID {
+		lastRetrievedMessageID = s.shard.GetClusterReplicationLevel(s.pollingCluster)
+	}
+
+	heartbeatTimer := time.NewTimer(s.config.ReplicationStreamHeartbeatInterval())
+	defer heartbeatTimer.Stop()
+
+	inFlight := 0
+	readNeeded := true
+	for {
+		select {
+		case <-doneCh:
+			return s.shardClosedError()
+		default:
+		}
+
+		if readNeeded && inFlight < s.config.ReplicationStreamMaxInFlightMessages() {
+			messages, err := s.replicatorProcessor.readMessages(ctx, lastRetrievedMessageID)
+			if err != nil {
+				return err
+			}
+			readNeeded = messages.GetHasMore()
+			lastRetrievedMessageID = messages.GetLastRetrievedMessageId()
+			if len(messages.GetReplicationTasks()) == 0 {
+				continue
+			}
+			if err := s.send(messages); err != nil {
+				return err
+			}
+			inFlight++
+			continue
+		}
+
+		select {
+		case <-notifyCh:
+			readNeeded = true
+		case token := <-ackCh:
+			inFlight--
+			s.updateReplicationLevel(token.GetLastProcessedMessageId())
+		case err := <-recvErrCh:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-heartbeatTimer.C:
+			// Heartbeats carry the shard status to the remote cluster and also cover
+			// replication tasks whose notification was missed.
+			if inFlight < s.config.ReplicationStreamMaxInFlightMessages() {
+				if err := s.send(&replication.ReplicationMessages{
+					LastRetrievedMessageId: lastRetrievedMessageID,
+				}); err != nil {
+					return err
+				}
+				inFlight++
+			}
+			readNeeded = true
+			heartbeatTimer.Reset(s.config.ReplicationStreamHeartbeatInterval())
+		case <-doneCh:
+			return s.shardClosedError()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *replicationStreamSender) shardClosedError() error {
+	return &persistence.ShardOwnershipLostError{
+		ShardID: s.shard.GetShardID(),
+		Msg:     "Shard engine stopped, replication stream closed.",
+	}
+}
+
+func (s *replicationStreamSender) receiveAcks(
+	ctx context.Context,
+	ackCh chan<- *replication.ReplicationToken,
+	recvErrCh chan<- error,
+) {
+
+	for {
+		request, err := s.stream.Recv()
+		if err != nil {
+			recvErrCh <- err
+			return
+		}
+		select {
+		case ackCh <- request.GetToken():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *replicationStreamSender) send(
+	messages *replication.ReplicationMessages,
+) error {
+
+	messages.SyncShardStatus = &replication.SyncShardStatus{
+		Timestamp: s.timeSource.Now().UnixNano(),
+	}
+	if err := s.stream.Send(&historyservice.StreamReplicationMessagesResponse{
+		Messages: messages,
+	}); err != nil {
+		return err
+	}
+	s.metricsClient.IncCounter(metrics.HistoryStreamReplicationMessagesScope, metrics.ReplicationStreamMessagesSent)
+	return nil
+}
+
+func (s *replicationStreamSender) updateReplicationLevel(lastProcessedMessageID int64) {
+	if lastProcessedMessageID == emptyMessageID {
+		return
+	}
+	if err := s.shard.UpdateClusterReplicationLevel(
+		s.pollingCluster,
+		lastProcessedMessageID,
+	); err != nil {
+		s.logger.Error("error updating replication level for shard", tag.Error(err), tag.OperationFailed)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/.gen/proto/replication"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	replicationStreamSenderSuite struct {
+		suite.Suite
+		*require.Assertions
+		controller *gomock.Controller
+
+		mockShard               *shardContextTest
+		mockReplicatorProcessor *MockReplicatorQueueProcessor
+		notifier                *replicationTaskNotifier
+		stream                  *testReplicationStream
+
+		sender *replicationStreamSender
+	}
+
+	testReplicationStream struct {
+		requests  chan *historyservice.StreamReplicationMessagesRequest
+		responses chan *historyservice.StreamReplicationMessagesResponse
+	}
+)
+
+func TestReplicationStreamSenderSuite(t *testing.T) {
+	s := new(replicationStreamSenderSuite)
+	suite.Run(t, s)
+}
+
+func (s *replicationStreamSenderSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:                 0,
+				RangeID:                 1,
+				ClusterReplicationLevel: map[string]int64{"standby": 10},
+			}},
+		NewDynamicConfigForTest(),
+	)
+	// skip persisting the shard info on replication level updates
+	s.mockShard.lastUpdated = time.Now()
+
+	s.mockReplicatorProcessor = NewMockReplicatorQueueProcessor(s.controller)
+	s.notifier = &replicationTaskNotifier{}
+	s.stream = &testReplicationStream{
+		requests:  make(chan *historyservice.StreamReplicationMessagesRequest, 10),
+		responses: make(chan *historyservice.StreamReplicationMessagesResponse, 10),
+	}
+	s.sender = newReplicationStreamSender(
+		s.mockShard,
+		s.mockReplicatorProcessor,
+		s.notifier,
+		"standby",
+		s.stream,
+		log.NewNoop(),
+	)
+}
+
+func (s *replicationStreamSenderSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *replicationStreamSenderSuite) TestRun_SendAndAck() {
+	task := &replication.ReplicationTask{SourceTaskId: 11}
+	s.mockReplicatorProcessor.EXPECT().readMessages(gomock.Any(), int64(10)).Return(&replication.ReplicationMessages{
+		ReplicationTasks:       []*replication.ReplicationTask{task},
+		LastRetrievedMessageId: 11,
+	}, nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.sender.run(context.Background(), &replication.ReplicationToken{
+			LastRetrievedMessageId: emptyMessageID,
+			LastProcessedMessageId: emptyMessageID,
+		})
+	}()
+
+	response := <-s.stream.responses
+	s.Equal([]*replication.ReplicationTask{task}, response.GetMessages().GetReplicationTasks())
+	s.NotNil(response.GetMessages().GetSyncShardStatus())
+
+	s.stream.requests <- &historyservice.StreamReplicationMessagesRequest{
+		Token: &replication.ReplicationToken{LastProcessedMessageId: 11},
+	}
+	close(s.stream.requests)
+
+	s.NoError(<-errCh)
+	s.Equal(int64(11), s.mockShard.GetClusterReplicationLevel("standby"))
+}
+
+func (s *replicationStreamSenderSuite) TestRun_NotifyNewTasks() {
+	task := &replication.ReplicationTask{SourceTaskId: 12}
+	s.mockReplicatorProcessor.EXPECT().readMessages(gomock.Any(), int64(10)).Return(&replication.ReplicationMessages{
+		LastRetrievedMessageId: 10,
+	}, nil)
+	s.mockReplicatorProcessor.EXPECT().readMessages(gomock.Any(), int64(10)).Return(&replication.ReplicationMessages{
+		ReplicationTasks:       []*replication.ReplicationTask{task},
+		LastRetrievedMessageId: 12,
+	}, nil)
+	s.mockReplicatorProcessor.EXPECT().readMessages(gomock.Any(), int64(12)).Return(&replication.ReplicationMessages{
+		LastRetrievedMessageId: 12,
+	}, nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.sender.run(ctx, &replication.ReplicationToken{
+			LastRetrievedMessageId: 10,
+			LastProcessedMessageId: 10,
+		})
+	}()
+
+	s.Eventually(func() bool {
+		s.notifier.notify()
+		return len(s.stream.responses) > 0
+	}, time.Second, 10*time.Millisecond)
+	response := <-s.stream.responses
+	s.Equal(int64(12), response.GetMessages().GetLastRetrievedMessageId())
+
+	cancel()
+	s.NoError(<-errCh)
+	close(s.stream.requests)
+}
+
+func (s *replicationStreamSenderSuite) TestRun_ShardEngineStopped() {
+	s.mockReplicatorProcessor.EXPECT().readMessages(gomock.Any(), int64(10)).Return(&replication.ReplicationMessages{
+		LastRetrievedMessageId: 10,
+	}, nil).AnyTimes()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.sender.run(context.Background(), &replication.ReplicationToken{
+			LastRetrievedMessageId: 10,
+			LastProcessedMessageId: 10,
+		})
+	}()
+
+	s.notifier.close()
+	err := <-errCh
+	s.IsType(&persistence.ShardOwnershipLostError{}, err)
+
+	// acks arriving after the engine stopped do not update the replication level of the closed shard
+	s.stream.requests <- &historyservice.StreamReplicationMessagesRequest{
+		Token: &replication.ReplicationToken{LastProcessedMessageId: 11},
+	}
+	close(s.stream.requests)
+	s.Equal(int64(10), s.mockShard.GetClusterReplicationLevel("standby"))
+}
+
+func (s *testReplicationStream) Send(
+	response *historyservice.StreamReplicationMessagesResponse,
+) error {
+	s.responses <- response
+	return nil
+}
+
+func (s *testReplicationStream) Recv() (*historyservice.StreamReplicationMessagesRequest, error) {
+	request, ok := <-s.requests
+	if !ok {
+		return nil, io.EOF
+	}
+	return request, nil
+}
@@ -271,7 +271,9 @@ func (e *replicationTaskExecutorImpl) handleHistoryReplicationTaskV2(
 		VersionHistoryItems: attr.VersionHistoryItems,
 		Events:              attr.Events,
 		// new run events does not need version history since there is no prior events
-		NewRunEvents: attr.NewRunEvents,
+		NewRunEvents:             attr.NewRunEvents,
+		HistoryStreamNextEventId: attr.HistoryStreamNextEventId,
+		CompletionCallbacks:      attr.CompletionCallbacks,
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), replicationTimeout)
 	defer cancel()
@@ -23,6 +23,8 @@
 package history
 
 import (
+	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -31,30 +33,34 @@ import (
 	"github.com/temporalio/temporal/client"
 	"github.com/temporalio/temporal/client/admin"
 	"github.com/temporalio/temporal/common"
-	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/headers"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
-	"github.com/temporalio/temporal/common/rpc"
+	"github.com/temporalio/temporal/common/metrics"
 	serviceConfig "github.com/temporalio/temporal/common/service/config"
 )
 
 const (
-	fetchTaskRequestTimeout = 60 * time.Second
-	requestChanBufferSize   = 1000
+	requestChanBufferSize = 1000
 )
 
 type (
 	// ReplicationTaskFetcherImpl is the implementation of fetching replication messages.
+	// It keeps one replication stream per shard open against the source cluster.
 	ReplicationTaskFetcherImpl struct {
 		status         int32
 		currentCluster string
 		sourceCluster  string
 		config         *Config
+		metricsClient  metrics.Client
 		logger         log.Logger
 		remotePeer     admin.Client
 		requestChan    chan *request
 		done           chan struct{}
+
+		sync.Mutex
+		streamRequestChans map[int32]chan *request
 	}
 	// ReplicationTaskFetcher is responsible for fetching replication messages from remote DC.
 	ReplicationTaskFetcher interface {
@@ -77,12 +83,22 @@ type (
 		logger   log.Logger
 		fetchers []ReplicationTaskFetcher
 	}
+
+	// replicationStream is an open replication stream for a single shard.
+	replicationStream struct {
+		client   adminservice.AdminService_StreamReplicationMessagesClient
+		messages <-chan *replication.ReplicationMessages
+		// closed once the source cluster closed the stream
+		closed <-chan struct{}
+		cancel context.CancelFunc
+	}
 )
 
 // NewReplicationTaskFetchers creates an instance of ReplicationTaskFetchers with given configs.
 func NewReplicationTaskFetchers(
 	logger log.Logger,
 	config *Config,
+	metricsClient metrics.Client,
 	consumerConfig *serviceConfig.ReplicationConsumerConfig,
 	clusterMetadata cluster.Metadata,
 	clientBean client.Bean,
@@ -103,6 +119,7 @@ func NewReplicationTaskFetchers(
 					clusterName,
 					currentCluster,
 					config,
+					metricsClient,
 					remoteFrontendClient,
 				)
 				fetchers = append(fetchers, fetcher)
@@ -152,18 +169,21 @@ func newReplicationTaskFetcher(
 	sourceCluster string,
 	currentCluster string,
 	config *Config,
+	metricsClient metrics.Client,
 	sourceFrontend admin.Client,
 ) *ReplicationTaskFetcherImpl {
 
 	return &ReplicationTaskFetcherImpl{
-		status:         common.DaemonStatusInitialized,
-		config:         config,
-		logger:         logger.WithTags(tag.ClusterName(sourceCluster)),
-		remotePeer:     sourceFrontend,
-		currentCluster: currentCluster,
-		sourceCluster:  sourceCluster,
-		requestChan:    make(chan *request, requestChanBufferSize),
-		done:           make(chan struct{}),
+		status:             common.DaemonStatusInitialized,
+		config:             config,
+		metricsClient:      metricsClient,
+		logger:             logger.WithTags(tag.ClusterName(sourceCluster)),
+		remotePeer:         sourceFrontend,
+		currentCluster:     currentCluster,
+		sourceCluster:      sourceCluster,
+		requestChan:        make(chan *request, requestChanBufferSize),
+		done:               make(chan struct{}),
+		streamRequestChans: make(map[int32]chan *request),
 	}
 }
 
@@ -173,10 +193,8 @@ func (f *ReplicationTaskFetcherImpl) Start() {
 		return
 	}
 
-	for i := 0; i < f.config.ReplicationTaskFetcherParallelism(); i++ {
-		go f.fetchTasks()
-	}
-	f.logger.Info("Replication task fetcher started.", tag.Counter(f.config.ReplicationTaskFetcherParallelism()))
+	go f.dispatchRequests()
+	f.logger.Info("Replication task fetcher started.")
 }
 
 // Stop stops the fetcher
@@ -189,96 +207,178 @@ func (f *ReplicationTaskFetcherImpl) Stop() {
 	f.logger.Info("Replication task fetcher stopped.")
 }
 
-// fetchTasks collects getReplicationTasks request from shards and send out aggregated request to source frontend.
-func (f *ReplicationTaskFetcherImpl) fetchTasks() {
-	timer := time.NewTimer(backoff.JitDuration(
-		f.config.ReplicationTaskFetcherAggregationInterval(),
-		f.config.ReplicationTaskFetcherTimerJitterCoefficient(),
-	))
-
-	requestByShard := make(map[int32]*request)
-
+// dispatchRequests routes the requests from shard processors to the stream of their shard.
+func (f *ReplicationTaskFetcherImpl) dispatchRequests() {
 	for {
 		select {
 		case request := <-f.requestChan:
-			// Here we only add the request to map. We will wait until timer fires to send the request to remote.
-			if req, ok := requestByShard[request.token.GetShardID()]; ok && req != request {
-				// since this replication task fetcher is per host
-				// and replication task processor is per shard
-				// during shard movement, duplicated requests can appear
-				// if shard moved from this host, to this host.
-				f.logger.Error("Get replication task request already exist for shard.")
-				close(req.respChan)
-			}
-			requestByShard[request.token.GetShardID()] = request
-
-		case <-timer.C:
-			// When timer fires, we collect all the requests we have so far and attempt to send them to remote.
-			err := f.fetchAndDistributeTasks(requestByShard)
-			if err != nil {
-				timer.Reset(backoff.JitDuration(
-					f.config.ReplicationTaskFetcherErrorRetryWait(),
-					f.config.ReplicationTaskFetcherTimerJitterCoefficient(),
-				))
-			} else {
-				timer.Reset(backoff.JitDuration(
-					f.config.ReplicationTaskFetcherAggregationInterval(),
-					f.config.ReplicationTaskFetcherTimerJitterCoefficient(),
-				))
+			select {
+			case f.getStreamRequestChan(request.token.GetShardID()) <- request:
+			case <-f.done:
+				return
 			}
 		case <-f.done:
-			timer.Stop()
 			return
 		}
 	}
 }
 
-func (f *ReplicationTaskFetcherImpl) fetchAndDistributeTasks(requestByShard map[int32]*request) error {
-	if len(requestByShard) == 0 {
-		// We don't receive tasks from previous fetch so processors are all sleeping.
-		f.logger.Debug("Skip fetching as no processor is asking for tasks.")
-		return nil
+func (f *ReplicationTaskFetcherImpl) getStreamRequestChan(shardID int32) chan<- *request {
+	f.Lock()
+	defer f.Unlock()
+
+	requestChan, ok := f.streamRequestChans[shardID]
+	if !ok {
+		requestChan = make(chan *request, 1)
+		f.streamRequestChans[shardID] = requestChan
+		go f.streamTasks(shardID, requestChan)
 	}
+	return requestChan
+}
 
-	messagesByShard, err := f.getMessages(requestByShard)
-	if err != nil {
-		f.logger.Error("Failed to get replication tasks", tag.Error(err))
-		return err
+// streamTasks serves the requests of a single shard processor from its replication stream.
+// The first request of a stream carries the token to start from, every following request
+// acknowledges the message delivered for the previous one.
+func (f *ReplicationTaskFetcherImpl) streamTasks(shardID int32, requestChan <-chan *request) {
+	logger := f.logger.WithTags(tag.ShardID(int(shardID)))
+	var stream *replicationStream
+	closeStream := func() {
+		if stream != nil {
+			stream.cancel()
+			stream = nil
+		}
 	}
+	defer closeStream()
 
-	f.logger.Debug("Successfully fetched replication tasks.", tag.Counter(len(messagesByShard)))
+	idleTimer := time.NewTimer(f.config.ReplicationStreamIdleTimeout())
+	defer idleTimer.Stop()
 
-	for shardID, tasks := range messagesByShard {
-		request := requestByShard[shardID]
-		request.respChan <- tasks
-		close(request.respChan)
-		delete(requestByShard, shardID)
-	}
+	for {
+		select {
+		case request := <-requestChan:
+			var err error
+			if stream != nil {
+				select {
+				case <-stream.closed:
+					if len(stream.messages) == 0 {
+						// the source cluster closed the stream, e.g. because the shard moved to another host,
+						// open it again so that it reaches the new owner of the shard
+						logger.Info("Reopening replication stream closed by source cluster.")
+						closeStream()
+					}
+				default:
+				}
+			}
+			if stream != nil {
+				err = stream.client.Send(&adminservice.StreamReplicationMessagesRequest{
+					Token:       request.token,
+					ClusterName: f.currentCluster,
+				})
+				if err != nil {
+					logger.Info("Reopening replication stream closed by source cluster.", tag.Error(err))
+					closeStream()
+				}
+			}
+			if stream == nil {
+				stream, err = f.openStream(request.token)
+			}
+			if err != nil {
+				logger.Error("Failed to send replication stream request.", tag.Error(err))
+				f.handleStreamFailure(request)
+				closeStream()
+				continue
+			}
 
-	return nil
-}
+			select {
+			case messages, ok := <-stream.messages:
+				if !ok {
+					logger.Warn("Replication stream closed by source cluster.")
+					f.handleStreamFailure(request)
+					closeStream()
+					continue
+				}
+				request.respChan <- messages
+				close(request.respChan)
+			case <-f.done:
+				return
+			}
 
-func (f *ReplicationTaskFetcherImpl) getMessages(
-	requestByShard map[int32]*request,
-) (map[int32]*replication.ReplicationMessages, error) {
-	var tokens []*replication.ReplicationToken
-	for _, request := range requestByShard {
-		tokens = append(tokens, request.token)
-	}
+			if !idleTimer.Stop() {
+				<-idleTimer.C
+			}
+			idleTimer.Reset(f.config.ReplicationStreamIdleTimeout())
 
-	ctx, cancel := rpc.NewContextWithTimeoutAndHeaders(fetchTaskRequestTimeout)
-	defer cancel()
+		case <-idleTimer.C:
+			// The processor of this shard has gone away, most likely because the shard moved to another host.
+			logger.Info("Closing idle replication stream.")
+			closeStream()
+			idleTimer.Reset(f.config.ReplicationStreamIdleTimeout())
 
-	request := &adminservice.GetReplicationMessagesRequest{
-		Tokens:      tokens,
-		ClusterName: f.currentCluster,
+		case <-f.done:
+			return
+		}
 	}
-	response, err := f.remotePeer.GetReplicationMessages(ctx, request)
+}
+
+func (f *ReplicationTaskFetcherImpl) openStream(
+	token *replication.ReplicationToken,
+) (*replicationStream, error) {
+
+	ctx, cancel := context.WithCancel(headers.SetVersions(context.Background()))
+	client, err := f.remotePeer.StreamReplicationMessages(ctx)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	if err := client.Send(&adminservice.StreamReplicationMessagesRequest{
+		Token:       token,
+		ClusterName: f.currentCluster,
+	}); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	messages := make(chan *replication.ReplicationMessages, f.config.ReplicationStreamMaxInFlightMessages())
+	closed := make(chan struct{})
+	go func() {
+		defer close(messages)
+		for {
+			response, err := client.Recv()
+			if err != nil {
+				close(closed)
+				return
+			}
+			select {
+			case messages <- response.GetMessages():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &replicationStream{
+		client:   client,
+		messages: messages,
+		closed:   closed,
+		cancel:   cancel,
+	}, nil
+}
 
-	return response.GetMessagesByShard(), err
+// handleStreamFailure releases the processor waiting on the request so it reopens the stream
+// from its last processed message once the retry wait has passed.
+func (f *ReplicationTaskFetcherImpl) handleStreamFailure(request *request) {
+	f.metricsClient.Scope(
+		metrics.ReplicationTaskFetcherScope,
+		metrics.TargetClusterTag(f.sourceCluster),
+	).IncCounter(metrics.ReplicationStreamFailures)
+
+	timer := time.NewTimer(f.config.ReplicationTaskFetcherErrorRetryWait())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-f.done:
+	}
+	close(request.respChan)
 }
 
 // GetSourceCluster returns the source cluster for the fetcher
@@ -21,6 +21,7 @@
 package history
 
 import (
+	"io"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -75,6 +76,7 @@ func (s *replicationTaskFetcherSuite) SetupTest() {
 		"standby",
 		"active",
 		s.config,
+		s.mockResource.GetMetricsClient(),
 		s.frontendClient,
 	)
 }
@@ -83,59 +85,125 @@ func (s *replicationTaskFetcherSuite) TearDownTest() {
 	s.controller.Finish()
 }
 
-func (s *replicationTaskFetcherSuite) TestGetMessages() {
-	requestByShard := make(map[int32]*request)
+func (s *replicationTaskFetcherSuite) TestOpenStream() {
 	token := &replication.ReplicationToken{
 		ShardID:                0,
 		LastProcessedMessageId: 1,
 		LastRetrievedMessageId: 2,
 	}
-	requestByShard[0] = &request{
-		token: token,
-	}
-	replicationMessageRequest := &adminservice.GetReplicationMessagesRequest{
-		Tokens: []*replication.ReplicationToken{
-			token,
-		},
+	messages := &replication.ReplicationMessages{LastRetrievedMessageId: 3}
+	streamClient := adminservicemock.NewMockAdminService_StreamReplicationMessagesClient(s.controller)
+	s.frontendClient.EXPECT().StreamReplicationMessages(gomock.Any()).Return(streamClient, nil)
+	streamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+		Token:       token,
 		ClusterName: "active",
-	}
-	messageByShared := make(map[int32]*replication.ReplicationMessages)
-	messageByShared[0] = &replication.ReplicationMessages{}
-	expectedResponse := &adminservice.GetReplicationMessagesResponse{
-		MessagesByShard: messageByShared,
-	}
-	s.frontendClient.EXPECT().GetReplicationMessages(gomock.Any(), replicationMessageRequest).Return(expectedResponse, nil)
-	response, err := s.replicationTaskFetcher.getMessages(requestByShard)
+	}).Return(nil)
+	streamClient.EXPECT().Recv().Return(&adminservice.StreamReplicationMessagesResponse{Messages: messages}, nil)
+	streamClient.EXPECT().Recv().Return(nil, io.EOF)
+
+	stream, err := s.replicationTaskFetcher.openStream(token)
 	s.NoError(err)
-	s.Equal(messageByShared, response)
+	defer stream.cancel()
+	s.Equal(messages, <-stream.messages)
+	_, ok := <-stream.messages
+	s.False(ok)
 }
 
-func (s *replicationTaskFetcherSuite) TestFetchAndDistributeTasks() {
-	requestByShard := make(map[int32]*request)
-	token := &replication.ReplicationToken{
+func (s *replicationTaskFetcherSuite) TestStreamTasks() {
+	firstToken := &replication.ReplicationToken{
 		ShardID:                0,
 		LastProcessedMessageId: 1,
+		LastRetrievedMessageId: 1,
+	}
+	secondToken := &replication.ReplicationToken{
+		ShardID:                0,
+		LastProcessedMessageId: 2,
 		LastRetrievedMessageId: 2,
 	}
+	firstMessages := &replication.ReplicationMessages{LastRetrievedMessageId: 2}
+	secondMessages := &replication.ReplicationMessages{LastRetrievedMessageId: 3}
+	streamClient := adminservicemock.NewMockAdminService_StreamReplicationMessagesClient(s.controller)
+	s.frontendClient.EXPECT().StreamReplicationMessages(gomock.Any()).Return(streamClient, nil)
+	gomock.InOrder(
+		streamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+			Token:       firstToken,
+			ClusterName: "active",
+		}).Return(nil),
+		streamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+			Token:       secondToken,
+			ClusterName: "active",
+		}).Return(nil),
+	)
+	gomock.InOrder(
+		streamClient.EXPECT().Recv().Return(&adminservice.StreamReplicationMessagesResponse{Messages: firstMessages}, nil),
+		streamClient.EXPECT().Recv().Return(&adminservice.StreamReplicationMessagesResponse{Messages: secondMessages}, nil),
+		streamClient.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	s.replicationTaskFetcher.Start()
+	defer s.replicationTaskFetcher.Stop()
+
 	respChan := make(chan *replication.ReplicationMessages, 1)
-	requestByShard[0] = &request{
-		token:    token,
-		respChan: respChan,
-	}
-	replicationMessageRequest := &adminservice.GetReplicationMessagesRequest{
-		Tokens: []*replication.ReplicationToken{
-			token,
-		},
-		ClusterName: "active",
+	s.replicationTaskFetcher.GetRequestChan() <- &request{token: firstToken, respChan: respChan}
+	s.Equal(firstMessages, <-respChan)
+
+	respChan = make(chan *replication.ReplicationMessages, 1)
+	s.replicationTaskFetcher.GetRequestChan() <- &request{token: secondToken, respChan: respChan}
+	s.Equal(secondMessages, <-respChan)
+}
+
+func (s *replicationTaskFetcherSuite) TestStreamTasks_ReopenClosedStream() {
+	firstToken := &replication.ReplicationToken{
+		ShardID:                0,
+		LastProcessedMessageId: 1,
+		LastRetrievedMessageId: 1,
 	}
-	messageByShared := make(map[int32]*replication.ReplicationMessages)
-	messageByShared[0] = &replication.ReplicationMessages{}
-	expectedResponse := &adminservice.GetReplicationMessagesResponse{
-		MessagesByShard: messageByShared,
+	secondToken := &replication.ReplicationToken{
+		ShardID:                0,
+		LastProcessedMessageId: 2,
+		LastRetrievedMessageId: 2,
 	}
-	s.frontendClient.EXPECT().GetReplicationMessages(gomock.Any(), replicationMessageRequest).Return(expectedResponse, nil)
-	err := s.replicationTaskFetcher.fetchAndDistributeTasks(requestByShard)
-	s.NoError(err)
-	respToken := <-respChan
-	s.Equal(messageByShared[0], respToken)
+	firstMessages := &replication.ReplicationMessages{LastRetrievedMessageId: 2}
+	secondMessages := &replication.ReplicationMessages{LastRetrievedMessageId: 3}
+
+	// the first stream is closed by the source cluster, e.g. because the shard moved to another host
+	firstStreamClient := adminservicemock.NewMockAdminService_StreamReplicationMessagesClient(s.controller)
+	firstStreamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+		Token:       firstToken,
+		ClusterName: "active",
+	}).Return(nil)
+	firstStreamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+		Token:       secondToken,
+		ClusterName: "active",
+	}).Return(io.EOF).MaxTimes(1)
+	gomock.InOrder(
+		firstStreamClient.EXPECT().Recv().Return(&adminservice.StreamReplicationMessagesResponse{Messages: firstMessages}, nil),
+		firstStreamClient.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	secondStreamClient := adminservicemock.NewMockAdminService_StreamReplicationMessagesClient(s.controller)
+	secondStreamClient.EXPECT().Send(&adminservice.StreamReplicationMessagesRequest{
+		Token:       secondToken,
+		ClusterName: "active",
+	}).Return(nil)
+	gomock.InOrder(
+		secondStreamClient.EXPECT().Recv().Return(&adminservice.StreamReplicationMessagesResponse{Messages: secondMessages}, nil),
+		secondStreamClient.EXPECT().Recv().Return(nil, io.EOF),
+	)
+
+	gomock.InOrder(
+		s.frontendClient.EXPECT().StreamReplicationMessages(gomock.Any()).Return(firstStreamClient, nil),
+		s.frontendClient.EXPECT().StreamReplicationMessages(gomock.Any()).Return(secondStreamClient, nil),
+	)
+
+	s.replicationTaskFetcher.Start()
+	defer s.replicationTaskFetcher.Stop()
+
+	respChan := make(chan *replication.ReplicationMessages, 1)
+	s.replicationTaskFetcher.GetRequestChan() <- &request{token: firstToken, respChan: respChan}
+	s.Equal(firstMessages, <-respChan)
+
+	respChan = make(chan *replication.ReplicationMessages, 1)
+	s.replicationTaskFetcher.GetRequestChan() <- &request{token: secondToken, respChan: respChan}
+	s.Equal(secondMessages, <-respChan)
 }
@@ -74,7 +74,6 @@ type (
 
 		taskRetryPolicy backoff.RetryPolicy
 		dlqRetryPolicy  backoff.RetryPolicy
-		noTaskRetrier   backoff.Retrier
 
 		lastProcessedMessageID int64
 		lastRetrievedMessageID int64
@@ -111,10 +110,6 @@ func NewReplicationTaskProcessor(
 	dlqRetryPolicy := backoff.NewExponentialRetryPolicy(dlqErrorRetryWait)
 	dlqRetryPolicy.SetExpirationInterval(backoff.NoInterval)
 
-	noTaskBackoffPolicy := backoff.NewExponentialRetryPolicy(config.ReplicationTaskProcessorNoTaskRetryWait())
-	noTaskBackoffPolicy.SetBackoffCoefficient(1)
-	noTaskBackoffPolicy.SetExpirationInterval(backoff.NoInterval)
-	noTaskRetrier := backoff.NewRetrier(noTaskBackoffPolicy, backoff.SystemClock)
 	return &ReplicationTaskProcessorImpl{
 		currentCluster:          shard.GetClusterMetadata().GetCurrentClusterName(),
 		sourceCluster:           replicationTaskFetcher.GetSourceCluster(),
@@ -127,7 +122,6 @@ func NewReplicationTaskProcessor(
 		logger:                  shard.GetLogger(),
 		replicationTaskExecutor: replicationTaskExecutor,
 		taskRetryPolicy:         taskRetryPolicy,
-		noTaskRetrier:           noTaskRetrier,
 		requestChan:             replicationTaskFetcher.GetRequestChan(),
 		syncShardChan:           make(chan *replication.SyncShardStatus),
 		done:                    make(chan struct{}),
@@ -272,12 +266,9 @@ func (p *ReplicationTaskProcessorImpl) sendFetchMessageRequest() <-chan *replica
 func (p *ReplicationTaskProcessorImpl) processResponse(response *replication.ReplicationMessages) {
 
 	p.syncShardChan <- response.GetSyncShardStatus()
-	// Note here we check replication tasks instead of hasMore. The expectation is that in a steady state
-	// we will receive replication tasks but hasMore is false (meaning that we are always catching up).
-	// So hasMore might not be a good indicator for additional wait.
+	// The source cluster only pushes a message when it has new tasks or a heartbeat is due,
+	// so an empty message needs no additional wait before it is acknowledged.
 	if len(response.ReplicationTasks) == 0 {
-		backoffDuration := p.noTaskRetrier.NextBackOff()
-		time.Sleep(backoffDuration)
 		return
 	}
 
@@ -293,7 +284,6 @@ func (p *ReplicationTaskProcessorImpl) processResponse(response *replication.Rep
 	p.lastRetrievedMessageID = response.GetLastRetrievedMessageId()
 	scope := p.metricsClient.Scope(metrics.ReplicationTaskFetcherScope, metrics.TargetClusterTag(p.sourceCluster))
 	scope.UpdateGauge(metrics.LastRetrievedMessageID, float64(p.lastRetrievedMessageID))
-	p.noTaskRetrier.Reset()
 }
 
 func (p *ReplicationTaskProcessorImpl) syncShardStatusLoop() {
@@ -33,6 +33,7 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
@@ -442,6 +443,28 @@ func (p *replicatorQueueProcessorImpl) getTasks(
 		lastReadTaskID = p.shard.GetClusterReplicationLevel(pollingCluster)
 	}
 
+	replicationMessages, err := p.readMessages(ctx, lastReadTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.shard.UpdateClusterReplicationLevel(
+		pollingCluster,
+		lastReadTaskID,
+	); err != nil {
+		p.logger.Error("error updating replication level for shard", tag.Error(err), tag.OperationFailed)
+	}
+
+	return replicationMessages, nil
+}
+
+// readMessages reads the replication tasks after lastReadTaskID without
+// moving the replication level of the polling cluster.
+func (p *replicatorQueueProcessorImpl) readMessages(
+	ctx context.Context,
+	lastReadTaskID int64,
+) (*replication.ReplicationMessages, error) {
+
 	taskInfoList, hasMore, err := p.readTasksWithBatchSize(lastReadTaskID, p.fetchTasksBatchSize)
 	if err != nil {
 		return nil, err
@@ -488,13 +511,6 @@ func (p *replicatorQueueProcessorImpl) getTasks(
 		time.Duration(len(replicationTasks)),
 	)
 
-	if err := p.shard.UpdateClusterReplicationLevel(
-		pollingCluster,
-		lastReadTaskID,
-	); err != nil {
-		p.logger.Error("error updating replication level for shard", tag.Error(err), tag.OperationFailed)
-	}
-
 	return &replication.ReplicationMessages{
 		ReplicationTasks:       replicationTasks,
 		HasMore:                hasMore,
@@ -521,6 +537,34 @@ func (p *replicatorQueueProcessorImpl) getTask(
 	return p.toReplicationTask(ctx, &persistence.ReplicationTaskInfoWrapper{ReplicationTaskInfo: task})
 }
 
+// hasPendingDomainTasks returns whether the shard has replication tasks of the domain after the read level.
+func (p *replicatorQueueProcessorImpl) hasPendingDomainTasks(
+	domainID string,
+	readLevel int64,
+) (bool, error) {
+
+	request := &persistence.GetReplicationTasksRequest{
+		ReadLevel:    readLevel,
+		MaxReadLevel: p.shard.GetTransferMaxReadLevel(),
+		BatchSize:    p.fetchTasksBatchSize,
+	}
+	for {
+		response, err := p.executionMgr.GetReplicationTasks(request)
+		if err != nil {
+			return false, err
+		}
+		for _, task := range response.Tasks {
+			if primitives.UUID(task.GetDomainID()).String() == domainID {
+				return true, nil
+			}
+		}
+		if len(response.NextPageToken) == 0 {
+			return false, nil
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+}
+
 func (p *replicatorQueueProcessorImpl) readTasksWithBatchSize(readLevel int64, batchSize int) ([]queueTaskInfo, bool, error) {
 	response, err := p.executionMgr.GetReplicationTasks(&persistence.GetReplicationTasksRequest{
 		ReadLevel:    readLevel,
@@ -720,6 +764,13 @@ func (p *replicatorQueueProcessorImpl) generateHistoryReplicationTask(
 				}
 			}
 
+			// completion callbacks are kept in mutable state only, so they are sent along with the
+			// events which start a run
+			var completionCallbacks []string
+			if task.FirstEventID == common.FirstEventID || len(task.NewRunBranchToken) != 0 {
+				completionCallbacks = callback.Targets(mutableState.GetExecutionInfo().CompletionCallbacks)
+			}
+
 			replicationTask := &replication.ReplicationTask{
 				TaskType: enums.ReplicationTaskTypeHistoryV2,
 				Attributes: &replication.ReplicationTask_HistoryTaskV2Attributes{
@@ -731,6 +782,10 @@ func (p *replicatorQueueProcessorImpl) generateHistoryReplicationTask(
 						VersionHistoryItems: versionHistoryItems,
 						Events:              eventsBlob,
 						NewRunEvents:        newRunEventsBlob,
+						// the progress of the history stream follows the workflow, so that the events are not
+						// published again after a failover
+						HistoryStreamNextEventId: mutableState.GetExecutionInfo().HistoryStreamNextEventID,
+						CompletionCallbacks:      completionCallbacks,
 					},
 				},
 			}
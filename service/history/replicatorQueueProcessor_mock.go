@@ -98,12 +98,42 @@ func (m *MockReplicatorQueueProcessor) getTask(arg0 context.Context, arg1 *repli
 	return ret0, ret1
 }
 
+// hasPendingDomainTasks mocks base method
+func (m *MockReplicatorQueueProcessor) hasPendingDomainTasks(arg0 string, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "hasPendingDomainTasks", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// hasPendingDomainTasks indicates an expected call of hasPendingDomainTasks
+func (mr *MockReplicatorQueueProcessorMockRecorder) hasPendingDomainTasks(arg0 interface{}, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "hasPendingDomainTasks", reflect.TypeOf((*MockReplicatorQueueProcessor)(nil).hasPendingDomainTasks), arg0, arg1)
+}
+
 // getTasks indicates an expected call of getTasks
 func (mr *MockReplicatorQueueProcessorMockRecorder) getTasks(arg0 interface{}, arg1 interface{}, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getTasks", reflect.TypeOf((*MockReplicatorQueueProcessor)(nil).getTasks), arg0, arg1, arg2)
 }
 
+// readMessages mocks base method
+func (m *MockReplicatorQueueProcessor) readMessages(arg0 context.Context, arg1 int64) (*replication.ReplicationMessages, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "readMessages", arg0, arg1)
+	ret0, _ := ret[0].(*replication.ReplicationMessages)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// readMessages indicates an expected call of readMessages
+func (mr *MockReplicatorQueueProcessorMockRecorder) readMessages(arg0 interface{}, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "readMessages", reflect.TypeOf((*MockReplicatorQueueProcessor)(nil).readMessages), arg0, arg1)
+}
+
 // notifyNewTask mocks base method
 func (m *MockReplicatorQueueProcessor) notifyNewTask() {
 	m.ctrl.T.Helper()
@@ -481,6 +481,44 @@ func (s *replicatorQueueProcessorSuite) TestSyncActivity_ActivityRunning() {
 	s.Nil(err)
 }
 
+func (s *replicatorQueueProcessorSuite) TestHasPendingDomainTasks() {
+	readLevel := int64(100)
+	otherDomainTask := &persistenceblobs.ReplicationTaskInfo{
+		TaskID:   readLevel + 1,
+		DomainID: primitives.MustParseUUID(uuid.New()),
+	}
+	domainTask := &persistenceblobs.ReplicationTaskInfo{
+		TaskID:   readLevel + 2,
+		DomainID: primitives.MustParseUUID(testDomainID),
+	}
+	pageToken := []byte("some random page token")
+
+	s.mockExecutionMgr.On("GetReplicationTasks", &persistence.GetReplicationTasksRequest{
+		ReadLevel:    readLevel,
+		MaxReadLevel: s.mockShard.GetTransferMaxReadLevel(),
+		BatchSize:    s.replicatorQueueProcessor.fetchTasksBatchSize,
+	}).Return(&persistence.GetReplicationTasksResponse{
+		Tasks:         []*persistenceblobs.ReplicationTaskInfo{otherDomainTask},
+		NextPageToken: pageToken,
+	}, nil).Twice()
+	s.mockExecutionMgr.On("GetReplicationTasks", &persistence.GetReplicationTasksRequest{
+		ReadLevel:     readLevel,
+		MaxReadLevel:  s.mockShard.GetTransferMaxReadLevel(),
+		BatchSize:     s.replicatorQueueProcessor.fetchTasksBatchSize,
+		NextPageToken: pageToken,
+	}).Return(&persistence.GetReplicationTasksResponse{
+		Tasks: []*persistenceblobs.ReplicationTaskInfo{domainTask},
+	}, nil).Twice()
+
+	hasPendingTasks, err := s.replicatorQueueProcessor.hasPendingDomainTasks(testDomainID, readLevel)
+	s.NoError(err)
+	s.True(hasPendingTasks)
+
+	hasPendingTasks, err = s.replicatorQueueProcessor.hasPendingDomainTasks(uuid.New(), readLevel)
+	s.NoError(err)
+	s.False(hasPendingTasks)
+}
+
 func (s *replicatorQueueProcessorSuite) TestPaginateHistoryWithShardID() {
 	firstEventID := int64(133)
 	nextEventID := int64(134)
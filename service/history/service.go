@@ -65,12 +65,16 @@ type Config struct {
 	HistoryCacheInitialSize dynamicconfig.IntPropertyFn
 	HistoryCacheMaxSize     dynamicconfig.IntPropertyFn
 	HistoryCacheTTL         dynamicconfig.DurationPropertyFn
+	// HistoryCacheMaxSizeInBytes is shared by the history caches of all shards on the host
+	HistoryCacheMaxSizeInBytes dynamicconfig.IntPropertyFn
 
 	// EventsCache settings
 	// Change of these configs require shard restart
 	EventsCacheInitialSize dynamicconfig.IntPropertyFn
 	EventsCacheMaxSize     dynamicconfig.IntPropertyFn
 	EventsCacheTTL         dynamicconfig.DurationPropertyFn
+	// EventsCacheMaxSizeInBytes is shared by the events caches of all shards on the host
+	EventsCacheMaxSizeInBytes dynamicconfig.IntPropertyFn
 
 	// ShardController settings
 	RangeSizeBits           uint
@@ -101,6 +105,8 @@ type Config struct {
 	TimerProcessorMaxTimeShift                       dynamicconfig.DurationPropertyFn
 	TimerProcessorHistoryArchivalSizeLimit           dynamicconfig.IntPropertyFn
 	TimerProcessorArchivalTimeLimit                  dynamicconfig.DurationPropertyFn
+	TimerProcessorEnableInMemoryTimers               dynamicconfig.BoolPropertyFn
+	TimerProcessorInMemoryTimerHorizon               dynamicconfig.DurationPropertyFn
 
 	// TransferQueueProcessor settings
 	TransferTaskBatchSize                               dynamicconfig.IntPropertyFn
@@ -116,6 +122,19 @@ type Config struct {
 	TransferProcessorCompleteTransferInterval           dynamicconfig.DurationPropertyFn
 	TransferProcessorVisibilityArchivalTimeLimit        dynamicconfig.DurationPropertyFn
 
+	// Split processing queue settings, shared by TransferQueueProcessor and TimerQueueProcessor
+	QueueProcessorEnableSplit             dynamicconfig.BoolPropertyFn
+	QueueProcessorSplitAttemptThreshold   dynamicconfig.IntPropertyFn
+	QueueProcessorMaxSplitQueues          dynamicconfig.IntPropertyFn
+	QueueProcessorSplitQueueMaxPollRPS    dynamicconfig.IntPropertyFn
+	QueueProcessorSplitQueueWorkerCount   dynamicconfig.IntPropertyFn
+	QueueProcessorSplitQueueMergeInterval dynamicconfig.DurationPropertyFn
+
+	// Transfer and timer task DLQ settings
+	TaskDLQEnabled            dynamicconfig.BoolPropertyFn
+	TaskDLQMaxRetryCount      dynamicconfig.IntPropertyFn
+	TaskDLQSizeReportInterval dynamicconfig.DurationPropertyFn
+
 	// ReplicatorQueueProcessor settings
 	ReplicatorTaskBatchSize                               dynamicconfig.IntPropertyFn
 	ReplicatorTaskWorkerCount                             dynamicconfig.IntPropertyFn
@@ -182,17 +201,22 @@ type Config struct {
 	// DecisionHeartbeatTimeout is to timeout behavior of: RespondDecisionTaskComplete with ForceCreateNewDecisionTask == true without any decisions
 	// So that decision will be scheduled to another worker(by clear stickyness)
 	DecisionHeartbeatTimeout dynamicconfig.DurationPropertyFnWithDomainFilter
+	// EagerActivityDispatchLimit is the max number of scheduled activities started and returned
+	// to the worker in the RespondDecisionTaskCompleted response, 0 disables eager dispatch
+	EagerActivityDispatchLimit dynamicconfig.IntPropertyFnWithDomainFilter
+	// ActivityConcurrencyLimits are the activity concurrency limits enforced by matching, activities of
+	// a limited type are never dispatched eagerly and their slots are released once they are no longer pending
+	ActivityConcurrencyLimits dynamicconfig.MapPropertyFn
 	// MaxDecisionStartToCloseSeconds is the StartToCloseSeconds for decision
 	MaxDecisionStartToCloseSeconds dynamicconfig.IntPropertyFnWithDomainFilter
 
 	// The following is used by the new RPC replication stack
-	ReplicationTaskFetcherParallelism                dynamicconfig.IntPropertyFn
-	ReplicationTaskFetcherAggregationInterval        dynamicconfig.DurationPropertyFn
-	ReplicationTaskFetcherTimerJitterCoefficient     dynamicconfig.FloatPropertyFn
+	ReplicationStreamMaxInFlightMessages             dynamicconfig.IntPropertyFn
+	ReplicationStreamHeartbeatInterval               dynamicconfig.DurationPropertyFn
+	ReplicationStreamIdleTimeout                     dynamicconfig.DurationPropertyFn
 	ReplicationTaskFetcherErrorRetryWait             dynamicconfig.DurationPropertyFn
 	ReplicationTaskProcessorErrorRetryWait           dynamicconfig.DurationPropertyFn
 	ReplicationTaskProcessorErrorRetryMaxAttempts    dynamicconfig.IntPropertyFn
-	ReplicationTaskProcessorNoTaskRetryWait          dynamicconfig.DurationPropertyFn
 	ReplicationTaskProcessorCleanupInterval          dynamicconfig.DurationPropertyFn
 	ReplicationTaskProcessorCleanupJitterCoefficient dynamicconfig.FloatPropertyFn
 
@@ -205,6 +229,21 @@ type Config struct {
 	MutableStateChecksumGenProbability    dynamicconfig.IntPropertyFnWithDomainFilter
 	MutableStateChecksumVerifyProbability dynamicconfig.IntPropertyFnWithDomainFilter
 	MutableStateChecksumInvalidateBefore  dynamicconfig.FloatPropertyFn
+
+	// Completion callback settings
+	CompletionCallbackMaxAttempts dynamicconfig.IntPropertyFnWithDomainFilter
+	CompletionCallbackTimeout     dynamicconfig.DurationPropertyFnWithDomainFilter
+	// CompletionCallbackAllowedHosts is the list of hosts http completion callbacks may call
+	CompletionCallbackAllowedHosts dynamicconfig.StringPropertyFnWithDomainFilter
+
+	// RehydratedWorkflowTTL is the time a workflow restored from the archive is kept before it is deleted again
+	RehydratedWorkflowTTL dynamicconfig.DurationPropertyFnWithDomainFilter
+
+	// History stream settings
+	HistoryStreamEnabled              dynamicconfig.BoolPropertyFnWithDomainFilter
+	HistoryStreamKafkaApplication     dynamicconfig.StringPropertyFnWithDomainFilter
+	HistoryStreamRedaction            dynamicconfig.StringPropertyFnWithDomainFilter
+	HistoryStreamMaxBatchesPerMessage dynamicconfig.IntPropertyFnWithDomainFilter
 }
 
 const (
@@ -230,9 +269,11 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		HistoryCacheInitialSize:                               dc.GetIntProperty(dynamicconfig.HistoryCacheInitialSize, 128),
 		HistoryCacheMaxSize:                                   dc.GetIntProperty(dynamicconfig.HistoryCacheMaxSize, 512),
 		HistoryCacheTTL:                                       dc.GetDurationProperty(dynamicconfig.HistoryCacheTTL, time.Hour),
+		HistoryCacheMaxSizeInBytes:                            dc.GetIntProperty(dynamicconfig.HistoryCacheMaxSizeInBytes, 0),
 		EventsCacheInitialSize:                                dc.GetIntProperty(dynamicconfig.EventsCacheInitialSize, 128),
 		EventsCacheMaxSize:                                    dc.GetIntProperty(dynamicconfig.EventsCacheMaxSize, 512),
 		EventsCacheTTL:                                        dc.GetDurationProperty(dynamicconfig.EventsCacheTTL, time.Hour),
+		EventsCacheMaxSizeInBytes:                             dc.GetIntProperty(dynamicconfig.EventsCacheMaxSizeInBytes, 0),
 		RangeSizeBits:                                         20, // 20 bits for sequencer, 2^20 sequence number for any range
 		AcquireShardInterval:                                  dc.GetDurationProperty(dynamicconfig.AcquireShardInterval, time.Minute),
 		AcquireShardConcurrency:                               dc.GetIntProperty(dynamicconfig.AcquireShardConcurrency, 1),
@@ -255,6 +296,8 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		TimerProcessorMaxTimeShift:                            dc.GetDurationProperty(dynamicconfig.TimerProcessorMaxTimeShift, 1*time.Second),
 		TimerProcessorHistoryArchivalSizeLimit:                dc.GetIntProperty(dynamicconfig.TimerProcessorHistoryArchivalSizeLimit, 500*1024),
 		TimerProcessorArchivalTimeLimit:                       dc.GetDurationProperty(dynamicconfig.TimerProcessorArchivalTimeLimit, 1*time.Second),
+		TimerProcessorEnableInMemoryTimers:                    dc.GetBoolProperty(dynamicconfig.TimerProcessorEnableInMemoryTimers, false),
+		TimerProcessorInMemoryTimerHorizon:                    dc.GetDurationProperty(dynamicconfig.TimerProcessorInMemoryTimerHorizon, 30*time.Second),
 		TransferTaskBatchSize:                                 dc.GetIntProperty(dynamicconfig.TransferTaskBatchSize, 100),
 		TransferProcessorFailoverMaxPollRPS:                   dc.GetIntProperty(dynamicconfig.TransferProcessorFailoverMaxPollRPS, 1),
 		TransferProcessorMaxPollRPS:                           dc.GetIntProperty(dynamicconfig.TransferProcessorMaxPollRPS, 20),
@@ -267,6 +310,15 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		TransferProcessorUpdateAckIntervalJitterCoefficient:   dc.GetFloat64Property(dynamicconfig.TransferProcessorUpdateAckIntervalJitterCoefficient, 0.15),
 		TransferProcessorCompleteTransferInterval:             dc.GetDurationProperty(dynamicconfig.TransferProcessorCompleteTransferInterval, 60*time.Second),
 		TransferProcessorVisibilityArchivalTimeLimit:          dc.GetDurationProperty(dynamicconfig.TransferProcessorVisibilityArchivalTimeLimit, 200*time.Millisecond),
+		QueueProcessorEnableSplit:                             dc.GetBoolProperty(dynamicconfig.QueueProcessorEnableSplit, false),
+		QueueProcessorSplitAttemptThreshold:                   dc.GetIntProperty(dynamicconfig.QueueProcessorSplitAttemptThreshold, 10),
+		QueueProcessorMaxSplitQueues:                          dc.GetIntProperty(dynamicconfig.QueueProcessorMaxSplitQueues, 10),
+		QueueProcessorSplitQueueMaxPollRPS:                    dc.GetIntProperty(dynamicconfig.QueueProcessorSplitQueueMaxPollRPS, 5),
+		QueueProcessorSplitQueueWorkerCount:                   dc.GetIntProperty(dynamicconfig.QueueProcessorSplitQueueWorkerCount, 2),
+		QueueProcessorSplitQueueMergeInterval:                 dc.GetDurationProperty(dynamicconfig.QueueProcessorSplitQueueMergeInterval, 5*time.Minute),
+		TaskDLQEnabled:                                        dc.GetBoolProperty(dynamicconfig.TaskDLQEnabled, false),
+		TaskDLQMaxRetryCount:                                  dc.GetIntProperty(dynamicconfig.TaskDLQMaxRetryCount, 200),
+		TaskDLQSizeReportInterval:                             dc.GetDurationProperty(dynamicconfig.TaskDLQSizeReportInterval, 5*time.Minute),
 		ReplicatorTaskBatchSize:                               dc.GetIntProperty(dynamicconfig.ReplicatorTaskBatchSize, 100),
 		ReplicatorTaskWorkerCount:                             dc.GetIntProperty(dynamicconfig.ReplicatorTaskWorkerCount, 10),
 		ReplicatorTaskMaxRetryCount:                           dc.GetIntProperty(dynamicconfig.ReplicatorTaskMaxRetryCount, 100),
@@ -311,14 +363,15 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		SearchAttributesTotalSizeLimit:    dc.GetIntPropertyFilteredByDomain(dynamicconfig.SearchAttributesTotalSizeLimit, 40*1024),
 		StickyTTL:                         dc.GetDurationPropertyFilteredByDomain(dynamicconfig.StickyTTL, time.Hour*24*365),
 		DecisionHeartbeatTimeout:          dc.GetDurationPropertyFilteredByDomain(dynamicconfig.DecisionHeartbeatTimeout, time.Minute*30),
+		EagerActivityDispatchLimit:        dc.GetIntPropertyFilteredByDomain(dynamicconfig.EagerActivityDispatchLimit, 0),
+		ActivityConcurrencyLimits:         dc.GetMapProperty(dynamicconfig.MatchingActivityConcurrencyLimits, nil),
 
-		ReplicationTaskFetcherParallelism:                dc.GetIntProperty(dynamicconfig.ReplicationTaskFetcherParallelism, 1),
-		ReplicationTaskFetcherAggregationInterval:        dc.GetDurationProperty(dynamicconfig.ReplicationTaskFetcherAggregationInterval, 2*time.Second),
-		ReplicationTaskFetcherTimerJitterCoefficient:     dc.GetFloat64Property(dynamicconfig.ReplicationTaskFetcherTimerJitterCoefficient, 0.15),
+		ReplicationStreamMaxInFlightMessages:             dc.GetIntProperty(dynamicconfig.ReplicationStreamMaxInFlightMessages, 4),
+		ReplicationStreamHeartbeatInterval:               dc.GetDurationProperty(dynamicconfig.ReplicationStreamHeartbeatInterval, 10*time.Second),
+		ReplicationStreamIdleTimeout:                     dc.GetDurationProperty(dynamicconfig.ReplicationStreamIdleTimeout, 5*time.Minute),
 		ReplicationTaskFetcherErrorRetryWait:             dc.GetDurationProperty(dynamicconfig.ReplicationTaskFetcherErrorRetryWait, time.Second),
 		ReplicationTaskProcessorErrorRetryWait:           dc.GetDurationProperty(dynamicconfig.ReplicationTaskProcessorErrorRetryWait, time.Second),
 		ReplicationTaskProcessorErrorRetryMaxAttempts:    dc.GetIntProperty(dynamicconfig.ReplicationTaskProcessorErrorRetryMaxAttempts, 20),
-		ReplicationTaskProcessorNoTaskRetryWait:          dc.GetDurationProperty(dynamicconfig.ReplicationTaskProcessorNoTaskInitialWait, 2*time.Second),
 		ReplicationTaskProcessorCleanupInterval:          dc.GetDurationProperty(dynamicconfig.ReplicationTaskProcessorCleanupInterval, 1*time.Minute),
 		ReplicationTaskProcessorCleanupJitterCoefficient: dc.GetFloat64Property(dynamicconfig.ReplicationTaskProcessorCleanupJitterCoefficient, 0.15),
 
@@ -328,6 +381,17 @@ func NewConfig(dc *dynamicconfig.Collection, numberOfShards int, storeType strin
 		MutableStateChecksumGenProbability:    dc.GetIntPropertyFilteredByDomain(dynamicconfig.MutableStateChecksumGenProbability, 0),
 		MutableStateChecksumVerifyProbability: dc.GetIntPropertyFilteredByDomain(dynamicconfig.MutableStateChecksumVerifyProbability, 0),
 		MutableStateChecksumInvalidateBefore:  dc.GetFloat64Property(dynamicconfig.MutableStateChecksumInvalidateBefore, 0),
+
+		CompletionCallbackMaxAttempts:  dc.GetIntPropertyFilteredByDomain(dynamicconfig.CompletionCallbackMaxAttempts, 10),
+		CompletionCallbackTimeout:      dc.GetDurationPropertyFilteredByDomain(dynamicconfig.CompletionCallbackTimeout, 10*time.Second),
+		CompletionCallbackAllowedHosts: dc.GetStringPropertyFnWithDomainFilter(dynamicconfig.CompletionCallbackAllowedHosts, ""),
+
+		RehydratedWorkflowTTL: dc.GetDurationPropertyFilteredByDomain(dynamicconfig.RehydratedWorkflowTTL, 24*time.Hour),
+
+		HistoryStreamEnabled:              dc.GetBoolPropertyFnWithDomainFilter(dynamicconfig.HistoryStreamEnabled, false),
+		HistoryStreamKafkaApplication:     dc.GetStringPropertyFnWithDomainFilter(dynamicconfig.HistoryStreamKafkaApplication, common.HistoryStreamAppName),
+		HistoryStreamRedaction:            dc.GetStringPropertyFnWithDomainFilter(dynamicconfig.HistoryStreamRedaction, historyStreamRedactionNone),
+		HistoryStreamMaxBatchesPerMessage: dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryStreamMaxBatchesPerMessage, 10),
 	}
 
 	return cfg
@@ -21,6 +21,7 @@
 package history
 
 import (
+	"fmt"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -54,6 +55,8 @@ type (
 		GetClusterMetadata() cluster.Metadata
 		GetConfig() *Config
 		GetEventsCache() eventsCache
+		GetHistoryCacheBudget() *cache.MemoryBudget
+		GetEventsCacheBudget() *cache.MemoryBudget
 		GetLogger() log.Logger
 		GetThrottledLogger() log.Logger
 		GetMetricsClient() metrics.Client
@@ -78,6 +81,8 @@ type (
 		UpdateTransferAckLevel(ackLevel int64) error
 		GetTransferClusterAckLevel(cluster string) int64
 		UpdateTransferClusterAckLevel(cluster string, ackLevel int64) error
+		GetTransferSplitQueueAckLevels() map[string]int64
+		UpdateTransferSplitQueueAckLevels(cluster string, ackLevel int64, splitQueueAckLevels map[string]int64) error
 
 		GetReplicatorAckLevel() int64
 		UpdateReplicatorAckLevel(ackLevel int64) error
@@ -91,6 +96,8 @@ type (
 		UpdateTimerAckLevel(ackLevel time.Time) error
 		GetTimerClusterAckLevel(cluster string) time.Time
 		UpdateTimerClusterAckLevel(cluster string, ackLevel time.Time) error
+		GetTimerSplitQueueAckLevels() map[string]time.Time
+		UpdateTimerSplitQueueAckLevels(cluster string, ackLevel time.Time, splitQueueAckLevels map[string]time.Time) error
 
 		UpdateTransferFailoverLevel(failoverID string, level persistence.TransferFailoverLevel) error
 		DeleteTransferFailoverLevel(failoverID string) error
@@ -235,6 +242,38 @@ func (s *shardContextImpl) UpdateTransferClusterAckLevel(cluster string, ackLeve
 	return s.updateShardInfoLocked()
 }
 
+func (s *shardContextImpl) GetTransferSplitQueueAckLevels() map[string]int64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	ackLevels := make(map[string]int64, len(s.shardInfo.TransferSplitQueueAckLevel))
+	for domainID, ackLevel := range s.shardInfo.TransferSplitQueueAckLevel {
+		ackLevels[domainID] = ackLevel
+	}
+	return ackLevels
+}
+
+// UpdateTransferSplitQueueAckLevels persists the ack level of the cluster together with the ack levels
+// of the domains split out of its processing queue
+func (s *shardContextImpl) UpdateTransferSplitQueueAckLevels(
+	cluster string,
+	ackLevel int64,
+	splitQueueAckLevels map[string]int64,
+) error {
+
+	s.Lock()
+	defer s.Unlock()
+
+	transferSplitQueueAckLevel := make(map[string]int64, len(splitQueueAckLevels))
+	for domainID, splitQueueAckLevel := range splitQueueAckLevels {
+		transferSplitQueueAckLevel[domainID] = splitQueueAckLevel
+	}
+	s.shardInfo.ClusterTransferAckLevel[cluster] = ackLevel
+	s.shardInfo.TransferSplitQueueAckLevel = transferSplitQueueAckLevel
+	s.shardInfo.StolenSinceRenew = 0
+	return s.updateShardInfoLocked()
+}
+
 func (s *shardContextImpl) GetReplicatorAckLevel() int64 {
 	s.RLock()
 	defer s.RUnlock()
@@ -360,6 +399,48 @@ func (s *shardContextImpl) UpdateTimerClusterAckLevel(cluster string, ackLevel t
 	return s.updateShardInfoLocked()
 }
 
+func (s *shardContextImpl) GetTimerSplitQueueAckLevels() map[string]time.Time {
+	s.RLock()
+	defer s.RUnlock()
+
+	ackLevels := make(map[string]time.Time, len(s.shardInfo.TimerSplitQueueAckLevel))
+	for domainID, ackLevel := range s.shardInfo.TimerSplitQueueAckLevel {
+		goTime, _ := types.TimestampFromProto(ackLevel)
+		ackLevels[domainID] = goTime
+	}
+	return ackLevels
+}
+
+// UpdateTimerSplitQueueAckLevels persists the ack level of the cluster together with the ack levels
+// of the domains split out of its processing queue
+func (s *shardContextImpl) UpdateTimerSplitQueueAckLevels(
+	cluster string,
+	ackLevel time.Time,
+	splitQueueAckLevels map[string]time.Time,
+) error {
+
+	s.Lock()
+	defer s.Unlock()
+
+	pTime, err := types.TimestampProto(ackLevel)
+	if err != nil {
+		return err
+	}
+	timerSplitQueueAckLevel := make(map[string]*types.Timestamp, len(splitQueueAckLevels))
+	for domainID, splitQueueAckLevel := range splitQueueAckLevels {
+		splitQueuePTime, err := types.TimestampProto(splitQueueAckLevel)
+		if err != nil {
+			return err
+		}
+		timerSplitQueueAckLevel[domainID] = splitQueuePTime
+	}
+
+	s.shardInfo.ClusterTimerAckLevel[cluster] = pTime
+	s.shardInfo.TimerSplitQueueAckLevel = timerSplitQueueAckLevel
+	s.shardInfo.StolenSinceRenew = 0
+	return s.updateShardInfoLocked()
+}
+
 func (s *shardContextImpl) UpdateTransferFailoverLevel(failoverID string, level persistence.TransferFailoverLevel) error {
 	s.Lock()
 	defer s.Unlock()
@@ -858,6 +939,20 @@ func (s *shardContextImpl) GetEventsCache() eventsCache {
 	return s.eventsCache
 }
 
+func (s *shardContextImpl) GetHistoryCacheBudget() *cache.MemoryBudget {
+	if s.shardItem == nil {
+		return nil
+	}
+	return s.shardItem.historyCacheBudget
+}
+
+func (s *shardContextImpl) GetEventsCacheBudget() *cache.MemoryBudget {
+	if s.shardItem == nil {
+		return nil
+	}
+	return s.shardItem.eventsCacheBudget
+}
+
 func (s *shardContextImpl) GetLogger() log.Logger {
 	return s.logger
 }
@@ -1204,6 +1299,11 @@ func acquireShard(shardItem *historyShardsItem, closeCh chan<- int) (ShardContex
 		shardItem.logger.Error("Fail to acquire shard.", tag.ShardID(shardItem.shardID), tag.Error(err))
 		return nil, err
 	}
+	if shardInfo.SplitInProgress {
+		// the workflows of the shard are being moved to its child shards
+		return nil, serviceerror.NewUnavailable(fmt.Sprintf("Shard %v is being split.", shardItem.shardID))
+	}
+	shardItem.splitShardCount = int(shardInfo.SplitShardCount)
 
 	updatedShardInfo := copyShardInfo(shardInfo)
 	ownershipChanged := shardInfo.Owner != shardItem.GetHostInfo().Identity()
@@ -1280,20 +1380,32 @@ func copyShardInfo(shardInfo *persistence.ShardInfoWithFailover) *persistence.Sh
 	for k, v := range shardInfo.ClusterReplicationLevel {
 		clusterReplicationLevel[k] = v
 	}
+	transferSplitQueueAckLevel := make(map[string]int64)
+	for k, v := range shardInfo.TransferSplitQueueAckLevel {
+		transferSplitQueueAckLevel[k] = v
+	}
+	timerSplitQueueAckLevel := make(map[string]*types.Timestamp)
+	for k, v := range shardInfo.TimerSplitQueueAckLevel {
+		timerSplitQueueAckLevel[k] = v
+	}
 	shardInfoCopy := &persistence.ShardInfoWithFailover{
 		ShardInfo: &persistenceblobs.ShardInfo{
-			ShardID:                   shardInfo.ShardID,
-			Owner:                     shardInfo.Owner,
-			RangeID:                   shardInfo.RangeID,
-			StolenSinceRenew:          shardInfo.StolenSinceRenew,
-			ReplicationAckLevel:       shardInfo.ReplicationAckLevel,
-			TransferAckLevel:          shardInfo.TransferAckLevel,
-			TimerAckLevel:             shardInfo.TimerAckLevel,
-			ClusterTransferAckLevel:   clusterTransferAckLevel,
-			ClusterTimerAckLevel:      clusterTimerAckLevel,
-			DomainNotificationVersion: shardInfo.DomainNotificationVersion,
-			ClusterReplicationLevel:   clusterReplicationLevel,
-			UpdatedAt:                 shardInfo.UpdatedAt,
+			ShardID:                    shardInfo.ShardID,
+			Owner:                      shardInfo.Owner,
+			RangeID:                    shardInfo.RangeID,
+			StolenSinceRenew:           shardInfo.StolenSinceRenew,
+			ReplicationAckLevel:        shardInfo.ReplicationAckLevel,
+			TransferAckLevel:           shardInfo.TransferAckLevel,
+			TimerAckLevel:              shardInfo.TimerAckLevel,
+			ClusterTransferAckLevel:    clusterTransferAckLevel,
+			ClusterTimerAckLevel:       clusterTimerAckLevel,
+			DomainNotificationVersion:  shardInfo.DomainNotificationVersion,
+			ClusterReplicationLevel:    clusterReplicationLevel,
+			UpdatedAt:                  shardInfo.UpdatedAt,
+			SplitShardCount:            shardInfo.SplitShardCount,
+			SplitInProgress:            shardInfo.SplitInProgress,
+			TransferSplitQueueAckLevel: transferSplitQueueAckLevel,
+			TimerSplitQueueAckLevel:    timerSplitQueueAckLevel,
 		},
 		TransferFailoverLevels: transferFailoverLevels,
 		TimerFailoverLevels:    timerFailoverLevels,
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/membership"
@@ -53,6 +54,8 @@ type (
 		throttledLogger    log.Logger
 		config             *Config
 		metricsScope       metrics.Scope
+		historyCacheBudget *cache.MemoryBudget
+		eventsCacheBudget  *cache.MemoryBudget
 
 		sync.RWMutex
 		historyShards map[int]*historyShardsItem
@@ -68,10 +71,15 @@ type (
 		logger          log.Logger
 		throttledLogger log.Logger
 		engineFactory   EngineFactory
+		// memory budgets shared by the caches of all the shards on the host
+		historyCacheBudget *cache.MemoryBudget
+		eventsCacheBudget  *cache.MemoryBudget
 
 		sync.RWMutex
 		status historyShardsItemStatus
 		engine Engine
+		// splitShardCount is the shard count the shard was split by when the engine was created
+		splitShardCount int
 	}
 )
 
@@ -87,6 +95,7 @@ func newShardController(
 	config *Config,
 ) *shardController {
 	hostIdentity := resource.GetHostInfo().Identity()
+	metricsClient := resource.GetMetricsClient()
 	return &shardController{
 		Resource:           resource,
 		status:             common.DaemonStatusInitialized,
@@ -98,7 +107,9 @@ func newShardController(
 		logger:             resource.GetLogger().WithTags(tag.ComponentShardController, tag.Address(hostIdentity)),
 		throttledLogger:    resource.GetThrottledLogger().WithTags(tag.ComponentShardController, tag.Address(hostIdentity)),
 		config:             config,
-		metricsScope:       resource.GetMetricsClient().Scope(metrics.HistoryShardControllerScope),
+		metricsScope:       metricsClient.Scope(metrics.HistoryShardControllerScope),
+		historyCacheBudget: cache.NewMemoryBudget(config.HistoryCacheMaxSizeInBytes, metricsClient.Scope(metrics.HistoryCacheStatsScope)),
+		eventsCacheBudget:  cache.NewMemoryBudget(config.EventsCacheMaxSizeInBytes, metricsClient.Scope(metrics.EventsCacheStatsScope)),
 	}
 }
 
@@ -107,6 +118,8 @@ func newHistoryShardsItem(
 	shardID int,
 	factory EngineFactory,
 	config *Config,
+	historyCacheBudget *cache.MemoryBudget,
+	eventsCacheBudget *cache.MemoryBudget,
 ) (*historyShardsItem, error) {
 
 	hostIdentity := resource.GetHostInfo().Identity()
@@ -118,6 +131,9 @@ func newHistoryShardsItem(
 		config:          config,
 		logger:          resource.GetLogger().WithTags(tag.ShardID(shardID), tag.Address(hostIdentity)),
 		throttledLogger: resource.GetThrottledLogger().WithTags(tag.ShardID(shardID), tag.Address(hostIdentity)),
+
+		historyCacheBudget: historyCacheBudget,
+		eventsCacheBudget:  eventsCacheBudget,
 	}, nil
 }
 
@@ -156,8 +172,25 @@ func (c *shardController) Stop() {
 }
 
 func (c *shardController) GetEngine(workflowID string) (Engine, error) {
-	shardID := c.config.GetShardID(workflowID)
-	return c.getEngineForShard(shardID)
+	shardID := c.GetHistoryShardRouter().GetShardID(workflowID)
+	sw := c.metricsScope.StartTimer(metrics.GetEngineForShardLatency)
+	defer sw.Stop()
+	item, err := c.getOrCreateHistoryShardItem(shardID)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := item.getOrCreateEngine(c.shardClosedCh)
+	if err != nil {
+		return nil, err
+	}
+
+	// the router of the caller may not know yet that the shard was split
+	if splitShardCount := item.getSplitShardCount(); splitShardCount > 0 {
+		if childShardID := common.WorkflowIDToHistoryShard(workflowID, splitShardCount); childShardID != shardID {
+			return nil, c.createSplitShardOwnershipLostError(childShardID)
+		}
+	}
+	return engine, nil
 }
 
 func (c *shardController) getEngineForShard(shardID int) (Engine, error) {
@@ -170,6 +203,14 @@ func (c *shardController) getEngineForShard(shardID int) (Engine, error) {
 	return item.getOrCreateEngine(c.shardClosedCh)
 }
 
+func (c *shardController) createSplitShardOwnershipLostError(childShardID int) error {
+	info, err := c.GetHistoryServiceResolver().Lookup(string(childShardID))
+	if err != nil {
+		return err
+	}
+	return createShardOwnershipLostError(c.GetHostInfo().Identity(), info.GetAddress())
+}
+
 func (c *shardController) removeEngineForShard(shardID int) {
 	sw := c.metricsScope.StartTimer(metrics.RemoveEngineForShardLatency)
 	defer sw.Stop()
@@ -214,6 +255,8 @@ func (c *shardController) getOrCreateHistoryShardItem(shardID int) (*historyShar
 			shardID,
 			c.engineFactory,
 			c.config,
+			c.historyCacheBudget,
+			c.eventsCacheBudget,
 		)
 		if err != nil {
 			return nil, err
@@ -325,7 +368,7 @@ func (c *shardController) acquireShards() {
 		}()
 	}
 	// Submit tasks to the channel.
-	for shardID := 0; shardID < c.config.NumberOfShards; shardID++ {
+	for _, shardID := range c.GetHistoryShardRouter().GetShardIDs() {
 		shardActionCh <- shardID
 	}
 	close(shardActionCh)
@@ -412,6 +455,12 @@ func (i *historyShardsItem) getOrCreateEngine(shardClosedCh chan<- int) (Engine,
 	}
 }
 
+func (i *historyShardsItem) getSplitShardCount() int {
+	i.RLock()
+	defer i.RUnlock()
+	return i.splitShardCount
+}
+
 func (i *historyShardsItem) stopEngine() {
 	i.Lock()
 	defer i.Unlock()
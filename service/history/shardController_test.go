@@ -45,6 +45,7 @@ import (
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/resource"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
@@ -101,6 +102,7 @@ func (s *shardControllerSuite) TearDownTest() {
 func (s *shardControllerSuite) TestAcquireShardSuccess() {
 	numShards := 10
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -178,6 +180,7 @@ func (s *shardControllerSuite) TestAcquireShardSuccess() {
 func (s *shardControllerSuite) TestAcquireShardsConcurrently() {
 	numShards := 10
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 	s.config.AcquireShardConcurrency = func(opts ...dynamicconfig.FilterOption) int {
 		return 10
 	}
@@ -258,6 +261,7 @@ func (s *shardControllerSuite) TestAcquireShardsConcurrently() {
 func (s *shardControllerSuite) TestAcquireShardLookupFailure() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 	for shardID := 0; shardID < numShards; shardID++ {
 		s.mockServiceResolver.EXPECT().Lookup(string(shardID)).Return(nil, errors.New("ring failure")).Times(1)
 	}
@@ -272,6 +276,7 @@ func (s *shardControllerSuite) TestAcquireShardLookupFailure() {
 func (s *shardControllerSuite) TestAcquireShardRenewSuccess() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -344,6 +349,7 @@ func (s *shardControllerSuite) TestAcquireShardRenewSuccess() {
 func (s *shardControllerSuite) TestAcquireShardRenewLookupFailed() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -416,6 +422,7 @@ func (s *shardControllerSuite) TestAcquireShardRenewLookupFailed() {
 func (s *shardControllerSuite) TestHistoryEngineClosed() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 	s.shardController = newShardController(s.mockResource, s.mockEngineFactory, s.config)
 	historyEngines := make(map[int]*MockEngine)
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -504,6 +511,7 @@ func (s *shardControllerSuite) TestHistoryEngineClosed() {
 func (s *shardControllerSuite) TestRingUpdated() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 	s.shardController = newShardController(s.mockResource, s.mockEngineFactory, s.config)
 	historyEngines := make(map[int]*MockEngine)
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -578,6 +586,7 @@ func (s *shardControllerSuite) TestRingUpdated() {
 func (s *shardControllerSuite) TestShardControllerClosed() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = sharding.NewStaticRouter(numShards)
 	s.shardController = newShardController(s.mockResource, s.mockEngineFactory, s.config)
 	historyEngines := make(map[int]*MockEngine)
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -31,6 +31,7 @@ import (
 	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/persistence"
@@ -638,6 +639,10 @@ func (b *stateBuilderImpl) applyEvents(
 				if err != nil {
 					return nil, err
 				}
+				// completion callbacks are carried over to the new run on continue as new
+				newRunMutableStateBuilder.GetExecutionInfo().CompletionCallbacks = callback.CopyAsPending(
+					b.mutableState.GetExecutionInfo().CompletionCallbacks,
+				)
 			}
 
 			err := b.mutableState.ReplicateWorkflowExecutionContinuedAsNewEvent(
@@ -25,9 +25,11 @@ import (
 
 	"go.temporal.io/temporal-proto/serviceerror"
 
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
 )
 
 type (
@@ -80,6 +82,35 @@ func (t *taskAllocatorImpl) verifyActiveTask(taskDomainID string, task interface
 		t.logger.Debug("Domain is not active, skip task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
 		return false, nil
 	}
+	if domainEntry.IsDomainInHandover() {
+		if isVisibilityTask(task) {
+			// the visibility records neither change the workflow nor create replication tasks
+			return true, nil
+		}
+		// the domain is draining for a graceful failover, its tasks must not change workflows and create
+		// new replication tasks, they are retried until the handover ends
+		t.logger.Debug("Domain is in handover, retry task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
+		return false, domainEntry.GetDomainHandoverErr()
+	}
+	if domainEntry.IsDomainInMigration() {
+		if t.currentClusterName != domainEntry.GetReplicationConfig().ActiveClusterName {
+			// the domain is migrated into this cluster, its tasks are processed as standby tasks until the cutover
+			t.logger.Debug("Domain is migrated into this cluster, skip task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
+			return false, nil
+		}
+		if domainEntry.GetInfo().Status == persistence.DomainStatusDeprecated {
+			// the domain is migrated away, its workflows are served by the other cluster
+			t.logger.Debug("Domain is migrated away, skip task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
+			return false, nil
+		}
+		if isVisibilityTask(task) {
+			// the visibility records list the workflows copied by the cutover of the migration
+			return true, nil
+		}
+		// the domain is locked for the cutover of the migration, the task is retried until the lock is released
+		t.logger.Debug("Domain is locked by migration, retry task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
+		return false, domainEntry.GetDomainMigrationErr()
+	}
 	t.logger.Debug("Domain is active, process task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
 	return true, nil
 }
@@ -111,6 +142,12 @@ func (t *taskAllocatorImpl) verifyStandbyTask(standbyCluster string, taskDomainI
 		t.logger.Warn("Cannot find domain, default to not process task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
 		return false, nil
 	}
+	if !domainEntry.IsGlobalDomain() && domainEntry.IsDomainInMigration() &&
+		domainEntry.GetReplicationConfig().ActiveClusterName == standbyCluster {
+		// the domain is migrated from the standby cluster, its workflows are replicated into this cluster
+		t.logger.Debug("Domain is migrated from standby cluster, process task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
+		return true, nil
+	}
 	if !domainEntry.IsGlobalDomain() {
 		// non global domain, timer task does not belong here
 		t.logger.Debug("Domain is not global, skip task.", tag.WorkflowDomainID(taskDomainID), tag.Value(task))
@@ -124,6 +161,20 @@ func (t *taskAllocatorImpl) verifyStandbyTask(standbyCluster string, taskDomainI
 	return true, nil
 }
 
+// isVisibilityTask returns whether the task only writes the visibility record of a workflow
+func isVisibilityTask(task interface{}) bool {
+	transferTask, ok := task.(*persistenceblobs.TransferTaskInfo)
+	if !ok {
+		return false
+	}
+	switch transferTask.TaskType {
+	case persistence.TransferTaskTypeRecordWorkflowStarted, persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
+		return true
+	default:
+		return false
+	}
+}
+
 // lock block all task allocation
 func (t *taskAllocatorImpl) lock() {
 	t.locker.Lock()
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	taskAllocatorSuite struct {
+		suite.Suite
+		*require.Assertions
+		controller *gomock.Controller
+
+		mockShard           *shardContextTest
+		mockDomainCache     *cache.MockDomainCache
+		mockClusterMetadata *cluster.MockMetadata
+
+		domainID          string
+		allocator         taskAllocator
+		replicationConfig *persistence.DomainReplicationConfig
+	}
+)
+
+func TestTaskAllocatorSuite(t *testing.T) {
+	s := new(taskAllocatorSuite)
+	suite.Run(t, s)
+}
+
+func (s *taskAllocatorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID: 0,
+				RangeID: 1,
+			}},
+		NewDynamicConfigForTest(),
+	)
+	s.mockDomainCache = s.mockShard.resource.DomainCache
+	s.mockClusterMetadata = s.mockShard.resource.ClusterMetadata
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+
+	s.domainID = testDomainID
+	s.replicationConfig = &persistence.DomainReplicationConfig{
+		ActiveClusterName: cluster.TestCurrentClusterName,
+		Clusters: []*persistence.ClusterReplicationConfig{
+			{ClusterName: cluster.TestCurrentClusterName},
+			{ClusterName: cluster.TestAlternativeClusterName},
+		},
+	}
+	s.allocator = newTaskAllocator(s.mockShard)
+}
+
+func (s *taskAllocatorSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *taskAllocatorSuite) TestVerifyActiveTask_DomainInHandover() {
+	s.mockDomainCache.EXPECT().GetDomainByID(s.domainID).Return(cache.NewGlobalDomainCacheEntryInHandoverForTest(
+		&persistence.DomainInfo{ID: s.domainID, Name: testDomainName},
+		&persistence.DomainConfig{},
+		s.replicationConfig,
+		1234,
+		cluster.TestAlternativeClusterName,
+		s.mockClusterMetadata,
+	), nil).Times(2)
+
+	// the timer task would change the workflow and create replication tasks while the domain is draining
+	timerTask := &persistenceblobs.TimerTaskInfo{
+		DomainID: primitives.MustParseUUID(s.domainID),
+		TaskType: persistence.TaskTypeUserTimer,
+	}
+	ok, err := s.allocator.verifyActiveTask(s.domainID, timerTask)
+	s.False(ok)
+	s.IsType(&serviceerror.Unavailable{}, err)
+
+	visibilityTask := &persistenceblobs.TransferTaskInfo{
+		DomainID: primitives.MustParseUUID(s.domainID),
+		TaskType: persistence.TransferTaskTypeRecordWorkflowStarted,
+	}
+	ok, err = s.allocator.verifyActiveTask(s.domainID, visibilityTask)
+	s.True(ok)
+	s.NoError(err)
+}
+
+func (s *taskAllocatorSuite) TestVerifyActiveTask_DomainActive() {
+	s.mockDomainCache.EXPECT().GetDomainByID(s.domainID).Return(cache.NewGlobalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: s.domainID, Name: testDomainName},
+		&persistence.DomainConfig{},
+		s.replicationConfig,
+		1234,
+		s.mockClusterMetadata,
+	), nil).Times(1)
+
+	timerTask := &persistenceblobs.TimerTaskInfo{
+		DomainID: primitives.MustParseUUID(s.domainID),
+		TaskType: persistence.TaskTypeUserTimer,
+	}
+	ok, err := s.allocator.verifyActiveTask(s.domainID, timerTask)
+	s.True(ok)
+	s.NoError(err)
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -copyright_file ../../LICENSE -package $GOPACKAGE -source $GOFILE -destination taskDLQHandler_mock.go -self_package github.com/temporalio/temporal/service/history
+
+package history
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+const (
+	taskDLQCategoryTransfer = "transfer"
+	taskDLQCategoryTimer    = "timer"
+
+	taskDLQSizeSampleSize = 1000
+)
+
+var (
+	errInvalidTaskDLQCategory = serviceerror.NewInvalidArgument("Task category must be transfer or timer.")
+)
+
+type (
+	// taskDLQHandler handles the transfer and timer tasks in the DLQ of a shard
+	taskDLQHandler interface {
+		common.Daemon
+
+		readMessages(
+			category string,
+			exclusiveBeginMessageID int64,
+			inclusiveEndMessageID int64,
+			pageSize int,
+			pageToken []byte,
+		) ([]*adminservice.TaskDLQMessage, []byte, error)
+		purgeMessages(
+			category string,
+			exclusiveBeginMessageID int64,
+			inclusiveEndMessageID int64,
+		) error
+		mergeMessages(
+			category string,
+			exclusiveBeginMessageID int64,
+			inclusiveEndMessageID int64,
+			pageSize int,
+			pageToken []byte,
+		) ([]byte, error)
+	}
+
+	taskDLQHandlerImpl struct {
+		status            int32
+		shard             ShardContext
+		transferProcessor transferQueueProcessor
+		timerProcessor    timerQueueProcessor
+		config            *Config
+		metricsClient     metrics.Client
+		logger            log.Logger
+		shutdownCh        chan struct{}
+	}
+)
+
+func newTaskDLQHandler(
+	shard ShardContext,
+	transferProcessor transferQueueProcessor,
+	timerProcessor timerQueueProcessor,
+) taskDLQHandler {
+
+	return &taskDLQHandlerImpl{
+		status:            common.DaemonStatusInitialized,
+		shard:             shard,
+		transferProcessor: transferProcessor,
+		timerProcessor:    timerProcessor,
+		config:            shard.GetConfig(),
+		metricsClient:     shard.GetMetricsClient(),
+		logger:            shard.GetLogger(),
+		shutdownCh:        make(chan struct{}),
+	}
+}
+
+func (h *taskDLQHandlerImpl) Start() {
+	if !atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	go h.reportSizeLoop()
+}
+
+func (h *taskDLQHandlerImpl) Stop() {
+	if !atomic.CompareAndSwapInt32(&h.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(h.shutdownCh)
+}
+
+func (h *taskDLQHandlerImpl) readMessages(
+	category string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]*adminservice.TaskDLQMessage, []byte, error) {
+
+	tasks, token, err := h.readTasks(category, exclusiveBeginMessageID, inclusiveEndMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]*adminservice.TaskDLQMessage, 0, len(tasks))
+	for _, task := range tasks {
+		messages = append(messages, toTaskDLQMessage(task))
+	}
+	return messages, token, nil
+}
+
+func (h *taskDLQHandlerImpl) purgeMessages(
+	category string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) error {
+
+	return h.deleteTasks(category, exclusiveBeginMessageID, inclusiveEndMessageID)
+}
+
+// mergeMessages executes a page of tasks of the DLQ again, the tasks which succeed are deleted from the DLQ.
+// Merging stops at the first task which fails again.
+func (h *taskDLQHandlerImpl) mergeMessages(
+	category string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]byte, error) {
+
+	tasks, token, err := h.readTasks(category, exclusiveBeginMessageID, inclusiveEndMessageID, pageSize, pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedLevel := exclusiveBeginMessageID
+	for _, task := range tasks {
+		if err := h.executeTask(category, task); err != nil {
+			h.logger.Warn("Failed to merge task from DLQ.",
+				tag.TaskID(task.GetTaskID()), tag.TaskType(task.GetTaskType()), tag.Error(err))
+			if mergedLevel > exclusiveBeginMessageID {
+				if deleteErr := h.deleteTasks(category, exclusiveBeginMessageID, mergedLevel); deleteErr != nil {
+					h.logger.Error("Failed to delete merged tasks from DLQ.", tag.Error(deleteErr))
+				}
+			}
+			return nil, err
+		}
+		mergedLevel = task.GetTaskID()
+	}
+
+	if mergedLevel > exclusiveBeginMessageID {
+		if err := h.deleteTasks(category, exclusiveBeginMessageID, mergedLevel); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+func (h *taskDLQHandlerImpl) executeTask(
+	category string,
+	task queueTaskInfo,
+) error {
+
+	var err error
+	switch category {
+	case taskDLQCategoryTransfer:
+		err = h.transferProcessor.ExecuteDLQTask(task)
+	case taskDLQCategoryTimer:
+		err = h.timerProcessor.ExecuteDLQTask(task)
+	default:
+		return errInvalidTaskDLQCategory
+	}
+
+	if _, ok := err.(*serviceerror.NotFound); ok || err == ErrTaskDiscarded {
+		// the workflow of the task is gone or the task is obsolete, there is nothing left to do
+		return nil
+	}
+	return err
+}
+
+func (h *taskDLQHandlerImpl) readTasks(
+	category string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+	pageSize int,
+	pageToken []byte,
+) ([]queueTaskInfo, []byte, error) {
+
+	switch category {
+	case taskDLQCategoryTransfer:
+		resp, err := h.shard.GetExecutionManager().GetTransferTasksFromDLQ(&persistence.GetTransferTasksFromDLQRequest{
+			GetTransferTasksRequest: persistence.GetTransferTasksRequest{
+				ReadLevel:     exclusiveBeginMessageID,
+				MaxReadLevel:  inclusiveEndMessageID,
+				BatchSize:     pageSize,
+				NextPageToken: pageToken,
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		tasks := make([]queueTaskInfo, 0, len(resp.Tasks))
+		for _, task := range resp.Tasks {
+			tasks = append(tasks, task)
+		}
+		return tasks, resp.NextPageToken, nil
+	case taskDLQCategoryTimer:
+		resp, err := h.shard.GetExecutionManager().GetTimerTasksFromDLQ(&persistence.GetTimerTasksFromDLQRequest{
+			ReadLevel:     exclusiveBeginMessageID,
+			MaxReadLevel:  inclusiveEndMessageID,
+			BatchSize:     pageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		tasks := make([]queueTaskInfo, 0, len(resp.Tasks))
+		for _, task := range resp.Tasks {
+			tasks = append(tasks, task)
+		}
+		return tasks, resp.NextPageToken, nil
+	default:
+		return nil, nil, errInvalidTaskDLQCategory
+	}
+}
+
+func (h *taskDLQHandlerImpl) deleteTasks(
+	category string,
+	exclusiveBeginMessageID int64,
+	inclusiveEndMessageID int64,
+) error {
+
+	switch category {
+	case taskDLQCategoryTransfer:
+		return h.shard.GetExecutionManager().RangeDeleteTransferTaskFromDLQ(&persistence.RangeDeleteTransferTaskFromDLQRequest{
+			ExclusiveBeginTaskID: exclusiveBeginMessageID,
+			InclusiveEndTaskID:   inclusiveEndMessageID,
+		})
+	case taskDLQCategoryTimer:
+		return h.shard.GetExecutionManager().RangeDeleteTimerTaskFromDLQ(&persistence.RangeDeleteTimerTaskFromDLQRequest{
+			ExclusiveBeginTaskID: exclusiveBeginMessageID,
+			InclusiveEndTaskID:   inclusiveEndMessageID,
+		})
+	default:
+		return errInvalidTaskDLQCategory
+	}
+}
+
+func (h *taskDLQHandlerImpl) reportSizeLoop() {
+	timer := time.NewTimer(h.config.TaskDLQSizeReportInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-h.shutdownCh:
+			return
+		case <-timer.C:
+			if h.config.TaskDLQEnabled() {
+				h.reportSize(taskDLQCategoryTransfer, metrics.TransferTaskDLQStatsScope)
+				h.reportSize(taskDLQCategoryTimer, metrics.TimerTaskDLQStatsScope)
+			}
+			timer.Reset(h.config.TaskDLQSizeReportInterval())
+		}
+	}
+}
+
+// reportSize samples the head of the DLQ instead of scanning it, the gauge is capped at
+// taskDLQSizeSampleSize so a large DLQ does not turn the report into a full table scan
+func (h *taskDLQHandlerImpl) reportSize(
+	category string,
+	scopeIdx int,
+) {
+
+	tasks, _, err := h.readTasks(category, 0, math.MaxInt64, taskDLQSizeSampleSize, nil)
+	if err != nil {
+		h.logger.Warn("Failed to read task DLQ size.", tag.Error(err))
+		return
+	}
+
+	h.metricsClient.Scope(
+		scopeIdx,
+		metrics.InstanceTag(strconv.Itoa(h.shard.GetShardID())),
+	).UpdateGauge(metrics.TaskDLQSizeGauge, float64(len(tasks)))
+}
+
+func toTaskDLQMessage(
+	task queueTaskInfo,
+) *adminservice.TaskDLQMessage {
+
+	message := &adminservice.TaskDLQMessage{
+		TaskID:     task.GetTaskID(),
+		TaskType:   task.GetTaskType(),
+		DomainID:   primitives.UUIDString(task.GetDomainID()),
+		WorkflowID: task.GetWorkflowID(),
+		RunID:      primitives.UUIDString(task.GetRunID()),
+		Version:    task.GetVersion(),
+	}
+	if visibilityTime, err := types.TimestampFromProto(task.GetVisibilityTimestamp()); err == nil {
+		message.VisibilityTimestamp = visibilityTime.UnixNano()
+	}
+	switch task := task.(type) {
+	case *persistenceblobs.TransferTaskInfo:
+		message.EventID = task.GetScheduleID()
+	case *persistenceblobs.TimerTaskInfo:
+		message.EventID = task.GetEventID()
+	}
+	return message
+}
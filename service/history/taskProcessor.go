@@ -27,6 +27,7 @@ import (
 	"github.com/gogo/protobuf/types"
 	"go.temporal.io/temporal-proto/serviceerror"
 
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
@@ -42,6 +43,11 @@ type (
 	taskProcessorOptions struct {
 		queueSize   int
 		workerCount int
+		// retryPolicy defaults to the persistence retry policy if not set
+		retryPolicy backoff.RetryPolicy
+		// enableDLQ is only set for active processors, standby tasks stay pending
+		// until the active cluster catches up and are never moved to the DLQ
+		enableDLQ bool
 	}
 
 	taskInfo struct {
@@ -68,6 +74,7 @@ type (
 		metricsClient metrics.Client
 		timeSource    clock.TimeSource
 		retryPolicy   backoff.RetryPolicy
+		enableDLQ     bool
 		workerWG      sync.WaitGroup
 
 		// worker coroutines notification
@@ -104,6 +111,11 @@ func newTaskProcessor(
 		workerNotificationChans = append(workerNotificationChans, make(chan struct{}, 1))
 	}
 
+	retryPolicy := options.retryPolicy
+	if retryPolicy == nil {
+		retryPolicy = common.CreatePersistanceRetryPolicy()
+	}
+
 	base := &taskProcessor{
 		shard:                   shard,
 		cache:                   historyCache,
@@ -114,7 +126,8 @@ func newTaskProcessor(
 		metricsClient:           shard.GetMetricsClient(),
 		timeSource:              shard.GetTimeSource(),
 		workerNotificationChans: workerNotificationChans,
-		retryPolicy:             common.CreatePersistanceRetryPolicy(),
+		retryPolicy:             retryPolicy,
+		enableDLQ:               options.enableDLQ,
 		numOfWorker:             options.workerCount,
 	}
 
@@ -210,6 +223,9 @@ FilterLoop:
 				task.logger.Error("Critical error processing task, retrying.",
 					tag.Error(err), tag.OperationCritical, tag.TaskType(task.task.GetTaskType()))
 			}
+			if t.shouldMoveTaskToDLQ(task, err) {
+				return t.moveTaskToDLQ(scope, task, err)
+			}
 		}
 		return err
 	}
@@ -308,6 +324,70 @@ func (t *taskProcessor) handleTaskError(
 	return err
 }
 
+func (t *taskProcessor) shouldMoveTaskToDLQ(
+	task *taskInfo,
+	err error,
+) bool {
+
+	if !t.enableDLQ || !t.config.TaskDLQEnabled() || task.attempt < t.config.TaskDLQMaxRetryCount() {
+		return false
+	}
+	return !isRetryableTaskError(err)
+}
+
+// isRetryableTaskError returns true for errors which don't mean that the task failed: the task waits
+// for the standby cluster, the domain failed over or the shard was lost. Tasks failing with these errors
+// are retried until they succeed, all other errors, transient ones included, count against the retries
+// of the task so that a task failing forever doesn't block the queue.
+func isRetryableTaskError(
+	err error,
+) bool {
+
+	if err == ErrTaskRetry {
+		return true
+	}
+	switch err.(type) {
+	case *serviceerror.DomainNotActive,
+		*persistence.ShardOwnershipLostError:
+		return true
+	}
+	return false
+}
+
+// moveTaskToDLQ puts a transfer or timer task which ran out of retries into the DLQ of the shard,
+// the task is acked if this succeeds. Other tasks are retried as before.
+func (t *taskProcessor) moveTaskToDLQ(
+	scope metrics.Scope,
+	task *taskInfo,
+	taskErr error,
+) error {
+
+	var err error
+	switch info := task.task.(type) {
+	case *persistenceblobs.TransferTaskInfo:
+		err = t.shard.GetExecutionManager().PutTransferTaskToDLQ(&persistence.PutTransferTaskToDLQRequest{
+			TaskInfo: info,
+		})
+	case *persistenceblobs.TimerTaskInfo:
+		err = t.shard.GetExecutionManager().PutTimerTaskToDLQ(&persistence.PutTimerTaskToDLQRequest{
+			TaskInfo: info,
+		})
+	default:
+		return taskErr
+	}
+
+	if err != nil {
+		scope.IncCounter(metrics.TaskDLQEnqueueFailedCounter)
+		task.logger.Error("Fail to put task to DLQ.", tag.Error(err))
+		return taskErr
+	}
+
+	scope.IncCounter(metrics.TaskDLQEnqueuedCounter)
+	task.logger.Warn("Task moved to DLQ after exhausting retries.",
+		tag.Error(taskErr), tag.Attempt(int32(task.attempt)), tag.TaskID(task.task.GetTaskID()))
+	return nil
+}
+
 func (t *taskProcessor) ackTaskOnce(
 	scope metrics.Scope,
 	task *taskInfo,
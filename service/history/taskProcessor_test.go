@@ -37,6 +37,7 @@ import (
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
 type (
@@ -243,3 +244,60 @@ func (s *taskProcessorSuite) TestHandleTaskError_RandomErr() {
 	taskInfo := newTaskInfo(s.mockProcessor, nil, s.logger)
 	s.Equal(err, s.taskProcessor.handleTaskError(s.scope, taskInfo, s.notificationChan, err))
 }
+
+func (s *taskProcessorSuite) TestShouldMoveTaskToDLQ() {
+	s.mockShard.GetConfig().TaskDLQEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	s.mockShard.GetConfig().TaskDLQMaxRetryCount = dynamicconfig.GetIntPropertyFn(3)
+	s.taskProcessor.enableDLQ = true
+
+	taskInfo := newTaskInfo(s.mockProcessor, nil, s.logger)
+	err := errors.New("random error")
+	s.False(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, err))
+
+	taskInfo.attempt = 3
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, err))
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, serviceerror.NewInvalidArgument("invalid task")))
+
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, serviceerror.NewInternal("internal")))
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, serviceerror.NewUnavailable("unavailable")))
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, serviceerror.NewResourceExhausted("busy")))
+	s.True(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, &persistence.TimeoutError{Msg: "timeout"}))
+
+	s.False(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, ErrTaskRetry))
+	s.False(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, serviceerror.NewDomainNotActive("domain", "active", "standby")))
+	s.False(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, &persistence.ShardOwnershipLostError{ShardID: 0, Msg: "lost"}))
+}
+
+func (s *taskProcessorSuite) TestProcessTaskAndAck_InternalErr_MovedToDLQ() {
+	s.mockShard.GetConfig().TaskDLQEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	s.mockShard.GetConfig().TaskDLQMaxRetryCount = dynamicconfig.GetIntPropertyFn(2)
+	s.taskProcessor.enableDLQ = true
+
+	timerTask := &persistenceblobs.TimerTaskInfo{TaskID: 12345, VisibilityTimestamp: types.TimestampNow()}
+	task := newTaskInfo(s.mockProcessor, timerTask, s.logger)
+	var taskFilter taskFilter = func(task queueTaskInfo) (bool, error) {
+		return true, nil
+	}
+	s.mockProcessor.On("getTaskFilter").Return(taskFilter).Once()
+	s.mockProcessor.On("process", task).Return(s.scopeIdx, serviceerror.NewInternal("internal")).Twice()
+	s.mockShard.resource.ExecutionMgr.On("PutTimerTaskToDLQ", &persistence.PutTimerTaskToDLQRequest{
+		TaskInfo: timerTask,
+	}).Return(nil).Once()
+	s.mockProcessor.On("complete", task).Once()
+	s.mockShard.resource.DomainCache.EXPECT().GetDomainName(gomock.Any()).Return(testDomainName, nil).Times(2)
+	s.taskProcessor.processTaskAndAck(
+		s.notificationChan,
+		task,
+	)
+	s.Equal(2, task.attempt)
+}
+
+func (s *taskProcessorSuite) TestShouldMoveTaskToDLQ_StandbyProcessor() {
+	s.mockShard.GetConfig().TaskDLQEnabled = dynamicconfig.GetBoolPropertyFn(true)
+	s.mockShard.GetConfig().TaskDLQMaxRetryCount = dynamicconfig.GetIntPropertyFn(3)
+	s.taskProcessor.enableDLQ = false
+
+	taskInfo := newTaskInfo(s.mockProcessor, nil, s.logger)
+	taskInfo.attempt = 3
+	s.False(s.taskProcessor.shouldMoveTaskToDLQ(taskInfo, errors.New("random error")))
+}
@@ -72,6 +72,8 @@ type (
 		sync.Mutex
 		// outstanding timer task -> finished (true)
 		outstandingTasks map[timerKey]bool
+		// outstanding timer tasks fired from memory, which are not read from persistence yet
+		inMemoryTasks map[timerKey]struct{}
 		// timer task ack level
 		ackLevel timerKey
 		// timer task read level, used by failover
@@ -80,6 +82,9 @@ type (
 		minQueryLevel time.Time
 		maxQueryLevel time.Time
 		pageToken     []byte
+		// finishLevel is used by split queues, once set the ack manager
+		// reads up to the finish level and then shuts down
+		finishLevel time.Time
 
 		clusterName string
 	}
@@ -151,6 +156,7 @@ func newTimerQueueAckMgr(
 		updateTimerAckLevel: updateTimerAckLevel,
 		timerQueueShutdown:  func() error { return nil },
 		outstandingTasks:    make(map[timerKey]bool),
+		inMemoryTasks:       make(map[timerKey]struct{}),
 		ackLevel:            ackLevel,
 		readLevel:           ackLevel,
 		minQueryLevel:       ackLevel.VisibilityTimestamp,
@@ -189,6 +195,7 @@ func newTimerQueueFailoverAckMgr(
 		updateTimerAckLevel: updateTimerAckLevel,
 		timerQueueShutdown:  timerQueueShutdown,
 		outstandingTasks:    make(map[timerKey]bool),
+		inMemoryTasks:       make(map[timerKey]struct{}),
 		ackLevel:            ackLevel,
 		readLevel:           ackLevel,
 		minQueryLevel:       ackLevel.VisibilityTimestamp,
@@ -201,6 +208,27 @@ func newTimerQueueFailoverAckMgr(
 	return timerQueueAckMgrImpl
 }
 
+func newTimerQueueSplitAckMgr(
+	scope int,
+	shard ShardContext,
+	metricsClient metrics.Client,
+	minLevel time.Time,
+	timeNow timeNow,
+	timerQueueShutdown timerQueueShutdown,
+	logger log.Logger,
+	clusterName string,
+) *timerQueueAckMgrImpl {
+	// ack level of the split queue is persisted by the main processor
+	updateTimerAckLevel := func(ackLevel timerKey) error {
+		return nil
+	}
+
+	timerQueueAckMgrImpl := newTimerQueueAckMgr(scope, shard, metricsClient, minLevel, timeNow, updateTimerAckLevel, logger, clusterName)
+	timerQueueAckMgrImpl.timerQueueShutdown = timerQueueShutdown
+	timerQueueAckMgrImpl.finishedChan = make(chan struct{}, 1)
+	return timerQueueAckMgrImpl
+}
+
 func (t *timerQueueAckMgrImpl) getFinishedChan() <-chan struct{} {
 	return t.finishedChan
 }
@@ -209,6 +237,7 @@ func (t *timerQueueAckMgrImpl) readTimerTasks() ([]*persistenceblobs.TimerTaskIn
 	if t.maxQueryLevel == t.minQueryLevel {
 		t.maxQueryLevel = t.shard.UpdateTimerMaxReadLevel(t.clusterName)
 	}
+	t.applyFinishLevel()
 	minQueryLevel := t.minQueryLevel
 	maxQueryLevel := t.maxQueryLevel
 	pageToken := t.pageToken
@@ -242,6 +271,15 @@ func (t *timerQueueAckMgrImpl) readTimerTasks() ([]*persistenceblobs.TimerTaskIn
 TaskFilterLoop:
 	for _, task := range tasks {
 		timerKey := timerKeyFromGogoTime(task.GetVisibilityTimestamp(), task.TaskID)
+		if _, isInMemory := t.inMemoryTasks[*timerKey]; isInMemory {
+			// timer already fired from memory, reading it from persistence
+			// moves the read level so that the ack level can pass it
+			delete(t.inMemoryTasks, *timerKey)
+			if compareTimerIDLess(&t.readLevel, timerKey) {
+				t.readLevel = *timerKey
+			}
+			continue TaskFilterLoop
+		}
 		_, isLoaded := t.outstandingTasks[*timerKey]
 		if isLoaded {
 			// timer already loaded
@@ -293,10 +331,28 @@ TaskFilterLoop:
 }
 
 // read lookAheadTask from s.GetTimerMaxReadLevel to poll interval from there.
+// The timers fired from memory are not read ahead, the read stops at the first of them, and if no timer
+// comes before it, the processor wakes up at it to read the timers fired from memory from persistence.
 func (t *timerQueueAckMgrImpl) readLookAheadTask() (*persistenceblobs.TimerTaskInfo, error) {
 	minQueryLevel := t.maxQueryLevel
 	maxQueryLevel := maximumTime
 
+	t.Lock()
+	var firstInMemoryTask *timerKey
+	for timerKey := range t.inMemoryTasks {
+		if timerKey.VisibilityTimestamp.Before(minQueryLevel) {
+			continue
+		}
+		if firstInMemoryTask == nil || compareTimerIDLess(&timerKey, firstInMemoryTask) {
+			key := timerKey
+			firstInMemoryTask = &key
+		}
+	}
+	t.Unlock()
+	if firstInMemoryTask != nil {
+		maxQueryLevel = firstInMemoryTask.VisibilityTimestamp
+	}
+
 	var tasks []*persistenceblobs.TimerTaskInfo
 	var err error
 	tasks, _, err = t.getTimerTasks(minQueryLevel, maxQueryLevel, 1, nil)
@@ -306,9 +362,64 @@ func (t *timerQueueAckMgrImpl) readLookAheadTask() (*persistenceblobs.TimerTaskI
 	if len(tasks) == 1 {
 		return tasks[0], nil
 	}
+	if firstInMemoryTask != nil {
+		visibilityTimestamp, err := types.TimestampProto(firstInMemoryTask.VisibilityTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		// only the visibility timestamp of the look ahead task is used
+		return &persistenceblobs.TimerTaskInfo{
+			VisibilityTimestamp: visibilityTimestamp,
+			TaskID:              firstInMemoryTask.TaskID,
+		}, nil
+	}
 	return nil, nil
 }
 
+// loadInMemoryTask adds a new timer task which is fired from memory to the outstanding tasks,
+// returns false if the task is already read from persistence, or the ack manager is not
+// reading new tasks, the task is then left to be read from persistence
+func (t *timerQueueAckMgrImpl) loadInMemoryTask(timerTask *persistenceblobs.TimerTaskInfo) bool {
+	timerKey := timerKeyFromGogoTime(timerTask.GetVisibilityTimestamp(), timerTask.TaskID)
+	t.Lock()
+	defer t.Unlock()
+
+	if t.isFailover || !compareTimerIDLess(&t.readLevel, timerKey) {
+		return false
+	}
+	if _, isLoaded := t.outstandingTasks[*timerKey]; isLoaded {
+		return false
+	}
+	t.outstandingTasks[*timerKey] = false
+	t.inMemoryTasks[*timerKey] = struct{}{}
+	return true
+}
+
+// finishReadAt makes the ack manager read up to the given level (exclusive), and then shut down
+func (t *timerQueueAckMgrImpl) finishReadAt(level time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.finishLevel = level
+}
+
+func (t *timerQueueAckMgrImpl) applyFinishLevel() {
+	t.Lock()
+	defer t.Unlock()
+
+	// wait for the current page to be read, before switching to failover mode
+	if t.isFailover || t.finishLevel.IsZero() || len(t.pageToken) != 0 {
+		return
+	}
+
+	t.isFailover = true
+	if t.minQueryLevel.Before(t.finishLevel) {
+		t.maxQueryLevel = t.finishLevel
+	} else {
+		t.maxQueryLevel = t.minQueryLevel
+	}
+}
+
 func (t *timerQueueAckMgrImpl) completeTimerTask(timerTask *persistenceblobs.TimerTaskInfo) {
 	timerKey := timerKeyFromGogoTime(timerTask.GetVisibilityTimestamp(), timerTask.TaskID)
 	t.Lock()
@@ -359,6 +470,11 @@ func (t *timerQueueAckMgrImpl) updateAckLevel() {
 
 MoveAckLevelLoop:
 	for _, current := range sequenceIDs {
+		if compareTimerIDLess(&t.readLevel, &current) {
+			// the tasks fired from memory after the read level can be completed before the tasks prior to them
+			// are read from persistence, the ack level can only pass them once they are read from persistence
+			break MoveAckLevelLoop
+		}
 		acked := outstandingTasks[current]
 		if acked {
 			ackLevel = current
@@ -494,6 +494,63 @@ func (s *timerQueueAckMgrSuite) TestReadCompleteUpdateTimerTasks() {
 	s.Equal(protoToNanos(timer3.VisibilityTimestamp), s.mockShard.GetTimerClusterAckLevel(s.clusterName).UnixNano())
 }
 
+func (s *timerQueueAckMgrSuite) TestReadCompleteUpdateTimerTasks_InMemory() {
+	// create 2 timers, timer1 < timer2 < now, timer2 is fired from memory
+	timer1 := &persistenceblobs.TimerTaskInfo{
+		DomainID:            TestDomainId,
+		WorkflowID:          "some random workflow ID",
+		RunID:               uuid.NewRandom(),
+		VisibilityTimestamp: gogoProtoTimestampNowAddDuration(-5),
+		TaskID:              int64(59),
+		TaskType:            1,
+		TimeoutType:         2,
+		EventID:             int64(28),
+		ScheduleAttempt:     0,
+	}
+	timer2 := &persistenceblobs.TimerTaskInfo{
+		DomainID:            TestDomainId,
+		WorkflowID:          "some random workflow ID",
+		RunID:               uuid.NewRandom(),
+		VisibilityTimestamp: timer1.VisibilityTimestamp,
+		TaskID:              timer1.TaskID + 1,
+		TaskType:            1,
+		TimeoutType:         2,
+		EventID:             int64(29),
+		ScheduleAttempt:     0,
+	}
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	ackLevel := s.mockShard.GetTimerClusterAckLevel(s.clusterName)
+
+	s.True(s.timerQueueAckMgr.loadInMemoryTask(timer2))
+	s.False(s.timerQueueAckMgr.loadInMemoryTask(timer2))
+
+	// ack level does not pass the timer fired from memory, until it is read from persistence
+	s.mockShardMgr.On("UpdateShard", mock.Anything).Return(nil).Once()
+	s.timerQueueAckMgr.completeTimerTask(timer2)
+	s.timerQueueAckMgr.updateAckLevel()
+	s.Equal(ackLevel.UnixNano(), s.mockShard.GetTimerClusterAckLevel(s.clusterName).UnixNano())
+
+	response := &persistence.GetTimerIndexTasksResponse{
+		Timers:        []*persistenceblobs.TimerTaskInfo{timer1, timer2},
+		NextPageToken: nil,
+	}
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(response, nil).Once()
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.Anything).Return(&persistence.GetTimerIndexTasksResponse{}, nil).Once()
+	filteredTasks, lookAheadTask, moreTasks, err := s.timerQueueAckMgr.readTimerTasks()
+	s.Nil(err)
+	s.Equal([]*persistenceblobs.TimerTaskInfo{timer1}, filteredTasks)
+	s.Nil(lookAheadTask)
+	s.False(moreTasks)
+	s.Empty(s.timerQueueAckMgr.inMemoryTasks)
+	s.False(s.timerQueueAckMgr.loadInMemoryTask(timer1))
+
+	s.mockShardMgr.On("UpdateShard", mock.Anything).Return(nil).Once()
+	s.timerQueueAckMgr.completeTimerTask(timer1)
+	s.timerQueueAckMgr.updateAckLevel()
+	s.Equal(protoToNanos(timer2.VisibilityTimestamp), s.mockShard.GetTimerClusterAckLevel(s.clusterName).UnixNano())
+	s.Empty(s.timerQueueAckMgr.outstandingTasks)
+}
+
 func (s *timerQueueAckMgrSuite) TestReadLookAheadTask() {
 	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(s.clusterName).AnyTimes()
 	level := s.mockShard.UpdateTimerMaxReadLevel(s.clusterName)
@@ -525,6 +582,40 @@ func (s *timerQueueAckMgrSuite) TestReadLookAheadTask() {
 	s.Equal(timer, lookAheadTask)
 }
 
+func (s *timerQueueAckMgrSuite) TestReadLookAheadTask_InMemoryTasks() {
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(s.clusterName).AnyTimes()
+	level := s.mockShard.UpdateTimerMaxReadLevel(s.clusterName)
+	s.timerQueueAckMgr.minQueryLevel = level
+	s.timerQueueAckMgr.maxQueryLevel = s.timerQueueAckMgr.minQueryLevel
+
+	protoFireTime, err := types.TimestampProto(level.Add(time.Second))
+	s.NoError(err)
+	timer := &persistenceblobs.TimerTaskInfo{
+		DomainID:            TestDomainId,
+		WorkflowID:          "some random workflow ID",
+		RunID:               uuid.NewRandom(),
+		VisibilityTimestamp: protoFireTime,
+		TaskID:              int64(59),
+		TaskType:            1,
+		TimeoutType:         2,
+		EventID:             int64(28),
+		ScheduleAttempt:     0,
+		Version:             int64(79),
+	}
+	s.True(s.timerQueueAckMgr.loadInMemoryTask(timer))
+
+	// the timer fired from memory is not read ahead, the processor wakes up at it instead
+	s.mockExecutionMgr.On("GetTimerIndexTasks", mock.MatchedBy(func(request *persistence.GetTimerIndexTasksRequest) bool {
+		return request.BatchSize == 1 &&
+			request.MinTimestamp.Equal(level) &&
+			request.MaxTimestamp.Equal(level.Add(time.Second))
+	})).Return(&persistence.GetTimerIndexTasksResponse{}, nil).Once()
+	lookAheadTask, err := s.timerQueueAckMgr.readLookAheadTask()
+	s.Nil(err)
+	s.NotNil(lookAheadTask)
+	s.Equal(protoToNanos(timer.VisibilityTimestamp), protoToNanos(lookAheadTask.VisibilityTimestamp))
+}
+
 // Tests for failover ack manager
 func (s *timerQueueFailoverAckMgrSuite) SetupSuite() {
 
@@ -29,6 +29,7 @@ import (
 	"github.com/pborman/uuid"
 
 	"github.com/temporalio/temporal/client/matching"
+	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
@@ -45,6 +46,9 @@ type (
 		currentClusterName      string
 		taskExecutor            queueTaskExecutor
 		timerQueueProcessorBase *timerQueueProcessorBase
+		// splitter is only set for the main processor of the shard
+		splitter      *queueSplitter
+		onTaskFailure func(taskInfo *taskInfo, err error)
 	}
 )
 
@@ -60,10 +64,27 @@ func newTimerQueueActiveProcessor(
 	timeNow := func() time.Time {
 		return shard.GetCurrentTime(currentClusterName)
 	}
+	logger = logger.WithTags(tag.ClusterName(currentClusterName))
+
+	var processor *timerQueueActiveProcessorImpl
+	splitter := newQueueSplitter(
+		splitQueueTypeTimer,
+		metrics.TimerActiveQueueProcessorScope,
+		shard,
+		func(domainID string, ackLevel int64) splitQueue {
+			return newTimerQueueSplitProcessor(shard, historyService, processor, domainID, time.Unix(0, ackLevel).UTC(), taskAllocator, logger)
+		},
+		logger,
+	)
 	updateShardAckLevel := func(ackLevel timerKey) error {
-		return shard.UpdateTimerClusterAckLevel(currentClusterName, ackLevel.VisibilityTimestamp)
+		splitter.mergeHealthySplitQueues()
+		minAckLevel := splitter.getMinAckLevel(ackLevel.VisibilityTimestamp.UnixNano())
+		splitQueueAckLevels := make(map[string]time.Time)
+		for domainID, splitQueueAckLevel := range splitter.getAckLevels() {
+			splitQueueAckLevels[domainID] = time.Unix(0, splitQueueAckLevel).UTC()
+		}
+		return shard.UpdateTimerSplitQueueAckLevels(currentClusterName, time.Unix(0, minAckLevel).UTC(), splitQueueAckLevels)
 	}
-	logger = logger.WithTags(tag.ClusterName(currentClusterName))
 	timerTaskFilter := func(taskInfo queueTaskInfo) (bool, error) {
 		timer, ok := taskInfo.(*persistenceblobs.TimerTaskInfo)
 		if !ok {
@@ -84,7 +105,7 @@ func newTimerQueueActiveProcessor(
 	)
 
 	timerGate := NewLocalTimerGate(shard.GetTimeSource())
-	processor := &timerQueueActiveProcessorImpl{
+	processor = &timerQueueActiveProcessorImpl{
 		shard:              shard,
 		timerTaskFilter:    timerTaskFilter,
 		now:                timeNow,
@@ -100,6 +121,11 @@ func newTimerQueueActiveProcessor(
 			shard.GetConfig().TimerProcessorMaxPollRPS,
 			logger,
 		),
+		splitter: splitter,
+	}
+	processor.timerQueueProcessorBase.enableInMemoryTimers()
+	processor.onTaskFailure = func(taskInfo *taskInfo, err error) {
+		splitter.reportFailure(taskInfo.task, taskInfo.attempt+1, err, processor.getAckLevel().VisibilityTimestamp.UnixNano())
 	}
 	processor.timerQueueProcessorBase.timerProcessor = processor
 	processor.taskExecutor = newTimerQueueActiveTaskExecutor(
@@ -205,10 +231,20 @@ func newTimerQueueFailoverProcessor(
 
 func (t *timerQueueActiveProcessorImpl) Start() {
 	t.timerQueueProcessorBase.Start()
+	if t.splitter != nil {
+		splitQueueAckLevels := make(map[string]int64)
+		for domainID, splitQueueAckLevel := range t.shard.GetTimerSplitQueueAckLevels() {
+			splitQueueAckLevels[domainID] = splitQueueAckLevel.UnixNano()
+		}
+		t.splitter.restore(splitQueueAckLevels)
+	}
 }
 
 func (t *timerQueueActiveProcessorImpl) Stop() {
 	t.timerQueueProcessorBase.Stop()
+	if t.splitter != nil {
+		t.splitter.stop()
+	}
 }
 
 func (t *timerQueueActiveProcessorImpl) getTaskFilter() taskFilter {
@@ -229,6 +265,21 @@ func (t *timerQueueActiveProcessorImpl) notifyNewTimers(
 	timerTasks []persistence.Task,
 ) {
 	t.timerQueueProcessorBase.notifyNewTimers(timerTasks)
+	if t.splitter != nil {
+		t.splitter.notifyNewTasks(timerTasks)
+	}
+}
+
+// notifyNewWorkflowTimers notifies the processor about the new active timers of a workflow, the timers
+// due within the in-memory timer horizon are fired from memory, the others are read from persistence
+func (t *timerQueueActiveProcessorImpl) notifyNewWorkflowTimers(
+	execution definition.WorkflowIdentifier,
+	timerTasks []persistence.Task,
+) {
+	t.timerQueueProcessorBase.notifyNewTimers(t.timerQueueProcessorBase.scheduleNewTimers(execution, timerTasks))
+	if t.splitter != nil {
+		t.splitter.notifyNewTasks(timerTasks)
+	}
 }
 
 func (t *timerQueueActiveProcessorImpl) complete(
@@ -246,5 +297,14 @@ func (t *timerQueueActiveProcessorImpl) process(
 ) (int, error) {
 	// TODO: task metricScope should be determined when creating taskInfo
 	metricScope := t.timerQueueProcessorBase.getTimerTaskMetricScope(int(taskInfo.task.GetTaskType()), true)
-	return metricScope, t.taskExecutor.execute(taskInfo.task, taskInfo.shouldProcessTask)
+	if t.splitter != nil && t.splitter.isOwnedBySplitQueue(taskInfo.task) {
+		// the task is processed by the split queue of its domain
+		return metricScope, nil
+	}
+
+	err := t.taskExecutor.execute(taskInfo.task, taskInfo.shouldProcessTask)
+	if err != nil && t.onTaskFailure != nil {
+		t.onTaskFailure(taskInfo, err)
+	}
+	return metricScope, err
 }
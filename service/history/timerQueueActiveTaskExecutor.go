@@ -177,6 +177,7 @@ func (t *timerQueueActiveTaskExecutor) executeActivityTimeoutTask(
 	referenceTime := t.shard.GetTimeSource().Now()
 	updateMutableState := false
 	scheduleDecision := false
+	var releaseRequests []*matchingservice.ReleaseActivityTaskSlotRequest
 
 	// need to clear activity heartbeat timer task mask for new activity timer task creation
 	// NOTE: LastHeartbeatTimeoutVisibility is for deduping heartbeat timer creation as it's possible
@@ -233,6 +234,13 @@ Loop:
 			metrics.TimerActiveTaskActivityTimeoutScope,
 			timerSequenceID.timerType,
 		)
+		releaseRequest, err := newReleaseActivityTaskSlotRequest(t.shard, mutableState, activityInfo)
+		if err != nil {
+			return err
+		}
+		if releaseRequest != nil {
+			releaseRequests = append(releaseRequests, releaseRequest)
+		}
 		if _, err := mutableState.AddActivityTaskTimedOutEvent(
 			activityInfo.ScheduleID,
 			activityInfo.StartedID,
@@ -248,7 +256,11 @@ Loop:
 	if !updateMutableState {
 		return nil
 	}
-	return t.updateWorkflowExecution(weContext, mutableState, scheduleDecision)
+	if err := t.updateWorkflowExecution(weContext, mutableState, scheduleDecision); err != nil {
+		return err
+	}
+	t.historyService.releaseActivityTaskSlots(releaseRequests)
+	return nil
 }
 
 func (t *timerQueueActiveTaskExecutor) executeDecisionTimeoutTask(
@@ -400,6 +412,12 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		return err
 	}
 
+	scheduledEvent, err := mutableState.GetActivityScheduledEvent(scheduledID)
+	if err != nil {
+		return err
+	}
+	activityType := scheduledEvent.GetActivityTaskScheduledEventAttributes().GetActivityType().GetName()
+
 	domainID := primitives.UUIDString(task.DomainID)
 	targetDomainID := domainID
 	if activityInfo.DomainID != "" {
@@ -409,10 +427,6 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		//  previously, DomainID in activity info is not used, so need to get
 		//  schedule event from DB checking whether activity to be scheduled
 		//  belongs to this domain
-		scheduledEvent, err := mutableState.GetActivityScheduledEvent(scheduledID)
-		if err != nil {
-			return err
-		}
 		if scheduledEvent.GetActivityTaskScheduledEventAttributes().GetDomain() != "" {
 			domainEntry, err := t.shard.GetDomainCache().GetDomain(scheduledEvent.GetActivityTaskScheduledEventAttributes().GetDomain())
 			if err != nil {
@@ -439,6 +453,7 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		TaskList:                      taskList,
 		ScheduleId:                    scheduledID,
 		ScheduleToStartTimeoutSeconds: scheduleToStartTimeout,
+		ActivityType:                  activityType,
 	})
 
 	return retError
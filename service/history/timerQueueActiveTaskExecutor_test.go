@@ -104,6 +104,7 @@ func (s *timerQueueActiveTaskExecutorSuite) SetupTest() {
 	s.mockTxProcessor.EXPECT().NotifyNewTask(gomock.Any(), gomock.Any()).AnyTimes()
 	s.mockReplicationProcessor.EXPECT().notifyNewTask().AnyTimes()
 	s.mockTimerProcessor.EXPECT().NotifyNewTimers(gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockTimerProcessor.EXPECT().NotifyNewWorkflowTimers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 	config := NewDynamicConfigForTest()
 	s.mockShard = newTestShardContext(
@@ -1108,6 +1109,7 @@ func (s *timerQueueActiveTaskExecutorSuite) TestActivityRetryTimer_Fire() {
 			},
 			ScheduleId:                    activityInfo.ScheduleID,
 			ScheduleToStartTimeoutSeconds: activityInfo.ScheduleToStartTimeout,
+			ActivityType:                  activityType,
 		},
 	).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
 
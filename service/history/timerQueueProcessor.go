@@ -29,9 +29,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/client/matching"
 	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
@@ -48,8 +50,11 @@ type (
 		common.Daemon
 		FailoverDomain(domainIDs map[string]struct{})
 		NotifyNewTimers(clusterName string, timerTask []persistence.Task)
+		NotifyNewWorkflowTimers(clusterName string, execution definition.WorkflowIdentifier, timerTasks []persistence.Task)
 		LockTaskProcessing()
 		UnlockTaskProcessing()
+		DescribeSplitQueues() []*adminservice.SplitQueueInfo
+		ExecuteDLQTask(task queueTaskInfo) error
 	}
 
 	timeNow                 func() time.Time
@@ -188,6 +193,21 @@ func (t *timerQueueProcessorImpl) NotifyNewTimers(
 	standbyTimerProcessor.retryTasks()
 }
 
+// NotifyNewWorkflowTimers - Notify the processor about the new timers of a workflow, the new active timers
+// due soon are fired from memory, while all timers are persisted and can be read from persistence.
+func (t *timerQueueProcessorImpl) NotifyNewWorkflowTimers(
+	clusterName string,
+	execution definition.WorkflowIdentifier,
+	timerTasks []persistence.Task,
+) {
+
+	if clusterName == t.currentClusterName {
+		t.activeTimerProcessor.notifyNewWorkflowTimers(execution, timerTasks)
+		return
+	}
+	t.NotifyNewTimers(clusterName, timerTasks)
+}
+
 func (t *timerQueueProcessorImpl) FailoverDomain(
 	domainIDs map[string]struct{},
 ) {
@@ -245,6 +265,20 @@ func (t *timerQueueProcessorImpl) UnlockTaskProcessing() {
 	t.taskAllocator.unlock()
 }
 
+// DescribeSplitQueues returns the domains split out of the active timer queue
+func (t *timerQueueProcessorImpl) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	return t.activeTimerProcessor.splitter.describe()
+}
+
+// ExecuteDLQTask executes a timer task of the DLQ of the shard with the active task executor
+func (t *timerQueueProcessorImpl) ExecuteDLQTask(task queueTaskInfo) error {
+	shouldProcessTask, err := t.activeTimerProcessor.timerTaskFilter(task)
+	if err != nil {
+		return err
+	}
+	return t.activeTimerProcessor.taskExecutor.execute(task, shouldProcessTask)
+}
+
 func (t *timerQueueProcessorImpl) completeTimersLoop() {
 	timer := time.NewTimer(t.config.TimerProcessorCompleteTimerInterval())
 	defer timer.Stop()
@@ -274,6 +308,10 @@ func (t *timerQueueProcessorImpl) completeTimersLoop() {
 func (t *timerQueueProcessorImpl) completeTimers() error {
 	lowerAckLevel := t.ackLevel
 	upperAckLevel := t.activeTimerProcessor.getAckLevel()
+	ackLevelNanos := upperAckLevel.VisibilityTimestamp.UnixNano()
+	if splitAckLevel := t.activeTimerProcessor.splitter.getMinAckLevel(ackLevelNanos); splitAckLevel < ackLevelNanos {
+		upperAckLevel = timerKey{VisibilityTimestamp: time.Unix(0, splitAckLevel).UTC()}
+	}
 
 	if t.isGlobalDomainEnabled {
 		for _, standbyTimerProcessor := range t.standbyTimerProcessors {
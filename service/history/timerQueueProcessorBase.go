@@ -22,21 +22,25 @@ package history
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/types"
+	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
 	"github.com/temporalio/temporal/common/quotas"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
@@ -46,6 +50,9 @@ var (
 
 	loadDomainEntryForTimerTaskRetryDelay = 100 * time.Millisecond
 	loadTimerTaskThrottleRetryDelay       = 5 * time.Second
+
+	timerWheelTick   = 10 * time.Millisecond
+	timerWheelLevels = 2
 )
 
 type (
@@ -75,6 +82,12 @@ type (
 		newTimerCh  chan struct{}
 		newTimeLock sync.Mutex
 		newTime     time.Time
+
+		// in-memory timers, only used by the main active processor,
+		// the timer wheel gate is updated while holding the timer wheel lock
+		timerWheelLock sync.Mutex
+		timerWheel     *timerWheel
+		timerWheelGate TimerGate
 	}
 )
 
@@ -92,6 +105,7 @@ func newTimerQueueProcessorBase(
 	options := taskProcessorOptions{
 		workerCount: shard.GetConfig().TimerTaskWorkerCount(),
 		queueSize:   shard.GetConfig().TimerTaskWorkerCount() * shard.GetConfig().TimerTaskBatchSize(),
+		enableDLQ:   scope == metrics.TimerActiveQueueProcessorScope,
 	}
 	taskProcessor := newTaskProcessor(options, shard, historyService.historyCache, log)
 	base := &timerQueueProcessorBase{
@@ -142,6 +156,9 @@ func (t *timerQueueProcessorBase) Stop() {
 	}
 
 	t.timerGate.Close()
+	if t.timerWheelGate != nil {
+		t.timerWheelGate.Close()
+	}
 	close(t.shutdownCh)
 	t.retryTasks()
 
@@ -173,6 +190,87 @@ RetryProcessor:
 	t.logger.Info("Timer processor exiting.")
 }
 
+// enableInMemoryTimers makes the processor fire new timers due within the in-memory timer horizon from memory
+func (t *timerQueueProcessorBase) enableInMemoryTimers() {
+	t.timerWheel = newTimerWheel(timerWheelTick, timerWheelLevels, t.timeSource.Now())
+	t.timerWheelGate = NewLocalTimerGate(t.timeSource)
+}
+
+// scheduleNewTimers schedules the new timers due within the in-memory timer horizon in the timer wheel, the
+// timers are persisted as well, but are only read from persistence to move the ack level past them, or to
+// recover them after the shard is reloaded. It returns the timers which are left to be read from persistence.
+func (t *timerQueueProcessorBase) scheduleNewTimers(
+	execution definition.WorkflowIdentifier,
+	timerTasks []persistence.Task,
+) []persistence.Task {
+
+	if t.timerWheel == nil || !t.config.TimerProcessorEnableInMemoryTimers() {
+		return timerTasks
+	}
+
+	horizon := t.config.TimerProcessorInMemoryTimerHorizon()
+	if horizon > t.timerWheel.horizon()-timerWheelLevels*timerWheelTick {
+		horizon = t.timerWheel.horizon() - timerWheelLevels*timerWheelTick
+	}
+	maxFireTime := t.timeSource.Now().Add(horizon)
+
+	var persistedTimerTasks []persistence.Task
+	scheduled := 0
+	t.timerWheelLock.Lock()
+	for _, task := range timerTasks {
+		fireTime := task.GetVisibilityTimestamp()
+		if fireTime.After(maxFireTime) || !t.timerWheel.fits(fireTime) {
+			persistedTimerTasks = append(persistedTimerTasks, task)
+			continue
+		}
+		timer, err := newTimerTaskInfo(execution, task)
+		if err != nil || !t.timerQueueAckMgr.loadInMemoryTask(timer) {
+			persistedTimerTasks = append(persistedTimerTasks, task)
+			continue
+		}
+		t.timerWheel.add(timer, fireTime)
+		scheduled++
+		t.metricsClient.IncCounter(t.getTimerTaskMetricScope(task.GetType(), true), metrics.NewTimerCounter)
+	}
+	if scheduled > 0 {
+		t.timerWheelGate.Update(t.timerWheel.nextFireTime())
+	}
+	t.timerWheelLock.Unlock()
+
+	t.metricsClient.AddCounter(t.scope, metrics.InMemoryTimerScheduledCounter, int64(scheduled))
+	return persistedTimerTasks
+}
+
+func (t *timerQueueProcessorBase) fireInMemoryTimers() {
+	t.timerWheelLock.Lock()
+	timers := t.timerWheel.advance(t.timeSource.Now())
+	if nextFireTime := t.timerWheel.nextFireTime(); !nextFireTime.IsZero() {
+		t.timerWheelGate.Update(nextFireTime)
+	}
+	t.timerWheelLock.Unlock()
+
+	t.metricsClient.AddCounter(t.scope, metrics.InMemoryTimerFiredCounter, int64(len(timers)))
+	for _, timer := range timers {
+		if shutdown := t.taskProcessor.addTask(
+			newTaskInfo(
+				t.timerProcessor,
+				timer,
+				initializeLoggerForTask(t.shard.GetShardID(), timer, t.logger),
+			),
+		); shutdown {
+			return
+		}
+	}
+}
+
+func (t *timerQueueProcessorBase) timerWheelFireChan() <-chan struct{} {
+	if t.timerWheelGate == nil {
+		// receiving from a nil channel blocks forever
+		return nil
+	}
+	return t.timerWheelGate.FireChan()
+}
+
 // NotifyNewTimers - Notify the processor about the new timer events arrival.
 // This should be called each time new timer events arrives, otherwise timers maybe fired unexpected.
 func (t *timerQueueProcessorBase) notifyNewTimers(
@@ -246,6 +344,8 @@ func (t *timerQueueProcessorBase) internalProcessor() error {
 			// use a separate goroutine since the caller hold the shutdownWG
 			go t.Stop()
 			return nil
+		case <-t.timerWheelFireChan():
+			t.fireInMemoryTimers()
 		case <-t.timerGate.FireChan():
 			lookAheadTimer, err := t.readAndFanoutTimerTasks()
 			if err != nil {
@@ -375,6 +475,59 @@ func (t *timerQueueProcessorBase) getTimerTaskType(
 	return "UnKnown"
 }
 
+// newTimerTaskInfo creates the timer task info of a new timer, as it is read from persistence
+func newTimerTaskInfo(
+	execution definition.WorkflowIdentifier,
+	task persistence.Task,
+) (*persistenceblobs.TimerTaskInfo, error) {
+
+	var eventID int64
+	var attempt int64
+	timeoutType := 0
+
+	switch t := task.(type) {
+	case *persistence.DecisionTimeoutTask:
+		eventID = t.EventID
+		timeoutType = t.TimeoutType
+		attempt = t.ScheduleAttempt
+	case *persistence.ActivityTimeoutTask:
+		eventID = t.EventID
+		timeoutType = t.TimeoutType
+		attempt = t.Attempt
+	case *persistence.UserTimerTask:
+		eventID = t.EventID
+	case *persistence.ActivityRetryTimerTask:
+		eventID = t.EventID
+		attempt = int64(t.Attempt)
+	case *persistence.WorkflowBackoffTimerTask:
+		eventID = t.EventID
+		timeoutType = t.TimeoutType
+	case *persistence.WorkflowTimeoutTask:
+		// noop
+	case *persistence.DeleteHistoryEventTask:
+		// noop
+	default:
+		return nil, serviceerror.NewInternal(fmt.Sprintf("Unknow timer type: %v", task.GetType()))
+	}
+
+	visibilityTimestamp, err := types.TimestampProto(task.GetVisibilityTimestamp())
+	if err != nil {
+		return nil, err
+	}
+	return &persistenceblobs.TimerTaskInfo{
+		DomainID:            primitives.MustParseUUID(execution.DomainID),
+		WorkflowID:          execution.WorkflowID,
+		RunID:               primitives.MustParseUUID(execution.RunID),
+		TaskType:            int32(task.GetType()),
+		TimeoutType:         int32(timeoutType),
+		Version:             task.GetVersion(),
+		ScheduleAttempt:     attempt,
+		EventID:             eventID,
+		TaskID:              task.GetTaskID(),
+		VisibilityTimestamp: visibilityTimestamp,
+	}, nil
+}
+
 func (t *timerQueueProcessorBase) getTimerTaskMetricScope(
 	taskType int,
 	isActive bool,
@@ -32,6 +32,8 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 
+	adminservice "github.com/temporalio/temporal/.gen/proto/adminservice"
+	definition "github.com/temporalio/temporal/common/definition"
 	persistence "github.com/temporalio/temporal/common/persistence"
 )
 
@@ -106,6 +108,18 @@ func (mr *MocktimerQueueProcessorMockRecorder) NotifyNewTimers(clusterName, time
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyNewTimers", reflect.TypeOf((*MocktimerQueueProcessor)(nil).NotifyNewTimers), clusterName, timerTask)
 }
 
+// NotifyNewWorkflowTimers mocks base method
+func (m *MocktimerQueueProcessor) NotifyNewWorkflowTimers(clusterName string, execution definition.WorkflowIdentifier, timerTasks []persistence.Task) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyNewWorkflowTimers", clusterName, execution, timerTasks)
+}
+
+// NotifyNewWorkflowTimers indicates an expected call of NotifyNewWorkflowTimers
+func (mr *MocktimerQueueProcessorMockRecorder) NotifyNewWorkflowTimers(clusterName, execution, timerTasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyNewWorkflowTimers", reflect.TypeOf((*MocktimerQueueProcessor)(nil).NotifyNewWorkflowTimers), clusterName, execution, timerTasks)
+}
+
 // LockTaskProcessing mocks base method
 func (m *MocktimerQueueProcessor) LockTaskProcessing() {
 	m.ctrl.T.Helper()
@@ -129,3 +143,31 @@ func (mr *MocktimerQueueProcessorMockRecorder) UnlockTaskProcessing() *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockTaskProcessing", reflect.TypeOf((*MocktimerQueueProcessor)(nil).UnlockTaskProcessing))
 }
+
+// DescribeSplitQueues mocks base method
+func (m *MocktimerQueueProcessor) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeSplitQueues")
+	ret0, _ := ret[0].([]*adminservice.SplitQueueInfo)
+	return ret0
+}
+
+// DescribeSplitQueues indicates an expected call of DescribeSplitQueues
+func (mr *MocktimerQueueProcessorMockRecorder) DescribeSplitQueues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSplitQueues", reflect.TypeOf((*MocktimerQueueProcessor)(nil).DescribeSplitQueues))
+}
+
+// ExecuteDLQTask mocks base method
+func (m *MocktimerQueueProcessor) ExecuteDLQTask(task queueTaskInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteDLQTask", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteDLQTask indicates an expected call of ExecuteDLQTask
+func (mr *MocktimerQueueProcessorMockRecorder) ExecuteDLQTask(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDLQTask", reflect.TypeOf((*MocktimerQueueProcessor)(nil).ExecuteDLQTask), task)
+}
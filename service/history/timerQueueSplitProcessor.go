@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+	"time"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	// timerQueueSplitProcessorImpl processes the timer tasks of a single domain split out of the main
+	// timer queue, the ack level is kept in memory and persisted by the main processor
+	timerQueueSplitProcessorImpl struct {
+		domainID      string
+		processor     *timerQueueActiveProcessorImpl
+		ackMgr        *timerQueueAckMgrImpl
+		mainProcessor *timerQueueActiveProcessorImpl
+
+		sync.Mutex
+		merging    bool
+		mergeLevel timerKey
+	}
+)
+
+var _ splitQueue = (*timerQueueSplitProcessorImpl)(nil)
+
+func newTimerQueueSplitProcessor(
+	shard ShardContext,
+	historyService *historyEngineImpl,
+	mainProcessor *timerQueueActiveProcessorImpl,
+	domainID string,
+	minLevel time.Time,
+	taskAllocator taskAllocator,
+	logger log.Logger,
+) *timerQueueSplitProcessorImpl {
+
+	config := shard.GetConfig()
+	currentClusterName := shard.GetService().GetClusterMetadata().GetCurrentClusterName()
+	timeNow := func() time.Time {
+		return shard.GetCurrentTime(currentClusterName)
+	}
+	timerAckMgrShutdown := func() error {
+		mainProcessor.splitter.removeSplitQueue(domainID)
+		return nil
+	}
+
+	logger = logger.WithTags(
+		tag.ClusterName(currentClusterName),
+		tag.WorkflowDomainID(domainID),
+	)
+
+	splitProcessor := &timerQueueSplitProcessorImpl{
+		domainID:      domainID,
+		mainProcessor: mainProcessor,
+	}
+	timerTaskFilter := func(taskInfo queueTaskInfo) (bool, error) {
+		timer, ok := taskInfo.(*persistenceblobs.TimerTaskInfo)
+		if !ok {
+			return false, errUnexpectedQueueTask
+		}
+		timerDomainID := primitives.UUID(timer.DomainID).String()
+		if timerDomainID != domainID || !splitProcessor.owns(timer) {
+			return false, nil
+		}
+		return taskAllocator.verifyActiveTask(timerDomainID, timer)
+	}
+
+	timerQueueAckMgr := newTimerQueueSplitAckMgr(
+		metrics.TimerSplitQueueProcessorScope,
+		shard,
+		historyService.metricsClient,
+		minLevel,
+		timeNow,
+		timerAckMgrShutdown,
+		logger,
+		currentClusterName,
+	)
+
+	timerGate := NewLocalTimerGate(shard.GetTimeSource())
+	processor := &timerQueueActiveProcessorImpl{
+		shard:              shard,
+		timerTaskFilter:    timerTaskFilter,
+		now:                timeNow,
+		logger:             logger,
+		metricsClient:      historyService.metricsClient,
+		currentClusterName: currentClusterName,
+		timerQueueProcessorBase: newTimerQueueProcessorBase(
+			metrics.TimerSplitQueueProcessorScope,
+			shard,
+			historyService,
+			timerQueueAckMgr,
+			timerGate,
+			config.QueueProcessorSplitQueueMaxPollRPS,
+			logger,
+		),
+		onTaskFailure: func(taskInfo *taskInfo, err error) {
+			mainProcessor.splitter.reportSplitQueueFailure(domainID, err)
+		},
+	}
+	// split queues run with fewer workers and back off longer on failing tasks
+	processor.timerQueueProcessorBase.taskProcessor = newTaskProcessor(
+		taskProcessorOptions{
+			workerCount: config.QueueProcessorSplitQueueWorkerCount(),
+			queueSize:   config.QueueProcessorSplitQueueWorkerCount() * config.TimerTaskBatchSize(),
+			retryPolicy: newSplitQueueTaskRetryPolicy(),
+			enableDLQ:   true,
+		},
+		shard,
+		historyService.historyCache,
+		processor.timerQueueProcessorBase.logger,
+	)
+	processor.timerQueueProcessorBase.timerProcessor = processor
+	processor.taskExecutor = newTimerQueueActiveTaskExecutor(
+		shard,
+		historyService,
+		processor,
+		logger,
+		historyService.metricsClient,
+		config,
+	)
+
+	splitProcessor.processor = processor
+	splitProcessor.ackMgr = timerQueueAckMgr
+	return splitProcessor
+}
+
+func (t *timerQueueSplitProcessorImpl) Start() {
+	t.processor.Start()
+}
+
+func (t *timerQueueSplitProcessorImpl) Stop() {
+	t.processor.Stop()
+}
+
+func (t *timerQueueSplitProcessorImpl) owns(
+	task queueTaskInfo,
+) bool {
+
+	t.Lock()
+	defer t.Unlock()
+	if !t.merging {
+		return true
+	}
+	return !compareTimerIDLess(&t.mergeLevel, timerKeyFromGogoTime(task.GetVisibilityTimestamp(), task.GetTaskID()))
+}
+
+func (t *timerQueueSplitProcessorImpl) merge() {
+	// the read level of the main queue is taken under the lock of owns, so that every task the main queue
+	// skipped before is below the merge level, and every task it processes from now on is above
+	t.Lock()
+	t.merging = true
+	t.mergeLevel = t.mainProcessor.getReadLevel()
+	mergeLevel := t.mergeLevel
+	t.Unlock()
+
+	// timer tasks are persisted with millisecond precision
+	t.ackMgr.finishReadAt(mergeLevel.VisibilityTimestamp.Add(time.Millisecond))
+	t.processor.timerQueueProcessorBase.notifyNewTimer(time.Time{})
+}
+
+func (t *timerQueueSplitProcessorImpl) isIdle() bool {
+	ackLevel := t.ackMgr.getAckLevel()
+	readLevel := t.ackMgr.getReadLevel()
+	return !compareTimerIDLess(&ackLevel, &readLevel)
+}
+
+func (t *timerQueueSplitProcessorImpl) notifyNewTasks(
+	tasks []persistence.Task,
+) {
+
+	if len(tasks) == 0 {
+		return
+	}
+
+	// new timer metrics are emitted by the main processor
+	newTime := tasks[0].GetVisibilityTimestamp()
+	for _, task := range tasks {
+		if ts := task.GetVisibilityTimestamp(); ts.Before(newTime) {
+			newTime = ts
+		}
+	}
+	t.processor.timerQueueProcessorBase.notifyNewTimer(newTime)
+}
+
+func (t *timerQueueSplitProcessorImpl) getAckLevel() int64 {
+	return t.ackMgr.getAckLevel().VisibilityTimestamp.UnixNano()
+}
+
+func (t *timerQueueSplitProcessorImpl) getReadLevel() int64 {
+	return t.ackMgr.getReadLevel().VisibilityTimestamp.UnixNano()
+}
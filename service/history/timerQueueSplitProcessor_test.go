@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/mocks"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	timerQueueSplitProcessorSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller          *gomock.Controller
+		mockShard           *shardContextTest
+		mockDomainCache     *cache.MockDomainCache
+		mockClusterMetadata *cluster.MockMetadata
+		mockShardMgr        *mocks.ShardManager
+
+		now            time.Time
+		mainProcessor  *timerQueueActiveProcessorImpl
+		splitProcessor *timerQueueSplitProcessorImpl
+	}
+)
+
+func TestTimerQueueSplitProcessorSuite(t *testing.T) {
+	s := new(timerQueueSplitProcessorSuite)
+	suite.Run(t, s)
+}
+
+func (s *timerQueueSplitProcessorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	config := NewDynamicConfigForTest()
+	config.QueueProcessorEnableSplit = dynamicconfig.GetBoolPropertyFn(true)
+	config.QueueProcessorSplitQueueMergeInterval = dynamicconfig.GetDurationPropertyFn(time.Minute)
+
+	s.now = time.Now().UTC()
+	ackLevel, err := types.TimestampProto(s.now)
+	s.NoError(err)
+
+	s.controller = gomock.NewController(s.T())
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:              0,
+				RangeID:              1,
+				TimerAckLevel:        ackLevel,
+				ClusterTimerAckLevel: map[string]*types.Timestamp{cluster.TestCurrentClusterName: ackLevel},
+			}},
+		config,
+	)
+	s.mockDomainCache = s.mockShard.resource.DomainCache
+	s.mockClusterMetadata = s.mockShard.resource.ClusterMetadata
+	s.mockShardMgr = s.mockShard.resource.ShardMgr
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockDomainCache.EXPECT().GetDomainByID(testDomainID).Return(testLocalDomainEntry, nil).AnyTimes()
+
+	logger := s.mockShard.GetLogger()
+	h := &historyEngineImpl{
+		currentClusterName: cluster.TestCurrentClusterName,
+		shard:              s.mockShard,
+		historyCache:       newHistoryCache(s.mockShard),
+		logger:             logger,
+		metricsClient:      s.mockShard.GetMetricsClient(),
+	}
+	s.mainProcessor = newTimerQueueActiveProcessor(
+		s.mockShard,
+		h,
+		s.mockShard.resource.MatchingClient,
+		newTaskAllocator(s.mockShard),
+		logger,
+	)
+	s.splitProcessor = newTimerQueueSplitProcessor(
+		s.mockShard,
+		h,
+		s.mainProcessor,
+		testDomainID,
+		s.now.Add(-time.Minute),
+		newTaskAllocator(s.mockShard),
+		logger,
+	)
+}
+
+func (s *timerQueueSplitProcessorSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *timerQueueSplitProcessorSuite) TestTaskFilter() {
+	filter := s.splitProcessor.processor.getTaskFilter()
+
+	ok, err := filter(s.newTimer("deadbeef-0123-4567-890a-bcdef0123451", s.now, 7))
+	s.NoError(err)
+	s.False(ok)
+
+	ok, err = filter(s.newTimer(testDomainID, s.now, 7))
+	s.NoError(err)
+	s.True(ok)
+}
+
+func (s *timerQueueSplitProcessorSuite) TestMerge() {
+	s.Equal(s.now.Add(-time.Minute).UnixNano(), s.splitProcessor.getAckLevel())
+	s.True(s.splitProcessor.owns(s.newTimer(testDomainID, s.now.Add(time.Hour), 20)))
+
+	// the split queue keeps the timers up to the read level of the main queue
+	s.splitProcessor.merge()
+	s.True(s.splitProcessor.owns(s.newTimer(testDomainID, s.now.Add(-time.Second), 20)))
+	s.True(s.splitProcessor.owns(s.newTimer(testDomainID, s.now, 0)))
+	s.False(s.splitProcessor.owns(s.newTimer(testDomainID, s.now, 1)))
+	s.False(s.splitProcessor.owns(s.newTimer(testDomainID, s.now.Add(time.Second), 0)))
+	s.Equal(s.now.Add(time.Millisecond), s.splitProcessor.ackMgr.finishLevel)
+
+	// the main queue skips the timers owned by the split queue
+	s.mainProcessor.splitter.splitQueues[testDomainID] = &splitQueueItem{queue: s.splitProcessor, merging: true}
+	s.True(s.mainProcessor.splitter.isOwnedBySplitQueue(s.newTimer(testDomainID, s.now, 0)))
+	s.False(s.mainProcessor.splitter.isOwnedBySplitQueue(s.newTimer(testDomainID, s.now.Add(time.Second), 0)))
+}
+
+func (s *timerQueueSplitProcessorSuite) TestUpdateAckLevel_PersistsSplitQueues() {
+	s.mainProcessor.splitter.splitQueues[testDomainID] = &splitQueueItem{
+		queue:           s.splitProcessor,
+		splitTime:       time.Now(),
+		lastFailureTime: time.Now(),
+	}
+	s.mockShardMgr.On("UpdateShard", mock.Anything).Return(nil).Once()
+
+	s.mainProcessor.timerQueueProcessorBase.timerQueueAckMgr.updateAckLevel()
+	splitAckLevel := s.now.Add(-time.Minute).UnixNano()
+	s.Equal(splitAckLevel, s.mockShard.GetTimerClusterAckLevel(cluster.TestCurrentClusterName).UnixNano())
+	splitQueueAckLevels := s.mockShard.GetTimerSplitQueueAckLevels()
+	s.Len(splitQueueAckLevels, 1)
+	s.Equal(splitAckLevel, splitQueueAckLevels[testDomainID].UnixNano())
+}
+
+func (s *timerQueueSplitProcessorSuite) newTimer(domainID string, timestamp time.Time, taskID int64) *persistenceblobs.TimerTaskInfo {
+	visibilityTimestamp, err := types.TimestampProto(timestamp)
+	s.NoError(err)
+	return &persistenceblobs.TimerTaskInfo{
+		DomainID:            primitives.MustParseUUID(domainID),
+		TaskID:              taskID,
+		VisibilityTimestamp: visibilityTimestamp,
+	}
+}
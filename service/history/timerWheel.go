@@ -0,0 +1,207 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"time"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+const (
+	timerWheelSlots = 64
+)
+
+type (
+	// timerWheel is a hierarchical timer wheel holding the timers in memory until they fire,
+	// level 0 has one slot per tick, and each slot of level n covers all slots of level n - 1.
+	// The timer wheel is not thread safe, and it is driven by the caller through advance.
+	timerWheel struct {
+		tick   time.Duration
+		levels [][timerWheelSlots][]*timerWheelEntry
+		// spans is the number of ticks covered by one slot of each level
+		spans []int64
+		// currentTick is the last tick the timer wheel advanced to,
+		// all timers with fire tick up to the current tick are fired
+		currentTick int64
+		size        int
+	}
+
+	timerWheelEntry struct {
+		fireTick int64
+		timer    *persistenceblobs.TimerTaskInfo
+	}
+)
+
+func newTimerWheel(
+	tick time.Duration,
+	numLevels int,
+	now time.Time,
+) *timerWheel {
+
+	spans := make([]int64, numLevels)
+	span := int64(1)
+	for level := range spans {
+		spans[level] = span
+		span *= timerWheelSlots
+	}
+	return &timerWheel{
+		tick:        tick,
+		levels:      make([][timerWheelSlots][]*timerWheelEntry, numLevels),
+		spans:       spans,
+		currentTick: now.UnixNano() / int64(tick),
+	}
+}
+
+// horizon returns the duration after which timers no longer fit into the timer wheel
+func (w *timerWheel) horizon() time.Duration {
+	span := w.spans[len(w.spans)-1] * timerWheelSlots
+	return time.Duration(span-1) * w.tick
+}
+
+// fits returns true if a timer with the given fire time can be added to the timer wheel
+func (w *timerWheel) fits(
+	fireTime time.Time,
+) bool {
+
+	fireTick := w.fireTick(fireTime)
+	return fireTick > w.currentTick && fireTick-w.currentTick < w.spans[len(w.spans)-1]*timerWheelSlots
+}
+
+// add schedules the timer to fire at the given time, returns false if the fire
+// time is beyond the horizon of the timer wheel or if the timer is already due
+func (w *timerWheel) add(
+	timer *persistenceblobs.TimerTaskInfo,
+	fireTime time.Time,
+) bool {
+
+	fireTick := w.fireTick(fireTime)
+	if fireTick <= w.currentTick {
+		return false
+	}
+	if !w.insert(&timerWheelEntry{fireTick: fireTick, timer: timer}) {
+		return false
+	}
+	w.size++
+	return true
+}
+
+// advance moves the timer wheel to the given time, and returns the timers fired in between
+func (w *timerWheel) advance(
+	now time.Time,
+) []*persistenceblobs.TimerTaskInfo {
+
+	nowTick := now.UnixNano() / int64(w.tick)
+	if w.size == 0 {
+		if nowTick > w.currentTick {
+			w.currentTick = nowTick
+		}
+		return nil
+	}
+
+	var fired []*persistenceblobs.TimerTaskInfo
+	for w.currentTick < nowTick && w.size > 0 {
+		w.currentTick++
+		w.cascade()
+
+		slot := &w.levels[0][w.currentTick%timerWheelSlots]
+		for _, entry := range *slot {
+			fired = append(fired, entry.timer)
+		}
+		w.size -= len(*slot)
+		*slot = nil
+	}
+	if w.size == 0 && nowTick > w.currentTick {
+		w.currentTick = nowTick
+	}
+	return fired
+}
+
+// nextFireTime returns the time at which the timer wheel needs to be advanced next,
+// it is the earliest of the fire time of the next level 0 timer and the times the
+// next non empty slots of the higher levels cascade, zero time if the timer wheel is empty
+func (w *timerWheel) nextFireTime() time.Time {
+	if w.size == 0 {
+		return time.Time{}
+	}
+
+	nextTick := int64(-1)
+	for level, span := range w.spans {
+		slotTick := w.currentTick / span
+		for i := int64(1); i <= timerWheelSlots; i++ {
+			if len(w.levels[level][(slotTick+i)%timerWheelSlots]) == 0 {
+				continue
+			}
+			// a level 0 slot fires at its tick, a higher level slot cascades at its first tick
+			if tick := (slotTick + i) * span; nextTick < 0 || tick < nextTick {
+				nextTick = tick
+			}
+			break
+		}
+	}
+	if nextTick < 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nextTick*int64(w.tick))
+}
+
+func (w *timerWheel) len() int {
+	return w.size
+}
+
+// fireTick rounds the fire time up, so that the timer never fires before its fire time
+func (w *timerWheel) fireTick(
+	fireTime time.Time,
+) int64 {
+
+	return (fireTime.UnixNano() + int64(w.tick) - 1) / int64(w.tick)
+}
+
+func (w *timerWheel) insert(
+	entry *timerWheelEntry,
+) bool {
+
+	delta := entry.fireTick - w.currentTick
+	for level, span := range w.spans {
+		if delta < span*timerWheelSlots {
+			index := (entry.fireTick / span) % timerWheelSlots
+			w.levels[level][index] = append(w.levels[level][index], entry)
+			return true
+		}
+	}
+	return false
+}
+
+// cascade moves the timers of the higher level slots starting at the current tick into the lower levels,
+// the highest level is cascaded first, so that its timers can be cascaded again by the lower levels
+func (w *timerWheel) cascade() {
+	for level := len(w.levels) - 1; level > 0; level-- {
+		if w.currentTick%w.spans[level] != 0 {
+			continue
+		}
+		slot := &w.levels[level][(w.currentTick/w.spans[level])%timerWheelSlots]
+		entries := *slot
+		*slot = nil
+		for _, entry := range entries {
+			w.insert(entry)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+type (
+	timerWheelSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		now        time.Time
+		timerWheel *timerWheel
+	}
+)
+
+func TestTimerWheelSuite(t *testing.T) {
+	s := new(timerWheelSuite)
+	suite.Run(t, s)
+}
+
+func (s *timerWheelSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.now = time.Unix(0, 0).Add(time.Hour)
+	s.timerWheel = newTimerWheel(10*time.Millisecond, 2, s.now)
+}
+
+func (s *timerWheelSuite) TestAdd_OutOfRange() {
+	s.Equal(4095*10*time.Millisecond, s.timerWheel.horizon())
+
+	s.False(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 1}, s.now))
+	s.False(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 2}, s.now.Add(-time.Second)))
+	s.False(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 3}, s.now.Add(time.Minute)))
+	s.Equal(0, s.timerWheel.len())
+	s.True(s.timerWheel.nextFireTime().IsZero())
+}
+
+func (s *timerWheelSuite) TestAdvance_NeverFiresEarly() {
+	fireTime := s.now.Add(25 * time.Millisecond)
+	s.True(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 1}, fireTime))
+	s.Equal(s.now.Add(30*time.Millisecond).UnixNano(), s.timerWheel.nextFireTime().UnixNano())
+
+	s.Empty(s.timerWheel.advance(fireTime.Add(-time.Nanosecond)))
+	fired := s.timerWheel.advance(s.now.Add(30 * time.Millisecond))
+	s.Len(fired, 1)
+	s.Equal(int64(1), fired[0].TaskID)
+	s.Equal(0, s.timerWheel.len())
+	s.True(s.timerWheel.nextFireTime().IsZero())
+}
+
+func (s *timerWheelSuite) TestAdvance_Cascade() {
+	// the second timer is on level 1 and cascades into level 0 before the first timer fires
+	s.True(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 1}, s.now.Add(30*time.Second)))
+	s.True(s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: 2}, s.now.Add(700*time.Millisecond)))
+	s.Equal(2, s.timerWheel.len())
+
+	s.Equal(s.now.Add(640*time.Millisecond).UnixNano(), s.timerWheel.nextFireTime().UnixNano())
+	s.Empty(s.timerWheel.advance(s.now.Add(640 * time.Millisecond)))
+	s.Equal(s.now.Add(700*time.Millisecond).UnixNano(), s.timerWheel.nextFireTime().UnixNano())
+
+	fired := s.timerWheel.advance(s.now.Add(time.Second))
+	s.Len(fired, 1)
+	s.Equal(int64(2), fired[0].TaskID)
+
+	fired = s.timerWheel.advance(s.now.Add(time.Minute))
+	s.Len(fired, 1)
+	s.Equal(int64(1), fired[0].TaskID)
+	s.Equal(0, s.timerWheel.len())
+}
+
+func (s *timerWheelSuite) TestAdvance_FiresInOrder() {
+	fireTimes := make(map[int64]time.Time)
+	for taskID := int64(1); taskID <= 1000; taskID++ {
+		fireTime := s.now.Add(time.Duration(rand.Int63n(int64(40 * time.Second))))
+		if s.timerWheel.add(&persistenceblobs.TimerTaskInfo{TaskID: taskID}, fireTime) {
+			fireTimes[taskID] = fireTime
+		}
+	}
+	s.Equal(len(fireTimes), s.timerWheel.len())
+
+	lastNow := s.now
+	for s.timerWheel.len() > 0 {
+		now := s.timerWheel.nextFireTime()
+		s.True(now.After(lastNow))
+		for _, timer := range s.timerWheel.advance(now) {
+			fireTime, ok := fireTimes[timer.TaskID]
+			s.True(ok)
+			// the timer neither fires early, nor is skipped by an earlier advance
+			s.False(fireTime.After(now))
+			s.True(fireTime.After(lastNow))
+			delete(fireTimes, timer.TaskID)
+		}
+		lastNow = now
+	}
+	s.Empty(fireTimes)
+}
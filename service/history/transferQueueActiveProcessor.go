@@ -47,6 +47,9 @@ type (
 		logger             log.Logger
 		metricsClient      metrics.Client
 		taskExecutor       queueTaskExecutor
+		// splitter is only set for the main processor of the shard
+		splitter      *queueSplitter
+		onTaskFailure func(taskInfo *taskInfo, err error)
 	}
 )
 
@@ -84,15 +87,27 @@ func newTransferQueueActiveProcessor(
 	maxReadAckLevel := func() int64 {
 		return shard.GetTransferMaxReadLevel()
 	}
+
+	var processor *transferQueueActiveProcessorImpl
+	splitter := newQueueSplitter(
+		splitQueueTypeTransfer,
+		metrics.TransferActiveQueueProcessorScope,
+		shard,
+		func(domainID string, ackLevel int64) splitQueue {
+			return newTransferQueueSplitProcessor(shard, historyService, processor, domainID, ackLevel, taskAllocator, logger)
+		},
+		logger,
+	)
 	updateTransferAckLevel := func(ackLevel int64) error {
-		return shard.UpdateTransferClusterAckLevel(currentClusterName, ackLevel)
+		splitter.mergeHealthySplitQueues()
+		return shard.UpdateTransferSplitQueueAckLevels(currentClusterName, splitter.getMinAckLevel(ackLevel), splitter.getAckLevels())
 	}
 
 	transferQueueShutdown := func() error {
 		return nil
 	}
 
-	processor := &transferQueueActiveProcessorImpl{
+	processor = &transferQueueActiveProcessorImpl{
 		currentClusterName: currentClusterName,
 		shard:              shard,
 		logger:             logger,
@@ -113,6 +128,10 @@ func newTransferQueueActiveProcessor(
 			transferQueueShutdown,
 			logger,
 		),
+		splitter: splitter,
+	}
+	processor.onTaskFailure = func(taskInfo *taskInfo, err error) {
+		splitter.reportFailure(taskInfo.task, taskInfo.attempt+1, err, processor.getQueueAckLevel())
 	}
 
 	queueAckMgr := newQueueAckMgr(shard, options, processor, shard.GetTransferClusterAckLevel(currentClusterName), logger)
@@ -214,6 +233,20 @@ func newTransferQueueFailoverProcessor(
 	return updateTransferAckLevel, processor
 }
 
+func (t *transferQueueActiveProcessorImpl) Start() {
+	t.queueProcessorBase.Start()
+	if t.splitter != nil {
+		t.splitter.restore(t.shard.GetTransferSplitQueueAckLevels())
+	}
+}
+
+func (t *transferQueueActiveProcessorImpl) Stop() {
+	t.queueProcessorBase.Stop()
+	if t.splitter != nil {
+		t.splitter.stop()
+	}
+}
+
 func (t *transferQueueActiveProcessorImpl) getTaskFilter() taskFilter {
 	return t.transferTaskFilter
 }
@@ -234,5 +267,14 @@ func (t *transferQueueActiveProcessorImpl) process(
 ) (int, error) {
 	// TODO: task metricScope should be determined when creating taskInfo
 	metricScope := t.getTransferTaskMetricsScope(taskInfo.task.GetTaskType(), true)
-	return metricScope, t.taskExecutor.execute(taskInfo.task, taskInfo.shouldProcessTask)
+	if t.splitter != nil && t.splitter.isOwnedBySplitQueue(taskInfo.task) {
+		// the task is processed by the split queue of its domain
+		return metricScope, nil
+	}
+
+	err := t.taskExecutor.execute(taskInfo.task, taskInfo.shouldProcessTask)
+	if err != nil && t.onTaskFailure != nil {
+		t.onTaskFailure(taskInfo, err)
+	}
+	return metricScope, err
 }
@@ -23,7 +23,10 @@ package history
 import (
 	"bytes"
 	ctx "context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/pborman/uuid"
 	"go.temporal.io/temporal-proto/enums"
@@ -34,10 +37,14 @@ import (
 	commonproto "go.temporal.io/temporal-proto/common"
 
 	h "github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/.gen/proto/historystream"
+	m "github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/client/history"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
+	"github.com/temporalio/temporal/common/callback"
+	"github.com/temporalio/temporal/common/codec"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
@@ -52,6 +59,7 @@ type (
 
 		historyClient           history.Client
 		parentClosePolicyClient parentclosepolicy.Client
+		callbackHTTPClient      *http.Client
 	}
 )
 
@@ -77,6 +85,10 @@ func newTransferQueueActiveTaskExecutor(
 			historyService.publicClient,
 			config.NumParentClosePolicySystemWorkflows(),
 		),
+		callbackHTTPClient: &http.Client{
+			// redirects could lead the callback to a host which is not allowed
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
 	}
 }
 
@@ -113,6 +125,10 @@ func (t *transferQueueActiveTaskExecutor) execute(
 		return t.processResetWorkflow(task)
 	case persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
 		return t.processUpsertWorkflowSearchAttributes(task)
+	case persistence.TransferTaskTypeCompletionCallback:
+		return t.processCompletionCallback(task)
+	case persistence.TransferTaskTypeHistoryStream:
+		return t.processHistoryStream(task)
 	default:
 		return errUnknownTransferTask
 	}
@@ -147,12 +163,22 @@ func (t *transferQueueActiveTaskExecutor) processActivityTask(
 	if err != nil || !ok {
 		return err
 	}
+	if ai.StartedID != common.EmptyEventID {
+		// activity was already started, most likely dispatched eagerly to the worker completing the decision
+		return nil
+	}
+
+	scheduledEvent, err := mutableState.GetActivityScheduledEvent(ai.ScheduleID)
+	if err != nil {
+		return err
+	}
+	activityType := scheduledEvent.GetActivityTaskScheduledEventAttributes().GetActivityType().GetName()
 
 	timeout := common.MinInt32(ai.ScheduleToStartTimeout, common.MaxTaskTimeout)
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	return t.pushActivity(task, timeout)
+	return t.pushActivity(task, timeout, activityType)
 }
 
 func (t *transferQueueActiveTaskExecutor) processDecisionTask(
@@ -268,6 +294,18 @@ func (t *transferQueueActiveTaskExecutor) processCloseExecution(
 	domainName := mutableState.GetDomainEntry().GetInfo().Name
 	children := mutableState.GetPendingChildExecutionInfos()
 
+	// the activities still pending when the workflow closed never respond, so their slots are released here
+	var releaseRequests []*m.ReleaseActivityTaskSlotRequest
+	for _, ai := range mutableState.GetPendingActivityInfos() {
+		releaseRequest, err := newReleaseActivityTaskSlotRequest(t.shard, mutableState, ai)
+		if err != nil {
+			return err
+		}
+		if releaseRequest != nil {
+			releaseRequests = append(releaseRequests, releaseRequest)
+		}
+	}
+
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
@@ -289,6 +327,10 @@ func (t *transferQueueActiveTaskExecutor) processCloseExecution(
 		return err
 	}
 
+	if err := releaseActivityTaskSlots(t.matchingClient, releaseRequests); err != nil {
+		return err
+	}
+
 	// Communicate the result to parent execution if this is Child Workflow execution
 	if replyToParentWorkflow {
 		ctx, cancel := ctx.WithTimeout(ctx.Background(), transferActiveTaskDefaultTimeout)
@@ -825,6 +867,369 @@ func (t *transferQueueActiveTaskExecutor) processResetWorkflow(
 	return nil
 }
 
+func (t *transferQueueActiveTaskExecutor) processCompletionCallback(
+	task *persistenceblobs.TransferTaskInfo,
+) (retError error) {
+
+	context, release, err := t.cache.getOrCreateWorkflowExecutionForBackground(
+		t.getDomainIDAndWorkflowExecution(task),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { release(retError) }()
+
+	mutableState, err := loadMutableStateForTransferTask(context, task, t.metricsClient, t.logger)
+	if err != nil {
+		return err
+	}
+	if mutableState == nil || mutableState.IsWorkflowExecutionRunning() {
+		return nil
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	if task.ScheduleID >= int64(len(executionInfo.CompletionCallbacks)) {
+		return nil
+	}
+	completionCallback := executionInfo.CompletionCallbacks[task.ScheduleID]
+	if completionCallback.GetState() != persistence.CompletionCallbackStatePending {
+		return nil
+	}
+
+	lastWriteVersion, err := mutableState.GetLastWriteVersion()
+	if err != nil {
+		return err
+	}
+	ok, err := verifyTaskVersion(t.shard, t.logger, task.DomainID, lastWriteVersion, task.Version, task)
+	if err != nil || !ok {
+		return err
+	}
+
+	completionEvent, err := mutableState.GetCompletionEvent()
+	if err != nil {
+		return err
+	}
+	encodedEvent, err := codec.NewJSONPBEncoder().Encode(completionEvent)
+	if err != nil {
+		return err
+	}
+	domainName := mutableState.GetDomainEntry().GetInfo().Name
+	payload, err := json.Marshal(&callback.Payload{
+		Domain:          domainName,
+		WorkflowID:      task.WorkflowID,
+		RunID:           primitives.UUIDString(task.RunID),
+		CloseStatus:     executionInfo.CloseStatus.String(),
+		CompletionEvent: encodedEvent,
+	})
+	if err != nil {
+		return err
+	}
+	target := &persistenceblobs.CompletionCallbackInfo{
+		Url:              completionCallback.GetUrl(),
+		SignalDomain:     completionCallback.GetSignalDomain(),
+		SignalWorkflowID: completionCallback.GetSignalWorkflowID(),
+		SignalRunID:      completionCallback.GetSignalRunID(),
+		SignalName:       completionCallback.GetSignalName(),
+	}
+
+	// release the context lock since we no longer need mutable state builder and
+	// the rest of logic is making RPC call, which takes time.
+	release(nil)
+	deliveryErr := t.deliverCompletionCallback(task, domainName, target, payload)
+
+	return t.recordCompletionCallbackResult(task, domainName, deliveryErr)
+}
+
+func (t *transferQueueActiveTaskExecutor) deliverCompletionCallback(
+	task *persistenceblobs.TransferTaskInfo,
+	domainName string,
+	target *persistenceblobs.CompletionCallbackInfo,
+	payload []byte,
+) error {
+
+	ctx, cancel := ctx.WithTimeout(ctx.Background(), t.config.CompletionCallbackTimeout(domainName))
+	defer cancel()
+
+	if !callback.IsSignal(target) {
+		// the allowed hosts may have changed since the workflow was started
+		if err := callback.CheckAllowed(target, t.config.CompletionCallbackAllowedHosts(domainName)); err != nil {
+			return &callback.DeliveryError{Message: err.Error()}
+		}
+		return callback.PostHTTP(ctx, t.callbackHTTPClient, target.GetUrl(), payload)
+	}
+
+	targetDomainID, err := t.shard.GetDomainCache().GetDomainID(target.GetSignalDomain())
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return &callback.DeliveryError{Message: err.Error()}
+		}
+		return err
+	}
+
+	request := &h.SignalWorkflowExecutionRequest{
+		DomainUUID: targetDomainID,
+		SignalRequest: &workflowservice.SignalWorkflowExecutionRequest{
+			Domain: target.GetSignalDomain(),
+			WorkflowExecution: &commonproto.WorkflowExecution{
+				WorkflowId: target.GetSignalWorkflowID(),
+				RunId:      target.GetSignalRunID(),
+			},
+			Identity:   identityHistoryService,
+			SignalName: target.GetSignalName(),
+			Input:      payload,
+			// Use a request ID derived from the callback to deduplicate SignalWorkflowExecution calls
+			RequestId: uuid.NewSHA1(uuid.NIL, []byte(fmt.Sprintf("%v/%v", primitives.UUIDString(task.RunID), task.ScheduleID))).String(),
+		},
+	}
+	op := func() error {
+		_, err := t.historyClient.SignalWorkflowExecution(ctx, request)
+		return err
+	}
+
+	err = backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
+	switch err.(type) {
+	case *serviceerror.NotFound, *serviceerror.InvalidArgument:
+		// target workflow does not exist or is already closed
+		return &callback.DeliveryError{Message: err.Error()}
+	}
+	return err
+}
+
+func (t *transferQueueActiveTaskExecutor) recordCompletionCallbackResult(
+	task *persistenceblobs.TransferTaskInfo,
+	domainName string,
+	deliveryErr error,
+) (retError error) {
+
+	context, release, err := t.cache.getOrCreateWorkflowExecutionForBackground(
+		t.getDomainIDAndWorkflowExecution(task),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { release(retError) }()
+
+	mutableState, err := loadMutableStateForTransferTask(context, task, t.metricsClient, t.logger)
+	if err != nil {
+		return err
+	}
+	if mutableState == nil {
+		return nil
+	}
+	executionInfo := mutableState.GetExecutionInfo()
+	if task.ScheduleID >= int64(len(executionInfo.CompletionCallbacks)) {
+		return nil
+	}
+	completionCallback := executionInfo.CompletionCallbacks[task.ScheduleID]
+	if completionCallback.GetState() != persistence.CompletionCallbackStatePending {
+		return nil
+	}
+
+	scope := t.metricsClient.Scope(metrics.TransferActiveTaskCompletionCallbackScope, metrics.DomainTag(domainName))
+	now := t.shard.GetTimeSource().Now()
+	completionCallback.Attempt++
+	completionCallback.LastAttemptTimestampNanos = now.UnixNano()
+	retryDelivery := false
+	switch {
+	case deliveryErr == nil:
+		completionCallback.State = persistence.CompletionCallbackStateSucceeded
+		completionCallback.LastFailure = ""
+		scope.IncCounter(metrics.CompletionCallbackDeliveredCounter)
+	case callback.IsRetryableError(deliveryErr) &&
+		int(completionCallback.Attempt) < t.config.CompletionCallbackMaxAttempts(domainName):
+		completionCallback.LastFailure = deliveryErr.Error()
+		retryDelivery = true
+	default:
+		completionCallback.State = persistence.CompletionCallbackStateFailed
+		completionCallback.LastFailure = deliveryErr.Error()
+		scope.IncCounter(metrics.CompletionCallbackFailedCounter)
+		t.logger.Warn("Completion callback delivery failed permanently.",
+			tag.WorkflowDomainName(domainName),
+			tag.WorkflowID(task.WorkflowID),
+			tag.WorkflowRunIDBytes(task.RunID),
+			tag.Error(deliveryErr),
+		)
+	}
+
+	if err := t.updateWorkflowExecutionWithoutEvents(task, context, now); err != nil {
+		return err
+	}
+
+	if retryDelivery {
+		// fail the task so it is redelivered with backoff
+		return deliveryErr
+	}
+	return nil
+}
+
+func (t *transferQueueActiveTaskExecutor) processHistoryStream(
+	task *persistenceblobs.TransferTaskInfo,
+) error {
+
+	domainEntry, err := t.shard.GetDomainCache().GetDomainByID(primitives.UUIDString(task.DomainID))
+	if err != nil {
+		return err
+	}
+	domainName := domainEntry.GetInfo().Name
+	if !t.config.HistoryStreamEnabled(domainName) {
+		return nil
+	}
+	publisher := t.historyService.historyStreamPublisher
+	if publisher == nil {
+		return errHistoryStreamNotConfigured
+	}
+
+	// the events of a run are published in order by one task at a time, the workflow lock is only held
+	// to read and update the progress of the stream so the workflow is not blocked while publishing
+	runID := primitives.UUIDString(task.RunID)
+	publisher.lockRun(runID)
+	defer publisher.unlockRun(runID)
+
+	stream, err := t.loadHistoryStream(task)
+	if err != nil || stream == nil {
+		return err
+	}
+
+	// publish all events not yet published up to the last event of the task, the events of
+	// this task may already be published by a task of the run which was processed earlier
+	nextEventID := task.ScheduleID + 1
+	if stream.firstEventID >= nextEventID {
+		return nil
+	}
+
+	application := t.config.HistoryStreamKafkaApplication(domainName)
+	redaction := t.config.HistoryStreamRedaction(domainName)
+	scope := t.metricsClient.Scope(metrics.TransferActiveTaskHistoryStreamScope, metrics.DomainTag(domainName))
+	request := &persistence.ReadHistoryBranchRequest{
+		BranchToken: stream.branchToken,
+		MinEventID:  stream.firstEventID,
+		MaxEventID:  nextEventID,
+		PageSize:    t.config.HistoryStreamMaxBatchesPerMessage(domainName),
+		ShardID:     common.IntPtr(t.shard.GetShardID()),
+	}
+	for {
+		response, err := t.shard.GetHistoryManager().ReadHistoryBranch(request)
+		if err != nil {
+			return err
+		}
+
+		if events := response.HistoryEvents; len(events) > 0 {
+			redactHistoryEvents(events, redaction)
+			if err := publisher.publish(application, &historystream.Message{
+				DomainID:     primitives.UUIDString(task.DomainID),
+				Domain:       domainName,
+				WorkflowID:   task.WorkflowID,
+				RunID:        runID,
+				WorkflowType: stream.workflowType,
+				TaskList:     stream.taskList,
+				FirstEventID: events[0].GetEventId(),
+				NextEventID:  events[len(events)-1].GetEventId() + 1,
+				Events:       events,
+				Redaction:    redaction,
+			}); err != nil {
+				return err
+			}
+			scope.AddCounter(metrics.HistoryStreamEventsPublishedCounter, int64(len(events)))
+		}
+
+		if len(response.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+
+	return t.updateHistoryStreamNextEventID(task, nextEventID)
+}
+
+// loadHistoryStream reads the progress of the history stream of the run and what is needed to publish its events,
+// it returns nil if the run no longer exists
+func (t *transferQueueActiveTaskExecutor) loadHistoryStream(
+	task *persistenceblobs.TransferTaskInfo,
+) (retStream *historyStream, retError error) {
+
+	context, release, err := t.cache.getOrCreateWorkflowExecutionForBackground(
+		t.getDomainIDAndWorkflowExecution(task),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { release(retError) }()
+
+	mutableState, err := loadMutableStateForTransferTask(context, task, t.metricsClient, t.logger)
+	if err != nil || mutableState == nil {
+		return nil, err
+	}
+
+	branchToken, err := mutableState.GetCurrentBranchToken()
+	if err != nil {
+		return nil, err
+	}
+	executionInfo := mutableState.GetExecutionInfo()
+	return &historyStream{
+		firstEventID: common.MaxInt64(executionInfo.HistoryStreamNextEventID, common.FirstEventID),
+		branchToken:  branchToken,
+		workflowType: executionInfo.WorkflowTypeName,
+		taskList:     executionInfo.TaskList,
+	}, nil
+}
+
+// updateHistoryStreamNextEventID records the progress of the history stream of the run in its mutable state.
+// The progress is not persisted right away, it is written with the next update of the workflow, which saves
+// a write per transaction. The events published since the last persisted progress are published again if the
+// mutable state is reloaded before, so the events are published at least once.
+func (t *transferQueueActiveTaskExecutor) updateHistoryStreamNextEventID(
+	task *persistenceblobs.TransferTaskInfo,
+	nextEventID int64,
+) (retError error) {
+
+	context, release, err := t.cache.getOrCreateWorkflowExecutionForBackground(
+		t.getDomainIDAndWorkflowExecution(task),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { release(retError) }()
+
+	mutableState, err := loadMutableStateForTransferTask(context, task, t.metricsClient, t.logger)
+	if err != nil || mutableState == nil {
+		return err
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	executionInfo.HistoryStreamNextEventID = common.MaxInt64(executionInfo.HistoryStreamNextEventID, nextEventID)
+	return nil
+}
+
+// updateWorkflowExecutionWithoutEvents persists mutable state changes which do not add history events,
+// the workflow may be closed and no longer the current run of its workflow ID
+func (t *transferQueueActiveTaskExecutor) updateWorkflowExecutionWithoutEvents(
+	task *persistenceblobs.TransferTaskInfo,
+	context workflowExecutionContext,
+	now time.Time,
+) error {
+
+	updateMode := persistence.UpdateWorkflowModeUpdateCurrent
+	resp, err := t.shard.GetExecutionManager().GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   primitives.UUIDString(task.DomainID),
+		WorkflowID: task.WorkflowID,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.RunID != primitives.UUIDString(task.RunID) {
+		updateMode = persistence.UpdateWorkflowModeBypassCurrent
+	}
+
+	return context.updateWorkflowExecutionWithNew(
+		now,
+		updateMode,
+		nil,
+		nil,
+		transactionPolicyActive,
+		nil,
+	)
+}
+
 func (t *transferQueueActiveTaskExecutor) recordChildExecutionStarted(
 	task *persistenceblobs.TransferTaskInfo,
 	context workflowExecutionContext,
@@ -21,6 +21,9 @@
 package history
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -37,6 +40,7 @@ import (
 
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservicemock"
+	"github.com/temporalio/temporal/.gen/proto/historystream"
 	"github.com/temporalio/temporal/.gen/proto/matchingservice"
 	"github.com/temporalio/temporal/.gen/proto/matchingservicemock"
 	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
@@ -45,6 +49,7 @@ import (
 	"github.com/temporalio/temporal/common/archiver/provider"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/callback"
 	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/cluster"
 	"github.com/temporalio/temporal/common/log"
@@ -135,6 +140,7 @@ func (s *transferQueueActiveTaskExecutorSuite) SetupTest() {
 	s.mockTxProcessor.EXPECT().NotifyNewTask(gomock.Any(), gomock.Any()).AnyTimes()
 	s.mockReplicationProcessor.EXPECT().notifyNewTask().AnyTimes()
 	s.mockTimerProcessor.EXPECT().NotifyNewTimers(gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockTimerProcessor.EXPECT().NotifyNewWorkflowTimers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 	config := NewDynamicConfigForTest()
 	s.mockShard = newTestShardContext(
@@ -261,7 +267,7 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessActivityTask_Success()
 
 	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
 	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
-	s.mockMatchingClient.EXPECT().AddActivityTask(gomock.Any(), s.createAddActivityTaskRequest(transferTask, ai)).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
+	s.mockMatchingClient.EXPECT().AddActivityTask(gomock.Any(), s.createAddActivityTaskRequest(transferTask, ai, activityType)).Return(&matchingservice.AddActivityTaskResponse{}, nil).Times(1)
 
 	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
 	s.Nil(err)
@@ -328,6 +334,63 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessActivityTask_Duplicati
 	s.Nil(err)
 }
 
+func (s *transferQueueActiveTaskExecutorSuite) TestProcessActivityTask_EagerlyStarted() {
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+	workflowType := "some random workflow type"
+	taskListName := "some random task list"
+
+	mutableState := newMutableStateBuilderWithReplicationStateWithEventV2(s.mockShard, s.mockShard.GetEventsCache(), s.logger, s.version, execution.GetRunId())
+	_, err := mutableState.AddWorkflowExecutionStartedEvent(
+		execution,
+		&historyservice.StartWorkflowExecutionRequest{
+			DomainUUID: s.domainID,
+			StartRequest: &workflowservice.StartWorkflowExecutionRequest{
+				WorkflowType:                        &commonproto.WorkflowType{Name: workflowType},
+				TaskList:                            &commonproto.TaskList{Name: taskListName},
+				ExecutionStartToCloseTimeoutSeconds: 2,
+				TaskStartToCloseTimeoutSeconds:      1,
+			},
+		},
+	)
+	s.Nil(err)
+
+	di := addDecisionTaskScheduledEvent(mutableState)
+	event := addDecisionTaskStartedEvent(mutableState, di.ScheduleID, taskListName, uuid.New())
+	di.StartedID = event.GetEventId()
+	event = addDecisionTaskCompletedEvent(mutableState, di.ScheduleID, di.StartedID, nil, "some random identity")
+
+	taskID := int64(59)
+	activityID := "activity-1"
+	activityType := "some random activity type"
+	event, ai := addActivityTaskScheduledEvent(mutableState, event.GetEventId(), activityID, activityType, taskListName, []byte{}, 1, 1, 1)
+
+	transferTask := &persistenceblobs.TransferTaskInfo{
+		Version:        s.version,
+		DomainID:       s.GetDomainIDBytes(),
+		TargetDomainID: primitives.MustParseUUID(s.targetDomainID),
+		WorkflowID:     execution.GetWorkflowId(),
+		RunID:          primitives.MustParseUUID(execution.GetRunId()),
+		TaskID:         taskID,
+		TaskList:       taskListName,
+		TaskType:       persistence.TransferTaskTypeActivityTask,
+		ScheduleID:     event.GetEventId(),
+	}
+
+	// activity started together with the decision completion, no task should be pushed to matching
+	event = addActivityTaskStartedEvent(mutableState, event.GetEventId(), "some random identity")
+	ai.StartedID = event.GetEventId()
+
+	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+}
+
 func (s *transferQueueActiveTaskExecutorSuite) TestProcessDecisionTask_FirstDecision() {
 
 	execution := commonproto.WorkflowExecution{
@@ -719,6 +782,71 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessCloseExecution_NoParen
 	s.Nil(err)
 }
 
+func (s *transferQueueActiveTaskExecutorSuite) TestProcessCloseExecution_ReleaseActivityTaskSlots() {
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+	workflowType := "some random workflow type"
+	taskListName := "some random task list"
+	limitedActivityType := "some limited activity type"
+	s.mockShard.config.ActivityConcurrencyLimits = dc.GetMapPropertyFn(map[string]interface{}{limitedActivityType: 1})
+
+	mutableState := newMutableStateBuilderWithReplicationStateWithEventV2(s.mockShard, s.mockShard.GetEventsCache(), s.logger, s.version, execution.GetRunId())
+	_, err := mutableState.AddWorkflowExecutionStartedEvent(
+		execution,
+		&historyservice.StartWorkflowExecutionRequest{
+			DomainUUID: s.domainID,
+			StartRequest: &workflowservice.StartWorkflowExecutionRequest{
+				WorkflowType:                        &commonproto.WorkflowType{Name: workflowType},
+				TaskList:                            &commonproto.TaskList{Name: taskListName},
+				ExecutionStartToCloseTimeoutSeconds: 2,
+				TaskStartToCloseTimeoutSeconds:      1,
+			},
+		},
+	)
+	s.Nil(err)
+
+	di := addDecisionTaskScheduledEvent(mutableState)
+	event := addDecisionTaskStartedEvent(mutableState, di.ScheduleID, taskListName, uuid.New())
+	di.StartedID = event.GetEventId()
+	event = addDecisionTaskCompletedEvent(mutableState, di.ScheduleID, di.StartedID, nil, "some random identity")
+
+	limitedEvent, _ := addActivityTaskScheduledEvent(mutableState, event.GetEventId(), "activity-1", limitedActivityType, taskListName, []byte{}, 1, 1, 1)
+	addActivityTaskScheduledEvent(mutableState, event.GetEventId(), "activity-2", "some random activity type", taskListName, []byte{}, 1, 1, 1)
+
+	taskID := int64(59)
+	event = addCompleteWorkflowEvent(mutableState, event.GetEventId(), nil)
+
+	transferTask := &persistenceblobs.TransferTaskInfo{
+		Version:    s.version,
+		DomainID:   s.GetDomainIDBytes(),
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      primitives.MustParseUUID(execution.GetRunId()),
+		TaskID:     taskID,
+		TaskList:   taskListName,
+		TaskType:   persistence.TransferTaskTypeCloseExecution,
+		ScheduleID: event.GetEventId(),
+	}
+
+	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+	s.mockVisibilityMgr.On("RecordWorkflowExecutionClosed", mock.Anything).Return(nil).Once()
+	s.mockArchivalMetadata.On("GetVisibilityConfig").Return(archiver.NewArchivalConfig("enabled", dc.GetStringPropertyFn("enabled"), dc.GetBoolPropertyFn(true), "disabled", "random URI"))
+	s.mockArchivalClient.On("Archive", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	s.mockMatchingClient.EXPECT().ReleaseActivityTaskSlot(gomock.Any(), &matchingservice.ReleaseActivityTaskSlotRequest{
+		DomainUUID:   s.domainID,
+		TaskList:     &commonproto.TaskList{Name: taskListName, Kind: enums.TaskListKindNormal},
+		Execution:    &execution,
+		ScheduleId:   limitedEvent.GetEventId(),
+		ActivityType: limitedActivityType,
+	}).Return(&matchingservice.ReleaseActivityTaskSlotResponse{}, nil).Times(1)
+
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+}
+
 func (s *transferQueueActiveTaskExecutorSuite) TestProcessCloseExecution_NoParent_HasFewChildren() {
 
 	execution := commonproto.WorkflowExecution{
@@ -1034,6 +1162,141 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessCloseExecution_NoParen
 	s.Nil(err)
 }
 
+func (s *transferQueueActiveTaskExecutorSuite) TestProcessCompletionCallback_HTTP() {
+
+	var payload callback.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.NoError(json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	s.transferQueueActiveTaskExecutor.config.CompletionCallbackAllowedHosts = dc.GetStringPropertyFnFilteredByDomain("127.0.0.1")
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+	workflowType := "some random workflow type"
+	taskListName := "some random task list"
+
+	mutableState := newMutableStateBuilderWithReplicationStateWithEventV2(s.mockShard, s.mockShard.GetEventsCache(), s.logger, s.version, execution.GetRunId())
+	_, err := mutableState.AddWorkflowExecutionStartedEvent(
+		execution,
+		&historyservice.StartWorkflowExecutionRequest{
+			DomainUUID: s.domainID,
+			StartRequest: &workflowservice.StartWorkflowExecutionRequest{
+				WorkflowType:                        &commonproto.WorkflowType{Name: workflowType},
+				TaskList:                            &commonproto.TaskList{Name: taskListName},
+				ExecutionStartToCloseTimeoutSeconds: 2,
+				TaskStartToCloseTimeoutSeconds:      1,
+			},
+			CompletionCallbacks: []string{server.URL},
+		},
+	)
+	s.Nil(err)
+
+	di := addDecisionTaskScheduledEvent(mutableState)
+	event := addDecisionTaskStartedEvent(mutableState, di.ScheduleID, taskListName, uuid.New())
+	di.StartedID = event.GetEventId()
+	event = addDecisionTaskCompletedEvent(mutableState, di.ScheduleID, di.StartedID, nil, "some random identity")
+
+	taskID := int64(59)
+	event = addCompleteWorkflowEvent(mutableState, event.GetEventId(), nil)
+
+	transferTask := &persistenceblobs.TransferTaskInfo{
+		Version:    s.version,
+		DomainID:   s.GetDomainIDBytes(),
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      primitives.MustParseUUID(execution.GetRunId()),
+		TaskID:     taskID,
+		TaskList:   taskListName,
+		TaskType:   persistence.TransferTaskTypeCompletionCallback,
+		ScheduleID: 0,
+	}
+
+	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(&persistence.GetCurrentExecutionResponse{RunID: execution.GetRunId()}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *p.UpdateWorkflowExecutionRequest) bool {
+		completionCallbacks := request.UpdateWorkflowMutation.ExecutionInfo.CompletionCallbacks
+		return request.Mode == p.UpdateWorkflowModeUpdateCurrent &&
+			len(completionCallbacks) == 1 &&
+			completionCallbacks[0].State == p.CompletionCallbackStateSucceeded &&
+			completionCallbacks[0].Attempt == 1
+	})).Return(&p.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &p.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+	s.Equal(execution.GetWorkflowId(), payload.WorkflowID)
+	s.Equal(execution.GetRunId(), payload.RunID)
+	s.NotEmpty(payload.CompletionEvent)
+}
+
+func (s *transferQueueActiveTaskExecutorSuite) TestProcessCompletionCallback_HTTPHostNotAllowed() {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("completion callback to a host which is not allowed must not be delivered")
+	}))
+	defer server.Close()
+	s.transferQueueActiveTaskExecutor.config.CompletionCallbackAllowedHosts = dc.GetStringPropertyFnFilteredByDomain("*.example.com")
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+	workflowType := "some random workflow type"
+	taskListName := "some random task list"
+
+	mutableState := newMutableStateBuilderWithReplicationStateWithEventV2(s.mockShard, s.mockShard.GetEventsCache(), s.logger, s.version, execution.GetRunId())
+	_, err := mutableState.AddWorkflowExecutionStartedEvent(
+		execution,
+		&historyservice.StartWorkflowExecutionRequest{
+			DomainUUID: s.domainID,
+			StartRequest: &workflowservice.StartWorkflowExecutionRequest{
+				WorkflowType:                        &commonproto.WorkflowType{Name: workflowType},
+				TaskList:                            &commonproto.TaskList{Name: taskListName},
+				ExecutionStartToCloseTimeoutSeconds: 2,
+				TaskStartToCloseTimeoutSeconds:      1,
+			},
+			CompletionCallbacks: []string{server.URL},
+		},
+	)
+	s.Nil(err)
+
+	di := addDecisionTaskScheduledEvent(mutableState)
+	event := addDecisionTaskStartedEvent(mutableState, di.ScheduleID, taskListName, uuid.New())
+	di.StartedID = event.GetEventId()
+	event = addDecisionTaskCompletedEvent(mutableState, di.ScheduleID, di.StartedID, nil, "some random identity")
+
+	taskID := int64(59)
+	event = addCompleteWorkflowEvent(mutableState, event.GetEventId(), nil)
+
+	transferTask := &persistenceblobs.TransferTaskInfo{
+		Version:    s.version,
+		DomainID:   s.GetDomainIDBytes(),
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      primitives.MustParseUUID(execution.GetRunId()),
+		TaskID:     taskID,
+		TaskList:   taskListName,
+		TaskType:   persistence.TransferTaskTypeCompletionCallback,
+		ScheduleID: 0,
+	}
+
+	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(&persistence.GetCurrentExecutionResponse{RunID: execution.GetRunId()}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *p.UpdateWorkflowExecutionRequest) bool {
+		completionCallbacks := request.UpdateWorkflowMutation.ExecutionInfo.CompletionCallbacks
+		return request.Mode == p.UpdateWorkflowModeUpdateCurrent &&
+			len(completionCallbacks) == 1 &&
+			completionCallbacks[0].State == p.CompletionCallbackStateFailed &&
+			completionCallbacks[0].Attempt == 1
+	})).Return(&p.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &p.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+}
+
 func (s *transferQueueActiveTaskExecutorSuite) TestProcessCancelExecution_Success() {
 
 	execution := commonproto.WorkflowExecution{
@@ -1838,6 +2101,100 @@ func (s *transferQueueActiveTaskExecutorSuite) TestProcessUpsertWorkflowSearchAt
 	s.Nil(err)
 }
 
+func (s *transferQueueActiveTaskExecutorSuite) TestProcessHistoryStream() {
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+	workflowType := "some random workflow type"
+	taskListName := "some random task list"
+
+	mutableState := newMutableStateBuilderWithReplicationStateWithEventV2(s.mockShard, s.mockShard.GetEventsCache(), s.logger, s.version, execution.GetRunId())
+	_, err := mutableState.AddWorkflowExecutionStartedEvent(
+		execution,
+		&historyservice.StartWorkflowExecutionRequest{
+			DomainUUID: s.domainID,
+			StartRequest: &workflowservice.StartWorkflowExecutionRequest{
+				WorkflowType:                        &commonproto.WorkflowType{Name: workflowType},
+				TaskList:                            &commonproto.TaskList{Name: taskListName},
+				ExecutionStartToCloseTimeoutSeconds: 2,
+				TaskStartToCloseTimeoutSeconds:      1,
+			},
+		},
+	)
+	s.Nil(err)
+
+	di := addDecisionTaskScheduledEvent(mutableState)
+	event := addDecisionTaskStartedEvent(mutableState, di.ScheduleID, taskListName, uuid.New())
+	// the started event is already published
+	mutableState.GetExecutionInfo().HistoryStreamNextEventID = di.ScheduleID
+	branchToken, err := mutableState.GetCurrentBranchToken()
+	s.Nil(err)
+
+	taskID := int64(59)
+	transferTask := &persistenceblobs.TransferTaskInfo{
+		Version:    s.version,
+		DomainID:   s.GetDomainIDBytes(),
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      primitives.MustParseUUID(execution.GetRunId()),
+		TaskID:     taskID,
+		TaskList:   taskListName,
+		TaskType:   persistence.TransferTaskTypeHistoryStream,
+		ScheduleID: event.GetEventId(),
+	}
+
+	events := []*commonproto.HistoryEvent{
+		{EventId: di.ScheduleID, Version: s.version},
+		{EventId: event.GetEventId(), Version: s.version},
+	}
+	mockProducer := &mocks.KafkaProducer{}
+	defer mockProducer.AssertExpectations(s.T())
+	s.transferQueueActiveTaskExecutor.historyService.historyStreamPublisher = newHistoryStreamPublisher(
+		mocks.NewMockMessagingClient(mockProducer, nil),
+		s.logger,
+	)
+	s.transferQueueActiveTaskExecutor.config.HistoryStreamEnabled = dc.GetBoolPropertyFnFilteredByDomain(true)
+
+	persistenceMutableState := s.createPersistenceMutableState(mutableState, event.GetEventId(), event.GetVersion())
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+	s.mockHistoryV2Mgr.On("ReadHistoryBranch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  di.ScheduleID,
+		MaxEventID:  event.GetEventId() + 1,
+		PageSize:    s.transferQueueActiveTaskExecutor.config.HistoryStreamMaxBatchesPerMessage(s.domainName),
+		ShardID:     common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(&persistence.ReadHistoryBranchResponse{HistoryEvents: events}, nil).Once()
+	mockProducer.On("Publish", &historystream.Message{
+		DomainID:     s.domainID,
+		Domain:       s.domainName,
+		WorkflowID:   execution.GetWorkflowId(),
+		RunID:        execution.GetRunId(),
+		WorkflowType: workflowType,
+		TaskList:     taskListName,
+		FirstEventID: di.ScheduleID,
+		NextEventID:  event.GetEventId() + 1,
+		Events:       events,
+		Redaction:    historyStreamRedactionNone,
+	}).Return(nil).Once()
+
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+	// the progress is written with the next update of the workflow
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "UpdateWorkflowExecution", mock.Anything)
+
+	context, release, err := s.transferQueueActiveTaskExecutor.cache.getOrCreateWorkflowExecutionForBackground(s.domainID, execution)
+	s.Nil(err)
+	mutableState, err = context.loadWorkflowExecution()
+	s.Nil(err)
+	s.Equal(event.GetEventId()+1, mutableState.GetExecutionInfo().HistoryStreamNextEventID)
+	release(nil)
+
+	// the events are not published again by a task of the run which is processed later
+	err = s.transferQueueActiveTaskExecutor.execute(transferTask, true)
+	s.Nil(err)
+}
+
 func (s *transferQueueActiveTaskExecutorSuite) TestCopySearchAttributes() {
 	var input map[string][]byte
 	s.Nil(copySearchAttributes(input))
@@ -1856,6 +2213,7 @@ func (s *transferQueueActiveTaskExecutorSuite) TestCopySearchAttributes() {
 func (s *transferQueueActiveTaskExecutorSuite) createAddActivityTaskRequest(
 	task *persistenceblobs.TransferTaskInfo,
 	ai *persistence.ActivityInfo,
+	activityType string,
 ) *matchingservice.AddActivityTaskRequest {
 	return &matchingservice.AddActivityTaskRequest{
 		DomainUUID:       primitives.UUID(task.TargetDomainID).String(),
@@ -1867,6 +2225,7 @@ func (s *transferQueueActiveTaskExecutorSuite) createAddActivityTaskRequest(
 		TaskList:                      &commonproto.TaskList{Name: task.TaskList},
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: ai.ScheduleToStartTimeout,
+		ActivityType:                  activityType,
 	}
 }
 
@@ -29,6 +29,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/client/history"
 	"github.com/temporalio/temporal/client/matching"
@@ -51,6 +52,8 @@ type (
 		NotifyNewTask(clusterName string, transferTasks []persistence.Task)
 		LockTaskProcessing()
 		UnlockTaskPrrocessing()
+		DescribeSplitQueues() []*adminservice.SplitQueueInfo
+		ExecuteDLQTask(task queueTaskInfo) error
 	}
 
 	taskFilter func(task queueTaskInfo) (bool, error)
@@ -194,6 +197,7 @@ func (t *transferQueueProcessorImpl) NotifyNewTask(
 		// we will ignore the current time passed in, since the active processor process task immediately
 		if len(transferTasks) != 0 {
 			t.activeTaskProcessor.notifyNewTask()
+			t.activeTaskProcessor.splitter.notifyNewTasks(transferTasks)
 		}
 		return
 	}
@@ -266,6 +270,20 @@ func (t *transferQueueProcessorImpl) UnlockTaskPrrocessing() {
 	t.taskAllocator.unlock()
 }
 
+// DescribeSplitQueues returns the domains split out of the active transfer queue
+func (t *transferQueueProcessorImpl) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	return t.activeTaskProcessor.splitter.describe()
+}
+
+// ExecuteDLQTask executes a transfer task of the DLQ of the shard with the active task executor
+func (t *transferQueueProcessorImpl) ExecuteDLQTask(task queueTaskInfo) error {
+	shouldProcessTask, err := t.activeTaskProcessor.transferTaskFilter(task)
+	if err != nil {
+		return err
+	}
+	return t.activeTaskProcessor.taskExecutor.execute(task, shouldProcessTask)
+}
+
 func (t *transferQueueProcessorImpl) completeTransferLoop() {
 	timer := time.NewTimer(t.config.TransferProcessorCompleteTransferInterval())
 	defer timer.Stop()
@@ -298,7 +316,7 @@ func (t *transferQueueProcessorImpl) completeTransferLoop() {
 
 func (t *transferQueueProcessorImpl) completeTransfer() error {
 	lowerAckLevel := t.ackLevel
-	upperAckLevel := t.activeTaskProcessor.queueAckMgr.getQueueAckLevel()
+	upperAckLevel := t.activeTaskProcessor.splitter.getMinAckLevel(t.activeTaskProcessor.queueAckMgr.getQueueAckLevel())
 
 	if t.isGlobalDomainEnabled {
 		for _, standbyTaskProcessor := range t.standbyTaskProcessors {
@@ -154,6 +154,16 @@ func (t *transferQueueProcessorBase) getTransferTaskMetricsScope(
 			return metrics.TransferActiveTaskUpsertWorkflowSearchAttributesScope
 		}
 		return metrics.TransferStandbyTaskUpsertWorkflowSearchAttributesScope
+	case persistence.TransferTaskTypeCompletionCallback:
+		if isActive {
+			return metrics.TransferActiveTaskCompletionCallbackScope
+		}
+		return metrics.TransferStandbyTaskCompletionCallbackScope
+	case persistence.TransferTaskTypeHistoryStream:
+		if isActive {
+			return metrics.TransferActiveTaskHistoryStreamScope
+		}
+		return metrics.TransferStandbyTaskHistoryStreamScope
 	default:
 		if isActive {
 			return metrics.TransferActiveQueueProcessorScope
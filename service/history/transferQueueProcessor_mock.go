@@ -32,6 +32,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 
+	adminservice "github.com/temporalio/temporal/.gen/proto/adminservice"
 	persistence "github.com/temporalio/temporal/common/persistence"
 )
 
@@ -129,3 +130,31 @@ func (mr *MocktransferQueueProcessorMockRecorder) UnlockTaskPrrocessing() *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockTaskPrrocessing", reflect.TypeOf((*MocktransferQueueProcessor)(nil).UnlockTaskPrrocessing))
 }
+
+// DescribeSplitQueues mocks base method
+func (m *MocktransferQueueProcessor) DescribeSplitQueues() []*adminservice.SplitQueueInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeSplitQueues")
+	ret0, _ := ret[0].([]*adminservice.SplitQueueInfo)
+	return ret0
+}
+
+// DescribeSplitQueues indicates an expected call of DescribeSplitQueues
+func (mr *MocktransferQueueProcessorMockRecorder) DescribeSplitQueues() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSplitQueues", reflect.TypeOf((*MocktransferQueueProcessor)(nil).DescribeSplitQueues))
+}
+
+// ExecuteDLQTask mocks base method
+func (m *MocktransferQueueProcessor) ExecuteDLQTask(task queueTaskInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteDLQTask", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteDLQTask indicates an expected call of ExecuteDLQTask
+func (mr *MocktransferQueueProcessorMockRecorder) ExecuteDLQTask(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDLQTask", reflect.TypeOf((*MocktransferQueueProcessor)(nil).ExecuteDLQTask), task)
+}
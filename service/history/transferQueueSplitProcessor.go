@@ -0,0 +1,194 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	// transferQueueSplitProcessorImpl processes the transfer tasks of a single domain split out of the main
+	// transfer queue, the ack level is kept in memory and persisted by the main processor
+	transferQueueSplitProcessorImpl struct {
+		*transferQueueActiveProcessorImpl
+
+		domainID      string
+		mainProcessor *transferQueueActiveProcessorImpl
+
+		sync.Mutex
+		merging          bool
+		mergeLevel       int64
+		readToMergeLevel bool
+	}
+)
+
+var _ splitQueue = (*transferQueueSplitProcessorImpl)(nil)
+
+func newTransferQueueSplitProcessor(
+	shard ShardContext,
+	historyService *historyEngineImpl,
+	mainProcessor *transferQueueActiveProcessorImpl,
+	domainID string,
+	ackLevel int64,
+	taskAllocator taskAllocator,
+	logger log.Logger,
+) *transferQueueSplitProcessorImpl {
+
+	config := shard.GetConfig()
+	options := &QueueProcessorOptions{
+		BatchSize:                          config.TransferTaskBatchSize,
+		WorkerCount:                        config.QueueProcessorSplitQueueWorkerCount,
+		MaxPollRPS:                         config.QueueProcessorSplitQueueMaxPollRPS,
+		MaxPollInterval:                    config.TransferProcessorMaxPollInterval,
+		MaxPollIntervalJitterCoefficient:   config.TransferProcessorMaxPollIntervalJitterCoefficient,
+		UpdateAckInterval:                  config.TransferProcessorUpdateAckInterval,
+		UpdateAckIntervalJitterCoefficient: config.TransferProcessorUpdateAckIntervalJitterCoefficient,
+		MaxRetryCount:                      config.TransferTaskMaxRetryCount,
+		MetricScope:                        metrics.TransferSplitQueueProcessorScope,
+		TaskRetryPolicy:                    newSplitQueueTaskRetryPolicy(),
+	}
+	currentClusterName := shard.GetService().GetClusterMetadata().GetCurrentClusterName()
+	logger = logger.WithTags(
+		tag.ClusterName(currentClusterName),
+		tag.WorkflowDomainID(domainID),
+	)
+
+	splitProcessor := &transferQueueSplitProcessorImpl{
+		domainID:      domainID,
+		mainProcessor: mainProcessor,
+	}
+	transferTaskFilter := func(taskInfo queueTaskInfo) (bool, error) {
+		task, ok := taskInfo.(*persistenceblobs.TransferTaskInfo)
+		if !ok {
+			return false, errUnexpectedQueueTask
+		}
+		taskDomainID := primitives.UUID(task.DomainID).String()
+		if taskDomainID != domainID || !splitProcessor.owns(task) {
+			return false, nil
+		}
+		return taskAllocator.verifyActiveTask(taskDomainID, task)
+	}
+	updateTransferAckLevel := func(ackLevel int64) error {
+		// ack level of the split queue is persisted by the main processor
+		return nil
+	}
+	transferQueueShutdown := func() error {
+		mainProcessor.splitter.removeSplitQueue(domainID)
+		return nil
+	}
+
+	processor := &transferQueueActiveProcessorImpl{
+		currentClusterName: currentClusterName,
+		shard:              shard,
+		logger:             logger,
+		metricsClient:      historyService.metricsClient,
+		transferTaskFilter: transferTaskFilter,
+		taskExecutor: newTransferQueueActiveTaskExecutor(
+			shard,
+			historyService,
+			logger,
+			historyService.metricsClient,
+			config,
+		),
+		transferQueueProcessorBase: newTransferQueueProcessorBase(
+			shard,
+			options,
+			splitProcessor.getMaxReadLevel,
+			updateTransferAckLevel,
+			transferQueueShutdown,
+			logger,
+		),
+		onTaskFailure: func(taskInfo *taskInfo, err error) {
+			mainProcessor.splitter.reportSplitQueueFailure(domainID, err)
+		},
+	}
+
+	queueAckMgr := newQueueSplitAckMgr(shard, options, processor, ackLevel, splitProcessor.canFinishRead, logger)
+	queueProcessorBase := newQueueProcessorBase(currentClusterName, shard, options, processor, queueAckMgr, historyService.historyCache, logger)
+	processor.queueAckMgr = queueAckMgr
+	processor.queueProcessorBase = queueProcessorBase
+	splitProcessor.transferQueueActiveProcessorImpl = processor
+	return splitProcessor
+}
+
+func (t *transferQueueSplitProcessorImpl) owns(
+	task queueTaskInfo,
+) bool {
+
+	t.Lock()
+	defer t.Unlock()
+	return !t.merging || task.GetTaskID() <= t.mergeLevel
+}
+
+func (t *transferQueueSplitProcessorImpl) merge() {
+	// the read level of the main queue is taken under the lock of owns, so that every task the main queue
+	// skipped before is below the merge level, and every task it processes from now on is above
+	t.Lock()
+	t.merging = true
+	t.mergeLevel = t.mainProcessor.getQueueReadLevel()
+	t.Unlock()
+
+	t.notifyNewTask()
+}
+
+func (t *transferQueueSplitProcessorImpl) isIdle() bool {
+	return t.getQueueAckLevel() == t.getQueueReadLevel()
+}
+
+func (t *transferQueueSplitProcessorImpl) notifyNewTasks(
+	tasks []persistence.Task,
+) {
+
+	t.notifyNewTask()
+}
+
+func (t *transferQueueSplitProcessorImpl) getAckLevel() int64 {
+	return t.getQueueAckLevel()
+}
+
+func (t *transferQueueSplitProcessorImpl) getReadLevel() int64 {
+	return t.getQueueReadLevel()
+}
+
+func (t *transferQueueSplitProcessorImpl) getMaxReadLevel() int64 {
+	t.Lock()
+	defer t.Unlock()
+
+	// once merging, only the tasks up to the read level of the main processor are left to the split queue
+	t.readToMergeLevel = t.merging
+	if t.merging {
+		return t.mergeLevel
+	}
+	return t.shard.GetTransferMaxReadLevel()
+}
+
+func (t *transferQueueSplitProcessorImpl) canFinishRead() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.readToMergeLevel
+}
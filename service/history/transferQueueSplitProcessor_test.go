@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/mocks"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	transferQueueSplitProcessorSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller          *gomock.Controller
+		mockShard           *shardContextTest
+		mockDomainCache     *cache.MockDomainCache
+		mockClusterMetadata *cluster.MockMetadata
+		mockShardMgr        *mocks.ShardManager
+
+		mainProcessor  *transferQueueActiveProcessorImpl
+		splitProcessor *transferQueueSplitProcessorImpl
+	}
+)
+
+func TestTransferQueueSplitProcessorSuite(t *testing.T) {
+	s := new(transferQueueSplitProcessorSuite)
+	suite.Run(t, s)
+}
+
+func (s *transferQueueSplitProcessorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	config := NewDynamicConfigForTest()
+	config.QueueProcessorEnableSplit = dynamicconfig.GetBoolPropertyFn(true)
+	config.QueueProcessorSplitQueueMergeInterval = dynamicconfig.GetDurationPropertyFn(time.Minute)
+
+	s.controller = gomock.NewController(s.T())
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:                 0,
+				RangeID:                 1,
+				TransferAckLevel:        10,
+				ClusterTransferAckLevel: map[string]int64{cluster.TestCurrentClusterName: 10},
+			}},
+		config,
+	)
+	s.mockDomainCache = s.mockShard.resource.DomainCache
+	s.mockClusterMetadata = s.mockShard.resource.ClusterMetadata
+	s.mockShardMgr = s.mockShard.resource.ShardMgr
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	s.mockDomainCache.EXPECT().GetDomainByID(testDomainID).Return(testLocalDomainEntry, nil).AnyTimes()
+
+	logger := s.mockShard.GetLogger()
+	h := &historyEngineImpl{
+		currentClusterName: cluster.TestCurrentClusterName,
+		shard:              s.mockShard,
+		historyCache:       newHistoryCache(s.mockShard),
+		logger:             logger,
+		metricsClient:      s.mockShard.GetMetricsClient(),
+	}
+	s.mainProcessor = newTransferQueueActiveProcessor(
+		s.mockShard,
+		h,
+		s.mockShard.resource.VisibilityMgr,
+		s.mockShard.resource.MatchingClient,
+		s.mockShard.resource.HistoryClient,
+		newTaskAllocator(s.mockShard),
+		logger,
+	)
+	s.splitProcessor = newTransferQueueSplitProcessor(s.mockShard, h, s.mainProcessor, testDomainID, 5, newTaskAllocator(s.mockShard), logger)
+}
+
+func (s *transferQueueSplitProcessorSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *transferQueueSplitProcessorSuite) TestTaskFilter() {
+	filter := s.splitProcessor.getTaskFilter()
+
+	ok, err := filter(s.newTask("deadbeef-0123-4567-890a-bcdef0123451", 7))
+	s.NoError(err)
+	s.False(ok)
+
+	ok, err = filter(s.newTask(testDomainID, 7))
+	s.NoError(err)
+	s.True(ok)
+}
+
+func (s *transferQueueSplitProcessorSuite) TestMerge() {
+	s.Equal(int64(5), s.splitProcessor.getAckLevel())
+	s.True(s.splitProcessor.owns(s.newTask(testDomainID, 20)))
+	s.Equal(s.mockShard.GetTransferMaxReadLevel(), s.splitProcessor.getMaxReadLevel())
+	s.False(s.splitProcessor.canFinishRead())
+
+	// the split queue keeps the tasks up to the read level of the main queue
+	s.splitProcessor.merge()
+	s.True(s.splitProcessor.owns(s.newTask(testDomainID, 10)))
+	s.False(s.splitProcessor.owns(s.newTask(testDomainID, 11)))
+	s.Equal(int64(10), s.splitProcessor.getMaxReadLevel())
+	s.True(s.splitProcessor.canFinishRead())
+
+	// the main queue skips the tasks owned by the split queue
+	s.mainProcessor.splitter.splitQueues[testDomainID] = &splitQueueItem{queue: s.splitProcessor, merging: true}
+	s.True(s.mainProcessor.splitter.isOwnedBySplitQueue(s.newTask(testDomainID, 10)))
+	s.False(s.mainProcessor.splitter.isOwnedBySplitQueue(s.newTask(testDomainID, 11)))
+}
+
+func (s *transferQueueSplitProcessorSuite) TestUpdateAckLevel_PersistsSplitQueues() {
+	s.mainProcessor.splitter.splitQueues[testDomainID] = &splitQueueItem{
+		queue:           s.splitProcessor,
+		splitTime:       time.Now(),
+		lastFailureTime: time.Now(),
+	}
+	s.mockShardMgr.On("UpdateShard", mock.Anything).Return(nil).Once()
+
+	s.NoError(s.mainProcessor.updateAckLevel(12))
+	s.Equal(int64(5), s.mockShard.GetTransferClusterAckLevel(cluster.TestCurrentClusterName))
+	s.Equal(map[string]int64{testDomainID: 5}, s.mockShard.GetTransferSplitQueueAckLevels())
+}
+
+func (s *transferQueueSplitProcessorSuite) newTask(domainID string, taskID int64) *persistenceblobs.TransferTaskInfo {
+	return &persistenceblobs.TransferTaskInfo{
+		DomainID:            primitives.MustParseUUID(domainID),
+		TaskID:              taskID,
+		VisibilityTimestamp: types.TimestampNow(),
+	}
+}
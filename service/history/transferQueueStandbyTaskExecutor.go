@@ -107,6 +107,11 @@ func (t *transferQueueStandbyTaskExecutor) execute(
 		return nil
 	case persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
 		return t.processUpsertWorkflowSearchAttributes(transferTask)
+	case persistence.TransferTaskTypeCompletionCallback:
+		return t.processCompletionCallback(transferTask)
+	case persistence.TransferTaskTypeHistoryStream:
+		// history events are only published by the active cluster
+		return nil
 	default:
 		return errUnknownTransferTask
 	}
@@ -130,8 +135,13 @@ func (t *transferQueueStandbyTaskExecutor) processActivityTask(
 		}
 
 		if activityInfo.StartedID == common.EmptyEventID {
+			scheduledEvent, err := mutableState.GetActivityScheduledEvent(activityInfo.ScheduleID)
+			if err != nil {
+				return nil, err
+			}
 			return newPushActivityToMatchingInfo(
 				activityInfo.ScheduleToStartTimeout,
+				scheduledEvent.GetActivityTaskScheduledEventAttributes().GetActivityType().GetName(),
 			), nil
 		}
 
@@ -336,6 +346,56 @@ func (t *transferQueueStandbyTaskExecutor) processSignalExecution(
 	)
 }
 
+func (t *transferQueueStandbyTaskExecutor) processCompletionCallback(
+	transferTask *persistenceblobs.TransferTaskInfo,
+) error {
+
+	processTaskIfClosed := true
+	actionFn := func(context workflowExecutionContext, mutableState mutableState) (interface{}, error) {
+
+		if mutableState.IsWorkflowExecutionRunning() {
+			// this can happen if workflow is reset.
+			return nil, nil
+		}
+
+		executionInfo := mutableState.GetExecutionInfo()
+		if transferTask.ScheduleID >= int64(len(executionInfo.CompletionCallbacks)) {
+			return nil, nil
+		}
+		completionCallback := executionInfo.CompletionCallbacks[transferTask.ScheduleID]
+		if completionCallback.GetState() != persistence.CompletionCallbackStatePending {
+			return nil, nil
+		}
+
+		lastWriteVersion, err := mutableState.GetLastWriteVersion()
+		if err != nil {
+			return nil, err
+		}
+		ok, err := verifyTaskVersion(t.shard, t.logger, transferTask.DomainID, lastWriteVersion, transferTask.Version, transferTask)
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		// the delivery is not replicated, keep the task until the active cluster is expected to
+		// have delivered the callback, so that it is delivered again if the domain fails over before
+		return completionCallback, nil
+	}
+
+	return t.processTransfer(
+		processTaskIfClosed,
+		transferTask,
+		actionFn,
+		getStandbyPostActionFn(
+			transferTask,
+			t.getCurrentTime,
+			t.config.StandbyTaskMissingEventsResendDelay(),
+			t.config.StandbyTaskMissingEventsDiscardDelay(),
+			standbyTaskPostActionNoOp,
+			standbyTransferTaskPostActionTaskDiscarded,
+		),
+	)
+}
+
 func (t *transferQueueStandbyTaskExecutor) processStartChildExecution(
 	transferTask *persistenceblobs.TransferTaskInfo,
 ) error {
@@ -521,6 +581,7 @@ func (t *transferQueueStandbyTaskExecutor) pushActivity(
 	return t.transferQueueTaskExecutorBase.pushActivity(
 		task.(*persistenceblobs.TransferTaskInfo),
 		timeout,
+		pushActivityInfo.activityType,
 	)
 }
 
@@ -90,6 +90,7 @@ func (t *transferQueueTaskExecutorBase) getDomainIDAndWorkflowExecution(
 func (t *transferQueueTaskExecutorBase) pushActivity(
 	task *persistenceblobs.TransferTaskInfo,
 	activityScheduleToStartTimeout int32,
+	activityType string,
 ) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), transferActiveTaskDefaultTimeout)
@@ -109,6 +110,7 @@ func (t *transferQueueTaskExecutorBase) pushActivity(
 		TaskList:                      &commonproto.TaskList{Name: task.TaskList},
 		ScheduleId:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: activityScheduleToStartTimeout,
+		ActivityType:                  activityType,
 	})
 
 	return err
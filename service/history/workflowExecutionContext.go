@@ -25,6 +25,7 @@ package history
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	commonproto "go.temporal.io/temporal-proto/common"
@@ -36,6 +37,7 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/definition"
 	"github.com/temporalio/temporal/common/locks"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
@@ -151,6 +153,9 @@ type (
 		mutableState    mutableState
 		stats           *persistence.ExecutionStats
 		updateCondition int64
+		// estimated size of the mutable state, accessed atomically
+		// since the history cache reads it without holding the lock
+		mutableStateSize int64
 	}
 )
 
@@ -197,6 +202,7 @@ func (c *workflowExecutionContextImpl) clear() {
 	c.stats = &persistence.ExecutionStats{
 		HistorySize: 0,
 	}
+	atomic.StoreInt64(&c.mutableStateSize, 0)
 }
 
 func (c *workflowExecutionContextImpl) getDomainID() string {
@@ -223,6 +229,57 @@ func (c *workflowExecutionContextImpl) setHistorySize(size int64) {
 	c.stats.HistorySize = size
 }
 
+// getMutableStateSize returns the estimated size of the mutable state held by this context,
+// it is the size loaded from the database, re-estimated after every update
+func (c *workflowExecutionContextImpl) getMutableStateSize() int64 {
+	return atomic.LoadInt64(&c.mutableStateSize)
+}
+
+func (c *workflowExecutionContextImpl) updateMutableStateSize(
+	size int,
+) {
+
+	atomic.StoreInt64(&c.mutableStateSize, int64(size))
+}
+
+// estimateMutableStateSize estimates the size of the mutable state the same way the
+// persistence layer does on load, buffered events are left out since they are flushed
+// with the next decision
+func estimateMutableStateSize(
+	mutableState mutableState,
+) int {
+
+	if mutableState == nil {
+		return 0
+	}
+
+	executionInfo := mutableState.GetExecutionInfo()
+	size := len(executionInfo.WorkflowID)
+	size += len(executionInfo.TaskList)
+	size += len(executionInfo.WorkflowTypeName)
+	size += len(executionInfo.ParentWorkflowID)
+
+	for _, ai := range mutableState.GetPendingActivityInfos() {
+		size += len(ai.ActivityID)
+		size += ai.ScheduledEvent.Size()
+		size += ai.StartedEvent.Size()
+		size += len(ai.Details)
+	}
+	for _, ti := range mutableState.GetPendingTimerInfos() {
+		size += len(ti.TimerID)
+	}
+	for _, ci := range mutableState.GetPendingChildExecutionInfos() {
+		size += ci.InitiatedEvent.Size()
+		size += ci.StartedEvent.Size()
+	}
+	for _, si := range mutableState.GetPendingSignalExternalInfos() {
+		size += len(si.Name)
+		size += len(si.Input)
+		size += len(si.Control)
+	}
+	return size
+}
+
 func (c *workflowExecutionContextImpl) loadExecutionStats() (*persistence.ExecutionStats, error) {
 	_, err := c.loadWorkflowExecution()
 	if err != nil {
@@ -258,6 +315,9 @@ func (c *workflowExecutionContextImpl) loadWorkflowExecution() (mutableState, er
 
 		c.stats = response.State.ExecutionStats
 		c.updateCondition = response.State.ExecutionInfo.NextEventID
+		if response.MutableStateStats != nil {
+			atomic.StoreInt64(&c.mutableStateSize, int64(response.MutableStateStats.MutableStateSize))
+		}
 
 		// finally emit execution and session stats
 		emitWorkflowExecutionStats(
@@ -329,6 +389,7 @@ func (c *workflowExecutionContextImpl) createWorkflowExecution(
 	}
 
 	c.notifyTasks(
+		newWorkflow.ExecutionInfo,
 		newWorkflow.TransferTasks,
 		newWorkflow.ReplicationTasks,
 		newWorkflow.TimerTasks,
@@ -479,12 +540,14 @@ func (c *workflowExecutionContextImpl) conflictResolveWorkflowExecution(
 	))
 
 	c.notifyTasks(
+		resetWorkflow.ExecutionInfo,
 		resetWorkflow.TransferTasks,
 		resetWorkflow.ReplicationTasks,
 		resetWorkflow.TimerTasks,
 	)
 	if newWorkflow != nil {
 		c.notifyTasks(
+			newWorkflow.ExecutionInfo,
 			newWorkflow.TransferTasks,
 			newWorkflow.ReplicationTasks,
 			newWorkflow.TimerTasks,
@@ -492,6 +555,7 @@ func (c *workflowExecutionContextImpl) conflictResolveWorkflowExecution(
 	}
 	if currentWorkflow != nil {
 		c.notifyTasks(
+			currentWorkflow.ExecutionInfo,
 			currentWorkflow.TransferTasks,
 			currentWorkflow.ReplicationTasks,
 			currentWorkflow.TimerTasks,
@@ -677,6 +741,7 @@ func (c *workflowExecutionContextImpl) updateWorkflowExecutionWithNew(
 
 	// notify current workflow tasks
 	c.notifyTasks(
+		currentWorkflow.ExecutionInfo,
 		currentWorkflow.TransferTasks,
 		currentWorkflow.ReplicationTasks,
 		currentWorkflow.TimerTasks,
@@ -685,6 +750,7 @@ func (c *workflowExecutionContextImpl) updateWorkflowExecutionWithNew(
 	// notify new workflow tasks
 	if newWorkflow != nil {
 		c.notifyTasks(
+			newWorkflow.ExecutionInfo,
 			newWorkflow.TransferTasks,
 			newWorkflow.ReplicationTasks,
 			newWorkflow.TimerTasks,
@@ -704,6 +770,9 @@ func (c *workflowExecutionContextImpl) updateWorkflowExecutionWithNew(
 		domainName,
 		resp.MutableStateUpdateSessionStats,
 	)
+	// the update session stats only cover the upserted records, so the size is re-estimated
+	// from the whole mutable state, which shrinks as pending records complete
+	c.updateMutableStateSize(estimateMutableStateSize(c.mutableState))
 	// emit workflow completion stats if any
 	if currentWorkflow.ExecutionInfo.State == persistence.WorkflowStateCompleted {
 		if event, err := c.mutableState.GetCompletionEvent(); err == nil {
@@ -716,13 +785,17 @@ func (c *workflowExecutionContextImpl) updateWorkflowExecutionWithNew(
 }
 
 func (c *workflowExecutionContextImpl) notifyTasks(
+	executionInfo *persistence.WorkflowExecutionInfo,
 	transferTasks []persistence.Task,
 	replicationTasks []persistence.Task,
 	timerTasks []persistence.Task,
 ) {
 	c.engine.NotifyNewTransferTasks(transferTasks)
 	c.engine.NotifyNewReplicationTasks(replicationTasks)
-	c.engine.NotifyNewTimerTasks(timerTasks)
+	c.engine.NotifyNewTimerTasks(
+		definition.NewWorkflowIdentifier(executionInfo.DomainID, executionInfo.WorkflowID, executionInfo.RunID),
+		timerTasks,
+	)
 }
 
 func (c *workflowExecutionContextImpl) mergeContinueAsNewReplicationTasks(
@@ -1115,6 +1188,7 @@ func (c *workflowExecutionContextImpl) resetWorkflowExecution(
 
 	// notify reset workflow tasks
 	c.notifyTasks(
+		resetWorkflow.ExecutionInfo,
 		resetWorkflow.TransferTasks,
 		resetWorkflow.ReplicationTasks,
 		resetWorkflow.TimerTasks,
@@ -1123,6 +1197,7 @@ func (c *workflowExecutionContextImpl) resetWorkflowExecution(
 	// notify current workflow tasks
 	if resetWFReq.CurrentWorkflowMutation != nil {
 		c.notifyTasks(
+			resetWFReq.CurrentWorkflowMutation.ExecutionInfo,
 			resetWFReq.CurrentWorkflowMutation.TransferTasks,
 			resetWFReq.CurrentWorkflowMutation.ReplicationTasks,
 			resetWFReq.CurrentWorkflowMutation.TimerTasks,
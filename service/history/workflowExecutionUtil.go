@@ -21,11 +21,14 @@
 package history
 
 import (
+	"time"
+
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/serviceerror"
 
 	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/persistence"
 )
 
 type workflowContext interface {
@@ -216,3 +219,56 @@ func terminateWorkflow(
 	)
 	return err
 }
+
+// createClosedWorkflow persists a closed workflow whose mutable state was rebuilt from a copy of its
+// history, e.g. when it is rehydrated from the archive or imported from another cluster. The workflow
+// is created as closed in a single write, so a failure never leaves behind a running workflow
+// without tasks.
+func createClosedWorkflow(
+	context workflowExecutionContext,
+	executionMgr persistence.ExecutionManager,
+	snapshot *persistence.WorkflowSnapshot,
+	historySize int64,
+	now time.Time,
+) error {
+
+	createMode, err := getClosedWorkflowCreateMode(
+		executionMgr,
+		snapshot.ExecutionInfo.DomainID,
+		snapshot.ExecutionInfo.WorkflowID,
+	)
+	if err != nil {
+		return err
+	}
+	return context.createWorkflowExecution(
+		snapshot,
+		historySize,
+		now,
+		createMode,
+		"",
+		common.EmptyVersion,
+	)
+}
+
+func getClosedWorkflowCreateMode(
+	executionMgr persistence.ExecutionManager,
+	domainID string,
+	workflowID string,
+) (persistence.CreateWorkflowMode, error) {
+
+	_, err := executionMgr.GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+	})
+	switch err.(type) {
+	case nil:
+		// the current record belongs to another run of the workflow and must be left alone
+		return persistence.CreateWorkflowModeZombie, nil
+	case *serviceerror.NotFound:
+		// no other run of the workflow exists, the closed workflow becomes the current one,
+		// this also allows to reset it
+		return persistence.CreateWorkflowModeBrandNew, nil
+	default:
+		return 0, err
+	}
+}
@@ -0,0 +1,529 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pborman/uuid"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/definition"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	// workflowImporter creates workflow executions from the history exported from another cluster,
+	// so that workflows can be moved between clusters which do not replicate to each other
+	workflowImporter interface {
+		importWorkflow(
+			ctx context.Context,
+			domainID string,
+			request *adminservice.ImportWorkflowExecutionRequest,
+		) ([]byte, error)
+	}
+
+	workflowImporterImpl struct {
+		shard             ShardContext
+		historyV2Mgr      persistence.HistoryManager
+		executionMgr      persistence.ExecutionManager
+		payloadSerializer persistence.PayloadSerializer
+		historyCache      *historyCache
+		newStateRebuilder nDCStateRebuilderProvider
+		logger            log.Logger
+	}
+
+	// importPageToken tracks the last batch appended to the history branch of an import, the branch itself
+	// is looked up from the history tree of the imported run and the token is verified against it
+	importPageToken struct {
+		LastBatchFirstEventID int64
+		LastEventID           int64
+		LastEventVersion      int64
+	}
+
+	// exportedMutableState is the part of the exported mutable state which is not recorded in the history,
+	// everything else is rebuilt from the imported history
+	exportedMutableState struct {
+		ExecutionInfo      *exportedExecutionInfo
+		ActivityInfos      map[int64]*exportedActivityInfo
+		SignalRequestedIDs map[string]struct{}
+	}
+
+	exportedExecutionInfo struct {
+		NextEventID int64
+	}
+
+	exportedActivityInfo struct {
+		Attempt                  int32
+		Details                  []byte
+		LastHeartBeatUpdatedTime time.Time
+		LastFailureReason        string
+		LastFailureDetails       []byte
+		LastWorkerIdentity       string
+	}
+)
+
+var _ workflowImporter = (*workflowImporterImpl)(nil)
+
+var (
+	errImportWorkflowExists         = serviceerror.NewInvalidArgument("Workflow execution already exists.")
+	errImportInvalidRunID           = serviceerror.NewInvalidArgument("Invalid RunId.")
+	errImportInvalidPageToken       = serviceerror.NewInvalidArgument("Invalid NextPageToken.")
+	errImportHistoryEmpty           = serviceerror.NewInvalidArgument("Imported workflow history is empty.")
+	errImportHistoryGap             = serviceerror.NewInvalidArgument("Imported workflow history does not continue the previous page.")
+	errImportHistoryIncomplete      = serviceerror.NewInvalidArgument("Imported workflow history does not match the exported mutable state.")
+	errImportCurrentWorkflowRunning = serviceerror.NewInvalidArgument("Another run of the workflow is still running.")
+)
+
+func newWorkflowImporter(
+	shard ShardContext,
+	historyCache *historyCache,
+	logger log.Logger,
+) *workflowImporterImpl {
+	return &workflowImporterImpl{
+		shard:             shard,
+		historyV2Mgr:      shard.GetHistoryManager(),
+		executionMgr:      shard.GetExecutionManager(),
+		payloadSerializer: shard.GetService().GetPayloadSerializer(),
+		historyCache:      historyCache,
+		newStateRebuilder: func() nDCStateRebuilder {
+			return newNDCStateRebuilder(shard, logger)
+		},
+		logger: logger,
+	}
+}
+
+// importWorkflow appends a page of the exported history to a new history branch and returns the token
+// for the next page. The last page rebuilds the mutable state from the history: activity, decision and
+// user timers as well as pending transfer tasks are regenerated from the rebuilt mutable state, so an
+// imported running workflow continues where it left off in the source cluster. Closed workflows are
+// persisted as closed, with their visibility record and retention deletion scheduled by the regular
+// close tasks. The branch of an import which is abandoned before the last page is deleted by the
+// history scavenger, or by the first page of a restarted import of the run.
+func (i *workflowImporterImpl) importWorkflow(
+	ctx context.Context,
+	domainID string,
+	request *adminservice.ImportWorkflowExecutionRequest,
+) (_ []byte, retError error) {
+
+	execution := commonproto.WorkflowExecution{
+		WorkflowId: request.GetExecution().GetWorkflowId(),
+		RunId:      request.GetExecution().GetRunId(),
+	}
+	if uuid.Parse(execution.GetRunId()) == nil {
+		return nil, errImportInvalidRunID
+	}
+	token, err := deserializeImportPageToken(request.GetNextPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	context, release, err := i.historyCache.getOrCreateWorkflowExecution(ctx, domainID, execution)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { release(retError) }()
+
+	switch _, err := context.loadWorkflowExecution(); err.(type) {
+	case nil:
+		return nil, errImportWorkflowExists
+	case *serviceerror.NotFound:
+		// workflow does not exist in this cluster, expected
+	default:
+		return nil, err
+	}
+
+	branchToken, token, err := i.importHistory(domainID, execution, token, request.GetHistoryBatches())
+	if err != nil {
+		return nil, err
+	}
+	if !request.GetLastPage() {
+		return serializeImportPageToken(token)
+	}
+
+	if err := i.createWorkflow(
+		ctx,
+		context,
+		domainID,
+		execution,
+		branchToken,
+		token,
+		request.GetMutableState(),
+	); err != nil {
+		i.deleteHistoryBranch(branchToken)
+		return nil, err
+	}
+
+	i.logger.Info("Imported workflow execution.",
+		tag.WorkflowDomainID(domainID),
+		tag.WorkflowID(execution.GetWorkflowId()),
+		tag.WorkflowRunID(execution.GetRunId()),
+	)
+	return nil, nil
+}
+
+// importHistory appends the history batches to the branch of the imported run and returns the branch with the
+// token of the next page, the first page creates the branch
+func (i *workflowImporterImpl) importHistory(
+	domainID string,
+	execution commonproto.WorkflowExecution,
+	token *importPageToken,
+	historyBatches []*commonproto.DataBlob,
+) (_ []byte, _ *importPageToken, retError error) {
+
+	isNewBranch := token == nil
+	var branchToken []byte
+	var err error
+	if isNewBranch {
+		if err := i.deleteAbandonedBranches(execution); err != nil {
+			return nil, nil, err
+		}
+		branchToken, err = persistence.NewHistoryBranchToken(primitives.MustParseUUID(execution.GetRunId()))
+		if err != nil {
+			return nil, nil, err
+		}
+		token = &importPageToken{
+			LastBatchFirstEventID: common.EmptyEventID,
+			LastEventID:           common.EmptyEventID,
+			LastEventVersion:      common.EmptyVersion,
+		}
+	} else {
+		branchToken, err = i.getImportedBranch(execution, token)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	next := *token
+	defer func() {
+		// a new branch cannot be continued without the token of its first page, so it is not left for the scavenger
+		if retError != nil && isNewBranch && next.LastEventID != common.EmptyEventID {
+			i.deleteHistoryBranch(branchToken)
+		}
+	}()
+	for _, blob := range historyBatches {
+		events, err := i.payloadSerializer.DeserializeBatchEvents(newImportedDataBlob(blob))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(events) == 0 {
+			continue
+		}
+		if next.LastEventID != common.EmptyEventID && events[0].GetEventId() != next.LastEventID+1 {
+			return nil, nil, errImportHistoryGap
+		}
+		if _, err := i.shard.AppendHistoryV2Events(&persistence.AppendHistoryNodesRequest{
+			IsNewBranch: next.LastEventID == common.EmptyEventID,
+			Info:        persistence.BuildHistoryGarbageCleanupInfo(domainID, execution.GetWorkflowId(), execution.GetRunId()),
+			BranchToken: branchToken,
+			Events:      events,
+			// TransactionID is set by shard context
+		}, domainID, execution); err != nil {
+			return nil, nil, err
+		}
+		lastEvent := events[len(events)-1]
+		next.LastBatchFirstEventID = events[0].GetEventId()
+		next.LastEventID = lastEvent.GetEventId()
+		next.LastEventVersion = lastEvent.GetVersion()
+	}
+
+	if next.LastEventID == common.EmptyEventID {
+		return nil, nil, errImportHistoryEmpty
+	}
+	return branchToken, &next, nil
+}
+
+// getImportedBranch returns the branch of the import continued by the page token, the page token comes from
+// the caller so it must match the last batch appended to the branch
+func (i *workflowImporterImpl) getImportedBranch(
+	execution commonproto.WorkflowExecution,
+	token *importPageToken,
+) ([]byte, error) {
+
+	treeID := primitives.MustParseUUID(execution.GetRunId())
+	tree, err := i.historyV2Mgr.GetHistoryTree(&persistence.GetHistoryTreeRequest{
+		TreeID:  treeID,
+		ShardID: common.IntPtr(i.shard.GetShardID()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the history tree of a run being imported only has the branch created by the first page
+	if len(tree.Branches) != 1 {
+		return nil, errImportInvalidPageToken
+	}
+	branchToken, err := persistence.NewHistoryBranchTokenByBranchID(treeID, tree.Branches[0].GetBranchID())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := i.historyV2Mgr.ReadHistoryBranchByBatch(&persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  token.LastBatchFirstEventID,
+		MaxEventID:  common.EndEventID,
+		PageSize:    2,
+		ShardID:     common.IntPtr(i.shard.GetShardID()),
+	})
+	switch err.(type) {
+	case nil:
+	case *serviceerror.NotFound:
+		return nil, errImportInvalidPageToken
+	default:
+		return nil, err
+	}
+	if len(resp.History) != 1 || len(resp.History[0].GetEvents()) == 0 {
+		return nil, errImportInvalidPageToken
+	}
+	events := resp.History[0].GetEvents()
+	lastEvent := events[len(events)-1]
+	if events[0].GetEventId() != token.LastBatchFirstEventID ||
+		lastEvent.GetEventId() != token.LastEventID ||
+		lastEvent.GetVersion() != token.LastEventVersion {
+		return nil, errImportInvalidPageToken
+	}
+	return branchToken, nil
+}
+
+// deleteAbandonedBranches deletes the branches left by an abandoned import of the run, so that an import
+// can be restarted without waiting for the history scavenger. The run does not exist in this cluster,
+// so its history tree only holds branches of imports.
+func (i *workflowImporterImpl) deleteAbandonedBranches(
+	execution commonproto.WorkflowExecution,
+) error {
+
+	treeID := primitives.MustParseUUID(execution.GetRunId())
+	tree, err := i.historyV2Mgr.GetHistoryTree(&persistence.GetHistoryTreeRequest{
+		TreeID:  treeID,
+		ShardID: common.IntPtr(i.shard.GetShardID()),
+	})
+	if err != nil {
+		return err
+	}
+	for _, branch := range tree.Branches {
+		branchToken, err := persistence.NewHistoryBranchTokenByBranchID(treeID, branch.GetBranchID())
+		if err != nil {
+			return err
+		}
+		if err := i.historyV2Mgr.DeleteHistoryBranch(&persistence.DeleteHistoryBranchRequest{
+			BranchToken: branchToken,
+			ShardID:     common.IntPtr(i.shard.GetShardID()),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *workflowImporterImpl) createWorkflow(
+	ctx context.Context,
+	context workflowExecutionContext,
+	domainID string,
+	execution commonproto.WorkflowExecution,
+	branchToken []byte,
+	token *importPageToken,
+	mutableStateJSON string,
+) error {
+
+	now := i.shard.GetTimeSource().Now()
+	workflowIdentifier := definition.NewWorkflowIdentifier(
+		domainID,
+		execution.GetWorkflowId(),
+		execution.GetRunId(),
+	)
+	rebuiltMutableState, rebuiltHistorySize, err := i.newStateRebuilder().rebuild(
+		ctx,
+		now,
+		workflowIdentifier,
+		branchToken,
+		token.LastEventID,
+		token.LastEventVersion,
+		workflowIdentifier,
+		branchToken,
+		uuid.New(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// the state rebuilder stamps the rebuild time as start time, keep the original one instead
+	startEvent, err := rebuiltMutableState.GetStartEvent()
+	if err != nil {
+		return err
+	}
+	rebuiltMutableState.GetExecutionInfo().StartTimestamp = time.Unix(0, startEvent.GetTimestamp())
+
+	if err := applyExportedMutableState(rebuiltMutableState, mutableStateJSON); err != nil {
+		return err
+	}
+
+	snapshot, _, err := rebuiltMutableState.CloseTransactionAsSnapshot(now, transactionPolicyPassive)
+	if err != nil {
+		return err
+	}
+	if !rebuiltMutableState.IsWorkflowExecutionRunning() {
+		return createClosedWorkflow(
+			context,
+			i.executionMgr,
+			snapshot,
+			rebuiltHistorySize,
+			now,
+		)
+	}
+
+	createMode, prevRunID, prevLastWriteVersion, err := i.getRunningWorkflowCreateMode(
+		domainID,
+		execution.GetWorkflowId(),
+	)
+	if err != nil {
+		return err
+	}
+	return context.createWorkflowExecution(
+		snapshot,
+		rebuiltHistorySize,
+		now,
+		createMode,
+		prevRunID,
+		prevLastWriteVersion,
+	)
+}
+
+// applyExportedMutableState restores the state of the source cluster which the history does not record,
+// like the attempts and heartbeat details of pending activities and the request IDs of received signals
+func applyExportedMutableState(
+	mutableState mutableState,
+	mutableStateJSON string,
+) error {
+
+	if mutableStateJSON == "" {
+		return nil
+	}
+	exported := &exportedMutableState{}
+	if err := json.Unmarshal([]byte(mutableStateJSON), exported); err != nil {
+		return serviceerror.NewInvalidArgument(err.Error())
+	}
+
+	if exported.ExecutionInfo != nil && exported.ExecutionInfo.NextEventID != mutableState.GetNextEventID() {
+		return errImportHistoryIncomplete
+	}
+	for scheduleID, exportedInfo := range exported.ActivityInfos {
+		ai, ok := mutableState.GetActivityInfo(scheduleID)
+		if !ok {
+			return errImportHistoryIncomplete
+		}
+		ai.Attempt = exportedInfo.Attempt
+		ai.Details = exportedInfo.Details
+		ai.LastHeartBeatUpdatedTime = exportedInfo.LastHeartBeatUpdatedTime
+		ai.LastFailureReason = exportedInfo.LastFailureReason
+		ai.LastFailureDetails = exportedInfo.LastFailureDetails
+		ai.LastWorkerIdentity = exportedInfo.LastWorkerIdentity
+		if err := mutableState.UpdateActivity(ai); err != nil {
+			return err
+		}
+	}
+	for requestID := range exported.SignalRequestedIDs {
+		mutableState.AddSignalRequested(requestID)
+	}
+	return nil
+}
+
+// newImportedDataBlob does not use persistence.NewDataBlobFromProto,
+// which panics on encodings not supported for history events
+func newImportedDataBlob(
+	blob *commonproto.DataBlob,
+) *serialization.DataBlob {
+
+	encoding := common.EncodingTypeThriftRW
+	if blob.GetEncodingType() == enums.EncodingTypeJSON {
+		encoding = common.EncodingTypeJSON
+	}
+	return &serialization.DataBlob{
+		Encoding: encoding,
+		Data:     blob.GetData(),
+	}
+}
+
+func (i *workflowImporterImpl) getRunningWorkflowCreateMode(
+	domainID string,
+	workflowID string,
+) (persistence.CreateWorkflowMode, string, int64, error) {
+
+	resp, err := i.executionMgr.GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+	})
+	switch err.(type) {
+	case nil:
+		if resp.State != persistence.WorkflowStateCompleted {
+			return 0, "", 0, errImportCurrentWorkflowRunning
+		}
+		// an imported running workflow takes over from the closed current run
+		return persistence.CreateWorkflowModeWorkflowIDReuse, resp.RunID, resp.LastWriteVersion, nil
+	case *serviceerror.NotFound:
+		return persistence.CreateWorkflowModeBrandNew, "", common.EmptyVersion, nil
+	default:
+		return 0, "", 0, err
+	}
+}
+
+func (i *workflowImporterImpl) deleteHistoryBranch(
+	branchToken []byte,
+) {
+
+	if err := i.historyV2Mgr.DeleteHistoryBranch(&persistence.DeleteHistoryBranchRequest{
+		BranchToken: branchToken,
+		ShardID:     common.IntPtr(i.shard.GetShardID()),
+	}); err != nil {
+		i.logger.Warn("Failed to delete history branch of imported workflow.", tag.Error(err))
+	}
+}
+
+func serializeImportPageToken(
+	token *importPageToken,
+) ([]byte, error) {
+
+	return json.Marshal(token)
+}
+
+func deserializeImportPageToken(
+	data []byte,
+) (*importPageToken, error) {
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+	token := &importPageToken{}
+	if err := json.Unmarshal(data, token); err != nil ||
+		token.LastBatchFirstEventID < common.FirstEventID ||
+		token.LastEventID < token.LastBatchFirstEventID {
+		return nil, errImportInvalidPageToken
+	}
+	return token, nil
+}
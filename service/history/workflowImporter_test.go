@@ -0,0 +1,330 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/mocks"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	workflowImporterSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller       *gomock.Controller
+		mockShard        *shardContextTest
+		mockHistoryV2Mgr *mocks.HistoryV2Manager
+		mockExecutionMgr *mocks.ExecutionManager
+
+		execution commonproto.WorkflowExecution
+
+		workflowImporter *workflowImporterImpl
+	}
+)
+
+func TestWorkflowImporterSuite(t *testing.T) {
+	s := new(workflowImporterSuite)
+	suite.Run(t, s)
+}
+
+func (s *workflowImporterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:          0,
+				RangeID:          1,
+				TransferAckLevel: 0,
+			}},
+		NewDynamicConfigForTest(),
+	)
+	s.mockHistoryV2Mgr = s.mockShard.resource.HistoryMgr
+	s.mockExecutionMgr = s.mockShard.resource.ExecutionMgr
+
+	s.execution = commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+
+	s.workflowImporter = newWorkflowImporter(
+		s.mockShard,
+		newHistoryCache(s.mockShard),
+		loggerimpl.NewDevelopmentForTest(s.Suite),
+	)
+}
+
+func (s *workflowImporterSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+}
+
+func (s *workflowImporterSuite) TestImportWorkflow_InvalidRunID() {
+	_, err := s.workflowImporter.importWorkflow(context.Background(), testDomainID, &adminservice.ImportWorkflowExecutionRequest{
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: s.execution.GetWorkflowId(),
+			RunId:      "some random run ID",
+		},
+	})
+	s.Equal(errImportInvalidRunID, err)
+}
+
+func (s *workflowImporterSuite) TestImportWorkflow_InvalidPageToken() {
+	_, err := s.workflowImporter.importWorkflow(context.Background(), testDomainID, &adminservice.ImportWorkflowExecutionRequest{
+		Execution:     &s.execution,
+		NextPageToken: []byte("some random page token"),
+	})
+	s.Equal(errImportInvalidPageToken, err)
+}
+
+func (s *workflowImporterSuite) TestImportHistory_Pages() {
+	serializer := persistence.NewPayloadSerializer()
+	firstBatch, err := serializer.SerializeBatchEvents([]*commonproto.HistoryEvent{{EventId: 1}, {EventId: 2}}, common.EncodingTypeThriftRW)
+	s.NoError(err)
+	secondBatch, err := serializer.SerializeBatchEvents([]*commonproto.HistoryEvent{{EventId: 3, Version: 5}}, common.EncodingTypeThriftRW)
+	s.NoError(err)
+	treeID := primitives.MustParseUUID(s.execution.GetRunId())
+	abandonedBranchID := primitives.MustParseUUID(uuid.New())
+	abandonedBranchToken, err := persistence.NewHistoryBranchTokenByBranchID(treeID, abandonedBranchID)
+	s.NoError(err)
+	// the first page deletes the branch of an abandoned import of the run
+	s.mockHistoryV2Mgr.On("GetHistoryTree", &persistence.GetHistoryTreeRequest{
+		TreeID:  treeID,
+		ShardID: common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(&persistence.GetHistoryTreeResponse{
+		Branches: []*persistenceblobs.HistoryBranch{{TreeID: treeID, BranchID: abandonedBranchID}},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("DeleteHistoryBranch", &persistence.DeleteHistoryBranchRequest{
+		BranchToken: abandonedBranchToken,
+		ShardID:     common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.MatchedBy(func(request *persistence.AppendHistoryNodesRequest) bool {
+		return request.IsNewBranch && request.Events[0].GetEventId() == 1
+	})).Return(&persistence.AppendHistoryNodesResponse{Size: 20}, nil).Once()
+
+	branchToken, token, err := s.workflowImporter.importHistory(
+		testDomainID,
+		s.execution,
+		nil,
+		[]*commonproto.DataBlob{firstBatch.ToProto()},
+	)
+	s.NoError(err)
+	s.NotEmpty(branchToken)
+	s.Equal(int64(1), token.LastBatchFirstEventID)
+	s.Equal(int64(2), token.LastEventID)
+
+	data, err := serializeImportPageToken(token)
+	s.NoError(err)
+	token, err = deserializeImportPageToken(data)
+	s.NoError(err)
+
+	branch, err := serialization.HistoryBranchFromBlob(branchToken, common.EncodingTypeProto3.String())
+	s.NoError(err)
+	s.mockHistoryV2Mgr.On("GetHistoryTree", &persistence.GetHistoryTreeRequest{
+		TreeID:  treeID,
+		ShardID: common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(&persistence.GetHistoryTreeResponse{
+		Branches: []*persistenceblobs.HistoryBranch{branch},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  1,
+		MaxEventID:  common.EndEventID,
+		PageSize:    2,
+		ShardID:     common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(&persistence.ReadHistoryBranchByBatchResponse{
+		History: []*commonproto.History{{Events: []*commonproto.HistoryEvent{{EventId: 1}, {EventId: 2}}}},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.MatchedBy(func(request *persistence.AppendHistoryNodesRequest) bool {
+		return !request.IsNewBranch && request.Events[0].GetEventId() == 3
+	})).Return(&persistence.AppendHistoryNodesResponse{Size: 10}, nil).Once()
+
+	nextBranchToken, token, err := s.workflowImporter.importHistory(
+		testDomainID,
+		s.execution,
+		token,
+		[]*commonproto.DataBlob{secondBatch.ToProto()},
+	)
+	s.NoError(err)
+	s.Equal(branchToken, nextBranchToken)
+	s.Equal(int64(3), token.LastBatchFirstEventID)
+	s.Equal(int64(3), token.LastEventID)
+	s.Equal(int64(5), token.LastEventVersion)
+}
+
+func (s *workflowImporterSuite) TestImportHistory_PageTokenNotLastBatch() {
+	treeID := primitives.MustParseUUID(s.execution.GetRunId())
+	branchID := primitives.MustParseUUID(uuid.New())
+	branchToken, err := persistence.NewHistoryBranchTokenByBranchID(treeID, branchID)
+	s.NoError(err)
+	s.mockHistoryV2Mgr.On("GetHistoryTree", mock.Anything).Return(&persistence.GetHistoryTreeResponse{
+		Branches: []*persistenceblobs.HistoryBranch{{TreeID: treeID, BranchID: branchID}},
+	}, nil).Once()
+	// the branch holds events past the ones of the page token
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  1,
+		MaxEventID:  common.EndEventID,
+		PageSize:    2,
+		ShardID:     common.IntPtr(s.mockShard.GetShardID()),
+	}).Return(&persistence.ReadHistoryBranchByBatchResponse{
+		History: []*commonproto.History{
+			{Events: []*commonproto.HistoryEvent{{EventId: 1}, {EventId: 2}}},
+			{Events: []*commonproto.HistoryEvent{{EventId: 3}}},
+		},
+	}, nil).Once()
+
+	_, _, err = s.workflowImporter.importHistory(
+		testDomainID,
+		s.execution,
+		&importPageToken{LastBatchFirstEventID: 1, LastEventID: 2},
+		nil,
+	)
+	s.Equal(errImportInvalidPageToken, err)
+}
+
+func (s *workflowImporterSuite) TestImportHistory_PageTokenOfAnotherRun() {
+	// the history tree of the run has no branch, the page token was issued for another run
+	s.mockHistoryV2Mgr.On("GetHistoryTree", mock.Anything).Return(&persistence.GetHistoryTreeResponse{}, nil).Once()
+
+	_, _, err := s.workflowImporter.importHistory(
+		testDomainID,
+		s.execution,
+		&importPageToken{LastBatchFirstEventID: 1, LastEventID: 2},
+		nil,
+	)
+	s.Equal(errImportInvalidPageToken, err)
+}
+
+func (s *workflowImporterSuite) TestImportHistory_Gap() {
+	serializer := persistence.NewPayloadSerializer()
+	batch, err := serializer.SerializeBatchEvents([]*commonproto.HistoryEvent{{EventId: 4}}, common.EncodingTypeThriftRW)
+	s.NoError(err)
+
+	treeID := primitives.MustParseUUID(s.execution.GetRunId())
+	branchID := primitives.MustParseUUID(uuid.New())
+	s.mockHistoryV2Mgr.On("GetHistoryTree", mock.Anything).Return(&persistence.GetHistoryTreeResponse{
+		Branches: []*persistenceblobs.HistoryBranch{{TreeID: treeID, BranchID: branchID}},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("ReadHistoryBranchByBatch", mock.Anything).Return(&persistence.ReadHistoryBranchByBatchResponse{
+		History: []*commonproto.History{{Events: []*commonproto.HistoryEvent{{EventId: 1}, {EventId: 2}}}},
+	}, nil).Once()
+
+	_, _, err = s.workflowImporter.importHistory(
+		testDomainID,
+		s.execution,
+		&importPageToken{LastBatchFirstEventID: 1, LastEventID: 2},
+		[]*commonproto.DataBlob{batch.ToProto()},
+	)
+	s.Equal(errImportHistoryGap, err)
+}
+
+func (s *workflowImporterSuite) TestImportHistory_Empty() {
+	s.mockHistoryV2Mgr.On("GetHistoryTree", mock.Anything).Return(&persistence.GetHistoryTreeResponse{}, nil).Once()
+
+	_, _, err := s.workflowImporter.importHistory(testDomainID, s.execution, nil, nil)
+	s.Equal(errImportHistoryEmpty, err)
+}
+
+func (s *workflowImporterSuite) TestApplyExportedMutableState() {
+	activityInfo := &persistence.ActivityInfo{ScheduleID: 5}
+	mutableState := NewMockmutableState(s.controller)
+	mutableState.EXPECT().GetNextEventID().Return(int64(7)).AnyTimes()
+	mutableState.EXPECT().GetActivityInfo(int64(5)).Return(activityInfo, true).Times(1)
+	mutableState.EXPECT().UpdateActivity(activityInfo).Return(nil).Times(1)
+	mutableState.EXPECT().AddSignalRequested("some random request ID").Times(1)
+
+	err := applyExportedMutableState(mutableState, `{
+		"ExecutionInfo": {"NextEventID": 7},
+		"ActivityInfos": {"5": {"ScheduleID": 5, "Attempt": 3, "Details": "ZGV0YWlscw==", "LastFailureReason": "some random reason"}},
+		"SignalRequestedIDs": {"some random request ID": {}}
+	}`)
+	s.NoError(err)
+	s.Equal(int32(3), activityInfo.Attempt)
+	s.Equal([]byte("details"), activityInfo.Details)
+	s.Equal("some random reason", activityInfo.LastFailureReason)
+
+	err = applyExportedMutableState(mutableState, `{"ExecutionInfo": {"NextEventID": 8}}`)
+	s.Equal(errImportHistoryIncomplete, err)
+}
+
+func (s *workflowImporterSuite) TestGetRunningWorkflowCreateMode_NoCurrentWorkflow() {
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(nil, serviceerror.NewNotFound("")).Once()
+
+	createMode, prevRunID, _, err := s.workflowImporter.getRunningWorkflowCreateMode(testDomainID, s.execution.GetWorkflowId())
+	s.NoError(err)
+	s.Equal(persistence.CreateWorkflowModeBrandNew, createMode)
+	s.Empty(prevRunID)
+}
+
+func (s *workflowImporterSuite) TestGetRunningWorkflowCreateMode_CurrentWorkflowClosed() {
+	currentRunID := uuid.New()
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(&persistence.GetCurrentExecutionResponse{
+		RunID:            currentRunID,
+		State:            persistence.WorkflowStateCompleted,
+		LastWriteVersion: 10,
+	}, nil).Once()
+
+	createMode, prevRunID, prevLastWriteVersion, err := s.workflowImporter.getRunningWorkflowCreateMode(testDomainID, s.execution.GetWorkflowId())
+	s.NoError(err)
+	s.Equal(persistence.CreateWorkflowModeWorkflowIDReuse, createMode)
+	s.Equal(currentRunID, prevRunID)
+	s.Equal(int64(10), prevLastWriteVersion)
+}
+
+func (s *workflowImporterSuite) TestGetRunningWorkflowCreateMode_CurrentWorkflowRunning() {
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(&persistence.GetCurrentExecutionResponse{
+		RunID: uuid.New(),
+		State: persistence.WorkflowStateRunning,
+	}, nil).Once()
+
+	_, _, _, err := s.workflowImporter.getRunningWorkflowCreateMode(testDomainID, s.execution.GetWorkflowId())
+	s.Equal(errImportCurrentWorkflowRunning, err)
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/pborman/uuid"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/archiver/provider"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/definition"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+const (
+	rehydrateArchivedHistoryPageSize = 100
+)
+
+type (
+	// workflowRehydrator restores archived workflow executions, so that they can be
+	// described, queried and reset after they have passed the domain retention
+	workflowRehydrator interface {
+		rehydrate(
+			ctx context.Context,
+			domainID string,
+			execution commonproto.WorkflowExecution,
+		) (time.Time, error)
+	}
+
+	workflowRehydratorImpl struct {
+		shard             ShardContext
+		domainCache       cache.DomainCache
+		historyV2Mgr      persistence.HistoryManager
+		executionMgr      persistence.ExecutionManager
+		archiverProvider  provider.ArchiverProvider
+		historyCache      *historyCache
+		newStateRebuilder nDCStateRebuilderProvider
+		config            *Config
+		logger            log.Logger
+	}
+)
+
+var _ workflowRehydrator = (*workflowRehydratorImpl)(nil)
+
+var (
+	errRehydrateWorkflowExists       = serviceerror.NewInvalidArgument("Workflow execution still exists, it does not need to be rehydrated.")
+	errRehydrateInvalidRunID         = serviceerror.NewInvalidArgument("Invalid RunId.")
+	errRehydrateHistoryNotClosed     = serviceerror.NewInternal("Archived workflow history does not end with a workflow close event.")
+	errRehydrateArchivedHistoryEmpty = serviceerror.NewNotFound("Archived workflow history is empty.")
+)
+
+func newWorkflowRehydrator(
+	shard ShardContext,
+	historyCache *historyCache,
+	logger log.Logger,
+) *workflowRehydratorImpl {
+	return &workflowRehydratorImpl{
+		shard:            shard,
+		domainCache:      shard.GetDomainCache(),
+		historyV2Mgr:     shard.GetHistoryManager(),
+		executionMgr:     shard.GetExecutionManager(),
+		archiverProvider: shard.GetService().GetArchiverProvider(),
+		historyCache:     historyCache,
+		newStateRebuilder: func() nDCStateRebuilder {
+			return newNDCStateRebuilder(shard, logger)
+		},
+		config: shard.GetConfig(),
+		logger: logger,
+	}
+}
+
+// rehydrate copies the archived history of a closed workflow into a new history branch, rebuilds
+// the mutable state from it and persists the workflow as closed. The only task generated for the
+// rehydrated workflow is the deletion once the rehydrated workflow TTL has passed, the returned
+// time is the time of that deletion. The deletion goes through the regular retention path, which
+// archives the unchanged history once more if archival is still enabled for the domain.
+func (r *workflowRehydratorImpl) rehydrate(
+	ctx context.Context,
+	domainID string,
+	execution commonproto.WorkflowExecution,
+) (_ time.Time, retError error) {
+
+	if uuid.Parse(execution.GetRunId()) == nil {
+		return time.Time{}, errRehydrateInvalidRunID
+	}
+	domainEntry, err := r.domainCache.GetDomainByID(domainID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	context, release, err := r.historyCache.getOrCreateWorkflowExecution(ctx, domainID, execution)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() { release(retError) }()
+
+	switch _, err := context.loadWorkflowExecution(); err.(type) {
+	case nil:
+		return time.Time{}, errRehydrateWorkflowExists
+	case *serviceerror.NotFound:
+		// workflow was deleted, expected
+	default:
+		return time.Time{}, err
+	}
+
+	branchToken, lastEvent, err := r.restoreHistory(ctx, domainEntry, execution)
+	if err != nil {
+		return time.Time{}, err
+	}
+	workflowCreated := false
+	defer func() {
+		if !workflowCreated {
+			r.deleteHistoryBranch(branchToken)
+		}
+	}()
+
+	now := r.shard.GetTimeSource().Now()
+	workflowIdentifier := definition.NewWorkflowIdentifier(
+		domainID,
+		execution.GetWorkflowId(),
+		execution.GetRunId(),
+	)
+	rebuiltMutableState, rebuiltHistorySize, err := r.newStateRebuilder().rebuild(
+		ctx,
+		now,
+		workflowIdentifier,
+		branchToken,
+		lastEvent.GetEventId(),
+		lastEvent.GetVersion(),
+		workflowIdentifier,
+		branchToken,
+		uuid.New(),
+	)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if rebuiltMutableState.IsWorkflowExecutionRunning() {
+		return time.Time{}, errRehydrateHistoryNotClosed
+	}
+
+	// the state rebuilder stamps the rebuild time as start time, keep the original one instead
+	startEvent, err := rebuiltMutableState.GetStartEvent()
+	if err != nil {
+		return time.Time{}, err
+	}
+	rebuiltMutableState.GetExecutionInfo().StartTimestamp = time.Unix(0, startEvent.GetTimestamp())
+
+	lastWriteVersion, err := rebuiltMutableState.GetLastWriteVersion()
+	if err != nil {
+		return time.Time{}, err
+	}
+	expirationTime := now.Add(r.config.RehydratedWorkflowTTL(domainEntry.GetInfo().Name))
+
+	snapshot, _, err := rebuiltMutableState.CloseTransactionAsSnapshot(now, transactionPolicyPassive)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// tasks regenerated by the state rebuilder would redo the side effects of closing the workflow,
+	// the only task a rehydrated workflow needs is the deletion once the TTL has passed
+	snapshot.TransferTasks = nil
+	snapshot.ReplicationTasks = nil
+	snapshot.TimerTasks = []persistence.Task{&persistence.DeleteHistoryEventTask{
+		VisibilityTimestamp: expirationTime,
+		Version:             lastWriteVersion,
+	}}
+	if err := createClosedWorkflow(
+		context,
+		r.executionMgr,
+		snapshot,
+		rebuiltHistorySize,
+		now,
+	); err != nil {
+		return time.Time{}, err
+	}
+	workflowCreated = true
+
+	r.logger.Info("Rehydrated archived workflow execution.",
+		tag.WorkflowDomainID(domainID),
+		tag.WorkflowID(execution.GetWorkflowId()),
+		tag.WorkflowRunID(execution.GetRunId()),
+	)
+	return expirationTime, nil
+}
+
+func (r *workflowRehydratorImpl) restoreHistory(
+	ctx context.Context,
+	domainEntry *cache.DomainCacheEntry,
+	execution commonproto.WorkflowExecution,
+) (_ []byte, _ *commonproto.HistoryEvent, retError error) {
+
+	URI, err := archiver.NewURI(domainEntry.GetConfig().HistoryArchivalURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	historyArchiver, err := r.archiverProvider.GetHistoryArchiver(URI.Scheme(), common.HistoryServiceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	domainID := domainEntry.GetInfo().ID
+	branchToken, err := persistence.NewHistoryBranchToken(primitives.MustParseUUID(execution.GetRunId()))
+	if err != nil {
+		return nil, nil, err
+	}
+	var lastEvent *commonproto.HistoryEvent
+	defer func() {
+		if retError != nil && lastEvent != nil {
+			r.deleteHistoryBranch(branchToken)
+		}
+	}()
+
+	request := &archiver.GetHistoryRequest{
+		DomainID:   domainID,
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      execution.GetRunId(),
+		PageSize:   rehydrateArchivedHistoryPageSize,
+	}
+	for {
+		resp, err := historyArchiver.Get(ctx, URI, request)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, batch := range resp.HistoryBatches {
+			if len(batch.Events) == 0 {
+				continue
+			}
+			if _, err := r.shard.AppendHistoryV2Events(&persistence.AppendHistoryNodesRequest{
+				IsNewBranch: lastEvent == nil,
+				Info:        persistence.BuildHistoryGarbageCleanupInfo(domainID, execution.GetWorkflowId(), execution.GetRunId()),
+				BranchToken: branchToken,
+				Events:      batch.Events,
+				// TransactionID is set by shard context
+			}, domainID, execution); err != nil {
+				return nil, nil, err
+			}
+			lastEvent = batch.Events[len(batch.Events)-1]
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+
+	if lastEvent == nil {
+		return nil, nil, errRehydrateArchivedHistoryEmpty
+	}
+	return branchToken, lastEvent, nil
+}
+
+func (r *workflowRehydratorImpl) deleteHistoryBranch(
+	branchToken []byte,
+) {
+
+	if err := r.historyV2Mgr.DeleteHistoryBranch(&persistence.DeleteHistoryBranchRequest{
+		BranchToken: branchToken,
+		ShardID:     common.IntPtr(r.shard.GetShardID()),
+	}); err != nil {
+		r.logger.Warn("Failed to delete history branch of rehydrated workflow.", tag.Error(err))
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/archiver"
+	"github.com/temporalio/temporal/common/archiver/provider"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/mocks"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	workflowRehydratorSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller           *gomock.Controller
+		mockShard            *shardContextTest
+		mockDomainCache      *cache.MockDomainCache
+		mockHistoryV2Mgr     *mocks.HistoryV2Manager
+		mockExecutionMgr     *mocks.ExecutionManager
+		mockArchiverProvider *provider.MockArchiverProvider
+		mockHistoryArchiver  *archiver.HistoryArchiverMock
+
+		domainEntry *cache.DomainCacheEntry
+		execution   commonproto.WorkflowExecution
+
+		workflowRehydrator *workflowRehydratorImpl
+	}
+)
+
+func TestWorkflowRehydratorSuite(t *testing.T) {
+	s := new(workflowRehydratorSuite)
+	suite.Run(t, s)
+}
+
+func (s *workflowRehydratorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockShard = newTestShardContext(
+		s.controller,
+		&persistence.ShardInfoWithFailover{
+			ShardInfo: &persistenceblobs.ShardInfo{
+				ShardID:          0,
+				RangeID:          1,
+				TransferAckLevel: 0,
+			}},
+		NewDynamicConfigForTest(),
+	)
+	s.mockDomainCache = s.mockShard.resource.DomainCache
+	s.mockHistoryV2Mgr = s.mockShard.resource.HistoryMgr
+	s.mockExecutionMgr = s.mockShard.resource.ExecutionMgr
+	s.mockArchiverProvider = s.mockShard.resource.ArchiverProvider
+	s.mockHistoryArchiver = &archiver.HistoryArchiverMock{}
+
+	s.domainEntry = cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{ID: testDomainID, Name: testDomainName},
+		&persistence.DomainConfig{Retention: 1, HistoryArchivalURI: "test:///history/archival"},
+		cluster.TestCurrentClusterName,
+		nil,
+	)
+	s.mockDomainCache.EXPECT().GetDomainByID(testDomainID).Return(s.domainEntry, nil).AnyTimes()
+	s.execution = commonproto.WorkflowExecution{
+		WorkflowId: "some random workflow ID",
+		RunId:      uuid.New(),
+	}
+
+	s.workflowRehydrator = newWorkflowRehydrator(
+		s.mockShard,
+		newHistoryCache(s.mockShard),
+		loggerimpl.NewDevelopmentForTest(s.Suite),
+	)
+}
+
+func (s *workflowRehydratorSuite) TearDownTest() {
+	s.controller.Finish()
+	s.mockShard.Finish(s.T())
+	s.mockHistoryArchiver.AssertExpectations(s.T())
+}
+
+func (s *workflowRehydratorSuite) TestRehydrate_InvalidRunID() {
+	_, err := s.workflowRehydrator.rehydrate(context.Background(), testDomainID, commonproto.WorkflowExecution{
+		WorkflowId: s.execution.GetWorkflowId(),
+		RunId:      "some random run ID",
+	})
+	s.Equal(errRehydrateInvalidRunID, err)
+}
+
+func (s *workflowRehydratorSuite) TestRestoreHistory() {
+	firstBatch := &commonproto.History{Events: []*commonproto.HistoryEvent{{EventId: 1}, {EventId: 2}}}
+	secondBatch := &commonproto.History{Events: []*commonproto.HistoryEvent{{EventId: 3}}}
+	s.mockArchiverProvider.On("GetHistoryArchiver", "test", common.HistoryServiceName).Return(s.mockHistoryArchiver, nil).Once()
+	s.mockHistoryArchiver.On("Get", mock.Anything, mock.Anything, mock.MatchedBy(func(request *archiver.GetHistoryRequest) bool {
+		return request.RunID == s.execution.GetRunId() && request.NextPageToken == nil
+	})).Return(&archiver.GetHistoryResponse{
+		HistoryBatches: []*commonproto.History{firstBatch},
+		NextPageToken:  []byte("some random page token"),
+	}, nil).Once()
+	s.mockHistoryArchiver.On("Get", mock.Anything, mock.Anything, mock.MatchedBy(func(request *archiver.GetHistoryRequest) bool {
+		return request.RunID == s.execution.GetRunId() && request.NextPageToken != nil
+	})).Return(&archiver.GetHistoryResponse{
+		HistoryBatches: []*commonproto.History{secondBatch},
+	}, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.MatchedBy(func(request *persistence.AppendHistoryNodesRequest) bool {
+		return request.IsNewBranch && request.Events[0].GetEventId() == 1
+	})).Return(&persistence.AppendHistoryNodesResponse{Size: 20}, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.MatchedBy(func(request *persistence.AppendHistoryNodesRequest) bool {
+		return !request.IsNewBranch && request.Events[0].GetEventId() == 3
+	})).Return(&persistence.AppendHistoryNodesResponse{Size: 10}, nil).Once()
+
+	branchToken, lastEvent, err := s.workflowRehydrator.restoreHistory(context.Background(), s.domainEntry, s.execution)
+	s.NoError(err)
+	s.NotEmpty(branchToken)
+	s.Equal(int64(3), lastEvent.GetEventId())
+}
+
+func (s *workflowRehydratorSuite) TestRestoreHistory_ArchiverFailed() {
+	s.mockArchiverProvider.On("GetHistoryArchiver", "test", common.HistoryServiceName).Return(s.mockHistoryArchiver, nil).Once()
+	s.mockHistoryArchiver.On("Get", mock.Anything, mock.Anything, mock.Anything).Return(nil, serviceerror.NewNotFound("")).Once()
+
+	_, _, err := s.workflowRehydrator.restoreHistory(context.Background(), s.domainEntry, s.execution)
+	s.IsType(&serviceerror.NotFound{}, err)
+}
+
+func (s *workflowRehydratorSuite) TestGetClosedWorkflowCreateMode_CurrentWorkflowExists() {
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(&persistence.GetCurrentExecutionResponse{RunID: uuid.New()}, nil).Once()
+
+	createMode, err := getClosedWorkflowCreateMode(s.mockExecutionMgr, testDomainID, s.execution.GetWorkflowId())
+	s.NoError(err)
+	s.Equal(persistence.CreateWorkflowModeZombie, createMode)
+}
+
+func (s *workflowRehydratorSuite) TestGetClosedWorkflowCreateMode_NoCurrentWorkflow() {
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(nil, serviceerror.NewNotFound("")).Once()
+
+	createMode, err := getClosedWorkflowCreateMode(s.mockExecutionMgr, testDomainID, s.execution.GetWorkflowId())
+	s.NoError(err)
+	s.Equal(persistence.CreateWorkflowModeBrandNew, createMode)
+}
+
+func (s *workflowRehydratorSuite) TestCreateClosedWorkflow_SingleWrite() {
+	s.mockExecutionMgr.On("GetCurrentExecution", &persistence.GetCurrentExecutionRequest{
+		DomainID:   testDomainID,
+		WorkflowID: s.execution.GetWorkflowId(),
+	}).Return(nil, serviceerror.NewNotFound("")).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		executionInfo := request.NewWorkflowSnapshot.ExecutionInfo
+		return request.Mode == persistence.CreateWorkflowModeBrandNew &&
+			executionInfo.State == persistence.WorkflowStateCompleted &&
+			executionInfo.CloseStatus == persistence.WorkflowCloseStatusCompleted
+	})).Return(&persistence.CreateWorkflowExecutionResponse{}, nil).Once()
+
+	mockEngine := NewMockEngine(s.controller)
+	mockEngine.EXPECT().NotifyNewTransferTasks(gomock.Any()).Times(1)
+	mockEngine.EXPECT().NotifyNewReplicationTasks(gomock.Any()).Times(1)
+	mockEngine.EXPECT().NotifyNewTimerTasks(gomock.Any(), gomock.Any()).Times(1)
+	s.mockShard.SetEngine(mockEngine)
+
+	context := newWorkflowExecutionContext(testDomainID, s.execution, s.mockShard, s.mockExecutionMgr, s.mockShard.GetLogger())
+	err := createClosedWorkflow(context, s.mockExecutionMgr, &persistence.WorkflowSnapshot{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{
+			DomainID:    testDomainID,
+			WorkflowID:  s.execution.GetWorkflowId(),
+			RunID:       s.execution.GetRunId(),
+			State:       persistence.WorkflowStateCompleted,
+			CloseStatus: persistence.WorkflowCloseStatusCompleted,
+		},
+	}, 100, s.mockShard.GetTimeSource().Now())
+	s.NoError(err)
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "UpdateWorkflowExecution", mock.Anything)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+const (
+	// activityReservationTTL is how long a concurrency slot is held for a dispatched activity
+	// task until the slot is acquired again with the timeout of the started activity
+	activityReservationTTL = 30 * time.Second
+)
+
+type (
+	// activityConcurrencyLimiter tracks the running activities of a task list and withholds dispatch
+	// of an activity type once its concurrency limit is reached. The limiter of the root partition
+	// tracks the activities dispatched by all partitions of the task list, the other partitions
+	// acquire and release their slots from it. A slot is acquired when a task is dispatched to a
+	// poller, acquired again with the start to close timeout once the activity is started and
+	// released when history reports the activity is no longer pending. The slots are persisted
+	// with the ack level of the task list, so they survive the task list moving to another host,
+	// and each slot has a lease so slots whose release was lost are eventually released. The
+	// slots acquired since the last ack level update are lost if the host crashes, so the limit
+	// can be exceeded by the activities started shortly before the crash.
+	activityConcurrencyLimiter struct {
+		sync.Mutex
+		limitFn    func(activityType string) int
+		timeSource clock.TimeSource
+		running    map[string]map[runningActivityKey]time.Time
+		releaseC   chan struct{}
+	}
+
+	runningActivityKey struct {
+		runID      string
+		scheduleID int64
+	}
+)
+
+func newActivityConcurrencyLimiter(
+	limitFn func(activityType string) int,
+	timeSource clock.TimeSource,
+) *activityConcurrencyLimiter {
+	return &activityConcurrencyLimiter{
+		limitFn:    limitFn,
+		timeSource: timeSource,
+		running:    make(map[string]map[runningActivityKey]time.Time),
+		releaseC:   make(chan struct{}, 1),
+	}
+}
+
+// load restores the running activities persisted with the task list
+func (l *activityConcurrencyLimiter) load(activities []*persistenceblobs.RunningActivityInfo) {
+	l.Lock()
+	defer l.Unlock()
+	l.running = make(map[string]map[runningActivityKey]time.Time)
+	for _, activity := range activities {
+		expiry, err := types.TimestampFromProto(activity.GetExpiry())
+		if err != nil {
+			continue
+		}
+		key := runningActivityKey{
+			runID:      primitives.UUIDString(activity.GetRunID()),
+			scheduleID: activity.GetScheduleID(),
+		}
+		l.activitiesLocked(activity.GetActivityType())[key] = expiry
+	}
+}
+
+// acquire holds a concurrency slot for the activity until it is released or the lease expires,
+// returns false if the limit of the activity type is reached. Acquiring the slot of an activity
+// which already holds one always succeeds and extends its lease. Activity types without a
+// limit always succeed.
+func (l *activityConcurrencyLimiter) acquire(
+	activityType string,
+	key runningActivityKey,
+	lease time.Duration,
+) bool {
+	limit := l.limitFn(activityType)
+	if limit <= 0 {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	activities := l.activitiesLocked(activityType)
+	expiry := l.timeSource.Now().Add(lease)
+	previous, ok := activities[key]
+	if !ok && len(activities) >= limit {
+		return false
+	}
+	if !ok || expiry.After(previous) {
+		activities[key] = expiry
+	}
+	return true
+}
+
+// release releases the slot of the activity
+func (l *activityConcurrencyLimiter) release(activityType string, key runningActivityKey) {
+	l.Lock()
+	defer l.Unlock()
+	activities, ok := l.running[activityType]
+	if !ok {
+		return
+	}
+	if _, ok := activities[key]; !ok {
+		return
+	}
+
+	delete(activities, key)
+	l.notifyReleaseLocked()
+}
+
+// releaseNotifyC is signaled whenever a slot is released
+func (l *activityConcurrencyLimiter) releaseNotifyC() <-chan struct{} {
+	return l.releaseC
+}
+
+// describe returns the current utilization of all activity types tracked by the task list
+func (l *activityConcurrencyLimiter) describe() []*matchingservice.ActivityConcurrencyInfo {
+	l.Lock()
+	defer l.Unlock()
+	result := make([]*matchingservice.ActivityConcurrencyInfo, 0, len(l.running))
+	for activityType := range l.running {
+		activities := l.activitiesLocked(activityType)
+		limit := l.limitFn(activityType)
+		if len(activities) == 0 && limit <= 0 {
+			continue
+		}
+		result = append(result, &matchingservice.ActivityConcurrencyInfo{
+			ActivityType: activityType,
+			Limit:        int32(limit),
+			Running:      int32(len(activities)),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ActivityType < result[j].ActivityType
+	})
+	return result
+}
+
+// activitiesLocked returns the running activities of the given type after dropping the expired ones
+func (l *activityConcurrencyLimiter) activitiesLocked(activityType string) map[runningActivityKey]time.Time {
+	activities, ok := l.running[activityType]
+	if !ok {
+		activities = make(map[runningActivityKey]time.Time)
+		l.running[activityType] = activities
+		return activities
+	}
+
+	now := l.timeSource.Now()
+	for key, expiry := range activities {
+		if !now.Before(expiry) {
+			delete(activities, key)
+			l.notifyReleaseLocked()
+		}
+	}
+	return activities
+}
+
+// snapshot returns the running activities to persist with the task list
+func (l *activityConcurrencyLimiter) snapshot() []*persistenceblobs.RunningActivityInfo {
+	l.Lock()
+	defer l.Unlock()
+	var activities []*persistenceblobs.RunningActivityInfo
+	for activityType := range l.running {
+		for key, expiry := range l.activitiesLocked(activityType) {
+			expiryProto, err := types.TimestampProto(expiry)
+			if err != nil {
+				continue
+			}
+			activities = append(activities, &persistenceblobs.RunningActivityInfo{
+				ActivityType: activityType,
+				RunID:        primitives.MustParseUUID(key.runID),
+				ScheduleID:   key.scheduleID,
+				Expiry:       expiryProto,
+			})
+		}
+	}
+	return activities
+}
+
+func (l *activityConcurrencyLimiter) notifyReleaseLocked() {
+	select {
+	case l.releaseC <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/common/clock"
+)
+
+type ActivityConcurrencyLimiterTestSuite struct {
+	suite.Suite
+	timeSource *clock.EventTimeSource
+	limits     map[string]int
+	limiter    *activityConcurrencyLimiter
+	runID      string
+}
+
+func TestActivityConcurrencyLimiterSuite(t *testing.T) {
+	suite.Run(t, new(ActivityConcurrencyLimiterTestSuite))
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) SetupTest() {
+	s.timeSource = clock.NewEventTimeSource().Update(time.Now())
+	s.limits = map[string]int{"limited": 2}
+	s.limiter = s.newLimiter()
+	s.runID = uuid.New()
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) newLimiter() *activityConcurrencyLimiter {
+	return newActivityConcurrencyLimiter(
+		func(activityType string) int {
+			return s.limits[activityType]
+		},
+		s.timeSource,
+	)
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) key(scheduleID int64) runningActivityKey {
+	return runningActivityKey{runID: s.runID, scheduleID: scheduleID}
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) acquire(activityType string, scheduleID int64, lease time.Duration) bool {
+	return s.limiter.acquire(activityType, s.key(scheduleID), lease)
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestAcquire() {
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	s.True(s.acquire("limited", 2, activityReservationTTL))
+	s.False(s.acquire("limited", 3, activityReservationTTL))
+	// acquiring a slot already held by the activity always succeeds
+	s.True(s.acquire("limited", 1, time.Minute))
+	s.Len(s.limiter.snapshot(), 2)
+
+	for i := int64(0); i < 10; i++ {
+		s.True(s.acquire("unlimited", i, activityReservationTTL))
+	}
+	s.Len(s.limiter.snapshot(), 2)
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestRelease() {
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	s.True(s.acquire("limited", 2, activityReservationTTL))
+
+	s.limiter.release("limited", s.key(1))
+	s.Len(s.limiter.releaseNotifyC(), 1)
+	s.Len(s.limiter.snapshot(), 1)
+	s.True(s.acquire("limited", 3, activityReservationTTL))
+
+	// releasing a slot which is not held is a no-op
+	s.limiter.release("limited", s.key(1))
+	s.limiter.release("other", s.key(1))
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestExpiry() {
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	// the lease of the started activity is extended
+	s.True(s.acquire("limited", 1, 2*activityReservationTTL))
+	// a shorter lease does not shorten the slot of a started activity
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	s.True(s.acquire("limited", 2, activityReservationTTL))
+	s.False(s.acquire("limited", 3, activityReservationTTL))
+
+	// the reservation expires first
+	s.timeSource.Update(s.timeSource.Now().Add(activityReservationTTL))
+	s.True(s.acquire("limited", 3, activityReservationTTL))
+	s.False(s.acquire("limited", 4, activityReservationTTL))
+
+	s.timeSource.Update(s.timeSource.Now().Add(activityReservationTTL))
+	s.True(s.acquire("limited", 4, activityReservationTTL))
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestPersistence() {
+	s.True(s.acquire("limited", 1, time.Minute))
+	s.True(s.acquire("limited", 2, time.Minute))
+
+	// the slots are restored by the limiter of the next owner of the task list
+	limiter := s.newLimiter()
+	limiter.load(s.limiter.snapshot())
+	s.False(limiter.acquire("limited", s.key(3), time.Minute))
+	limiter.release("limited", s.key(1))
+	s.True(limiter.acquire("limited", s.key(3), time.Minute))
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestSnapshot_ExpiredSlots() {
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	s.True(s.acquire("limited", 2, time.Hour))
+	s.Len(s.limiter.snapshot(), 2)
+
+	// the expired slots are not persisted
+	s.timeSource.Update(s.timeSource.Now().Add(activityReservationTTL))
+	snapshot := s.limiter.snapshot()
+	s.Len(snapshot, 1)
+	s.Equal(int64(2), snapshot[0].GetScheduleID())
+}
+
+func (s *ActivityConcurrencyLimiterTestSuite) TestDescribe() {
+	s.Empty(s.limiter.describe())
+
+	s.True(s.acquire("limited", 1, activityReservationTTL))
+	s.True(s.acquire("unlimited", 2, activityReservationTTL))
+	s.limits["other"] = 5
+	s.True(s.acquire("other", 3, activityReservationTTL))
+
+	result := s.limiter.describe()
+	s.Len(result, 2)
+	s.Equal("limited", result[0].ActivityType)
+	s.Equal(int32(2), result[0].Limit)
+	s.Equal(int32(1), result[0].Running)
+	s.Equal("other", result[1].ActivityType)
+	s.Equal(int32(5), result[1].Limit)
+	s.Equal(int32(1), result[1].Running)
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 )
 
@@ -57,6 +58,9 @@ type (
 		OutstandingTaskAppendsThreshold dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		MaxTaskBatchSize                dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 
+		// ActivityConcurrencyLimits is the max number of running activities per activity type, across all partitions
+		ActivityConcurrencyLimits dynamicconfig.MapPropertyFn
+
 		ThrottledLogRPS dynamicconfig.IntPropertyFn
 	}
 
@@ -84,6 +88,9 @@ type (
 		MaxTaskBatchSize                func() int
 		NumWritePartitions              func() int
 		NumReadPartitions               func() int
+		// ActivityConcurrencyLimit is the max number of running activities of the given type
+		// dispatched by all partitions of the task list, 0 means no limit
+		ActivityConcurrencyLimit func(activityType string) int
 	}
 )
 
@@ -110,6 +117,7 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		ForwarderMaxOutstandingTasks:    dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxOutstandingTasks, 1),
 		ForwarderMaxRatePerSecond:       dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxRatePerSecond, 10),
 		ForwarderMaxChildrenPerNode:     dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxChildrenPerNode, 20),
+		ActivityConcurrencyLimits:       dc.GetMapProperty(dynamicconfig.MatchingActivityConcurrencyLimits, nil),
 	}
 }
 
@@ -160,6 +168,16 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 		NumReadPartitions: func() int {
 			return common.MaxInt(1, config.NumTasklistReadPartitions(domain, taskListName, taskType))
 		},
+		ActivityConcurrencyLimit: func(activityType string) int {
+			if taskType != persistence.TaskListTypeActivity || activityType == "" {
+				return 0
+			}
+			limits := config.ActivityConcurrencyLimits(
+				dynamicconfig.DomainFilter(domain),
+				dynamicconfig.TaskListFilter(id.GetRoot()),
+			)
+			return activityConcurrencyLimit(limits, activityType)
+		},
 		forwarderConfig: forwarderConfig{
 			ForwarderMaxOutstandingPolls: func() int {
 				return config.ForwarderMaxOutstandingPolls(domain, taskListName, taskType)
@@ -176,3 +194,16 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 		},
 	}, nil
 }
+
+func activityConcurrencyLimit(limits map[string]interface{}, activityType string) int {
+	switch limit := limits[activityType].(type) {
+	case int:
+		return limit
+	case int64:
+		return int(limit)
+	case float64:
+		return int(limit)
+	default:
+		return 0
+	}
+}
@@ -40,12 +40,15 @@ type (
 		taskType     int32
 		rangeID      int64
 		ackLevel     int64
-		store        persistence.TaskManager
-		logger       log.Logger
+		// activities holding a concurrency slot, persisted with every update of the task list
+		runningActivities []*persistenceblobs.RunningActivityInfo
+		store             persistence.TaskManager
+		logger            log.Logger
 	}
 	taskListState struct {
-		rangeID  int64
-		ackLevel int64
+		rangeID           int64
+		ackLevel          int64
+		runningActivities []*persistenceblobs.RunningActivityInfo
 	}
 )
 
@@ -93,26 +96,29 @@ func (db *taskListDB) RenewLease() (taskListState, error) {
 		return taskListState{}, err
 	}
 	db.ackLevel = resp.TaskListInfo.Data.AckLevel
+	db.runningActivities = resp.TaskListInfo.Data.RunningActivities
 	db.rangeID = resp.TaskListInfo.RangeID
-	return taskListState{rangeID: db.rangeID, ackLevel: db.ackLevel}, nil
+	return taskListState{rangeID: db.rangeID, ackLevel: db.ackLevel, runningActivities: db.runningActivities}, nil
 }
 
-// UpdateState updates the taskList state with the given value
-func (db *taskListDB) UpdateState(ackLevel int64) error {
+// UpdateState updates the taskList state with the given ack level and activities holding a concurrency slot
+func (db *taskListDB) UpdateState(ackLevel int64, runningActivities []*persistenceblobs.RunningActivityInfo) error {
 	db.Lock()
 	defer db.Unlock()
 	_, err := db.store.UpdateTaskList(&persistence.UpdateTaskListRequest{
 		TaskListInfo: &persistenceblobs.TaskListInfo{
-			DomainID: db.domainID,
-			Name:     db.taskListName,
-			TaskType: db.taskType,
-			AckLevel: ackLevel,
-			Kind:     db.taskListKind,
+			DomainID:          db.domainID,
+			Name:              db.taskListName,
+			TaskType:          db.taskType,
+			AckLevel:          ackLevel,
+			Kind:              db.taskListKind,
+			RunningActivities: runningActivities,
 		},
 		RangeID: db.rangeID,
 	})
 	if err == nil {
 		db.ackLevel = ackLevel
+		db.runningActivities = runningActivities
 	}
 	return err
 }
@@ -125,11 +131,12 @@ func (db *taskListDB) CreateTasks(tasks []*persistenceblobs.AllocatedTaskInfo) (
 		&persistence.CreateTasksRequest{
 			TaskListInfo: &persistence.PersistedTaskListInfo{
 				Data: &persistenceblobs.TaskListInfo{
-					DomainID: db.domainID,
-					Name:     db.taskListName,
-					TaskType: db.taskType,
-					AckLevel: db.ackLevel,
-					Kind:     db.taskListKind,
+					DomainID:          db.domainID,
+					Name:              db.taskListName,
+					TaskType:          db.taskType,
+					AckLevel:          db.ackLevel,
+					Kind:              db.taskListKind,
+					RunningActivities: db.runningActivities,
 				},
 				RangeID: db.rangeID,
 			},
@@ -185,6 +185,7 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 			Source:                        task.source,
 			ScheduleToStartTimeoutSeconds: newScheduleToStartTimeout,
 			ForwardedFrom:                 fwdr.taskListID.name,
+			ActivityType:                  task.event.Data.GetActivityType(),
 		})
 	default:
 		return errInvalidTaskListType
@@ -294,6 +294,39 @@ func (h *Handler) ListTaskListPartitions(ctx context.Context, request *matchings
 	return response, h.handleErr(err, scope)
 }
 
+// UnloadTaskList stops the task list manager of a task list owned by this host
+func (h *Handler) UnloadTaskList(ctx context.Context, request *matchingservice.UnloadTaskListRequest) (_ *matchingservice.UnloadTaskListResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingUnloadTaskListScope
+	sw := h.startRequestProfile("UnloadTaskList", scope)
+	defer sw.Stop()
+
+	response, err := h.engine.UnloadTaskList(ctx, request)
+	return response, h.handleErr(err, scope)
+}
+
+// AcquireActivityTaskSlot acquires the concurrency slot of an activity before its task is dispatched
+func (h *Handler) AcquireActivityTaskSlot(ctx context.Context, request *matchingservice.AcquireActivityTaskSlotRequest) (_ *matchingservice.AcquireActivityTaskSlotResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingAcquireActivityTaskSlotScope
+	sw := h.startRequestProfile("AcquireActivityTaskSlot", scope)
+	defer sw.Stop()
+
+	response, err := h.engine.AcquireActivityTaskSlot(ctx, request)
+	return response, h.handleErr(err, scope)
+}
+
+// ReleaseActivityTaskSlot releases the concurrency slot of an activity which is no longer pending
+func (h *Handler) ReleaseActivityTaskSlot(ctx context.Context, request *matchingservice.ReleaseActivityTaskSlotRequest) (_ *matchingservice.ReleaseActivityTaskSlotResponse, retError error) {
+	defer log.CapturePanicGRPC(h.GetLogger(), &retError)
+	scope := metrics.MatchingReleaseActivityTaskSlotScope
+	sw := h.startRequestProfile("ReleaseActivityTaskSlot", scope)
+	defer sw.Stop()
+
+	response, err := h.engine.ReleaseActivityTaskSlot(ctx, request)
+	return response, h.handleErr(err, scope)
+}
+
 func (h *Handler) handleErr(err error, scope int) error {
 
 	if err == nil {
@@ -23,6 +23,7 @@ package matching
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"go.temporal.io/temporal-proto/enums"
@@ -46,6 +47,8 @@ type TaskMatcher struct {
 	queryTaskC chan *internalTask
 	// ratelimiter that limits the rate at which tasks can be dispatched to consumers
 	limiter *quotas.RateLimiter
+	// number of local pollers waiting for a task, accessed atomically
+	numPollers int32
 
 	fwdr          *Forwarder
 	scope         func() metrics.Scope // domain metric scope
@@ -266,6 +269,8 @@ forLoop:
 // On success, the returned task could be a query task or a regular task
 // Returns ErrNoTasks when context deadline is exceeded
 func (tm *TaskMatcher) Poll(ctx context.Context) (*internalTask, error) {
+	atomic.AddInt32(&tm.numPollers, 1)
+	defer atomic.AddInt32(&tm.numPollers, -1)
 	// try local match first without blocking until context timeout
 	if task, err := tm.pollNonBlocking(ctx, tm.taskC, tm.queryTaskC); err == nil {
 		return task, nil
@@ -289,6 +294,11 @@ func (tm *TaskMatcher) PollForQuery(ctx context.Context) (*internalTask, error)
 	return tm.pollOrForward(ctx, nil, tm.queryTaskC)
 }
 
+// hasPollers returns true if local pollers are waiting for a task
+func (tm *TaskMatcher) hasPollers() bool {
+	return atomic.LoadInt32(&tm.numPollers) > 0
+}
+
 // UpdateRatelimit updates the task dispatch rate
 func (tm *TaskMatcher) UpdateRatelimit(rps *float64) {
 	if rps == nil {
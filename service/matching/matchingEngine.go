@@ -287,12 +287,13 @@ func (e *matchingEngineImpl) AddActivityTask(ctx context.Context, addRequest *ma
 	expiry := types.TimestampNow()
 	expiry.Seconds += int64(addRequest.GetScheduleToStartTimeoutSeconds())
 	taskInfo := &persistenceblobs.TaskInfo{
-		DomainID:    sourceDomainID,
-		RunID:       runID,
-		WorkflowID:  addRequest.Execution.GetWorkflowId(),
-		ScheduleID:  addRequest.GetScheduleId(),
-		CreatedTime: now,
-		Expiry:      expiry,
+		DomainID:     sourceDomainID,
+		RunID:        runID,
+		WorkflowID:   addRequest.Execution.GetWorkflowId(),
+		ScheduleID:   addRequest.GetScheduleId(),
+		CreatedTime:  now,
+		Expiry:       expiry,
+		ActivityType: addRequest.GetActivityType(),
 	}
 
 	return tlMgr.AddTask(ctx, addTaskParams{
@@ -422,6 +423,10 @@ pollLoop:
 		pollerCtx := context.WithValue(ctx, pollerIDKey, pollerID)
 		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
 		taskListKind := request.TaskList.GetKind()
+		tlMgr, err := e.getTaskListManager(taskList, taskListKind)
+		if err != nil {
+			return nil, err
+		}
 		task, err := e.getTask(pollerCtx, taskList, maxDispatch, taskListKind)
 		if err != nil {
 			// TODO: Is empty poll the best reply for errPumpClosed?
@@ -441,20 +446,50 @@ pollLoop:
 		resp, err := e.recordActivityTaskStarted(ctx, request, task)
 		if err != nil {
 			switch err.(type) {
-			case *serviceerror.NotFound, *serviceerror.EventAlreadyStarted:
+			case *serviceerror.NotFound:
+				// the activity is no longer pending
+				e.logger.Debug("Duplicated activity task", tag.Name(taskListName), tag.TaskID(task.event.TaskID))
+				releaseActivityTaskSlot(tlMgr, e.logger, task.event.Data)
+				task.finish(nil)
+			case *serviceerror.EventAlreadyStarted:
+				// the activity is started by another task which holds the slot
 				e.logger.Debug("Duplicated activity task", tag.Name(taskListName), tag.TaskID(task.event.TaskID))
 				task.finish(nil)
 			default:
+				releaseActivityTaskSlot(tlMgr, e.logger, task.event.Data)
 				task.finish(err)
 			}
 
 			continue pollLoop
 		}
+		e.activityTaskStarted(ctx, tlMgr, task, resp)
 		task.finish(nil)
 		return e.createPollForActivityTaskResponse(task, resp), nil
 	}
 }
 
+// activityTaskStarted holds the concurrency slot of the started activity for its start to close timeout,
+// the slot is released by history once the activity is no longer pending
+func (e *matchingEngineImpl) activityTaskStarted(
+	ctx context.Context,
+	tlMgr taskListManager,
+	task *internalTask,
+	historyResponse *historyservice.RecordActivityTaskStartedResponse,
+) {
+	attributes := historyResponse.ScheduledEvent.GetActivityTaskScheduledEventAttributes()
+	timeout := attributes.GetStartToCloseTimeoutSeconds()
+	if timeout <= 0 {
+		timeout = attributes.GetScheduleToCloseTimeoutSeconds()
+	}
+	if _, err := tlMgr.AcquireActivityTaskSlot(ctx, task.event.Data, time.Duration(timeout)*time.Second); err != nil {
+		e.logger.Warn("Failed to extend the activity task slot of a started activity.",
+			tag.WorkflowID(task.event.Data.GetWorkflowID()),
+			tag.WorkflowRunID(primitives.UUIDString(task.event.Data.GetRunID())),
+			tag.WorkflowScheduleID(task.event.Data.GetScheduleID()),
+			tag.Error(err))
+	}
+}
+
 type queryResult struct {
 	workerResponse *matchingservice.RespondQueryTaskCompletedRequest
 	internalError  error
@@ -666,7 +701,89 @@ func (e *matchingEngineImpl) getTask(
 	return tlMgr.GetTask(ctx, maxDispatchPerSecond)
 }
 
-func (e *matchingEngineImpl) unloadTaskList(id *taskListID) {
+// AcquireActivityTaskSlot acquires the concurrency slot of an activity in the root partition of the task list
+func (e *matchingEngineImpl) AcquireActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.AcquireActivityTaskSlotRequest,
+) (*matchingservice.AcquireActivityTaskSlotResponse, error) {
+	tlMgr, err := e.getActivityTaskSlotTaskList(request.GetDomainUUID(), request.TaskList.GetName(), request.Execution.GetRunId())
+	if err != nil {
+		return nil, err
+	}
+	acquired, err := tlMgr.AcquireActivityTaskSlot(
+		ctx,
+		newActivityTaskSlotInfo(request.Execution, request.GetScheduleId(), request.GetActivityType()),
+		time.Duration(request.GetLeaseSeconds())*time.Second,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &matchingservice.AcquireActivityTaskSlotResponse{Acquired: acquired}, nil
+}
+
+// ReleaseActivityTaskSlot releases the concurrency slot of an activity in the root partition of the task list
+func (e *matchingEngineImpl) ReleaseActivityTaskSlot(
+	ctx context.Context,
+	request *matchingservice.ReleaseActivityTaskSlotRequest,
+) (*matchingservice.ReleaseActivityTaskSlotResponse, error) {
+	tlMgr, err := e.getActivityTaskSlotTaskList(request.GetDomainUUID(), request.TaskList.GetName(), request.Execution.GetRunId())
+	if err != nil {
+		return nil, err
+	}
+	info := newActivityTaskSlotInfo(request.Execution, request.GetScheduleId(), request.GetActivityType())
+	if err := tlMgr.ReleaseActivityTaskSlot(ctx, info); err != nil {
+		return nil, err
+	}
+	return &matchingservice.ReleaseActivityTaskSlotResponse{}, nil
+}
+
+// getActivityTaskSlotTaskList returns the root partition which tracks the activity concurrency slots of the task list,
+// the task list is loaded as the slots are persisted with it
+func (e *matchingEngineImpl) getActivityTaskSlotTaskList(
+	domainID string,
+	taskListName string,
+	runID string,
+) (taskListManager, error) {
+	taskList, err := newTaskListID(domainID, taskListName, persistence.TaskListTypeActivity)
+	if err != nil {
+		return nil, err
+	}
+	if !taskList.IsRoot() {
+		return nil, serviceerror.NewInvalidArgument("Activity task slots are only tracked by the root partition.")
+	}
+	if uuid.Parse(runID) == nil {
+		return nil, serviceerror.NewInvalidArgument("Invalid RunId.")
+	}
+	return e.getTaskListManager(taskList, enums.TaskListKindNormal)
+}
+
+func newActivityTaskSlotInfo(
+	execution *commonproto.WorkflowExecution,
+	scheduleID int64,
+	activityType string,
+) *persistenceblobs.TaskInfo {
+	return &persistenceblobs.TaskInfo{
+		WorkflowID:   execution.GetWorkflowId(),
+		RunID:        primitives.MustParseUUID(execution.GetRunId()),
+		ScheduleID:   scheduleID,
+		ActivityType: activityType,
+	}
+}
+
+// UnloadTaskList stops the task list manager of a task list, the task list is loaded again by the next request
+func (e *matchingEngineImpl) UnloadTaskList(ctx context.Context, request *matchingservice.UnloadTaskListRequest) (*matchingservice.UnloadTaskListResponse, error) {
+	taskListType := persistence.TaskListTypeDecision
+	if request.GetTaskListType() == enums.TaskListTypeActivity {
+		taskListType = persistence.TaskListTypeActivity
+	}
+	taskList, err := newTaskListID(request.GetDomainUUID(), request.TaskList.GetName(), taskListType)
+	if err != nil {
+		return nil, err
+	}
+	return &matchingservice.UnloadTaskListResponse{Unloaded: e.unloadTaskList(taskList)}, nil
+}
+
+func (e *matchingEngineImpl) unloadTaskList(id *taskListID) bool {
 	e.taskListsLock.Lock()
 	tlMgr, ok := e.taskLists[*id]
 	if ok {
@@ -676,6 +793,7 @@ func (e *matchingEngineImpl) unloadTaskList(id *taskListID) {
 	if ok {
 		tlMgr.Stop()
 	}
+	return ok
 }
 
 // Populate the decision task response based on context and scheduled/started events.
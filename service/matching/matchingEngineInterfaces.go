@@ -39,5 +39,8 @@ type (
 		CancelOutstandingPoll(ctx context.Context, request *matchingservice.CancelOutstandingPollRequest) error
 		DescribeTaskList(ctx context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error)
 		ListTaskListPartitions(ctx context.Context, request *matchingservice.ListTaskListPartitionsRequest) (*matchingservice.ListTaskListPartitionsResponse, error)
+		UnloadTaskList(ctx context.Context, request *matchingservice.UnloadTaskListRequest) (*matchingservice.UnloadTaskListResponse, error)
+		AcquireActivityTaskSlot(ctx context.Context, request *matchingservice.AcquireActivityTaskSlotRequest) (*matchingservice.AcquireActivityTaskSlotResponse, error)
+		ReleaseActivityTaskSlot(ctx context.Context, request *matchingservice.ReleaseActivityTaskSlotRequest) (*matchingservice.ReleaseActivityTaskSlotResponse, error)
 	}
 )
@@ -487,6 +487,30 @@ func (s *matchingEngineSuite) TestTaskWriterShutdown() {
 	tlmImpl.taskWriter.stopped = 1 // reset it back to old value
 }
 
+func (s *matchingEngineSuite) TestUnloadTaskList() {
+	domainID := primitives.UUID(uuid.NewRandom())
+	tl := "makeToast"
+	tlID := newTestTaskListID(domainID.String(), tl, persistence.TaskListTypeActivity)
+	request := &matchingservice.UnloadTaskListRequest{
+		DomainUUID:   domainID.String(),
+		TaskList:     &commonproto.TaskList{Name: tl},
+		TaskListType: enums.TaskListTypeActivity,
+	}
+
+	resp, err := s.matchingEngine.UnloadTaskList(context.Background(), request)
+	s.NoError(err)
+	s.False(resp.GetUnloaded())
+
+	_, err = s.matchingEngine.getTaskListManager(tlID, enums.TaskListKindNormal)
+	s.NoError(err)
+	s.Len(s.matchingEngine.getTaskLists(100), 1)
+
+	resp, err = s.matchingEngine.UnloadTaskList(context.Background(), request)
+	s.NoError(err)
+	s.True(resp.GetUnloaded())
+	s.Empty(s.matchingEngine.getTaskLists(100))
+}
+
 func (s *matchingEngineSuite) TestAddThenConsumeActivities() {
 	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
 
@@ -120,3 +120,27 @@ func (h *NilCheckHandler) ListTaskListPartitions(ctx context.Context, request *m
 	}
 	return resp, err
 }
+
+func (h *NilCheckHandler) UnloadTaskList(ctx context.Context, request *matchingservice.UnloadTaskListRequest) (*matchingservice.UnloadTaskListResponse, error) {
+	resp, err := h.parentHandler.UnloadTaskList(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.UnloadTaskListResponse{}
+	}
+	return resp, err
+}
+
+func (h *NilCheckHandler) AcquireActivityTaskSlot(ctx context.Context, request *matchingservice.AcquireActivityTaskSlotRequest) (*matchingservice.AcquireActivityTaskSlotResponse, error) {
+	resp, err := h.parentHandler.AcquireActivityTaskSlot(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.AcquireActivityTaskSlotResponse{}
+	}
+	return resp, err
+}
+
+func (h *NilCheckHandler) ReleaseActivityTaskSlot(ctx context.Context, request *matchingservice.ReleaseActivityTaskSlotRequest) (*matchingservice.ReleaseActivityTaskSlotResponse, error) {
+	resp, err := h.parentHandler.ReleaseActivityTaskSlot(ctx, request)
+	if resp == nil && err == nil {
+		resp = &matchingservice.ReleaseActivityTaskSlotResponse{}
+	}
+	return resp, err
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/backoff"
 	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
@@ -83,6 +84,12 @@ type (
 		GetAllPollerInfo() []*commonproto.PollerInfo
 		// DescribeTaskList returns information about the target task list
 		DescribeTaskList(includeTaskListStatus bool) *matchingservice.DescribeTaskListResponse
+		// AcquireActivityTaskSlot acquires a concurrency slot for the activity of the task from the root partition,
+		// which holds it until it is released or the lease expires. Returns false if the limit of the activity
+		// type is reached, activity types without a limit always succeed.
+		AcquireActivityTaskSlot(ctx context.Context, info *persistenceblobs.TaskInfo, lease time.Duration) (bool, error)
+		// ReleaseActivityTaskSlot releases the concurrency slot of the activity of the task in the root partition
+		ReleaseActivityTaskSlot(ctx context.Context, info *persistenceblobs.TaskInfo) error
 		String() string
 	}
 
@@ -98,6 +105,7 @@ type (
 		taskGC           *taskGC
 		taskAckManager   ackManager   // tracks ackLevel for delivered messages
 		matcher          *TaskMatcher // for matching a task producer with a poller
+		activityLimiter  *activityConcurrencyLimiter
 		domainCache      cache.DomainCache
 		logger           log.Logger
 		metricsClient    metrics.Client
@@ -122,6 +130,8 @@ type (
 const (
 	// maxSyncMatchWaitTime is the max amount of time that we are willing to wait for a sync match to happen
 	maxSyncMatchWaitTime = 200 * time.Millisecond
+	// activityTaskSlotTimeout is the timeout of releasing an activity concurrency slot in the root partition
+	activityTaskSlotTimeout = 5 * time.Second
 )
 
 var _ taskListManager = (*taskListManagerImpl)(nil)
@@ -156,6 +166,10 @@ func newTaskListManager(
 		pollerHistory:       newPollerHistory(),
 		outstandingPollsMap: make(map[string]context.CancelFunc),
 		taskListKind:        int(taskListKind),
+		activityLimiter: newActivityConcurrencyLimiter(
+			taskListConfig.ActivityConcurrencyLimit,
+			clock.NewRealTimeSource(),
+		),
 	}
 	tlMgr.domainNameValue.Store("")
 	tlMgr.domainScopeValue.Store(e.metricsClient.Scope(metrics.MatchingTaskListMgrScope, metrics.DomainUnknownTag()))
@@ -184,6 +198,7 @@ func (c *taskListManagerImpl) Start() error {
 	}
 
 	c.taskAckManager.setAckLevel(state.ackLevel)
+	c.activityLimiter.load(state.runningActivities)
 	c.taskWriter.Start(c.rangeIDToTaskIDBlock(state.rangeID))
 	c.taskReader.Start()
 
@@ -359,10 +374,69 @@ func (c *taskListManagerImpl) DescribeTaskList(includeTaskListStatus bool) *matc
 			EndID:   taskIDBlock.end,
 		},
 	}
+	response.ActivityConcurrency = c.activityLimiter.describe()
 
 	return response
 }
 
+// AcquireActivityTaskSlot acquires a concurrency slot for the activity of the task from the root partition,
+// which holds it until it is released or the lease expires. Returns false if the limit of the activity
+// type is reached, activity types without a limit always succeed.
+func (c *taskListManagerImpl) AcquireActivityTaskSlot(
+	ctx context.Context,
+	info *persistenceblobs.TaskInfo,
+	lease time.Duration,
+) (bool, error) {
+	activityType := info.GetActivityType()
+	if c.config.ActivityConcurrencyLimit(activityType) <= 0 {
+		return true, nil
+	}
+
+	runID := primitives.UUIDString(info.GetRunID())
+	if c.taskListID.IsRoot() {
+		c.startWG.Wait()
+		return c.activityLimiter.acquire(activityType, runningActivityKey{runID: runID, scheduleID: info.GetScheduleID()}, lease), nil
+	}
+	resp, err := c.engine.matchingClient.AcquireActivityTaskSlot(ctx, &matchingservice.AcquireActivityTaskSlotRequest{
+		DomainUUID:   c.taskListID.domainID,
+		TaskList:     &commonproto.TaskList{Name: c.taskListID.GetRoot(), Kind: enums.TaskListKindNormal},
+		Execution:    &commonproto.WorkflowExecution{WorkflowId: info.GetWorkflowID(), RunId: runID},
+		ScheduleId:   info.GetScheduleID(),
+		ActivityType: activityType,
+		LeaseSeconds: int32(lease / time.Second),
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetAcquired(), nil
+}
+
+// ReleaseActivityTaskSlot releases the concurrency slot of the activity of the task in the root partition
+func (c *taskListManagerImpl) ReleaseActivityTaskSlot(
+	ctx context.Context,
+	info *persistenceblobs.TaskInfo,
+) error {
+	activityType := info.GetActivityType()
+	runID := primitives.UUIDString(info.GetRunID())
+	if c.taskListID.IsRoot() {
+		// the slot is released even if the limit was removed meanwhile
+		c.startWG.Wait()
+		c.activityLimiter.release(activityType, runningActivityKey{runID: runID, scheduleID: info.GetScheduleID()})
+		return nil
+	}
+	if c.config.ActivityConcurrencyLimit(activityType) <= 0 {
+		return nil
+	}
+	_, err := c.engine.matchingClient.ReleaseActivityTaskSlot(ctx, &matchingservice.ReleaseActivityTaskSlotRequest{
+		DomainUUID:   c.taskListID.domainID,
+		TaskList:     &commonproto.TaskList{Name: c.taskListID.GetRoot(), Kind: enums.TaskListKindNormal},
+		Execution:    &commonproto.WorkflowExecution{WorkflowId: info.GetWorkflowID(), RunId: runID},
+		ScheduleId:   info.GetScheduleID(),
+		ActivityType: activityType,
+	})
+	return err
+}
+
 func (c *taskListManagerImpl) String() string {
 	buf := new(bytes.Buffer)
 	if c.taskListID.taskType == persistence.TaskListTypeActivity {
@@ -479,7 +553,27 @@ func (c *taskListManagerImpl) executeWithRetry(
 	return
 }
 
-func (c *taskListManagerImpl) trySyncMatch(ctx context.Context, params addTaskParams) (bool, error) {
+func (c *taskListManagerImpl) trySyncMatch(ctx context.Context, params addTaskParams) (matched bool, err error) {
+	// tasks forwarded from a child partition already hold their slot
+	if params.forwardedFrom == "" && c.config.ActivityConcurrencyLimit(params.taskInfo.GetActivityType()) > 0 {
+		if !c.matcher.hasPollers() {
+			// the task of a limited activity type is not forwarded to the parent partition, so the task
+			// goes to the backlog without acquiring a slot which would be released right away
+			return false, nil
+		}
+		if acquired, acquireErr := c.AcquireActivityTaskSlot(ctx, params.taskInfo, activityReservationTTL); acquireErr != nil || !acquired {
+			// concurrency limit of the activity type is reached, the task goes
+			// to the backlog and is dispatched once a running activity closes
+			c.domainScope().IncCounter(metrics.ConcurrencyLimitedCounter)
+			return false, nil
+		}
+		defer func() {
+			if !matched {
+				releaseActivityTaskSlot(c, c.logger, params.taskInfo)
+			}
+		}()
+	}
+
 	childCtx, cancel := c.newChildContext(ctx, maxSyncMatchWaitTime, time.Second)
 
 	// Mocking out TaskId for syncmatch as it hasn't been allocated yet
@@ -489,11 +583,25 @@ func (c *taskListManagerImpl) trySyncMatch(ctx context.Context, params addTaskPa
 	}
 
 	task := newInternalTask(fakeTaskIdWrapper, c.completeTask, params.source, params.forwardedFrom, true)
-	matched, err := c.matcher.Offer(childCtx, task)
+	matched, err = c.matcher.Offer(childCtx, task)
 	cancel()
 	return matched, err
 }
 
+// releaseActivityTaskSlot releases the concurrency slot of an activity whose dispatch did not start it,
+// failures are only logged as the slot is released once its lease expires
+func releaseActivityTaskSlot(tlMgr taskListManager, logger log.Logger, info *persistenceblobs.TaskInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), activityTaskSlotTimeout)
+	defer cancel()
+	if err := tlMgr.ReleaseActivityTaskSlot(ctx, info); err != nil {
+		logger.Warn("Failed to release activity task slot.",
+			tag.WorkflowID(info.GetWorkflowID()),
+			tag.WorkflowRunID(primitives.UUIDString(info.GetRunID())),
+			tag.WorkflowScheduleID(info.GetScheduleID()),
+			tag.Error(err))
+	}
+}
+
 // newChildContext creates a child context with desired timeout.
 // if tailroom is non-zero, then child context timeout will be
 // the minOf(parentCtx.Deadline()-tailroom, timeout). Use this
@@ -35,6 +35,11 @@ import (
 	"github.com/temporalio/temporal/service/worker/scanner/tasklist"
 )
 
+const (
+	// withheldTasksRetryInterval is the interval to retry dispatching tasks withheld by activity concurrency limits
+	withheldTasksRetryInterval = time.Second
+)
+
 type (
 	taskReader struct {
 		taskBuffer chan *persistenceblobs.AllocatedTaskInfo // tasks loaded from persistence
@@ -50,6 +55,8 @@ type (
 		// separate shutdownC needed for dispatchTasks go routine to allow
 		// getTasksPump to be stopped without stopping dispatchTasks in unit tests
 		dispatcherShutdownC chan struct{}
+		// tasks of activity types which reached their concurrency limit, only used by dispatchBufferedTasks
+		withheld *withheldTasks
 	}
 )
 
@@ -64,6 +71,7 @@ func newTaskReader(tlMgr *taskListManagerImpl) *taskReader {
 		// we always dequeue the head of the buffer and try to dispatch it to a poller
 		// so allocate one less than desired target buffer size
 		taskBuffer: make(chan *persistenceblobs.AllocatedTaskInfo, tlMgr.config.GetTasksBatchSize()-1),
+		withheld:   newWithheldTasks(tlMgr.config.GetTasksBatchSize()),
 	}
 }
 
@@ -87,6 +95,13 @@ func (tr *taskReader) Signal() {
 }
 
 func (tr *taskReader) dispatchBufferedTasks() {
+	// tasks of activity types which reached their concurrency limit are withheld per
+	// activity type, while tasks of other activity types keep getting dispatched
+	withheldTimer := time.NewTimer(withheldTasksRetryInterval)
+	defer withheldTimer.Stop()
+	// the ID of the last task read from the buffer, the dropped withheld tasks are read again up to it
+	readLevel := int64(0)
+
 dispatchLoop:
 	for {
 		select {
@@ -94,27 +109,111 @@ dispatchLoop:
 			if !ok { // Task list getTasks pump is shutdown
 				break dispatchLoop
 			}
-			task := newInternalTask(taskInfo, tr.tlMgr.completeTask, enums.TaskSourceDbBacklog, "", false)
-			for {
-				err := tr.tlMgr.DispatchTask(tr.cancelCtx, task)
-				if err == nil {
-					break
-				}
-				if err == context.Canceled {
-					tr.tlMgr.logger.Info("Tasklist manager context is cancelled, shutting down")
-					break dispatchLoop
-				}
-				// this should never happen unless there is a bug - don't drop the task
-				tr.scope().IncCounter(metrics.BufferThrottleCounter)
-				tr.logger().Error("taskReader: unexpected error dispatching task", tag.Error(err))
-				runtime.Gosched()
+			readLevel = taskInfo.GetTaskID()
+			task := tr.newBacklogTask(taskInfo)
+			if tr.withheld.isWithheld(taskInfo.Data.GetActivityType()) || !tr.acquireActivityTaskSlot(task) {
+				tr.withheld.add(task)
+				continue dispatchLoop
+			}
+			if !tr.dispatchTask(task) {
+				break dispatchLoop
+			}
+		case <-tr.tlMgr.activityLimiter.releaseNotifyC():
+			// only signaled in the root partition, the other partitions retry with the timer
+			if !tr.dispatchWithheldTasks(readLevel) {
+				break dispatchLoop
+			}
+		case <-withheldTimer.C:
+			// slots are also released by other partitions, by history and when their lease expires
+			if !tr.dispatchWithheldTasks(readLevel) {
+				break dispatchLoop
 			}
+			withheldTimer.Reset(withheldTasksRetryInterval)
 		case <-tr.dispatcherShutdownC:
 			break dispatchLoop
 		}
 	}
 }
 
+// dispatchWithheldTasks dispatches the withheld tasks of the activity types which are below their concurrency
+// limit, in the order they were read. Once the parked tasks of a type are dispatched, its dropped tasks are read
+// again from persistence up to readLevel. Returns false if the task list is shutting down.
+func (tr *taskReader) dispatchWithheldTasks(readLevel int64) bool {
+	for _, activityType := range tr.withheld.activityTypes() {
+		reloaded := false
+		for {
+			task, ok := tr.withheld.peek(activityType)
+			if !ok {
+				// read at most one batch per activity type, the next batch is read with the next retry
+				if reloaded || !tr.reloadWithheldTasks(activityType, readLevel) {
+					break
+				}
+				reloaded = true
+				continue
+			}
+			if !tr.acquireActivityTaskSlot(task) {
+				break
+			}
+			tr.withheld.pop(activityType)
+			if !tr.dispatchTask(task) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reloadWithheldTasks reads a batch of the dropped tasks of the activity type from persistence,
+// returns false if no task of the type was dropped or the read failed
+func (tr *taskReader) reloadWithheldTasks(activityType string, readLevel int64) bool {
+	skipLevel, ok := tr.withheld.skipLevel(activityType)
+	if !ok {
+		return false
+	}
+	tasks, err := tr.getTaskBatchWithRange(skipLevel, readLevel)
+	if err != nil {
+		tr.logger().Warn("taskReader: failed to read withheld tasks", tag.Error(err))
+		return false
+	}
+	tr.withheld.reload(activityType, tasks, len(tasks) < tr.tlMgr.config.GetTasksBatchSize(), tr.newBacklogTask)
+	return true
+}
+
+func (tr *taskReader) newBacklogTask(taskInfo *persistenceblobs.AllocatedTaskInfo) *internalTask {
+	return newInternalTask(taskInfo, tr.tlMgr.completeTask, enums.TaskSourceDbBacklog, "", false)
+}
+
+// acquireActivityTaskSlot acquires the concurrency slot of the task, returns false if the task is withheld
+func (tr *taskReader) acquireActivityTaskSlot(task *internalTask) bool {
+	acquired, err := tr.tlMgr.AcquireActivityTaskSlot(tr.cancelCtx, task.event.Data, activityReservationTTL)
+	if err != nil {
+		tr.logger().Warn("taskReader: failed to acquire activity task slot", tag.Error(err))
+	}
+	if err != nil || !acquired {
+		tr.scope().IncCounter(metrics.ConcurrencyLimitedCounter)
+		return false
+	}
+	return true
+}
+
+func (tr *taskReader) dispatchTask(task *internalTask) bool {
+	for {
+		err := tr.tlMgr.DispatchTask(tr.cancelCtx, task)
+		if err == nil {
+			return true
+		}
+		if err == context.Canceled {
+			tr.tlMgr.logger.Info("Tasklist manager context is cancelled, shutting down")
+			releaseActivityTaskSlot(tr.tlMgr, tr.logger(), task.event.Data)
+			return false
+		}
+		// this should never happen unless there is a bug - don't drop the task
+		tr.scope().IncCounter(metrics.BufferThrottleCounter)
+		tr.logger().Error("taskReader: unexpected error dispatching task", tag.Error(err))
+		runtime.Gosched()
+	}
+}
+
 func (tr *taskReader) getTasksPump() {
 	tr.tlMgr.startWG.Wait()
 	defer close(tr.taskBuffer)
@@ -266,7 +365,7 @@ func (tr *taskReader) addSingleTaskToBuffer(
 }
 
 func (tr *taskReader) persistAckLevel() error {
-	return tr.tlMgr.db.UpdateState(tr.tlMgr.taskAckManager.getAckLevel())
+	return tr.tlMgr.db.UpdateState(tr.tlMgr.taskAckManager.getAckLevel(), tr.tlMgr.activityLimiter.snapshot())
 }
 
 func (tr *taskReader) isTaskAddedRecently(lastAddTime time.Time) bool {
@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/persistence"
 )
 
@@ -46,7 +47,7 @@ type (
 
 const (
 	// taskListPartitionPrefix is the required naming prefix for any task list partition other than partition 0
-	taskListPartitionPrefix = "/__temporal_sys/"
+	taskListPartitionPrefix = common.TaskListPartitionPrefix
 )
 
 // newTaskListName returns a fully qualified task list name.
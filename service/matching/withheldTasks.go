@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sort"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+type (
+	// withheldTasks holds the backlog tasks of the activity types which reached their concurrency limit,
+	// parked per activity type so that the task reader keeps reading and dispatching the tasks of the other
+	// activity types. Up to maxTasksPerType tasks of a type are parked, the later tasks of the type are
+	// dropped and read again from persistence once the parked ones are dispatched: the dropped tasks are
+	// the tasks of the type above its skip level, which are still in persistence as they were not acked.
+	// The tasks of a type are dispatched in the order they were read.
+	withheldTasks struct {
+		maxTasksPerType int
+		parked          map[string][]*internalTask
+		skipLevels      map[string]int64
+	}
+)
+
+func newWithheldTasks(maxTasksPerType int) *withheldTasks {
+	return &withheldTasks{
+		maxTasksPerType: maxTasksPerType,
+		parked:          make(map[string][]*internalTask),
+		skipLevels:      make(map[string]int64),
+	}
+}
+
+// isWithheld returns true if tasks of the activity type are withheld,
+// the later tasks of the type are withheld after them
+func (w *withheldTasks) isWithheld(activityType string) bool {
+	_, skipped := w.skipLevels[activityType]
+	return len(w.parked[activityType]) > 0 || skipped
+}
+
+// add parks the task, or drops it if the tasks of its type are already dropped or too many are parked
+func (w *withheldTasks) add(task *internalTask) {
+	activityType := task.event.Data.GetActivityType()
+	if _, skipped := w.skipLevels[activityType]; skipped {
+		return
+	}
+	if len(w.parked[activityType]) >= w.maxTasksPerType {
+		w.skipLevels[activityType] = task.event.GetTaskID() - 1
+		return
+	}
+	w.parked[activityType] = append(w.parked[activityType], task)
+}
+
+// activityTypes returns the activity types with withheld tasks
+func (w *withheldTasks) activityTypes() []string {
+	var activityTypes []string
+	for activityType := range w.parked {
+		activityTypes = append(activityTypes, activityType)
+	}
+	for activityType := range w.skipLevels {
+		if _, ok := w.parked[activityType]; !ok {
+			activityTypes = append(activityTypes, activityType)
+		}
+	}
+	sort.Strings(activityTypes)
+	return activityTypes
+}
+
+// peek returns the oldest parked task of the activity type
+func (w *withheldTasks) peek(activityType string) (*internalTask, bool) {
+	tasks := w.parked[activityType]
+	if len(tasks) == 0 {
+		return nil, false
+	}
+	return tasks[0], true
+}
+
+// pop removes the oldest parked task of the activity type
+func (w *withheldTasks) pop(activityType string) {
+	tasks := w.parked[activityType]
+	if len(tasks) <= 1 {
+		delete(w.parked, activityType)
+		return
+	}
+	tasks[0] = nil
+	w.parked[activityType] = tasks[1:]
+}
+
+// skipLevel returns the level after which the dropped tasks of the activity type are read again,
+// returns false if no task of the type was dropped
+func (w *withheldTasks) skipLevel(activityType string) (int64, bool) {
+	skipLevel, ok := w.skipLevels[activityType]
+	return skipLevel, ok
+}
+
+// reload parks the dropped tasks of the activity type from a batch read from persistence after its skip level,
+// and moves the skip level past the batch. isLastBatch is true if the batch reaches the last task read by the
+// task reader, the tasks of the type stop being dropped then.
+func (w *withheldTasks) reload(
+	activityType string,
+	tasks []*persistenceblobs.AllocatedTaskInfo,
+	isLastBatch bool,
+	newTask func(*persistenceblobs.AllocatedTaskInfo) *internalTask,
+) {
+	for _, task := range tasks {
+		if task.Data.GetActivityType() != activityType {
+			continue
+		}
+		if len(w.parked[activityType]) >= w.maxTasksPerType {
+			w.skipLevels[activityType] = task.GetTaskID() - 1
+			return
+		}
+		w.parked[activityType] = append(w.parked[activityType], newTask(task))
+	}
+	if isLastBatch {
+		delete(w.skipLevels, activityType)
+		return
+	}
+	if len(tasks) > 0 {
+		w.skipLevels[activityType] = tasks[len(tasks)-1].GetTaskID()
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+)
+
+type WithheldTasksTestSuite struct {
+	suite.Suite
+	withheld *withheldTasks
+}
+
+func TestWithheldTasksSuite(t *testing.T) {
+	suite.Run(t, new(WithheldTasksTestSuite))
+}
+
+func (s *WithheldTasksTestSuite) SetupTest() {
+	s.withheld = newWithheldTasks(2)
+}
+
+func (s *WithheldTasksTestSuite) newTaskInfo(taskID int64, activityType string) *persistenceblobs.AllocatedTaskInfo {
+	return &persistenceblobs.AllocatedTaskInfo{
+		Data:   &persistenceblobs.TaskInfo{ActivityType: activityType},
+		TaskID: taskID,
+	}
+}
+
+func (s *WithheldTasksTestSuite) newTask(info *persistenceblobs.AllocatedTaskInfo) *internalTask {
+	return newInternalTask(info, nil, 0, "", false)
+}
+
+func (s *WithheldTasksTestSuite) popTaskID(activityType string) int64 {
+	task, ok := s.withheld.peek(activityType)
+	s.True(ok)
+	s.withheld.pop(activityType)
+	return task.event.GetTaskID()
+}
+
+func (s *WithheldTasksTestSuite) TestParkPerActivityType() {
+	s.False(s.withheld.isWithheld("a"))
+	s.withheld.add(s.newTask(s.newTaskInfo(1, "a")))
+	s.withheld.add(s.newTask(s.newTaskInfo(2, "b")))
+	s.withheld.add(s.newTask(s.newTaskInfo(3, "a")))
+	s.True(s.withheld.isWithheld("a"))
+	s.True(s.withheld.isWithheld("b"))
+	s.False(s.withheld.isWithheld("c"))
+	s.Equal([]string{"a", "b"}, s.withheld.activityTypes())
+
+	s.Equal(int64(1), s.popTaskID("a"))
+	s.Equal(int64(3), s.popTaskID("a"))
+	_, ok := s.withheld.peek("a")
+	s.False(ok)
+	s.False(s.withheld.isWithheld("a"))
+	s.Equal([]string{"b"}, s.withheld.activityTypes())
+}
+
+func (s *WithheldTasksTestSuite) TestDropAndReload() {
+	s.withheld.add(s.newTask(s.newTaskInfo(1, "a")))
+	s.withheld.add(s.newTask(s.newTaskInfo(2, "a")))
+	// the parking of the type is full, the later tasks of the type are dropped
+	s.withheld.add(s.newTask(s.newTaskInfo(4, "a")))
+	s.withheld.add(s.newTask(s.newTaskInfo(6, "a")))
+	s.withheld.add(s.newTask(s.newTaskInfo(7, "a")))
+	skipLevel, ok := s.withheld.skipLevel("a")
+	s.True(ok)
+	s.Equal(int64(3), skipLevel)
+
+	// the tasks of the type stay dropped even if a parked task is dispatched
+	s.Equal(int64(1), s.popTaskID("a"))
+	s.withheld.add(s.newTask(s.newTaskInfo(8, "a")))
+	s.Equal(int64(2), s.popTaskID("a"))
+	s.True(s.withheld.isWithheld("a"))
+	s.Equal([]string{"a"}, s.withheld.activityTypes())
+
+	// the first batch read again fills the parking of the type
+	s.withheld.reload("a", []*persistenceblobs.AllocatedTaskInfo{
+		s.newTaskInfo(4, "a"),
+		s.newTaskInfo(5, "b"),
+		s.newTaskInfo(6, "a"),
+		s.newTaskInfo(7, "a"),
+	}, false, s.newTask)
+	skipLevel, ok = s.withheld.skipLevel("a")
+	s.True(ok)
+	s.Equal(int64(6), skipLevel)
+	s.Equal(int64(4), s.popTaskID("a"))
+	s.Equal(int64(6), s.popTaskID("a"))
+
+	// the last batch reaches the read level of the task reader
+	s.withheld.reload("a", []*persistenceblobs.AllocatedTaskInfo{
+		s.newTaskInfo(7, "a"),
+		s.newTaskInfo(8, "a"),
+	}, true, s.newTask)
+	_, ok = s.withheld.skipLevel("a")
+	s.False(ok)
+	s.Equal(int64(7), s.popTaskID("a"))
+	s.Equal(int64(8), s.popTaskID("a"))
+	s.False(s.withheld.isWithheld("a"))
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package failover
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/workflowservice"
+
+	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/client/history"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/clock"
+	"github.com/temporalio/temporal/common/domain"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/membership"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+const (
+	handoverRequestTimeout = 10 * time.Second
+)
+
+type (
+	// Config contains the configuration of the handover processor
+	Config struct {
+		HandoverCheckInterval dynamicconfig.DurationPropertyFn
+	}
+
+	// HandoverProcessor completes graceful domain failovers. For every domain in handover that
+	// is active in the current cluster, it fails the domain over to the handover cluster once
+	// all history shards drained their replication tasks or the failover timeout has passed.
+	HandoverProcessor struct {
+		status          int32
+		config          *Config
+		numberOfShards  int
+		domainCache     cache.DomainCache
+		metadataMgr     persistence.MetadataManager
+		domainHandler   domain.Handler
+		historyClient   history.Client
+		hostInfo        *membership.HostInfo
+		serviceResolver membership.ServiceResolver
+		metricsClient   metrics.Client
+		logger          log.Logger
+		timeSource      clock.TimeSource
+		done            chan struct{}
+	}
+)
+
+// NewHandoverProcessor creates a new handover processor
+func NewHandoverProcessor(
+	config *Config,
+	numberOfShards int,
+	domainCache cache.DomainCache,
+	metadataMgr persistence.MetadataManager,
+	domainHandler domain.Handler,
+	historyClient history.Client,
+	hostInfo *membership.HostInfo,
+	serviceResolver membership.ServiceResolver,
+	metricsClient metrics.Client,
+	logger log.Logger,
+	timeSource clock.TimeSource,
+) *HandoverProcessor {
+	return &HandoverProcessor{
+		status:          common.DaemonStatusInitialized,
+		config:          config,
+		numberOfShards:  numberOfShards,
+		domainCache:     domainCache,
+		metadataMgr:     metadataMgr,
+		domainHandler:   domainHandler,
+		historyClient:   historyClient,
+		hostInfo:        hostInfo,
+		serviceResolver: serviceResolver,
+		metricsClient:   metricsClient,
+		logger:          logger.WithTags(tag.ComponentWorker),
+		timeSource:      timeSource,
+		done:            make(chan struct{}),
+	}
+}
+
+// Start starts the handover processor
+func (p *HandoverProcessor) Start() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	go p.processorLoop()
+	p.logger.Info("Domain handover processor started.")
+}
+
+// Stop stops the handover processor
+func (p *HandoverProcessor) Stop() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(p.done)
+	p.logger.Info("Domain handover processor stopped.")
+}
+
+func (p *HandoverProcessor) processorLoop() {
+	timer := time.NewTimer(p.config.HandoverCheckInterval())
+
+	for {
+		select {
+		case <-timer.C:
+			p.checkDomainHandovers()
+			timer.Reset(p.config.HandoverCheckInterval())
+		case <-p.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (p *HandoverProcessor) checkDomainHandovers() {
+	for domainID, entry := range p.domainCache.GetAllDomain() {
+		if !entry.IsDomainInHandover() || !entry.IsDomainActive() {
+			continue
+		}
+
+		// The following is a best effort to make sure only one worker is completing the handover
+		// of a particular domain. Completing the same handover twice is harmless, the second
+		// attempt is rejected since the domain is no longer active in the current cluster.
+		info, err := p.serviceResolver.Lookup(domainID)
+		if err != nil {
+			p.logger.Info("Failed to lookup host info. Skip current run", tag.WorkflowDomainID(domainID))
+			continue
+		}
+		if info.Identity() != p.hostInfo.Identity() {
+			continue
+		}
+
+		if err := p.checkDomainHandover(domainID); err != nil {
+			p.metricsClient.IncCounter(metrics.DomainHandoverScope, metrics.DomainHandoverFailures)
+			p.logger.Warn("Failed to process domain handover.", tag.WorkflowDomainID(domainID), tag.Error(err))
+		}
+	}
+}
+
+func (p *HandoverProcessor) checkDomainHandover(domainID string) error {
+	// the domain cache may be stale, always make the decision on the persisted domain record
+	resp, err := p.metadataMgr.GetDomain(&persistence.GetDomainRequest{ID: domainID})
+	if err != nil {
+		return err
+	}
+	if resp.HandoverClusterName == "" {
+		return nil
+	}
+
+	domainName := resp.Info.Name
+	timedOut := p.timeSource.Now().UnixNano() >= resp.FailoverEndTime
+	if !timedOut {
+		drained, err := p.isHandoverDrained(domainID)
+		if err != nil {
+			return err
+		}
+		if !drained {
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handoverRequestTimeout)
+	defer cancel()
+	if _, err := p.domainHandler.UpdateDomain(ctx, &workflowservice.UpdateDomainRequest{
+		Name: domainName,
+		ReplicationConfiguration: &commonproto.DomainReplicationConfiguration{
+			ActiveClusterName: resp.HandoverClusterName,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if timedOut {
+		p.metricsClient.IncCounter(metrics.DomainHandoverScope, metrics.DomainHandoverTimedOutCount)
+		p.logger.Warn("Domain handover timed out, failed over with pending replication tasks.",
+			tag.WorkflowDomainName(domainName), tag.ClusterName(resp.HandoverClusterName))
+		return nil
+	}
+	p.metricsClient.IncCounter(metrics.DomainHandoverScope, metrics.DomainHandoverCompletedCount)
+	p.logger.Info("Domain handover completed.", tag.WorkflowDomainName(domainName), tag.ClusterName(resp.HandoverClusterName))
+	return nil
+}
+
+func (p *HandoverProcessor) isHandoverDrained(domainID string) (bool, error) {
+	shardIDs := make([]int32, p.numberOfShards)
+	for i := range shardIDs {
+		shardIDs[i] = int32(i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handoverRequestTimeout)
+	defer cancel()
+	resp, err := p.historyClient.GetDomainHandoverStatus(ctx, &historyservice.GetDomainHandoverStatusRequest{
+		DomainUUID: domainID,
+		ShardIDs:   shardIDs,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(resp.PendingShardIDs) == 0, nil
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/temporal"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
+	"go.temporal.io/temporal/activity"
+	"golang.org/x/time/rate"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/historyservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/xdc"
+)
+
+const (
+	pageSize = 100
+	// attemptsOnError is the number of times copying a single workflow is retried within a round
+	attemptsOnError = 3
+)
+
+type (
+	copyTask struct {
+		execution commonproto.WorkflowExecution
+		attempts  int
+		// hbd is the checkpoint of the page the workflow is listed on
+		hbd CopyHeartBeatDetails
+	}
+
+	copyResult int
+)
+
+const (
+	copyResultCopied copyResult = iota
+	copyResultSkipped
+	copyResultFailed
+)
+
+// copyDomainActivity creates the migrated domain in this cluster with the same ID as in the source cluster,
+// active in the source cluster until the cutover, and locks it, so that it does not accept any requests while
+// its workflows are replicated
+func copyDomainActivity(ctx context.Context, params MigrationParams) error {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	sourceClient := migrator.clientBean.GetRemoteFrontendClient(params.SourceCluster)
+
+	resp, err := sourceClient.DescribeDomain(ctx, &workflowservice.DescribeDomainRequest{
+		Name: params.DomainName,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetIsGlobalDomain() {
+		return temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("domain %v is a global domain, use a failover instead", params.DomainName))
+	}
+
+	info := resp.GetDomainInfo()
+	config := resp.GetConfiguration()
+	badBinaries := commonproto.BadBinaries{Binaries: map[string]*commonproto.BadBinaryInfo{}}
+	if config.GetBadBinaries() != nil && config.GetBadBinaries().Binaries != nil {
+		badBinaries = *config.GetBadBinaries()
+	}
+	currentCluster := migrator.clusterMetadata.GetCurrentClusterName()
+	_, err = migrator.metadataMgr.CreateDomain(&persistence.CreateDomainRequest{
+		Info: &persistence.DomainInfo{
+			ID:          info.GetUuid(),
+			Name:        info.GetName(),
+			Status:      persistence.DomainStatusRegistered,
+			OwnerEmail:  info.GetOwnerEmail(),
+			Description: info.GetDescription(),
+			Data:        info.GetData(),
+		},
+		Config: &persistence.DomainConfig{
+			Retention:                config.GetWorkflowExecutionRetentionPeriodInDays(),
+			EmitMetric:               config.GetEmitMetric().GetValue(),
+			HistoryArchivalStatus:    config.GetHistoryArchivalStatus(),
+			HistoryArchivalURI:       config.GetHistoryArchivalURI(),
+			VisibilityArchivalStatus: config.GetVisibilityArchivalStatus(),
+			VisibilityArchivalURI:    config.GetVisibilityArchivalURI(),
+			BadBinaries:              badBinaries,
+		},
+		ReplicationConfig: &persistence.DomainReplicationConfig{
+			ActiveClusterName: params.SourceCluster,
+			Clusters: []*persistence.ClusterReplicationConfig{
+				{ClusterName: currentCluster},
+				{ClusterName: params.SourceCluster},
+			},
+		},
+		IsGlobalDomain:  false,
+		ConfigVersion:   0,
+		FailoverVersion: common.EmptyVersion,
+	})
+	if err != nil {
+		// the domain is already copied by an earlier attempt, unless another domain has the same name
+		existing, getErr := migrator.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: params.DomainName})
+		if getErr != nil {
+			return err
+		}
+		if existing.Info.ID != info.GetUuid() {
+			return temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("another domain named %v already exists in cluster %v", params.DomainName, currentCluster))
+		}
+	}
+
+	return migrator.domainHandler.StartDomainMigration(ctx, params.DomainName, params.SourceCluster)
+}
+
+// copyWorkflowsActivity replicates the workflows closed after request.ClosedAfter as well as all open workflows
+// from the source cluster into this cluster. Only the events a workflow does not have in this cluster yet are
+// replicated, so every round replicates the events the workflows made progress with since the last round.
+func copyWorkflowsActivity(ctx context.Context, params MigrationParams, request copyWorkflowsRequest) (CopyHeartBeatDetails, error) {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	sourceClient := migrator.clientBean.GetRemoteFrontendClient(params.SourceCluster)
+
+	hbd := CopyHeartBeatDetails{}
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &hbd); err != nil {
+			migrator.metricsClient.IncCounter(metrics.DomainMigrationScope, metrics.DomainMigrationFailures)
+			getActivityLogger(ctx).Error("Failed to recover from last heartbeat, start over from beginning", tag.Error(err))
+			hbd = CopyHeartBeatDetails{}
+		}
+	}
+	if hbd.StartedAt == 0 {
+		hbd.StartedAt = time.Now().UnixNano()
+	}
+
+	domainResp, err := migrator.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: params.DomainName})
+	if err != nil {
+		return CopyHeartBeatDetails{}, err
+	}
+	domainID := domainResp.Info.ID
+
+	// workflows started after the listing began are left to the next round,
+	// their visibility records might not be written yet anyway
+	startTimeFilter := &commonproto.StartTimeFilter{
+		EarliestTime: 0,
+		LatestTime:   hbd.StartedAt + visibilityDelay.Nanoseconds(),
+	}
+
+	resender := newHistoryResender(migrator, params.SourceCluster)
+	rateLimiter := rate.NewLimiter(rate.Limit(params.RPS), params.RPS)
+	taskCh := make(chan copyTask, pageSize)
+	respCh := make(chan copyResult, pageSize)
+	defer close(taskCh)
+	for i := 0; i < params.Concurrency; i++ {
+		go startCopyProcessor(ctx, domainID, resender, taskCh, respCh, rateLimiter)
+	}
+
+	for {
+		var executions []*commonproto.WorkflowExecutionInfo
+		var nextPageToken []byte
+		if hbd.Open {
+			resp, err := sourceClient.ListOpenWorkflowExecutions(ctx, &workflowservice.ListOpenWorkflowExecutionsRequest{
+				Domain:          params.DomainName,
+				MaximumPageSize: int32(pageSize),
+				NextPageToken:   hbd.PageToken,
+				StartTimeFilter: startTimeFilter,
+			})
+			if err != nil {
+				return CopyHeartBeatDetails{}, err
+			}
+			executions, nextPageToken = resp.Executions, resp.NextPageToken
+		} else {
+			resp, err := sourceClient.ListClosedWorkflowExecutions(ctx, &workflowservice.ListClosedWorkflowExecutionsRequest{
+				Domain:          params.DomainName,
+				MaximumPageSize: int32(pageSize),
+				NextPageToken:   hbd.PageToken,
+				StartTimeFilter: startTimeFilter,
+			})
+			if err != nil {
+				return CopyHeartBeatDetails{}, err
+			}
+			executions, nextPageToken = resp.Executions, resp.NextPageToken
+		}
+
+		batchCount := 0
+		for _, wf := range executions {
+			if !hbd.Open && wf.GetCloseTime().GetValue() <= request.ClosedAfter {
+				continue
+			}
+			taskCh <- copyTask{execution: *wf.Execution, hbd: hbd}
+			batchCount++
+		}
+
+		// wait for all workflows of the page being replicated
+		for i := 0; i < batchCount; i++ {
+			select {
+			case result := <-respCh:
+				switch result {
+				case copyResultCopied:
+					hbd.CopiedCount++
+				case copyResultSkipped:
+					hbd.SkippedCount++
+				default:
+					hbd.FailedCount++
+				}
+			case <-ctx.Done():
+				return CopyHeartBeatDetails{}, ctx.Err()
+			}
+		}
+
+		hbd.PageToken = nextPageToken
+		if len(hbd.PageToken) == 0 {
+			if hbd.Open {
+				break
+			}
+			// the closed workflows are listed first, a workflow closing in between is listed by the next round
+			hbd.Open = true
+		}
+		activity.RecordHeartbeat(ctx, hbd)
+	}
+
+	return hbd, nil
+}
+
+func startCopyProcessor(
+	ctx context.Context,
+	domainID string,
+	resender xdc.NDCHistoryResender,
+	taskCh chan copyTask,
+	respCh chan copyResult,
+	limiter *rate.Limiter,
+) {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task, ok := <-taskCh:
+			if !ok {
+				return
+			}
+			respCh <- processCopyTask(ctx, domainID, resender, task, limiter, migrator)
+		}
+	}
+}
+
+func processCopyTask(
+	ctx context.Context,
+	domainID string,
+	resender xdc.NDCHistoryResender,
+	task copyTask,
+	limiter *rate.Limiter,
+	migrator *Migrator,
+) copyResult {
+	for {
+		copied, err := copyWorkflow(ctx, domainID, resender, task, limiter)
+		if err == nil {
+			if copied {
+				migrator.metricsClient.IncCounter(metrics.DomainMigrationScope, metrics.DomainMigrationCopiedCount)
+				return copyResultCopied
+			}
+			migrator.metricsClient.IncCounter(metrics.DomainMigrationScope, metrics.DomainMigrationSkippedCount)
+			return copyResultSkipped
+		}
+
+		migrator.metricsClient.IncCounter(metrics.DomainMigrationScope, metrics.DomainMigrationFailures)
+		getActivityLogger(ctx).Error("Failed to replicate workflow",
+			tag.WorkflowID(task.execution.GetWorkflowId()),
+			tag.WorkflowRunID(task.execution.GetRunId()),
+			tag.Error(err))
+		if task.attempts >= attemptsOnError || isDone(ctx) {
+			return copyResultFailed
+		}
+		task.attempts++
+	}
+}
+
+// copyWorkflow replicates the events of a workflow which are not in this cluster yet from the source cluster,
+// it returns false if there are no such events, or the workflow does not exist anymore in the source cluster
+func copyWorkflow(
+	ctx context.Context,
+	domainID string,
+	resender xdc.NDCHistoryResender,
+	task copyTask,
+	limiter *rate.Limiter,
+) (bool, error) {
+	if err := limiter.Wait(ctx); err != nil {
+		return false, err
+	}
+	activity.RecordHeartbeat(ctx, task.hbd)
+
+	execution := task.execution
+	startEventID, startEventVersion, err := getLastReplicatedEvent(ctx, domainID, execution)
+	if err != nil {
+		return false, err
+	}
+
+	if err := resender.SendSingleWorkflowHistory(
+		domainID,
+		execution.GetWorkflowId(),
+		execution.GetRunId(),
+		startEventID,
+		startEventVersion,
+		common.EmptyEventID,
+		common.EmptyVersion,
+	); err != nil {
+		// NotFound means the workflow is deleted by retention
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return false, nil
+		}
+		return false, err
+	}
+
+	lastEventID, _, err := getLastReplicatedEvent(ctx, domainID, execution)
+	if err != nil {
+		return false, err
+	}
+	return lastEventID != startEventID, nil
+}
+
+// getLastReplicatedEvent returns the ID and version of the last event of a workflow in this cluster,
+// which is the exclusive start of the events to replicate
+func getLastReplicatedEvent(
+	ctx context.Context,
+	domainID string,
+	execution commonproto.WorkflowExecution,
+) (int64, int64, error) {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	resp, err := migrator.clientBean.GetHistoryClient().GetMutableState(ctx, &historyservice.GetMutableStateRequest{
+		DomainUUID: domainID,
+		Execution:  &execution,
+	})
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return common.FirstEventID - 1, common.EmptyVersion, nil
+		}
+		return 0, 0, err
+	}
+
+	versionHistories := persistence.NewVersionHistoriesFromProto(resp.GetVersionHistories())
+	currentVersionHistory, err := versionHistories.GetCurrentVersionHistory()
+	if err != nil {
+		return 0, 0, err
+	}
+	lastItem, err := currentVersionHistory.GetLastItem()
+	if err != nil {
+		return 0, 0, err
+	}
+	return lastItem.GetEventID(), lastItem.GetVersion(), nil
+}
+
+func newHistoryResender(migrator *Migrator, sourceCluster string) xdc.NDCHistoryResender {
+	historyClient := migrator.clientBean.GetHistoryClient()
+	return xdc.NewNDCHistoryResender(
+		migrator.domainCache,
+		migrator.clientBean.GetRemoteAdminClient(sourceCluster),
+		func(ctx context.Context, request *historyservice.ReplicateEventsV2Request) error {
+			_, err := historyClient.ReplicateEventsV2(ctx, request)
+			return err
+		},
+		persistence.NewPayloadSerializer(),
+		migrator.logger,
+	)
+}
+
+// lockSourceDomainActivity locks the domain in the source cluster, so that it stops accepting requests
+func lockSourceDomainActivity(ctx context.Context, params MigrationParams) error {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	sourceAdminClient := migrator.clientBean.GetRemoteAdminClient(params.SourceCluster)
+
+	_, err := sourceAdminClient.StartDomainMigration(ctx, &adminservice.StartDomainMigrationRequest{
+		Domain:            params.DomainName,
+		TargetClusterName: migrator.clusterMetadata.GetCurrentClusterName(),
+	})
+	return err
+}
+
+// endMigrationActivity either unlocks the domain in the source cluster when the migration is aborted,
+// or deprecates the domain in the source cluster and unlocks it in this cluster after the cutover
+func endMigrationActivity(ctx context.Context, params MigrationParams, aborted bool) error {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	sourceAdminClient := migrator.clientBean.GetRemoteAdminClient(params.SourceCluster)
+
+	_, err := sourceAdminClient.EndDomainMigration(ctx, &adminservice.EndDomainMigrationRequest{
+		Domain:  params.DomainName,
+		Aborted: aborted,
+	})
+	if err != nil {
+		// InvalidArgument means the domain is not locked in the source cluster anymore, by an earlier attempt
+		if _, ok := err.(*serviceerror.InvalidArgument); !ok {
+			return err
+		}
+	}
+	if aborted {
+		return nil
+	}
+
+	domainResp, err := migrator.metadataMgr.GetDomain(&persistence.GetDomainRequest{Name: params.DomainName})
+	if err != nil {
+		return err
+	}
+	if domainResp.MigrationClusterName == "" {
+		return nil
+	}
+	return migrator.domainHandler.EndDomainMigration(ctx, params.DomainName, false)
+}
+
+func isDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func getActivityLogger(ctx context.Context) log.Logger {
+	migrator := ctx.Value(migratorContextKey).(*Migrator)
+	wfInfo := activity.GetInfo(ctx)
+	return migrator.logger.WithTags(
+		tag.WorkflowID(wfInfo.WorkflowExecution.ID),
+		tag.WorkflowRunID(wfInfo.WorkflowExecution.RunID),
+		tag.WorkflowDomainName(wfInfo.WorkflowDomain),
+	)
+}
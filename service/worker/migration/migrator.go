@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"context"
+
+	"go.temporal.io/temporal/activity"
+	sdkclient "go.temporal.io/temporal/client"
+	"go.temporal.io/temporal/worker"
+	"go.temporal.io/temporal/workflow"
+
+	"github.com/temporalio/temporal/client"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/cluster"
+	"github.com/temporalio/temporal/common/domain"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	// BootstrapParams contains the set of params needed to bootstrap
+	// the domain migration sub-system
+	BootstrapParams struct {
+		// ServiceClient is an instance of cadence service client
+		ServiceClient sdkclient.Client
+		// ClientBean is an instance of client.Bean for a collection of clients,
+		// including the clients of the source cluster of a migration
+		ClientBean client.Bean
+		// MetadataManager is used to copy the migrated domain into this cluster
+		MetadataManager persistence.MetadataManager
+		// DomainHandler locks and unlocks the migrated domain in this cluster
+		DomainHandler domain.Handler
+		// DomainCache is used to replicate the workflows of the migrated domain
+		DomainCache cache.DomainCache
+		// ClusterMetadata contains the metadata for this cluster
+		ClusterMetadata cluster.Metadata
+		// MetricsClient is an instance of metrics object for emitting stats
+		MetricsClient metrics.Client
+		Logger        log.Logger
+	}
+
+	// Migrator is the background sub-system that executes the workflows migrating local domains
+	// from another cluster into this cluster. It is also the context object that gets passed
+	// around within the migration activities.
+	Migrator struct {
+		svcClient       sdkclient.Client
+		clientBean      client.Bean
+		metadataMgr     persistence.MetadataManager
+		domainHandler   domain.Handler
+		domainCache     cache.DomainCache
+		clusterMetadata cluster.Metadata
+		metricsClient   metrics.Client
+		logger          log.Logger
+	}
+)
+
+// New returns a new instance of the domain Migrator
+func New(params *BootstrapParams) *Migrator {
+	return &Migrator{
+		svcClient:       params.ServiceClient,
+		clientBean:      params.ClientBean,
+		metadataMgr:     params.MetadataManager,
+		domainHandler:   params.DomainHandler,
+		domainCache:     params.DomainCache,
+		clusterMetadata: params.ClusterMetadata,
+		metricsClient:   params.MetricsClient,
+		logger:          params.Logger.WithTags(tag.ComponentDomainMigrator),
+	}
+}
+
+// Start starts the worker for the domain migration workflows
+func (m *Migrator) Start() error {
+	ctx := context.WithValue(context.Background(), migratorContextKey, m)
+	workerOpts := worker.Options{
+		BackgroundActivityContext: ctx,
+	}
+	migrationWorker := worker.New(m.svcClient, MigratorTaskListName, workerOpts)
+	migrationWorker.RegisterWorkflowWithOptions(MigrationWorkflow, workflow.RegisterOptions{Name: MigrationWFTypeName})
+	migrationWorker.RegisterActivityWithOptions(copyDomainActivity, activity.RegisterOptions{Name: copyDomainActivityName})
+	migrationWorker.RegisterActivityWithOptions(copyWorkflowsActivity, activity.RegisterOptions{Name: copyWorkflowsActivityName})
+	migrationWorker.RegisterActivityWithOptions(lockSourceDomainActivity, activity.RegisterOptions{Name: lockSourceDomainActivityName})
+	migrationWorker.RegisterActivityWithOptions(endMigrationActivity, activity.RegisterOptions{Name: endMigrationActivityName})
+
+	return migrationWorker.Start()
+}
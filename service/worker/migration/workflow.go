@@ -0,0 +1,318 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/temporal"
+	"go.temporal.io/temporal/workflow"
+
+	"github.com/temporalio/temporal/common/cache"
+)
+
+const (
+	migratorContextKey = "migratorContext"
+	// MigratorTaskListName is the tasklist name
+	MigratorTaskListName = "cadence-sys-domain-migration-tasklist"
+	// MigrationWFTypeName is the workflow type
+	MigrationWFTypeName = "cadence-sys-domain-migration-workflow"
+	// MigrationWFIDPrefix is the prefix of the workflow ID, followed by the domain name,
+	// so that only one migration of a domain runs at a time
+	MigrationWFIDPrefix = "cadence-sys-domain-migration-"
+	// MigrationStateQueryType is the query type returning the MigrationState of a migration workflow
+	MigrationStateQueryType = "migration-state"
+
+	copyDomainActivityName       = "cadence-sys-domain-migration-copy-domain-activity"
+	copyWorkflowsActivityName    = "cadence-sys-domain-migration-copy-workflows-activity"
+	lockSourceDomainActivityName = "cadence-sys-domain-migration-lock-source-activity"
+	endMigrationActivityName     = "cadence-sys-domain-migration-end-activity"
+
+	// InfiniteDuration is a long duration(20 yrs) we used for infinite workflow running
+	InfiniteDuration = 20 * 365 * 24 * time.Hour
+
+	// DefaultRPS is the default RPS of copied workflows
+	DefaultRPS = 50
+	// DefaultConcurrency is the default number of workflows copied in parallel
+	DefaultConcurrency = 5
+	// DefaultLagThreshold is the default number of workflows a catch up round replicated events of,
+	// below which the migration moves on to the cutover
+	DefaultLagThreshold = 100
+	// DefaultMaxCatchUpRounds is the default number of catch up rounds after which the
+	// migration moves on to the cutover regardless of the lag
+	DefaultMaxCatchUpRounds = 10
+	// DefaultActivityHeartBeatTimeout is the default value for ActivityHeartBeatTimeout
+	DefaultActivityHeartBeatTimeout = 30 * time.Second
+
+	// domainCacheRefreshDelay is the time to wait after locking the source domain,
+	// until all hosts of the source cluster rejects writes to the domain
+	domainCacheRefreshDelay = 3 * cache.DomainCacheRefreshInterval
+	// visibilityDelay covers the visibility records of the source cluster, which are
+	// written asynchronously once a workflow started or closed
+	visibilityDelay = time.Minute
+	// nonRetryableErrReason is the reason of errors which fail the migration right away
+	nonRetryableErrReason = "cadence-sys-domain-migration-non-retryable-error"
+)
+
+const (
+	// MigrationPhaseCopyDomain copies the domain record and locks the domain in the target cluster
+	MigrationPhaseCopyDomain = "copy-domain"
+	// MigrationPhaseCatchUp replicates the events of the workflows in rounds, until a round replicates
+	// events of fewer workflows than the lag threshold
+	MigrationPhaseCatchUp = "catch-up"
+	// MigrationPhaseCutover locks the domain in the source cluster, replicates the remaining events,
+	// deprecates the domain in the source cluster and unlocks it in the target
+	MigrationPhaseCutover = "cutover"
+	// MigrationPhaseCompleted means the domain is served by the target cluster
+	MigrationPhaseCompleted = "completed"
+)
+
+type (
+	// MigrationParams is the parameters of the domain migration workflow
+	MigrationParams struct {
+		// DomainName is the local domain to migrate into the cluster running the workflow
+		DomainName string
+		// SourceCluster is the cluster the domain is migrated from
+		SourceCluster string
+
+		// Below are all optional
+		// RPS of replicated workflows. Default to DefaultRPS
+		RPS int
+		// Number of workflows replicated in parallel. Default to DefaultConcurrency
+		Concurrency int
+		// LagThreshold default to DefaultLagThreshold
+		LagThreshold int
+		// MaxCatchUpRounds default to DefaultMaxCatchUpRounds
+		MaxCatchUpRounds int
+		// timeout for activity heartbeat
+		ActivityHeartBeatTimeout time.Duration
+		// State is the checkpoint the migration continues from. It is carried over when the workflow
+		// continues as new after each catch up round, and taken from the last run of the workflow
+		// when a failed or terminated migration is started again.
+		State MigrationState
+	}
+
+	// MigrationState is the progress of a domain migration
+	MigrationState struct {
+		Phase         string
+		CatchUpRounds int
+		// ClosedCopiedUpTo is the close time in nanoseconds up to which all closed workflows were replicated,
+		// later rounds only list the workflows closed after it, next to the open workflows
+		ClosedCopiedUpTo int64
+		// LastRoundCopiedCount is the number of workflows the last round replicated events of,
+		// it is the lag of the migration
+		LastRoundCopiedCount int
+		// SourceLocked is set once the domain rejects writes in the source cluster
+		SourceLocked bool
+		// Ending is set once the domain is deprecated in the source cluster,
+		// from then on the migration cannot be aborted anymore
+		Ending       bool
+		CopiedCount  int
+		SkippedCount int
+		FailedCount  int
+	}
+
+	copyWorkflowsRequest struct {
+		// ClosedAfter skips closed workflows which closed before, they were replicated by an earlier round
+		ClosedAfter int64
+	}
+
+	// CopyHeartBeatDetails is the struct for heartbeat details of the copy workflows activity
+	CopyHeartBeatDetails struct {
+		PageToken []byte
+		// Open is set once the closed workflows are replicated and the open workflows are listed
+		Open bool
+		// StartedAt is the time in nanoseconds the listing of the workflows started
+		StartedAt int64
+		// Number of workflows events were replicated of
+		CopiedCount int
+		// Number of workflows which had no events to replicate or are deleted in the source cluster
+		SkippedCount int
+		// Number of workflows that give up due to errors
+		FailedCount int
+	}
+)
+
+var (
+	migrationActivityRetryPolicy = temporal.RetryPolicy{
+		InitialInterval:          10 * time.Second,
+		BackoffCoefficient:       1.7,
+		MaximumInterval:          5 * time.Minute,
+		ExpirationInterval:       InfiniteDuration,
+		NonRetriableErrorReasons: []string{nonRetryableErrReason},
+	}
+
+	migrationActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		RetryPolicy:            &migrationActivityRetryPolicy,
+	}
+
+	copyWorkflowsActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    InfiniteDuration,
+		RetryPolicy:            &migrationActivityRetryPolicy,
+	}
+)
+
+// MigrationWorkflow migrates a local domain from the source cluster into the cluster running the workflow.
+// The events of the workflows are replicated through the history resender, which requires the source cluster
+// to keep version histories for local domains. Until the cutover the domain is active in the source cluster,
+// the target cluster processes the tasks of the replicated workflows as standby tasks, and fails them over
+// like those of a global domain once it takes over the domain. Each catch up round replicates the events the
+// workflows made progress with since the last round, the domain is locked in the source cluster only for the
+// last round, which is the downtime of the migration.
+func MigrationWorkflow(ctx workflow.Context, params MigrationParams) (MigrationState, error) {
+	params = setDefaultParams(params)
+	if err := validateParams(params); err != nil {
+		return MigrationState{}, err
+	}
+	state := params.State
+	if err := workflow.SetQueryHandler(ctx, MigrationStateQueryType, func() (MigrationState, error) {
+		return state, nil
+	}); err != nil {
+		return state, err
+	}
+
+	err := runMigration(ctx, params, &state)
+	if err != nil && temporal.IsCanceledError(err) && state.SourceLocked && !state.Ending {
+		// the migration was aborted, the domain is served by the source cluster again,
+		// the target keeps its replicas locked, so that the migration can be started again
+		disconnectedCtx, _ := workflow.NewDisconnectedContext(ctx)
+		opt := workflow.WithActivityOptions(disconnectedCtx, migrationActivityOptions)
+		if endErr := workflow.ExecuteActivity(opt, endMigrationActivityName, params, true).Get(disconnectedCtx, nil); endErr != nil {
+			workflow.GetLogger(ctx).Error(fmt.Sprintf("Failed to unlock the domain in the source cluster: %v", endErr))
+			return state, endErr
+		}
+		state.SourceLocked = false
+	}
+	return state, err
+}
+
+func runMigration(ctx workflow.Context, params MigrationParams, state *MigrationState) error {
+	opt := workflow.WithActivityOptions(ctx, migrationActivityOptions)
+	copyActivityOptions := copyWorkflowsActivityOptions
+	copyActivityOptions.HeartbeatTimeout = params.ActivityHeartBeatTimeout
+	copyOpt := workflow.WithActivityOptions(ctx, copyActivityOptions)
+
+	if state.Phase == "" {
+		state.Phase = MigrationPhaseCopyDomain
+	}
+
+	if state.Phase == MigrationPhaseCopyDomain {
+		if err := workflow.ExecuteActivity(opt, copyDomainActivityName, params).Get(ctx, nil); err != nil {
+			return err
+		}
+		state.Phase = MigrationPhaseCatchUp
+	}
+
+	if state.Phase == MigrationPhaseCatchUp {
+		var result CopyHeartBeatDetails
+		if err := workflow.ExecuteActivity(copyOpt, copyWorkflowsActivityName, params, copyWorkflowsRequest{
+			ClosedAfter: state.ClosedCopiedUpTo,
+		}).Get(ctx, &result); err != nil {
+			return err
+		}
+		state.addRound(result)
+		state.CatchUpRounds++
+		if result.CopiedCount+result.FailedCount > params.LagThreshold && state.CatchUpRounds < params.MaxCatchUpRounds {
+			// the state is the checkpoint of the next round
+			params.State = *state
+			return workflow.NewContinueAsNewError(ctx, MigrationWFTypeName, params)
+		}
+		state.Phase = MigrationPhaseCutover
+	}
+
+	if state.Phase == MigrationPhaseCutover {
+		if !state.SourceLocked {
+			if err := workflow.ExecuteActivity(opt, lockSourceDomainActivityName, params).Get(ctx, nil); err != nil {
+				return err
+			}
+			state.SourceLocked = true
+			if err := workflow.Sleep(ctx, domainCacheRefreshDelay); err != nil {
+				return err
+			}
+		}
+
+		var result CopyHeartBeatDetails
+		if err := workflow.ExecuteActivity(copyOpt, copyWorkflowsActivityName, params, copyWorkflowsRequest{
+			ClosedAfter: state.ClosedCopiedUpTo,
+		}).Get(ctx, &result); err != nil {
+			return err
+		}
+		state.addRound(result)
+		if result.FailedCount > 0 {
+			return fmt.Errorf("failed to replicate %v workflows, start the migration again to retry", result.FailedCount)
+		}
+
+		state.Ending = true
+		if err := workflow.ExecuteActivity(opt, endMigrationActivityName, params, false).Get(ctx, nil); err != nil {
+			return err
+		}
+		state.Phase = MigrationPhaseCompleted
+	}
+	return nil
+}
+
+func (s *MigrationState) addRound(result CopyHeartBeatDetails) {
+	s.CopiedCount += result.CopiedCount
+	s.SkippedCount += result.SkippedCount
+	s.FailedCount += result.FailedCount
+	s.LastRoundCopiedCount = result.CopiedCount
+	if result.FailedCount == 0 && result.StartedAt > 0 {
+		// workflows failed to copy are retried by the next round
+		s.ClosedCopiedUpTo = result.StartedAt - visibilityDelay.Nanoseconds()
+	}
+}
+
+func validateParams(params MigrationParams) error {
+	if params.DomainName == "" || params.SourceCluster == "" {
+		return fmt.Errorf("must provide required parameters: DomainName/SourceCluster")
+	}
+	switch params.State.Phase {
+	case "", MigrationPhaseCopyDomain, MigrationPhaseCatchUp, MigrationPhaseCutover:
+		return nil
+	case MigrationPhaseCompleted:
+		return fmt.Errorf("domain %v is already migrated", params.DomainName)
+	default:
+		return fmt.Errorf("not supported migration phase: %v", params.State.Phase)
+	}
+}
+
+func setDefaultParams(params MigrationParams) MigrationParams {
+	if params.RPS <= 0 {
+		params.RPS = DefaultRPS
+	}
+	if params.Concurrency <= 0 {
+		params.Concurrency = DefaultConcurrency
+	}
+	if params.LagThreshold <= 0 {
+		params.LagThreshold = DefaultLagThreshold
+	}
+	if params.MaxCatchUpRounds <= 0 {
+		params.MaxCatchUpRounds = DefaultMaxCatchUpRounds
+	}
+	if params.ActivityHeartBeatTimeout <= 0 {
+		params.ActivityHeartBeatTimeout = DefaultActivityHeartBeatTimeout
+	}
+	return params
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/temporal/activity"
+	"go.temporal.io/temporal/testsuite"
+	"go.temporal.io/temporal/workflow"
+)
+
+type migrationWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+}
+
+func TestMigrationWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(migrationWorkflowTestSuite))
+}
+
+func (s *migrationWorkflowTestSuite) newTestEnv() *testsuite.TestWorkflowEnvironment {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(MigrationWorkflow, workflow.RegisterOptions{Name: MigrationWFTypeName})
+	env.RegisterActivityWithOptions(copyDomainActivity, activity.RegisterOptions{Name: copyDomainActivityName})
+	env.RegisterActivityWithOptions(copyWorkflowsActivity, activity.RegisterOptions{Name: copyWorkflowsActivityName})
+	env.RegisterActivityWithOptions(lockSourceDomainActivity, activity.RegisterOptions{Name: lockSourceDomainActivityName})
+	env.RegisterActivityWithOptions(endMigrationActivity, activity.RegisterOptions{Name: endMigrationActivityName})
+	return env
+}
+
+func (s *migrationWorkflowTestSuite) TestMigration_Completed() {
+	env := s.newTestEnv()
+	env.OnActivity(copyDomainActivityName, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(copyWorkflowsActivityName, mock.Anything, mock.Anything, copyWorkflowsRequest{}).
+		Return(CopyHeartBeatDetails{StartedAt: 1000000000000, CopiedCount: 10}, nil).Once()
+	env.OnActivity(lockSourceDomainActivityName, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(copyWorkflowsActivityName, mock.Anything, mock.Anything, mock.MatchedBy(func(request copyWorkflowsRequest) bool {
+		return request.ClosedAfter > 0
+	})).Return(CopyHeartBeatDetails{StartedAt: 2000000000000, CopiedCount: 2, SkippedCount: 1}, nil).Once()
+	env.OnActivity(endMigrationActivityName, mock.Anything, mock.Anything, false).Return(nil).Once()
+
+	env.ExecuteWorkflow(MigrationWFTypeName, MigrationParams{DomainName: "some-domain", SourceCluster: "standby"})
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	env.AssertExpectations(s.T())
+
+	var state MigrationState
+	s.NoError(env.GetWorkflowResult(&state))
+	s.Equal(MigrationPhaseCompleted, state.Phase)
+	s.True(state.SourceLocked)
+	s.True(state.Ending)
+	s.Equal(1, state.CatchUpRounds)
+	s.Equal(12, state.CopiedCount)
+	s.Equal(1, state.SkippedCount)
+}
+
+func (s *migrationWorkflowTestSuite) TestMigration_ContinueAsNewWhileLagging() {
+	env := s.newTestEnv()
+	env.OnActivity(copyWorkflowsActivityName, mock.Anything, mock.Anything, mock.Anything).
+		Return(CopyHeartBeatDetails{StartedAt: 1000000000000, CopiedCount: DefaultLagThreshold + 1}, nil).Once()
+
+	env.ExecuteWorkflow(MigrationWFTypeName, MigrationParams{
+		DomainName:    "some-domain",
+		SourceCluster: "standby",
+		State:         MigrationState{Phase: MigrationPhaseCatchUp},
+	})
+	s.True(env.IsWorkflowCompleted())
+	_, ok := env.GetWorkflowError().(*workflow.ContinueAsNewError)
+	s.True(ok)
+	env.AssertExpectations(s.T())
+}
+
+func (s *migrationWorkflowTestSuite) TestMigration_FailedCopiesBlockCutover() {
+	env := s.newTestEnv()
+	env.OnActivity(copyWorkflowsActivityName, mock.Anything, mock.Anything, mock.Anything).
+		Return(CopyHeartBeatDetails{StartedAt: 1000000000000, FailedCount: 1}, nil).Once()
+
+	env.ExecuteWorkflow(MigrationWFTypeName, MigrationParams{
+		DomainName:    "some-domain",
+		SourceCluster: "standby",
+		State:         MigrationState{Phase: MigrationPhaseCutover, SourceLocked: true},
+	})
+	s.True(env.IsWorkflowCompleted())
+	s.Error(env.GetWorkflowError())
+	env.AssertExpectations(s.T())
+}
+
+func (s *migrationWorkflowTestSuite) TestMigration_AlreadyCompleted() {
+	env := s.newTestEnv()
+	env.ExecuteWorkflow(MigrationWFTypeName, MigrationParams{
+		DomainName:    "some-domain",
+		SourceCluster: "standby",
+		State:         MigrationState{Phase: MigrationPhaseCompleted},
+	})
+	s.True(env.IsWorkflowCompleted())
+	s.Error(env.GetWorkflowError())
+}
+
+func (s *migrationWorkflowTestSuite) TestMigrationState_AddRound() {
+	state := MigrationState{ClosedCopiedUpTo: 5}
+	state.addRound(CopyHeartBeatDetails{StartedAt: 1000000000000, CopiedCount: 3, FailedCount: 1})
+	s.Equal(int64(5), state.ClosedCopiedUpTo)
+	s.Equal(3, state.LastRoundCopiedCount)
+
+	state.addRound(CopyHeartBeatDetails{StartedAt: 1000000000000, CopiedCount: 2})
+	s.Equal(1000000000000-visibilityDelay.Nanoseconds(), state.ClosedCopiedUpTo)
+	s.Equal(5, state.CopiedCount)
+	s.Equal(1, state.FailedCount)
+}
@@ -35,7 +35,6 @@ import (
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/clock"
-	"github.com/temporalio/temporal/common/domain"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/messaging"
@@ -46,25 +45,24 @@ import (
 
 type (
 	replicationTaskProcessor struct {
-		currentCluster                string
-		sourceCluster                 string
-		consumerName                  string
-		client                        messaging.Client
-		consumer                      messaging.Consumer
-		isStarted                     int32
-		isStopped                     int32
-		shutdownWG                    sync.WaitGroup
-		shutdownCh                    chan struct{}
-		config                        *Config
-		logger                        log.Logger
-		metricsClient                 metrics.Client
-		domainreplicationTaskExecutor domain.ReplicationTaskExecutor
-		historyRereplicator           xdc.HistoryRereplicator
-		nDCHistoryResender            xdc.NDCHistoryResender
-		historyClient                 history.Client
-		domainCache                   cache.DomainCache
-		timeSource                    clock.TimeSource
-		sequentialTaskProcessor       task.Processor
+		currentCluster          string
+		sourceCluster           string
+		consumerName            string
+		client                  messaging.Client
+		consumer                messaging.Consumer
+		isStarted               int32
+		isStopped               int32
+		shutdownWG              sync.WaitGroup
+		shutdownCh              chan struct{}
+		config                  *Config
+		logger                  log.Logger
+		metricsClient           metrics.Client
+		historyRereplicator     xdc.HistoryRereplicator
+		nDCHistoryResender      xdc.NDCHistoryResender
+		historyClient           history.Client
+		domainCache             cache.DomainCache
+		timeSource              clock.TimeSource
+		sequentialTaskProcessor task.Processor
 	}
 )
 
@@ -89,7 +87,6 @@ func newReplicationTaskProcessor(
 	config *Config,
 	logger log.Logger,
 	metricsClient metrics.Client,
-	domainreplicationTaskExecutor domain.ReplicationTaskExecutor,
 	historyRereplicator xdc.HistoryRereplicator,
 	nDCHistoryResender xdc.NDCHistoryResender,
 	historyClient history.Client,
@@ -101,21 +98,20 @@ func newReplicationTaskProcessor(
 		common.IsWhitelistServiceTransientError)
 
 	return &replicationTaskProcessor{
-		currentCluster:                currentCluster,
-		sourceCluster:                 sourceCluster,
-		consumerName:                  consumer,
-		client:                        client,
-		shutdownCh:                    make(chan struct{}),
-		config:                        config,
-		logger:                        logger,
-		metricsClient:                 metricsClient,
-		domainreplicationTaskExecutor: domainreplicationTaskExecutor,
-		historyRereplicator:           historyRereplicator,
-		nDCHistoryResender:            nDCHistoryResender,
-		historyClient:                 retryableHistoryClient,
-		timeSource:                    clock.NewRealTimeSource(),
-		domainCache:                   domainCache,
-		sequentialTaskProcessor:       sequentialTaskProcessor,
+		currentCluster:          currentCluster,
+		sourceCluster:           sourceCluster,
+		consumerName:            consumer,
+		client:                  client,
+		shutdownCh:              make(chan struct{}),
+		config:                  config,
+		logger:                  logger,
+		metricsClient:           metricsClient,
+		historyRereplicator:     historyRereplicator,
+		nDCHistoryResender:      nDCHistoryResender,
+		historyClient:           retryableHistoryClient,
+		timeSource:              clock.NewRealTimeSource(),
+		domainCache:             domainCache,
+		sequentialTaskProcessor: sequentialTaskProcessor,
 	}
 }
 
@@ -291,16 +287,9 @@ func (p *replicationTaskProcessor) handleDomainReplicationTask(task *replication
 	sw := p.metricsClient.StartTimer(metrics.DomainReplicationTaskScope, metrics.ReplicatorLatency)
 	defer sw.Stop()
 
-	defer func() {
-		if retError == nil {
-			p.ackMsg(msg, logger)
-		}
-	}()
-
-	err := p.domainreplicationTaskExecutor.Execute(task.GetDomainTaskAttributes())
-	if err != nil {
-		return err
-	}
+	// domains are replicated through the domain replication queue, tasks left over in kafka are obsolete
+	logger.Warn("Dropping domain replication task received from kafka.")
+	p.ackMsg(msg, logger)
 	return nil
 }
 
@@ -37,7 +37,6 @@ import (
 	"github.com/temporalio/temporal/.gen/proto/replication"
 	"github.com/temporalio/temporal/common/cache"
 	"github.com/temporalio/temporal/common/cluster"
-	"github.com/temporalio/temporal/common/domain"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
 	messageMocks "github.com/temporalio/temporal/common/messaging/mocks"
@@ -65,8 +64,7 @@ type (
 		logger         log.Logger
 		metricsClient  metrics.Client
 
-		mockMsg                           *messageMocks.Message
-		mockDomainReplicationTaskExecutor *domain.MockReplicationTaskExecutor
+		mockMsg *messageMocks.Message
 
 		mockRereplicator *xdc.MockHistoryRereplicator
 
@@ -122,7 +120,6 @@ func (s *replicationTaskProcessorSuite) SetupTest() {
 	s.mockMsg = &messageMocks.Message{}
 	s.mockMsg.On("Partition").Return(int32(0))
 	s.mockMsg.On("Offset").Return(int64(0))
-	s.mockDomainReplicationTaskExecutor = domain.NewMockReplicationTaskExecutor(s.controller)
 	s.mockRereplicator = &xdc.MockHistoryRereplicator{}
 
 	s.currentCluster = cluster.TestAlternativeClusterName
@@ -136,7 +133,6 @@ func (s *replicationTaskProcessorSuite) SetupTest() {
 		s.config,
 		s.logger,
 		s.metricsClient,
-		s.mockDomainReplicationTaskExecutor,
 		s.mockRereplicator,
 		s.mockNDCResender,
 		s.mockHistoryClient,
@@ -158,7 +154,7 @@ func (s *replicationTaskProcessorSuite) TestDecodeMsgAndSubmit_BadEncoding() {
 	s.processor.decodeMsgAndSubmit(s.mockMsg)
 }
 
-func (s *replicationTaskProcessorSuite) TestDecodeMsgAndSubmit_Domain_Success() {
+func (s *replicationTaskProcessorSuite) TestDecodeMsgAndSubmit_Domain_Dropped() {
 	replicationAttr := &replication.DomainTaskAttributes{
 		DomainOperation: enums.DomainOperationUpdate,
 		Id:              "some random domain ID",
@@ -170,26 +166,6 @@ func (s *replicationTaskProcessorSuite) TestDecodeMsgAndSubmit_Domain_Success()
 	replicationTaskBinary, err := replicationTask.Marshal()
 	s.Nil(err)
 	s.mockMsg.On("Value").Return(replicationTaskBinary)
-	s.mockDomainReplicationTaskExecutor.EXPECT().Execute(replicationAttr).Return(nil).Times(1)
-	s.mockMsg.On("Ack").Return(nil).Once()
-
-	s.processor.decodeMsgAndSubmit(s.mockMsg)
-}
-
-func (s *replicationTaskProcessorSuite) TestDecodeMsgAndSubmit_Domain_FailedThenSuccess() {
-	replicationAttr := &replication.DomainTaskAttributes{
-		DomainOperation: enums.DomainOperationUpdate,
-		Id:              "some random domain ID",
-	}
-	replicationTask := &replication.ReplicationTask{
-		TaskType:   enums.ReplicationTaskTypeDomain,
-		Attributes: &replication.ReplicationTask_DomainTaskAttributes{DomainTaskAttributes: replicationAttr},
-	}
-	replicationTaskBinary, err := replicationTask.Marshal()
-	s.Nil(err)
-	s.mockMsg.On("Value").Return(replicationTaskBinary)
-	s.mockDomainReplicationTaskExecutor.EXPECT().Execute(replicationAttr).Return(errors.New("some random error")).Times(1)
-	s.mockDomainReplicationTaskExecutor.EXPECT().Execute(replicationAttr).Return(nil).Times(1)
 	s.mockMsg.On("Ack").Return(nil).Once()
 
 	s.processor.decodeMsgAndSubmit(s.mockMsg)
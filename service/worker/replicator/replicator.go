@@ -122,19 +122,20 @@ func (r *Replicator) Start() error {
 		}
 
 		if clusterName != currentClusterName {
-			if replicationConsumerConfig.Type == config.ReplicationConsumerTypeRPC {
-				processor := newDomainReplicationMessageProcessor(
-					clusterName,
-					r.logger.WithTags(tag.ComponentReplicationTaskProcessor, tag.SourceCluster(clusterName)),
-					r.clientBean.GetRemoteAdminClient(clusterName),
-					r.metricsClient,
-					r.domainReplicationTaskExecutor,
-					r.hostInfo,
-					r.serviceResolver,
-					r.domainReplicationQueue,
-				)
-				r.domainProcessors = append(r.domainProcessors, processor)
-			} else {
+			// domain replication tasks are always pulled from the domain replication queue of the remote cluster
+			processor := newDomainReplicationMessageProcessor(
+				clusterName,
+				r.logger.WithTags(tag.ComponentReplicationTaskProcessor, tag.SourceCluster(clusterName)),
+				r.clientBean.GetRemoteAdminClient(clusterName),
+				r.metricsClient,
+				r.domainReplicationTaskExecutor,
+				r.hostInfo,
+				r.serviceResolver,
+				r.domainReplicationQueue,
+			)
+			r.domainProcessors = append(r.domainProcessors, processor)
+
+			if replicationConsumerConfig.Type != config.ReplicationConsumerTypeRPC {
 				r.createKafkaProcessors(currentClusterName, clusterName)
 			}
 		}
@@ -196,7 +197,6 @@ func (r *Replicator) createKafkaProcessors(currentClusterName string, clusterNam
 		r.config,
 		logger,
 		r.metricsClient,
-		r.domainReplicationTaskExecutor,
 		historyRereplicator,
 		nDCHistoryReplicator,
 		r.historyClient,
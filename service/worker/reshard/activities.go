@@ -0,0 +1,672 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reshard
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/temporal"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal/activity"
+
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/sharding"
+)
+
+const (
+	pageSize = 100
+	// rangeSizeBits is the number of task IDs a range ID of a shard allows to allocate, it must be the
+	// same as the RangeSizeBits of the history service, which allocates the task IDs of a loaded shard
+	rangeSizeBits = 20
+)
+
+type (
+	// childShard allocates the task IDs of the tasks moved into a child shard from its range,
+	// the range is taken over by the split, so that earlier attempts fail to write into the child shard
+	childShard struct {
+		resharder  *Resharder
+		shardID    int
+		rangeID    int64
+		nextTaskID int64
+	}
+)
+
+// startReshardActivity records the target shard count in the history shard routing,
+// and returns the shard count the shards are split from
+func startReshardActivity(ctx context.Context, params ReshardParams) (int, error) {
+	resharder := ctx.Value(resharderContextKey).(*Resharder)
+
+	resp, err := resharder.clusterMetadataMgr.GetHistoryShardRouting()
+	if err != nil {
+		return 0, err
+	}
+	routing := resp.Routing
+	shardCount := int(routing.GetShardCount())
+	targetShardCount := int(routing.GetTargetShardCount())
+	switch {
+	case targetShardCount == params.TargetShardCount:
+		// the reshard was started by an earlier attempt
+		return shardCount, nil
+	case targetShardCount > 0:
+		return 0, temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("history shards are being split into %v shards", targetShardCount))
+	case shardCount == params.TargetShardCount:
+		return 0, temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("history shards are already split into %v shards", shardCount))
+	case params.TargetShardCount < shardCount || params.TargetShardCount%shardCount != 0:
+		return 0, temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("target shard count %v is not a multiple of the shard count %v", params.TargetShardCount, shardCount))
+	}
+
+	if err := resharder.clusterMetadataMgr.UpdateHistoryShardRouting(&persistence.UpdateHistoryShardRoutingRequest{
+		Routing: &pblobs.HistoryShardRouting{
+			ShardCount:       routing.GetShardCount(),
+			TargetShardCount: int32(params.TargetShardCount),
+		},
+		PreviousVersion: resp.Version,
+	}); err != nil {
+		return 0, err
+	}
+	resharder.logger.Info("History shard reshard started.", tag.Number(int64(params.TargetShardCount)))
+	return shardCount, nil
+}
+
+// splitShardActivity moves the workflow executions of a shard which belong to its child shards under the
+// target shard count into the child shards together with their tasks, and returns the number of moved
+// executions. The shard cannot be loaded by the history hosts until the executions are moved and the
+// routing points to the child shards.
+func splitShardActivity(ctx context.Context, request splitShardRequest) (int, error) {
+	resharder := ctx.Value(resharderContextKey).(*Resharder)
+	logger := resharder.logger.WithTags(tag.ShardID(request.ShardID))
+
+	split, err := resharder.isShardSplit(request.ShardID)
+	if err != nil {
+		return 0, err
+	}
+	if split {
+		// the executions were moved by an earlier attempt, only the cleanup of the shard may be left
+		return 0, resharder.cleanupSplitShard(ctx, request)
+	}
+
+	rangeID, err := resharder.freezeShard(request.ShardID)
+	if err != nil {
+		return 0, err
+	}
+	childShards := make(map[int]*childShard)
+	for _, childShardID := range sharding.GetChildShardIDs(request.ShardID, request.ShardCount, request.TargetShardCount)[1:] {
+		child, err := resharder.acquireChildShard(childShardID)
+		if err != nil {
+			return 0, err
+		}
+		childShards[childShardID] = child
+	}
+	tasks, err := resharder.loadMovedExecutionTasks(request)
+	if err != nil {
+		return 0, err
+	}
+
+	hbd := SplitHeartBeatDetails{}
+	err = resharder.forEachMovedExecution(ctx, request, func(execution *persistence.ConcreteExecution, childShardID int) error {
+		executionTasks := tasks[executionKey{
+			domainID:   execution.DomainID,
+			workflowID: execution.WorkflowID,
+			runID:      execution.RunID,
+		}]
+		if err := resharder.moveExecution(execution, request.ShardID, childShards[childShardID], executionTasks); err != nil {
+			logger.Error("Failed to move workflow execution.",
+				tag.WorkflowDomainID(execution.DomainID),
+				tag.WorkflowID(execution.WorkflowID),
+				tag.WorkflowRunID(execution.RunID),
+				tag.Error(err))
+			return err
+		}
+		hbd.MovedCount++
+		return nil
+	}, func() {
+		activity.RecordHeartbeat(ctx, hbd)
+	})
+	if err != nil {
+		return hbd.MovedCount, err
+	}
+
+	// an attempt started later may have taken over the shard and its children, the routing is only updated
+	// by the attempt which still owns all of them, and the range IDs of the children are bumped before,
+	// so that no earlier attempt can write into the child shards once they are served
+	if err := resharder.checkShardFrozen(request.ShardID, rangeID); err != nil {
+		return hbd.MovedCount, err
+	}
+	for _, child := range childShards {
+		if err := child.renewRange(); err != nil {
+			return hbd.MovedCount, err
+		}
+	}
+
+	// the moved executions are routed to the child shards from now on
+	if err := resharder.addSplitShard(request.ShardID); err != nil {
+		return hbd.MovedCount, err
+	}
+	logger.Info("History shard split.", tag.Counter(hbd.MovedCount))
+	return hbd.MovedCount, resharder.cleanupSplitShard(ctx, request)
+}
+
+// completeReshardActivity routes all workflows by the target shard count
+func completeReshardActivity(ctx context.Context, params ReshardParams) error {
+	resharder := ctx.Value(resharderContextKey).(*Resharder)
+
+	resp, err := resharder.clusterMetadataMgr.GetHistoryShardRouting()
+	if err != nil {
+		return err
+	}
+	routing := resp.Routing
+	if int(routing.GetShardCount()) == params.TargetShardCount {
+		// completed by an earlier attempt
+		return nil
+	}
+	if len(routing.GetSplitShardIDs()) != int(routing.GetShardCount()) {
+		return temporal.NewCustomError(nonRetryableErrReason, fmt.Sprintf("only %v of %v history shards are split",
+			len(routing.GetSplitShardIDs()), routing.GetShardCount()))
+	}
+
+	if err := resharder.clusterMetadataMgr.UpdateHistoryShardRouting(&persistence.UpdateHistoryShardRoutingRequest{
+		Routing: &pblobs.HistoryShardRouting{
+			ShardCount: int32(params.TargetShardCount),
+		},
+		PreviousVersion: resp.Version,
+	}); err != nil {
+		return err
+	}
+	resharder.logger.Info("History shard reshard completed.", tag.Number(int64(params.TargetShardCount)))
+	return nil
+}
+
+func (r *Resharder) isShardSplit(shardID int) (bool, error) {
+	resp, err := r.clusterMetadataMgr.GetHistoryShardRouting()
+	if err != nil {
+		return false, err
+	}
+	for _, splitShardID := range resp.Routing.GetSplitShardIDs() {
+		if int(splitShardID) == shardID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addSplitShard routes the workflows of the shard by the target shard count,
+// shards are split in parallel so the update is retried until it is applied
+func (r *Resharder) addSplitShard(shardID int) error {
+	for {
+		resp, err := r.clusterMetadataMgr.GetHistoryShardRouting()
+		if err != nil {
+			return err
+		}
+		routing := resp.Routing
+		for _, splitShardID := range routing.GetSplitShardIDs() {
+			if int(splitShardID) == shardID {
+				return nil
+			}
+		}
+
+		err = r.clusterMetadataMgr.UpdateHistoryShardRouting(&persistence.UpdateHistoryShardRoutingRequest{
+			Routing: &pblobs.HistoryShardRouting{
+				ShardCount:       routing.GetShardCount(),
+				TargetShardCount: routing.GetTargetShardCount(),
+				SplitShardIDs:    append(routing.GetSplitShardIDs(), int32(shardID)),
+			},
+			PreviousVersion: resp.Version,
+		})
+		if _, ok := err.(*persistence.ConditionFailedError); !ok {
+			return err
+		}
+	}
+}
+
+// freezeShard prevents the shard from being loaded and returns its range ID, the range ID is bumped
+// so that the current owner of the shard fails to write and unloads it, it is bumped by every attempt
+// of the split so that an earlier attempt still running is fenced off
+func (r *Resharder) freezeShard(shardID int) (int64, error) {
+	resp, err := r.shardMgr.GetShard(&persistence.GetShardRequest{ShardID: int32(shardID)})
+	if err != nil {
+		return 0, err
+	}
+	shardInfo := resp.ShardInfo
+
+	previousRangeID := shardInfo.RangeID
+	shardInfo.RangeID++
+	shardInfo.SplitInProgress = true
+	if err := r.shardMgr.UpdateShard(&persistence.UpdateShardRequest{
+		ShardInfo:       shardInfo,
+		PreviousRangeID: previousRangeID,
+	}); err != nil {
+		return 0, err
+	}
+	return shardInfo.RangeID, nil
+}
+
+// checkShardFrozen returns an error if the shard was taken over by another attempt since it was frozen
+func (r *Resharder) checkShardFrozen(shardID int, rangeID int64) error {
+	resp, err := r.shardMgr.GetShard(&persistence.GetShardRequest{ShardID: int32(shardID)})
+	if err != nil {
+		return err
+	}
+	if !resp.ShardInfo.SplitInProgress || resp.ShardInfo.RangeID != rangeID {
+		return &persistence.ShardOwnershipLostError{
+			ShardID: shardID,
+			Msg:     fmt.Sprintf("Shard is not frozen with range ID %v anymore, current range ID is %v.", rangeID, resp.ShardInfo.RangeID),
+		}
+	}
+	return nil
+}
+
+// unfreezeShard allows the shard to be loaded again, the shard rejects
+// the workflows which hash to another shard under the split shard count
+func (r *Resharder) unfreezeShard(shardID int, splitShardCount int) error {
+	resp, err := r.shardMgr.GetShard(&persistence.GetShardRequest{ShardID: int32(shardID)})
+	if err != nil {
+		return err
+	}
+	shardInfo := resp.ShardInfo
+	if !shardInfo.SplitInProgress && int(shardInfo.SplitShardCount) == splitShardCount {
+		return nil
+	}
+
+	previousRangeID := shardInfo.RangeID
+	shardInfo.RangeID++
+	shardInfo.SplitInProgress = false
+	shardInfo.SplitShardCount = int32(splitShardCount)
+	return r.shardMgr.UpdateShard(&persistence.UpdateShardRequest{
+		ShardInfo:       shardInfo,
+		PreviousRangeID: previousRangeID,
+	})
+}
+
+// acquireChildShard takes over the range of the child shard, the executions and their tasks are written
+// into the child shard with the new range ID
+func (r *Resharder) acquireChildShard(shardID int) (*childShard, error) {
+	rangeID, err := r.getOrCreateShard(shardID)
+	if err != nil {
+		return nil, err
+	}
+	child := &childShard{
+		resharder: r,
+		shardID:   shardID,
+		rangeID:   rangeID,
+	}
+	if err := child.renewRange(); err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
+// renewRange bumps the range ID of the child shard, it fails if another attempt took over the child shard
+func (s *childShard) renewRange() error {
+	resp, err := s.resharder.shardMgr.GetShard(&persistence.GetShardRequest{ShardID: int32(s.shardID)})
+	if err != nil {
+		return err
+	}
+	shardInfo := resp.ShardInfo
+	if shardInfo.RangeID != s.rangeID {
+		return &persistence.ShardOwnershipLostError{
+			ShardID: s.shardID,
+			Msg:     fmt.Sprintf("Shard range ID was updated from %v to %v by another attempt.", s.rangeID, shardInfo.RangeID),
+		}
+	}
+
+	shardInfo.RangeID++
+	if err := s.resharder.shardMgr.UpdateShard(&persistence.UpdateShardRequest{
+		ShardInfo:       shardInfo,
+		PreviousRangeID: s.rangeID,
+	}); err != nil {
+		return err
+	}
+	s.rangeID = shardInfo.RangeID
+	s.nextTaskID = s.rangeID << rangeSizeBits
+	return nil
+}
+
+// assignTaskIDs allocates the IDs of the tasks from the range of the child shard, the history host
+// which loads the child shard later on allocates its task IDs from a higher range
+func (s *childShard) assignTaskIDs(tasks []persistence.Task) error {
+	for _, task := range tasks {
+		if s.nextTaskID >= (s.rangeID+1)<<rangeSizeBits {
+			if err := s.renewRange(); err != nil {
+				return err
+			}
+		}
+		task.SetTaskID(s.nextTaskID)
+		s.nextTaskID++
+	}
+	return nil
+}
+
+// getOrCreateShard returns the range ID of the shard, the shard is created the same way as
+// it is by the history hosts when it is loaded for the first time
+func (r *Resharder) getOrCreateShard(shardID int) (int64, error) {
+	resp, err := r.shardMgr.GetShard(&persistence.GetShardRequest{ShardID: int32(shardID)})
+	if err == nil {
+		return resp.ShardInfo.RangeID, nil
+	}
+	if _, ok := err.(*serviceerror.NotFound); !ok {
+		return 0, err
+	}
+
+	shardInfo := &pblobs.ShardInfo{
+		ShardID:          int32(shardID),
+		RangeID:          0,
+		TransferAckLevel: 0,
+	}
+	err = r.shardMgr.CreateShard(&persistence.CreateShardRequest{ShardInfo: shardInfo})
+	if _, ok := err.(*persistence.ShardAlreadyExistError); ok {
+		return r.getOrCreateShard(shardID)
+	}
+	return shardInfo.RangeID, err
+}
+
+// cleanupSplitShard deletes the moved executions from the split shard, and allows the shard to be loaded again
+func (r *Resharder) cleanupSplitShard(ctx context.Context, request splitShardRequest) error {
+	executionMgr, err := r.persistenceBean.GetExecutionManager(request.ShardID)
+	if err != nil {
+		return err
+	}
+
+	deletedCount := 0
+	if err := r.forEachMovedExecution(ctx, request, func(execution *persistence.ConcreteExecution, _ int) error {
+		if err := executionMgr.DeleteCurrentWorkflowExecution(&persistence.DeleteCurrentWorkflowExecutionRequest{
+			DomainID:   execution.DomainID,
+			WorkflowID: execution.WorkflowID,
+			RunID:      execution.RunID,
+		}); err != nil {
+			return err
+		}
+		if err := executionMgr.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
+			DomainID:   execution.DomainID,
+			WorkflowID: execution.WorkflowID,
+			RunID:      execution.RunID,
+		}); err != nil {
+			return err
+		}
+		deletedCount++
+		return nil
+	}, func() {
+		activity.RecordHeartbeat(ctx, deletedCount)
+	}); err != nil {
+		return err
+	}
+
+	return r.unfreezeShard(request.ShardID, request.TargetShardCount)
+}
+
+// forEachMovedExecution calls fn for every execution of the split shard which belongs to one of its child shards
+func (r *Resharder) forEachMovedExecution(
+	ctx context.Context,
+	request splitShardRequest,
+	fn func(execution *persistence.ConcreteExecution, childShardID int) error,
+	onPage func(),
+) error {
+	executionMgr, err := r.persistenceBean.GetExecutionManager(request.ShardID)
+	if err != nil {
+		return err
+	}
+
+	var pageToken []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := executionMgr.ListConcreteExecutions(&persistence.ListConcreteExecutionsRequest{
+			PageSize:      pageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, execution := range resp.Executions {
+			childShardID := request.getChildShardID(execution.WorkflowID)
+			if childShardID == request.ShardID {
+				continue
+			}
+			if err := fn(execution, childShardID); err != nil {
+				return err
+			}
+		}
+		onPage()
+
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			return nil
+		}
+	}
+}
+
+// getChildShardID returns the shard the workflow belongs to under the target shard count
+func (r splitShardRequest) getChildShardID(workflowID string) int {
+	return common.WorkflowIDToHistoryShard(workflowID, r.TargetShardCount)
+}
+
+// moveExecution copies the execution into the child shard together with its history and its tasks.
+// The execution is created in a single write, so the copy in the child shard is complete once it exists.
+func (r *Resharder) moveExecution(
+	execution *persistence.ConcreteExecution,
+	shardID int,
+	child *childShard,
+	tasks *executionTasks,
+) error {
+
+	executionMgr, err := r.persistenceBean.GetExecutionManager(shardID)
+	if err != nil {
+		return err
+	}
+	childExecutionMgr, err := r.persistenceBean.GetExecutionManager(child.shardID)
+	if err != nil {
+		return err
+	}
+
+	getRequest := &persistence.GetWorkflowExecutionRequest{
+		DomainID: execution.DomainID,
+		Execution: commonproto.WorkflowExecution{
+			WorkflowId: execution.WorkflowID,
+			RunId:      execution.RunID,
+		},
+	}
+	resp, err := executionMgr.GetWorkflowExecution(getRequest)
+	if err != nil {
+		return err
+	}
+	state := resp.State
+	executionInfo := state.ExecutionInfo
+
+	isCurrent := false
+	currentResp, err := executionMgr.GetCurrentExecution(&persistence.GetCurrentExecutionRequest{
+		DomainID:   execution.DomainID,
+		WorkflowID: execution.WorkflowID,
+	})
+	switch err.(type) {
+	case nil:
+		isCurrent = currentResp.RunID == execution.RunID
+	case *serviceerror.NotFound:
+	default:
+		return err
+	}
+
+	childResp, err := childExecutionMgr.GetWorkflowExecution(getRequest)
+	switch err.(type) {
+	case nil:
+		// created by an earlier attempt, which may have failed to write the buffered events
+		return r.moveBufferedEvents(childExecutionMgr, child, state, childResp.State, isCurrent)
+	case *serviceerror.NotFound:
+	default:
+		return err
+	}
+
+	// the history is moved first, so that the copied execution never points to a missing history
+	treeIDs, err := getHistoryTreeIDs(executionInfo, state.VersionHistories)
+	if err != nil {
+		return err
+	}
+	for _, treeID := range treeIDs {
+		if err := r.historyMgr.MoveHistoryTree(&persistence.MoveHistoryTreeRequest{
+			TreeID:        treeID,
+			ShardID:       shardID,
+			TargetShardID: child.shardID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// a closed execution is created as closed, only running executions
+	// which are not the current run of their workflow become zombies
+	createMode := persistence.CreateWorkflowModeBrandNew
+	createExecutionInfo := *executionInfo
+	if !isCurrent {
+		createMode = persistence.CreateWorkflowModeZombie
+		if createExecutionInfo.State != persistence.WorkflowStateCompleted {
+			createExecutionInfo.State = persistence.WorkflowStateZombie
+		}
+	}
+
+	snapshot := newWorkflowSnapshot(state)
+	snapshot.ExecutionInfo = &createExecutionInfo
+	if tasks != nil {
+		if err := child.assignTaskIDs(tasks.transferTasks); err != nil {
+			return err
+		}
+		if err := child.assignTaskIDs(tasks.timerTasks); err != nil {
+			return err
+		}
+		snapshot.TransferTasks = tasks.transferTasks
+		snapshot.TimerTasks = tasks.timerTasks
+	}
+	if _, err := childExecutionMgr.CreateWorkflowExecution(&persistence.CreateWorkflowExecutionRequest{
+		RangeID:                  child.rangeID,
+		Mode:                     createMode,
+		PreviousLastWriteVersion: common.EmptyVersion,
+		NewWorkflowSnapshot:      *snapshot,
+	}); err != nil {
+		return err
+	}
+
+	childState := *state
+	childState.ExecutionInfo = &createExecutionInfo
+	childState.BufferedEvents = nil
+	return r.moveBufferedEvents(childExecutionMgr, child, state, &childState, isCurrent)
+}
+
+// moveBufferedEvents writes the buffered events of the execution into its copy in the child shard,
+// they cannot be written when the execution is created. The copy is compared with the execution,
+// so that the buffered events are written only once.
+func (r *Resharder) moveBufferedEvents(
+	childExecutionMgr persistence.ExecutionManager,
+	child *childShard,
+	state *persistence.WorkflowMutableState,
+	childState *persistence.WorkflowMutableState,
+	isCurrent bool,
+) error {
+
+	if len(state.BufferedEvents) == 0 || len(childState.BufferedEvents) != 0 {
+		return nil
+	}
+
+	updateMode := persistence.UpdateWorkflowModeBypassCurrent
+	if isCurrent {
+		updateMode = persistence.UpdateWorkflowModeUpdateCurrent
+	}
+	childExecutionInfo := childState.ExecutionInfo
+	_, err := childExecutionMgr.UpdateWorkflowExecution(&persistence.UpdateWorkflowExecutionRequest{
+		RangeID: child.rangeID,
+		Mode:    updateMode,
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			ExecutionInfo:     childExecutionInfo,
+			ExecutionStats:    childState.ExecutionStats,
+			ReplicationState:  childState.ReplicationState,
+			VersionHistories:  childState.VersionHistories,
+			NewBufferedEvents: state.BufferedEvents,
+			Condition:         childExecutionInfo.NextEventID,
+		},
+	})
+	return err
+}
+
+func newWorkflowSnapshot(state *persistence.WorkflowMutableState) *persistence.WorkflowSnapshot {
+	snapshot := &persistence.WorkflowSnapshot{
+		ExecutionInfo:    state.ExecutionInfo,
+		ExecutionStats:   state.ExecutionStats,
+		ReplicationState: state.ReplicationState,
+		VersionHistories: state.VersionHistories,
+		Condition:        state.ExecutionInfo.NextEventID,
+	}
+	for _, info := range state.ActivityInfos {
+		snapshot.ActivityInfos = append(snapshot.ActivityInfos, info)
+	}
+	for _, info := range state.TimerInfos {
+		snapshot.TimerInfos = append(snapshot.TimerInfos, info)
+	}
+	for _, info := range state.ChildExecutionInfos {
+		snapshot.ChildExecutionInfos = append(snapshot.ChildExecutionInfos, info)
+	}
+	for _, info := range state.RequestCancelInfos {
+		snapshot.RequestCancelInfos = append(snapshot.RequestCancelInfos, info)
+	}
+	for _, info := range state.SignalInfos {
+		snapshot.SignalInfos = append(snapshot.SignalInfos, info)
+	}
+	for signalRequestedID := range state.SignalRequestedIDs {
+		snapshot.SignalRequestedIDs = append(snapshot.SignalRequestedIDs, signalRequestedID)
+	}
+	return snapshot
+}
+
+// getHistoryTreeIDs returns the history trees of all branches of the execution,
+// a reset execution shares the tree of the execution it was reset from
+func getHistoryTreeIDs(
+	executionInfo *persistence.WorkflowExecutionInfo,
+	versionHistories *persistence.VersionHistories,
+) ([]primitives.UUID, error) {
+
+	branchTokens := [][]byte{executionInfo.BranchToken}
+	if versionHistories != nil {
+		for _, versionHistory := range versionHistories.Histories {
+			branchTokens = append(branchTokens, versionHistory.GetBranchToken())
+		}
+	}
+
+	var treeIDs []primitives.UUID
+	seen := make(map[string]struct{})
+	for _, branchToken := range branchTokens {
+		if len(branchToken) == 0 {
+			continue
+		}
+		branch, err := serialization.HistoryBranchFromBlob(branchToken, common.EncodingTypeProto3.String())
+		if err != nil {
+			return nil, err
+		}
+		treeID := primitives.UUID(branch.GetTreeID())
+		if _, ok := seen[treeID.String()]; ok {
+			continue
+		}
+		seen[treeID.String()] = struct{}{}
+		treeIDs = append(treeIDs, treeID)
+	}
+	return treeIDs, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reshard
+
+import (
+	"context"
+
+	"go.temporal.io/temporal/activity"
+	sdkclient "go.temporal.io/temporal/client"
+	"go.temporal.io/temporal/worker"
+	"go.temporal.io/temporal/workflow"
+
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
+)
+
+type (
+	// BootstrapParams contains the set of params needed to bootstrap
+	// the history reshard sub-system
+	BootstrapParams struct {
+		// ServiceClient is an instance of cadence service client
+		ServiceClient sdkclient.Client
+		// PersistenceBean gives access to the shards and the executions of every shard
+		PersistenceBean persistenceClient.Bean
+		// MetricsClient is an instance of metrics object for emitting stats
+		MetricsClient metrics.Client
+		Logger        log.Logger
+	}
+
+	// Resharder is the background sub-system that executes the workflow splitting the history shards.
+	// It is also the context object that gets passed around within the reshard activities.
+	Resharder struct {
+		svcClient          sdkclient.Client
+		shardMgr           persistence.ShardManager
+		historyMgr         persistence.HistoryManager
+		clusterMetadataMgr persistence.ClusterMetadataManager
+		persistenceBean    persistenceClient.Bean
+		metricsClient      metrics.Client
+		logger             log.Logger
+	}
+)
+
+// New returns a new instance of the Resharder
+func New(params *BootstrapParams) *Resharder {
+	return &Resharder{
+		svcClient:          params.ServiceClient,
+		shardMgr:           params.PersistenceBean.GetShardManager(),
+		historyMgr:         params.PersistenceBean.GetHistoryManager(),
+		clusterMetadataMgr: params.PersistenceBean.GetClusterMetadataManager(),
+		persistenceBean:    params.PersistenceBean,
+		metricsClient:      params.MetricsClient,
+		logger:             params.Logger.WithTags(tag.ComponentResharder),
+	}
+}
+
+// Start starts the worker for the reshard workflow
+func (r *Resharder) Start() error {
+	ctx := context.WithValue(context.Background(), resharderContextKey, r)
+	workerOpts := worker.Options{
+		BackgroundActivityContext: ctx,
+	}
+	reshardWorker := worker.New(r.svcClient, ResharderTaskListName, workerOpts)
+	reshardWorker.RegisterWorkflowWithOptions(ReshardWorkflow, workflow.RegisterOptions{Name: ReshardWFTypeName})
+	reshardWorker.RegisterActivityWithOptions(startReshardActivity, activity.RegisterOptions{Name: startReshardActivityName})
+	reshardWorker.RegisterActivityWithOptions(splitShardActivity, activity.RegisterOptions{Name: splitShardActivityName})
+	reshardWorker.RegisterActivityWithOptions(completeReshardActivity, activity.RegisterOptions{Name: completeReshardActivityName})
+
+	return reshardWorker.Start()
+}
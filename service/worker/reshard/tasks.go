@@ -0,0 +1,285 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reshard
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+type (
+	executionKey struct {
+		domainID   string
+		workflowID string
+		runID      string
+	}
+
+	// executionTasks are the transfer and timer tasks of an execution left in the queues of the split shard
+	executionTasks struct {
+		transferTasks []persistence.Task
+		timerTasks    []persistence.Task
+	}
+)
+
+func newExecutionKey(domainID []byte, workflowID string, runID []byte) executionKey {
+	return executionKey{
+		domainID:   primitives.UUIDString(domainID),
+		workflowID: workflowID,
+		runID:      primitives.UUIDString(runID),
+	}
+}
+
+// loadMovedExecutionTasks reads the transfer and timer queues of the split shard, and returns the tasks
+// of the executions which are moved into the child shards. The shard is frozen, so the queues do not change.
+func (r *Resharder) loadMovedExecutionTasks(request splitShardRequest) (map[executionKey]*executionTasks, error) {
+	executionMgr, err := r.persistenceBean.GetExecutionManager(request.ShardID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[executionKey]*executionTasks)
+	getExecutionTasks := func(domainID []byte, workflowID string, runID []byte) *executionTasks {
+		if request.getChildShardID(workflowID) == request.ShardID {
+			return nil
+		}
+		key := newExecutionKey(domainID, workflowID, runID)
+		if _, ok := tasks[key]; !ok {
+			tasks[key] = &executionTasks{}
+		}
+		return tasks[key]
+	}
+
+	var pageToken []byte
+	for {
+		resp, err := executionMgr.GetTransferTasks(&persistence.GetTransferTasksRequest{
+			ReadLevel:     0,
+			MaxReadLevel:  math.MaxInt64,
+			BatchSize:     pageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range resp.Tasks {
+			executionTasks := getExecutionTasks(info.GetDomainID(), info.GetWorkflowID(), info.GetRunID())
+			if executionTasks == nil {
+				continue
+			}
+			task, err := newTransferTask(info)
+			if err != nil {
+				return nil, err
+			}
+			executionTasks.transferTasks = append(executionTasks.transferTasks, task)
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	pageToken = nil
+	for {
+		resp, err := executionMgr.GetTimerIndexTasks(&persistence.GetTimerIndexTasksRequest{
+			MinTimestamp:  time.Unix(0, 0),
+			MaxTimestamp:  time.Unix(0, math.MaxInt64),
+			BatchSize:     pageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range resp.Timers {
+			executionTasks := getExecutionTasks(info.GetDomainID(), info.GetWorkflowID(), info.GetRunID())
+			if executionTasks == nil {
+				continue
+			}
+			task, err := newTimerTask(info)
+			if err != nil {
+				return nil, err
+			}
+			executionTasks.timerTasks = append(executionTasks.timerTasks, task)
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	return tasks, nil
+}
+
+// newTransferTask converts a persisted transfer task back into the task written by the history service,
+// the task ID is allocated again when the task is written into the child shard
+func newTransferTask(info *pblobs.TransferTaskInfo) (persistence.Task, error) {
+	visibilityTimestamp, err := types.TimestampFromProto(info.GetVisibilityTimestamp())
+	if err != nil {
+		return nil, err
+	}
+	targetDomainID := primitives.UUIDString(info.GetTargetDomainID())
+
+	switch int(info.GetTaskType()) {
+	case persistence.TransferTaskTypeActivityTask:
+		return &persistence.ActivityTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			DomainID:            targetDomainID,
+			TaskList:            info.GetTaskList(),
+			ScheduleID:          info.GetScheduleID(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeDecisionTask:
+		return &persistence.DecisionTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			DomainID:            targetDomainID,
+			TaskList:            info.GetTaskList(),
+			ScheduleID:          info.GetScheduleID(),
+			Version:             info.GetVersion(),
+			RecordVisibility:    info.GetRecordVisibility(),
+		}, nil
+	case persistence.TransferTaskTypeCancelExecution:
+		return &persistence.CancelExecutionTask{
+			VisibilityTimestamp:     visibilityTimestamp,
+			TargetDomainID:          targetDomainID,
+			TargetWorkflowID:        info.GetTargetWorkflowID(),
+			TargetRunID:             primitives.UUIDString(info.GetTargetRunID()),
+			TargetChildWorkflowOnly: info.GetTargetChildWorkflowOnly(),
+			InitiatedID:             info.GetScheduleID(),
+			Version:                 info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeSignalExecution:
+		return &persistence.SignalExecutionTask{
+			VisibilityTimestamp:     visibilityTimestamp,
+			TargetDomainID:          targetDomainID,
+			TargetWorkflowID:        info.GetTargetWorkflowID(),
+			TargetRunID:             primitives.UUIDString(info.GetTargetRunID()),
+			TargetChildWorkflowOnly: info.GetTargetChildWorkflowOnly(),
+			InitiatedID:             info.GetScheduleID(),
+			Version:                 info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeStartChildExecution:
+		return &persistence.StartChildExecutionTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			TargetDomainID:      targetDomainID,
+			TargetWorkflowID:    info.GetTargetWorkflowID(),
+			InitiatedID:         info.GetScheduleID(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeCompletionCallback:
+		return &persistence.CompletionCallbackTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			CallbackID:          info.GetScheduleID(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeHistoryStream:
+		return &persistence.HistoryStreamTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			LastEventID:         info.GetScheduleID(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeCloseExecution:
+		return &persistence.CloseExecutionTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeRecordWorkflowStarted:
+		return &persistence.RecordWorkflowStartedTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeResetWorkflow:
+		return &persistence.ResetWorkflowTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
+		return &persistence.UpsertWorkflowSearchAttributesTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown transfer task type: %v", info.GetTaskType())
+	}
+}
+
+// newTimerTask converts a persisted timer task back into the task written by the history service,
+// the task ID is allocated again when the task is written into the child shard
+func newTimerTask(info *pblobs.TimerTaskInfo) (persistence.Task, error) {
+	visibilityTimestamp, err := types.TimestampFromProto(info.GetVisibilityTimestamp())
+	if err != nil {
+		return nil, err
+	}
+
+	switch int(info.GetTaskType()) {
+	case persistence.TaskTypeDecisionTimeout:
+		return &persistence.DecisionTimeoutTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			EventID:             info.GetEventID(),
+			ScheduleAttempt:     info.GetScheduleAttempt(),
+			TimeoutType:         int(info.GetTimeoutType()),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TaskTypeActivityTimeout:
+		return &persistence.ActivityTimeoutTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			TimeoutType:         int(info.GetTimeoutType()),
+			EventID:             info.GetEventID(),
+			Attempt:             info.GetScheduleAttempt(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TaskTypeUserTimer:
+		return &persistence.UserTimerTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			EventID:             info.GetEventID(),
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TaskTypeActivityRetryTimer:
+		return &persistence.ActivityRetryTimerTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			EventID:             info.GetEventID(),
+			Version:             info.GetVersion(),
+			Attempt:             int32(info.GetScheduleAttempt()),
+		}, nil
+	case persistence.TaskTypeWorkflowBackoffTimer:
+		return &persistence.WorkflowBackoffTimerTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			EventID:             info.GetEventID(),
+			Version:             info.GetVersion(),
+			TimeoutType:         int(info.GetTimeoutType()),
+		}, nil
+	case persistence.TaskTypeWorkflowTimeout:
+		return &persistence.WorkflowTimeoutTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	case persistence.TaskTypeDeleteHistoryEvent:
+		return &persistence.DeleteHistoryEventTask{
+			VisibilityTimestamp: visibilityTimestamp,
+			Version:             info.GetVersion(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown timer task type: %v", info.GetTaskType())
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reshard
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/temporal"
+	"go.temporal.io/temporal/workflow"
+)
+
+const (
+	resharderContextKey = "resharderContext"
+	// ResharderTaskListName is the tasklist name
+	ResharderTaskListName = "cadence-sys-history-reshard-tasklist"
+	// ReshardWFTypeName is the workflow type
+	ReshardWFTypeName = "cadence-sys-history-reshard-workflow"
+	// ReshardWFID is the workflow ID, only one reshard runs at a time
+	ReshardWFID = "cadence-sys-history-reshard"
+	// ReshardStateQueryType is the query type returning the ReshardState of a reshard workflow
+	ReshardStateQueryType = "reshard-state"
+
+	startReshardActivityName    = "cadence-sys-history-reshard-start-activity"
+	splitShardActivityName      = "cadence-sys-history-reshard-split-shard-activity"
+	completeReshardActivityName = "cadence-sys-history-reshard-complete-activity"
+
+	// InfiniteDuration is a long duration(20 yrs) we used for infinite workflow running
+	InfiniteDuration = 20 * 365 * 24 * time.Hour
+
+	// DefaultConcurrency is the default number of shards split in parallel
+	DefaultConcurrency = 1
+	// DefaultActivityHeartBeatTimeout is the default value for ActivityHeartBeatTimeout
+	DefaultActivityHeartBeatTimeout = 30 * time.Second
+
+	// nonRetryableErrReason is the reason of errors which fail the reshard right away
+	nonRetryableErrReason = "cadence-sys-history-reshard-non-retryable-error"
+)
+
+const (
+	// ReshardPhaseStart records the target shard count in the routing
+	ReshardPhaseStart = "start"
+	// ReshardPhaseSplit splits the shards one by one, each shard is unavailable while it is split
+	ReshardPhaseSplit = "split"
+	// ReshardPhaseCompleted means all workflows are routed by the target shard count
+	ReshardPhaseCompleted = "completed"
+)
+
+type (
+	// ReshardParams is the parameters of the reshard workflow
+	ReshardParams struct {
+		// TargetShardCount must be a multiple of the current shard count
+		TargetShardCount int
+
+		// Below are all optional
+		// Number of shards split in parallel. Default to DefaultConcurrency
+		Concurrency int
+		// timeout for activity heartbeat
+		ActivityHeartBeatTimeout time.Duration
+		// State is the checkpoint the reshard continues from, it is taken from the last run
+		// of the workflow when a failed or terminated reshard is started again.
+		State ReshardState
+	}
+
+	// ReshardState is the progress of a reshard
+	ReshardState struct {
+		Phase string
+		// ShardCount is the shard count before the reshard
+		ShardCount int
+		// SplitShardIDs are the shards which are split already
+		SplitShardIDs []int
+		MovedCount    int
+	}
+
+	splitShardRequest struct {
+		ShardID          int
+		ShardCount       int
+		TargetShardCount int
+	}
+
+	// SplitHeartBeatDetails is the struct for heartbeat details of the split shard activity
+	SplitHeartBeatDetails struct {
+		// Number of workflow executions moved to the child shards
+		MovedCount int
+	}
+)
+
+var (
+	reshardActivityRetryPolicy = temporal.RetryPolicy{
+		InitialInterval:          10 * time.Second,
+		BackoffCoefficient:       1.7,
+		MaximumInterval:          5 * time.Minute,
+		ExpirationInterval:       InfiniteDuration,
+		NonRetriableErrorReasons: []string{nonRetryableErrReason},
+	}
+
+	reshardActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		RetryPolicy:            &reshardActivityRetryPolicy,
+	}
+
+	splitShardActivityOptions = workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    InfiniteDuration,
+		RetryPolicy:            &reshardActivityRetryPolicy,
+	}
+)
+
+// ReshardWorkflow splits the history shards online into a multiple of the current shard count.
+// Each shard is split on its own: it is unloaded while the workflows hashing to its children under the
+// target shard count are moved into the child shards together with their tasks, afterwards the shard and
+// its children are routed by the target shard count, while the shards which are not split yet keep being
+// routed by the current one. The shards being split are unavailable, the other shards keep serving during
+// the whole reshard. The replication ack levels of a split shard are not carried over to its children, and
+// components which read the number of shards from the static config, like the history scanner, only use
+// the target shard count once numHistoryShards is updated and they are restarted.
+func ReshardWorkflow(ctx workflow.Context, params ReshardParams) (ReshardState, error) {
+	params = setDefaultParams(params)
+	if err := validateParams(params); err != nil {
+		return ReshardState{}, err
+	}
+	state := params.State
+	if err := workflow.SetQueryHandler(ctx, ReshardStateQueryType, func() (ReshardState, error) {
+		return state, nil
+	}); err != nil {
+		return state, err
+	}
+
+	err := runReshard(ctx, params, &state)
+	return state, err
+}
+
+func runReshard(ctx workflow.Context, params ReshardParams, state *ReshardState) error {
+	opt := workflow.WithActivityOptions(ctx, reshardActivityOptions)
+	splitActivityOptions := splitShardActivityOptions
+	splitActivityOptions.HeartbeatTimeout = params.ActivityHeartBeatTimeout
+	splitOpt := workflow.WithActivityOptions(ctx, splitActivityOptions)
+
+	if state.Phase == "" {
+		state.Phase = ReshardPhaseStart
+	}
+
+	if state.Phase == ReshardPhaseStart {
+		if err := workflow.ExecuteActivity(opt, startReshardActivityName, params).Get(ctx, &state.ShardCount); err != nil {
+			return err
+		}
+		state.Phase = ReshardPhaseSplit
+	}
+
+	if state.Phase == ReshardPhaseSplit {
+		split := make(map[int]struct{}, len(state.SplitShardIDs))
+		for _, shardID := range state.SplitShardIDs {
+			split[shardID] = struct{}{}
+		}
+		var pending []int
+		for shardID := 0; shardID < state.ShardCount; shardID++ {
+			if _, ok := split[shardID]; !ok {
+				pending = append(pending, shardID)
+			}
+		}
+
+		for len(pending) > 0 {
+			batchSize := params.Concurrency
+			if batchSize > len(pending) {
+				batchSize = len(pending)
+			}
+			batch := pending[:batchSize]
+			pending = pending[batchSize:]
+
+			movedCount, err := executeForShards(ctx, splitOpt, splitShardActivityName, batch, state.ShardCount, params.TargetShardCount)
+			if err != nil {
+				return err
+			}
+			state.SplitShardIDs = append(state.SplitShardIDs, batch...)
+			state.MovedCount += movedCount
+		}
+
+		if err := workflow.ExecuteActivity(opt, completeReshardActivityName, params).Get(ctx, nil); err != nil {
+			return err
+		}
+		state.Phase = ReshardPhaseCompleted
+	}
+	return nil
+}
+
+// executeForShards runs the activity for all the shards in parallel, and returns the sum of their results
+func executeForShards(
+	ctx workflow.Context,
+	opt workflow.Context,
+	activityName string,
+	shardIDs []int,
+	shardCount int,
+	targetShardCount int,
+) (int, error) {
+	futures := make([]workflow.Future, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		futures = append(futures, workflow.ExecuteActivity(opt, activityName, splitShardRequest{
+			ShardID:          shardID,
+			ShardCount:       shardCount,
+			TargetShardCount: targetShardCount,
+		}))
+	}
+
+	total := 0
+	for _, future := range futures {
+		var result int
+		if err := future.Get(ctx, &result); err != nil {
+			return total, err
+		}
+		total += result
+	}
+	return total, nil
+}
+
+func validateParams(params ReshardParams) error {
+	if params.TargetShardCount <= 0 {
+		return fmt.Errorf("must provide required parameters: TargetShardCount")
+	}
+	switch params.State.Phase {
+	case "", ReshardPhaseStart, ReshardPhaseSplit:
+		return nil
+	case ReshardPhaseCompleted:
+		return fmt.Errorf("history shards are already split into %v shards", params.TargetShardCount)
+	default:
+		return fmt.Errorf("not supported reshard phase: %v", params.State.Phase)
+	}
+}
+
+func setDefaultParams(params ReshardParams) ReshardParams {
+	if params.Concurrency <= 0 {
+		params.Concurrency = DefaultConcurrency
+	}
+	if params.ActivityHeartBeatTimeout <= 0 {
+		params.ActivityHeartBeatTimeout = DefaultActivityHeartBeatTimeout
+	}
+	return params
+}
@@ -0,0 +1,526 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executions
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/sharding"
+)
+
+type (
+	// ShardChecker checks the invariants of the workflow executions and history branches of a shard.
+	// The checks run while the shard is owned by a history host, so the executions updated after
+	// the tasks of the shard were loaded are skipped, their new tasks may be missing from the loaded
+	// ones. With fix enabled, the executions without history are deleted and so are the orphan
+	// history branches, the other violations are only reported. A violation is only fixed if it
+	// is still there when it is checked again right before the fix.
+	ShardChecker struct {
+		shardID          int
+		shardRouter      sharding.Router
+		executionManager p.ExecutionManager
+		historyManager   p.HistoryManager
+		fix              bool
+		logger           log.Logger
+		// transferTasks and timerTasks are the number of tasks by execution of the task types checked
+		// by the invariants, loaded by LoadTasks
+		transferTasks map[executionKey]map[int]int
+		timerTasks    map[executionKey]map[int]int
+		// tasksLoadedAt is the time LoadTasks started loading the tasks
+		tasksLoadedAt time.Time
+	}
+
+	// ShardReport is the result of checking a shard
+	ShardReport struct {
+		ShardID        int             `json:"shardId"`
+		ExecutionCount int             `json:"executionCount"`
+		BranchCount    int             `json:"branchCount"`
+		Failures       []*CheckFailure `json:"failures"`
+	}
+)
+
+const (
+	listPageSize    = 1000
+	historyPageSize = 100
+	// only history branches older than this threshold are checked, the archiver deletes the
+	// mutable state before the history, same as the history scavenger clean up threshold
+	orphanHistoryMinAge = time.Hour * 24 * common.MaxWorkflowRetentionPeriodInDays * 2
+	// maxClockSkew is the clock skew allowed between the checker and the history hosts
+	// when the last update of an execution is compared with the time its tasks were loaded
+	maxClockSkew = time.Minute
+)
+
+// NewShardChecker returns a checker of the given shard
+func NewShardChecker(
+	shardID int,
+	shardRouter sharding.Router,
+	executionManager p.ExecutionManager,
+	historyManager p.HistoryManager,
+	fix bool,
+	logger log.Logger,
+) *ShardChecker {
+	return &ShardChecker{
+		shardID:          shardID,
+		shardRouter:      shardRouter,
+		executionManager: executionManager,
+		historyManager:   historyManager,
+		fix:              fix,
+		logger:           logger.WithTags(tag.ShardID(shardID)),
+	}
+}
+
+// Run checks every execution of the shard and, if checkHistoryBranches is set,
+// every history branch of the cluster which belongs to the shard
+func (c *ShardChecker) Run(checkHistoryBranches bool) (*ShardReport, error) {
+	report := &ShardReport{ShardID: c.shardID}
+	if err := c.LoadTasks(); err != nil {
+		return nil, err
+	}
+
+	var pageToken []byte
+	for {
+		resp, err := c.executionManager.ListConcreteExecutions(&p.ListConcreteExecutionsRequest{
+			PageSize:      listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Executions {
+			failures, err := c.CheckExecution(e.DomainID, e.WorkflowID, e.RunID)
+			if err != nil {
+				return nil, err
+			}
+			report.ExecutionCount++
+			report.Failures = append(report.Failures, failures...)
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	if !checkHistoryBranches {
+		return report, nil
+	}
+	pageToken = nil
+	for {
+		resp, err := c.historyManager.GetAllHistoryTreeBranches(&p.GetAllHistoryTreeBranchesRequest{
+			PageSize:      listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range resp.Branches {
+			checked, failure, err := c.CheckHistoryBranch(branch)
+			if err != nil {
+				return nil, err
+			}
+			if checked {
+				report.BranchCount++
+			}
+			if failure != nil {
+				report.Failures = append(report.Failures, failure)
+			}
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	return report, nil
+}
+
+// LoadTasks counts the transfer and timer tasks of the shard page by page, it must be called before CheckExecution
+func (c *ShardChecker) LoadTasks() error {
+	c.transferTasks = make(map[executionKey]map[int]int)
+	c.timerTasks = make(map[executionKey]map[int]int)
+	c.tasksLoadedAt = time.Now()
+
+	var pageToken []byte
+	for {
+		resp, err := c.executionManager.GetTransferTasks(&p.GetTransferTasksRequest{
+			ReadLevel:     0,
+			MaxReadLevel:  math.MaxInt64,
+			BatchSize:     listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.Tasks {
+			if _, ok := checkedTransferTaskTypes[int(task.GetTaskType())]; !ok {
+				continue
+			}
+			key := newExecutionKey(task.GetDomainID(), task.GetWorkflowID(), task.GetRunID())
+			countTask(c.transferTasks, key, int(task.GetTaskType()))
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	pageToken = nil
+	for {
+		resp, err := c.executionManager.GetTimerIndexTasks(&p.GetTimerIndexTasksRequest{
+			MinTimestamp:  time.Unix(0, 0),
+			MaxTimestamp:  time.Unix(0, math.MaxInt64),
+			BatchSize:     listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.Timers {
+			if _, ok := checkedTimerTaskTypes[int(task.GetTaskType())]; !ok {
+				continue
+			}
+			key := newExecutionKey(task.GetDomainID(), task.GetWorkflowID(), task.GetRunID())
+			countTask(c.timerTasks, key, int(task.GetTaskType()))
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// CheckExecution checks the invariants of a workflow execution of the shard and returns the violated ones,
+// an execution updated since the tasks were loaded is skipped and has no violations
+func (c *ShardChecker) CheckExecution(domainID, workflowID, runID string) ([]*CheckFailure, error) {
+	resp, err := c.executionManager.GetWorkflowExecution(&p.GetWorkflowExecutionRequest{
+		DomainID:  domainID,
+		Execution: commonproto.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+	})
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			// deleted since it was listed
+			return nil, nil
+		}
+		return nil, err
+	}
+	if resp.State.ExecutionInfo.LastUpdatedTimestamp.After(c.tasksLoadedAt.Add(-maxClockSkew)) {
+		c.logger.Debug("Workflow execution updated since the tasks were loaded, skipped",
+			tag.WorkflowDomainID(domainID), tag.WorkflowID(workflowID), tag.WorkflowRunID(runID))
+		return nil, nil
+	}
+
+	key := executionKey{domainID: domainID, workflowID: workflowID, runID: runID}
+	e := &execution{
+		executionKey:  key,
+		mutableState:  resp.State,
+		transferTasks: c.transferTasks[key],
+		timerTasks:    c.timerTasks[key],
+	}
+
+	current, err := c.executionManager.GetCurrentExecution(&p.GetCurrentExecutionRequest{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+	})
+	if err == nil {
+		e.currentRunID = current.RunID
+		e.currentState = current.State
+	} else if _, ok := err.(*serviceerror.NotFound); !ok {
+		return nil, err
+	}
+
+	branchToken, err := getBranchToken(resp.State)
+	if err != nil {
+		return nil, err
+	}
+	if e.lastEventID, err = c.getLastEventID(branchToken); err != nil {
+		return nil, err
+	}
+
+	failures := checkExecution(e)
+	for _, failure := range failures {
+		c.logger.Warn("Workflow execution invariant violated", getFailureLoggingTags(failure)...)
+		if c.fix && failure.Invariant == InvariantHistoryExists {
+			missing, err := c.isHistoryMissing(e, branchToken)
+			if err != nil {
+				c.logger.Warn("Unable to check the workflow execution again, not deleted", append(getFailureLoggingTags(failure), tag.Error(err))...)
+				continue
+			}
+			if !missing {
+				c.logger.Info("Workflow execution has history now, not deleted", getFailureLoggingTags(failure)...)
+				continue
+			}
+			if err := c.deleteExecution(e); err != nil {
+				return nil, err
+			}
+			failure.Fixed = true
+			c.logger.Info("Deleted workflow execution without history", getFailureLoggingTags(failure)...)
+		}
+	}
+	return failures, nil
+}
+
+// isHistoryMissing checks the execution again before it is deleted. The history is only missing
+// if the execution is unchanged and reading the first event of its branch returns not found,
+// an empty or failed read is never taken as missing history.
+func (c *ShardChecker) isHistoryMissing(e *execution, branchToken []byte) (bool, error) {
+	resp, err := c.executionManager.GetWorkflowExecution(&p.GetWorkflowExecutionRequest{
+		DomainID:  e.domainID,
+		Execution: commonproto.WorkflowExecution{WorkflowId: e.workflowID, RunId: e.runID},
+	})
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			// deleted since it was checked
+			return false, nil
+		}
+		return false, err
+	}
+	currentBranchToken, err := getBranchToken(resp.State)
+	if err != nil {
+		return false, err
+	}
+	if resp.State.ExecutionInfo.NextEventID != e.mutableState.ExecutionInfo.NextEventID ||
+		!bytes.Equal(currentBranchToken, branchToken) {
+		return false, nil
+	}
+
+	_, err = c.historyManager.ReadHistoryBranch(&p.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  common.EndEventID,
+		PageSize:    1,
+		ShardID:     common.IntPtr(c.shardID),
+	})
+	if err == nil {
+		return false, nil
+	}
+	if _, ok := err.(*serviceerror.NotFound); ok {
+		return true, nil
+	}
+	return false, err
+}
+
+// CheckHistoryBranch checks that a history branch is referenced by its workflow execution if the
+// workflow execution belongs to the shard, checked is false if the branch is not checked
+func (c *ShardChecker) CheckHistoryBranch(branch p.HistoryBranchDetail) (checked bool, failure *CheckFailure, err error) {
+	domainID, workflowID, runID, err := p.SplitHistoryGarbageCleanupInfo(branch.Info)
+	if err != nil {
+		c.logger.Error("Unable to parse the history branch info", tag.DetailInfo(branch.Info))
+		return false, nil, nil
+	}
+	if c.shardRouter.GetShardID(workflowID) != c.shardID || time.Since(branch.ForkTime) < orphanHistoryMinAge {
+		return false, nil, nil
+	}
+
+	failure = &CheckFailure{
+		DomainID:   domainID,
+		WorkflowID: workflowID,
+		RunID:      runID,
+		TreeID:     branch.TreeID,
+		BranchID:   branch.BranchID,
+		Invariant:  InvariantOrphanHistory,
+	}
+	resp, err := c.executionManager.GetWorkflowExecution(&p.GetWorkflowExecutionRequest{
+		DomainID:  domainID,
+		Execution: commonproto.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+	})
+	switch err.(type) {
+	case nil:
+		referenced, err := isBranchReferenced(resp.State, branch.BranchID)
+		if err != nil || referenced {
+			return true, nil, err
+		}
+		failure.Details = "history branch is not referenced by the workflow execution"
+	case *serviceerror.NotFound:
+		failure.Details = "workflow execution of the history branch doesn't exist"
+	default:
+		return false, nil, err
+	}
+
+	c.logger.Warn("History branch invariant violated", getFailureLoggingTags(failure)...)
+	if c.fix {
+		orphan, err := c.isBranchOrphan(domainID, workflowID, runID, branch.BranchID)
+		if err != nil {
+			c.logger.Warn("Unable to check the history branch again, not deleted", append(getFailureLoggingTags(failure), tag.Error(err))...)
+			return true, failure, nil
+		}
+		if !orphan {
+			c.logger.Info("History branch is referenced now, not deleted", getFailureLoggingTags(failure)...)
+			return true, failure, nil
+		}
+		branchToken, err := p.NewHistoryBranchTokenByBranchID(
+			primitives.MustParseUUID(branch.TreeID),
+			primitives.MustParseUUID(branch.BranchID))
+		if err != nil {
+			return true, nil, err
+		}
+		if err := c.historyManager.DeleteHistoryBranch(&p.DeleteHistoryBranchRequest{
+			BranchToken: branchToken,
+			ShardID:     common.IntPtr(c.shardID),
+		}); err != nil {
+			return true, nil, err
+		}
+		failure.Fixed = true
+		c.logger.Info("Deleted orphan history branch", getFailureLoggingTags(failure)...)
+	}
+	return true, failure, nil
+}
+
+// isBranchOrphan checks again that the history branch is not referenced by its workflow execution
+func (c *ShardChecker) isBranchOrphan(domainID, workflowID, runID, branchID string) (bool, error) {
+	resp, err := c.executionManager.GetWorkflowExecution(&p.GetWorkflowExecutionRequest{
+		DomainID:  domainID,
+		Execution: commonproto.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+	})
+	switch err.(type) {
+	case nil:
+		referenced, err := isBranchReferenced(resp.State, branchID)
+		return !referenced, err
+	case *serviceerror.NotFound:
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// getLastEventID returns the ID of the last event of the branch, 0 if the branch has no events
+func (c *ShardChecker) getLastEventID(branchToken []byte) (int64, error) {
+	var lastEventID int64
+	req := &p.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  common.EndEventID,
+		PageSize:    historyPageSize,
+		ShardID:     common.IntPtr(c.shardID),
+	}
+	for {
+		resp, err := c.historyManager.ReadHistoryBranch(req)
+		if err != nil {
+			if _, ok := err.(*serviceerror.NotFound); ok {
+				return lastEventID, nil
+			}
+			return 0, err
+		}
+		if len(resp.HistoryEvents) > 0 {
+			lastEventID = resp.HistoryEvents[len(resp.HistoryEvents)-1].GetEventId()
+		}
+		req.NextPageToken = resp.NextPageToken
+		if len(req.NextPageToken) == 0 {
+			return lastEventID, nil
+		}
+	}
+}
+
+// deleteExecution deletes the execution and the current execution if it points to the execution
+func (c *ShardChecker) deleteExecution(e *execution) error {
+	if err := c.executionManager.DeleteWorkflowExecution(&p.DeleteWorkflowExecutionRequest{
+		DomainID:   e.domainID,
+		WorkflowID: e.workflowID,
+		RunID:      e.runID,
+	}); err != nil {
+		return err
+	}
+	if e.currentRunID != e.runID {
+		return nil
+	}
+	return c.executionManager.DeleteCurrentWorkflowExecution(&p.DeleteCurrentWorkflowExecutionRequest{
+		DomainID:   e.domainID,
+		WorkflowID: e.workflowID,
+		RunID:      e.runID,
+	})
+}
+
+// getBranchToken returns the branch token of the current history branch of the execution
+func getBranchToken(ms *p.WorkflowMutableState) ([]byte, error) {
+	if ms.VersionHistories == nil {
+		return ms.ExecutionInfo.BranchToken, nil
+	}
+	versionHistory, err := ms.VersionHistories.GetCurrentVersionHistory()
+	if err != nil {
+		return nil, err
+	}
+	return versionHistory.GetBranchToken(), nil
+}
+
+// isBranchReferenced returns true if any version history of the execution is on the branch
+func isBranchReferenced(ms *p.WorkflowMutableState, branchID string) (bool, error) {
+	branchTokens := [][]byte{ms.ExecutionInfo.BranchToken}
+	if ms.VersionHistories != nil {
+		branchTokens = nil
+		for _, versionHistory := range ms.VersionHistories.Histories {
+			branchTokens = append(branchTokens, versionHistory.GetBranchToken())
+		}
+	}
+	for _, branchToken := range branchTokens {
+		branch, err := serialization.HistoryBranchFromBlob(branchToken, common.EncodingTypeProto3.String())
+		if err != nil {
+			return false, err
+		}
+		if primitives.UUIDString(branch.GetBranchID()) == branchID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newExecutionKey(domainID []byte, workflowID string, runID []byte) executionKey {
+	return executionKey{
+		domainID:   primitives.UUIDString(domainID),
+		workflowID: workflowID,
+		runID:      primitives.UUIDString(runID),
+	}
+}
+
+func countTask(tasks map[executionKey]map[int]int, key executionKey, taskType int) {
+	if tasks[key] == nil {
+		tasks[key] = make(map[int]int)
+	}
+	tasks[key][taskType]++
+}
+
+func getFailureLoggingTags(failure *CheckFailure) []tag.Tag {
+	tags := []tag.Tag{
+		tag.WorkflowDomainID(failure.DomainID),
+		tag.WorkflowID(failure.WorkflowID),
+		tag.WorkflowRunID(failure.RunID),
+		tag.Value(failure.Invariant),
+		tag.DetailInfo(failure.Details),
+	}
+	if failure.BranchID != "" {
+		tags = append(tags, tag.WorkflowTreeID(failure.TreeID), tag.WorkflowBranchID(failure.BranchID))
+	}
+	return tags
+}
+
+// String returns the failure in a single line
+func (f *CheckFailure) String() string {
+	return fmt.Sprintf("%v %v/%v/%v: %v", f.Invariant, f.DomainID, f.WorkflowID, f.RunID, f.Details)
+}
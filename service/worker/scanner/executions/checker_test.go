@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/log/loggerimpl"
+	"github.com/temporalio/temporal/common/mocks"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/sharding"
+)
+
+type (
+	checkerSuite struct {
+		suite.Suite
+		mockExecutionManager *mocks.ExecutionManager
+		mockHistoryManager   *mocks.HistoryV2Manager
+		checker              *ShardChecker
+	}
+)
+
+const (
+	testDomainID   = "deadbeef-0123-4567-890a-bcdef0123456"
+	testWorkflowID = "test-workflow-id"
+	testRunID      = "0d00698f-08e1-4d36-a3e2-3bf109f5d2d6"
+)
+
+func TestCheckerSuite(t *testing.T) {
+	suite.Run(t, new(checkerSuite))
+}
+
+func (s *checkerSuite) SetupTest() {
+	s.mockExecutionManager = &mocks.ExecutionManager{}
+	s.mockHistoryManager = &mocks.HistoryV2Manager{}
+	s.checker = NewShardChecker(0, sharding.NewStaticRouter(1), s.mockExecutionManager, s.mockHistoryManager, true, loggerimpl.NewNopLogger())
+	s.checker.tasksLoadedAt = time.Now()
+
+	s.mockExecutionManager.On("GetCurrentExecution", mock.Anything).Return(nil, serviceerror.NewNotFound("")).Maybe()
+}
+
+func (s *checkerSuite) TearDownTest() {
+	s.mockExecutionManager.AssertExpectations(s.T())
+	s.mockHistoryManager.AssertExpectations(s.T())
+}
+
+func (s *checkerSuite) TestCheckExecution_FixConfirmedMissingHistory() {
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(s.newGetWorkflowExecutionResponse(5), nil).Twice()
+	s.mockHistoryManager.On("ReadHistoryBranch", mock.Anything).Return(nil, serviceerror.NewNotFound("")).Twice()
+	s.mockExecutionManager.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	failures, err := s.checker.CheckExecution(testDomainID, testWorkflowID, testRunID)
+	s.NoError(err)
+	s.Len(failures, 1)
+	s.Equal(InvariantHistoryExists, failures[0].Invariant)
+	s.True(failures[0].Fixed)
+}
+
+func (s *checkerSuite) TestCheckExecution_NoFixOnFailedHistoryRead() {
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(s.newGetWorkflowExecutionResponse(5), nil).Twice()
+	s.mockHistoryManager.On("ReadHistoryBranch", mock.Anything).Return(nil, serviceerror.NewNotFound("")).Once()
+	s.mockHistoryManager.On("ReadHistoryBranch", mock.Anything).Return(nil, serviceerror.NewInternal("")).Once()
+
+	failures, err := s.checker.CheckExecution(testDomainID, testWorkflowID, testRunID)
+	s.NoError(err)
+	s.Len(failures, 1)
+	s.False(failures[0].Fixed)
+}
+
+func (s *checkerSuite) TestCheckExecution_NoFixOnEmptyHistoryRead() {
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(s.newGetWorkflowExecutionResponse(5), nil).Twice()
+	s.mockHistoryManager.On("ReadHistoryBranch", mock.Anything).Return(&p.ReadHistoryBranchResponse{}, nil).Twice()
+
+	failures, err := s.checker.CheckExecution(testDomainID, testWorkflowID, testRunID)
+	s.NoError(err)
+	s.Len(failures, 1)
+	s.Equal(InvariantHistoryExists, failures[0].Invariant)
+	s.False(failures[0].Fixed)
+}
+
+func (s *checkerSuite) TestCheckExecution_NoFixIfExecutionChanged() {
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(s.newGetWorkflowExecutionResponse(5), nil).Once()
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(s.newGetWorkflowExecutionResponse(7), nil).Once()
+	s.mockHistoryManager.On("ReadHistoryBranch", mock.Anything).Return(nil, serviceerror.NewNotFound("")).Once()
+
+	failures, err := s.checker.CheckExecution(testDomainID, testWorkflowID, testRunID)
+	s.NoError(err)
+	s.Len(failures, 1)
+	s.False(failures[0].Fixed)
+}
+
+func (s *checkerSuite) TestCheckExecution_SkipUpdatedSinceTasksLoaded() {
+	resp := s.newGetWorkflowExecutionResponse(5)
+	resp.State.ExecutionInfo.LastUpdatedTimestamp = s.checker.tasksLoadedAt
+	resp.State.ActivityInfos = map[int64]*p.ActivityInfo{
+		5: {ScheduleID: 5, StartedID: common.EmptyEventID},
+	}
+	s.mockExecutionManager.On("GetWorkflowExecution", mock.Anything).Return(resp, nil).Once()
+
+	failures, err := s.checker.CheckExecution(testDomainID, testWorkflowID, testRunID)
+	s.NoError(err)
+	s.Empty(failures)
+}
+
+func (s *checkerSuite) newGetWorkflowExecutionResponse(nextEventID int64) *p.GetWorkflowExecutionResponse {
+	return &p.GetWorkflowExecutionResponse{
+		State: &p.WorkflowMutableState{
+			ExecutionInfo: &p.WorkflowExecutionInfo{
+				DomainID:             testDomainID,
+				WorkflowID:           testWorkflowID,
+				RunID:                testRunID,
+				BranchToken:          []byte("branch-token"),
+				NextEventID:          nextEventID,
+				State:                p.WorkflowStateCompleted,
+				LastUpdatedTimestamp: s.checker.tasksLoadedAt.Add(-time.Hour),
+			},
+		},
+	}
+}
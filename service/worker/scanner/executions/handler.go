@@ -22,7 +22,12 @@
 
 package executions
 
-import "github.com/temporalio/temporal/service/worker/scanner/executor"
+import (
+	"sync/atomic"
+
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/service/worker/scanner/executor"
+)
 
 type handlerStatus = executor.TaskStatus
 
@@ -37,9 +42,20 @@ const scannerTaskListPrefix = "cadence-sys-executions-scanner"
 // validateHandler validates a single execution.
 // It operates in two phases: collection step and validation step.
 // During collection step information from persistence is read for this workflow execution.
-// During validation step invariants are asserted over everything that was read.
-// In the future its possible to add a third step here which will additionally take automatic recovery actions if validation failed.
-func (s *Scavenger) validateHandler(key *executionKey) handlerStatus {
-	// TODO: implement this
+// During validation step invariants are asserted over everything that was read, and
+// executions without history are deleted when the scavenger runs with fix enabled.
+func (s *Scavenger) validateHandler(checker *ShardChecker, key *executionKey) handlerStatus {
+	failures, err := checker.CheckExecution(key.domainID, key.workflowID, key.runID)
+	if err != nil {
+		s.logger.Error("Failed to check workflow execution",
+			tag.WorkflowDomainID(key.domainID),
+			tag.WorkflowID(key.workflowID),
+			tag.WorkflowRunID(key.runID),
+			tag.Error(err))
+		atomic.AddInt64(&s.stats.nErrors, 1)
+		return handlerStatusErr
+	}
+	atomic.AddInt64(&s.stats.nChecked, 1)
+	s.recordFailures(failures)
 	return handlerStatusDone
 }
@@ -0,0 +1,169 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executions
+
+import (
+	"fmt"
+
+	"github.com/temporalio/temporal/common"
+	p "github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	// InvariantType is the name of an invariant of workflow executions and history branches
+	InvariantType string
+
+	// CheckFailure is an invariant violated by a workflow execution or a history branch
+	CheckFailure struct {
+		DomainID   string        `json:"domainId"`
+		WorkflowID string        `json:"workflowId"`
+		RunID      string        `json:"runId"`
+		TreeID     string        `json:"treeId,omitempty"`
+		BranchID   string        `json:"branchId,omitempty"`
+		Invariant  InvariantType `json:"invariant"`
+		Details    string        `json:"details"`
+		Fixed      bool          `json:"fixed"`
+	}
+
+	// execution is everything loaded from persistence to check a workflow execution
+	execution struct {
+		executionKey
+		mutableState *p.WorkflowMutableState
+		// currentRunID is empty if there is no current execution
+		currentRunID string
+		currentState int
+		// lastEventID is the ID of the last event of the current history branch, 0 if the branch is empty
+		lastEventID int64
+		// transferTasks and timerTasks are the number of tasks of the execution by task type
+		transferTasks map[int]int
+		timerTasks    map[int]int
+	}
+)
+
+const (
+	// InvariantHistoryExists is violated if the current history branch of an execution has no events
+	InvariantHistoryExists InvariantType = "history_exists"
+	// InvariantNextEventID is violated if the next event ID of an execution doesn't follow the last history event
+	InvariantNextEventID InvariantType = "next_event_id"
+	// InvariantActivityTasks is violated if an execution has pending activities but no activity task
+	InvariantActivityTasks InvariantType = "activity_tasks"
+	// InvariantTimerTasks is violated if an execution has pending user timers but no user timer task
+	InvariantTimerTasks InvariantType = "timer_tasks"
+	// InvariantCurrentExecution is violated if the current execution row doesn't agree with the execution
+	InvariantCurrentExecution InvariantType = "current_execution"
+	// InvariantOrphanHistory is violated if a history branch is not referenced by its workflow execution
+	InvariantOrphanHistory InvariantType = "orphan_history"
+)
+
+var (
+	// checkedTransferTaskTypes and checkedTimerTaskTypes are the task types used by the invariants,
+	// the other tasks are not loaded by the checker
+	checkedTransferTaskTypes = map[int]struct{}{p.TransferTaskTypeActivityTask: {}}
+	checkedTimerTaskTypes    = map[int]struct{}{p.TaskTypeActivityTimeout: {}, p.TaskTypeUserTimer: {}}
+)
+
+// timerTaskStatusNone is the task status of timers and activities without timer task,
+// it must be the same as TimerTaskStatusNone of the history service
+const timerTaskStatusNone = 0
+
+// checkExecution returns the invariants violated by the execution
+func checkExecution(e *execution) []*CheckFailure {
+	var failures []*CheckFailure
+	for _, check := range []func(*execution) *CheckFailure{
+		checkHistory,
+		checkActivityTasks,
+		checkTimerTasks,
+		checkCurrentExecution,
+	} {
+		if failure := check(e); failure != nil {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+func checkHistory(e *execution) *CheckFailure {
+	if e.lastEventID == 0 {
+		return e.newFailure(InvariantHistoryExists, "history branch has no events")
+	}
+	nextEventID := e.mutableState.ExecutionInfo.NextEventID
+	if e.lastEventID != nextEventID-1 {
+		return e.newFailure(InvariantNextEventID,
+			fmt.Sprintf("next event ID %v doesn't follow the last event ID %v", nextEventID, e.lastEventID))
+	}
+	return nil
+}
+
+func checkActivityTasks(e *execution) *CheckFailure {
+	if e.transferTasks[p.TransferTaskTypeActivityTask] > 0 || e.timerTasks[p.TaskTypeActivityTimeout] > 0 {
+		return nil
+	}
+	for _, ai := range e.mutableState.ActivityInfos {
+		if ai.TimerTaskStatus != timerTaskStatusNone {
+			return e.newFailure(InvariantActivityTasks,
+				fmt.Sprintf("activity %v has a timer task status but there is no activity timeout task", ai.ScheduleID))
+		}
+		if ai.StartedID == common.EmptyEventID {
+			return e.newFailure(InvariantActivityTasks,
+				fmt.Sprintf("activity %v is not started but there is no activity task", ai.ScheduleID))
+		}
+	}
+	return nil
+}
+
+func checkTimerTasks(e *execution) *CheckFailure {
+	if e.timerTasks[p.TaskTypeUserTimer] > 0 {
+		return nil
+	}
+	for _, ti := range e.mutableState.TimerInfos {
+		if ti.GetTaskStatus() != timerTaskStatusNone {
+			return e.newFailure(InvariantTimerTasks,
+				fmt.Sprintf("timer %v has a timer task status but there is no user timer task", ti.GetTimerID()))
+		}
+	}
+	return nil
+}
+
+func checkCurrentExecution(e *execution) *CheckFailure {
+	state := e.mutableState.ExecutionInfo.State
+	if e.currentRunID == e.runID {
+		if e.currentState != state {
+			return e.newFailure(InvariantCurrentExecution,
+				fmt.Sprintf("current execution state %v doesn't match execution state %v", e.currentState, state))
+		}
+		return nil
+	}
+	if state == p.WorkflowStateCreated || state == p.WorkflowStateRunning {
+		return e.newFailure(InvariantCurrentExecution,
+			fmt.Sprintf("execution is running but the current run is %q", e.currentRunID))
+	}
+	return nil
+}
+
+func (e *execution) newFailure(invariant InvariantType, details string) *CheckFailure {
+	return &CheckFailure{
+		DomainID:   e.domainID,
+		WorkflowID: e.workflowID,
+		RunID:      e.runID,
+		Invariant:  invariant,
+		Details:    details,
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	p "github.com/temporalio/temporal/common/persistence"
+)
+
+type (
+	InvariantsTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestInvariantsTestSuite(t *testing.T) {
+	suite.Run(t, new(InvariantsTestSuite))
+}
+
+func (s *InvariantsTestSuite) TestValidExecution() {
+	e := s.newExecution()
+	e.mutableState.ActivityInfos[5] = &p.ActivityInfo{ScheduleID: 5, StartedID: common.EmptyEventID, TimerTaskStatus: 1}
+	e.mutableState.TimerInfos["t1"] = &pblobs.TimerInfo{TimerID: "t1", TaskStatus: 1}
+	e.transferTasks[p.TransferTaskTypeActivityTask] = 1
+	e.timerTasks[p.TaskTypeActivityTimeout] = 1
+	e.timerTasks[p.TaskTypeUserTimer] = 1
+	s.Empty(checkExecution(e))
+}
+
+func (s *InvariantsTestSuite) TestHistoryExists() {
+	e := s.newExecution()
+	e.lastEventID = 0
+	s.assertFailures(e, InvariantHistoryExists)
+}
+
+func (s *InvariantsTestSuite) TestNextEventID() {
+	e := s.newExecution()
+	e.lastEventID = 5
+	s.assertFailures(e, InvariantNextEventID)
+}
+
+func (s *InvariantsTestSuite) TestActivityTasks() {
+	e := s.newExecution()
+	e.mutableState.ActivityInfos[5] = &p.ActivityInfo{ScheduleID: 5, StartedID: common.EmptyEventID}
+	s.assertFailures(e, InvariantActivityTasks)
+
+	e = s.newExecution()
+	e.mutableState.ActivityInfos[5] = &p.ActivityInfo{ScheduleID: 5, StartedID: 6, TimerTaskStatus: 1}
+	s.assertFailures(e, InvariantActivityTasks)
+
+	e = s.newExecution()
+	e.mutableState.ActivityInfos[5] = &p.ActivityInfo{ScheduleID: 5, StartedID: 6}
+	s.Empty(checkExecution(e))
+}
+
+func (s *InvariantsTestSuite) TestTimerTasks() {
+	e := s.newExecution()
+	e.mutableState.TimerInfos["t1"] = &pblobs.TimerInfo{TimerID: "t1", TaskStatus: 1}
+	s.assertFailures(e, InvariantTimerTasks)
+}
+
+func (s *InvariantsTestSuite) TestCurrentExecution() {
+	e := s.newExecution()
+	e.currentState = p.WorkflowStateCompleted
+	s.assertFailures(e, InvariantCurrentExecution)
+
+	e = s.newExecution()
+	e.currentRunID = "other-run"
+	s.assertFailures(e, InvariantCurrentExecution)
+
+	e = s.newExecution()
+	e.currentRunID = "other-run"
+	e.mutableState.ExecutionInfo.State = p.WorkflowStateCompleted
+	s.Empty(checkExecution(e))
+}
+
+func (s *InvariantsTestSuite) assertFailures(e *execution, expected ...InvariantType) {
+	failures := checkExecution(e)
+	s.Len(failures, len(expected))
+	for i, failure := range failures {
+		s.Equal(expected[i], failure.Invariant)
+		s.Equal(e.domainID, failure.DomainID)
+		s.Equal(e.workflowID, failure.WorkflowID)
+		s.Equal(e.runID, failure.RunID)
+	}
+}
+
+func (s *InvariantsTestSuite) newExecution() *execution {
+	return &execution{
+		executionKey: executionKey{
+			domainID:   "domain-id",
+			workflowID: "workflow-id",
+			runID:      "run-id",
+		},
+		mutableState: &p.WorkflowMutableState{
+			ActivityInfos: make(map[int64]*p.ActivityInfo),
+			TimerInfos:    make(map[string]*pblobs.TimerInfo),
+			ExecutionInfo: &p.WorkflowExecutionInfo{
+				State:       p.WorkflowStateRunning,
+				NextEventID: 11,
+			},
+		},
+		currentRunID:  "run-id",
+		currentState:  p.WorkflowStateRunning,
+		lastEventID:   10,
+		transferTasks: make(map[int]int),
+		timerTasks:    make(map[int]int),
+	}
+}
@@ -27,38 +27,39 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/temporalio/temporal/client/frontend"
-
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
 	"github.com/temporalio/temporal/common/metrics"
 	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/sharding"
 	"github.com/temporalio/temporal/service/worker/scanner/executor"
 )
 
-type ()
-
 type (
 	// Scavenger is the type that holds the state for executions scavenger daemon
 	Scavenger struct {
-		params ScannerWorkflowParams
-		// TODO: currently frontend client does not support scan visibility records without domain filter (need to chat with Bowei to understand if querying without domain filter is an issue)? Maybe we go directly to elasticSearch?
-		frontendClient frontend.Client // used to query visibility
-		historyDB      p.HistoryManager
-		executor       executor.Executor
-		metrics        metrics.Client
-		logger         log.Logger
-		stats          stats
-		status         int32
-		stopC          chan struct{}
-		stopWG         sync.WaitGroup
+		params              ScannerWorkflowParams
+		shardRouter         sharding.Router
+		getExecutionManager func(shardID int) (p.ExecutionManager, error)
+		historyDB           p.HistoryManager
+		executor            executor.Executor
+		metrics             metrics.Client
+		logger              log.Logger
+		stats               stats
+		status              int32
+		stopC               chan struct{}
+		stopWG              sync.WaitGroup
 	}
 
 	// ScannerWorkflowParams are the parameters passed to the executions scanner workflow
 	ScannerWorkflowParams struct {
-		VisibilityQuery string // optionally can be provided to limit the scope of the scan
-
-		// add other fields here such as: bool generateReport, string outputLocation, bool runInDryMode etc...
+		// ShardIDs limits the scan to the given shards, all shards are scanned if empty
+		ShardIDs []int
+		// CheckHistoryBranches enables the orphan history check, which reads every history branch of the cluster
+		CheckHistoryBranches bool
+		// Fix deletes the executions without history and the orphan history branches
+		Fix bool
 	}
 
 	executionKey struct {
@@ -68,20 +69,23 @@ type (
 	}
 
 	stats struct {
-		// TODO: include stats here that should be tracked throughout execution of scavenger
+		nChecked   int64
+		nCorrupted int64
+		nFixed     int64
+		nErrors    int64
 	}
 
 	// executorTask is a runnable task that adheres to the executor.Task interface
 	// for the scavenger, each of this task processes a single workflow execution
 	executorTask struct {
 		executionKey
-		scvg *Scavenger
+		checker *ShardChecker
+		scvg    *Scavenger
 	}
 )
 
 var (
-	executionsBatchSize      = 32   // maximum number of executions we process concurrently
-	executionsPageSize       = 1000 // page size of executions read from visibility manager
+	executionsBatchSize      = 32 // maximum number of executions we process concurrently
 	executorPollInterval     = time.Minute
 	executorMaxDeferredTasks = 10000
 )
@@ -89,16 +93,16 @@ var (
 // NewScavenger returns an instance of executions scavenger daemon
 // The Scavenger can be started by calling the Start() method on the
 // returned object. Calling the Start() method will result in one
-// complete iteration over all of the open workflow executions in the system. For
-// each executions, will attempt to validate the workflow execution and emit metrics/logs on validation failures.
+// complete iteration over all of the workflow executions of the shards. For
+// each execution, will attempt to validate the workflow execution and emit metrics/logs on validation failures.
 //
-// The scavenger will retry on all persistence errors infinitely and will only stop under
-// two conditions
-//  - either all executions are processed successfully (or)
+// The scavenger will stop under two conditions
+//  - either all executions are processed (or)
 //  - Stop() method is called to stop the scavenger
 func NewScavenger(
 	params ScannerWorkflowParams,
-	frontendClient frontend.Client,
+	shardRouter sharding.Router,
+	getExecutionManager func(shardID int) (p.ExecutionManager, error),
 	historyDB p.HistoryManager,
 	metricsClient metrics.Client,
 	logger log.Logger,
@@ -107,13 +111,14 @@ func NewScavenger(
 	taskExecutor := executor.NewFixedSizePoolExecutor(
 		executionsBatchSize, executorMaxDeferredTasks, metricsClient, metrics.ExecutionsScavengerScope)
 	return &Scavenger{
-		params:         params,
-		frontendClient: frontendClient,
-		historyDB:      historyDB,
-		metrics:        metricsClient,
-		logger:         logger,
-		stopC:          stopC,
-		executor:       taskExecutor,
+		params:              params,
+		shardRouter:         shardRouter,
+		getExecutionManager: getExecutionManager,
+		historyDB:           historyDB,
+		metrics:             metricsClient,
+		logger:              logger,
+		stopC:               stopC,
+		executor:            taskExecutor,
 	}
 }
 
@@ -148,14 +153,123 @@ func (s *Scavenger) Alive() bool {
 	return atomic.LoadInt32(&s.status) == common.DaemonStatusStarted
 }
 
-// run does a single run over all executions and validates them
+// run does a single run over all executions of the shards and validates them
 func (s *Scavenger) run() {
-	// TODO: implement this
-	// 1. read from visibility records from frontend.Client
-	// 2. create executionTasks
-	// 3. pass them off to the executor to run
-	// 4. wait until the executor is done
-	// 5. emit metrics on the run of scavenger
+	defer func() {
+		s.emitStats()
+		go s.Stop()
+		s.stopWG.Done()
+	}()
+
+	for _, shardID := range s.getShardIDs() {
+		checker, err := s.newShardChecker(shardID)
+		if err != nil {
+			s.logger.Error("Failed to create shard checker", tag.ShardID(shardID), tag.Error(err))
+			atomic.AddInt64(&s.stats.nErrors, 1)
+			continue
+		}
+		if !s.scanShard(checker) {
+			return
+		}
+	}
+	if s.params.CheckHistoryBranches {
+		s.scanHistoryBranches()
+	}
+}
+
+// scanShard validates all executions of a shard, returns false if the scavenger is stopped
+func (s *Scavenger) scanShard(checker *ShardChecker) bool {
+	if err := checker.LoadTasks(); err != nil {
+		s.logger.Error("Failed to load shard tasks", tag.ShardID(checker.shardID), tag.Error(err))
+		atomic.AddInt64(&s.stats.nErrors, 1)
+		return true
+	}
+
+	var pageToken []byte
+	for {
+		resp, err := checker.executionManager.ListConcreteExecutions(&p.ListConcreteExecutionsRequest{
+			PageSize:      listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			s.logger.Error("Failed to list shard executions", tag.ShardID(checker.shardID), tag.Error(err))
+			atomic.AddInt64(&s.stats.nErrors, 1)
+			break
+		}
+		for _, e := range resp.Executions {
+			if !s.executor.Submit(s.newTask(checker, e)) {
+				return false
+			}
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	s.awaitExecutor()
+	return s.Alive()
+}
+
+// scanHistoryBranches validates the history branches of the shards
+func (s *Scavenger) scanHistoryBranches() {
+	shardIDs := make(map[int]struct{})
+	for _, shardID := range s.getShardIDs() {
+		shardIDs[shardID] = struct{}{}
+	}
+	checkers := make(map[int]*ShardChecker)
+
+	var pageToken []byte
+	for {
+		resp, err := s.historyDB.GetAllHistoryTreeBranches(&p.GetAllHistoryTreeBranchesRequest{
+			PageSize:      listPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			s.logger.Error("Failed to list history branches", tag.Error(err))
+			atomic.AddInt64(&s.stats.nErrors, 1)
+			return
+		}
+		for _, branch := range resp.Branches {
+			_, workflowID, _, err := p.SplitHistoryGarbageCleanupInfo(branch.Info)
+			if err != nil {
+				s.logger.Error("Unable to parse the history branch info", tag.DetailInfo(branch.Info))
+				continue
+			}
+			shardID := s.shardRouter.GetShardID(workflowID)
+			if _, ok := shardIDs[shardID]; !ok {
+				continue
+			}
+			checker, ok := checkers[shardID]
+			if !ok {
+				if checker, err = s.newShardChecker(shardID); err != nil {
+					s.logger.Error("Failed to create shard checker", tag.ShardID(shardID), tag.Error(err))
+					atomic.AddInt64(&s.stats.nErrors, 1)
+					continue
+				}
+				checkers[shardID] = checker
+			}
+			_, failure, err := checker.CheckHistoryBranch(branch)
+			if err != nil {
+				s.logger.Error("Failed to check history branch", tag.WorkflowTreeID(branch.TreeID), tag.WorkflowBranchID(branch.BranchID), tag.Error(err))
+				atomic.AddInt64(&s.stats.nErrors, 1)
+				continue
+			}
+			if failure != nil {
+				s.recordFailures([]*CheckFailure{failure})
+			}
+		}
+
+		select {
+		case <-s.stopC:
+			return
+		default:
+		}
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			return
+		}
+	}
 }
 
 func (s *Scavenger) awaitExecutor() {
@@ -171,23 +285,59 @@ func (s *Scavenger) awaitExecutor() {
 	}
 }
 
+func (s *Scavenger) recordFailures(failures []*CheckFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	atomic.AddInt64(&s.stats.nCorrupted, 1)
+	for _, failure := range failures {
+		if failure.Fixed {
+			atomic.AddInt64(&s.stats.nFixed, 1)
+			return
+		}
+	}
+}
+
 func (s *Scavenger) emitStats() {
-	// TODO: implement this, this will emit metrics after a full run of executor scavenger is finished
+	s.metrics.UpdateGauge(metrics.ExecutionsScavengerScope, metrics.ExecutionsCheckedCount, float64(s.stats.nChecked))
+	s.metrics.UpdateGauge(metrics.ExecutionsScavengerScope, metrics.ExecutionsCorruptedCount, float64(s.stats.nCorrupted))
+	s.metrics.UpdateGauge(metrics.ExecutionsScavengerScope, metrics.ExecutionsFixedCount, float64(s.stats.nFixed))
+	s.metrics.UpdateGauge(metrics.ExecutionsScavengerScope, metrics.ExecutionsCheckErrorCount, float64(s.stats.nErrors))
+	s.logger.Info("Executions scavenger finished",
+		tag.Counter(int(s.stats.nChecked)),
+		tag.NumberProcessed(int(s.stats.nCorrupted)),
+		tag.NumberDeleted(int(s.stats.nFixed)))
+}
+
+func (s *Scavenger) getShardIDs() []int {
+	if len(s.params.ShardIDs) > 0 {
+		return s.params.ShardIDs
+	}
+	return s.shardRouter.GetShardIDs()
+}
+
+func (s *Scavenger) newShardChecker(shardID int) (*ShardChecker, error) {
+	executionManager, err := s.getExecutionManager(shardID)
+	if err != nil {
+		return nil, err
+	}
+	return NewShardChecker(shardID, s.shardRouter, executionManager, s.historyDB, s.params.Fix, s.logger), nil
 }
 
 // newTask returns a new instance of an executable task which will process a single execution
-func (s *Scavenger) newTask(domainID, workflowID, runID string) executor.Task {
+func (s *Scavenger) newTask(checker *ShardChecker, e *p.ConcreteExecution) executor.Task {
 	return &executorTask{
 		executionKey: executionKey{
-			domainID:   domainID,
-			workflowID: workflowID,
-			runID:      runID,
+			domainID:   e.DomainID,
+			workflowID: e.WorkflowID,
+			runID:      e.RunID,
 		},
-		scvg: s,
+		checker: checker,
+		scvg:    s,
 	}
 }
 
 // Run runs the task
 func (t *executorTask) Run() executor.TaskStatus {
-	return t.scvg.validateHandler(&t.executionKey)
+	return t.scvg.validateHandler(t.checker, &t.executionKey)
 }
@@ -22,6 +22,9 @@ package history
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	commonproto "go.temporal.io/temporal-proto/common"
@@ -36,6 +39,7 @@ import (
 	"github.com/temporalio/temporal/common/metrics"
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
@@ -46,18 +50,29 @@ type (
 		SkipCount     int
 		ErrorCount    int
 		SuccCount     int
+		// GarbageCount is the number of garbage branches found, they are only deleted if the scavenger is not in dry run mode
+		GarbageCount int
+		// NextShardID is the first shard of the shard group being listed when branches are listed by shard
+		NextShardID int
+		// ShardPageTokens are the pagination tokens of the shards of the group being listed, a nil token
+		// means the listing of the shard didn't start yet
+		ShardPageTokens map[int][]byte
 	}
 
 	// Scavenger is the type that holds the state for history scavenger daemon
 	Scavenger struct {
-		db       persistence.HistoryManager
-		client   historyservice.HistoryServiceClient
-		hbd      ScavengerHeartbeatDetails
-		rps      int
-		limiter  *rate.Limiter
-		metrics  metrics.Client
-		logger   log.Logger
-		isInTest bool
+		db           persistence.HistoryManager
+		client       historyservice.HistoryServiceClient
+		hbd          ScavengerHeartbeatDetails
+		rps          int
+		shardRouter  sharding.Router
+		listByShard  bool
+		dryRun       bool
+		limiter      *rate.Limiter
+		metrics      metrics.Client
+		logger       log.Logger
+		isInTest     bool
+		garbageCount int64
 	}
 
 	taskDetail struct {
@@ -66,6 +81,7 @@ type (
 		runID      string
 		treeID     string
 		branchID   string
+		shardID    int
 
 		// passing along the current heartbeat details to make heartbeat within a task so that it won't timeout
 		hbd ScavengerHeartbeatDetails
@@ -76,6 +92,8 @@ const (
 	// used this to decide how many goroutines to process
 	rpsPerConcurrency = 50
 	pageSize          = 1000
+	// number of shards listed in parallel when branches are listed by shard
+	shardListConcurrency = 8
 	// only clean up history branches that older than this threshold
 	// we double the MaxWorkflowRetentionPeriodInDays to avoid racing condition with history archival.
 	// Our history archiver delete mutable state, and then upload history to blob store and then delete history.
@@ -91,11 +109,18 @@ const (
 // each branch, the scavenger will attempt
 //  - describe the corresponding workflow execution
 //  - deletion of history itself, if there are no workflow execution
+// Stores partitioning history by shard (SQL) are listed shard by shard, with
+// shardListConcurrency shards listed in parallel, following the shards of the
+// history shard router so that split shards are listed too. In dry run mode, the garbage
+// branches are only logged and counted.
 func NewScavenger(
 	db persistence.HistoryManager,
 	rps int,
 	client historyservice.HistoryServiceClient,
 	hbd ScavengerHeartbeatDetails,
+	shardRouter sharding.Router,
+	listByShard bool,
+	dryRun bool,
 	metricsClient metrics.Client,
 	logger log.Logger,
 ) *Scavenger {
@@ -103,20 +128,24 @@ func NewScavenger(
 	rateLimiter := rate.NewLimiter(rate.Limit(rps), rps)
 
 	return &Scavenger{
-		db:      db,
-		client:  client,
-		hbd:     hbd,
-		rps:     rps,
-		limiter: rateLimiter,
-		metrics: metricsClient,
-		logger:  logger,
+		db:          db,
+		client:      client,
+		hbd:         hbd,
+		rps:         rps,
+		shardRouter: shardRouter,
+		listByShard: listByShard,
+		dryRun:      dryRun,
+		limiter:     rateLimiter,
+		metrics:     metricsClient,
+		logger:      logger,
 	}
 }
 
 // Run runs the scavenger
 func (s *Scavenger) Run(ctx context.Context) (ScavengerHeartbeatDetails, error) {
-	taskCh := make(chan taskDetail, pageSize)
-	respCh := make(chan error, pageSize)
+	// a page holds up to shardListConcurrency pages of the shards listed in parallel
+	taskCh := make(chan taskDetail, pageSize*shardListConcurrency)
+	respCh := make(chan error, pageSize*shardListConcurrency)
 	concurrency := s.rps/rpsPerConcurrency + 1
 
 	for i := 0; i < concurrency; i++ {
@@ -124,19 +153,16 @@ func (s *Scavenger) Run(ctx context.Context) (ScavengerHeartbeatDetails, error)
 	}
 
 	for {
-		resp, err := s.db.GetAllHistoryTreeBranches(&persistence.GetAllHistoryTreeBranchesRequest{
-			PageSize:      pageSize,
-			NextPageToken: s.hbd.NextPageToken,
-		})
+		branches, nextPage, err := s.listNextPage(ctx)
 		if err != nil {
 			return s.hbd, err
 		}
-		batchCount := len(resp.Branches)
+		batchCount := len(branches)
 
 		skips := 0
 		errorsOnSplitting := 0
 		// send all tasks
-		for _, br := range resp.Branches {
+		for _, br := range branches {
 			if time.Now().Add(-cleanUpThreshold).Before(br.ForkTime) {
 				batchCount--
 				skips++
@@ -159,6 +185,7 @@ func (s *Scavenger) Run(ctx context.Context) (ScavengerHeartbeatDetails, error)
 				runID:      rid,
 				treeID:     br.TreeID,
 				branchID:   br.BranchID,
+				shardID:    s.shardRouter.GetShardID(wid),
 
 				hbd: s.hbd,
 			}
@@ -188,22 +215,122 @@ func (s *Scavenger) Run(ctx context.Context) (ScavengerHeartbeatDetails, error)
 			}
 		}
 
+		done := nextPage()
 		s.hbd.CurrentPage++
-		s.hbd.NextPageToken = resp.NextPageToken
 		s.hbd.SuccCount += succCount
 		s.hbd.ErrorCount += errCount + errorsOnSplitting
 		s.hbd.SkipCount += skips
+		s.hbd.GarbageCount += int(atomic.SwapInt64(&s.garbageCount, 0))
 		if !s.isInTest {
 			activity.RecordHeartbeat(ctx, s.hbd)
 		}
 
-		if len(s.hbd.NextPageToken) == 0 {
+		if done {
 			break
 		}
 	}
 	return s.hbd, nil
 }
 
+// listNextPage reads the next page of history branches, the returned function moves the
+// heartbeat details past the page once it is processed and returns true if the listing is done
+func (s *Scavenger) listNextPage(ctx context.Context) ([]persistence.HistoryBranchDetail, func() bool, error) {
+	if s.listByShard {
+		return s.listNextShardPages(ctx)
+	}
+
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, nil, err
+	}
+	resp, err := s.db.GetAllHistoryTreeBranches(&persistence.GetAllHistoryTreeBranchesRequest{
+		PageSize:      pageSize,
+		NextPageToken: s.hbd.NextPageToken,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Branches, func() bool {
+		s.hbd.NextPageToken = resp.NextPageToken
+		return len(s.hbd.NextPageToken) == 0
+	}, nil
+}
+
+// listNextShardPages reads the next page of every shard of the group being listed in parallel
+func (s *Scavenger) listNextShardPages(ctx context.Context) ([]persistence.HistoryBranchDetail, func() bool, error) {
+	if len(s.hbd.ShardPageTokens) == 0 {
+		s.hbd.ShardPageTokens = make(map[int][]byte)
+		for _, shardID := range s.nextShardIDs() {
+			if len(s.hbd.ShardPageTokens) == shardListConcurrency {
+				break
+			}
+			s.hbd.ShardPageTokens[shardID] = nil
+		}
+	}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var listErr error
+	var branches []persistence.HistoryBranchDetail
+	nextPageTokens := make(map[int][]byte)
+	lastShardID := s.hbd.NextShardID
+	for shardID, pageToken := range s.hbd.ShardPageTokens {
+		if shardID > lastShardID {
+			lastShardID = shardID
+		}
+		wg.Add(1)
+		go func(shardID int, pageToken []byte) {
+			defer wg.Done()
+			err := s.limiter.Wait(ctx)
+			var resp *persistence.GetAllHistoryTreeBranchesResponse
+			if err == nil {
+				resp, err = s.db.GetAllHistoryTreeBranches(&persistence.GetAllHistoryTreeBranchesRequest{
+					PageSize:      pageSize,
+					NextPageToken: pageToken,
+					ShardID:       common.IntPtr(shardID),
+				})
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				listErr = err
+				return
+			}
+			branches = append(branches, resp.Branches...)
+			if len(resp.NextPageToken) > 0 {
+				nextPageTokens[shardID] = resp.NextPageToken
+			}
+		}(shardID, pageToken)
+	}
+	wg.Wait()
+	if listErr != nil {
+		return nil, nil, listErr
+	}
+
+	return branches, func() bool {
+		if len(nextPageTokens) > 0 {
+			s.hbd.ShardPageTokens = nextPageTokens
+			return false
+		}
+		s.hbd.ShardPageTokens = nil
+		s.hbd.NextShardID = lastShardID + 1
+		return len(s.nextShardIDs()) == 0
+	}, nil
+}
+
+// nextShardIDs returns the shards currently serving workflows which are not listed yet, in ascending order,
+// the shard IDs of the split shards are not contiguous so the listing progress is tracked by shard ID
+func (s *Scavenger) nextShardIDs() []int {
+	var shardIDs []int
+	for _, shardID := range s.shardRouter.GetShardIDs() {
+		if shardID >= s.hbd.NextShardID {
+			shardIDs = append(shardIDs, shardID)
+		}
+	}
+	sort.Ints(shardIDs)
+	return shardIDs
+}
+
 func (s *Scavenger) startTaskProcessor(
 	ctx context.Context,
 	taskCh chan taskDetail,
@@ -242,6 +369,14 @@ func (s *Scavenger) startTaskProcessor(
 
 			if err != nil {
 				if _, ok := err.(*serviceerror.NotFound); ok {
+					atomic.AddInt64(&s.garbageCount, 1)
+					if s.dryRun {
+						s.logger.Info("found history garbage, skipping deletion in dry run mode",
+							getTaskLoggingTags(nil, task)...)
+						respCh <- nil
+						continue
+					}
+
 					//deleting history branch
 					var branchToken []byte
 					branchToken, err = persistence.NewHistoryBranchTokenByBranchID(
@@ -254,12 +389,15 @@ func (s *Scavenger) startTaskProcessor(
 						continue
 					}
 
+					if err = s.limiter.Wait(ctx); err != nil {
+						respCh <- err
+						s.logger.Error("encounter error when wait for rate limiter",
+							getTaskLoggingTags(err, task)...)
+						continue
+					}
 					err = s.db.DeleteHistoryBranch(&persistence.DeleteHistoryBranchRequest{
 						BranchToken: branchToken,
-						// This is a required argument but it is not needed for Cassandra.
-						// Since this scanner is only for Cassandra,
-						// we can fill any number here to let to code go through
-						ShardID: common.IntPtr(1),
+						ShardID:     common.IntPtr(task.shardID),
 					})
 					if err != nil {
 						respCh <- err
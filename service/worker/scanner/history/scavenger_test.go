@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally"
 	commonproto "go.temporal.io/temporal-proto/common"
@@ -35,6 +36,7 @@ import (
 
 	"github.com/temporalio/temporal/.gen/proto/historyservice"
 	"github.com/temporalio/temporal/.gen/proto/historyservicemock"
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
 	"github.com/temporalio/temporal/common"
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
@@ -42,6 +44,7 @@ import (
 	"github.com/temporalio/temporal/common/mocks"
 	p "github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/primitives"
+	"github.com/temporalio/temporal/common/sharding"
 )
 
 type (
@@ -83,7 +86,7 @@ func (s *ScavengerTestSuite) createTestScavenger(rps int) (*mocks.HistoryV2Manag
 	db := &mocks.HistoryV2Manager{}
 	controller := gomock.NewController(s.T())
 	historyClient := historyservicemock.NewMockHistoryServiceClient(controller)
-	scvgr := NewScavenger(db, 100, historyClient, ScavengerHeartbeatDetails{}, s.metric, s.logger)
+	scvgr := NewScavenger(db, 100, historyClient, ScavengerHeartbeatDetails{}, sharding.NewStaticRouter(1), false, false, s.metric, s.logger)
 	scvgr.isInTest = true
 	return db, historyClient, scvgr, controller
 }
@@ -348,25 +351,25 @@ func (s *ScavengerTestSuite) TestDeletingBranchesTwoPages() {
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken1,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(nil).Once()
 	branchToken2, err := p.NewHistoryBranchTokenByBranchID(treeID2, branchID2)
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken2,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(nil).Once()
 	branchToken3, err := p.NewHistoryBranchTokenByBranchID(treeID3, branchID3)
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken3,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(nil).Once()
 	branchToken4, err := p.NewHistoryBranchTokenByBranchID(treeID4, branchID4)
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken4,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(nil).Once()
 
 	hbd, err := scvgr.Run(context.Background())
@@ -458,14 +461,14 @@ func (s *ScavengerTestSuite) TestMixesTwoPages() {
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken3,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(nil).Once()
 
 	branchToken4, err := p.NewHistoryBranchTokenByBranchID(treeID4, branchID4)
 	s.Nil(err)
 	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
 		BranchToken: branchToken4,
-		ShardID:     common.IntPtr(1),
+		ShardID:     common.IntPtr(0),
 	}).Return(fmt.Errorf("failed to delete history")).Once()
 
 	hbd, err := scvgr.Run(context.Background())
@@ -476,3 +479,186 @@ func (s *ScavengerTestSuite) TestMixesTwoPages() {
 	s.Equal(2, hbd.CurrentPage)
 	s.Equal(0, len(hbd.NextPageToken))
 }
+
+func (s *ScavengerTestSuite) TestDryRun() {
+	db, client, scvgr, controller := s.createTestScavenger(100)
+	defer controller.Finish()
+	scvgr.dryRun = true
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize: pageSize,
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{
+		Branches: []p.HistoryBranchDetail{
+			{
+				TreeID:   treeID1.String(),
+				BranchID: branchID1.String(),
+				ForkTime: time.Now().Add(-cleanUpThreshold * 2),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID1", "workflowID1", "runID1"),
+			},
+			{
+				TreeID:   treeID2.String(),
+				BranchID: branchID2.String(),
+				ForkTime: time.Now().Add(-cleanUpThreshold * 2),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID2", "workflowID2", "runID2"),
+			},
+		},
+	}, nil).Once()
+
+	client.EXPECT().DescribeMutableState(gomock.Any(), &historyservice.DescribeMutableStateRequest{
+		DomainUUID: "domainID1",
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID1",
+			RunId:      "runID1",
+		},
+	}).Return(nil, serviceerror.NewNotFound(""))
+	client.EXPECT().DescribeMutableState(gomock.Any(), &historyservice.DescribeMutableStateRequest{
+		DomainUUID: "domainID2",
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID2",
+			RunId:      "runID2",
+		},
+	}).Return(&historyservice.DescribeMutableStateResponse{}, nil)
+
+	hbd, err := scvgr.Run(context.Background())
+	s.Nil(err)
+	s.Equal(0, hbd.SkipCount)
+	s.Equal(2, hbd.SuccCount)
+	s.Equal(0, hbd.ErrorCount)
+	s.Equal(1, hbd.GarbageCount)
+	s.Equal(1, hbd.CurrentPage)
+	db.AssertNotCalled(s.T(), "DeleteHistoryBranch", mock.Anything)
+}
+
+func (s *ScavengerTestSuite) TestListByShard() {
+	db, client, scvgr, controller := s.createTestScavenger(100)
+	defer controller.Finish()
+	scvgr.shardRouter = sharding.NewStaticRouter(2)
+	scvgr.listByShard = true
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize: pageSize,
+		ShardID:  common.IntPtr(0),
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{
+		NextPageToken: []byte("page1"),
+		Branches: []p.HistoryBranchDetail{
+			{
+				TreeID:   treeID1.String(),
+				BranchID: branchID1.String(),
+				ForkTime: time.Now().Add(-cleanUpThreshold * 2),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID1", "workflowID1", "runID1"),
+			},
+		},
+	}, nil).Once()
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize:      pageSize,
+		NextPageToken: []byte("page1"),
+		ShardID:       common.IntPtr(0),
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{
+		Branches: []p.HistoryBranchDetail{
+			{
+				TreeID:   treeID2.String(),
+				BranchID: branchID2.String(),
+				ForkTime: time.Now(),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID2", "workflowID2", "runID2"),
+			},
+		},
+	}, nil).Once()
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize: pageSize,
+		ShardID:  common.IntPtr(1),
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{
+		Branches: []p.HistoryBranchDetail{
+			{
+				TreeID:   treeID3.String(),
+				BranchID: branchID3.String(),
+				ForkTime: time.Now(),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID3", "workflowID3", "runID3"),
+			},
+		},
+	}, nil).Once()
+
+	client.EXPECT().DescribeMutableState(gomock.Any(), &historyservice.DescribeMutableStateRequest{
+		DomainUUID: "domainID1",
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: "workflowID1",
+			RunId:      "runID1",
+		},
+	}).Return(nil, serviceerror.NewNotFound(""))
+	branchToken1, err := p.NewHistoryBranchTokenByBranchID(treeID1, branchID1)
+	s.Nil(err)
+	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
+		BranchToken: branchToken1,
+		ShardID:     common.IntPtr(common.WorkflowIDToHistoryShard("workflowID1", 2)),
+	}).Return(nil).Once()
+
+	hbd, err := scvgr.Run(context.Background())
+	s.Nil(err)
+	s.Equal(2, hbd.SkipCount)
+	s.Equal(1, hbd.SuccCount)
+	s.Equal(0, hbd.ErrorCount)
+	s.Equal(1, hbd.GarbageCount)
+	s.Equal(2, hbd.CurrentPage)
+	s.Equal(2, hbd.NextShardID)
+	s.Nil(hbd.ShardPageTokens)
+	db.AssertExpectations(s.T())
+}
+
+func (s *ScavengerTestSuite) TestListByShard_SplitShards() {
+	db, client, scvgr, controller := s.createTestScavenger(100)
+	defer controller.Finish()
+	// shard 1 is split into shards 1 and 3, shard 2 doesn't serve workflows yet
+	scvgr.shardRouter = sharding.NewStaticRouterFromRouting(&pblobs.HistoryShardRouting{
+		ShardCount:       2,
+		TargetShardCount: 4,
+		SplitShardIDs:    []int32{1},
+	})
+	scvgr.listByShard = true
+	// resume the listing after shard 0
+	scvgr.hbd.NextShardID = 1
+
+	workflowID := ""
+	for i := 0; workflowID == ""; i++ {
+		if wid := fmt.Sprintf("workflowID-%v", i); common.WorkflowIDToHistoryShard(wid, 4) == 3 {
+			workflowID = wid
+		}
+	}
+
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize: pageSize,
+		ShardID:  common.IntPtr(1),
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{}, nil).Once()
+	db.On("GetAllHistoryTreeBranches", &p.GetAllHistoryTreeBranchesRequest{
+		PageSize: pageSize,
+		ShardID:  common.IntPtr(3),
+	}).Return(&p.GetAllHistoryTreeBranchesResponse{
+		Branches: []p.HistoryBranchDetail{
+			{
+				TreeID:   treeID1.String(),
+				BranchID: branchID1.String(),
+				ForkTime: time.Now().Add(-cleanUpThreshold * 2),
+				Info:     p.BuildHistoryGarbageCleanupInfo("domainID1", workflowID, "runID1"),
+			},
+		},
+	}, nil).Once()
+
+	client.EXPECT().DescribeMutableState(gomock.Any(), &historyservice.DescribeMutableStateRequest{
+		DomainUUID: "domainID1",
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      "runID1",
+		},
+	}).Return(nil, serviceerror.NewNotFound(""))
+	branchToken1, err := p.NewHistoryBranchTokenByBranchID(treeID1, branchID1)
+	s.Nil(err)
+	db.On("DeleteHistoryBranch", &p.DeleteHistoryBranchRequest{
+		BranchToken: branchToken1,
+		ShardID:     common.IntPtr(3),
+	}).Return(nil).Once()
+
+	hbd, err := scvgr.Run(context.Background())
+	s.Nil(err)
+	s.Equal(1, hbd.SuccCount)
+	s.Equal(1, hbd.GarbageCount)
+	s.Equal(1, hbd.CurrentPage)
+	s.Equal(4, hbd.NextShardID)
+	s.Nil(hbd.ShardPageTokens)
+	db.AssertExpectations(s.T())
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/temporalio/temporal/common/service/config"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 	"github.com/temporalio/temporal/service/worker/scanner/executions"
+	"github.com/temporalio/temporal/service/worker/scanner/stuck"
 )
 
 const (
@@ -45,13 +46,6 @@ const (
 	scannerStartUpDelay = time.Second * 4
 )
 
-var (
-	defaultExecutionsScannerParams = executions.ScannerWorkflowParams{
-		// fullExecutionsScanDefaultQuery indicates the visibility scanner should scan through all open workflows
-		VisibilityQuery: "SELECT * from elasticSearch.executions WHERE state IS open", // TODO: depending on if we go straight to ES or through frontend this query will look different
-	}
-)
-
 type (
 	// Config defines the configuration for scanner
 	Config struct {
@@ -65,8 +59,24 @@ type (
 		TaskListScannerEnabled dynamicconfig.BoolPropertyFn
 		// HistoryScannerEnabled indicates if history scanner should be started as part of scanner
 		HistoryScannerEnabled dynamicconfig.BoolPropertyFn
+		// HistoryScannerDryRun indicates if history scanner should only report garbage history branches,
+		// it defaults to true on SQL clusters
+		HistoryScannerDryRun dynamicconfig.BoolPropertyFn
 		// ExecutionsScannerEnabled indicates if executions scanner should be started as part of scanner
 		ExecutionsScannerEnabled dynamicconfig.BoolPropertyFn
+		// StuckWorkflowScannerEnabled indicates if stuck workflow scanner should be started as part of scanner
+		StuckWorkflowScannerEnabled dynamicconfig.BoolPropertyFn
+		// StuckWorkflowDecisionAttempts is the decision task attempt from which a workflow is reported as stuck
+		StuckWorkflowDecisionAttempts dynamicconfig.IntPropertyFn
+		// StuckWorkflowScheduleToStartThreshold is how long an activity may wait on a task list without pollers
+		StuckWorkflowScheduleToStartThreshold dynamicconfig.DurationPropertyFn
+		// StuckWorkflowIdleThreshold is how long an open workflow without pending work may stay unchanged
+		StuckWorkflowIdleThreshold dynamicconfig.DurationPropertyFn
+		// StuckWorkflowMaxReported is the max number of stuck workflows kept in the report of a domain
+		StuckWorkflowMaxReported dynamicconfig.IntPropertyFn
+		// StuckWorkflowTaskListReadPartitions is the number of read partitions of a task list, the pollers of
+		// every partition are checked
+		StuckWorkflowTaskListReadPartitions dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 	}
 
 	// BootstrapParams contains the set of params needed to bootstrap
@@ -129,26 +139,39 @@ func (s *Scanner) Start() error {
 	var workerTaskListNames []string
 	if s.context.cfg.ExecutionsScannerEnabled() {
 		workerTaskListNames = append(workerTaskListNames, executionsScannerTaskListName)
-		go s.startWorkflowWithRetry(executionsScannerWFStartOptions, executionsScannerWFTypeName, defaultExecutionsScannerParams)
+		// executions scanner only reports corruptions by default
+		executionsScannerParams := executions.ScannerWorkflowParams{
+			CheckHistoryBranches: true,
+		}
+		go s.startWorkflowWithRetry(executionsScannerWFStartOptions, executionsScannerWFTypeName, executionsScannerParams)
 	}
 
 	if s.context.cfg.Persistence.DefaultStoreType() == config.StoreTypeSQL && s.context.cfg.TaskListScannerEnabled() {
 		go s.startWorkflowWithRetry(tlScannerWFStartOptions, tlScannerWFTypeName)
 		workerTaskListNames = append(workerTaskListNames, tlScannerTaskListName)
-	} else if s.context.cfg.Persistence.DefaultStoreType() == config.StoreTypeCassandra && s.context.cfg.HistoryScannerEnabled() {
+	}
+
+	if s.context.cfg.HistoryScannerEnabled() {
 		go s.startWorkflowWithRetry(historyScannerWFStartOptions, historyScannerWFTypeName)
 		workerTaskListNames = append(workerTaskListNames, historyScannerTaskListName)
 	}
 
+	if s.context.cfg.StuckWorkflowScannerEnabled() {
+		go s.startWorkflowWithRetry(stuckScannerWFStartOptions, stuck.ScannerWFTypeName, stuck.ScannerWorkflowParams{})
+		workerTaskListNames = append(workerTaskListNames, stuckScannerTaskListName)
+	}
+
 	for _, tl := range workerTaskListNames {
 		work := worker.New(s.context.GetSDKClient(), tl, workerOpts)
 
 		work.RegisterWorkflowWithOptions(TaskListScannerWorkflow, workflow.RegisterOptions{Name: tlScannerWFTypeName})
 		work.RegisterWorkflowWithOptions(HistoryScannerWorkflow, workflow.RegisterOptions{Name: historyScannerWFTypeName})
 		work.RegisterWorkflowWithOptions(ExecutionsScannerWorkflow, workflow.RegisterOptions{Name: executionsScannerWFTypeName})
+		work.RegisterWorkflowWithOptions(StuckWorkflowScannerWorkflow, workflow.RegisterOptions{Name: stuck.ScannerWFTypeName})
 		work.RegisterActivityWithOptions(TaskListScavengerActivity, activity.RegisterOptions{Name: taskListScavengerActivityName})
 		work.RegisterActivityWithOptions(HistoryScavengerActivity, activity.RegisterOptions{Name: historyScavengerActivityName})
 		work.RegisterActivityWithOptions(ExecutionsScavengerActivity, activity.RegisterOptions{Name: executionsScavengerActivityName})
+		work.RegisterActivityWithOptions(StuckWorkflowScanActivity, activity.RegisterOptions{Name: stuckScanActivityName})
 
 		if err := work.Start(); err != nil {
 			return err
@@ -0,0 +1,391 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stuck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	"go.temporal.io/temporal-proto/workflowservice"
+	"golang.org/x/time/rate"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/common/sharding"
+)
+
+const (
+	// ScannerWFID is the workflow ID of the stuck workflow scanner
+	ScannerWFID = "cadence-sys-stuck-workflow-scanner"
+	// ScannerWFTypeName is the workflow type of the stuck workflow scanner
+	ScannerWFTypeName = "cadence-sys-stuck-workflow-scanner-workflow"
+	// ReportsQueryType is the query type returning the last DomainReport of every domain,
+	// keyed by domain name, from the stuck workflow scanner
+	ReportsQueryType = "stuck-workflow-reports"
+
+	// ReasonDecisionTaskFailing means the decision task of the workflow keeps failing or timing out
+	ReasonDecisionTaskFailing Reason = "DecisionTaskFailing"
+	// ReasonActivityNoPoller means an activity is waiting past its schedule to start threshold
+	// on a task list without pollers
+	ReasonActivityNoPoller Reason = "ActivityNoPoller"
+	// ReasonNoPendingWork means the workflow is not closed but has nothing that could make progress
+	// except for an external signal or cancellation
+	ReasonNoPendingWork Reason = "NoPendingWork"
+
+	pageSize = 1000
+)
+
+type (
+	// Reason is the diagnosis of a stuck workflow
+	Reason string
+
+	// ScannerWorkflowParams are the params of the stuck workflow scanner, the reports are
+	// carried over when the scanner continues as new
+	ScannerWorkflowParams struct {
+		Reports map[string]*DomainReport
+	}
+
+	// Workflow is a workflow execution diagnosed as stuck
+	Workflow struct {
+		WorkflowID   string
+		RunID        string
+		WorkflowType string
+		Reason       Reason
+		Details      string
+	}
+
+	// DomainReport is the result of the scan of the open workflows of a domain
+	DomainReport struct {
+		Domain       string
+		ScanTime     time.Time
+		CheckedCount int
+		ErrorCount   int
+		StuckCounts  map[Reason]int
+		// Workflows are the stuck workflows found, at most Thresholds.MaxReportedWorkflows of them
+		Workflows []Workflow
+	}
+
+	// Thresholds decide when a workflow is diagnosed as stuck
+	Thresholds struct {
+		// DecisionAttempts is the decision task attempt from which the decision task is considered failing
+		DecisionAttempts int
+		// ScheduleToStart is how long an activity may wait for a poller
+		ScheduleToStart time.Duration
+		// Idle is how long a workflow without pending work may stay open
+		Idle time.Duration
+		// MaxReportedWorkflows is the max number of stuck workflows listed in the report of a domain
+		MaxReportedWorkflows int
+	}
+
+	// Detector finds the stuck workflows of domains by checking the mutable state
+	// of every open workflow listed by visibility
+	Detector struct {
+		thresholds               Thresholds
+		visibilityManager        persistence.VisibilityManager
+		executionManagerProvider func(int) (persistence.ExecutionManager, error)
+		shardRouter              sharding.Router
+		domainCache              cache.DomainCache
+		matchingClient           matchingservice.MatchingServiceClient
+		numReadPartitions        dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		limiter                  *rate.Limiter
+		metricsClient            metrics.Client
+		logger                   log.Logger
+		now                      func() time.Time
+	}
+
+	pollerKey struct {
+		domainID string
+		taskList string
+	}
+)
+
+// NewDetector returns a new stuck workflow detector
+func NewDetector(
+	thresholds Thresholds,
+	rps int,
+	visibilityManager persistence.VisibilityManager,
+	executionManagerProvider func(int) (persistence.ExecutionManager, error),
+	shardRouter sharding.Router,
+	domainCache cache.DomainCache,
+	matchingClient matchingservice.MatchingServiceClient,
+	numReadPartitions dynamicconfig.IntPropertyFnWithTaskListInfoFilters,
+	metricsClient metrics.Client,
+	logger log.Logger,
+) *Detector {
+
+	return &Detector{
+		thresholds:               thresholds,
+		visibilityManager:        visibilityManager,
+		executionManagerProvider: executionManagerProvider,
+		shardRouter:              shardRouter,
+		domainCache:              domainCache,
+		matchingClient:           matchingClient,
+		numReadPartitions:        numReadPartitions,
+		limiter:                  rate.NewLimiter(rate.Limit(rps), rps),
+		metricsClient:            metricsClient,
+		logger:                   logger,
+		now:                      time.Now,
+	}
+}
+
+// ScanDomain diagnoses the open workflows of the domain and emits the number of stuck workflows per reason,
+// heartbeat is called after every page of workflows.
+func (d *Detector) ScanDomain(
+	ctx context.Context,
+	domainID string,
+	domainName string,
+	heartbeat func(),
+) (*DomainReport, error) {
+
+	now := d.now()
+	report := &DomainReport{
+		Domain:      domainName,
+		ScanTime:    now,
+		StuckCounts: make(map[Reason]int),
+	}
+	pollers := make(map[pollerKey]bool)
+	hasPollers := func(activityDomainID string, taskList string) (bool, error) {
+		key := pollerKey{domainID: activityDomainID, taskList: taskList}
+		if result, ok := pollers[key]; ok {
+			return result, nil
+		}
+		// the activity may be scheduled on the task list of another domain
+		activityDomainName := domainName
+		if activityDomainID != domainID {
+			name, err := d.domainCache.GetDomainName(activityDomainID)
+			if err != nil {
+				return false, err
+			}
+			activityDomainName = name
+		}
+		result, err := d.hasPollers(ctx, activityDomainID, activityDomainName, taskList)
+		if err != nil {
+			return false, err
+		}
+		pollers[key] = result
+		return result, nil
+	}
+
+	request := &persistence.ListWorkflowExecutionsRequest{
+		DomainUUID:        domainID,
+		Domain:            domainName,
+		EarliestStartTime: 0,
+		LatestStartTime:   now.UnixNano(),
+		PageSize:          pageSize,
+	}
+	for {
+		if err := d.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := d.visibilityManager.ListOpenWorkflowExecutions(request)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range resp.Executions {
+			if err := d.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+			workflow, err := d.checkWorkflow(domainID, info, now, hasPollers)
+			if err != nil {
+				report.ErrorCount++
+				d.logger.Error("Failed to check workflow for stuck workflow scan",
+					tag.WorkflowDomainName(domainName),
+					tag.WorkflowID(info.GetExecution().GetWorkflowId()),
+					tag.WorkflowRunID(info.GetExecution().GetRunId()),
+					tag.Error(err))
+				continue
+			}
+			report.CheckedCount++
+			if workflow == nil {
+				continue
+			}
+			report.StuckCounts[workflow.Reason]++
+			if len(report.Workflows) < d.thresholds.MaxReportedWorkflows {
+				report.Workflows = append(report.Workflows, *workflow)
+			}
+			d.logger.Info("Stuck workflow found",
+				tag.WorkflowDomainName(domainName),
+				tag.WorkflowID(workflow.WorkflowID),
+				tag.WorkflowRunID(workflow.RunID),
+				tag.WorkflowType(workflow.WorkflowType),
+				tag.WorkflowStuckReason(string(workflow.Reason)),
+				tag.DetailInfo(workflow.Details))
+		}
+		if heartbeat != nil {
+			heartbeat()
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+
+	d.emitMetrics(report)
+	return report, nil
+}
+
+// hasPollers returns true if any read partition of the activity task list has pollers, pollers are
+// only recorded by the partition they poll
+func (d *Detector) hasPollers(
+	ctx context.Context,
+	domainID string,
+	domainName string,
+	taskList string,
+) (bool, error) {
+
+	numPartitions := common.MaxInt(1, d.numReadPartitions(domainName, taskList, persistence.TaskListTypeActivity))
+	for partition := 0; partition < numPartitions; partition++ {
+		partitionName := taskList
+		if partition > 0 {
+			partitionName = fmt.Sprintf("%v%v/%v", common.TaskListPartitionPrefix, taskList, partition)
+		}
+		resp, err := d.matchingClient.DescribeTaskList(ctx, &matchingservice.DescribeTaskListRequest{
+			DomainUUID: domainID,
+			DescRequest: &workflowservice.DescribeTaskListRequest{
+				Domain:       domainName,
+				TaskList:     &commonproto.TaskList{Name: partitionName},
+				TaskListType: enums.TaskListTypeActivity,
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(resp.GetPollers()) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *Detector) checkWorkflow(
+	domainID string,
+	info *commonproto.WorkflowExecutionInfo,
+	now time.Time,
+	hasPollers func(domainID string, taskList string) (bool, error),
+) (*Workflow, error) {
+
+	executionManager, err := d.executionManagerProvider(d.shardRouter.GetShardID(info.GetExecution().GetWorkflowId()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := executionManager.GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
+		DomainID: domainID,
+		Execution: commonproto.WorkflowExecution{
+			WorkflowId: info.GetExecution().GetWorkflowId(),
+			RunId:      info.GetExecution().GetRunId(),
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			// the workflow was closed and deleted since it was listed
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	reason, details, err := Diagnose(resp.State, d.thresholds, now, hasPollers)
+	if err != nil || reason == "" {
+		return nil, err
+	}
+	return &Workflow{
+		WorkflowID:   info.GetExecution().GetWorkflowId(),
+		RunID:        info.GetExecution().GetRunId(),
+		WorkflowType: info.GetType().GetName(),
+		Reason:       reason,
+		Details:      details,
+	}, nil
+}
+
+func (d *Detector) emitMetrics(report *DomainReport) {
+	scope := d.metricsClient.Scope(metrics.StuckWorkflowScannerScope).Tagged(metrics.DomainTag(report.Domain))
+	scope.UpdateGauge(metrics.StuckWorkflowsCheckedCount, float64(report.CheckedCount))
+	scope.UpdateGauge(metrics.StuckWorkflowsCheckErrorCount, float64(report.ErrorCount))
+	scope.UpdateGauge(metrics.StuckWorkflowsDecisionTaskFailingCount, float64(report.StuckCounts[ReasonDecisionTaskFailing]))
+	scope.UpdateGauge(metrics.StuckWorkflowsActivityNoPollerCount, float64(report.StuckCounts[ReasonActivityNoPoller]))
+	scope.UpdateGauge(metrics.StuckWorkflowsNoPendingWorkCount, float64(report.StuckCounts[ReasonNoPendingWork]))
+}
+
+// Diagnose returns the reason why the workflow is stuck, an empty reason if it is not stuck
+func Diagnose(
+	state *persistence.WorkflowMutableState,
+	thresholds Thresholds,
+	now time.Time,
+	hasPollers func(domainID string, taskList string) (bool, error),
+) (Reason, string, error) {
+
+	executionInfo := state.ExecutionInfo
+	if executionInfo.State == persistence.WorkflowStateCompleted || executionInfo.State == persistence.WorkflowStateZombie {
+		return "", "", nil
+	}
+
+	decisionPending := executionInfo.DecisionScheduleID != common.EmptyEventID
+	if decisionPending && thresholds.DecisionAttempts > 0 && executionInfo.DecisionAttempt >= int64(thresholds.DecisionAttempts) {
+		return ReasonDecisionTaskFailing, fmt.Sprintf("decision task attempt %v", executionInfo.DecisionAttempt), nil
+	}
+
+	// check the activities in schedule ID order for a stable diagnosis
+	scheduleIDs := make([]int64, 0, len(state.ActivityInfos))
+	for scheduleID := range state.ActivityInfos {
+		scheduleIDs = append(scheduleIDs, scheduleID)
+	}
+	sort.Slice(scheduleIDs, func(i, j int) bool { return scheduleIDs[i] < scheduleIDs[j] })
+	for _, scheduleID := range scheduleIDs {
+		ai := state.ActivityInfos[scheduleID]
+		if ai.StartedID != common.EmptyEventID || now.Sub(ai.ScheduledTime) < thresholds.ScheduleToStart {
+			continue
+		}
+		domainID := ai.DomainID
+		if domainID == "" {
+			domainID = executionInfo.DomainID
+		}
+		polled, err := hasPollers(domainID, ai.TaskList)
+		if err != nil {
+			return "", "", err
+		}
+		if !polled {
+			return ReasonActivityNoPoller, fmt.Sprintf("activity %v waiting since %v on task list %v without pollers",
+				ai.ActivityID, ai.ScheduledTime.UTC().Format(time.RFC3339), ai.TaskList), nil
+		}
+	}
+
+	hasPendingWork := decisionPending ||
+		len(state.ActivityInfos) > 0 ||
+		len(state.TimerInfos) > 0 ||
+		len(state.ChildExecutionInfos) > 0 ||
+		len(state.RequestCancelInfos) > 0 ||
+		len(state.SignalInfos) > 0 ||
+		len(state.BufferedEvents) > 0
+	if !hasPendingWork && thresholds.Idle > 0 && now.Sub(executionInfo.LastUpdatedTimestamp) >= thresholds.Idle {
+		return ReasonNoPendingWork, fmt.Sprintf("no pending work since %v", executionInfo.LastUpdatedTimestamp.UTC().Format(time.RFC3339)), nil
+	}
+	return "", "", nil
+}
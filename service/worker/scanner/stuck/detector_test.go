@@ -0,0 +1,328 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stuck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/.gen/proto/matchingservicemock"
+	pblobs "github.com/temporalio/temporal/.gen/proto/persistenceblobs"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/cache"
+	"github.com/temporalio/temporal/common/log"
+	"github.com/temporalio/temporal/common/metrics"
+	"github.com/temporalio/temporal/common/mocks"
+	p "github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+)
+
+type (
+	DetectorTestSuite struct {
+		suite.Suite
+		now        time.Time
+		thresholds Thresholds
+	}
+
+	testRouter struct{}
+)
+
+func TestDetectorTestSuite(t *testing.T) {
+	suite.Run(t, new(DetectorTestSuite))
+}
+
+func (s *DetectorTestSuite) SetupTest() {
+	s.now = time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.thresholds = Thresholds{
+		DecisionAttempts:     10,
+		ScheduleToStart:      10 * time.Minute,
+		Idle:                 24 * time.Hour,
+		MaxReportedWorkflows: 1,
+	}
+}
+
+func (s *DetectorTestSuite) TestDiagnose_NotStuck() {
+	state := s.newMutableState()
+	state.ExecutionInfo.LastUpdatedTimestamp = s.now.Add(-time.Hour)
+	reason, _, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+
+	state = s.newMutableState()
+	state.ExecutionInfo.State = p.WorkflowStateCompleted
+	reason, _, err = Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+}
+
+func (s *DetectorTestSuite) TestDiagnose_DecisionTaskFailing() {
+	state := s.newMutableState()
+	state.ExecutionInfo.DecisionScheduleID = 5
+	state.ExecutionInfo.DecisionAttempt = 9
+	reason, _, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+
+	state.ExecutionInfo.DecisionAttempt = 10
+	reason, details, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Equal(ReasonDecisionTaskFailing, reason)
+	s.Equal("decision task attempt 10", details)
+}
+
+func (s *DetectorTestSuite) TestDiagnose_ActivityNoPoller() {
+	state := s.newMutableState()
+	state.ActivityInfos[5] = &p.ActivityInfo{
+		ScheduleID:    5,
+		StartedID:     common.EmptyEventID,
+		ScheduledTime: s.now.Add(-5 * time.Minute),
+		ActivityID:    "a1",
+		TaskList:      "tl",
+	}
+	reason, _, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+
+	state.ActivityInfos[5].ScheduledTime = s.now.Add(-time.Hour)
+	reason, _, err = Diagnose(state, s.thresholds, s.now, func(domainID string, taskList string) (bool, error) {
+		s.Equal("domain-id", domainID)
+		s.Equal("tl", taskList)
+		return true, nil
+	})
+	s.NoError(err)
+	s.Empty(reason)
+
+	reason, details, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Equal(ReasonActivityNoPoller, reason)
+	s.Contains(details, "activity a1")
+
+	state.ActivityInfos[5].StartedID = 6
+	reason, _, err = Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+}
+
+func (s *DetectorTestSuite) TestDiagnose_NoPendingWork() {
+	state := s.newMutableState()
+	reason, _, err := Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Equal(ReasonNoPendingWork, reason)
+
+	state.TimerInfos["t1"] = &pblobs.TimerInfo{TimerID: "t1"}
+	reason, _, err = Diagnose(state, s.thresholds, s.now, s.noPollers)
+	s.NoError(err)
+	s.Empty(reason)
+}
+
+func (s *DetectorTestSuite) TestScanDomain() {
+	controller := gomock.NewController(s.T())
+	defer controller.Finish()
+
+	visibilityManager := &mocks.VisibilityManager{}
+	defer visibilityManager.AssertExpectations(s.T())
+	executionManager := &mocks.ExecutionManager{}
+	defer executionManager.AssertExpectations(s.T())
+	matchingClient := matchingservicemock.NewMockMatchingServiceClient(controller)
+
+	visibilityManager.On("ListOpenWorkflowExecutions", mock.Anything).Return(&p.ListWorkflowExecutionsResponse{
+		Executions: []*commonproto.WorkflowExecutionInfo{
+			s.newExecutionInfo("wid1"),
+			s.newExecutionInfo("wid2"),
+			s.newExecutionInfo("wid3"),
+		},
+	}, nil).Once()
+
+	failing := s.newMutableState()
+	failing.ExecutionInfo.DecisionScheduleID = 5
+	failing.ExecutionInfo.DecisionAttempt = 20
+	idle := s.newMutableState()
+	executionManager.On("GetWorkflowExecution", mock.MatchedBy(func(request *p.GetWorkflowExecutionRequest) bool {
+		return request.Execution.GetWorkflowId() == "wid1"
+	})).Return(&p.GetWorkflowExecutionResponse{State: failing}, nil).Once()
+	executionManager.On("GetWorkflowExecution", mock.MatchedBy(func(request *p.GetWorkflowExecutionRequest) bool {
+		return request.Execution.GetWorkflowId() == "wid2"
+	})).Return(&p.GetWorkflowExecutionResponse{State: idle}, nil).Once()
+	executionManager.On("GetWorkflowExecution", mock.MatchedBy(func(request *p.GetWorkflowExecutionRequest) bool {
+		return request.Execution.GetWorkflowId() == "wid3"
+	})).Return(nil, p.ErrPersistenceLimitExceeded).Once()
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(&matchingservice.DescribeTaskListResponse{}, nil).AnyTimes()
+
+	detector := NewDetector(
+		s.thresholds,
+		1000,
+		visibilityManager,
+		func(int) (p.ExecutionManager, error) { return executionManager, nil },
+		testRouter{},
+		cache.NewMockDomainCache(controller),
+		matchingClient,
+		dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1),
+		metrics.NewClient(tally.NoopScope, metrics.Worker),
+		log.NewNoop(),
+	)
+	detector.now = func() time.Time { return s.now }
+
+	heartbeats := 0
+	report, err := detector.ScanDomain(context.Background(), "domain-id", "domain", func() { heartbeats++ })
+	s.NoError(err)
+	s.Equal(1, heartbeats)
+	s.Equal("domain", report.Domain)
+	s.Equal(2, report.CheckedCount)
+	s.Equal(1, report.ErrorCount)
+	s.Equal(map[Reason]int{ReasonDecisionTaskFailing: 1, ReasonNoPendingWork: 1}, report.StuckCounts)
+	s.Equal([]Workflow{{
+		WorkflowID:   "wid1",
+		RunID:        "rid",
+		WorkflowType: "wf-type",
+		Reason:       ReasonDecisionTaskFailing,
+		Details:      "decision task attempt 20",
+	}}, report.Workflows)
+}
+
+func (s *DetectorTestSuite) TestScanDomain_ActivityOfAnotherDomain() {
+	controller := gomock.NewController(s.T())
+	defer controller.Finish()
+
+	visibilityManager := &mocks.VisibilityManager{}
+	defer visibilityManager.AssertExpectations(s.T())
+	executionManager := &mocks.ExecutionManager{}
+	defer executionManager.AssertExpectations(s.T())
+	domainCache := cache.NewMockDomainCache(controller)
+	matchingClient := matchingservicemock.NewMockMatchingServiceClient(controller)
+
+	visibilityManager.On("ListOpenWorkflowExecutions", mock.Anything).Return(&p.ListWorkflowExecutionsResponse{
+		Executions: []*commonproto.WorkflowExecutionInfo{s.newExecutionInfo("wid1")},
+	}, nil).Once()
+	state := s.newMutableState()
+	state.ActivityInfos[5] = &p.ActivityInfo{
+		ScheduleID:    5,
+		StartedID:     common.EmptyEventID,
+		ScheduledTime: s.now.Add(-time.Hour),
+		DomainID:      "other-domain-id",
+		ActivityID:    "a1",
+		TaskList:      "tl",
+	}
+	executionManager.On("GetWorkflowExecution", mock.Anything).Return(&p.GetWorkflowExecutionResponse{State: state}, nil).Once()
+	domainCache.EXPECT().GetDomainName("other-domain-id").Return("other-domain", nil).Times(1)
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error) {
+			s.Equal("other-domain-id", request.DomainUUID)
+			s.Equal("other-domain", request.DescRequest.Domain)
+			return &matchingservice.DescribeTaskListResponse{}, nil
+		}).Times(1)
+
+	detector := NewDetector(
+		s.thresholds,
+		1000,
+		visibilityManager,
+		func(int) (p.ExecutionManager, error) { return executionManager, nil },
+		testRouter{},
+		domainCache,
+		matchingClient,
+		dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1),
+		metrics.NewClient(tally.NoopScope, metrics.Worker),
+		log.NewNoop(),
+	)
+	detector.now = func() time.Time { return s.now }
+
+	report, err := detector.ScanDomain(context.Background(), "domain-id", "domain", func() {})
+	s.NoError(err)
+	s.Equal(map[Reason]int{ReasonActivityNoPoller: 1}, report.StuckCounts)
+}
+
+func (s *DetectorTestSuite) TestHasPollers_Partitions() {
+	controller := gomock.NewController(s.T())
+	defer controller.Finish()
+
+	matchingClient := matchingservicemock.NewMockMatchingServiceClient(controller)
+	describeTaskList := func(taskList string, pollers []*commonproto.PollerInfo) *gomock.Call {
+		return matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, request *matchingservice.DescribeTaskListRequest) (*matchingservice.DescribeTaskListResponse, error) {
+				s.Equal(taskList, request.DescRequest.TaskList.GetName())
+				return &matchingservice.DescribeTaskListResponse{Pollers: pollers}, nil
+			}).Times(1)
+	}
+	// the pollers only poll the last partition
+	gomock.InOrder(
+		describeTaskList("tl", nil),
+		describeTaskList("/__temporal_sys/tl/1", nil),
+		describeTaskList("/__temporal_sys/tl/2", []*commonproto.PollerInfo{{Identity: "worker"}}),
+	)
+
+	detector := NewDetector(
+		s.thresholds,
+		1000,
+		&mocks.VisibilityManager{},
+		func(int) (p.ExecutionManager, error) { return &mocks.ExecutionManager{}, nil },
+		testRouter{},
+		cache.NewMockDomainCache(controller),
+		matchingClient,
+		dynamicconfig.GetIntPropertyFilteredByTaskListInfo(3),
+		metrics.NewClient(tally.NoopScope, metrics.Worker),
+		log.NewNoop(),
+	)
+	hasPollers, err := detector.hasPollers(context.Background(), "domain-id", "domain", "tl")
+	s.NoError(err)
+	s.True(hasPollers)
+}
+
+func (s *DetectorTestSuite) noPollers(string, string) (bool, error) {
+	return false, nil
+}
+
+func (s *DetectorTestSuite) newExecutionInfo(workflowID string) *commonproto.WorkflowExecutionInfo {
+	return &commonproto.WorkflowExecutionInfo{
+		Execution: &commonproto.WorkflowExecution{WorkflowId: workflowID, RunId: "rid"},
+		Type:      &commonproto.WorkflowType{Name: "wf-type"},
+	}
+}
+
+// newMutableState returns the mutable state of a workflow without pending work, idle for two days
+func (s *DetectorTestSuite) newMutableState() *p.WorkflowMutableState {
+	return &p.WorkflowMutableState{
+		ExecutionInfo: &p.WorkflowExecutionInfo{
+			DomainID:             "domain-id",
+			State:                p.WorkflowStateRunning,
+			DecisionScheduleID:   common.EmptyEventID,
+			DecisionStartedID:    common.EmptyEventID,
+			LastUpdatedTimestamp: s.now.Add(-48 * time.Hour),
+		},
+		ActivityInfos:       make(map[int64]*p.ActivityInfo),
+		TimerInfos:          make(map[string]*pblobs.TimerInfo),
+		ChildExecutionInfos: make(map[int64]*p.ChildExecutionInfo),
+		RequestCancelInfos:  make(map[int64]*pblobs.RequestCancelInfo),
+		SignalInfos:         make(map[int64]*pblobs.SignalInfo),
+	}
+}
+
+func (testRouter) Start()                           {}
+func (testRouter) Stop()                            {}
+func (testRouter) GetShardID(workflowID string) int { return 0 }
+func (testRouter) GetShardIDs() []int               { return []int{0} }
+func (testRouter) NumberOfShards() int              { return 1 }
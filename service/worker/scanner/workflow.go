@@ -30,8 +30,11 @@ import (
 	"go.temporal.io/temporal/workflow"
 
 	"github.com/temporalio/temporal/common/log/tag"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/service/config"
 	"github.com/temporalio/temporal/service/worker/scanner/executions"
 	"github.com/temporalio/temporal/service/worker/scanner/history"
+	"github.com/temporalio/temporal/service/worker/scanner/stuck"
 	"github.com/temporalio/temporal/service/worker/scanner/tasklist"
 )
 
@@ -60,6 +63,14 @@ const (
 	executionsScannerWFTypeName     = "cadence-sys-executions-scanner-workflow"
 	executionsScannerTaskListName   = "cadence-sys-executions-scanner-tasklist-0"
 	executionsScavengerActivityName = "cadence-sys-executions-scanner-scvg-activity"
+
+	stuckScannerTaskListName = "cadence-sys-stuck-workflow-scanner-tasklist-0"
+	stuckScanActivityName    = "cadence-sys-stuck-workflow-scanner-scan-activity"
+	// stuckScannerInterval is the time between two scans of the stuck workflow scanner
+	stuckScannerInterval = time.Hour
+	// stuckScannerScansPerRun is the number of scans after which the stuck workflow scanner continues as new
+	stuckScannerScansPerRun = 24
+	stuckScanDomainPageSize = 100
 )
 
 var (
@@ -99,6 +110,13 @@ var (
 		WorkflowIDReusePolicy:        cclient.WorkflowIDReusePolicyAllowDuplicate,
 		CronSchedule:                 "0 */12 * * *",
 	}
+	// the stuck workflow scanner is not a cron workflow, so that its reports can be queried between scans
+	stuckScannerWFStartOptions = cclient.StartWorkflowOptions{
+		ID:                           stuck.ScannerWFID,
+		TaskList:                     stuckScannerTaskListName,
+		ExecutionStartToCloseTimeout: infiniteDuration,
+		WorkflowIDReusePolicy:        cclient.WorkflowIDReusePolicyAllowDuplicate,
+	}
 )
 
 // TaskListScannerWorkflow is the workflow that runs the task-list scanner background daemon
@@ -132,6 +150,40 @@ func ExecutionsScannerWorkflow(
 	return future.Get(ctx, nil)
 }
 
+// StuckWorkflowScannerWorkflow is the workflow that periodically scans all domains for stuck workflows,
+// the last report of every domain is returned by the stuck.ReportsQueryType query
+func StuckWorkflowScannerWorkflow(
+	ctx workflow.Context,
+	params stuck.ScannerWorkflowParams,
+) error {
+
+	reports := params.Reports
+	if err := workflow.SetQueryHandler(ctx, stuck.ReportsQueryType, func() (map[string]*stuck.DomainReport, error) {
+		return reports, nil
+	}); err != nil {
+		return err
+	}
+
+	for i := 0; i < stuckScannerScansPerRun; i++ {
+		var result []*stuck.DomainReport
+		if err := workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, activityOptions),
+			stuckScanActivityName,
+		).Get(ctx, &result); err != nil {
+			return err
+		}
+		// domains which are gone or not active anymore drop out of the reports
+		reports = make(map[string]*stuck.DomainReport, len(result))
+		for _, report := range result {
+			reports[report.Domain] = report
+		}
+		if err := workflow.Sleep(ctx, stuckScannerInterval); err != nil {
+			return err
+		}
+	}
+	return workflow.NewContinueAsNewError(ctx, stuck.ScannerWFTypeName, stuck.ScannerWorkflowParams{Reports: reports})
+}
+
 // HistoryScavengerActivity is the activity that runs history scavenger
 func HistoryScavengerActivity(
 	activityCtx context.Context,
@@ -152,6 +204,9 @@ func HistoryScavengerActivity(
 		rps,
 		ctx.GetHistoryClient(),
 		hbd,
+		ctx.GetHistoryShardRouter(),
+		ctx.cfg.Persistence.DefaultStoreType() == config.StoreTypeSQL,
+		ctx.cfg.HistoryScannerDryRun(),
 		ctx.GetMetricsClient(),
 		ctx.GetLogger(),
 	)
@@ -186,7 +241,14 @@ func ExecutionsScavengerActivity(
 ) error {
 
 	ctx := activityCtx.Value(scannerContextKey).(scannerContext)
-	scavenger := executions.NewScavenger(executionsScannerWorkflowParams, ctx.GetFrontendClient(), ctx.GetHistoryManager(), ctx.GetMetricsClient(), ctx.GetLogger())
+	scavenger := executions.NewScavenger(
+		executionsScannerWorkflowParams,
+		ctx.GetHistoryShardRouter(),
+		ctx.GetExecutionManager,
+		ctx.GetHistoryManager(),
+		ctx.GetMetricsClient(),
+		ctx.GetLogger(),
+	)
 	ctx.GetLogger().Info("Starting executions scavenger")
 	scavenger.Start()
 	for scavenger.Alive() {
@@ -200,3 +262,63 @@ func ExecutionsScavengerActivity(
 	}
 	return nil
 }
+
+// StuckWorkflowScanActivity is the activity that scans the domains active in the current cluster for stuck workflows
+func StuckWorkflowScanActivity(
+	activityCtx context.Context,
+) ([]*stuck.DomainReport, error) {
+
+	ctx := activityCtx.Value(scannerContextKey).(scannerContext)
+	detector := stuck.NewDetector(
+		stuck.Thresholds{
+			DecisionAttempts:     ctx.cfg.StuckWorkflowDecisionAttempts(),
+			ScheduleToStart:      ctx.cfg.StuckWorkflowScheduleToStartThreshold(),
+			Idle:                 ctx.cfg.StuckWorkflowIdleThreshold(),
+			MaxReportedWorkflows: ctx.cfg.StuckWorkflowMaxReported(),
+		},
+		ctx.cfg.PersistenceMaxQPS(),
+		ctx.GetVisibilityManager(),
+		ctx.GetExecutionManager,
+		ctx.GetHistoryShardRouter(),
+		ctx.GetDomainCache(),
+		ctx.GetMatchingClient(),
+		ctx.cfg.StuckWorkflowTaskListReadPartitions,
+		ctx.GetMetricsClient(),
+		ctx.GetLogger(),
+	)
+	heartbeat := func() {
+		activity.RecordHeartbeat(activityCtx)
+	}
+
+	var reports []*stuck.DomainReport
+	request := &persistence.ListDomainsRequest{PageSize: stuckScanDomainPageSize}
+	for {
+		resp, err := ctx.GetMetadataManager().ListDomains(request)
+		if err != nil {
+			return nil, err
+		}
+		for _, domain := range resp.Domains {
+			if domain.Info.Status != persistence.DomainStatusRegistered {
+				continue
+			}
+			// mutable states and pollers of a global domain are only up to date in its active cluster
+			if domain.IsGlobalDomain && domain.ReplicationConfig.ActiveClusterName != ctx.cfg.ClusterMetadata.GetCurrentClusterName() {
+				continue
+			}
+			report, err := detector.ScanDomain(activityCtx, domain.Info.ID, domain.Info.Name, heartbeat)
+			if err != nil {
+				if activityCtx.Err() != nil {
+					return nil, activityCtx.Err()
+				}
+				ctx.GetLogger().Error("Failed to scan domain for stuck workflows",
+					tag.WorkflowDomainName(domain.Info.Name), tag.Error(err))
+				continue
+			}
+			reports = append(reports, report)
+		}
+		if len(resp.NextPageToken) == 0 {
+			return reports, nil
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+}
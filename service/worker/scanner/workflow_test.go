@@ -38,6 +38,7 @@ import (
 	"github.com/temporalio/temporal/common/metrics"
 	p "github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/resource"
+	"github.com/temporalio/temporal/service/worker/scanner/stuck"
 )
 
 type scannerWorkflowTestSuite struct {
@@ -69,6 +70,30 @@ func (s *scannerWorkflowTestSuite) TestWorkflow() {
 	s.True(env.IsWorkflowCompleted())
 }
 
+func (s *scannerWorkflowTestSuite) TestStuckWorkflowScannerWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(StuckWorkflowScannerWorkflow, workflow.RegisterOptions{Name: stuck.ScannerWFTypeName})
+	env.RegisterActivityWithOptions(StuckWorkflowScanActivity, activity.RegisterOptions{Name: stuckScanActivityName})
+	report := &stuck.DomainReport{
+		Domain:       "test-domain",
+		CheckedCount: 3,
+		StuckCounts:  map[stuck.Reason]int{stuck.ReasonNoPendingWork: 1},
+	}
+	env.OnActivity(stuckScanActivityName, mock.Anything).Return([]*stuck.DomainReport{report}, nil).Times(stuckScannerScansPerRun)
+	env.ExecuteWorkflow(stuck.ScannerWFTypeName, stuck.ScannerWorkflowParams{})
+	s.True(env.IsWorkflowCompleted())
+	_, ok := env.GetWorkflowError().(*workflow.ContinueAsNewError)
+	s.True(ok)
+
+	value, err := env.QueryWorkflow(stuck.ReportsQueryType)
+	s.NoError(err)
+	var reports map[string]*stuck.DomainReport
+	s.NoError(value.Get(&reports))
+	s.Len(reports, 1)
+	s.Equal(3, reports["test-domain"].CheckedCount)
+	s.Equal(1, reports["test-domain"].StuckCounts[stuck.ReasonNoPendingWork])
+}
+
 func (s *scannerWorkflowTestSuite) TestScavengerActivity() {
 	env := s.NewTestActivityEnvironment()
 	s.registerActivities(env)
@@ -34,12 +34,16 @@ import (
 	"github.com/temporalio/temporal/common/persistence"
 	persistenceClient "github.com/temporalio/temporal/common/persistence/client"
 	"github.com/temporalio/temporal/common/resource"
+	"github.com/temporalio/temporal/common/service/config"
 	"github.com/temporalio/temporal/common/service/dynamicconfig"
 	"github.com/temporalio/temporal/service/worker/archiver"
 	"github.com/temporalio/temporal/service/worker/batcher"
+	"github.com/temporalio/temporal/service/worker/failover"
 	"github.com/temporalio/temporal/service/worker/indexer"
+	"github.com/temporalio/temporal/service/worker/migration"
 	"github.com/temporalio/temporal/service/worker/parentclosepolicy"
 	"github.com/temporalio/temporal/service/worker/replicator"
+	"github.com/temporalio/temporal/service/worker/reshard"
 	"github.com/temporalio/temporal/service/worker/scanner"
 )
 
@@ -64,8 +68,11 @@ type (
 		IndexerCfg                    *indexer.Config
 		ScannerCfg                    *scanner.Config
 		BatcherCfg                    *batcher.Config
+		HandoverCfg                   *failover.Config
 		ThrottledLogRPS               dynamicconfig.IntPropertyFn
 		EnableBatcher                 dynamicconfig.BoolPropertyFn
+		EnableDomainMigrator          dynamicconfig.BoolPropertyFn
+		EnableHistoryResharder        dynamicconfig.BoolPropertyFn
 		EnableParentClosePolicyWorker dynamicconfig.BoolPropertyFn
 	}
 )
@@ -105,6 +112,8 @@ func NewService(
 // NewConfig builds the new Config for cadence-worker service
 func NewConfig(params *resource.BootstrapParams) *Config {
 	dc := dynamicconfig.NewCollection(params.DynamicConfig, params.Logger)
+	// the history scanner only reports garbage history branches on SQL unless deletion is enabled explicitly
+	isSQLStore := params.PersistenceConfig.DefaultStoreType() == config.StoreTypeSQL
 	config := &Config{
 		ReplicationCfg: &replicator.Config{
 			PersistenceMaxQPS:                  dc.GetIntProperty(dynamicconfig.WorkerPersistenceMaxQPS, 500),
@@ -128,13 +137,26 @@ func NewConfig(params *resource.BootstrapParams) *Config {
 			ClusterMetadata:          params.ClusterMetadata,
 			TaskListScannerEnabled:   dc.GetBoolProperty(dynamicconfig.TaskListScannerEnabled, true),
 			HistoryScannerEnabled:    dc.GetBoolProperty(dynamicconfig.HistoryScannerEnabled, true),
+			HistoryScannerDryRun:     dc.GetBoolProperty(dynamicconfig.HistoryScannerDryRun, isSQLStore),
 			ExecutionsScannerEnabled: dc.GetBoolProperty(dynamicconfig.ExecutionsScannerEnabled, false),
+
+			StuckWorkflowScannerEnabled:           dc.GetBoolProperty(dynamicconfig.StuckWorkflowScannerEnabled, false),
+			StuckWorkflowDecisionAttempts:         dc.GetIntProperty(dynamicconfig.StuckWorkflowDecisionAttempts, 10),
+			StuckWorkflowScheduleToStartThreshold: dc.GetDurationProperty(dynamicconfig.StuckWorkflowScheduleToStartThreshold, 10*time.Minute),
+			StuckWorkflowIdleThreshold:            dc.GetDurationProperty(dynamicconfig.StuckWorkflowIdleThreshold, 7*24*time.Hour),
+			StuckWorkflowMaxReported:              dc.GetIntProperty(dynamicconfig.StuckWorkflowMaxReported, 100),
+			StuckWorkflowTaskListReadPartitions:   dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingNumTasklistReadPartitions, 1),
 		},
 		BatcherCfg: &batcher.Config{
 			AdminOperationToken: dc.GetStringProperty(dynamicconfig.AdminOperationToken, common.DefaultAdminOperationToken),
 			ClusterMetadata:     params.ClusterMetadata,
 		},
+		HandoverCfg: &failover.Config{
+			HandoverCheckInterval: dc.GetDurationProperty(dynamicconfig.WorkerDomainHandoverCheckInterval, 10*time.Second),
+		},
 		EnableBatcher:                 dc.GetBoolProperty(dynamicconfig.EnableBatcher, false),
+		EnableDomainMigrator:          dc.GetBoolProperty(dynamicconfig.EnableDomainMigrator, false),
+		EnableHistoryResharder:        dc.GetBoolProperty(dynamicconfig.EnableHistoryResharder, false),
 		EnableParentClosePolicyWorker: dc.GetBoolProperty(dynamicconfig.EnableParentClosePolicyWorker, true),
 		ThrottledLogRPS:               dc.GetIntProperty(dynamicconfig.WorkerThrottledLogRPS, 20),
 	}
@@ -174,6 +196,7 @@ func (s *Service) Start() {
 
 	if s.GetClusterMetadata().IsGlobalDomainEnabled() {
 		s.startReplicator()
+		s.startHandoverProcessor()
 	}
 	if s.GetArchivalMetadata().GetHistoryConfig().ClusterConfiguredForArchival() {
 		s.startArchiver()
@@ -181,6 +204,12 @@ func (s *Service) Start() {
 	if s.config.EnableBatcher() {
 		s.startBatcher()
 	}
+	if s.config.EnableDomainMigrator() {
+		s.startMigrator()
+	}
+	if s.config.EnableHistoryResharder() {
+		s.startResharder()
+	}
 	if s.config.EnableParentClosePolicyWorker() {
 		s.startParentClosePolicyProcessor()
 	}
@@ -228,6 +257,34 @@ func (s *Service) startBatcher() {
 	}
 }
 
+func (s *Service) startMigrator() {
+	params := &migration.BootstrapParams{
+		ServiceClient:   s.params.PublicClient,
+		ClientBean:      s.GetClientBean(),
+		MetadataManager: s.GetMetadataManager(),
+		DomainHandler:   s.newDomainHandler(),
+		DomainCache:     s.GetDomainCache(),
+		ClusterMetadata: s.GetClusterMetadata(),
+		MetricsClient:   s.GetMetricsClient(),
+		Logger:          s.GetLogger(),
+	}
+	if err := migration.New(params).Start(); err != nil {
+		s.GetLogger().Fatal("error starting domain migrator", tag.Error(err))
+	}
+}
+
+func (s *Service) startResharder() {
+	params := &reshard.BootstrapParams{
+		ServiceClient:   s.params.PublicClient,
+		PersistenceBean: s.GetPersistenceBean(),
+		MetricsClient:   s.GetMetricsClient(),
+		Logger:          s.GetLogger(),
+	}
+	if err := reshard.New(params).Start(); err != nil {
+		s.GetLogger().Fatal("error starting history resharder", tag.Error(err))
+	}
+}
+
 func (s *Service) startScanner() {
 	params := &scanner.BootstrapParams{
 		Config: *s.config.ScannerCfg,
@@ -262,6 +319,36 @@ func (s *Service) startReplicator() {
 	}
 }
 
+func (s *Service) startHandoverProcessor() {
+	handoverProcessor := failover.NewHandoverProcessor(
+		s.config.HandoverCfg,
+		s.params.PersistenceConfig.NumHistoryShards,
+		s.GetDomainCache(),
+		s.GetMetadataManager(),
+		s.newDomainHandler(),
+		s.GetHistoryClient(),
+		s.GetHostInfo(),
+		s.GetWorkerServiceResolver(),
+		s.GetMetricsClient(),
+		s.GetLogger(),
+		s.GetTimeSource(),
+	)
+	handoverProcessor.Start()
+}
+
+func (s *Service) newDomainHandler() domain.Handler {
+	return domain.NewHandler(
+		domain.MinRetentionDays,
+		dynamicconfig.GetIntPropertyFilteredByDomain(domain.MaxBadBinaries),
+		s.GetLogger(),
+		s.GetMetadataManager(),
+		s.GetClusterMetadata(),
+		domain.NewDomainReplicator(s.GetDomainReplicationQueue(), s.GetLogger()),
+		s.GetArchivalMetadata(),
+		s.GetArchiverProvider(),
+	)
+}
+
 func (s *Service) startIndexer() {
 	visibilityIndexer := indexer.NewIndexer(
 		s.config.IndexerCfg,
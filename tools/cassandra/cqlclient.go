@@ -71,6 +71,8 @@ const (
 	writeSchemaVersionCQL       = `INSERT into schema_version(keyspace_name, creation_time, curr_version, min_compatible_version) VALUES (?,?,?,?)`
 	writeSchemaUpdateHistoryCQL = `INSERT into schema_update_history(year, month, update_time, old_version, new_version, manifest_md5, description) VALUES(?,?,?,?,?,?,?)`
 
+	readSchemaUpdateHistoryCQL = `SELECT update_time, old_version, new_version, manifest_md5 from schema_update_history`
+
 	createSchemaVersionTableCQL = `CREATE TABLE schema_version(keyspace_name text PRIMARY KEY, ` +
 		`creation_time timestamp, ` +
 		`curr_version text, ` +
@@ -198,6 +200,20 @@ func (client *cqlClient) WriteSchemaUpdateLog(oldVersion string, newVersion stri
 	return query.Exec()
 }
 
+// ReadSchemaUpdateLog returns all entries of the schema update history table
+func (client *cqlClient) ReadSchemaUpdateLog() ([]schema.SchemaUpdateLogEntry, error) {
+	iter := client.session.Query(readSchemaUpdateHistoryCQL).Iter()
+	var result []schema.SchemaUpdateLogEntry
+	var entry schema.SchemaUpdateLogEntry
+	for iter.Scan(&entry.UpdateTime, &entry.OldVersion, &entry.NewVersion, &entry.ManifestMD5) {
+		result = append(result, entry)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Exec executes a cql statement
 func (client *cqlClient) Exec(stmt string, args ...interface{}) error {
 	return client.session.Query(stmt, args...).Exec()
@@ -133,6 +133,24 @@ func updateSchema(cli *cli.Context) error {
 	return nil
 }
 
+// rollbackSchema executes the rollbackSchemaTask
+// using the given command line args as input
+func rollbackSchema(cli *cli.Context) error {
+	config, err := newCQLClientConfig(cli)
+	if err != nil {
+		return handleErr(schema.NewConfigError(err.Error()))
+	}
+	client, err := newCQLClient(config)
+	if err != nil {
+		return handleErr(err)
+	}
+	defer client.Close()
+	if err := schema.Rollback(cli, client); err != nil {
+		return handleErr(err)
+	}
+	return nil
+}
+
 // createKeyspace creates a cassandra Keyspace
 func createKeyspace(cli *cli.Context) error {
 	config, err := newCQLClientConfig(cli)
@@ -180,6 +180,28 @@ func buildCLIOptions() *cli.App {
 				cliHandler(c, updateSchema)
 			},
 		},
+		{
+			Name:    "rollback-schema",
+			Aliases: []string{"rollback"},
+			Usage:   "rollback cassandra schema to a specific version using the down scripts of the versioned schema",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  schema.CLIFlagTargetVersion,
+					Usage: "target version for the schema rollback",
+				},
+				cli.StringFlag{
+					Name:  schema.CLIFlagSchemaDir,
+					Usage: "path to directory containing versioned schema",
+				},
+				cli.BoolFlag{
+					Name:  schema.CLIFlagDryrun,
+					Usage: "print the rollback statements without executing them",
+				},
+			},
+			Action: func(c *cli.Context) {
+				cliHandler(c, rollbackSchema)
+			},
+		},
 		{
 			Name:    "create-Keyspace",
 			Aliases: []string{"create"},
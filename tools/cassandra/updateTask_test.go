@@ -57,6 +57,13 @@ func (s *UpdateSchemaTestSuite) TestUpdateSchema() {
 	s.RunUpdateSchemaTest(buildCLIOptions(), client, "-k", createTestCQLFileContent(), []string{"events", "tasks"})
 }
 
+func (s *UpdateSchemaTestSuite) TestRollbackSchema() {
+	client, err := newTestCQLClient(s.DBName)
+	s.Nil(err)
+	defer client.Close()
+	s.RunRollbackSchemaTest(buildCLIOptions(), client, "-k", createTestCQLFileContent(), []string{"events", "tasks"})
+}
+
 func (s *UpdateSchemaTestSuite) TestDryrun() {
 	client, err := newTestCQLClient(s.DBName)
 	s.Nil(err)
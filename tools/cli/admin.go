@@ -20,7 +20,13 @@
 
 package cli
 
-import "github.com/urfave/cli"
+import (
+	"github.com/urfave/cli"
+
+	es "github.com/temporalio/temporal/common/elasticsearch"
+	"github.com/temporalio/temporal/service/worker/migration"
+	"github.com/temporalio/temporal/service/worker/reshard"
+)
 
 func newAdminWorkflowCommands() []cli.Command {
 	return []cli.Command{
@@ -133,6 +139,60 @@ func newAdminWorkflowCommands() []cli.Command {
 				AdminRefreshWorkflowTasks(c)
 			},
 		},
+		{
+			Name:    "rehydrate",
+			Aliases: []string{"rh"},
+			Usage:   "Restores an archived workflow execution, so it can be described, queried and reset until it expires again",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminRehydrateWorkflow(c)
+			},
+		},
+		{
+			Name:    "export",
+			Aliases: []string{"ex"},
+			Usage:   "Exports the history and mutable state of a workflow execution into a file, which can be imported into another cluster",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID, default to the current run",
+				},
+				cli.StringFlag{
+					Name:  FlagOutputFilenameWithAlias,
+					Usage: "Output file to write the exported workflow to",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminExportWorkflow(c)
+			},
+		},
+		{
+			Name:    "import",
+			Aliases: []string{"im"},
+			Usage:   "Imports a workflow execution exported from another cluster",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagInputFileWithAlias,
+					Usage: "Input file written by the export command",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminImportWorkflow(c)
+			},
+		},
 		{
 			Name:    "delete",
 			Aliases: []string{"del"},
@@ -199,6 +259,20 @@ func newAdminWorkflowCommands() []cli.Command {
 				AdminDeleteWorkflow(c)
 			},
 		},
+		{
+			Name:    "stuck",
+			Aliases: []string{"st"},
+			Usage:   "Show the stuck workflows found by the last scan of the stuck workflow scanner",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDomain,
+					Usage: "Only show the stuck workflows of this domain, default to all domains",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminStuckWorkflows(c)
+			},
+		},
 	}
 }
 
@@ -240,6 +314,95 @@ func newAdminShardManagementCommands() []cli.Command {
 				AdminRemoveTask(c)
 			},
 		},
+		{
+			Name:    "scan",
+			Aliases: []string{"sc"},
+			Usage:   "check the workflow executions of a shard for corruptions",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  FlagShardID,
+					Usage: "ShardID for the temporal cluster to manage",
+				},
+				cli.BoolFlag{
+					Name:  FlagCheckHistoryBranches,
+					Usage: "also look for history branches of the shard without a workflow execution, this reads every history branch of the cluster",
+				},
+				cli.BoolFlag{
+					Name:  FlagFix,
+					Usage: "delete the executions without history and the orphan history branches",
+				},
+
+				// for persistence connection
+				// TODO need to support other database: https://github.com/uber/cadence/issues/2777
+				cli.StringFlag{
+					Name:  FlagDBAddress,
+					Usage: "persistence address(right now only cassandra is supported)",
+				},
+				cli.IntFlag{
+					Name:  FlagDBPort,
+					Value: 9042,
+					Usage: "persistence port",
+				},
+				cli.StringFlag{
+					Name:  FlagUsername,
+					Usage: "cassandra username",
+				},
+				cli.StringFlag{
+					Name:  FlagPassword,
+					Usage: "cassandra password",
+				},
+				cli.StringFlag{
+					Name:  FlagKeyspace,
+					Usage: "cassandra keyspace",
+				},
+				cli.BoolFlag{
+					Name:  FlagEnableTLS,
+					Usage: "enable TLS over cassandra connection",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCertPath,
+					Usage: "cassandra tls client cert path (tls must be enabled)",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSKeyPath,
+					Usage: "cassandra tls client key path (tls must be enabled)",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCaPath,
+					Usage: "cassandra tls client ca path (tls must be enabled)",
+				},
+				cli.BoolFlag{
+					Name:  FlagTLSEnableHostVerification,
+					Usage: "cassandra tls verify hostname and server cert (tls must be enabled)",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminShardScan(c)
+			},
+		},
+		{
+			Name:    "reshard",
+			Aliases: []string{"rs"},
+			Usage:   "Split every history shard into the same number of shards online, the workflows of a shard are unavailable while it is split",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  FlagNumberOfShards,
+					Usage: "Number of history shards after the split, a multiple of the current number of shards",
+				},
+				cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: reshard.DefaultConcurrency,
+					Usage: "Number of shards split in parallel",
+				},
+				cli.BoolFlag{
+					Name:  FlagDescribe,
+					Usage: "Describe the progress of the reshard",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminReshard(c)
+			},
+		},
 	}
 }
 
@@ -282,7 +445,51 @@ func newAdminHistoryHostCommands() []cli.Command {
 				},
 				cli.IntFlag{
 					Name:  FlagNumberOfShards,
-					Usage: "NumberOfShards for the temporal cluster(see config for numHistoryShards)",
+					Usage: "NumberOfShards for the temporal cluster(see config for numHistoryShards), ignored when the persistence address is set",
+				},
+
+				// for persistence connection, to follow the shards split by a reshard
+				// TODO need to support other database: https://github.com/uber/cadence/issues/2777
+				cli.StringFlag{
+					Name:  FlagDBAddress,
+					Usage: "persistence address(right now only cassandra is supported)",
+				},
+				cli.IntFlag{
+					Name:  FlagDBPort,
+					Value: 9042,
+					Usage: "persistence port",
+				},
+				cli.StringFlag{
+					Name:  FlagUsername,
+					Usage: "cassandra username",
+				},
+				cli.StringFlag{
+					Name:  FlagPassword,
+					Usage: "cassandra password",
+				},
+				cli.StringFlag{
+					Name:  FlagKeyspace,
+					Usage: "cassandra keyspace",
+				},
+				cli.BoolFlag{
+					Name:  FlagEnableTLS,
+					Usage: "enable TLS over cassandra connection",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCertPath,
+					Usage: "cassandra tls client cert path (tls must be enabled)",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSKeyPath,
+					Usage: "cassandra tls client key path (tls must be enabled)",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCaPath,
+					Usage: "cassandra tls client ca path (tls must be enabled)",
+				},
+				cli.BoolFlag{
+					Name:  FlagTLSEnableHostVerification,
+					Usage: "cassandra tls verify hostname and server cert (tls must be enabled)",
 				},
 			},
 			Action: func(c *cli.Context) {
@@ -383,6 +590,43 @@ func newAdminDomainCommands() []cli.Command {
 				AdminGetDomainIDOrName(c)
 			},
 		},
+		{
+			Name:    "migrate",
+			Aliases: []string{"mi"},
+			Usage:   "Migrate local domain from another cluster into this cluster, by replicating its workflows and locking it in the source cluster for the cutover",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagSourceCluster,
+					Usage: "Cluster the domain is migrated from",
+				},
+				cli.IntFlag{
+					Name:  FlagRPS,
+					Value: migration.DefaultRPS,
+					Usage: "RPS of replicated workflows",
+				},
+				cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: migration.DefaultConcurrency,
+					Usage: "Number of workflows replicated in parallel",
+				},
+				cli.IntFlag{
+					Name:  FlagLagThreshold,
+					Value: migration.DefaultLagThreshold,
+					Usage: "Number of workflows a catch up round replicated events of, below which the domain is locked in the source cluster for the cutover",
+				},
+				cli.BoolFlag{
+					Name:  FlagDescribe,
+					Usage: "Describe the progress of the migration",
+				},
+				cli.BoolFlag{
+					Name:  FlagAbort,
+					Usage: "Abort the migration, only possible before the domain is deprecated in the source cluster",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminMigrateDomain(c)
+			},
+		},
 	}
 }
 
@@ -525,11 +769,6 @@ clusters:
 					Name:  FlagTargetCluster,
 					Usage: "Name of targetCluster to receive the replication task",
 				},
-				cli.IntFlag{
-					Name:  FlagNumberOfShards,
-					Usage: "NumberOfShards is required to calculate shardID. (see server config for numHistoryShards)",
-				},
-
 				// for multiple workflow
 				cli.StringFlag{
 					Name:  FlagInputFileWithAlias,
@@ -739,6 +978,29 @@ func newAdminElasticSearchCommands() []cli.Command {
 				GenerateReport(c)
 			},
 		},
+		{
+			Name:    "mapping-version",
+			Aliases: []string{"mv"},
+			Usage:   "Show the schema version of the index mapping on ElasticSearch",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagURL,
+					Usage: "URL of ElasticSearch cluster",
+				},
+				cli.StringFlag{
+					Name:  FlagIndex,
+					Usage: "ElasticSearch target index",
+				},
+				cli.StringFlag{
+					Name:  FlagESVersion,
+					Value: es.VersionV6,
+					Usage: "Major version of ElasticSearch cluster (v6 or v7)",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminGetMappingVersion(c)
+			},
+		},
 	}
 }
 
@@ -763,6 +1025,24 @@ func newAdminTaskListCommands() []cli.Command {
 				AdminDescribeTaskList(c)
 			},
 		},
+		{
+			Name:  "unload",
+			Usage: "Unload tasklist from the matching host which owns it, it is loaded again by the next request",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminUnloadTaskList(c)
+			},
+		},
 	}
 }
 
@@ -811,7 +1091,7 @@ func newAdminDLQCommands() []cli.Command {
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  FlagDLQTypeWithAlias,
-					Usage: "Type of DLQ to manage. (Options: domain, history)",
+					Usage: "Type of DLQ to manage. (Options: domain, history, transfer, timer)",
 				},
 				cli.IntFlag{
 					Name:  FlagShardIDWithAlias,
@@ -825,6 +1105,10 @@ func newAdminDLQCommands() []cli.Command {
 					Name:  FlagLastMessageID,
 					Usage: "The upper boundary of the read message",
 				},
+				cli.IntFlag{
+					Name:  FlagFirstMessageIDWithAlias,
+					Usage: "The exclusive lower boundary of the messages, only for transfer and timer DLQ",
+				},
 				cli.StringFlag{
 					Name:  FlagOutputFilenameWithAlias,
 					Usage: "Output file to write to, if not provided output is written to stdout",
@@ -841,7 +1125,7 @@ func newAdminDLQCommands() []cli.Command {
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  FlagDLQTypeWithAlias,
-					Usage: "Type of DLQ to manage. (Options: domain, history)",
+					Usage: "Type of DLQ to manage. (Options: domain, history, transfer, timer)",
 				},
 				cli.IntFlag{
 					Name:  FlagShardIDWithAlias,
@@ -851,6 +1135,10 @@ func newAdminDLQCommands() []cli.Command {
 					Name:  FlagLastMessageID,
 					Usage: "The upper boundary of the read message",
 				},
+				cli.IntFlag{
+					Name:  FlagFirstMessageIDWithAlias,
+					Usage: "The exclusive lower boundary of the messages, only for transfer and timer DLQ",
+				},
 			},
 			Action: func(c *cli.Context) {
 				AdminPurgeDLQMessages(c)
@@ -863,7 +1151,7 @@ func newAdminDLQCommands() []cli.Command {
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  FlagDLQTypeWithAlias,
-					Usage: "Type of DLQ to manage. (Options: domain, history)",
+					Usage: "Type of DLQ to manage. (Options: domain, history, transfer, timer)",
 				},
 				cli.IntFlag{
 					Name:  FlagShardIDWithAlias,
@@ -873,10 +1161,36 @@ func newAdminDLQCommands() []cli.Command {
 					Name:  FlagLastMessageID,
 					Usage: "The upper boundary of the read message",
 				},
+				cli.IntFlag{
+					Name:  FlagFirstMessageIDWithAlias,
+					Usage: "The exclusive lower boundary of the messages, only for transfer and timer DLQ",
+				},
 			},
 			Action: func(c *cli.Context) {
 				AdminMergeDLQMessages(c)
 			},
 		},
+		{
+			Name:    "describe",
+			Aliases: []string{"d"},
+			Usage:   "Describe a transfer or timer task in the DLQ of a shard",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDLQTypeWithAlias,
+					Usage: "Type of DLQ to manage. (Options: transfer, timer)",
+				},
+				cli.IntFlag{
+					Name:  FlagShardIDWithAlias,
+					Usage: "ShardID",
+				},
+				cli.Int64Flag{
+					Name:  FlagMessageIDWithAlias,
+					Usage: "The task id of the message",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeTaskDLQMessage(c)
+			},
+		},
 	}
 }
@@ -79,7 +79,7 @@ func AdminDescribeCluster(c *cli.Context) {
 		ErrorAndExit("Operation DescribeCluster failed.", err)
 	}
 
-	prettyPrintJSONObject(response)
+	printObject(c, response)
 }
 
 func intValTypeToString(valType int) string {
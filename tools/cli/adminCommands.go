@@ -42,11 +42,28 @@ import (
 	"github.com/temporalio/temporal/common/persistence/serialization"
 	"github.com/temporalio/temporal/common/primitives"
 	"github.com/temporalio/temporal/common/service/config"
+	"github.com/temporalio/temporal/common/service/dynamicconfig"
+	"github.com/temporalio/temporal/common/sharding"
+	"github.com/temporalio/temporal/service/worker/scanner/executions"
 	"github.com/temporalio/temporal/tools/cassandra"
 )
 
 const maxEventID = 9999
 
+const exportWorkflowPageSize = 100
+
+// importWorkflowMaxPageSizeInBytes keeps an import page well below the gRPC message size limit
+const importWorkflowMaxPageSizeInBytes = 2 * 1024 * 1024
+
+// exportedWorkflow is the file format of an exported workflow execution
+type exportedWorkflow struct {
+	Domain         string                  `json:"domain"`
+	WorkflowID     string                  `json:"workflowId"`
+	RunID          string                  `json:"runId"`
+	MutableState   string                  `json:"mutableState,omitempty"`
+	HistoryBatches []*commonproto.DataBlob `json:"historyBatches"`
+}
+
 // AdminShowWorkflow shows history
 func AdminShowWorkflow(c *cli.Context) {
 	tid := c.String(FlagTreeID)
@@ -80,24 +97,30 @@ func AdminShowWorkflow(c *cli.Context) {
 		ErrorAndExit("no events", nil)
 	}
 	allEvents := &commonproto.History{}
+	var historyBatches [][]*commonproto.HistoryEvent
 	totalSize := 0
-	for idx, b := range history {
+	for _, b := range history {
 		totalSize += len(b.Data)
-		fmt.Printf("======== batch %v, blob len: %v ======\n", idx+1, len(b.Data))
-		historyBatchThrift, err := serializer.DeserializeBatchEvents(b)
+		historyBatch, err := serializer.DeserializeBatchEvents(b)
 		if err != nil {
 			ErrorAndExit("DeserializeBatchEvents err", err)
 		}
-		historyBatch := historyBatchThrift
+		historyBatches = append(historyBatches, historyBatch)
 		allEvents.Events = append(allEvents.Events, historyBatch...)
+	}
+
+	printOutput(c, allEvents, func() {
 		encoder := codec.NewJSONPBEncoder()
-		data, err := encoder.EncodeHistoryEvents(historyBatch)
-		if err != nil {
-			ErrorAndExit("EncodeHistoryEvents err", err)
+		for idx, historyBatch := range historyBatches {
+			fmt.Printf("======== batch %v, blob len: %v ======\n", idx+1, len(history[idx].Data))
+			data, err := encoder.EncodeHistoryEvents(historyBatch)
+			if err != nil {
+				ErrorAndExit("EncodeHistoryEvents err", err)
+			}
+			fmt.Println(string(data))
 		}
-		fmt.Println(string(data))
-	}
-	fmt.Printf("======== total batches %v, total blob len: %v ======\n", len(history), totalSize)
+		fmt.Printf("======== total batches %v, total blob len: %v ======\n", len(history), totalSize)
+	})
 
 	if outputFileName != "" {
 		encoder := codec.NewJSONPBEncoder()
@@ -115,42 +138,68 @@ func AdminShowWorkflow(c *cli.Context) {
 func AdminDescribeWorkflow(c *cli.Context) {
 
 	resp := describeMutableState(c)
-	prettyPrintJSONObject(resp)
-
-	if resp != nil {
-		msStr := resp.GetMutableStateInDatabase()
-		ms := persistence.WorkflowMutableState{}
-		// TODO: this won't work for some cases because json.Unmarshal can't be used for proto object
-		// Proper refactoring is required here: resp.GetMutableStateInDatabase() should return proto object.
-		err := json.Unmarshal([]byte(msStr), &ms)
-		if err != nil {
-			ErrorAndExit("json.Unmarshal err", err)
-		}
-		currentBranchToken := ms.ExecutionInfo.BranchToken
-		if ms.VersionHistories != nil {
-			// if VersionHistories is set, then all branch infos are stored in VersionHistories
-			currentVersionHistory, err := ms.VersionHistories.GetCurrentVersionHistory()
-			if err != nil {
-				ErrorAndExit("ms.VersionHistories.GetCurrentVersionHistory err", err)
-			}
-			currentBranchToken = currentVersionHistory.GetBranchToken()
-		}
-
-		branchInfo := persistenceblobs.HistoryBranch{}
-		err = branchInfo.Unmarshal(currentBranchToken)
-		if err != nil {
-			ErrorAndExit("failed to unmarshal current branch token from proto", err)
-		}
-		prettyPrintJSONObject(branchInfo)
-		if ms.ExecutionInfo.AutoResetPoints != nil {
+	output := newAdminDescribeWorkflowOutput(resp)
+	printOutput(c, output, func() {
+		prettyPrintJSONObject(resp)
+		prettyPrintJSONObject(output.CurrentBranch)
+		if len(output.AutoResetPoints) > 0 {
 			fmt.Println("auto-reset-points:")
-			for _, p := range ms.ExecutionInfo.AutoResetPoints.Points {
+			for _, p := range output.AutoResetPoints {
 				createT := time.Unix(0, p.GetCreatedTimeNano())
 				expireT := time.Unix(0, p.GetExpiringTimeNano())
 				fmt.Println(p.GetBinaryChecksum(), p.GetRunId(), p.GetFirstDecisionCompletedId(), p.GetResettable(), createT, expireT)
 			}
 		}
+	})
+}
+
+// adminDescribeWorkflowOutput is the structured output of AdminDescribeWorkflow, it adds the decoded
+// current branch and the auto reset points to the raw mutable state
+type adminDescribeWorkflowOutput struct {
+	ShardID                string                          `json:"shardId"`
+	HistoryAddress         string                          `json:"historyAddr"`
+	MutableStateInCache    string                          `json:"mutableStateInCache,omitempty"`
+	MutableStateInDatabase string                          `json:"mutableStateInDatabase"`
+	CurrentBranch          *persistenceblobs.HistoryBranch `json:"currentBranch"`
+	AutoResetPoints        []*commonproto.ResetPointInfo   `json:"autoResetPoints,omitempty"`
+}
+
+func newAdminDescribeWorkflowOutput(resp *adminservice.DescribeWorkflowExecutionResponse) *adminDescribeWorkflowOutput {
+	msStr := resp.GetMutableStateInDatabase()
+	ms := persistence.WorkflowMutableState{}
+	// TODO: this won't work for some cases because json.Unmarshal can't be used for proto object
+	// Proper refactoring is required here: resp.GetMutableStateInDatabase() should return proto object.
+	err := json.Unmarshal([]byte(msStr), &ms)
+	if err != nil {
+		ErrorAndExit("json.Unmarshal err", err)
+	}
+	currentBranchToken := ms.ExecutionInfo.BranchToken
+	if ms.VersionHistories != nil {
+		// if VersionHistories is set, then all branch infos are stored in VersionHistories
+		currentVersionHistory, err := ms.VersionHistories.GetCurrentVersionHistory()
+		if err != nil {
+			ErrorAndExit("ms.VersionHistories.GetCurrentVersionHistory err", err)
+		}
+		currentBranchToken = currentVersionHistory.GetBranchToken()
+	}
+
+	branchInfo := &persistenceblobs.HistoryBranch{}
+	err = branchInfo.Unmarshal(currentBranchToken)
+	if err != nil {
+		ErrorAndExit("failed to unmarshal current branch token from proto", err)
+	}
+
+	output := &adminDescribeWorkflowOutput{
+		ShardID:                resp.GetShardId(),
+		HistoryAddress:         resp.GetHistoryAddr(),
+		MutableStateInCache:    resp.GetMutableStateInCache(),
+		MutableStateInDatabase: msStr,
+		CurrentBranch:          branchInfo,
 	}
+	if ms.ExecutionInfo.AutoResetPoints != nil {
+		output.AutoResetPoints = ms.ExecutionInfo.AutoResetPoints.Points
+	}
+	return output
 }
 
 func describeMutableState(c *cli.Context) *adminservice.DescribeWorkflowExecutionResponse {
@@ -206,13 +255,21 @@ func AdminDeleteWorkflow(c *cli.Context) {
 		}
 	}
 
+	output := &adminDeleteWorkflowOutput{
+		DomainID:   domainID,
+		WorkflowID: wid,
+		RunID:      rid,
+		ShardID:    shardIDInt,
+	}
 	for _, branchToken := range branchTokens {
 		branchInfo, err := serialization.HistoryBranchFromBlob(branchToken, common.EncodingTypeProto3.String())
 		if err != nil {
 			ErrorAndExit("thriftrwEncoder.Decode err", err)
 		}
-		fmt.Println("deleting history events for ...")
-		prettyPrintJSONObject(branchInfo)
+		printMessage(c, "deleting history events for ...")
+		if isTableOutput(c) {
+			prettyPrintJSONObject(branchInfo)
+		}
 		histV2 := cassp.NewHistoryV2PersistenceFromSession(session, loggerimpl.NewNopLogger())
 		err = histV2.DeleteHistoryBranch(&persistence.InternalDeleteHistoryBranchRequest{
 			BranchInfo: branchInfo,
@@ -220,11 +277,13 @@ func AdminDeleteWorkflow(c *cli.Context) {
 		})
 		if err != nil {
 			if skipError {
-				fmt.Println("failed to delete history, ", err)
+				printMessage(c, "failed to delete history, %v", err)
+				continue
 			} else {
 				ErrorAndExit("DeleteHistoryBranch err", err)
 			}
 		}
+		output.DeletedBranches = append(output.DeletedBranches, branchInfo)
 	}
 
 	exeStore, _ := cassp.NewWorkflowExecutionPersistence(shardIDInt, session, loggerimpl.NewNopLogger())
@@ -237,12 +296,14 @@ func AdminDeleteWorkflow(c *cli.Context) {
 	err = exeStore.DeleteWorkflowExecution(req)
 	if err != nil {
 		if skipError {
-			fmt.Println("delete mutableState row failed, ", err)
+			printMessage(c, "delete mutableState row failed, %v", err)
 		} else {
 			ErrorAndExit("delete mutableState row failed", err)
 		}
+	} else {
+		output.MutableStateDeleted = true
+		printMessage(c, "delete mutableState row successfully")
 	}
-	fmt.Println("delete mutableState row successfully")
 
 	deleteCurrentReq := &persistence.DeleteCurrentWorkflowExecutionRequest{
 		DomainID:   domainID,
@@ -253,12 +314,29 @@ func AdminDeleteWorkflow(c *cli.Context) {
 	err = exeStore.DeleteCurrentWorkflowExecution(deleteCurrentReq)
 	if err != nil {
 		if skipError {
-			fmt.Println("delete current row failed, ", err)
+			printMessage(c, "delete current row failed, %v", err)
 		} else {
 			ErrorAndExit("delete current row failed", err)
 		}
+	} else {
+		output.CurrentRowDeleted = true
+		printMessage(c, "delete current row successfully")
+	}
+
+	if !isTableOutput(c) {
+		printObject(c, output)
 	}
-	fmt.Println("delete current row successfully")
+}
+
+// adminDeleteWorkflowOutput is the structured output of AdminDeleteWorkflow
+type adminDeleteWorkflowOutput struct {
+	DomainID            string                            `json:"domainId"`
+	WorkflowID          string                            `json:"workflowId"`
+	RunID               string                            `json:"runId"`
+	ShardID             int                               `json:"shardId"`
+	DeletedBranches     []*persistenceblobs.HistoryBranch `json:"deletedBranches"`
+	MutableStateDeleted bool                              `json:"mutableStateDeleted"`
+	CurrentRowDeleted   bool                              `json:"currentRowDeleted"`
 }
 
 func readOneRow(query *gocql.Query) (map[string]interface{}, error) {
@@ -303,6 +381,21 @@ func connectToCassandra(c *cli.Context) *gocql.Session {
 	return session
 }
 
+// newHistoryShardRouter returns a router following the history shard routing persisted in the cluster,
+// so that the workflows of split shards are mapped to the shards serving them
+func newHistoryShardRouter(session *gocql.Session) sharding.Router {
+	logger := loggerimpl.NewNopLogger()
+	clusterMetadataMgr := persistence.NewClusterMetadataManagerImpl(
+		cassp.NewClusterMetadataPersistenceFromSession(session, logger),
+		logger,
+	)
+	resp, err := clusterMetadataMgr.GetHistoryShardRouting()
+	if err != nil {
+		ErrorAndExit("Failed to load the history shard routing", err)
+	}
+	return sharding.NewStaticRouterFromRouting(resp.Routing)
+}
+
 // AdminGetDomainIDOrName map domain
 func AdminGetDomainIDOrName(c *cli.Context) {
 	domainID := c.String(FlagDomainID)
@@ -322,7 +415,9 @@ func AdminGetDomainIDOrName(c *cli.Context) {
 		}
 		domain := res["domain"].(map[string]interface{})
 		domainName := domain["name"].(string)
-		fmt.Printf("domainName for domainID %v is %v \n", domainID, domainName)
+		printOutput(c, &domainIDAndNameOutput{DomainID: domainID, DomainName: domainName}, func() {
+			fmt.Printf("domainName for domainID %v is %v \n", domainID, domainName)
+		})
 	} else {
 		tmpl := "select domain from domains_by_name where name = ?"
 		tmplV2 := "select domain from domains_by_name_v2 where domains_partition=0 and name = ?"
@@ -330,35 +425,64 @@ func AdminGetDomainIDOrName(c *cli.Context) {
 		query := session.Query(tmpl, domainName)
 		res, err := readOneRow(query)
 		if err != nil {
-			fmt.Printf("v1 return error: %v , trying v2...\n", err)
+			printMessage(c, "v1 return error: %v , trying v2...", err)
 
 			query := session.Query(tmplV2, domainName)
-			res, err := readOneRow(query)
+			res, err = readOneRow(query)
 			if err != nil {
 				ErrorAndExit("readOneRow for v2", err)
 			}
-			domain := res["domain"].(map[string]interface{})
-			domainID := domain["id"].(gocql.UUID).String()
-			fmt.Printf("domainID for domainName %v is %v \n", domainName, domainID)
-		} else {
-			domain := res["domain"].(map[string]interface{})
-			domainID := domain["id"].(gocql.UUID).String()
-			fmt.Printf("domainID for domainName %v is %v \n", domainName, domainID)
 		}
+		domain := res["domain"].(map[string]interface{})
+		domainID := domain["id"].(gocql.UUID).String()
+		printOutput(c, &domainIDAndNameOutput{DomainID: domainID, DomainName: domainName}, func() {
+			fmt.Printf("domainID for domainName %v is %v \n", domainName, domainID)
+		})
 	}
 }
 
+// domainIDAndNameOutput is the structured output of AdminGetDomainIDOrName
+type domainIDAndNameOutput struct {
+	DomainID   string `json:"domainId"`
+	DomainName string `json:"domainName"`
+}
+
 // AdminGetShardID get shardID
 func AdminGetShardID(c *cli.Context) {
 	wid := getRequiredOption(c, FlagWorkflowID)
-	numberOfShards := c.Int(FlagNumberOfShards)
 
-	if numberOfShards <= 0 {
-		ErrorAndExit("numberOfShards is required", nil)
-		return
+	var router sharding.Router
+	if c.IsSet(FlagDBAddress) {
+		session := connectToCassandra(c)
+		defer session.Close()
+		router = newHistoryShardRouter(session)
+	} else {
+		numberOfShards := c.Int(FlagNumberOfShards)
+		if numberOfShards <= 0 {
+			ErrorAndExit("numberOfShards or the persistence address is required", nil)
+			return
+		}
+		router = sharding.NewStaticRouter(numberOfShards)
+	}
+	shardID := router.GetShardID(wid)
+	printOutput(c, &shardIDOutput{WorkflowID: wid, ShardID: shardID}, func() {
+		fmt.Printf("ShardID for workflowID: %v is %v \n", wid, shardID)
+	})
+}
+
+func isServingShard(router sharding.Router, shardID int) bool {
+	for _, servingShardID := range router.GetShardIDs() {
+		if servingShardID == shardID {
+			return true
+		}
 	}
-	shardID := common.WorkflowIDToHistoryShard(wid, numberOfShards)
-	fmt.Printf("ShardID for workflowID: %v is %v \n", wid, shardID)
+	return false
+}
+
+// shardIDOutput is the structured output of AdminGetShardID
+type shardIDOutput struct {
+	WorkflowID string `json:"workflowId"`
+	ShardID    int    `json:"shardId"`
 }
 
 // AdminRemoveTask describes history host
@@ -401,6 +525,45 @@ func AdminShardManagement(c *cli.Context) {
 	}
 }
 
+// AdminShardScan checks the workflow executions of a shard for corruptions
+func AdminShardScan(c *cli.Context) {
+	shardID := getRequiredIntOption(c, FlagShardID)
+
+	session := connectToCassandra(c)
+	defer session.Close()
+	logger := loggerimpl.NewNopLogger()
+
+	router := newHistoryShardRouter(session)
+	if !isServingShard(router, shardID) {
+		ErrorAndExit(fmt.Sprintf("ShardID must be one of %v.", router.GetShardIDs()), nil)
+	}
+
+	executionStore, err := cassp.NewWorkflowExecutionPersistence(shardID, session, logger)
+	if err != nil {
+		ErrorAndExit("Failed to create execution persistence", err)
+	}
+	executionManager := persistence.NewExecutionManagerImpl(executionStore, logger)
+	historyManager := persistence.NewHistoryV2ManagerImpl(
+		cassp.NewHistoryV2PersistenceFromSession(session, logger),
+		logger,
+		dynamicconfig.GetIntPropertyFn(common.DefaultTransactionSizeLimit),
+	)
+
+	checker := executions.NewShardChecker(shardID, router, executionManager, historyManager, c.Bool(FlagFix), logger)
+	report, err := checker.Run(c.Bool(FlagCheckHistoryBranches))
+	if err != nil {
+		ErrorAndExit("Shard scan has failed", err)
+	}
+
+	printOutput(c, report, func() {
+		fmt.Printf("Shard %v: checked %v executions and %v history branches, found %v corruptions\n",
+			report.ShardID, report.ExecutionCount, report.BranchCount, len(report.Failures))
+		for _, failure := range report.Failures {
+			fmt.Println(failure.String())
+		}
+	})
+}
+
 // AdminDescribeHistoryHost describes history host
 func AdminDescribeHistoryHost(c *cli.Context) {
 	adminClient := cFactory.AdminClient(c)
@@ -437,7 +600,7 @@ func AdminDescribeHistoryHost(c *cli.Context) {
 	if !printFully {
 		resp.ShardIDs = nil
 	}
-	prettyPrintJSONObject(resp)
+	printObject(c, resp)
 }
 
 // AdminRefreshWorkflowTasks refreshes all the tasks of a workflow
@@ -461,6 +624,168 @@ func AdminRefreshWorkflowTasks(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Refresh workflow task failed", err)
 	} else {
-		fmt.Println("Refresh workflow task succeeded.")
+		printMessage(c, "Refresh workflow task succeeded.")
+	}
+}
+
+// AdminRehydrateWorkflow restores an archived workflow execution as a closed workflow
+func AdminRehydrateWorkflow(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := getRequiredOption(c, FlagRunID)
+
+	// reading the archived history may take a while
+	ctx, cancel := newContextForLongPoll(c)
+	defer cancel()
+
+	resp, err := adminClient.RehydrateWorkflowExecution(ctx, &adminservice.RehydrateWorkflowExecutionRequest{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: wid,
+			RunId:      rid,
+		},
+	})
+	if err != nil {
+		ErrorAndExit("Rehydrate workflow failed", err)
+	}
+	printOutput(c, resp, func() {
+		fmt.Printf("Rehydrate workflow succeeded, the workflow will be deleted again at %v.\n", convertTime(resp.GetExpirationTime(), false))
+	})
+}
+
+// AdminExportWorkflow exports the history and mutable state of a workflow execution into a file
+func AdminExportWorkflow(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := c.String(FlagRunID)
+	outputFileName := getRequiredOption(c, FlagOutputFilename)
+
+	ctx, cancel := newContextForLongPoll(c)
+	defer cancel()
+
+	exported := &exportedWorkflow{Domain: domain}
+	request := &adminservice.ExportWorkflowExecutionRequest{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: wid,
+			RunId:      rid,
+		},
+		MaximumPageSize: exportWorkflowPageSize,
+	}
+	for {
+		resp, err := adminClient.ExportWorkflowExecution(ctx, request)
+		if err != nil {
+			ErrorAndExit("Export workflow failed", err)
+		}
+		if len(request.NextPageToken) == 0 {
+			exported.WorkflowID = resp.GetExecution().GetWorkflowId()
+			exported.RunID = resp.GetExecution().GetRunId()
+			exported.MutableState = resp.GetMutableState()
+			// following pages must read the history of the same run
+			request.Execution = resp.GetExecution()
+		}
+		exported.HistoryBatches = append(exported.HistoryBatches, resp.GetHistoryBatches()...)
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		ErrorAndExit("Failed to serialize exported workflow.", err)
+	}
+	if err := ioutil.WriteFile(outputFileName, data, 0666); err != nil {
+		ErrorAndExit("Failed to write exported workflow file.", err)
+	}
+	output := &transferWorkflowOutput{
+		WorkflowID:     exported.WorkflowID,
+		RunID:          exported.RunID,
+		HistoryBatches: len(exported.HistoryBatches),
+	}
+	printOutput(c, output, func() {
+		fmt.Printf("Export workflow succeeded, run %v with %v history batches written to %v.\n", output.RunID, output.HistoryBatches, outputFileName)
+	})
+}
+
+// transferWorkflowOutput is the structured output of AdminExportWorkflow and AdminImportWorkflow
+type transferWorkflowOutput struct {
+	WorkflowID     string `json:"workflowId"`
+	RunID          string `json:"runId"`
+	HistoryBatches int    `json:"historyBatches"`
+}
+
+// AdminImportWorkflow imports a workflow execution exported from another cluster
+func AdminImportWorkflow(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+
+	inputFileName := getRequiredOption(c, FlagInputFile)
+	data, err := ioutil.ReadFile(inputFileName)
+	if err != nil {
+		ErrorAndExit("Failed to read exported workflow file.", err)
+	}
+	exported := &exportedWorkflow{}
+	if err := json.Unmarshal(data, exported); err != nil {
+		ErrorAndExit("Failed to deserialize exported workflow.", err)
+	}
+	domain := exported.Domain
+	if c.GlobalIsSet(FlagDomain) {
+		// allows to import into a domain with a different name
+		domain = c.GlobalString(FlagDomain)
+	}
+
+	ctx, cancel := newContextForLongPoll(c)
+	defer cancel()
+
+	request := &adminservice.ImportWorkflowExecutionRequest{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: exported.WorkflowID,
+			RunId:      exported.RunID,
+		},
+	}
+	pages := splitHistoryBatches(exported.HistoryBatches, importWorkflowMaxPageSizeInBytes)
+	for i, page := range pages {
+		request.HistoryBatches = page
+		if i == len(pages)-1 {
+			request.LastPage = true
+			request.MutableState = exported.MutableState
+		}
+		resp, err := adminClient.ImportWorkflowExecution(ctx, request)
+		if err != nil {
+			ErrorAndExit("Import workflow failed", err)
+		}
+		request.NextPageToken = resp.GetNextPageToken()
+	}
+
+	output := &transferWorkflowOutput{
+		WorkflowID:     exported.WorkflowID,
+		RunID:          exported.RunID,
+		HistoryBatches: len(exported.HistoryBatches),
+	}
+	printOutput(c, output, func() {
+		fmt.Printf("Import workflow succeeded, run %v with %v history batches imported.\n", output.RunID, output.HistoryBatches)
+	})
+}
+
+// splitHistoryBatches splits the history into pages of at most maxPageSizeInBytes,
+// a single batch larger than that becomes a page of its own
+func splitHistoryBatches(historyBatches []*commonproto.DataBlob, maxPageSizeInBytes int) [][]*commonproto.DataBlob {
+	pages := [][]*commonproto.DataBlob{nil}
+	pageSize := 0
+	for _, batch := range historyBatches {
+		last := len(pages) - 1
+		if len(pages[last]) > 0 && pageSize+len(batch.GetData()) > maxPageSizeInBytes {
+			pages = append(pages, nil)
+			last++
+			pageSize = 0
+		}
+		pages[last] = append(pages[last], batch)
+		pageSize += len(batch.GetData())
 	}
+	return pages
 }
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonproto "go.temporal.io/temporal-proto/common"
+)
+
+func TestSplitHistoryBatches(t *testing.T) {
+	batch := func(size int) *commonproto.DataBlob {
+		return &commonproto.DataBlob{Data: make([]byte, size)}
+	}
+	historyBatches := []*commonproto.DataBlob{batch(4), batch(4), batch(12), batch(1), batch(1)}
+
+	pages := splitHistoryBatches(historyBatches, 10)
+	require.Equal(t, [][]*commonproto.DataBlob{
+		historyBatches[0:2],
+		historyBatches[2:3],
+		historyBatches[3:5],
+	}, pages)
+}
+
+func TestSplitHistoryBatches_Empty(t *testing.T) {
+	pages := splitHistoryBatches(nil, 10)
+	require.Len(t, pages, 1)
+	require.Empty(t, pages[0])
+}
@@ -42,11 +42,16 @@ const (
 
 // AdminGetDLQMessages gets DLQ metadata
 func AdminGetDLQMessages(c *cli.Context) {
+	dlqType := getRequiredOption(c, FlagDLQType)
+	if isTaskDLQType(dlqType) {
+		adminGetTaskDLQMessages(c, dlqType)
+		return
+	}
+
 	ctx, cancel := newContext(c)
 	defer cancel()
 
 	adminClient := cFactory.AdminClient(c)
-	dlqType := getRequiredOption(c, FlagDLQType)
 	outputFile := getOutputFile(c.String(FlagOutputFilename))
 	defer outputFile.Close()
 
@@ -102,11 +107,15 @@ func AdminGetDLQMessages(c *cli.Context) {
 
 // AdminPurgeDLQMessages deletes messages from DLQ
 func AdminPurgeDLQMessages(c *cli.Context) {
+	dlqType := getRequiredOption(c, FlagDLQType)
+	if isTaskDLQType(dlqType) {
+		adminPurgeTaskDLQMessages(c, dlqType)
+		return
+	}
+
 	ctx, cancel := newContext(c)
 	defer cancel()
 
-	dlqType := getRequiredOption(c, FlagDLQType)
-
 	var lastMessageID int64
 	if c.IsSet(FlagLastMessageID) {
 		lastMessageID = c.Int64(FlagLastMessageID)
@@ -119,18 +128,22 @@ func AdminPurgeDLQMessages(c *cli.Context) {
 		Type:                  toQueueType(dlqType),
 		InclusiveEndMessageID: lastMessageID,
 	}); err != nil {
-		ErrorAndExit("Failed to purge dlq", nil)
+		ErrorAndExit("Failed to purge dlq", err)
 	}
-	fmt.Println("Successfully purge DLQ Messages.")
+	printMessage(c, "Successfully purge DLQ Messages.")
 }
 
 // AdminMergeDLQMessages merges message from DLQ
 func AdminMergeDLQMessages(c *cli.Context) {
+	dlqType := getRequiredOption(c, FlagDLQType)
+	if isTaskDLQType(dlqType) {
+		adminMergeTaskDLQMessages(c, dlqType)
+		return
+	}
+
 	ctx, cancel := newContext(c)
 	defer cancel()
 
-	dlqType := getRequiredOption(c, FlagDLQType)
-
 	var lastMessageID int64
 	if c.IsSet(FlagLastMessageID) {
 		lastMessageID = c.Int64(FlagLastMessageID)
@@ -154,9 +167,151 @@ func AdminMergeDLQMessages(c *cli.Context) {
 		}
 
 		request.NextPageToken = response.NextPageToken
-		fmt.Printf("Successfully merged %v messages. More messages to merge.\n", defaultPageSize)
+		printMessage(c, "Successfully merged %v messages. More messages to merge.", defaultPageSize)
+	}
+	printMessage(c, "Successfully merged all messages.")
+}
+
+// AdminDescribeTaskDLQMessage describes a transfer or timer task in the DLQ of a shard
+func AdminDescribeTaskDLQMessage(c *cli.Context) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	dlqType := getRequiredOption(c, FlagDLQType)
+	if !isTaskDLQType(dlqType) {
+		ErrorAndExit("Only transfer and timer DLQ messages can be described.", fmt.Errorf("the queue type is not supported. Type: %v", dlqType))
+	}
+	shardID := getRequiredIntOption(c, FlagShardID)
+	messageID := getRequiredInt64Option(c, FlagMessageID)
+
+	adminClient := cFactory.AdminClient(c)
+	resp, err := adminClient.ReadTaskDLQMessages(ctx, &adminservice.ReadTaskDLQMessagesRequest{
+		ShardID:                 int32(shardID),
+		TaskCategory:            dlqType,
+		ExclusiveBeginMessageID: messageID - 1,
+		InclusiveEndMessageID:   messageID,
+		MaximumPageSize:         1,
+	})
+	if err != nil {
+		ErrorAndExit("Failed to read DLQ message.", err)
+	}
+	if len(resp.GetMessages()) == 0 {
+		ErrorAndExit(fmt.Sprintf("Message %v is not in the DLQ.", messageID), nil)
+	}
+	prettyPrintJSONObject(resp.GetMessages()[0])
+}
+
+func adminGetTaskDLQMessages(c *cli.Context, dlqType string) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	adminClient := cFactory.AdminClient(c)
+	shardID := getRequiredIntOption(c, FlagShardID)
+	outputFile := getOutputFile(c.String(FlagOutputFilename))
+	defer outputFile.Close()
+
+	remainingMessageCount := common.EndMessageID
+	if c.IsSet(FlagMaxMessageCount) {
+		remainingMessageCount = c.Int64(FlagMaxMessageCount)
+	}
+
+	paginationFunc := func(paginationToken []byte) ([]interface{}, []byte, error) {
+		resp, err := adminClient.ReadTaskDLQMessages(ctx, &adminservice.ReadTaskDLQMessagesRequest{
+			ShardID:                 int32(shardID),
+			TaskCategory:            dlqType,
+			ExclusiveBeginMessageID: c.Int64(FlagFirstMessageID),
+			InclusiveEndMessageID:   c.Int64(FlagLastMessageID),
+			MaximumPageSize:         defaultPageSize,
+			NextPageToken:           paginationToken,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		var paginateItems []interface{}
+		for _, item := range resp.GetMessages() {
+			paginateItems = append(paginateItems, item)
+		}
+		return paginateItems, resp.GetNextPageToken(), err
+	}
+
+	iterator := collection.NewPagingIterator(paginationFunc)
+	var lastReadMessageID int64
+	for iterator.HasNext() && remainingMessageCount > 0 {
+		item, err := iterator.Next()
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("fail to read dlq message. Last read message id: %v", lastReadMessageID), err)
+		}
+
+		message := item.(*adminservice.TaskDLQMessage)
+		encoder := codec.NewJSONPBIndentEncoder(" ")
+		messageStr, err := encoder.Encode(message)
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("fail to encode dlq message. Last read message id: %v", lastReadMessageID), err)
+		}
+
+		lastReadMessageID = message.GetTaskID()
+		remainingMessageCount--
+		_, err = outputFile.WriteString(fmt.Sprintf("%v\n", string(messageStr)))
+		if err != nil {
+			ErrorAndExit("fail to print dlq messages.", err)
+		}
+	}
+}
+
+func adminPurgeTaskDLQMessages(c *cli.Context, dlqType string) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	shardID := getRequiredIntOption(c, FlagShardID)
+	if !c.IsSet(FlagLastMessageID) {
+		confirmOrExit("Are you sure to purge all DLQ messages without a upper boundary?")
+	}
+
+	adminClient := cFactory.AdminClient(c)
+	if _, err := adminClient.PurgeTaskDLQMessages(ctx, &adminservice.PurgeTaskDLQMessagesRequest{
+		ShardID:                 int32(shardID),
+		TaskCategory:            dlqType,
+		ExclusiveBeginMessageID: c.Int64(FlagFirstMessageID),
+		InclusiveEndMessageID:   c.Int64(FlagLastMessageID),
+	}); err != nil {
+		ErrorAndExit("Failed to purge dlq", err)
+	}
+	printMessage(c, "Successfully purge DLQ Messages.")
+}
+
+func adminMergeTaskDLQMessages(c *cli.Context, dlqType string) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	shardID := getRequiredIntOption(c, FlagShardID)
+	if !c.IsSet(FlagLastMessageID) {
+		confirmOrExit("Are you sure to merge all DLQ messages without a upper boundary?")
+	}
+
+	adminClient := cFactory.AdminClient(c)
+	request := &adminservice.MergeTaskDLQMessagesRequest{
+		ShardID:                 int32(shardID),
+		TaskCategory:            dlqType,
+		ExclusiveBeginMessageID: c.Int64(FlagFirstMessageID),
+		InclusiveEndMessageID:   c.Int64(FlagLastMessageID),
+		MaximumPageSize:         defaultPageSize,
+	}
+
+	var response *adminservice.MergeTaskDLQMessagesResponse
+	var err error
+	for response == nil || len(response.GetNextPageToken()) > 0 {
+		response, err = adminClient.MergeTaskDLQMessages(ctx, request)
+		if err != nil {
+			ErrorAndExit("Failed to merge DLQ message", err)
+		}
+
+		request.NextPageToken = response.NextPageToken
 	}
-	fmt.Println("Successfully merged all messages.")
+	printMessage(c, "Successfully merged all messages.")
+}
+
+func isTaskDLQType(dlqType string) bool {
+	return dlqType == "transfer" || dlqType == "timer"
 }
 
 func toQueueType(dlqType string) enums.DLQType {
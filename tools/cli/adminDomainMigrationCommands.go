@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"github.com/urfave/cli"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	sdkclient "go.temporal.io/temporal/client"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/service/worker/migration"
+)
+
+// AdminMigrateDomain starts or resumes the migration of a local domain from the source cluster into
+// the cluster tctl is connected to, it also describes and aborts a running migration
+func AdminMigrateDomain(c *cli.Context) {
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	workflowID := migration.MigrationWFIDPrefix + domain
+	client := cFactory.SDKClient(c, common.SystemLocalDomainName)
+
+	switch {
+	case c.Bool(FlagDescribe):
+		state, running, err := getDomainMigrationState(c, client, workflowID)
+		if err != nil {
+			ErrorAndExit("Failed to describe domain migration", err)
+		}
+		printObject(c, map[string]interface{}{
+			"running": running,
+			"state":   state,
+		})
+	case c.Bool(FlagAbort):
+		tcCtx, cancel := newContext(c)
+		defer cancel()
+		if err := client.CancelWorkflow(tcCtx, workflowID, ""); err != nil {
+			ErrorAndExit("Failed to abort domain migration", err)
+		}
+		printMessage(c, "Domain migration is aborted, the domain is unlocked in the source cluster unless it was deprecated already.")
+	default:
+		startDomainMigration(c, client, domain, workflowID)
+	}
+}
+
+func startDomainMigration(c *cli.Context, client sdkclient.Client, domain string, workflowID string) {
+	sourceCluster := getRequiredOption(c, FlagSourceCluster)
+
+	state, running, err := getDomainMigrationState(c, client, workflowID)
+	if err != nil {
+		ErrorAndExit("Failed to get the checkpoint of the last domain migration", err)
+	}
+	if running {
+		ErrorAndExit("Domain migration is already running, use --"+FlagDescribe+" to see its progress", nil)
+	}
+	if state.Phase == migration.MigrationPhaseCompleted {
+		ErrorAndExit("Domain is already migrated", nil)
+	}
+
+	params := migration.MigrationParams{
+		DomainName:    domain,
+		SourceCluster: sourceCluster,
+		RPS:           c.Int(FlagRPS),
+		Concurrency:   c.Int(FlagConcurrency),
+		LagThreshold:  c.Int(FlagLagThreshold),
+		State:         state,
+	}
+	options := sdkclient.StartWorkflowOptions{
+		ID:                           workflowID,
+		TaskList:                     migration.MigratorTaskListName,
+		ExecutionStartToCloseTimeout: migration.InfiniteDuration,
+	}
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	wf, err := client.ExecuteWorkflow(tcCtx, options, migration.MigrationWFTypeName, params)
+	if err != nil {
+		ErrorAndExit("Failed to start domain migration", err)
+	}
+	output := map[string]interface{}{
+		"msg":   "domain migration is started",
+		"runID": wf.GetRunID(),
+	}
+	if state.Phase != "" {
+		output["resumedFrom"] = state
+	}
+	printObject(c, output)
+}
+
+// getDomainMigrationState returns the checkpoint of the last run of the migration workflow
+func getDomainMigrationState(c *cli.Context, client sdkclient.Client, workflowID string) (migration.MigrationState, bool, error) {
+	state := migration.MigrationState{}
+
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	resp, err := client.DescribeWorkflowExecution(tcCtx, workflowID, "")
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return state, false, nil
+		}
+		return state, false, err
+	}
+	running := resp.WorkflowExecutionInfo.GetCloseStatus() == enums.WorkflowExecutionCloseStatusRunning
+
+	queryCtx, queryCancel := newContext(c)
+	defer queryCancel()
+	value, err := client.QueryWorkflow(queryCtx, workflowID, resp.WorkflowExecutionInfo.GetExecution().GetRunId(), migration.MigrationStateQueryType)
+	if err != nil {
+		return state, running, err
+	}
+	if err := value.Get(&state); err != nil {
+		return state, running, err
+	}
+	return state, running, nil
+}
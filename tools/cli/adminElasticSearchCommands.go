@@ -27,6 +27,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -105,23 +106,61 @@ func AdminCatIndices(c *cli.Context) {
 		ErrorAndExit("Unable to cat indices", err)
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	header := []string{"health", "status", "index", "pri", "rep", "docs.count", "docs.deleted", "store.size", "pri.store.size"}
-	table.SetHeader(header)
-	for _, row := range resp {
-		data := make([]string, len(header))
-		data[0] = row.Health
-		data[1] = row.Status
-		data[2] = row.Index
-		data[3] = strconv.Itoa(row.Pri)
-		data[4] = strconv.Itoa(row.Rep)
-		data[5] = strconv.Itoa(row.DocsCount)
-		data[6] = strconv.Itoa(row.DocsDeleted)
-		data[7] = row.StoreSize
-		data[8] = row.PriStoreSize
-		table.Append(data)
+	printOutput(c, resp, func() {
+		table := tablewriter.NewWriter(os.Stdout)
+		header := []string{"health", "status", "index", "pri", "rep", "docs.count", "docs.deleted", "store.size", "pri.store.size"}
+		table.SetHeader(header)
+		for _, row := range resp {
+			data := make([]string, len(header))
+			data[0] = row.Health
+			data[1] = row.Status
+			data[2] = row.Index
+			data[3] = strconv.Itoa(row.Pri)
+			data[4] = strconv.Itoa(row.Rep)
+			data[5] = strconv.Itoa(row.DocsCount)
+			data[6] = strconv.Itoa(row.DocsDeleted)
+			data[7] = row.StoreSize
+			data[8] = row.PriStoreSize
+			table.Append(data)
+		}
+		table.Render()
+	})
+}
+
+// AdminGetMappingVersion shows the schema version of the index mapping
+func AdminGetMappingVersion(c *cli.Context) {
+	esURL, err := url.Parse(getRequiredOption(c, FlagURL))
+	if err != nil {
+		ErrorAndExit("Invalid ElasticSearch URL", err)
 	}
-	table.Render()
+	index := getRequiredOption(c, FlagIndex)
+	esClient, err := es.NewClient(&es.Config{
+		URL:     *esURL,
+		Version: c.String(FlagESVersion),
+	})
+	if err != nil {
+		ErrorAndExit("Unable to create ElasticSearch client", err)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	version, err := es.GetSchemaVersion(ctx, esClient, index)
+	if err != nil {
+		ErrorAndExit("Unable to get index mapping", err)
+	}
+	printOutput(c, &mappingVersionOutput{Index: index, Version: version}, func() {
+		if version == "" {
+			fmt.Printf("Index %s has no schema version, it was not set up by temporal-es-tool.\n", index)
+			return
+		}
+		fmt.Printf("Index %s schema version: %s\n", index, version)
+	})
+}
+
+// mappingVersionOutput is the structured output of AdminGetMappingVersion
+type mappingVersionOutput struct {
+	Index   string `json:"index"`
+	Version string `json:"version"`
 }
 
 // AdminIndex used to bulk insert message from kafka parse
@@ -131,7 +131,7 @@ func AdminKafkaParse(c *cli.Context) {
 	<-doneCh
 
 	if skipErrMode {
-		fmt.Printf("%v messages were skipped due to errors in parsing", atomic.LoadInt32(&skippedCount))
+		printMessage(c, "%v messages were skipped due to errors in parsing", atomic.LoadInt32(&skippedCount))
 	}
 }
 
@@ -171,8 +171,7 @@ func getInputFile(inputFile string) *os.File {
 			ErrorAndExit("Failed to stat stdin file handle", err)
 		}
 		if info.Mode()&os.ModeCharDevice != 0 || info.Size() <= 0 {
-			fmt.Println("Misuse of pipe mode")
-			os.Exit(1)
+			ErrorAndExit("Misuse of pipe mode", nil)
 		}
 		return os.Stdin
 	}
@@ -479,7 +478,7 @@ type ClustersConfig struct {
 	TLS      auth.TLS
 }
 
-func doRereplicate(shardID int, domainID, wid, rid string, minID, maxID int64, targets []string, producer messaging.Producer, session *gocql.Session) {
+func doRereplicate(c *cli.Context, shardID int, domainID, wid, rid string, minID, maxID int64, targets []string, producer messaging.Producer, session *gocql.Session) {
 	if minID <= 0 {
 		minID = 1
 	}
@@ -494,7 +493,7 @@ func doRereplicate(shardID int, domainID, wid, rid string, minID, maxID int64, t
 	exeMgr := persistence.NewExecutionManagerImpl(exeM, loggerimpl.NewNopLogger())
 
 	for {
-		fmt.Printf("Start rereplicate for wid: %v, rid:%v \n", wid, rid)
+		printMessage(c, "Start rereplicate for wid: %v, rid:%v", wid, rid)
 		resp, err := exeMgr.GetWorkflowExecution(&persistence.GetWorkflowExecutionRequest{
 			DomainID: domainID,
 			Execution: commonproto.WorkflowExecution{
@@ -564,10 +563,10 @@ func doRereplicate(shardID int, domainID, wid, rid string, minID, maxID int64, t
 			if err != nil {
 				ErrorAndExit("Publish task error", err)
 			}
-			fmt.Printf("publish task successfully firstEventID %v, lastEventID %v \n", firstEvent.GetEventId(), lastEvent.GetEventId())
+			printMessage(c, "publish task successfully firstEventID %v, lastEventID %v", firstEvent.GetEventId(), lastEvent.GetEventId())
 		}
 
-		fmt.Printf("Done rereplicate for wid: %v, rid:%v \n", wid, rid)
+		printMessage(c, "Done rereplicate for wid: %v, rid:%v", wid, rid)
 		runtime.GC()
 		if continueAsNew {
 			rid = newRunID
@@ -581,16 +580,12 @@ func doRereplicate(shardID int, domainID, wid, rid string, minID, maxID int64, t
 
 // AdminRereplicate parses will re-publish replication tasks to topic
 func AdminRereplicate(c *cli.Context) {
-	numberOfShards := c.Int(FlagNumberOfShards)
-	if numberOfShards <= 0 {
-		ErrorAndExit("numberOfShards is must be > 0", nil)
-		return
-	}
 	target := getRequiredOption(c, FlagTargetCluster)
 	targets := []string{target}
 
 	producer := newKafkaProducer(c)
 	session := connectToCassandra(c)
+	router := newHistoryShardRouter(session)
 
 	if c.IsSet(FlagInputFile) {
 		inFile := c.String(FlagInputFile)
@@ -609,14 +604,14 @@ func AdminRereplicate(c *cli.Context) {
 			idx++
 			line := strings.TrimSpace(scanner.Text())
 			if len(line) == 0 {
-				fmt.Printf("line %v is empty, skipped\n", idx)
+				printMessage(c, "line %v is empty, skipped", idx)
 				continue
 			}
 			cols := strings.Split(line, ",")
 			if len(cols) < 3 {
 				ErrorAndExit("Split failed", fmt.Errorf("line %v has less than 3 cols separated by comma, only %v ", idx, len(cols)))
 			}
-			fmt.Printf("Start processing line %v ...\n", idx)
+			printMessage(c, "Start processing line %v ...", idx)
 			domainID := strings.TrimSpace(cols[0])
 			wid := strings.TrimSpace(cols[1])
 			rid := strings.TrimSpace(cols[2])
@@ -636,9 +631,9 @@ func AdminRereplicate(c *cli.Context) {
 				maxID = int64(i)
 			}
 
-			shardID := common.WorkflowIDToHistoryShard(wid, numberOfShards)
-			doRereplicate(shardID, domainID, wid, rid, minID, maxID, targets, producer, session)
-			fmt.Printf("Done processing line %v ...\n", idx)
+			shardID := router.GetShardID(wid)
+			doRereplicate(c, shardID, domainID, wid, rid, minID, maxID, targets, producer, session)
+			printMessage(c, "Done processing line %v ...", idx)
 		}
 		if err := scanner.Err(); err != nil {
 			ErrorAndExit("scanner failed", err)
@@ -650,8 +645,8 @@ func AdminRereplicate(c *cli.Context) {
 		minID := c.Int64(FlagMinEventID)
 		maxID := c.Int64(FlagMaxEventID)
 
-		shardID := common.WorkflowIDToHistoryShard(wid, numberOfShards)
-		doRereplicate(shardID, domainID, wid, rid, minID, maxID, targets, producer, session)
+		shardID := router.GetShardID(wid)
+		doRereplicate(c, shardID, domainID, wid, rid, minID, maxID, targets, producer, session)
 	}
 }
 
@@ -691,28 +686,28 @@ func AdminPurgeTopic(c *cli.Context) {
 	group := getRequiredOption(c, FlagGroup)
 	brokers, tlsConfig, err := loadBrokerConfig(hostFile, cluster)
 
-	consumer := createConsumerAndWaitForReady(brokers, tlsConfig, group, topic)
+	consumer := createConsumerAndWaitForReady(c, brokers, tlsConfig, group, topic)
 
 	highWaterMarks, ok := consumer.HighWaterMarks()[topic]
 	if !ok {
 		ErrorAndExit("", fmt.Errorf("cannot find high watermark"))
 	}
-	fmt.Printf("Topic high watermark %v.\n", highWaterMarks)
+	printMessage(c, "Topic high watermark %v.", highWaterMarks)
 	for partition, hi := range highWaterMarks {
 		consumer.MarkPartitionOffset(topic, partition, hi-1, "")
-		fmt.Printf("set partition offset %v:%v \n", partition, hi)
+		printMessage(c, "set partition offset %v:%v", partition, hi)
 	}
 	err = consumer.CommitOffsets()
 	if err != nil {
 		ErrorAndExit("fail to commit offset", err)
 	}
 
-	consumer = createConsumerAndWaitForReady(brokers, tlsConfig, group, topic)
+	consumer = createConsumerAndWaitForReady(c, brokers, tlsConfig, group, topic)
 	msg, ok := <-consumer.Messages()
 	if !ok {
-		fmt.Println("consumer channel is closed")
+		printMessage(c, "consumer channel is closed")
 	}
-	fmt.Printf("current offset sample: %v: %v \n", msg.Partition, msg.Offset)
+	printMessage(c, "current offset sample: %v: %v", msg.Partition, msg.Offset)
 }
 
 // AdminMergeDLQ publish replication tasks from DLQ or JSON file
@@ -730,7 +725,7 @@ func AdminMergeDLQ(c *cli.Context) {
 	if c.IsSet(FlagInputFile) {
 		inFile = c.String(FlagInputFile)
 		// parse json input as replicaiton tasks
-		tasks, err = parseReplicationTask(inFile)
+		tasks, err = parseReplicationTask(c, inFile)
 		if err != nil {
 			ErrorAndExit("", err)
 		}
@@ -738,10 +733,10 @@ func AdminMergeDLQ(c *cli.Context) {
 		for idx, t := range tasks {
 			err := producer.Publish(t)
 			if err != nil {
-				fmt.Printf("cannot publish task %v to topic \n", idx)
+				printMessage(c, "cannot publish task %v to topic", idx)
 				ErrorAndExit("", err)
 			} else {
-				fmt.Printf("replication task sent: %v firstID %v, nextID %v \n", idx, t.GetHistoryTaskAttributes().GetFirstEventId(), t.GetHistoryTaskAttributes().GetNextEventId())
+				printMessage(c, "replication task sent: %v firstID %v, nextID %v", idx, t.GetHistoryTaskAttributes().GetFirstEventId(), t.GetHistoryTaskAttributes().GetNextEventId())
 			}
 		}
 	} else {
@@ -755,23 +750,23 @@ func AdminMergeDLQ(c *cli.Context) {
 			ErrorAndExit("", err)
 		}
 
-		consumer := createConsumerAndWaitForReady(fromBrokers, tlsConfig, group, fromTopic)
+		consumer := createConsumerAndWaitForReady(c, fromBrokers, tlsConfig, group, fromTopic)
 
 		highWaterMarks, ok := consumer.HighWaterMarks()[fromTopic]
 		if !ok {
 			ErrorAndExit("", fmt.Errorf("cannot find high watermark"))
 		}
-		fmt.Printf("Topic high watermark %v.\n", highWaterMarks)
+		printMessage(c, "Topic high watermark %v.", highWaterMarks)
 		for partition := range highWaterMarks {
 			consumer.MarkPartitionOffset(fromTopic, partition, startOffset, "")
-			fmt.Printf("reset offset %v:%v \n", partition, startOffset)
+			printMessage(c, "reset offset %v:%v", partition, startOffset)
 		}
 		err = consumer.CommitOffsets()
 		if err != nil {
 			ErrorAndExit("fail to commit offset", err)
 		}
 		// create consumer again to make sure MarkPartitionOffset works
-		consumer = createConsumerAndWaitForReady(fromBrokers, tlsConfig, group, fromTopic)
+		consumer = createConsumerAndWaitForReady(c, fromBrokers, tlsConfig, group, fromTopic)
 
 		for {
 			select {
@@ -780,7 +775,7 @@ func AdminMergeDLQ(c *cli.Context) {
 					return
 				}
 				if msg.Offset < startOffset {
-					fmt.Printf("Wrong Message [%v],[%v] \n", msg.Partition, msg.Offset)
+					printMessage(c, "Wrong Message [%v],[%v]", msg.Partition, msg.Offset)
 					ErrorAndExit("", fmt.Errorf("offset is not correct"))
 					continue
 				} else {
@@ -793,20 +788,20 @@ func AdminMergeDLQ(c *cli.Context) {
 					err = producer.Publish(&task)
 
 					if err != nil {
-						fmt.Printf("[Error] Message [%v],[%v] failed: %v\n", msg.Partition, msg.Offset, err)
+						printMessage(c, "[Error] Message [%v],[%v] failed: %v", msg.Partition, msg.Offset, err)
 					} else {
-						fmt.Printf("Message [%v],[%v] succeeded\n", msg.Partition, msg.Offset)
+						printMessage(c, "Message [%v],[%v] succeeded", msg.Partition, msg.Offset)
 					}
 				}
 				consumer.MarkOffset(msg, "")
 			case <-time.After(time.Second * 5):
-				fmt.Println("heartbeat: waiting for more messages, Ctrl+C to stop any time...")
+				printMessage(c, "heartbeat: waiting for more messages, Ctrl+C to stop any time...")
 			}
 		}
 	}
 }
 
-func createConsumerAndWaitForReady(brokers []string, tlsConfig *tls.Config, group, fromTopic string) *cluster.Consumer {
+func createConsumerAndWaitForReady(c *cli.Context, brokers []string, tlsConfig *tls.Config, group, fromTopic string) *cluster.Consumer {
 	config := cluster.NewConfig()
 	config.Consumer.Return.Errors = true
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
@@ -832,12 +827,12 @@ func createConsumerAndWaitForReady(brokers []string, tlsConfig *tls.Config, grou
 		if partitions := ntf.Current[fromTopic]; len(partitions) > 0 && ntf.Type == cluster.RebalanceOK {
 			break
 		}
-		fmt.Println("Waiting for consumer ready...")
+		printMessage(c, "Waiting for consumer ready...")
 	}
 	return consumer
 }
 
-func parseReplicationTask(in string) (tasks []*replication.ReplicationTask, err error) {
+func parseReplicationTask(c *cli.Context, in string) (tasks []*replication.ReplicationTask, err error) {
 	// This code is executed from the CLI. All user input is from a CLI user.
 	// #nosec
 	file, err := os.Open(in)
@@ -853,14 +848,14 @@ func parseReplicationTask(in string) (tasks []*replication.ReplicationTask, err
 		idx++
 		line := strings.TrimSpace(scanner.Text())
 		if len(line) == 0 {
-			fmt.Printf("line %v is empty, skipped\n", idx)
+			printMessage(c, "line %v is empty, skipped", idx)
 			continue
 		}
 
 		t := &replication.ReplicationTask{}
 		err := encoder.Decode([]byte(line), t)
 		if err != nil {
-			fmt.Printf("line %v cannot be deserialized to replicaiton task: %v.\n", idx, line)
+			printMessage(c, "line %v cannot be deserialized to replicaiton task: %v.", idx, line)
 			return nil, err
 		}
 		tasks = append(tasks, t)
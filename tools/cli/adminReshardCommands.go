@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"github.com/urfave/cli"
+	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/serviceerror"
+	sdkclient "go.temporal.io/temporal/client"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/service/worker/reshard"
+)
+
+// AdminReshard starts or resumes splitting the history shards of the cluster tctl is connected to,
+// it also describes a running reshard
+func AdminReshard(c *cli.Context) {
+	client := cFactory.SDKClient(c, common.SystemLocalDomainName)
+
+	if c.Bool(FlagDescribe) {
+		state, running, err := getReshardState(c, client)
+		if err != nil {
+			ErrorAndExit("Failed to describe reshard", err)
+		}
+		printObject(c, map[string]interface{}{
+			"running": running,
+			"state":   state,
+		})
+		return
+	}
+
+	targetShardCount := c.Int(FlagNumberOfShards)
+	if targetShardCount <= 0 {
+		ErrorAndExit("Option "+FlagNumberOfShards+" is required", nil)
+	}
+	state, running, err := getReshardState(c, client)
+	if err != nil {
+		ErrorAndExit("Failed to get the checkpoint of the last reshard", err)
+	}
+	if running {
+		ErrorAndExit("Reshard is already running, use --"+FlagDescribe+" to see its progress", nil)
+	}
+	if state.Phase == reshard.ReshardPhaseCompleted {
+		// a completed reshard is not resumed, the shards are split again
+		state = reshard.ReshardState{}
+	}
+
+	params := reshard.ReshardParams{
+		TargetShardCount: targetShardCount,
+		Concurrency:      c.Int(FlagConcurrency),
+		State:            state,
+	}
+	options := sdkclient.StartWorkflowOptions{
+		ID:                           reshard.ReshardWFID,
+		TaskList:                     reshard.ResharderTaskListName,
+		ExecutionStartToCloseTimeout: reshard.InfiniteDuration,
+	}
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	wf, err := client.ExecuteWorkflow(tcCtx, options, reshard.ReshardWFTypeName, params)
+	if err != nil {
+		ErrorAndExit("Failed to start reshard", err)
+	}
+	output := map[string]interface{}{
+		"msg":   "reshard is started",
+		"runID": wf.GetRunID(),
+	}
+	if state.Phase != "" {
+		output["resumedFrom"] = state
+	}
+	printObject(c, output)
+}
+
+// getReshardState returns the checkpoint of the last run of the reshard workflow
+func getReshardState(c *cli.Context, client sdkclient.Client) (reshard.ReshardState, bool, error) {
+	state := reshard.ReshardState{}
+
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	resp, err := client.DescribeWorkflowExecution(tcCtx, reshard.ReshardWFID, "")
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			return state, false, nil
+		}
+		return state, false, err
+	}
+	running := resp.WorkflowExecutionInfo.GetCloseStatus() == enums.WorkflowExecutionCloseStatusRunning
+
+	queryCtx, queryCancel := newContext(c)
+	defer queryCancel()
+	value, err := client.QueryWorkflow(queryCtx, reshard.ReshardWFID, resp.WorkflowExecutionInfo.GetExecution().GetRunId(), reshard.ReshardStateQueryType)
+	if err != nil {
+		return state, running, err
+	}
+	if err := value.Get(&state); err != nil {
+		return state, running, err
+	}
+	return state, running, nil
+}
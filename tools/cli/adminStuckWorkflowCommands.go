@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+	"go.temporal.io/temporal-proto/serviceerror"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/service/worker/scanner/stuck"
+)
+
+// AdminStuckWorkflows shows the stuck workflows found by the last scan of the stuck workflow scanner,
+// for a single domain or for every domain active in the cluster
+func AdminStuckWorkflows(c *cli.Context) {
+	client := cFactory.SDKClient(c, common.SystemLocalDomainName)
+	domain := c.String(FlagDomain)
+
+	tcCtx, cancel := newContext(c)
+	defer cancel()
+	value, err := client.QueryWorkflow(tcCtx, stuck.ScannerWFID, "", stuck.ReportsQueryType)
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			ErrorAndExit("Stuck workflow scanner is not running, it is enabled by the worker.stuckWorkflowScannerEnabled dynamic config", err)
+		}
+		ErrorAndExit("Failed to query the stuck workflow scanner", err)
+	}
+	reports := make(map[string]*stuck.DomainReport)
+	if err := value.Get(&reports); err != nil {
+		ErrorAndExit("Failed to decode the stuck workflow reports", err)
+	}
+
+	var selected []*stuck.DomainReport
+	if domain != "" {
+		report, ok := reports[domain]
+		if !ok {
+			ErrorAndExit(fmt.Sprintf("No stuck workflow report for domain %v, either the domain is not active in this cluster "+
+				"or the first scan did not complete yet.", domain), nil)
+		}
+		selected = append(selected, report)
+	} else {
+		for _, report := range reports {
+			selected = append(selected, report)
+		}
+		sort.Slice(selected, func(i, j int) bool { return selected[i].Domain < selected[j].Domain })
+	}
+
+	printOutput(c, selected, func() {
+		for _, report := range selected {
+			printStuckWorkflowReport(c, report)
+		}
+	})
+}
+
+func printStuckWorkflowReport(c *cli.Context, report *stuck.DomainReport) {
+	printMessage(c, "Domain %v: checked %v open workflows at %v, %v check errors",
+		report.Domain, report.CheckedCount, report.ScanTime.Format(defaultDateTimeFormat), report.ErrorCount)
+	for _, reason := range []stuck.Reason{stuck.ReasonDecisionTaskFailing, stuck.ReasonActivityNoPoller, stuck.ReasonNoPendingWork} {
+		printMessage(c, "  %v: %v", reason, report.StuckCounts[reason])
+	}
+	if len(report.Workflows) == 0 {
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Workflow ID", "Run ID", "Workflow Type", "Reason", "Details"})
+	table.SetHeaderLine(false)
+	for _, workflow := range report.Workflows {
+		table.Append([]string{workflow.WorkflowID, workflow.RunID, workflow.WorkflowType, string(workflow.Reason), workflow.Details})
+	}
+	table.Render()
+}
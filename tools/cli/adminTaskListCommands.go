@@ -31,6 +31,12 @@ import (
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
 	"go.temporal.io/temporal-proto/workflowservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/.gen/proto/matchingservice"
+	"github.com/temporalio/temporal/common/headers"
 )
 
 // AdminDescribeTaskList displays poller and status information of task list.
@@ -52,23 +58,73 @@ func AdminDescribeTaskList(c *cli.Context) {
 		IncludeTaskListStatus: true,
 	}
 
-	response, err := frontendClient.DescribeTaskList(ctx, request)
+	var header metadata.MD
+	response, err := frontendClient.DescribeTaskList(ctx, request, grpc.Header(&header))
 	if err != nil {
 		ErrorAndExit("Operation DescribeTaskList failed.", err)
 	}
 
-	taskListStatus := response.GetTaskListStatus()
-	if taskListStatus == nil {
-		ErrorAndExit(colorMagenta("No tasklist status information."), nil)
+	output := &adminDescribeTaskListOutput{
+		Pollers:             response.GetPollers(),
+		TaskListStatus:      response.GetTaskListStatus(),
+		ActivityConcurrency: decodeActivityConcurrency(header.Get(headers.ActivityConcurrencyHeaderName)),
 	}
-	printTaskListStatus(taskListStatus)
-	fmt.Printf("\n")
+	printOutput(c, output, func() {
+		taskListStatus := response.GetTaskListStatus()
+		if taskListStatus == nil {
+			ErrorAndExit(colorMagenta("No tasklist status information."), nil)
+		}
+		printTaskListStatus(taskListStatus)
+		fmt.Printf("\n")
+
+		pollers := response.Pollers
+		if len(pollers) == 0 {
+			ErrorAndExit(colorMagenta("No poller for tasklist: "+taskList), nil)
+		}
+		printPollerInfo(pollers, taskListType)
+
+		if len(output.ActivityConcurrency) > 0 {
+			fmt.Printf("\n")
+			printActivityConcurrency(output.ActivityConcurrency)
+		}
+	})
+}
 
-	pollers := response.Pollers
-	if len(pollers) == 0 {
-		ErrorAndExit(colorMagenta("No poller for tasklist: "+taskList), nil)
+// adminDescribeTaskListOutput is the structured output of AdminDescribeTaskList, it adds the activity
+// concurrency returned in the response header to the task list status and pollers
+type adminDescribeTaskListOutput struct {
+	Pollers             []*commonproto.PollerInfo                  `json:"pollers"`
+	TaskListStatus      *commonproto.TaskListStatus                `json:"taskListStatus"`
+	ActivityConcurrency []*matchingservice.ActivityConcurrencyInfo `json:"activityConcurrency,omitempty"`
+}
+
+// AdminUnloadTaskList unloads a task list from the matching host which owns it
+func AdminUnloadTaskList(c *cli.Context) {
+	adminClient := cFactory.AdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := enums.TaskListTypeDecision
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		taskListType = enums.TaskListTypeActivity
 	}
-	printPollerInfo(pollers, taskListType)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	response, err := adminClient.UnloadTaskList(ctx, &adminservice.UnloadTaskListRequest{
+		Domain:       domain,
+		TaskList:     &commonproto.TaskList{Name: taskList},
+		TaskListType: taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("Operation UnloadTaskList failed.", err)
+	}
+	printOutput(c, response, func() {
+		if response.GetUnloaded() {
+			fmt.Printf("TaskList %v is unloaded.\n", taskList)
+		} else {
+			fmt.Printf("TaskList %v is not loaded.\n", taskList)
+		}
+	})
 }
 
 func printTaskListStatus(taskListStatus *commonproto.TaskListStatus) {
@@ -88,6 +144,33 @@ func printTaskListStatus(taskListStatus *commonproto.TaskListStatus) {
 	table.Render()
 }
 
+func decodeActivityConcurrency(values []string) []*matchingservice.ActivityConcurrencyInfo {
+	var result []*matchingservice.ActivityConcurrencyInfo
+	for _, value := range values {
+		info := &matchingservice.ActivityConcurrencyInfo{}
+		if err := info.Unmarshal([]byte(value)); err != nil {
+			ErrorAndExit("Failed to decode activity concurrency.", err)
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+func printActivityConcurrency(activityConcurrency []*matchingservice.ActivityConcurrencyInfo) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Activity Type", "Running", "Limit"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+	for _, info := range activityConcurrency {
+		table.Append([]string{info.GetActivityType(),
+			strconv.Itoa(int(info.GetRunning())),
+			strconv.Itoa(int(info.GetLimit()))})
+	}
+	table.Render()
+}
+
 func printPollerInfo(pollers []*commonproto.PollerInfo, taskListType enums.TaskListType) {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetBorder(false)
@@ -60,7 +60,54 @@ func NewCliApp() *cli.App {
 			Usage:  "optional timeout for context of RPC call in seconds",
 			EnvVar: "TEMPORAL_CONTEXT_TIMEOUT",
 		},
+		cli.StringFlag{
+			Name:   FlagOutputWithAlias,
+			Value:  outputFormatTable,
+			Usage:  "output format of the command result: table, json, jsonl or yaml",
+			EnvVar: "TEMPORAL_CLI_OUTPUT",
+		},
+		cli.StringFlag{
+			Name:   FlagProfileWithAlias,
+			Usage:  "name of the profile in the tctl config file, the current profile is used if not set",
+			EnvVar: "TEMPORAL_CLI_PROFILE",
+		},
+		cli.BoolFlag{
+			Name:   FlagEnableTLS,
+			Usage:  "connect to the frontend service with TLS",
+			EnvVar: "TEMPORAL_CLI_TLS",
+		},
+		cli.StringFlag{
+			Name:   FlagTLSCertPath,
+			Usage:  "path to the client certificate, implies --" + FlagEnableTLS,
+			EnvVar: "TEMPORAL_CLI_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   FlagTLSKeyPath,
+			Usage:  "path to the private key of the client certificate",
+			EnvVar: "TEMPORAL_CLI_TLS_KEY",
+		},
+		cli.StringFlag{
+			Name:   FlagTLSCaPath,
+			Usage:  "path to the CA certificate used to verify the server, implies --" + FlagEnableTLS,
+			EnvVar: "TEMPORAL_CLI_TLS_CA",
+		},
+		cli.StringFlag{
+			Name:   FlagTLSServerName,
+			Usage:  "server name used to verify the hostname returned by the server",
+			EnvVar: "TEMPORAL_CLI_TLS_SERVER_NAME",
+		},
+		cli.BoolFlag{
+			Name:   FlagTLSDisableHostVerification,
+			Usage:  "skip the verification of the hostname and certificate of the server",
+			EnvVar: "TEMPORAL_CLI_TLS_DISABLE_HOST_VERIFICATION",
+		},
+		cli.StringSliceFlag{
+			Name:   FlagHeader,
+			Usage:  "header sent with every request, in the format key=value, can be repeated",
+			EnvVar: "TEMPORAL_CLI_HEADERS",
+		},
 	}
+	app.Before = applyProfile
 	app.Commands = []cli.Command{
 		{
 			Name:        "domain",
@@ -147,6 +194,12 @@ func NewCliApp() *cli.App {
 			Usage:       "Operate temporal cluster",
 			Subcommands: newClusterCommands(),
 		},
+		{
+			Name:        "config",
+			Aliases:     []string{"cfg"},
+			Usage:       "Manage tctl profiles",
+			Subcommands: newConfigCommands(),
+		},
 	}
 
 	// set builder if not customized
@@ -21,6 +21,11 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -39,9 +44,11 @@ import (
 	"go.temporal.io/temporal-proto/workflowservicemock"
 	sdkclient "go.temporal.io/temporal/client"
 	sdkmocks "go.temporal.io/temporal/mocks"
+	"google.golang.org/grpc"
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/.gen/proto/adminservicemock"
+	"github.com/temporalio/temporal/common"
 )
 
 type cliAppSuite struct {
@@ -67,6 +74,10 @@ func (m *clientFactoryMock) AdminClient(c *cli.Context) adminservice.AdminServic
 	return m.serverAdminClient
 }
 
+func (m *clientFactoryMock) AdminClientForAddress(c *cli.Context, address string) adminservice.AdminServiceClient {
+	return m.serverAdminClient
+}
+
 func (m *clientFactoryMock) SDKClient(c *cli.Context, domain string) sdkclient.Client {
 	return m.sdkClient
 }
@@ -529,6 +540,105 @@ func (s *cliAppSuite) TestAdminDescribeWorkflow_Failed() {
 	s.Equal(1, errorCode)
 }
 
+func (s *cliAppSuite) TestAdminDescribeWorkflow_JSONOutput() {
+	resp := &adminservice.DescribeWorkflowExecutionResponse{
+		ShardId:                "test-shard-id",
+		HistoryAddr:            "ip:port",
+		MutableStateInDatabase: `{"ExecutionInfo":{"BranchToken":"ChBNWvyipehOuYvioA1u+suwEhDyawZ9XsdN6Liiof+Novu5","AutoResetPoints":{"points":[{"binaryChecksum":"test-checksum"}]}}}`,
+	}
+
+	s.serverAdminClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil)
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--do", domainName, "--output", "json", "admin", "wf", "describe", "-w", "test-wf-id"}))
+	})
+
+	var output map[string]interface{}
+	s.NoError(json.Unmarshal([]byte(out), &output))
+	s.Equal("test-shard-id", output["shardId"])
+	s.Equal("ip:port", output["historyAddr"])
+	s.NotNil(output["currentBranch"])
+	s.Len(output["autoResetPoints"], 1)
+}
+
+func (s *cliAppSuite) TestAdminGetShardID_JSONOutput() {
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--output", "json", "admin", "hist", "getshard", "-w", "test-wf-id", "--number_of_shards", "16"}))
+	})
+
+	var output shardIDOutput
+	s.NoError(json.Unmarshal([]byte(out), &output))
+	s.Equal("test-wf-id", output.WorkflowID)
+	s.Equal(common.WorkflowIDToHistoryShard("test-wf-id", 16), output.ShardID)
+}
+
+func (s *cliAppSuite) TestAdminRefreshWorkflowTasks_JSONOutput() {
+	s.serverAdminClient.EXPECT().RefreshWorkflowTasks(gomock.Any(), gomock.Any()).Return(&adminservice.RefreshWorkflowTasksResponse{}, nil)
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--do", domainName, "--output", "json", "admin", "wf", "refresh-tasks", "-w", "test-wf-id"}))
+	})
+	s.Empty(out)
+}
+
+func (s *cliAppSuite) TestAdminRehydrateWorkflow_JSONOutput() {
+	resp := &adminservice.RehydrateWorkflowExecutionResponse{ExpirationTime: 100}
+	s.serverAdminClient.EXPECT().RehydrateWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, nil)
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--do", domainName, "--output", "json", "admin", "wf", "rehydrate", "-w", "test-wf-id", "-r", "test-run-id"}))
+	})
+
+	var output map[string]interface{}
+	s.NoError(json.Unmarshal([]byte(out), &output))
+	s.Equal("100", output["expirationTime"])
+}
+
+func (s *cliAppSuite) TestAdminImportWorkflow_JSONOutput() {
+	dir, err := ioutil.TempDir("", "tctl-import-test")
+	s.NoError(err)
+	defer os.RemoveAll(dir)
+	inputFileName := filepath.Join(dir, "exported.json")
+	data, err := json.Marshal(&exportedWorkflow{
+		Domain:         domainName,
+		WorkflowID:     "test-wf-id",
+		RunID:          "test-run-id",
+		MutableState:   "{}",
+		HistoryBatches: []*commonproto.DataBlob{{Data: []byte("some random history")}},
+	})
+	s.NoError(err)
+	s.NoError(ioutil.WriteFile(inputFileName, data, 0666))
+
+	s.serverAdminClient.EXPECT().ImportWorkflowExecution(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *adminservice.ImportWorkflowExecutionRequest, _ ...grpc.CallOption) (*adminservice.ImportWorkflowExecutionResponse, error) {
+			s.True(request.GetLastPage())
+			s.Equal("{}", request.GetMutableState())
+			s.Len(request.GetHistoryBatches(), 1)
+			return &adminservice.ImportWorkflowExecutionResponse{}, nil
+		})
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--output", "json", "admin", "wf", "import", "--input_file", inputFileName}))
+	})
+
+	var output transferWorkflowOutput
+	s.NoError(json.Unmarshal([]byte(out), &output))
+	s.Equal("test-run-id", output.RunID)
+	s.Equal(1, output.HistoryBatches)
+}
+
+func (s *cliAppSuite) TestAdminPurgeDLQMessages_JSONOutput() {
+	s.serverAdminClient.EXPECT().PurgeDLQMessages(gomock.Any(), gomock.Any()).Return(&adminservice.PurgeDLQMessagesResponse{}, nil)
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--output", "json", "admin", "dlq", "purge", "--dlq_type", "domain", "--last_message_id", "10"}))
+	})
+	s.Empty(out)
+}
+
+func (s *cliAppSuite) TestAdminMergeDLQMessages_JSONOutput() {
+	s.serverAdminClient.EXPECT().MergeDLQMessages(gomock.Any(), gomock.Any()).Return(&adminservice.MergeDLQMessagesResponse{}, nil)
+	out := captureStdout(s.T(), func() {
+		s.Nil(s.app.Run([]string{"", "--output", "json", "admin", "dlq", "merge", "--dlq_type", "domain", "--last_message_id", "10"}))
+	})
+	s.Empty(out)
+}
+
 func (s *cliAppSuite) TestAdminAddSearchAttribute() {
 	err := s.app.Run([]string{"", "--do", domainName, "admin", "cl", "asa", "--search_attr_key", "testKey", "--search_attr_type", "1"})
 	s.Nil(err)
@@ -26,6 +26,7 @@ import (
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli"
+	"go.temporal.io/temporal-proto/enums"
 )
 
 // GetSearchAttributes get valid search attributes
@@ -39,12 +40,18 @@ func GetSearchAttributes(c *cli.Context) {
 		ErrorAndExit("Failed to get search attributes.", err)
 	}
 
+	printOutput(c, resp, func() {
+		printSearchAttributes(resp.Keys)
+	})
+}
+
+func printSearchAttributes(keys map[string]enums.IndexedValueType) {
 	table := tablewriter.NewWriter(os.Stdout)
 	header := []string{"Key", "Value type"}
 	table.SetHeader(header)
 	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue)
 	rows := [][]string{}
-	for k, v := range resp.Keys {
+	for k, v := range keys {
 		rows = append(rows, []string{k, v.String()})
 	}
 	sort.Sort(byKey(rows))
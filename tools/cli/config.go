@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import "github.com/urfave/cli"
+
+func newConfigCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List profiles in the tctl config file",
+			Action: func(c *cli.Context) {
+				ListProfiles(c)
+			},
+		},
+		{
+			Name:        "use",
+			Usage:       "Set the current profile",
+			Description: "temporal config use <profile>",
+			Action: func(c *cli.Context) {
+				UseProfile(c)
+			},
+		},
+		{
+			Name:        "set",
+			Usage:       "Create or update a profile",
+			Description: "temporal config set <profile> --address <host:port> --domain <domain> --header key=value",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Usage: "host:port for temporal frontend service",
+				},
+				cli.StringFlag{
+					Name:  FlagDomainWithAlias,
+					Usage: "default temporal workflow domain",
+				},
+				cli.BoolFlag{
+					Name:  FlagEnableTLS,
+					Usage: "connect to the frontend service with TLS, use --" + FlagEnableTLS + "=false to disable",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCertPath,
+					Usage: "path to the client certificate",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSKeyPath,
+					Usage: "path to the private key of the client certificate",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSCaPath,
+					Usage: "path to the CA certificate used to verify the server",
+				},
+				cli.StringFlag{
+					Name:  FlagTLSServerName,
+					Usage: "server name used to verify the hostname returned by the server",
+				},
+				cli.BoolFlag{
+					Name:  FlagTLSDisableHostVerification,
+					Usage: "skip the verification of the hostname and certificate of the server",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagHeader,
+					Usage: "header sent with every request, in the format key=value, can be repeated. An empty value removes the header",
+				},
+			},
+			Action: func(c *cli.Context) {
+				SetProfile(c)
+			},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+)
+
+// ListProfiles lists the profiles in the tctl config file
+func ListProfiles(c *cli.Context) {
+	path, config := loadProfileConfigOrExit()
+
+	printOutput(c, config.masked(), func() {
+		if len(config.Profiles) == 0 {
+			printMessage(c, "No profiles in %v.", path)
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetBorder(false)
+		table.SetColumnSeparator("|")
+		table.SetHeader([]string{"Current", "Name", "Address", "Domain", "TLS", "Headers"})
+		table.SetHeaderLine(false)
+		table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+		for _, name := range config.profileNames() {
+			prof := config.Profiles[name]
+			current := ""
+			if name == config.CurrentProfile {
+				current = "*"
+			}
+			tlsEnabled := prof.TLS.Enabled || prof.TLS.CertFile != "" || prof.TLS.CaFile != ""
+			table.Append([]string{current, name, prof.Address, prof.Domain, strconv.FormatBool(tlsEnabled), strconv.Itoa(len(prof.Headers))})
+		}
+		table.Render()
+	})
+}
+
+// UseProfile sets the current profile
+func UseProfile(c *cli.Context) {
+	if !c.Args().Present() {
+		ErrorAndExit("Argument profile is required.", nil)
+	}
+	name := c.Args().First()
+
+	path, config := loadProfileConfigOrExit()
+	if _, ok := config.Profiles[name]; !ok {
+		ErrorAndExit("Profile "+name+" does not exist, create it with: temporal config set "+name, nil)
+	}
+	config.CurrentProfile = name
+	if err := saveProfileConfig(path, config); err != nil {
+		ErrorAndExit("Unable to save tctl config file.", err)
+	}
+	printMessage(c, "Current profile is now %v.", name)
+}
+
+// SetProfile creates or updates a profile, only the given options are changed.
+// The first profile becomes the current profile.
+func SetProfile(c *cli.Context) {
+	if !c.Args().Present() {
+		ErrorAndExit("Argument profile is required.", nil)
+	}
+	name := c.Args().First()
+
+	headers, err := parseHeaders(c.StringSlice(FlagHeader))
+	if err != nil {
+		ErrorAndExit("Invalid header.", err)
+	}
+
+	path, config := loadProfileConfigOrExit()
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]*profile)
+	}
+	prof, ok := config.Profiles[name]
+	if !ok {
+		prof = &profile{}
+		config.Profiles[name] = prof
+	}
+	if config.CurrentProfile == "" {
+		config.CurrentProfile = name
+	}
+
+	if c.IsSet(FlagAddress) {
+		prof.Address = c.String(FlagAddress)
+	}
+	if c.IsSet(FlagDomain) {
+		prof.Domain = c.String(FlagDomain)
+	}
+	if c.IsSet(FlagEnableTLS) {
+		prof.TLS.Enabled = c.Bool(FlagEnableTLS)
+	}
+	if c.IsSet(FlagTLSCertPath) {
+		prof.TLS.CertFile = c.String(FlagTLSCertPath)
+	}
+	if c.IsSet(FlagTLSKeyPath) {
+		prof.TLS.KeyFile = c.String(FlagTLSKeyPath)
+	}
+	if c.IsSet(FlagTLSCaPath) {
+		prof.TLS.CaFile = c.String(FlagTLSCaPath)
+	}
+	if c.IsSet(FlagTLSServerName) {
+		prof.TLS.ServerName = c.String(FlagTLSServerName)
+	}
+	if c.IsSet(FlagTLSDisableHostVerification) {
+		prof.TLS.DisableHostVerification = c.Bool(FlagTLSDisableHostVerification)
+	}
+	for key, value := range headers {
+		if value == "" {
+			delete(prof.Headers, key)
+			continue
+		}
+		if prof.Headers == nil {
+			prof.Headers = make(map[string]string)
+		}
+		prof.Headers[key] = value
+	}
+
+	if err := saveProfileConfig(path, config); err != nil {
+		ErrorAndExit("Unable to save tctl config file.", err)
+	}
+	if ok {
+		printMessage(c, "Profile %v is updated.", name)
+	} else {
+		printMessage(c, "Profile %v is created.", name)
+	}
+}
@@ -56,10 +56,12 @@ const (
 	defaultContextTimeoutForLongPoll             = 2 * time.Minute
 	defaultContextTimeoutForListArchivedWorkflow = 3 * time.Minute
 
-	defaultDecisionTimeoutInSeconds = 10
-	defaultPageSizeForList          = 500
-	defaultPageSizeForScan          = 2000
-	defaultWorkflowIDReusePolicy    = enums.WorkflowIdReusePolicyAllowDuplicate
+	defaultDecisionTimeoutInSeconds         = 10
+	defaultGracefulFailoverTimeoutInSeconds = 300
+	gracefulFailoverPollInterval            = 5 * time.Second
+	defaultPageSizeForList                  = 500
+	defaultPageSizeForScan                  = 2000
+	defaultWorkflowIDReusePolicy            = enums.WorkflowIdReusePolicyAllowDuplicate
 
 	workflowStatusNotSet = -1
 	showErrorStackEnv    = `TEMPORAL_CLI_SHOW_STACKS`
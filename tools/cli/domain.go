@@ -82,6 +82,15 @@ func newDomainCommands() []cli.Command {
 				newDomainCLI(c, false).UpdateDomain(c)
 			},
 		},
+		{
+			Name:    "failover",
+			Aliases: []string{"fo"},
+			Usage:   "Fail over global workflow domain to another cluster",
+			Flags:   failoverDomainFlags,
+			Action: func(c *cli.Context) {
+				newDomainCLI(c, false).FailoverDomain(c)
+			},
+		},
 		{
 			Name:    "describe",
 			Aliases: []string{"desc"},
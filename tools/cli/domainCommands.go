@@ -36,6 +36,7 @@ import (
 	"go.temporal.io/temporal-proto/serviceerror"
 	"go.temporal.io/temporal-proto/workflowservice"
 
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/common/domain"
 )
 
@@ -149,7 +150,7 @@ func (d *domainCLIImpl) RegisterDomain(c *cli.Context) {
 			ErrorAndExit(fmt.Sprintf("Domain %s already registered.", domainName), err)
 		}
 	} else {
-		fmt.Printf("Domain %s successfully registered.\n", domainName)
+		printMessage(c, "Domain %s successfully registered.", domainName)
 	}
 }
 
@@ -163,7 +164,7 @@ func (d *domainCLIImpl) UpdateDomain(c *cli.Context) {
 
 	if c.IsSet(FlagActiveClusterName) {
 		activeCluster := c.String(FlagActiveClusterName)
-		fmt.Printf("Will set active cluster name to: %s, other flag will be omitted.\n", activeCluster)
+		printMessage(c, "Will set active cluster name to: %s, other flag will be omitted.", activeCluster)
 		replicationConfig := &commonproto.DomainReplicationConfiguration{
 			ActiveClusterName: activeCluster,
 		}
@@ -278,10 +279,73 @@ func (d *domainCLIImpl) UpdateDomain(c *cli.Context) {
 			ErrorAndExit(fmt.Sprintf("Domain %s does not exist.", domainName), err)
 		}
 	} else {
-		fmt.Printf("Domain %s successfully updated.\n", domainName)
+		printMessage(c, "Domain %s successfully updated.", domainName)
 	}
 }
 
+// FailoverDomain fails over a global domain to another cluster
+func (d *domainCLIImpl) FailoverDomain(c *cli.Context) {
+	domainName := getRequiredGlobalOption(c, FlagDomain)
+	activeCluster := getRequiredOption(c, FlagActiveClusterName)
+
+	if !c.Bool(FlagGracefulFailover) {
+		ctx, cancel := newContext(c)
+		defer cancel()
+		if err := d.updateDomain(ctx, &workflowservice.UpdateDomainRequest{
+			Name: domainName,
+			ReplicationConfiguration: &commonproto.DomainReplicationConfiguration{
+				ActiveClusterName: activeCluster,
+			},
+		}); err != nil {
+			ErrorAndExit("Operation FailoverDomain failed.", err)
+		}
+		printMessage(c, "Domain %s successfully failed over to cluster %s.", domainName, activeCluster)
+		return
+	}
+
+	adminClient := cFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	if _, err := adminClient.StartDomainHandover(ctx, &adminservice.StartDomainHandoverRequest{
+		Domain:              domainName,
+		HandoverClusterName: activeCluster,
+		TimeoutInSeconds:    int32(c.Int(FlagFailoverTimeout)),
+	}); err != nil {
+		ErrorAndExit("Operation StartDomainHandover failed.", err)
+	}
+	printMessage(c, "Domain %s is being gracefully failed over to cluster %s.", domainName, activeCluster)
+
+	for {
+		resp := describeDomainHandover(c, adminClient, domainName)
+		if resp.GetActiveClusterName() == activeCluster {
+			printMessage(c, "Domain %s successfully failed over to cluster %s.", domainName, activeCluster)
+			return
+		}
+		if resp.GetHandoverClusterName() == "" {
+			ErrorAndExit(fmt.Sprintf("Graceful failover of domain %s was aborted, active cluster is %s.", domainName, resp.GetActiveClusterName()), nil)
+		}
+		printMessage(c, "Waiting for replication to catch up: %d of %d shards pending, failover deadline %s.",
+			len(resp.GetPendingShardIDs()), resp.GetNumberOfShards(), convertTime(resp.GetFailoverEndTime(), false))
+		time.Sleep(gracefulFailoverPollInterval)
+	}
+}
+
+func describeDomainHandover(
+	c *cli.Context,
+	adminClient adminservice.AdminServiceClient,
+	domainName string,
+) *adminservice.DescribeDomainHandoverResponse {
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.DescribeDomainHandover(ctx, &adminservice.DescribeDomainHandoverRequest{
+		Domain: domainName,
+	})
+	if err != nil {
+		ErrorAndExit("Operation DescribeDomainHandover failed.", err)
+	}
+	return resp
+}
+
 // DescribeDomain updates a domain
 func (d *domainCLIImpl) DescribeDomain(c *cli.Context) {
 	domainName := c.GlobalString(FlagDomain)
@@ -303,6 +367,12 @@ func (d *domainCLIImpl) DescribeDomain(c *cli.Context) {
 		ErrorAndExit(fmt.Sprintf("Domain %s does not exist.", domainName), err)
 	}
 
+	printOutput(c, resp, func() {
+		printDomainDescription(resp)
+	})
+}
+
+func printDomainDescription(resp *workflowservice.DescribeDomainResponse) {
 	var formatStr = "Name: %v\nUUID: %v\nDescription: %v\nOwnerEmail: %v\nDomainData: %#v\nStatus: %v\nRetentionInDays: %v\n" +
 		"EmitMetrics: %v\nActiveClusterName: %v\nClusters: %v\nHistoryArchivalStatus: %v\n"
 	descValues := []interface{}{
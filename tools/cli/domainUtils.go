@@ -23,7 +23,6 @@ package cli
 import (
 	"strings"
 
-	"github.com/stretchr/testify/mock"
 	"github.com/uber-go/tally"
 	"github.com/urfave/cli"
 	"go.temporal.io/temporal-proto/workflowservice"
@@ -36,7 +35,6 @@ import (
 	"github.com/temporalio/temporal/common/log"
 	"github.com/temporalio/temporal/common/log/loggerimpl"
 	"github.com/temporalio/temporal/common/metrics"
-	"github.com/temporalio/temporal/common/mocks"
 	"github.com/temporalio/temporal/common/persistence"
 	"github.com/temporalio/temporal/common/persistence/client"
 	"github.com/temporalio/temporal/common/service/config"
@@ -164,6 +162,22 @@ var (
 		},
 	}
 
+	failoverDomainFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  FlagActiveClusterNameWithAlias,
+			Usage: "Target active cluster name",
+		},
+		cli.BoolFlag{
+			Name:  FlagGracefulFailoverWithAlias,
+			Usage: "Gracefully fail over: reject new writes and wait for replication to catch up before switching the active cluster",
+		},
+		cli.IntFlag{
+			Name:  FlagFailoverTimeoutWithAlias,
+			Value: defaultGracefulFailoverTimeoutInSeconds,
+			Usage: "Graceful failover timeout in seconds, the domain is failed over regardless of pending replication afterwards",
+		},
+	}
+
 	describeDomainFlags = []cli.Flag{
 		cli.StringFlag{
 			Name:  FlagDomainID,
@@ -219,7 +233,7 @@ func initializeAdminDomainHandler(
 		configuration,
 		logger,
 	)
-	metadataMgr := initializeMetadataMgr(
+	pFactory := initializePersistenceFactory(
 		configuration,
 		clusterMetadata,
 		metricsClient,
@@ -228,8 +242,9 @@ func initializeAdminDomainHandler(
 	dynamicConfig := initializeDynamicConfig(configuration, logger)
 	return initializeDomainHandler(
 		logger,
-		metadataMgr,
+		initializeMetadataMgr(pFactory),
 		clusterMetadata,
+		initializeDomainReplicationQueue(pFactory),
 		initializeArchivalMetadata(configuration, dynamicConfig),
 		initializeArchivalProvider(configuration, clusterMetadata, metricsClient, logger),
 	)
@@ -253,6 +268,7 @@ func initializeDomainHandler(
 	logger log.Logger,
 	metadataMgr persistence.MetadataManager,
 	clusterMetadata cluster.Metadata,
+	domainReplicationQueue persistence.DomainReplicationQueue,
 	archivalMetadata archiver.ArchivalMetadata,
 	archiverProvider provider.ArchiverProvider,
 ) domain.Handler {
@@ -262,7 +278,7 @@ func initializeDomainHandler(
 		logger,
 		metadataMgr,
 		clusterMetadata,
-		initializeDomainReplicator(logger),
+		domain.NewDomainReplicator(domainReplicationQueue, logger),
 		archivalMetadata,
 		archiverProvider,
 	)
@@ -274,12 +290,12 @@ func initializeLogger(
 	return loggerimpl.NewLogger(serviceConfig.Log.NewZapLogger())
 }
 
-func initializeMetadataMgr(
+func initializePersistenceFactory(
 	serviceConfig *config.Config,
 	clusterMetadata cluster.Metadata,
 	metricsClient metrics.Client,
 	logger log.Logger,
-) persistence.MetadataManager {
+) client.Factory {
 
 	pConfig := serviceConfig.Persistence
 	pConfig.VisibilityConfig = &config.VisibilityConfig{
@@ -295,6 +311,13 @@ func initializeMetadataMgr(
 		metricsClient,
 		logger,
 	)
+	return pFactory
+}
+
+func initializeMetadataMgr(
+	pFactory client.Factory,
+) persistence.MetadataManager {
+
 	metadata, err := pFactory.NewMetadataManager()
 	if err != nil {
 		ErrorAndExit("Unable to initialize metadata manager.", err)
@@ -302,6 +325,17 @@ func initializeMetadataMgr(
 	return metadata
 }
 
+func initializeDomainReplicationQueue(
+	pFactory client.Factory,
+) persistence.DomainReplicationQueue {
+
+	domainReplicationQueue, err := pFactory.NewDomainReplicationQueue()
+	if err != nil {
+		ErrorAndExit("Unable to initialize domain replication queue.", err)
+	}
+	return domainReplicationQueue
+}
+
 func initializeClusterMetadata(
 	serviceConfig *config.Config,
 	logger log.Logger,
@@ -371,15 +405,6 @@ func initializeArchivalProvider(
 	return archiverProvider
 }
 
-func initializeDomainReplicator(
-	logger log.Logger,
-) domain.Replicator {
-
-	replicationMessageSink := &mocks.KafkaProducer{}
-	replicationMessageSink.On("Publish", mock.Anything).Return(nil)
-	return domain.NewDomainReplicator(replicationMessageSink, logger)
-}
-
 func initializeDynamicConfig(
 	serviceConfig *config.Config,
 	logger log.Logger,
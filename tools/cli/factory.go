@@ -21,11 +21,21 @@
 package cli
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
 	"github.com/urfave/cli"
 	"go.temporal.io/temporal-proto/workflowservice"
 	sdkclient "go.temporal.io/temporal/client"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/temporalio/temporal/.gen/proto/adminservice"
 	"github.com/temporalio/temporal/common/rpc"
@@ -35,6 +45,7 @@ import (
 type ClientFactory interface {
 	FrontendClient(c *cli.Context) workflowservice.WorkflowServiceClient
 	AdminClient(c *cli.Context) adminservice.AdminServiceClient
+	AdminClientForAddress(c *cli.Context, address string) adminservice.AdminServiceClient
 	SDKClient(c *cli.Context, domain string) sdkclient.Client
 }
 
@@ -56,28 +67,43 @@ func NewClientFactory() ClientFactory {
 
 // FrontendClient builds a frontend client
 func (b *clientFactory) FrontendClient(c *cli.Context) workflowservice.WorkflowServiceClient {
-	connection := b.createGRPCConnection(c.GlobalString(FlagAddress))
+	connection := b.createGRPCConnection(c, c.GlobalString(FlagAddress))
 
 	return workflowservice.NewWorkflowServiceClient(connection)
 }
 
 // AdminClient builds an admin client (based on server side thrift interface)
 func (b *clientFactory) AdminClient(c *cli.Context) adminservice.AdminServiceClient {
-	connection := b.createGRPCConnection(c.GlobalString(FlagAddress))
+	connection := b.createGRPCConnection(c, c.GlobalString(FlagAddress))
 
 	return adminservice.NewAdminServiceClient(connection)
 }
 
-// AdminClient builds an admin client (based on server side thrift interface)
+// AdminClientForAddress builds an admin client of the cluster at the given address
+func (b *clientFactory) AdminClientForAddress(c *cli.Context, address string) adminservice.AdminServiceClient {
+	connection := b.createGRPCConnection(c, address)
+
+	return adminservice.NewAdminServiceClient(connection)
+}
+
+// SDKClient builds an SDK client
 func (b *clientFactory) SDKClient(c *cli.Context, domain string) sdkclient.Client {
 	hostPort := c.GlobalString(FlagAddress)
 	if hostPort == "" {
 		hostPort = localHostPort
 	}
 
+	transportOption, headersInterceptor := b.getConnectionOptions(c)
 	sdkClient, err := sdkclient.NewClient(sdkclient.Options{
 		HostPort:   hostPort,
 		DomainName: domain,
+		GRPCDialer: func(params sdkclient.GRPCDialerParams) (*grpc.ClientConn, error) {
+			return grpc.Dial(params.HostPort,
+				transportOption,
+				grpc.WithChainUnaryInterceptor(append(params.RequiredInterceptors, headersInterceptor)...),
+				grpc.WithDefaultServiceConfig(params.DefaultServiceConfig),
+			)
+		},
 	})
 	if err != nil {
 		b.logger.Fatal("Failed to create SDK client", zap.Error(err))
@@ -86,12 +112,13 @@ func (b *clientFactory) SDKClient(c *cli.Context, domain string) sdkclient.Clien
 	return sdkClient
 }
 
-func (b *clientFactory) createGRPCConnection(hostPort string) *grpc.ClientConn {
+func (b *clientFactory) createGRPCConnection(c *cli.Context, hostPort string) *grpc.ClientConn {
 	if hostPort == "" {
 		hostPort = localHostPort
 	}
 
-	connection, err := rpc.Dial(hostPort)
+	transportOption, headersInterceptor := b.getConnectionOptions(c)
+	connection, err := rpc.DialWithOptions(hostPort, transportOption, grpc.WithChainUnaryInterceptor(headersInterceptor))
 	if err != nil {
 		b.logger.Fatal("Failed to create connection", zap.Error(err))
 		return nil
@@ -99,3 +126,83 @@ func (b *clientFactory) createGRPCConnection(hostPort string) *grpc.ClientConn {
 
 	return connection
 }
+
+// getConnectionOptions returns the transport security option and the interceptor adding the
+// configured headers, both are built from the global options
+func (b *clientFactory) getConnectionOptions(c *cli.Context) (grpc.DialOption, grpc.UnaryClientInterceptor) {
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		b.logger.Fatal("Failed to create TLS config", zap.Error(err))
+	}
+	transportOption := grpc.WithInsecure()
+	if tlsConfig != nil {
+		transportOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	headers, err := parseHeaders(c.GlobalStringSlice(FlagHeader))
+	if err != nil {
+		b.logger.Fatal("Failed to parse headers", zap.Error(err))
+	}
+	return transportOption, newHeadersInterceptor(headers)
+}
+
+// newTLSConfig returns the TLS config of the frontend connection, or nil if TLS is not enabled
+func newTLSConfig(c *cli.Context) (*tls.Config, error) {
+	certPath := c.GlobalString(FlagTLSCertPath)
+	keyPath := c.GlobalString(FlagTLSKeyPath)
+	caPath := c.GlobalString(FlagTLSCaPath)
+	if !c.GlobalBool(FlagEnableTLS) && certPath == "" && caPath == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         c.GlobalString(FlagTLSServerName),
+		InsecureSkipVerify: c.GlobalBool(FlagTLSDisableHostVerification),
+	}
+	if certPath != "" || keyPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if caPath != "" {
+		pemData, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificate found in %v", caPath)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	return tlsConfig, nil
+}
+
+// parseHeaders parses headers in the key=value format
+func parseHeaders(headers []string) (map[string]string, error) {
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		kv := strings.SplitN(header, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || key == "" {
+			return nil, errors.New("header must be in the format key=value: " + header)
+		}
+		result[key] = strings.TrimSpace(kv[1])
+	}
+	return result, nil
+}
+
+func newHeadersInterceptor(headers map[string]string) grpc.UnaryClientInterceptor {
+	pairs := make([]string, 0, 2*len(headers))
+	for key, value := range headers {
+		pairs = append(pairs, key, value)
+	}
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if len(pairs) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
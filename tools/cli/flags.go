@@ -168,6 +168,7 @@ const (
 	FlagMessageTypeWithAlias              = FlagMessageType + ", mt"
 	FlagURL                               = "url"
 	FlagIndex                             = "index"
+	FlagESVersion                         = "es_version"
 	FlagBatchSize                         = "batch_size"
 	FlagBatchSizeWithAlias                = FlagBatchSize + ", bs"
 	FlagMemoKey                           = "memo_key"
@@ -180,6 +181,8 @@ const (
 	FlagRemoveBadBinary                   = "remove_bad_binary"
 	FlagResetType                         = "reset_type"
 	FlagResetPointsOnly                   = "reset_points_only"
+	FlagEventType                         = "event_type"
+	FlagOmitPayloads                      = "omit_payloads"
 	FlagResetBadBinaryChecksum            = "reset_bad_binary_checksum"
 	FlagListQuery                         = "query"
 	FlagListQueryWithAlias                = FlagListQuery + ", q"
@@ -189,7 +192,14 @@ const (
 	FlagSignalNameWithAlias               = FlagSignalName + ", sig"
 	FlagRemoveTaskID                      = "task_id"
 	FlagRemoveTypeID                      = "type_id"
+	FlagFix                               = "fix"
+	FlagCheckHistoryBranches              = "check_history_branches"
 	FlagRPS                               = "rps"
+	FlagConcurrency                       = "concurrency"
+	FlagSourceCluster                     = "source_cluster"
+	FlagLagThreshold                      = "lag_threshold"
+	FlagDescribe                          = "describe"
+	FlagAbort                             = "abort"
 	FlagJobID                             = "job_id"
 	FlagJobIDWithAlias                    = FlagJobID + ", jid"
 	FlagYes                               = "yes"
@@ -210,6 +220,29 @@ const (
 	FlagMaxMessageCountWithAlias          = FlagMaxMessageCount + ", mmc"
 	FlagLastMessageID                     = "last_message_id"
 	FlagLastMessageIDWithAlias            = FlagLastMessageID + ", lm"
+	FlagFirstMessageID                    = "first_message_id"
+	FlagFirstMessageIDWithAlias           = FlagFirstMessageID + ", fm"
+	FlagMessageID                         = "message_id"
+	FlagMessageIDWithAlias                = FlagMessageID + ", mid"
+	FlagGracefulFailover                  = "graceful"
+	FlagGracefulFailoverWithAlias         = FlagGracefulFailover + ", gf"
+	FlagFailoverTimeout                   = "failover_timeout"
+	FlagFailoverTimeoutWithAlias          = FlagFailoverTimeout + ", fot"
+	FlagWorkflowID2                       = "workflow_id2"
+	FlagWorkflowID2WithAlias              = FlagWorkflowID2 + ", wid2, w2"
+	FlagRunID2                            = "run_id2"
+	FlagRunID2WithAlias                   = FlagRunID2 + ", rid2, r2"
+	FlagAddress2                          = "address2"
+	FlagAddress2WithAlias                 = FlagAddress2 + ", ad2"
+	FlagIgnoreFields                      = "ignore_fields"
+	FlagIgnoreFieldsWithAlias             = FlagIgnoreFields + ", if"
+	FlagOutput                            = "output"
+	FlagOutputWithAlias                   = FlagOutput + ", o"
+	FlagProfile                           = "profile"
+	FlagProfileWithAlias                  = FlagProfile + ", pr"
+	FlagTLSServerName                     = "tls_server_name"
+	FlagTLSDisableHostVerification        = "tls_disable_host_verification"
+	FlagHeader                            = "header"
 )
 
 var flagsForExecution = []cli.Flag{
@@ -227,6 +260,41 @@ func getFlagsForShow() []cli.Flag {
 	return append(flagsForExecution, getFlagsForShowID()...)
 }
 
+func getFlagsForDiff() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  FlagWorkflowIDWithAlias,
+			Usage: "WorkflowID",
+		},
+		cli.StringFlag{
+			Name:  FlagRunIDWithAlias,
+			Usage: "RunID",
+		},
+		cli.StringFlag{
+			Name:  FlagWorkflowID2WithAlias,
+			Usage: "WorkflowID of the second history, defaults to the first WorkflowID",
+		},
+		cli.StringFlag{
+			Name:  FlagRunID2WithAlias,
+			Usage: "RunID of the second history, defaults to the first RunID",
+		},
+		cli.StringFlag{
+			Name:  FlagAddress2WithAlias,
+			Usage: "host:port of the frontend of the cluster to read the second history from, defaults to the global address",
+		},
+		cli.StringFlag{
+			Name:  FlagIgnoreFieldsWithAlias,
+			Usage: "Comma separated event fields to ignore in the comparison",
+			Value: "timestamp,taskId",
+		},
+		cli.IntFlag{
+			Name:  FlagMaxFieldLengthWithAlias,
+			Usage: "Maximum length for each attribute field",
+			Value: defaultMaxFieldLength,
+		},
+	}
+}
+
 func getFlagsForShowID() []cli.Flag {
 	return []cli.Flag{
 		cli.BoolFlag{
@@ -262,6 +330,31 @@ func getFlagsForShowID() []cli.Flag {
 			Name:  FlagResetPointsOnly,
 			Usage: "Only show events that are eligible for reset",
 		},
+		cli.StringSliceFlag{
+			Name:  FlagEventType,
+			Usage: "Only show events of the given type, e.g. ActivityTaskScheduled (repeatable)",
+		},
+		cli.Int64Flag{
+			Name:  FlagMinEventID,
+			Usage: "Only show events with event ID larger than or equal to this value",
+		},
+		cli.Int64Flag{
+			Name:  FlagMaxEventID,
+			Usage: "Only show events with event ID smaller than or equal to this value",
+		},
+		cli.StringFlag{
+			Name: FlagEarliestTime,
+			Usage: "Only show events at or after this time, supported formats are '2006-01-02T15:04:05+07:00', raw UnixNano and " +
+				"time range (N<duration>), e.g. '15m' implies last 15 minutes",
+		},
+		cli.StringFlag{
+			Name:  FlagLatestTime,
+			Usage: "Only show events at or before this time, supported formats are the same as for " + FlagEarliestTime,
+		},
+		cli.BoolFlag{
+			Name:  FlagOmitPayloads,
+			Usage: "Do not return event payloads (inputs, results, details)",
+		},
 	}
 }
 
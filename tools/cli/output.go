@@ -0,0 +1,197 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+	outputFormatJSONL = "jsonl"
+	outputFormatYAML  = "yaml"
+)
+
+var outputFormats = []string{outputFormatTable, outputFormatJSON, outputFormatJSONL, outputFormatYAML}
+
+type (
+	// outputStream prints the items of a paginated result as soon as they are fetched.
+	// json prints a single array, jsonl prints one item per line and yaml prints a sequence.
+	outputStream struct {
+		format string
+		count  int
+	}
+)
+
+// getOutputFormat returns the output format selected by the global output flag
+func getOutputFormat(c *cli.Context) string {
+	format := strings.ToLower(c.GlobalString(FlagOutput))
+	if format == "" {
+		return outputFormatTable
+	}
+	for _, f := range outputFormats {
+		if format == f {
+			return format
+		}
+	}
+	ErrorAndExit(fmt.Sprintf("Option %s must be one of %s.", FlagOutput, strings.Join(outputFormats, ", ")), nil)
+	return ""
+}
+
+// isTableOutput returns true if the human readable output is selected
+func isTableOutput(c *cli.Context) bool {
+	return getOutputFormat(c) == outputFormatTable
+}
+
+// printOutput prints the result of a command. With the table output printTable renders the
+// human readable output, every other format serializes o.
+func printOutput(c *cli.Context, o interface{}, printTable func()) {
+	format := getOutputFormat(c)
+	if format == outputFormatTable {
+		printTable()
+		return
+	}
+	data, err := marshalOutput(o, format)
+	if err != nil {
+		ErrorAndExit("Failed to serialize output.", err)
+	}
+	_, _ = os.Stdout.Write(data)
+	if format != outputFormatYAML {
+		fmt.Println()
+	}
+}
+
+// printObject prints an object without a table representation,
+// the table output shows it as indented json
+func printObject(c *cli.Context, o interface{}) {
+	printOutput(c, o, func() {
+		prettyPrintJSONObject(o)
+	})
+}
+
+// printMessage prints an informational message. With a structured output format the
+// message goes to stderr, so that stdout only contains the serialized result.
+func printMessage(c *cli.Context, format string, args ...interface{}) {
+	if isTableOutput(c) {
+		fmt.Printf(format+"\n", args...)
+		return
+	}
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+func newOutputStream(c *cli.Context) *outputStream {
+	return &outputStream{
+		format: getOutputFormat(c),
+	}
+}
+
+// Print prints a single item of the result
+func (s *outputStream) Print(o interface{}) {
+	format := s.format
+	if format == outputFormatJSON {
+		// items of the json array are printed in a single line each
+		format = outputFormatJSONL
+	}
+	data, err := marshalOutput(o, format)
+	if err != nil {
+		ErrorAndExit("Failed to serialize output.", err)
+	}
+
+	switch s.format {
+	case outputFormatJSON:
+		if s.count == 0 {
+			fmt.Println("[")
+		} else {
+			fmt.Println(",")
+		}
+		_, _ = os.Stdout.Write(data)
+	case outputFormatYAML:
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		for i, line := range lines {
+			if i == 0 {
+				fmt.Println("- " + line)
+			} else {
+				fmt.Println("  " + line)
+			}
+		}
+	default:
+		_, _ = os.Stdout.Write(data)
+		fmt.Println()
+	}
+	s.count++
+}
+
+// Close terminates the result, it must be called once all items are printed
+func (s *outputStream) Close() {
+	switch s.format {
+	case outputFormatJSON:
+		if s.count == 0 {
+			fmt.Println("[]")
+		} else {
+			fmt.Println()
+			fmt.Println("]")
+		}
+	case outputFormatYAML:
+		if s.count == 0 {
+			fmt.Println("[]")
+		}
+	}
+}
+
+// marshalOutput serializes o, protobuf messages are serialized with their json field names
+func marshalOutput(o interface{}, format string) ([]byte, error) {
+	var data []byte
+	var err error
+	if pb, ok := o.(proto.Message); ok && !reflect.ValueOf(pb).IsNil() {
+		marshaler := jsonpb.Marshaler{}
+		if format == outputFormatJSON {
+			marshaler.Indent = "  "
+		}
+		var buf bytes.Buffer
+		err = marshaler.Marshal(&buf, pb)
+		data = buf.Bytes()
+	} else if format == outputFormatJSON {
+		data, err = json.MarshalIndent(o, "", "  ")
+	} else {
+		data, err = json.Marshal(o)
+	}
+	if err != nil || format != outputFormatYAML {
+		return data, err
+	}
+
+	// yaml is converted from json, so both formats share the same field names
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
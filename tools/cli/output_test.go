@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonproto "go.temporal.io/temporal-proto/common"
+)
+
+type outputTestItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestMarshalOutput_Struct(t *testing.T) {
+	item := outputTestItem{Name: "foo", Count: 2}
+
+	data, err := marshalOutput(item, outputFormatJSONL)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"foo","count":2}`, string(data))
+
+	data, err = marshalOutput(item, outputFormatJSON)
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"name\": \"foo\",\n  \"count\": 2\n}", string(data))
+
+	data, err = marshalOutput(item, outputFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, "count: 2\nname: foo\n", string(data))
+}
+
+func TestMarshalOutput_Proto(t *testing.T) {
+	execution := &commonproto.WorkflowExecution{WorkflowId: "wid", RunId: "rid"}
+
+	data, err := marshalOutput(execution, outputFormatJSONL)
+	require.NoError(t, err)
+	require.Equal(t, `{"workflowId":"wid","runId":"rid"}`, string(data))
+
+	data, err = marshalOutput(execution, outputFormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, "runId: rid\nworkflowId: wid\n", string(data))
+}
+
+func TestMarshalOutput_NilProto(t *testing.T) {
+	var execution *commonproto.WorkflowExecution
+	data, err := marshalOutput(execution, outputFormatJSONL)
+	require.NoError(t, err)
+	require.Equal(t, "null", string(data))
+}
+
+// captureStdout returns everything f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	dataCh := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		dataCh <- data
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	require.NoError(t, w.Close())
+	return string(<-dataCh)
+}
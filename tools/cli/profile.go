@@ -0,0 +1,229 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// profileConfigEnvVar overrides the location of the tctl config file
+	profileConfigEnvVar   = "TEMPORAL_CLI_CONFIG"
+	profileConfigDir      = ".config/tctl"
+	profileConfigFileName = "config.yaml"
+	configCommandName     = "config"
+	maskedHeaderValue     = "******"
+)
+
+type (
+	// profileConfig is the content of the tctl config file
+	profileConfig struct {
+		CurrentProfile string              `yaml:"currentProfile,omitempty" json:"currentProfile,omitempty"`
+		Profiles       map[string]*profile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	}
+
+	// profile is a named environment, its values are used for the global options
+	// which are set neither on the command line nor by environment variables
+	profile struct {
+		Address string            `yaml:"address,omitempty" json:"address,omitempty"`
+		Domain  string            `yaml:"domain,omitempty" json:"domain,omitempty"`
+		TLS     profileTLS        `yaml:"tls,omitempty" json:"tls"`
+		Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	}
+
+	// profileTLS is the TLS config of a profile, the server is verified unless it is disabled explicitly
+	profileTLS struct {
+		Enabled                 bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+		CertFile                string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+		KeyFile                 string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+		CaFile                  string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+		ServerName              string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+		DisableHostVerification bool   `yaml:"disableHostVerification,omitempty" json:"disableHostVerification,omitempty"`
+	}
+)
+
+// getProfileConfigPath returns the location of the tctl config file
+func getProfileConfigPath() (string, error) {
+	if path := os.Getenv(profileConfigEnvVar); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, profileConfigDir, profileConfigFileName), nil
+}
+
+// loadProfileConfig reads the tctl config file, a missing file is an empty config
+func loadProfileConfig(path string) (*profileConfig, error) {
+	config := &profileConfig{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", path, err)
+	}
+	return config, nil
+}
+
+// saveProfileConfig writes the tctl config file, it may contain credentials so it is only readable by the owner
+func saveProfileConfig(path string, config *profileConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func loadProfileConfigOrExit() (string, *profileConfig) {
+	path, err := getProfileConfigPath()
+	if err != nil {
+		ErrorAndExit("Unable to locate tctl config file.", err)
+	}
+	config, err := loadProfileConfig(path)
+	if err != nil {
+		ErrorAndExit("Unable to load tctl config file.", err)
+	}
+	return path, config
+}
+
+// getProfile returns the profile with the given name, or the current profile if name is empty.
+// It returns nil if name is empty and there is no current profile.
+func (p *profileConfig) getProfile(name string) (*profile, error) {
+	if name == "" {
+		name = p.CurrentProfile
+		if name == "" {
+			return nil, nil
+		}
+	}
+	prof, ok := p.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %v does not exist", name)
+	}
+	return prof, nil
+}
+
+// profileNames returns the sorted names of all profiles
+func (p *profileConfig) profileNames() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile sets the global options which are not set on the command line or by environment
+// variables to the values of the selected profile. Precedence is flag, environment variable,
+// profile and then the default value of the option.
+func applyProfile(c *cli.Context) error {
+	// config commands manage the config file, they must work even if it refers to a missing profile
+	if isConfigCommand(c) && !c.IsSet(FlagProfile) {
+		return nil
+	}
+
+	path, config := loadProfileConfigOrExit()
+	prof, err := config.getProfile(c.String(FlagProfile))
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Invalid profile in %v.", path), err)
+	}
+	if prof == nil {
+		return nil
+	}
+
+	values := map[string]string{
+		FlagAddress:       prof.Address,
+		FlagDomain:        prof.Domain,
+		FlagTLSCertPath:   prof.TLS.CertFile,
+		FlagTLSKeyPath:    prof.TLS.KeyFile,
+		FlagTLSCaPath:     prof.TLS.CaFile,
+		FlagTLSServerName: prof.TLS.ServerName,
+	}
+	if prof.TLS.Enabled {
+		values[FlagEnableTLS] = strconv.FormatBool(true)
+	}
+	if prof.TLS.DisableHostVerification {
+		values[FlagTLSDisableHostVerification] = strconv.FormatBool(true)
+	}
+	for flagName, value := range values {
+		if value == "" || c.IsSet(flagName) {
+			continue
+		}
+		if err := c.Set(flagName, value); err != nil {
+			return err
+		}
+	}
+
+	if !c.IsSet(FlagHeader) {
+		keys := make([]string, 0, len(prof.Headers))
+		for key := range prof.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := c.Set(FlagHeader, key+"="+prof.Headers[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isConfigCommand returns true if the command line runs one of the config commands, by name or alias
+func isConfigCommand(c *cli.Context) bool {
+	command := c.App.Command(c.Args().First())
+	return command != nil && command.Name == configCommandName
+}
+
+// masked returns a copy of the config in which the header values are replaced,
+// headers usually contain credentials which must not be printed
+func (p *profileConfig) masked() *profileConfig {
+	result := &profileConfig{
+		CurrentProfile: p.CurrentProfile,
+		Profiles:       make(map[string]*profile, len(p.Profiles)),
+	}
+	for name, prof := range p.Profiles {
+		maskedProf := *prof
+		if len(prof.Headers) > 0 {
+			maskedProf.Headers = make(map[string]string, len(prof.Headers))
+			for key := range prof.Headers {
+				maskedProf.Headers[key] = maskedHeaderValue
+			}
+		}
+		result.Profiles[name] = &maskedProf
+	}
+	return result
+}
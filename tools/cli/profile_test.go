@@ -0,0 +1,218 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestProfileConfig_SaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tctl-profile-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tctl", profileConfigFileName)
+
+	config, err := loadProfileConfig(path)
+	require.NoError(t, err)
+	require.Empty(t, config.Profiles)
+
+	config = &profileConfig{
+		CurrentProfile: "staging",
+		Profiles: map[string]*profile{
+			"staging": {
+				Address: "staging:7233",
+				Domain:  "samples",
+				TLS:     profileTLS{Enabled: true, CaFile: "/tmp/ca.pem"},
+				Headers: map[string]string{"authorization": "token"},
+			},
+			"dev": {Address: "localhost:7233"},
+		},
+	}
+	require.NoError(t, saveProfileConfig(path, config))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := loadProfileConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, config, loaded)
+	require.Equal(t, []string{"dev", "staging"}, loaded.profileNames())
+}
+
+func TestProfileConfig_GetProfile(t *testing.T) {
+	config := &profileConfig{
+		Profiles: map[string]*profile{"dev": {Address: "localhost:7233"}},
+	}
+
+	prof, err := config.getProfile("")
+	require.NoError(t, err)
+	require.Nil(t, prof)
+
+	config.CurrentProfile = "dev"
+	prof, err = config.getProfile("")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:7233", prof.Address)
+
+	_, err = config.getProfile("prod")
+	require.Error(t, err)
+}
+
+func TestApplyProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tctl-profile-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, profileConfigFileName)
+	require.NoError(t, saveProfileConfig(path, &profileConfig{
+		CurrentProfile: "staging",
+		Profiles: map[string]*profile{
+			"staging": {
+				Address: "staging:7233",
+				Domain:  "samples",
+				TLS:     profileTLS{Enabled: true, ServerName: "staging"},
+				Headers: map[string]string{"authorization": "token"},
+			},
+			"prod": {Address: "prod:7233"},
+		},
+	}))
+	os.Setenv(profileConfigEnvVar, path)
+	defer os.Unsetenv(profileConfigEnvVar)
+
+	run := func(args ...string) *cli.Context {
+		var result *cli.Context
+		app := NewCliApp()
+		app.Commands = []cli.Command{{
+			Name: "test",
+			Action: func(c *cli.Context) {
+				result = c
+			},
+		}}
+		require.NoError(t, app.Run(append(append([]string{"tctl"}, args...), "test")))
+		return result
+	}
+
+	c := run()
+	require.Equal(t, "staging:7233", c.GlobalString(FlagAddress))
+	require.Equal(t, "samples", c.GlobalString(FlagDomain))
+	require.True(t, c.GlobalBool(FlagEnableTLS))
+	require.Equal(t, "staging", c.GlobalString(FlagTLSServerName))
+	require.Equal(t, []string{"authorization=token"}, c.GlobalStringSlice(FlagHeader))
+
+	c = run("--address", "other:7233", "--header", "x=y")
+	require.Equal(t, "other:7233", c.GlobalString(FlagAddress))
+	require.Equal(t, "samples", c.GlobalString(FlagDomain))
+	require.Equal(t, []string{"x=y"}, c.GlobalStringSlice(FlagHeader))
+
+	c = run("--profile", "prod")
+	require.Equal(t, "prod:7233", c.GlobalString(FlagAddress))
+	require.Equal(t, "default", c.GlobalString(FlagDomain))
+	require.False(t, c.GlobalBool(FlagEnableTLS))
+}
+
+func TestApplyProfile_ConfigCommandAlias(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tctl-profile-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, profileConfigFileName)
+	require.NoError(t, saveProfileConfig(path, &profileConfig{CurrentProfile: "missing"}))
+	os.Setenv(profileConfigEnvVar, path)
+	defer os.Unsetenv(profileConfigEnvVar)
+
+	for _, name := range []string{"config", "cfg"} {
+		called := false
+		app := NewCliApp()
+		app.Commands = []cli.Command{{
+			Name:    configCommandName,
+			Aliases: []string{"cfg"},
+			Action: func(c *cli.Context) {
+				called = true
+			},
+		}}
+		require.NoError(t, app.Run([]string{"tctl", name}))
+		require.True(t, called, name)
+	}
+}
+
+func TestProfileConfig_Masked(t *testing.T) {
+	config := &profileConfig{
+		CurrentProfile: "staging",
+		Profiles: map[string]*profile{
+			"staging": {
+				Address: "staging:7233",
+				Headers: map[string]string{"authorization": "token"},
+			},
+			"dev": {Address: "localhost:7233"},
+		},
+	}
+
+	masked := config.masked()
+	require.Equal(t, "staging", masked.CurrentProfile)
+	require.Equal(t, "staging:7233", masked.Profiles["staging"].Address)
+	require.Equal(t, map[string]string{"authorization": maskedHeaderValue}, masked.Profiles["staging"].Headers)
+	require.Nil(t, masked.Profiles["dev"].Headers)
+	require.Equal(t, "token", config.Profiles["staging"].Headers["authorization"])
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	run := func(args ...string) *cli.Context {
+		var result *cli.Context
+		app := NewCliApp()
+		app.Before = nil
+		app.Commands = []cli.Command{{
+			Name: "test",
+			Action: func(c *cli.Context) {
+				result = c
+			},
+		}}
+		require.NoError(t, app.Run(append(append([]string{"tctl"}, args...), "test")))
+		return result
+	}
+
+	tlsConfig, err := newTLSConfig(run())
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	tlsConfig, err = newTLSConfig(run("--tls", "--tls_server_name", "frontend"))
+	require.NoError(t, err)
+	require.False(t, tlsConfig.InsecureSkipVerify)
+	require.Equal(t, "frontend", tlsConfig.ServerName)
+
+	tlsConfig, err = newTLSConfig(run("--tls", "--tls_disable_host_verification"))
+	require.NoError(t, err)
+	require.True(t, tlsConfig.InsecureSkipVerify)
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders([]string{"authorization=Bearer a=b", " key = value "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"authorization": "Bearer a=b", "key": "value"}, headers)
+
+	_, err = parseHeaders([]string{"authorization"})
+	require.Error(t, err)
+	_, err = parseHeaders([]string{"=value"})
+	require.Error(t, err)
+}
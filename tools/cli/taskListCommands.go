@@ -44,7 +44,12 @@ func DescribeTaskList(c *cli.Context) {
 		ErrorAndExit("Operation DescribeTaskList failed.", err)
 	}
 
-	pollers := response.Pollers
+	printOutput(c, response, func() {
+		printTaskListPollers(taskList, taskListType, response.Pollers)
+	})
+}
+
+func printTaskListPollers(taskList string, taskListType enums.TaskListType, pollers []*commonproto.PollerInfo) {
 	if len(pollers) == 0 {
 		ErrorAndExit(colorMagenta("No poller for tasklist: "+taskList), nil)
 	}
@@ -82,12 +87,14 @@ func ListTaskListPartitions(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Operation ListTaskListPartitions failed.", err)
 	}
-	if len(response.DecisionTaskListPartitions) > 0 {
-		printTaskListPartitions("Decision", response.DecisionTaskListPartitions)
-	}
-	if len(response.ActivityTaskListPartitions) > 0 {
-		printTaskListPartitions("Activity", response.ActivityTaskListPartitions)
-	}
+	printOutput(c, response, func() {
+		if len(response.DecisionTaskListPartitions) > 0 {
+			printTaskListPartitions("Decision", response.DecisionTaskListPartitions)
+		}
+		if len(response.ActivityTaskListPartitions) > 0 {
+			printTaskListPartitions("Activity", response.ActivityTaskListPartitions)
+		}
+	})
 }
 
 func printTaskListPartitions(taskListType string, partitions []*commonproto.TaskListPartitionMetadata) {
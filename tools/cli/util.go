@@ -42,9 +42,12 @@ import (
 	"github.com/valyala/fastjson"
 	commonproto "go.temporal.io/temporal-proto/common"
 	"go.temporal.io/temporal-proto/enums"
+	"go.temporal.io/temporal-proto/workflowservice"
 	sdkclient "go.temporal.io/temporal/client"
 
+	"github.com/temporalio/temporal/.gen/proto/token"
 	"github.com/temporalio/temporal/common/codec"
+	"github.com/temporalio/temporal/common/historyfilter"
 	"github.com/temporalio/temporal/common/rpc"
 )
 
@@ -66,6 +69,32 @@ func GetHistory(ctx context.Context, workflowClient sdkclient.Client, workflowID
 	return history, nil
 }
 
+// GetFilteredHistory returns the history events accepted by the filter, filtering is done by the server
+func GetFilteredHistory(ctx context.Context, frontendClient workflowservice.WorkflowServiceClient, domain, workflowID, runID string,
+	filter *token.HistoryEventFilter) (*commonproto.History, error) {
+	ctx = historyfilter.AppendToOutgoingContext(ctx, filter)
+	req := &workflowservice.GetWorkflowExecutionHistoryRequest{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      runID,
+		},
+	}
+
+	history := &commonproto.History{}
+	for {
+		resp, err := frontendClient.GetWorkflowExecutionHistory(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		history.Events = append(history.Events, resp.GetHistory().GetEvents()...)
+		if len(resp.NextPageToken) == 0 {
+			return history, nil
+		}
+		req.NextPageToken = resp.NextPageToken
+	}
+}
+
 // HistoryEventToString convert HistoryEvent to string
 func HistoryEventToString(e *commonproto.HistoryEvent, printFully bool, maxFieldLength int) string {
 	data := getEventAttributes(e)
@@ -44,6 +44,15 @@ func newWorkflowCommands() []cli.Command {
 				ShowHistory(c)
 			},
 		},
+		{
+			Name:        "diff",
+			Usage:       "compare the histories of two workflow runs, or of one workflow run in two clusters",
+			Description: "temporal workflow diff -w <wid> -r <rid> -r2 <rid2>, or temporal --ad <cluster1> workflow diff -w <wid> -r <rid> --ad2 <cluster2>",
+			Flags:       getFlagsForDiff(),
+			Action: func(c *cli.Context) {
+				DiffHistory(c)
+			},
+		},
 		{
 			Name:        "showid",
 			Usage:       "show workflow history with given workflow_id and optional run_id (a shortcut of `show -w <wid> -r <rid>`)",
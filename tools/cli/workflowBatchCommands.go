@@ -50,7 +50,7 @@ func TerminateBatchJob(c *cli.Context) {
 	output := map[string]interface{}{
 		"msg": "batch job is terminated",
 	}
-	prettyPrintJSONObject(output)
+	printObject(c, output)
 }
 
 // DescribeBatchJob describe the status of the batch job
@@ -84,7 +84,7 @@ func DescribeBatchJob(c *cli.Context) {
 			output["progress"] = hbd
 		}
 	}
-	prettyPrintJSONObject(output)
+	printObject(c, output)
 }
 
 // ListBatchJobs list the started batch jobs
@@ -120,7 +120,7 @@ func ListBatchJobs(c *cli.Context) {
 
 		output = append(output, job)
 	}
-	prettyPrintJSONObject(output)
+	printObject(c, output)
 }
 
 // StartBatchJob starts a batch job
@@ -150,7 +150,7 @@ func StartBatchJob(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Failed to count impacting workflows for starting a batch job", err)
 	}
-	fmt.Printf("This batch job will be operating on %v workflows.\n", resp.GetCount())
+	printMessage(c, "This batch job will be operating on %v workflows.", resp.GetCount())
 	if !c.Bool(FlagYes) {
 		reader := bufio.NewReader(os.Stdin)
 		for {
@@ -162,7 +162,7 @@ func StartBatchJob(c *cli.Context) {
 			if strings.EqualFold(strings.TrimSpace(text), "yes") {
 				break
 			} else {
-				fmt.Println("Batch job is not started")
+				printMessage(c, "Batch job is not started")
 				return
 			}
 		}
@@ -200,7 +200,7 @@ func StartBatchJob(c *cli.Context) {
 		"msg":   "batch job is started",
 		"jobID": wf.GetID(),
 	}
-	prettyPrintJSONObject(output)
+	printObject(c, output)
 }
 
 func validateBatchType(bt string) bool {
@@ -47,8 +47,10 @@ import (
 	"go.temporal.io/temporal/client"
 
 	cliproto "github.com/temporalio/temporal/.gen/proto/cli"
+	"github.com/temporalio/temporal/.gen/proto/token"
 	"github.com/temporalio/temporal/common/clock"
 	"github.com/temporalio/temporal/common/codec"
+	"github.com/temporalio/temporal/common/historyfilter"
 	"github.com/temporalio/temporal/service/history"
 )
 
@@ -85,46 +87,50 @@ func showHistoryHelper(c *cli.Context, wid, rid string) {
 		maxFieldLength = c.Int(FlagMaxFieldLength)
 	}
 	resetPointsOnly := c.Bool(FlagResetPointsOnly)
+	filter := getHistoryEventFilter(c)
 
 	ctx, cancel := newContext(c)
 	defer cancel()
-	history, err := GetHistory(ctx, wfClient, wid, rid)
+	var history *commonproto.History
+	var err error
+	if filter != nil {
+		domain := getRequiredGlobalOption(c, FlagDomain)
+		history, err = GetFilteredHistory(ctx, cFactory.FrontendClient(c), domain, wid, rid, filter)
+	} else {
+		history, err = GetHistory(ctx, wfClient, wid, rid)
+	}
 	if err != nil {
 		ErrorAndExit(fmt.Sprintf("Failed to get history on workflow id: %s, run id: %s.", wid, rid), err)
 	}
 
-	prevEvent := commonproto.HistoryEvent{}
-	if printFully { // dump everything
-		for _, e := range history.Events {
-			if resetPointsOnly {
-				if prevEvent.GetEventType() != enums.EventTypeDecisionTaskStarted {
-					prevEvent = *e
-					continue
-				}
-				prevEvent = *e
+	events := history.Events
+	if resetPointsOnly {
+		events = getResetPointEvents(events)
+	}
+
+	if !isTableOutput(c) {
+		if c.IsSet(FlagEventID) {
+			e := getHistoryEvent(history, c.Int(FlagEventID))
+			printOutput(c, e, nil)
+		} else {
+			stream := newOutputStream(c)
+			for _, e := range events {
+				stream.Print(e)
 			}
+			stream.Close()
+		}
+	} else if printFully { // dump everything
+		for _, e := range events {
 			fmt.Println(anyToString(e, true, maxFieldLength))
 		}
 	} else if c.IsSet(FlagEventID) { // only dump that event
-		eventID := c.Int(FlagEventID)
-		if eventID <= 0 || eventID > len(history.Events) {
-			ErrorAndExit("EventId out of range.", fmt.Errorf("number should be 1 - %d inclusive", len(history.Events)))
-		}
-		e := history.Events[eventID-1]
+		e := getHistoryEvent(history, c.Int(FlagEventID))
 		fmt.Println(anyToString(e, true, 0))
 	} else { // use table to pretty output, will trim long text
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetBorder(false)
 		table.SetColumnSeparator("")
-		for _, e := range history.Events {
-			if resetPointsOnly {
-				if prevEvent.GetEventType() != enums.EventTypeDecisionTaskStarted {
-					prevEvent = *e
-					continue
-				}
-				prevEvent = *e
-			}
-
+		for _, e := range events {
 			var columns []string
 			columns = append(columns, strconv.FormatInt(e.GetEventId(), 10))
 
@@ -155,6 +161,52 @@ func showHistoryHelper(c *cli.Context, wid, rid string) {
 	}
 }
 
+// getResetPointEvents returns the events that are eligible for reset,
+// which are the events following a DecisionTaskStarted event
+func getResetPointEvents(events []*commonproto.HistoryEvent) []*commonproto.HistoryEvent {
+	var result []*commonproto.HistoryEvent
+	for i := 1; i < len(events); i++ {
+		if events[i-1].GetEventType() == enums.EventTypeDecisionTaskStarted {
+			result = append(result, events[i])
+		}
+	}
+	return result
+}
+
+func getHistoryEvent(history *commonproto.History, eventID int) *commonproto.HistoryEvent {
+	// filtered history does not hold all events, so look the event up by its ID
+	for _, e := range history.Events {
+		if e.GetEventId() == int64(eventID) {
+			return e
+		}
+	}
+	ErrorAndExit("EventId out of range.", fmt.Errorf("event %d is not in the history", eventID))
+	return nil
+}
+
+// getHistoryEventFilter returns the history event filter set by the flags, nil if none is set
+func getHistoryEventFilter(c *cli.Context) *token.HistoryEventFilter {
+	filter := &token.HistoryEventFilter{
+		MinEventId:   c.Int64(FlagMinEventID),
+		MaxEventId:   c.Int64(FlagMaxEventID),
+		OmitPayloads: c.Bool(FlagOmitPayloads),
+	}
+	for _, name := range c.StringSlice(FlagEventType) {
+		eventType, err := historyfilter.ParseEventType(name)
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("Option %s format is invalid.", FlagEventType), err)
+		}
+		filter.EventTypes = append(filter.EventTypes, eventType)
+	}
+	now := time.Now()
+	filter.StartTime = parseTime(c.String(FlagEarliestTime), 0, now)
+	filter.EndTime = parseTime(c.String(FlagLatestTime), 0, now)
+	if historyfilter.IsEmpty(filter) {
+		return nil
+	}
+	return filter
+}
+
 // StartWorkflow starts a new workflow execution
 func StartWorkflow(c *cli.Context) {
 	startWorkflowHelper(c, false)
@@ -224,7 +276,10 @@ func startWorkflowHelper(c *cli.Context, shouldPrintProgress bool) {
 		if err != nil {
 			ErrorAndExit("Failed to create workflow.", err)
 		} else {
-			fmt.Printf("Started Workflow Id: %s, run Id: %s\n", wid, resp.GetRunId())
+			execution := &commonproto.WorkflowExecution{WorkflowId: wid, RunId: resp.GetRunId()}
+			printOutput(c, execution, func() {
+				fmt.Printf("Started Workflow Id: %s, run Id: %s\n", wid, resp.GetRunId())
+			})
 		}
 	}
 
@@ -237,6 +292,12 @@ func startWorkflowHelper(c *cli.Context, shouldPrintProgress bool) {
 			ErrorAndExit("Failed to run workflow.", err)
 		}
 
+		if !isTableOutput(c) {
+			printMessage(c, "Running execution: workflow Id: %s, run Id: %s", wid, resp.GetRunId())
+			printWorkflowProgress(c, wid, resp.GetRunId())
+			return
+		}
+
 		// print execution summary
 		fmt.Println(colorMagenta("Running execution:"))
 		table := tablewriter.NewWriter(os.Stdout)
@@ -345,6 +406,11 @@ func getPrintableSearchAttr(searchAttr *commonproto.SearchAttributes) string {
 
 // helper function to print workflow progress with time refresh every second
 func printWorkflowProgress(c *cli.Context, wid, rid string) {
+	if !isTableOutput(c) {
+		streamWorkflowHistory(c, wid, rid)
+		return
+	}
+
 	fmt.Println(colorMagenta("Progress:"))
 
 	wfClient := getWorkflowClient(c)
@@ -402,6 +468,25 @@ func printWorkflowProgress(c *cli.Context, wid, rid string) {
 	}
 }
 
+// streamWorkflowHistory prints the events of the workflow as they are appended until the workflow is closed
+func streamWorkflowHistory(c *cli.Context, wid, rid string) {
+	wfClient := getWorkflowClient(c)
+
+	tcCtx, cancel := newContextForLongPoll(c)
+	defer cancel()
+
+	stream := newOutputStream(c)
+	iter := wfClient.GetWorkflowHistory(tcCtx, wid, rid, true, enums.HistoryEventFilterTypeAllEvent)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			ErrorAndExit("Unable to read event.", err)
+		}
+		stream.Print(event)
+	}
+	stream.Close()
+}
+
 // TerminateWorkflow terminates a workflow execution
 func TerminateWorkflow(c *cli.Context) {
 	wfClient := getWorkflowClient(c)
@@ -417,7 +502,7 @@ func TerminateWorkflow(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Terminate workflow failed.", err)
 	} else {
-		fmt.Println("Terminate workflow succeeded.")
+		printMessage(c, "Terminate workflow succeeded.")
 	}
 }
 
@@ -435,7 +520,7 @@ func CancelWorkflow(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Cancel workflow failed.", err)
 	} else {
-		fmt.Println("Cancel workflow succeeded.")
+		printMessage(c, "Cancel workflow succeeded.")
 	}
 }
 
@@ -465,7 +550,7 @@ func SignalWorkflow(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Signal workflow failed.", err)
 	} else {
-		fmt.Println("Signal workflow succeeded.")
+		printMessage(c, "Signal workflow succeeded.")
 	}
 }
 
@@ -536,12 +621,30 @@ func queryWorkflowHelper(c *cli.Context, queryType string) {
 		return
 	}
 
-	if queryResponse.QueryRejected != nil {
-		fmt.Printf("Query was rejected, workflow is in state: %v\n", queryResponse.QueryRejected.CloseStatus)
-	} else {
-		// assume it is json encoded
-		fmt.Printf("Query result as JSON:\n%v\n", string(queryResponse.QueryResult))
+	printOutput(c, newQueryWorkflowOutput(queryResponse), func() {
+		if queryResponse.QueryRejected != nil {
+			fmt.Printf("Query was rejected, workflow is in state: %v\n", queryResponse.QueryRejected.CloseStatus)
+		} else {
+			// assume it is json encoded
+			fmt.Printf("Query result as JSON:\n%v\n", string(queryResponse.QueryResult))
+		}
+	})
+}
+
+// newQueryWorkflowOutput returns the structured output of a query,
+// the query result is embedded as is if it is json encoded
+func newQueryWorkflowOutput(resp *workflowservice.QueryWorkflowResponse) map[string]interface{} {
+	if resp.QueryRejected != nil {
+		return map[string]interface{}{
+			"queryRejected": map[string]interface{}{
+				"closeStatus": resp.QueryRejected.CloseStatus.String(),
+			},
+		}
+	}
+	if json.Valid(resp.QueryResult) {
+		return map[string]interface{}{"queryResult": json.RawMessage(resp.QueryResult)}
 	}
+	return map[string]interface{}{"queryResult": string(resp.QueryResult)}
 }
 
 // ListWorkflow list workflow executions based on filters
@@ -552,6 +655,22 @@ func ListWorkflow(c *cli.Context) {
 	printJSON := c.Bool(FlagPrintJSON)
 	printDecodedRaw := c.Bool(FlagPrintFullyDetail)
 
+	if !isTableOutput(c) {
+		// with a structured output, more streams all pages without prompting
+		stream := newOutputStream(c)
+		var results []*commonproto.WorkflowExecutionInfo
+		var nextPageToken []byte
+		for {
+			results, nextPageToken = getListResultInRaw(c, queryOpen, nextPageToken)
+			printExecutionsToStream(stream, results)
+			if !more || len(nextPageToken) == 0 {
+				break
+			}
+		}
+		stream.Close()
+		return
+	}
+
 	if printJSON || printDecodedRaw {
 		if !more {
 			results, _ := getListResultInRaw(c, queryOpen, nil)
@@ -595,6 +714,21 @@ func ListAllWorkflow(c *cli.Context) {
 	printJSON := c.Bool(FlagPrintJSON)
 	printDecodedRaw := c.Bool(FlagPrintFullyDetail)
 
+	if !isTableOutput(c) {
+		stream := newOutputStream(c)
+		var results []*commonproto.WorkflowExecutionInfo
+		var nextPageToken []byte
+		for {
+			results, nextPageToken = getListResultInRaw(c, queryOpen, nextPageToken)
+			printExecutionsToStream(stream, results)
+			if len(nextPageToken) == 0 {
+				break
+			}
+		}
+		stream.Close()
+		return
+	}
+
 	if printJSON || printDecodedRaw {
 		var results []*commonproto.WorkflowExecutionInfo
 		var nextPageToken []byte
@@ -628,6 +762,21 @@ func ScanAllWorkflow(c *cli.Context) {
 	printJSON := c.Bool(FlagPrintJSON)
 	printDecodedRaw := c.Bool(FlagPrintFullyDetail)
 
+	if !isTableOutput(c) {
+		stream := newOutputStream(c)
+		var results []*commonproto.WorkflowExecutionInfo
+		var nextPageToken []byte
+		for {
+			results, nextPageToken = getScanResultInRaw(c, nextPageToken)
+			printExecutionsToStream(stream, results)
+			if len(nextPageToken) == 0 {
+				break
+			}
+		}
+		stream.Close()
+		return
+	}
+
 	if printJSON || printDecodedRaw {
 		var results []*commonproto.WorkflowExecutionInfo
 		var nextPageToken []byte
@@ -677,7 +826,9 @@ func CountWorkflow(c *cli.Context) {
 		ErrorAndExit("Failed to count workflow.", err)
 	}
 
-	fmt.Println(response.GetCount())
+	printOutput(c, response, func() {
+		fmt.Println(response.GetCount())
+	})
 }
 
 // ListArchivedWorkflow lists archived workflow executions based on filters
@@ -727,7 +878,16 @@ func ListArchivedWorkflow(c *cli.Context) {
 	printDateTime := c.Bool(FlagPrintDateTime)
 	printMemo := c.Bool(FlagPrintMemo)
 	printSearchAttr := c.Bool(FlagPrintSearchAttr)
-	if printJSON || printDecodedRaw {
+	if !isTableOutput(c) {
+		// with a structured output, the pages are streamed without prompting
+		printAll = true
+		stream := newOutputStream(c)
+		prePrintFn = func() {}
+		printFn = func(execution []*commonproto.WorkflowExecutionInfo, _ bool) {
+			printExecutionsToStream(stream, execution)
+		}
+		postPrintFn = func() { stream.Close() }
+	} else if printJSON || printDecodedRaw {
 		prePrintFn = func() { fmt.Println("[") }
 		printFn = func(execution []*commonproto.WorkflowExecutionInfo, more bool) {
 			printListResults(execution, printJSON, more)
@@ -829,14 +989,16 @@ func describeWorkflowHelper(c *cli.Context, wid, rid string) {
 	}
 
 	if printResetPointsOnly {
-		printAutoResetPoints(resp)
+		printOutput(c, resp.WorkflowExecutionInfo.GetAutoResetPoints(), func() {
+			printAutoResetPoints(resp)
+		})
 		return
 	}
 
 	if printRaw {
-		prettyPrintJSONObject(resp)
+		printObject(c, resp)
 	} else {
-		prettyPrintJSONObject(convertDescribeWorkflowExecutionResponse(resp, frontendClient, c))
+		printObject(c, convertDescribeWorkflowExecutionResponse(resp, frontendClient, c))
 	}
 }
 
@@ -1320,6 +1482,12 @@ func getWorkflowIDReusePolicy(value int) enums.WorkflowIdReusePolicy {
 	return enums.WorkflowIdReusePolicyAllowDuplicateFailedOnly //doesn't really matter
 }
 
+func printExecutionsToStream(stream *outputStream, executions []*commonproto.WorkflowExecutionInfo) {
+	for _, execution := range executions {
+		stream.Print(execution)
+	}
+}
+
 // default will print decoded raw
 func printListResults(executions []*commonproto.WorkflowExecutionInfo, inJSON bool, more bool) {
 	encoder := codec.NewJSONPBEncoder()
@@ -1395,14 +1563,14 @@ func ResetWorkflow(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("reset failed", err)
 	}
-	prettyPrintJSONObject(resp)
+	printObject(c, resp)
 }
 
 func processResets(c *cli.Context, domain string, wes chan commonproto.WorkflowExecution, done chan bool, wg *sync.WaitGroup, params batchResetParamsType) {
 	for {
 		select {
 		case we := <-wes:
-			fmt.Println("received: ", we.GetWorkflowId(), we.GetRunId())
+			printMessage(c, "received: %v %v", we.GetWorkflowId(), we.GetRunId())
 			wid := we.GetWorkflowId()
 			rid := we.GetRunId()
 			var err error
@@ -1414,12 +1582,12 @@ func processResets(c *cli.Context, domain string, wes chan commonproto.WorkflowE
 				if _, ok := err.(*serviceerror.InvalidArgument); ok {
 					break
 				}
-				fmt.Println("failed and retry...: ", wid, rid, err)
+				printMessage(c, "failed and retry...: %v %v %v", wid, rid, err)
 				time.Sleep(time.Millisecond * time.Duration(rand.Intn(2000)))
 			}
 			time.Sleep(time.Millisecond * time.Duration(rand.Intn(1000)))
 			if err != nil {
-				fmt.Println("[ERROR] failed processing: ", wid, rid, err.Error())
+				printMessage(c, "[ERROR] failed processing: %v %v %v", wid, rid, err.Error())
 			}
 		case <-done:
 			wg.Done()
@@ -1493,7 +1661,7 @@ func ResetInBatch(c *cli.Context) {
 			idx++
 			line := strings.TrimSpace(scanner.Text())
 			if len(line) == 0 {
-				fmt.Printf("line %v is empty, skipped\n", idx)
+				printMessage(c, "line %v is empty, skipped", idx)
 				continue
 			}
 			cols := strings.Split(line, separator)
@@ -1505,7 +1673,7 @@ func ResetInBatch(c *cli.Context) {
 			excludes[wid] = rid
 		}
 	}
-	fmt.Println("num of excludes:", len(excludes))
+	printMessage(c, "num of excludes: %v", len(excludes))
 
 	if len(inFileName) > 0 {
 		inFile, err := os.Open(inFileName)
@@ -1519,14 +1687,14 @@ func ResetInBatch(c *cli.Context) {
 			idx++
 			line := strings.TrimSpace(scanner.Text())
 			if len(line) == 0 {
-				fmt.Printf("line %v is empty, skipped\n", idx)
+				printMessage(c, "line %v is empty, skipped", idx)
 				continue
 			}
 			cols := strings.Split(line, separator)
 			if len(cols) < 1 {
 				ErrorAndExit("Split failed", fmt.Errorf("line %v has less than 1 cols separated by comma, only %v ", idx, len(cols)))
 			}
-			fmt.Printf("Start processing line %v ...\n", idx)
+			printMessage(c, "Start processing line %v ...", idx)
 			wid := strings.TrimSpace(cols[0])
 			rid := ""
 			if len(cols) > 1 {
@@ -1535,7 +1703,7 @@ func ResetInBatch(c *cli.Context) {
 
 			_, ok := excludes[wid]
 			if ok {
-				fmt.Println("skip by exclude file: ", wid, rid)
+				printMessage(c, "skip by exclude file: %v %v", wid, rid)
 				continue
 			}
 
@@ -1556,7 +1724,7 @@ func ResetInBatch(c *cli.Context) {
 				rid := we.Execution.GetRunId()
 				_, ok := excludes[wid]
 				if ok {
-					fmt.Println("skip by exclude file: ", wid, rid)
+					printMessage(c, "skip by exclude file: %v %v", wid, rid)
 					continue
 				}
 
@@ -1573,7 +1741,7 @@ func ResetInBatch(c *cli.Context) {
 	}
 
 	close(done)
-	fmt.Println("wait for all goroutines...")
+	printMessage(c, "wait for all goroutines...")
 	wg.Wait()
 }
 
@@ -1612,7 +1780,7 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 
 	currentRunID := resp.WorkflowExecutionInfo.Execution.GetRunId()
 	if currentRunID != rid && params.skipBaseNotCurrent {
-		fmt.Println("skip because base run is different from current run: ", wid, rid, currentRunID)
+		printMessage(c, "skip because base run is different from current run: %v %v %v", wid, rid, currentRunID)
 		return nil
 	}
 	if rid == "" {
@@ -1621,7 +1789,7 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 
 	if resp.WorkflowExecutionInfo.CloseStatus == enums.WorkflowExecutionCloseStatusRunning || resp.WorkflowExecutionInfo.CloseTime == nil {
 		if params.skipOpen {
-			fmt.Println("skip because current run is open: ", wid, rid, currentRunID)
+			printMessage(c, "skip because current run is open: %v %v %v", wid, rid, currentRunID)
 			//skip and not terminate current if open
 			return nil
 		}
@@ -1633,7 +1801,7 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 			return printErrorAndReturn("check isLastEventDecisionTaskFailedWithNonDeterminism failed", err)
 		}
 		if !isLDN {
-			fmt.Println("skip because last event is not DecisionTaskFailedWithNonDeterminism")
+			printMessage(c, "skip because last event is not DecisionTaskFailedWithNonDeterminism")
 			return nil
 		}
 	}
@@ -1642,10 +1810,10 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 	if err != nil {
 		return printErrorAndReturn("getResetEventIDByType failed", err)
 	}
-	fmt.Println("DecisionFinishEventId for reset:", wid, rid, resetBaseRunID, decisionFinishID)
+	printMessage(c, "DecisionFinishEventId for reset: %v %v %v %v", wid, rid, resetBaseRunID, decisionFinishID)
 
 	if params.dryRun {
-		fmt.Printf("dry run to reset wid: %v, rid:%v to baseRunID:%v, eventID:%v \n", wid, rid, resetBaseRunID, decisionFinishID)
+		printMessage(c, "dry run to reset wid: %v, rid:%v to baseRunID:%v, eventID:%v ", wid, rid, resetBaseRunID, decisionFinishID)
 	} else {
 		resp2, err := frontendClient.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
 			Domain: domain,
@@ -1661,7 +1829,7 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 		if err != nil {
 			return printErrorAndReturn("ResetWorkflowExecution failed", err)
 		}
-		fmt.Println("new runID for wid/rid is ,", wid, rid, resp2.GetRunId())
+		printMessage(c, "new runID for wid/rid is , %v %v %v", wid, rid, resp2.GetRunId())
 	}
 
 	return nil
@@ -1714,7 +1882,7 @@ func isLastEventDecisionTaskFailedWithNonDeterminism(ctx context.Context, domain
 }
 
 func getResetEventIDByType(ctx context.Context, c *cli.Context, resetType, domain, wid, rid string, frontendClient workflowservice.WorkflowServiceClient) (resetBaseRunID string, decisionFinishID int64, err error) {
-	fmt.Println("resetType:", resetType)
+	printMessage(c, "resetType: %v", resetType)
 	switch resetType {
 	case "LastDecisionCompleted":
 		resetBaseRunID, decisionFinishID, err = getLastDecisionCompletedID(ctx, domain, wid, rid, frontendClient)
@@ -1918,7 +2086,7 @@ func CompleteActivity(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Completing activity failed", err)
 	} else {
-		fmt.Println("Complete activity successfully.")
+		printMessage(c, "Complete activity successfully.")
 	}
 }
 
@@ -1950,7 +2118,7 @@ func FailActivity(c *cli.Context) {
 	if err != nil {
 		ErrorAndExit("Failing activity failed", err)
 	} else {
-		fmt.Println("Fail activity successfully.")
+		printMessage(c, "Fail activity successfully.")
 	}
 }
 
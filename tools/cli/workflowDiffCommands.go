@@ -0,0 +1,532 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli"
+	commonproto "go.temporal.io/temporal-proto/common"
+
+	"github.com/temporalio/temporal/.gen/proto/adminservice"
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/codec"
+	"github.com/temporalio/temporal/common/persistence"
+	"github.com/temporalio/temporal/common/persistence/serialization"
+	"github.com/temporalio/temporal/common/primitives"
+)
+
+const (
+	diffRawHistoryPageSize = 100
+	diffMissingEvent       = "<missing>"
+	diffEventField         = "<event>"
+)
+
+type (
+	// historyDiffSource is one side of a history comparison
+	historyDiffSource struct {
+		address        string
+		workflowID     string
+		runID          string
+		events         []*commonproto.HistoryEvent
+		versionHistory *commonproto.VersionHistory
+	}
+
+	// eventFieldDiff is a difference between two events with the same event ID
+	eventFieldDiff struct {
+		eventID int64
+		field   string
+		left    string
+		right   string
+	}
+
+	// historyDiff is the result of comparing two histories
+	historyDiff struct {
+		// lcaItem is the lowest common ancestor of the two version histories, nil if unknown
+		lcaItem *persistence.VersionHistoryItem
+		// divergedEventID is the first event ID which differs, common.EmptyEventID if the histories are identical
+		divergedEventID int64
+		fieldDiffs      []eventFieldDiff
+	}
+
+	// historyDiffOutput is the structured form of a history comparison used by non-table output formats
+	historyDiffOutput struct {
+		Left            historyDiffSourceOutput `json:"left"`
+		Right           historyDiffSourceOutput `json:"right"`
+		LCAEventID      *int64                  `json:"lcaEventId,omitempty"`
+		LCAVersion      *int64                  `json:"lcaVersion,omitempty"`
+		Identical       bool                    `json:"identical"`
+		DivergedEventID int64                   `json:"divergedEventId,omitempty"`
+		FieldDiffs      []eventFieldDiffOutput  `json:"fieldDiffs"`
+	}
+
+	historyDiffSourceOutput struct {
+		Address    string `json:"address"`
+		WorkflowID string `json:"workflowId"`
+		RunID      string `json:"runId"`
+		EventCount int    `json:"eventCount"`
+	}
+
+	eventFieldDiffOutput struct {
+		EventID int64  `json:"eventId"`
+		Field   string `json:"field"`
+		Left    string `json:"left"`
+		Right   string `json:"right"`
+	}
+)
+
+// DiffHistory compares the histories of two workflow runs, or of one workflow run in two clusters
+func DiffHistory(c *cli.Context) {
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := getRequiredOption(c, FlagRunID)
+	wid2 := c.String(FlagWorkflowID2)
+	if wid2 == "" {
+		wid2 = wid
+	}
+	rid2 := c.String(FlagRunID2)
+	if rid2 == "" {
+		rid2 = rid
+	}
+	address := c.GlobalString(FlagAddress)
+	address2 := c.String(FlagAddress2)
+	if address2 == "" {
+		address2 = address
+	}
+	if address == address2 && wid == wid2 && rid == rid2 {
+		ErrorAndExit(fmt.Sprintf("Option %s, %s or %s is required to compare two different histories.", FlagWorkflowID2, FlagRunID2, FlagAddress2), nil)
+	}
+
+	leftClient := cFactory.AdminClient(c)
+	rightClient := leftClient
+	if address2 != address {
+		rightClient = cFactory.AdminClientForAddress(c, address2)
+	}
+
+	left := getDiffSource(c, leftClient, domain, address, wid, rid)
+	right := getDiffSource(c, rightClient, domain, address2, wid2, rid2)
+
+	ignoredFields := make(map[string]struct{})
+	if c.String(FlagIgnoreFields) != "" {
+		for _, field := range trimSpace(strings.Split(c.String(FlagIgnoreFields), ",")) {
+			ignoredFields[field] = struct{}{}
+		}
+	}
+
+	diff, err := diffHistories(left, right, ignoredFields)
+	if err != nil {
+		ErrorAndExit("Failed to compare histories.", err)
+	}
+	printOutput(c, newHistoryDiffOutput(left, right, diff), func() {
+		printHistoryDiff(left, right, diff, c.Int(FlagMaxFieldLength))
+	})
+}
+
+func getDiffSource(
+	c *cli.Context,
+	adminClient adminservice.AdminServiceClient,
+	domain string,
+	address string,
+	wid string,
+	rid string,
+) *historyDiffSource {
+
+	source := &historyDiffSource{
+		address:    address,
+		workflowID: wid,
+		runID:      rid,
+	}
+	if source.address == "" {
+		source.address = localHostPort
+	}
+
+	startItem, endItem := describeDiffSourceRange(c, adminClient, domain, source)
+
+	serializer := persistence.NewPayloadSerializer()
+	var token []byte
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.GetWorkflowExecutionRawHistoryV2(ctx, &adminservice.GetWorkflowExecutionRawHistoryV2Request{
+			Domain: domain,
+			Execution: &commonproto.WorkflowExecution{
+				WorkflowId: wid,
+				RunId:      rid,
+			},
+			StartEventId:      startItem.EventID,
+			StartEventVersion: startItem.Version,
+			EndEventId:        endItem.EventID,
+			EndEventVersion:   endItem.Version,
+			MaximumPageSize:   diffRawHistoryPageSize,
+			NextPageToken:     token,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("Failed to get history on workflow id: %s, run id: %s from %s.", wid, rid, source.address), err)
+		}
+
+		if resp.VersionHistory != nil {
+			source.versionHistory = resp.VersionHistory
+		}
+		for _, blob := range resp.HistoryBatches {
+			events, err := serializer.DeserializeBatchEvents(persistence.NewDataBlobFromProto(blob))
+			if err != nil {
+				ErrorAndExit("DeserializeBatchEvents err", err)
+			}
+			source.events = append(source.events, events...)
+		}
+
+		token = resp.NextPageToken
+		if len(token) == 0 {
+			return source
+		}
+	}
+}
+
+// describeDiffSourceRange loads the mutable state of the source workflow and returns the
+// exclusive event range covering its whole current branch
+func describeDiffSourceRange(
+	c *cli.Context,
+	adminClient adminservice.AdminServiceClient,
+	domain string,
+	source *historyDiffSource,
+) (*persistence.VersionHistoryItem, *persistence.VersionHistoryItem) {
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.DescribeWorkflowExecution(ctx, &adminservice.DescribeWorkflowExecutionRequest{
+		Domain: domain,
+		Execution: &commonproto.WorkflowExecution{
+			WorkflowId: source.workflowID,
+			RunId:      source.runID,
+		},
+	})
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to describe workflow id: %s, run id: %s from %s.", source.workflowID, source.runID, source.address), err)
+	}
+
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(resp.GetMutableStateInDatabase()), &ms); err != nil {
+		ErrorAndExit("json.Unmarshal err", err)
+	}
+
+	startItem, endItem, err := getCurrentBranchRange(ms.VersionHistories)
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to get event range on workflow id: %s, run id: %s from %s.", source.workflowID, source.runID, source.address), err)
+	}
+	return startItem, endItem
+}
+
+// getCurrentBranchRange returns the exclusive start and end items which select every event
+// of the current version history branch
+func getCurrentBranchRange(
+	versionHistories *persistence.VersionHistories,
+) (*persistence.VersionHistoryItem, *persistence.VersionHistoryItem, error) {
+
+	if versionHistories == nil {
+		return nil, nil, errors.New("workflow has no version histories")
+	}
+	currentVersionHistory, err := versionHistories.GetCurrentVersionHistory()
+	if err != nil {
+		return nil, nil, err
+	}
+	firstItem, err := currentVersionHistory.GetFirstItem()
+	if err != nil {
+		return nil, nil, err
+	}
+	lastItem, err := currentVersionHistory.GetLastItem()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	startItem := persistence.NewVersionHistoryItem(common.FirstEventID-1, firstItem.Version)
+	endItem := persistence.NewVersionHistoryItem(lastItem.EventID+1, lastItem.Version)
+	return startItem, endItem, nil
+}
+
+// diffHistories aligns the events of both histories by event ID and compares their fields.
+// Events after the lowest common ancestor of the version histories are on diverged branches.
+func diffHistories(
+	left *historyDiffSource,
+	right *historyDiffSource,
+	ignoredFields map[string]struct{},
+) (*historyDiff, error) {
+
+	diff := &historyDiff{
+		divergedEventID: common.EmptyEventID,
+	}
+
+	if len(left.versionHistory.GetItems()) > 0 && len(right.versionHistory.GetItems()) > 0 {
+		lcaItem, err := persistence.NewVersionHistoryFromProto(left.versionHistory).FindLCAItem(
+			persistence.NewVersionHistoryFromProto(right.versionHistory),
+		)
+		if err == nil {
+			diff.lcaItem = lcaItem
+		}
+	}
+
+	leftEvents := indexEventsByID(left.events)
+	rightEvents := indexEventsByID(right.events)
+	eventIDs := make([]int64, 0, len(leftEvents)+len(rightEvents))
+	for eventID := range leftEvents {
+		eventIDs = append(eventIDs, eventID)
+	}
+	for eventID := range rightEvents {
+		if _, ok := leftEvents[eventID]; !ok {
+			eventIDs = append(eventIDs, eventID)
+		}
+	}
+	sort.Slice(eventIDs, func(i, j int) bool { return eventIDs[i] < eventIDs[j] })
+
+	for _, eventID := range eventIDs {
+		leftEvent, rightEvent := leftEvents[eventID], rightEvents[eventID]
+
+		var fieldDiffs []eventFieldDiff
+		if leftEvent == nil || rightEvent == nil {
+			fieldDiffs = []eventFieldDiff{{
+				eventID: eventID,
+				field:   diffEventField,
+				left:    eventTypeOrMissing(leftEvent),
+				right:   eventTypeOrMissing(rightEvent),
+			}}
+		} else {
+			leftFields, err := flattenEvent(leftEvent)
+			if err != nil {
+				return nil, err
+			}
+			rightFields, err := flattenEvent(rightEvent)
+			if err != nil {
+				return nil, err
+			}
+			fieldDiffs = diffEventFields(eventID, leftFields, rightFields, ignoredFields)
+		}
+
+		if len(fieldDiffs) > 0 && diff.divergedEventID == common.EmptyEventID {
+			diff.divergedEventID = eventID
+		}
+		diff.fieldDiffs = append(diff.fieldDiffs, fieldDiffs...)
+	}
+	return diff, nil
+}
+
+func indexEventsByID(events []*commonproto.HistoryEvent) map[int64]*commonproto.HistoryEvent {
+	result := make(map[int64]*commonproto.HistoryEvent, len(events))
+	for _, event := range events {
+		result[event.GetEventId()] = event
+	}
+	return result
+}
+
+func eventTypeOrMissing(event *commonproto.HistoryEvent) string {
+	if event == nil {
+		return diffMissingEvent
+	}
+	return event.GetEventType().String()
+}
+
+func diffEventFields(
+	eventID int64,
+	leftFields map[string]string,
+	rightFields map[string]string,
+	ignoredFields map[string]struct{},
+) []eventFieldDiff {
+
+	fields := make([]string, 0, len(leftFields)+len(rightFields))
+	for field := range leftFields {
+		fields = append(fields, field)
+	}
+	for field := range rightFields {
+		if _, ok := leftFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var result []eventFieldDiff
+	for _, field := range fields {
+		if isIgnoredField(field, ignoredFields) {
+			continue
+		}
+		leftValue, leftOK := leftFields[field]
+		rightValue, rightOK := rightFields[field]
+		if leftOK && rightOK && leftValue == rightValue {
+			continue
+		}
+		if !leftOK {
+			leftValue = diffMissingEvent
+		}
+		if !rightOK {
+			rightValue = diffMissingEvent
+		}
+		result = append(result, eventFieldDiff{
+			eventID: eventID,
+			field:   field,
+			left:    leftValue,
+			right:   rightValue,
+		})
+	}
+	return result
+}
+
+// isIgnoredField matches the field path as well as its last element,
+// so "timestamp" ignores the timestamp of every event
+func isIgnoredField(field string, ignoredFields map[string]struct{}) bool {
+	if _, ok := ignoredFields[field]; ok {
+		return true
+	}
+	if idx := strings.LastIndex(field, "."); idx >= 0 {
+		_, ok := ignoredFields[field[idx+1:]]
+		return ok
+	}
+	return false
+}
+
+// flattenEvent returns the leaf fields of the json representation of the event, keyed by their dotted path
+func flattenEvent(event *commonproto.HistoryEvent) (map[string]string, error) {
+	data, err := codec.NewJSONPBEncoder().Encode(event)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	flattenJSONValue("", value, result)
+	return result, nil
+}
+
+func flattenJSONValue(path string, value interface{}, result map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenJSONValue(childPath, child, result)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenJSONValue(path+"["+strconv.Itoa(i)+"]", child, result)
+		}
+	default:
+		result[path] = fmt.Sprintf("%v", v)
+	}
+}
+
+func newHistoryDiffOutput(left, right *historyDiffSource, diff *historyDiff) *historyDiffOutput {
+	output := &historyDiffOutput{
+		Left:       newHistoryDiffSourceOutput(left),
+		Right:      newHistoryDiffSourceOutput(right),
+		Identical:  diff.divergedEventID == common.EmptyEventID,
+		FieldDiffs: make([]eventFieldDiffOutput, 0, len(diff.fieldDiffs)),
+	}
+	if diff.lcaItem != nil {
+		lcaEventID, lcaVersion := diff.lcaItem.GetEventID(), diff.lcaItem.GetVersion()
+		output.LCAEventID, output.LCAVersion = &lcaEventID, &lcaVersion
+	}
+	if !output.Identical {
+		output.DivergedEventID = diff.divergedEventID
+	}
+	for _, fieldDiff := range diff.fieldDiffs {
+		output.FieldDiffs = append(output.FieldDiffs, eventFieldDiffOutput{
+			EventID: fieldDiff.eventID,
+			Field:   fieldDiff.field,
+			Left:    fieldDiff.left,
+			Right:   fieldDiff.right,
+		})
+	}
+	return output
+}
+
+func newHistoryDiffSourceOutput(source *historyDiffSource) historyDiffSourceOutput {
+	return historyDiffSourceOutput{
+		Address:    source.address,
+		WorkflowID: source.workflowID,
+		RunID:      source.runID,
+		EventCount: len(source.events),
+	}
+}
+
+func printHistoryDiff(left, right *historyDiffSource, diff *historyDiff, maxFieldLength int) {
+	printDiffSource("Left", left)
+	printDiffSource("Right", right)
+
+	if diff.lcaItem != nil {
+		fmt.Printf("Lowest common ancestor: event ID %v, version %v\n", diff.lcaItem.GetEventID(), diff.lcaItem.GetVersion())
+	} else {
+		fmt.Println("Lowest common ancestor: not found")
+	}
+
+	if diff.divergedEventID == common.EmptyEventID {
+		fmt.Println(colorGreen("Histories are identical."))
+		return
+	}
+	fmt.Printf("%s %v\n", colorRed("Histories diverge at event ID"), diff.divergedEventID)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Event ID", "Field", "Left", "Right"})
+	table.SetHeaderLine(false)
+	for _, fieldDiff := range diff.fieldDiffs {
+		table.Append([]string{
+			strconv.FormatInt(fieldDiff.eventID, 10),
+			fieldDiff.field,
+			trimTextAndBreakWords(fieldDiff.left, maxFieldLength),
+			trimTextAndBreakWords(fieldDiff.right, maxFieldLength),
+		})
+	}
+	table.Render()
+}
+
+func printDiffSource(name string, source *historyDiffSource) {
+	fmt.Printf("%s: address %v, workflow ID %v, run ID %v, %v events\n",
+		name, source.address, source.workflowID, source.runID, len(source.events))
+
+	if source.versionHistory == nil {
+		return
+	}
+	if branchInfo, err := serialization.HistoryBranchFromBlob(
+		source.versionHistory.GetBranchToken(),
+		common.EncodingTypeProto3.String(),
+	); err == nil {
+		fmt.Printf("  branch: tree ID %v, branch ID %v\n",
+			primitives.UUIDString(branchInfo.GetTreeID()), primitives.UUIDString(branchInfo.GetBranchID()))
+		for _, ancestor := range branchInfo.GetAncestors() {
+			fmt.Printf("  ancestor: branch ID %v, events [%v, %v)\n",
+				primitives.UUIDString(ancestor.GetBranchID()), ancestor.GetBeginNodeID(), ancestor.GetEndNodeID())
+		}
+	}
+	items := make([]string, 0, len(source.versionHistory.GetItems()))
+	for _, item := range source.versionHistory.GetItems() {
+		items = append(items, fmt.Sprintf("(%v, %v)", item.GetEventID(), item.GetVersion()))
+	}
+	fmt.Printf("  version history (event ID, version): %v\n", strings.Join(items, " "))
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonproto "go.temporal.io/temporal-proto/common"
+	"go.temporal.io/temporal-proto/enums"
+
+	"github.com/temporalio/temporal/common"
+	"github.com/temporalio/temporal/common/persistence"
+)
+
+func TestDiffHistories_Identical(t *testing.T) {
+	left := newDiffTestSource(
+		&commonproto.VersionHistoryItem{EventID: 2, Version: 1},
+		newDiffTestEvent(1, enums.EventTypeWorkflowExecutionStarted, 1, 100),
+		newDiffTestEvent(2, enums.EventTypeDecisionTaskScheduled, 1, 101),
+	)
+	right := newDiffTestSource(
+		&commonproto.VersionHistoryItem{EventID: 2, Version: 1},
+		newDiffTestEvent(1, enums.EventTypeWorkflowExecutionStarted, 1, 200),
+		newDiffTestEvent(2, enums.EventTypeDecisionTaskScheduled, 1, 201),
+	)
+
+	diff, err := diffHistories(left, right, map[string]struct{}{"taskId": {}})
+	require.NoError(t, err)
+	require.Equal(t, common.EmptyEventID, diff.divergedEventID)
+	require.Empty(t, diff.fieldDiffs)
+	require.Equal(t, int64(2), diff.lcaItem.GetEventID())
+	require.Equal(t, int64(1), diff.lcaItem.GetVersion())
+}
+
+func TestDiffHistories_Diverged(t *testing.T) {
+	left := newDiffTestSource(
+		&commonproto.VersionHistoryItem{EventID: 3, Version: 1},
+		newDiffTestEvent(1, enums.EventTypeWorkflowExecutionStarted, 1, 100),
+		newDiffTestEvent(2, enums.EventTypeDecisionTaskScheduled, 1, 101),
+		newDiffTestEvent(3, enums.EventTypeDecisionTaskStarted, 1, 102),
+	)
+	right := newDiffTestSource(
+		&commonproto.VersionHistoryItem{EventID: 2, Version: 1},
+		newDiffTestEvent(1, enums.EventTypeWorkflowExecutionStarted, 1, 100),
+		newDiffTestEvent(2, enums.EventTypeDecisionTaskScheduled, 1, 101),
+	)
+	right.versionHistory.Items = append(right.versionHistory.Items, &commonproto.VersionHistoryItem{EventID: 4, Version: 2})
+	right.events = append(right.events,
+		newDiffTestEvent(3, enums.EventTypeDecisionTaskTimedOut, 2, 102),
+		newDiffTestEvent(4, enums.EventTypeDecisionTaskScheduled, 2, 103),
+	)
+
+	diff, err := diffHistories(left, right, map[string]struct{}{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), diff.lcaItem.GetEventID())
+	require.Equal(t, int64(3), diff.divergedEventID)
+	require.Equal(t, []eventFieldDiff{
+		{eventID: 3, field: "eventType", left: "EventTypeDecisionTaskStarted", right: "EventTypeDecisionTaskTimedOut"},
+		{eventID: 3, field: "version", left: "1", right: "2"},
+		{eventID: 4, field: diffEventField, left: diffMissingEvent, right: "EventTypeDecisionTaskScheduled"},
+	}, diff.fieldDiffs)
+}
+
+func TestIsIgnoredField(t *testing.T) {
+	ignoredFields := map[string]struct{}{
+		"timestamp": {},
+		"workflowExecutionStartedEventAttributes.input": {},
+	}
+	require.True(t, isIgnoredField("timestamp", ignoredFields))
+	require.True(t, isIgnoredField("workflowExecutionStartedEventAttributes.input", ignoredFields))
+	require.True(t, isIgnoredField("activityTaskScheduledEventAttributes.header.timestamp", ignoredFields))
+	require.False(t, isIgnoredField("eventType", ignoredFields))
+	require.False(t, isIgnoredField("activityTaskScheduledEventAttributes.input", ignoredFields))
+}
+
+func TestGetCurrentBranchRange(t *testing.T) {
+	versionHistory := persistence.NewVersionHistory([]byte("branch"), []*persistence.VersionHistoryItem{
+		persistence.NewVersionHistoryItem(3, 1),
+		persistence.NewVersionHistoryItem(7, 2),
+	})
+	startItem, endItem, err := getCurrentBranchRange(persistence.NewVersionHistories(versionHistory))
+	require.NoError(t, err)
+	require.Equal(t, persistence.NewVersionHistoryItem(common.FirstEventID-1, 1), startItem)
+	require.Equal(t, persistence.NewVersionHistoryItem(8, 2), endItem)
+}
+
+func TestGetCurrentBranchRange_NoVersionHistories(t *testing.T) {
+	_, _, err := getCurrentBranchRange(nil)
+	require.Error(t, err)
+}
+
+func newDiffTestSource(item *commonproto.VersionHistoryItem, events ...*commonproto.HistoryEvent) *historyDiffSource {
+	return &historyDiffSource{
+		events: events,
+		versionHistory: &commonproto.VersionHistory{
+			Items: []*commonproto.VersionHistoryItem{item},
+		},
+	}
+}
+
+func newDiffTestEvent(eventID int64, eventType enums.EventType, version int64, taskID int64) *commonproto.HistoryEvent {
+	return &commonproto.HistoryEvent{
+		EventId:   eventID,
+		EventType: eventType,
+		Version:   version,
+		TaskId:    taskID,
+	}
+}
@@ -76,6 +76,15 @@ func Update(cli *cli.Context, db DB) error {
 	return newUpdateSchemaTask(db, cfg).Run()
 }
 
+// Rollback rolls back the schema for the specified database to the target version
+func Rollback(cli *cli.Context, db DB) error {
+	cfg, err := newRollbackConfig(cli)
+	if err != nil {
+		return err
+	}
+	return newRollbackSchemaTask(db, cfg).Run()
+}
+
 func newUpdateConfig(cli *cli.Context) (*UpdateConfig, error) {
 	config := new(UpdateConfig)
 	config.SchemaDir = cli.String(CLIOptSchemaDir)
@@ -88,6 +97,18 @@ func newUpdateConfig(cli *cli.Context) (*UpdateConfig, error) {
 	return config, nil
 }
 
+func newRollbackConfig(cli *cli.Context) (*RollbackConfig, error) {
+	config := new(RollbackConfig)
+	config.SchemaDir = cli.String(CLIOptSchemaDir)
+	config.IsDryRun = cli.Bool(CLIOptDryrun)
+	config.TargetVersion = cli.String(CLIOptTargetVersion)
+
+	if err := validateRollbackConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 func newSetupConfig(cli *cli.Context) (*SetupConfig, error) {
 	config := new(SetupConfig)
 	config.SchemaFilePath = cli.String(CLIOptSchemaFile)
@@ -134,6 +155,21 @@ func validateUpdateConfig(config *UpdateConfig) error {
 	return nil
 }
 
+func validateRollbackConfig(config *RollbackConfig) error {
+	if len(config.SchemaDir) == 0 {
+		return NewConfigError("missing " + flag(CLIOptSchemaDir) + " argument ")
+	}
+	if len(config.TargetVersion) == 0 {
+		return NewConfigError("missing " + flag(CLIOptTargetVersion) + " argument ")
+	}
+	ver, err := parseValidateVersion(config.TargetVersion)
+	if err != nil {
+		return NewConfigError("invalid " + flag(CLIOptTargetVersion) + " argument:" + err.Error())
+	}
+	config.TargetVersion = ver
+	return nil
+}
+
 func flag(opt string) string {
 	return "(-" + opt + ")"
 }
@@ -101,6 +101,29 @@ func (s *HandlerTestSuite) TestValidateUpdateConfig() {
 	s.Equal("1.2", config.TargetVersion)
 }
 
+func (s *HandlerTestSuite) TestValidateRollbackConfig() {
+
+	config := new(RollbackConfig)
+	s.Error(validateRollbackConfig(config))
+
+	config.SchemaDir = "/tmp"
+	config.TargetVersion = ""
+	s.Error(validateRollbackConfig(config))
+
+	config.SchemaDir = "/tmp"
+	config.TargetVersion = "abc"
+	s.Error(validateRollbackConfig(config))
+
+	config.SchemaDir = ""
+	config.TargetVersion = "1.2"
+	s.Error(validateRollbackConfig(config))
+
+	config.SchemaDir = "/tmp"
+	config.TargetVersion = "v1.2"
+	s.NoError(validateRollbackConfig(config))
+	s.Equal("1.2", config.TargetVersion)
+}
+
 func (s *HandlerTestSuite) assertValidateSetupSucceeds(input *SetupConfig) {
 	err := validateSetupConfig(input)
 	s.Nil(err)
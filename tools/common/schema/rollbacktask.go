@@ -0,0 +1,252 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package schema
+
+import (
+	"fmt"
+	"log"
+)
+
+type (
+	// RollbackTask represents a task
+	// that rolls back schema updates
+	RollbackTask struct {
+		db     DB
+		config *RollbackConfig
+	}
+
+	// rollbackSet represents the changes which
+	// revert a single schema version
+	rollbackSet struct {
+		version                  string
+		prevVersion              string
+		prevMinCompatibleVersion string
+		manifest                 *manifest
+		manifestMD5              string
+		checksum                 string
+		stmts                    []string
+	}
+)
+
+var (
+	// rollback scripts mostly drop what the update scripts created
+	whitelistedRollbackPrefixes = []string{"CREATE", "ALTER", "INSERT", "UPDATE", "DELETE", "DROP"}
+)
+
+// newRollbackSchemaTask returns a new instance of RollbackTask
+func newRollbackSchemaTask(db DB, config *RollbackConfig) *RollbackTask {
+	return &RollbackTask{
+		db:     db,
+		config: config,
+	}
+}
+
+// Run executes the task
+func (task *RollbackTask) Run() error {
+	config := task.config
+
+	log.Printf("RollbackSchemaTask started, config=%+v\n", config)
+
+	currVer, err := task.db.ReadSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("error reading current schema version:%v", err.Error())
+	}
+
+	if cmpVersion(config.TargetVersion, currVer) >= 0 {
+		return fmt.Errorf("target version %v must be lower than current version %v", config.TargetVersion, currVer)
+	}
+
+	rollbacks, err := task.buildRollbackSet(currVer)
+	if err != nil {
+		return err
+	}
+
+	if err := task.verifyChecksums(rollbacks); err != nil {
+		return err
+	}
+
+	if config.IsDryRun {
+		task.printRollbacks(rollbacks)
+		log.Printf("RollbackSchemaTask dryrun done, no statement was executed\n")
+		return nil
+	}
+
+	if err := task.executeRollbacks(rollbacks); err != nil {
+		return err
+	}
+
+	log.Printf("RollbackSchemaTask done\n")
+
+	return nil
+}
+
+func (task *RollbackTask) executeRollbacks(rollbacks []rollbackSet) error {
+
+	for _, rs := range rollbacks {
+
+		log.Printf("---- Executing rollback of version %v ----\n", rs.version)
+		for _, stmt := range rs.stmts {
+			log.Println(rmspaceRegex.ReplaceAllString(stmt, " "))
+			if err := task.db.Exec(stmt); err != nil {
+				return fmt.Errorf("error executing statement:%v", err)
+			}
+		}
+		log.Printf("---- Done ----\n")
+
+		if err := task.db.UpdateSchemaVersion(rs.prevVersion, rs.prevMinCompatibleVersion); err != nil {
+			return fmt.Errorf("failed to update schema_version table, err=%v", err.Error())
+		}
+		if err := task.db.WriteSchemaUpdateLog(
+			rs.version,
+			rs.prevVersion,
+			rs.checksum,
+			"rollback: "+rs.manifest.Description,
+		); err != nil {
+			return fmt.Errorf("failed to add entry to schema_update_history, err=%v", err.Error())
+		}
+
+		log.Printf("Schema rolled back from %v to %v\n", rs.version, rs.prevVersion)
+	}
+
+	return nil
+}
+
+func (task *RollbackTask) printRollbacks(rollbacks []rollbackSet) {
+	for _, rs := range rollbacks {
+		log.Printf("---- Planned rollback from version %v to %v ----\n", rs.version, rs.prevVersion)
+		for _, stmt := range rs.stmts {
+			log.Println(rmspaceRegex.ReplaceAllString(stmt, " "))
+		}
+	}
+}
+
+// buildRollbackSet returns the rollbacks of all versions in the range
+// targetVer < ver <= currVer, ordered from the highest to the lowest version
+func (task *RollbackTask) buildRollbackSet(currVer string) ([]rollbackSet, error) {
+
+	config := task.config
+
+	verDirs, err := readSchemaDir(config.SchemaDir, config.TargetVersion, currVer)
+	if err != nil {
+		return nil, fmt.Errorf("error listing schema dir:%v", err.Error())
+	}
+
+	prevVersion := config.TargetVersion
+	prevMinCompatibleVersion := config.TargetVersion
+	if m, err := readManifest(config.SchemaDir + "/v" + config.TargetVersion); err == nil {
+		prevMinCompatibleVersion = m.MinCompatibleVersion
+	}
+
+	result := make([]rollbackSet, len(verDirs))
+
+	for i, vd := range verDirs {
+
+		dirPath := config.SchemaDir + "/" + vd
+
+		m, e := readManifest(dirPath)
+		if e != nil {
+			return nil, fmt.Errorf("error processing manifest for version %v:%v", vd, e.Error())
+		}
+
+		if m.CurrVersion != dirToVersion(vd) {
+			return nil, fmt.Errorf("manifest version doesn't match with dirname, dir=%v,manifest.version=%v",
+				vd, m.CurrVersion)
+		}
+
+		if len(m.SchemaRollbackCqlFiles) == 0 {
+			return nil, fmt.Errorf("manifest for version %v does not declare SchemaRollbackCqlFiles", vd)
+		}
+
+		stmts, e := parseStmtFiles(dirPath, m.SchemaRollbackCqlFiles)
+		if e != nil {
+			return nil, e
+		}
+
+		e = validateStmtPrefixes(stmts, whitelistedRollbackPrefixes)
+		if e != nil {
+			return nil, fmt.Errorf("error processing rollback of version %v:%v", vd, e.Error())
+		}
+
+		checksum, e := scriptsChecksum(dirPath, m.SchemaUpdateCqlFiles)
+		if e != nil {
+			return nil, e
+		}
+
+		// rollbacks are executed in the reverse order of the updates
+		result[len(verDirs)-1-i] = rollbackSet{
+			version:                  m.CurrVersion,
+			prevVersion:              prevVersion,
+			prevMinCompatibleVersion: prevMinCompatibleVersion,
+			manifest:                 m,
+			manifestMD5:              m.md5,
+			checksum:                 checksum,
+			stmts:                    stmts,
+		}
+		prevVersion = m.CurrVersion
+		prevMinCompatibleVersion = m.MinCompatibleVersion
+	}
+
+	return result, nil
+}
+
+// verifyChecksums makes sure that the update scripts of the versions to roll back
+// were not modified after they were applied, since the rollback scripts are written
+// against the update scripts in the schema dir
+func (task *RollbackTask) verifyChecksums(rollbacks []rollbackSet) error {
+
+	entries, err := task.db.ReadSchemaUpdateLog()
+	if err != nil {
+		return fmt.Errorf("error reading schema update history:%v", err.Error())
+	}
+
+	for _, rs := range rollbacks {
+
+		applied, found := latestUpdateLogEntry(entries, rs.version)
+		if !found {
+			log.Printf("no schema update history found for version %v, skip checksum verification\n", rs.version)
+			continue
+		}
+
+		// entries recorded by older versions of the tool hold the md5 of the manifest only
+		if applied.ManifestMD5 != rs.checksum && applied.ManifestMD5 != rs.manifestMD5 {
+			return fmt.Errorf("schema files of version %v were modified after they were applied, "+
+				"recorded checksum=%v, current checksum=%v", rs.version, applied.ManifestMD5, rs.checksum)
+		}
+	}
+
+	return nil
+}
+
+// latestUpdateLogEntry returns the latest entry which updated the schema to the given version
+func latestUpdateLogEntry(entries []SchemaUpdateLogEntry, version string) (SchemaUpdateLogEntry, bool) {
+	var result SchemaUpdateLogEntry
+	found := false
+	for _, entry := range entries {
+		if entry.NewVersion != version || cmpVersion(entry.OldVersion, entry.NewVersion) >= 0 {
+			continue
+		}
+		if !found || entry.UpdateTime.After(result.UpdateTime) {
+			result = entry
+			found = true
+		}
+	}
+	return result, found
+}
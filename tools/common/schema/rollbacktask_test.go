@@ -0,0 +1,228 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package schema
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	RollbackTaskTestSuite struct {
+		*require.Assertions // override suite.Suite.Assertions with require.Assertions; this means that s.NotNil(nil) will stop the test, not merely log an error
+		suite.Suite
+		schemaDir string
+	}
+
+	fakeSchemaDB struct {
+		version    string
+		minVersion string
+		stmts      []string
+		updateLog  []SchemaUpdateLogEntry
+	}
+)
+
+func TestRollbackTaskTestSuite(t *testing.T) {
+	suite.Run(t, new(RollbackTaskTestSuite))
+}
+
+func (s *RollbackTaskTestSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	var err error
+	s.schemaDir, err = ioutil.TempDir("", "rollback_schema_test")
+	s.NoError(err)
+
+	s.writeVersion("1.0", "1.0", `["base.cql"]`, ``)
+	s.writeFile("v1.0/base.cql", "CREATE TABLE events(id int, PRIMARY KEY (id));")
+	s.writeVersion("2.0", "1.0", `["domains.cql"]`, `["domains_down.cql"]`)
+	s.writeFile("v2.0/domains.cql", "CREATE TABLE domains(id int, PRIMARY KEY (id));")
+	s.writeFile("v2.0/domains_down.cql", "DROP TABLE domains;")
+	s.writeVersion("3.0", "2.0", `["tasks.cql"]`, `["tasks_down.cql"]`)
+	s.writeFile("v3.0/tasks.cql", "CREATE TABLE tasks(id int, PRIMARY KEY (id));\nALTER TABLE domains ADD name text;")
+	s.writeFile("v3.0/tasks_down.cql", "ALTER TABLE domains DROP name;\nDROP TABLE tasks;")
+}
+
+func (s *RollbackTaskTestSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.schemaDir))
+}
+
+func (s *RollbackTaskTestSuite) TestRollback() {
+	db := s.newUpdatedDB()
+
+	err := newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "1.0"}).Run()
+	s.NoError(err)
+
+	s.Equal("1.0", db.version)
+	s.Equal("1.0", db.minVersion)
+	s.Equal([]string{
+		"ALTER TABLE domains DROP name;",
+		"DROP TABLE tasks;",
+		"DROP TABLE domains;",
+	}, db.stmts)
+
+	s.Len(db.updateLog, 4)
+	s.Equal("3.0", db.updateLog[2].OldVersion)
+	s.Equal("2.0", db.updateLog[2].NewVersion)
+	s.Equal("2.0", db.updateLog[3].OldVersion)
+	s.Equal("1.0", db.updateLog[3].NewVersion)
+}
+
+func (s *RollbackTaskTestSuite) TestRollback_DryRun() {
+	db := s.newUpdatedDB()
+
+	err := newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "2.0", IsDryRun: true}).Run()
+	s.NoError(err)
+
+	s.Equal("3.0", db.version)
+	s.Empty(db.stmts)
+	s.Len(db.updateLog, 2)
+}
+
+func (s *RollbackTaskTestSuite) TestRollback_InvalidTargetVersion() {
+	db := s.newUpdatedDB()
+
+	err := newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "3.0"}).Run()
+	s.Error(err)
+	s.Equal("3.0", db.version)
+}
+
+func (s *RollbackTaskTestSuite) TestRollback_MissingRollbackFiles() {
+	db := s.newUpdatedDB()
+
+	err := newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "0.0"}).Run()
+	s.Error(err)
+	s.Equal("3.0", db.version)
+	s.Empty(db.stmts)
+}
+
+func (s *RollbackTaskTestSuite) TestRollback_ChecksumMismatch() {
+	db := s.newUpdatedDB()
+	s.writeFile("v3.0/tasks.cql", "CREATE TABLE tasks(id int, name text, PRIMARY KEY (id));")
+
+	err := newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "2.0"}).Run()
+	s.Error(err)
+	s.Equal("3.0", db.version)
+	s.Empty(db.stmts)
+}
+
+func (s *RollbackTaskTestSuite) TestRollback_LegacyChecksum() {
+	db := s.newUpdatedDB()
+	m, err := readManifest(s.schemaDir + "/v3.0")
+	s.NoError(err)
+	db.updateLog[1].ManifestMD5 = m.md5
+
+	err = newRollbackSchemaTask(db, &RollbackConfig{SchemaDir: s.schemaDir, TargetVersion: "2.0"}).Run()
+	s.NoError(err)
+	s.Equal("2.0", db.version)
+}
+
+func (s *RollbackTaskTestSuite) TestLatestUpdateLogEntry() {
+	now := time.Now()
+	entries := []SchemaUpdateLogEntry{
+		{UpdateTime: now.Add(-time.Hour), OldVersion: "1.0", NewVersion: "2.0", ManifestMD5: "a"},
+		{UpdateTime: now.Add(-time.Minute), OldVersion: "3.0", NewVersion: "2.0", ManifestMD5: "b"},
+		{UpdateTime: now, OldVersion: "1.0", NewVersion: "2.0", ManifestMD5: "c"},
+	}
+
+	entry, found := latestUpdateLogEntry(entries, "2.0")
+	s.True(found)
+	s.Equal("c", entry.ManifestMD5)
+
+	_, found = latestUpdateLogEntry(entries, "3.0")
+	s.False(found)
+}
+
+// newUpdatedDB returns a db which was updated from 1.0 to 3.0 with the current schema dir
+func (s *RollbackTaskTestSuite) newUpdatedDB() *fakeSchemaDB {
+	db := &fakeSchemaDB{version: "3.0", minVersion: "2.0"}
+	for i, ver := range []string{"2.0", "3.0"} {
+		m, err := readManifest(s.schemaDir + "/v" + ver)
+		s.NoError(err)
+		checksum, err := scriptsChecksum(s.schemaDir+"/v"+ver, m.SchemaUpdateCqlFiles)
+		s.NoError(err)
+		db.updateLog = append(db.updateLog, SchemaUpdateLogEntry{
+			UpdateTime:  time.Now().Add(time.Duration(i-2) * time.Minute),
+			OldVersion:  []string{"1.0", "2.0"}[i],
+			NewVersion:  ver,
+			ManifestMD5: checksum,
+		})
+	}
+	return db
+}
+
+func (s *RollbackTaskTestSuite) writeVersion(version, minVersion, updateFiles, rollbackFiles string) {
+	s.NoError(os.Mkdir(s.schemaDir+"/v"+version, os.FileMode(0700)))
+	manifest := `{"CurrVersion": "` + version + `", "MinCompatibleVersion": "` + minVersion +
+		`", "Description": "v` + version + `", "SchemaUpdateCqlFiles": ` + updateFiles
+	if len(rollbackFiles) > 0 {
+		manifest += `, "SchemaRollbackCqlFiles": ` + rollbackFiles
+	}
+	s.writeFile("v"+version+"/manifest.json", manifest+"}")
+}
+
+func (s *RollbackTaskTestSuite) writeFile(name, content string) {
+	s.NoError(ioutil.WriteFile(s.schemaDir+"/"+name, []byte(content), os.FileMode(0600)))
+}
+
+func (db *fakeSchemaDB) Exec(stmt string, args ...interface{}) error {
+	db.stmts = append(db.stmts, stmt)
+	return nil
+}
+
+func (db *fakeSchemaDB) DropAllTables() error {
+	return nil
+}
+
+func (db *fakeSchemaDB) CreateSchemaVersionTables() error {
+	return nil
+}
+
+func (db *fakeSchemaDB) ReadSchemaVersion() (string, error) {
+	return db.version, nil
+}
+
+func (db *fakeSchemaDB) UpdateSchemaVersion(newVersion string, minCompatibleVersion string) error {
+	db.version = newVersion
+	db.minVersion = minCompatibleVersion
+	return nil
+}
+
+func (db *fakeSchemaDB) WriteSchemaUpdateLog(oldVersion string, newVersion string, manifestMD5 string, desc string) error {
+	db.updateLog = append(db.updateLog, SchemaUpdateLogEntry{
+		UpdateTime:  time.Now(),
+		OldVersion:  oldVersion,
+		NewVersion:  newVersion,
+		ManifestMD5: manifestMD5,
+	})
+	return nil
+}
+
+func (db *fakeSchemaDB) ReadSchemaUpdateLog() ([]SchemaUpdateLogEntry, error) {
+	return db.updateLog, nil
+}
+
+func (db *fakeSchemaDB) Close() {}
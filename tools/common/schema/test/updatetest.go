@@ -112,6 +112,46 @@ func (tb *UpdateSchemaTestBase) RunUpdateSchemaTest(app *cli.App, db DB, dbNameF
 	tb.NoError(db.DropAllTables())
 }
 
+// RunRollbackSchemaTest tests schema rollback
+func (tb *UpdateSchemaTestBase) RunRollbackSchemaTest(app *cli.App, db DB, dbNameFlag string, sqlFileContent string, expectedTables []string) {
+	tmpDir, err := ioutil.TempDir("", "rollback_schema_test")
+	tb.Nil(err)
+	defer os.RemoveAll(tmpDir)
+
+	tb.makeSchemaVersionDirs(tmpDir, sqlFileContent)
+
+	tb.NoError(app.Run([]string{"./tool", dbNameFlag, tb.DBName, "-q", "setup-schema", "-v", "0.0"}))
+	tb.NoError(app.Run([]string{"./tool", dbNameFlag, tb.DBName, "-q", "update-schema", "-d", tmpDir, "-v", "2.0"}))
+
+	// dryrun must leave the schema untouched
+	tb.NoError(app.Run([]string{"./tool", dbNameFlag, tb.DBName, "-q", "rollback-schema", "-d", tmpDir, "-v", "1.0", "-y"}))
+	ver, err := db.ReadSchemaVersion()
+	tb.Nil(err)
+	tb.Equal("2.0", ver)
+
+	tb.NoError(app.Run([]string{"./tool", dbNameFlag, tb.DBName, "-q", "rollback-schema", "-d", tmpDir, "-v", "1.0"}))
+
+	ver, err = db.ReadSchemaVersion()
+	tb.Nil(err)
+	tb.Equal("1.0", ver)
+
+	expected := getExpectedTables(true, expectedTables)
+	tables, err := db.ListTables()
+	tb.Nil(err)
+	tb.Equal(len(expected), len(tables))
+	for _, t := range tables {
+		_, ok := expected[t]
+		tb.True(ok)
+	}
+
+	// v1.0 has no down scripts, the rollback must be rejected
+	tb.NoError(app.Run([]string{"./tool", dbNameFlag, tb.DBName, "-q", "rollback-schema", "-d", tmpDir, "-v", "0.0"}))
+	ver, err = db.ReadSchemaVersion()
+	tb.Nil(err)
+	tb.Equal("1.0", ver)
+	tb.NoError(db.DropAllTables())
+}
+
 func (tb *UpdateSchemaTestBase) makeSchemaVersionDirs(rootDir string, sqlFileContent string) {
 	mData := `{
 		"CurrVersion": "1.0",
@@ -131,7 +171,8 @@ func (tb *UpdateSchemaTestBase) makeSchemaVersionDirs(rootDir string, sqlFileCon
 		"CurrVersion": "2.0",
 		"MinCompatibleVersion": "1.0",
 		"Description": "v2 of schema",
-		"SchemaUpdateCqlFiles": ["domain.cql"]
+		"SchemaUpdateCqlFiles": ["domain.cql"],
+		"SchemaRollbackCqlFiles": ["domain_rollback.cql"]
 	}`
 
 	domain := `CREATE TABLE domains(
@@ -145,4 +186,6 @@ func (tb *UpdateSchemaTestBase) makeSchemaVersionDirs(rootDir string, sqlFileCon
 	tb.Nil(err)
 	err = ioutil.WriteFile(dir+"/domain.cql", []byte(domain), os.FileMode(0600))
 	tb.Nil(err)
+	err = ioutil.WriteFile(dir+"/domain_rollback.cql", []byte("DROP TABLE domains;"), os.FileMode(0600))
+	tb.Nil(err)
 }
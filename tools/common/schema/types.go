@@ -23,6 +23,7 @@ package schema
 import (
 	"fmt"
 	"regexp"
+	"time"
 )
 
 type (
@@ -39,6 +40,13 @@ type (
 		SchemaDir     string
 		IsDryRun      bool
 	}
+	// RollbackConfig holds the config
+	// params for executing a RollbackTask
+	RollbackConfig struct {
+		TargetVersion string
+		SchemaDir     string
+		IsDryRun      bool
+	}
 	// SetupConfig holds the config
 	// params need by the SetupTask
 	SetupConfig struct {
@@ -62,9 +70,19 @@ type (
 		UpdateSchemaVersion(newVersion string, minCompatibleVersion string) error
 		// WriteSchemaUpdateLog adds an entry to the schema update history table
 		WriteSchemaUpdateLog(oldVersion string, newVersion string, manifestMD5 string, desc string) error
+		// ReadSchemaUpdateLog returns all entries of the schema update history table
+		ReadSchemaUpdateLog() ([]SchemaUpdateLogEntry, error)
 		// Close gracefully closes the client object
 		Close()
 	}
+	// SchemaUpdateLogEntry is an entry of the
+	// schema update history table
+	SchemaUpdateLogEntry struct {
+		UpdateTime  time.Time
+		OldVersion  string
+		NewVersion  string
+		ManifestMD5 string
+	}
 )
 
 const (
@@ -50,7 +50,9 @@ type (
 		MinCompatibleVersion string
 		Description          string
 		SchemaUpdateCqlFiles []string
-		md5                  string
+		// SchemaRollbackCqlFiles optionally reverts the changes of SchemaUpdateCqlFiles
+		SchemaRollbackCqlFiles []string
+		md5                    string
 	}
 
 	// changeSet represents all the changes
@@ -59,6 +61,7 @@ type (
 		version  string
 		manifest *manifest
 		cqlStmts []string
+		checksum string
 	}
 
 	// byVersion is a comparator type
@@ -160,7 +163,7 @@ func (task *UpdateTask) updateSchemaVersion(oldVer string, cs *changeSet) error
 		return fmt.Errorf("failed to update schema_version table, err=%v", err.Error())
 	}
 
-	err = task.db.WriteSchemaUpdateLog(oldVer, cs.manifest.CurrVersion, cs.manifest.md5, cs.manifest.Description)
+	err = task.db.WriteSchemaUpdateLog(oldVer, cs.manifest.CurrVersion, cs.checksum, cs.manifest.Description)
 	if err != nil {
 		return fmt.Errorf("failed to add entry to schema_update_history, err=%v", err.Error())
 	}
@@ -203,10 +206,16 @@ func (task *UpdateTask) buildChangeSet(currVer string) ([]changeSet, error) {
 			return nil, fmt.Errorf("error processing version %v:%v", vd, e.Error())
 		}
 
+		checksum, e := scriptsChecksum(dirPath, m.SchemaUpdateCqlFiles)
+		if e != nil {
+			return nil, e
+		}
+
 		cs := changeSet{}
 		cs.manifest = m
 		cs.cqlStmts = stmts
 		cs.version = m.CurrVersion
+		cs.checksum = checksum
 		result = append(result, cs)
 	}
 
@@ -214,10 +223,14 @@ func (task *UpdateTask) buildChangeSet(currVer string) ([]changeSet, error) {
 }
 
 func (task *UpdateTask) parseSQLStmts(dir string, manifest *manifest) ([]string, error) {
+	return parseStmtFiles(dir, manifest.SchemaUpdateCqlFiles)
+}
+
+func parseStmtFiles(dir string, files []string) ([]string, error) {
 
 	result := make([]string, 0, 4)
 
-	for _, file := range manifest.SchemaUpdateCqlFiles {
+	for _, file := range files {
 		path := dir + "/" + file
 		stmts, err := ParseFile(path)
 		if err != nil {
@@ -234,9 +247,13 @@ func (task *UpdateTask) parseSQLStmts(dir string, manifest *manifest) ([]string,
 }
 
 func validateCQLStmts(stmts []string) error {
+	return validateStmtPrefixes(stmts, whitelistedCQLPrefixes[:])
+}
+
+func validateStmtPrefixes(stmts []string, whitelistedPrefixes []string) error {
 	for _, stmt := range stmts {
 		valid := false
-		for _, prefix := range whitelistedCQLPrefixes {
+		for _, prefix := range whitelistedPrefixes {
 			if strings.HasPrefix(stmt, prefix) {
 				valid = true
 				break
@@ -292,6 +309,22 @@ func readManifest(dirPath string) (*manifest, error) {
 	return &manifest, nil
 }
 
+// scriptsChecksum returns the md5 checksum of the content of the given schema files,
+// it is recorded in the schema update history to detect files modified after they were applied
+func scriptsChecksum(dir string, files []string) (string, error) {
+	// See comment above. This is an appropriate usage of md5.
+	// #nosec
+	hash := md5.New()
+	for _, file := range files {
+		content, err := ioutil.ReadFile(dir + "/" + file)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // readSchemaDir returns a sorted list of subdir names that hold
 // the schema changes for versions in the range startVer < ver <= endVer
 // when endVer is empty this method returns all subdir names that are greater than startVer
@@ -121,3 +121,20 @@ func getExpectedVersion(dir string) (string, error) {
 	}
 	return result, nil
 }
+
+// CmpVersion compares two version strings, see cmpVersion
+func CmpVersion(a, b string) int {
+	return cmpVersion(a, b)
+}
+
+// ParseValidateVersion validates that the given input conforms to either of vx.x or x.x and
+// returns x.x on success
+func ParseValidateVersion(ver string) (string, error) {
+	return parseValidateVersion(ver)
+}
+
+// ReadSchemaDir returns a sorted list of subdir names that hold the schema
+// changes for versions in the range startVer < ver <= endVer, see readSchemaDir
+func ReadSchemaDir(dir string, startVer string, endVer string) ([]string, error) {
+	return readSchemaDir(dir, startVer, endVer)
+}
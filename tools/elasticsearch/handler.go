@@ -0,0 +1,298 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+
+	es "github.com/temporalio/temporal/common/elasticsearch"
+	"github.com/temporalio/temporal/tools/common/schema"
+)
+
+const (
+	manifestFileName = "manifest.json"
+	// initialSchemaVersion is the schema version of an index which was not setup by the tool
+	initialSchemaVersion = "0.0"
+)
+
+type (
+	// manifest is the json blob describing a versioned index template update
+	manifest struct {
+		CurrVersion          string
+		MinCompatibleVersion string
+		Description          string
+		// IndexTemplateFile is the index template of this version, it replaces the current template
+		IndexTemplateFile string
+		// MappingUpdateFile is the optional typeless mapping update applied to the existing visibility index
+		MappingUpdateFile string
+	}
+
+	// indexConfig identifies the visibility index and its index template
+	indexConfig struct {
+		Index    string
+		Template string
+	}
+
+	// updateConfig holds the parameters of an update-schema run
+	updateConfig struct {
+		indexConfig
+		SchemaDir     string
+		TargetVersion string
+		IsDryRun      bool
+	}
+)
+
+// setupSchema puts the index template and creates the visibility index
+func setupSchema(cli *cli.Context) error {
+	filePath := cli.String(schema.CLIOptSchemaFile)
+	if filePath == "" {
+		return handleErr(schema.NewConfigError("missing " + flag(schema.CLIOptSchemaFile) + " argument "))
+	}
+	var version string
+	if cli.IsSet(schema.CLIOptVersion) {
+		var err error
+		if version, err = schema.ParseValidateVersion(cli.String(schema.CLIOptVersion)); err != nil {
+			return handleErr(schema.NewConfigError(err.Error()))
+		}
+	}
+	template, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return handleErr(err)
+	}
+	client, err := newClient(cli)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	config := newIndexConfig(cli)
+	if err := setupIndex(context.Background(), client, config, string(template), version); err != nil {
+		return handleErr(err)
+	}
+	log.Printf("index template %v and index %v setup\n", config.Template, config.Index)
+	return nil
+}
+
+// updateSchema applies the versioned index template updates
+// after the current schema version of the visibility index
+func updateSchema(cli *cli.Context) error {
+	config := &updateConfig{
+		indexConfig: newIndexConfig(cli),
+		SchemaDir:   cli.String(schema.CLIOptSchemaDir),
+		IsDryRun:    cli.Bool(schema.CLIOptDryrun),
+	}
+	if config.SchemaDir == "" {
+		return handleErr(schema.NewConfigError("missing " + flag(schema.CLIOptSchemaDir) + " argument "))
+	}
+	if targetVersion := cli.String(schema.CLIOptTargetVersion); targetVersion != "" {
+		var err error
+		if config.TargetVersion, err = schema.ParseValidateVersion(targetVersion); err != nil {
+			return handleErr(schema.NewConfigError(err.Error()))
+		}
+	}
+	client, err := newClient(cli)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	if err := updateIndex(context.Background(), client, config); err != nil {
+		return handleErr(err)
+	}
+	log.Printf("index %v schema is at the target version\n", config.Index)
+	return nil
+}
+
+// setupIndex puts the index template, creates the index if it doesn't exist
+// and records the schema version if one is given
+func setupIndex(
+	ctx context.Context,
+	client es.Client,
+	config indexConfig,
+	template string,
+	version string,
+) error {
+
+	if err := client.PutIndexTemplate(ctx, config.Template, template); err != nil {
+		return fmt.Errorf("error putting index template: %v", err)
+	}
+	exists, err := client.IndexExists(ctx, config.Index)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := client.CreateIndex(ctx, config.Index); err != nil {
+			return fmt.Errorf("error creating index: %v", err)
+		}
+	}
+
+	if version != "" {
+		if err := es.PutSchemaVersion(ctx, client, config.Index, version); err != nil {
+			return fmt.Errorf("error updating schema version: %v", err)
+		}
+	}
+	return nil
+}
+
+// updateIndex applies all the versions of the schema dir after the current
+// schema version of the index, up to the target version
+func updateIndex(
+	ctx context.Context,
+	client es.Client,
+	config *updateConfig,
+) error {
+
+	currVersion, err := es.GetSchemaVersion(ctx, client, config.Index)
+	if err != nil {
+		return fmt.Errorf("error reading current schema version: %v", err)
+	}
+	if currVersion == "" {
+		currVersion = initialSchemaVersion
+	}
+
+	versionDirs, err := schema.ReadSchemaDir(config.SchemaDir, currVersion, config.TargetVersion)
+	if err != nil {
+		return fmt.Errorf("error listing schema dir: %v", err)
+	}
+	for _, versionDir := range versionDirs {
+		if err := applyUpdate(ctx, client, config, filepath.Join(config.SchemaDir, versionDir), versionDir[1:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyUpdate(
+	ctx context.Context,
+	client es.Client,
+	config *updateConfig,
+	dir string,
+	version string,
+) error {
+	m, err := readManifest(dir)
+	if err != nil {
+		return fmt.Errorf("error processing manifest for version %v: %v", version, err)
+	}
+	if m.CurrVersion != version {
+		return fmt.Errorf("manifest version doesn't match with dirname, dir=%v, manifest.version=%v", dir, m.CurrVersion)
+	}
+
+	template, err := ioutil.ReadFile(filepath.Join(dir, m.IndexTemplateFile))
+	if err != nil {
+		return err
+	}
+	var mappingUpdate map[string]interface{}
+	if m.MappingUpdateFile != "" {
+		data, err := ioutil.ReadFile(filepath.Join(dir, m.MappingUpdateFile))
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &mappingUpdate); err != nil {
+			return fmt.Errorf("error parsing mapping update file: %v", err)
+		}
+	}
+
+	log.Printf("---- Executing updates for version %v: %v ----\n", version, m.Description)
+	if config.IsDryRun {
+		log.Printf("put index template %v from %v\n", config.Template, m.IndexTemplateFile)
+		if mappingUpdate != nil {
+			log.Printf("put mapping of index %v from %v\n", config.Index, m.MappingUpdateFile)
+		}
+		return nil
+	}
+
+	if err := client.PutIndexTemplate(ctx, config.Template, string(template)); err != nil {
+		return fmt.Errorf("error putting index template: %v", err)
+	}
+	exists, err := client.IndexExists(ctx, config.Index)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := client.CreateIndex(ctx, config.Index); err != nil {
+			return fmt.Errorf("error creating index: %v", err)
+		}
+	} else if mappingUpdate != nil {
+		if err := client.PutMappingBody(ctx, config.Index, mappingUpdate); err != nil {
+			return fmt.Errorf("error updating index mapping: %v", err)
+		}
+	}
+	if err := es.PutSchemaVersion(ctx, client, config.Index, m.CurrVersion); err != nil {
+		return fmt.Errorf("error updating schema version: %v", err)
+	}
+	log.Printf("---- Done ----\n")
+	return nil
+}
+
+func readManifest(dir string) (*manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.CurrVersion, err = schema.ParseValidateVersion(m.CurrVersion); err != nil {
+		return nil, fmt.Errorf("invalid CurrVersion in manifest: %v", err)
+	}
+	if m.MinCompatibleVersion, err = schema.ParseValidateVersion(m.MinCompatibleVersion); err != nil {
+		return nil, fmt.Errorf("invalid MinCompatibleVersion in manifest: %v", err)
+	}
+	if m.IndexTemplateFile == "" {
+		return nil, fmt.Errorf("manifest missing IndexTemplateFile")
+	}
+	return &m, nil
+}
+
+func newIndexConfig(cli *cli.Context) indexConfig {
+	return indexConfig{
+		Index:    cli.GlobalString(cliOptIndex),
+		Template: cli.GlobalString(cliOptTemplate),
+	}
+}
+
+func newClient(cli *cli.Context) (es.Client, error) {
+	esURL, err := url.Parse(cli.GlobalString(cliOptURL))
+	if err != nil {
+		return nil, schema.NewConfigError("invalid " + flag(cliOptURL) + " argument: " + err.Error())
+	}
+	return es.NewClient(&es.Config{
+		URL:     *esURL,
+		Version: cli.GlobalString(cliOptESVersion),
+	})
+}
+
+func flag(opt string) string {
+	return "(-" + opt + ")"
+}
+
+func handleErr(err error) error {
+	log.Println(err)
+	return err
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/temporalio/temporal/common/elasticsearch/mocks"
+)
+
+type (
+	HandlerTestSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		client    *mocks.Client
+		schemaDir string
+		config    indexConfig
+	}
+)
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+func (s *HandlerTestSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.client = &mocks.Client{}
+	s.config = indexConfig{Index: "test-index", Template: "test-template"}
+
+	var err error
+	s.schemaDir, err = ioutil.TempDir("", "es_handler_test")
+	s.NoError(err)
+	s.writeVersion("v0.1", `{"CurrVersion": "0.1", "MinCompatibleVersion": "0.1", "IndexTemplateFile": "index_template.json"}`, "")
+	s.writeVersion("v0.2", `{"CurrVersion": "0.2", "MinCompatibleVersion": "0.1", "IndexTemplateFile": "index_template.json", "MappingUpdateFile": "mapping.json"}`,
+		`{"properties": {"NewField": {"type": "keyword"}}}`)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	s.client.AssertExpectations(s.T())
+	s.NoError(os.RemoveAll(s.schemaDir))
+}
+
+func (s *HandlerTestSuite) TestSetupIndex_NewIndex() {
+	ctx := context.Background()
+	s.client.On("PutIndexTemplate", ctx, s.config.Template, "template body").Return(nil).Once()
+	s.client.On("IndexExists", ctx, s.config.Index).Return(false, nil).Once()
+	s.client.On("CreateIndex", ctx, s.config.Index).Return(nil).Once()
+	s.client.On("PutMappingBody", ctx, s.config.Index, schemaVersionBody("0.1")).Return(nil).Once()
+
+	s.NoError(setupIndex(ctx, s.client, s.config, "template body", "0.1"))
+}
+
+func (s *HandlerTestSuite) TestSetupIndex_ExistingIndexWithoutVersion() {
+	ctx := context.Background()
+	s.client.On("PutIndexTemplate", ctx, s.config.Template, "template body").Return(nil).Once()
+	s.client.On("IndexExists", ctx, s.config.Index).Return(true, nil).Once()
+
+	s.NoError(setupIndex(ctx, s.client, s.config, "template body", ""))
+}
+
+func (s *HandlerTestSuite) TestUpdateIndex() {
+	ctx := context.Background()
+	s.client.On("GetMapping", ctx, s.config.Index).Return(schemaVersionBody("0.1"), nil).Once()
+	s.client.On("PutIndexTemplate", ctx, s.config.Template, "template v0.2").Return(nil).Once()
+	s.client.On("IndexExists", ctx, s.config.Index).Return(true, nil).Once()
+	s.client.On("PutMappingBody", ctx, s.config.Index, map[string]interface{}{
+		"properties": map[string]interface{}{"NewField": map[string]interface{}{"type": "keyword"}},
+	}).Return(nil).Once()
+	s.client.On("PutMappingBody", ctx, s.config.Index, schemaVersionBody("0.2")).Return(nil).Once()
+
+	s.NoError(updateIndex(ctx, s.client, &updateConfig{indexConfig: s.config, SchemaDir: s.schemaDir}))
+}
+
+func (s *HandlerTestSuite) TestUpdateIndex_UnversionedIndex() {
+	ctx := context.Background()
+	s.client.On("GetMapping", ctx, s.config.Index).Return(map[string]interface{}{}, nil).Once()
+	s.client.On("PutIndexTemplate", ctx, s.config.Template, "template v0.1").Return(nil).Once()
+	s.client.On("IndexExists", ctx, s.config.Index).Return(false, nil).Once()
+	s.client.On("CreateIndex", ctx, s.config.Index).Return(nil).Once()
+	s.client.On("PutMappingBody", ctx, s.config.Index, schemaVersionBody("0.1")).Return(nil).Once()
+
+	s.NoError(updateIndex(ctx, s.client, &updateConfig{indexConfig: s.config, SchemaDir: s.schemaDir, TargetVersion: "0.1"}))
+}
+
+func (s *HandlerTestSuite) TestUpdateIndex_DryRun() {
+	ctx := context.Background()
+	s.client.On("GetMapping", ctx, s.config.Index).Return(schemaVersionBody("0.1"), nil).Once()
+
+	s.NoError(updateIndex(ctx, s.client, &updateConfig{indexConfig: s.config, SchemaDir: s.schemaDir, IsDryRun: true}))
+	s.client.AssertNotCalled(s.T(), "PutIndexTemplate", mock.Anything, mock.Anything, mock.Anything)
+	s.client.AssertNotCalled(s.T(), "PutMappingBody", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestUpdateIndex_ManifestVersionMismatch() {
+	ctx := context.Background()
+	s.writeVersion("v0.3", `{"CurrVersion": "0.4", "MinCompatibleVersion": "0.1", "IndexTemplateFile": "index_template.json"}`, "")
+	s.client.On("GetMapping", ctx, s.config.Index).Return(schemaVersionBody("0.2"), nil).Once()
+
+	s.Error(updateIndex(ctx, s.client, &updateConfig{indexConfig: s.config, SchemaDir: s.schemaDir}))
+}
+
+func (s *HandlerTestSuite) TestReadManifest_Invalid() {
+	s.writeVersion("v0.3", `{"CurrVersion": "0.3", "MinCompatibleVersion": "0.1"}`, "")
+	_, err := readManifest(filepath.Join(s.schemaDir, "v0.3"))
+	s.Error(err)
+
+	s.writeVersion("v0.4", `{"CurrVersion": "abc", "MinCompatibleVersion": "0.1", "IndexTemplateFile": "index_template.json"}`, "")
+	_, err = readManifest(filepath.Join(s.schemaDir, "v0.4"))
+	s.Error(err)
+}
+
+func (s *HandlerTestSuite) writeVersion(dirName string, manifest string, mappingUpdate string) {
+	dir := filepath.Join(s.schemaDir, dirName)
+	s.NoError(os.MkdirAll(dir, 0755))
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, manifestFileName), []byte(manifest), 0644))
+	s.NoError(ioutil.WriteFile(filepath.Join(dir, "index_template.json"), []byte("template "+dirName), 0644))
+	if mappingUpdate != "" {
+		s.NoError(ioutil.WriteFile(filepath.Join(dir, "mapping.json"), []byte(mappingUpdate), 0644))
+	}
+}
+
+func schemaVersionBody(version string) map[string]interface{} {
+	return map[string]interface{}{
+		"_meta": map[string]interface{}{
+			"schema_version": version,
+		},
+	}
+}
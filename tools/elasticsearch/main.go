@@ -0,0 +1,150 @@
+// Copyright (c) 2020 Temporal Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package elasticsearch
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+
+	es "github.com/temporalio/temporal/common/elasticsearch"
+	"github.com/temporalio/temporal/tools/common/schema"
+)
+
+const (
+	// cliOptURL is the cli option for the ElasticSearch url
+	cliOptURL = "url"
+	// cliOptESVersion is the cli option for the ElasticSearch version
+	cliOptESVersion = "es-version"
+	// cliOptIndex is the cli option for the visibility index name
+	cliOptIndex = "index"
+	// cliOptTemplate is the cli option for the index template name
+	cliOptTemplate = "template"
+
+	// cliFlagURL is the cli flag for the ElasticSearch url
+	cliFlagURL = cliOptURL + ", u"
+	// cliFlagESVersion is the cli flag for the ElasticSearch version
+	cliFlagESVersion = cliOptESVersion + ", ev"
+	// cliFlagIndex is the cli flag for the visibility index name
+	cliFlagIndex = cliOptIndex + ", i"
+	// cliFlagTemplate is the cli flag for the index template name
+	cliFlagTemplate = cliOptTemplate + ", t"
+)
+
+// RunTool runs the temporal-es-tool command line tool
+func RunTool(args []string) error {
+	app := BuildCLIOptions()
+	return app.Run(args)
+}
+
+// root handler for all cli commands
+func cliHandler(c *cli.Context, handler func(c *cli.Context) error) {
+	quiet := c.GlobalBool(schema.CLIOptQuiet)
+	err := handler(c)
+	if err != nil && !quiet {
+		os.Exit(1)
+	}
+}
+
+// BuildCLIOptions builds the options for cli
+func BuildCLIOptions() *cli.App {
+
+	app := cli.NewApp()
+	app.Name = "temporal-es-tool"
+	app.Usage = "Command line tool for temporal elasticsearch operations"
+	app.Version = "0.0.1"
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   cliFlagURL,
+			Value:  "http://127.0.0.1:9200",
+			Usage:  "url of elasticsearch to connect to",
+			EnvVar: "ES_URL",
+		},
+		cli.StringFlag{
+			Name:   cliFlagESVersion,
+			Value:  es.VersionV6,
+			Usage:  "major version of elasticsearch, one of v6 or v7",
+			EnvVar: "ES_VERSION",
+		},
+		cli.StringFlag{
+			Name:   cliFlagIndex,
+			Value:  "temporal-visibility-dev",
+			Usage:  "name of the visibility index",
+			EnvVar: "ES_VISIBILITY_INDEX",
+		},
+		cli.StringFlag{
+			Name:   cliFlagTemplate,
+			Value:  "temporal-visibility-template",
+			Usage:  "name of the visibility index template",
+			EnvVar: "ES_VISIBILITY_TEMPLATE",
+		},
+		cli.BoolFlag{
+			Name:  schema.CLIFlagQuiet,
+			Usage: "Don't set exit status to 1 on error",
+		},
+	}
+
+	app.Commands = []cli.Command{
+		{
+			Name:    "setup-schema",
+			Aliases: []string{"setup"},
+			Usage:   "setup index template and visibility index",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  schema.CLIFlagVersion,
+					Usage: "initial version of the index schema, defaults to the version of the index template",
+				},
+				cli.StringFlag{
+					Name:  schema.CLIFlagSchemaFile,
+					Usage: "path to the index template file",
+				},
+			},
+			Action: func(c *cli.Context) {
+				cliHandler(c, setupSchema)
+			},
+		},
+		{
+			Name:    "update-schema",
+			Aliases: []string{"update"},
+			Usage:   "update index template and visibility index mapping to a specific version",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  schema.CLIFlagTargetVersion,
+					Usage: "target version for the schema update, defaults to latest",
+				},
+				cli.StringFlag{
+					Name:  schema.CLIFlagSchemaDir,
+					Usage: "path to directory containing versioned index templates",
+				},
+				cli.BoolFlag{
+					Name:  schema.CLIFlagDryrun,
+					Usage: "do a dryrun, only print the updates",
+				},
+			},
+			Action: func(c *cli.Context) {
+				cliHandler(c, updateSchema)
+			},
+		},
+	}
+
+	return app
+}
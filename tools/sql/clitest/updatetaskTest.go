@@ -68,6 +68,14 @@ func (s *UpdateSchemaTestSuite) TestUpdateSchema() {
 	s.RunUpdateSchemaTest(sql.BuildCLIOptions(), conn, "--db", createTestSQLFileContent(), []string{"task_maps", "tasks"})
 }
 
+// TestRollbackSchema test
+func (s *UpdateSchemaTestSuite) TestRollbackSchema() {
+	conn, err := newTestConn(s.DBName, s.pluginName)
+	s.Nil(err)
+	defer conn.Close()
+	s.RunRollbackSchemaTest(sql.BuildCLIOptions(), conn, "--db", createTestSQLFileContent(), []string{"task_maps", "tasks"})
+}
+
 // TestDryrun test
 func (s *UpdateSchemaTestSuite) TestDryrun() {
 	conn, err := newTestConn(s.DBName, s.pluginName)
@@ -70,6 +70,24 @@ func (c *Connection) WriteSchemaUpdateLog(oldVersion string, newVersion string,
 	return c.adminDb.WriteSchemaUpdateLog(oldVersion, newVersion, manifestMD5, desc)
 }
 
+// ReadSchemaUpdateLog returns all entries of the schema update history table
+func (c *Connection) ReadSchemaUpdateLog() ([]schema.SchemaUpdateLogEntry, error) {
+	rows, err := c.adminDb.ReadSchemaUpdateLog()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]schema.SchemaUpdateLogEntry, len(rows))
+	for i, row := range rows {
+		result[i] = schema.SchemaUpdateLogEntry{
+			UpdateTime:  row.UpdateTime,
+			OldVersion:  row.OldVersion,
+			NewVersion:  row.NewVersion,
+			ManifestMD5: row.ManifestMD5,
+		}
+	}
+	return result, nil
+}
+
 // Exec executes a sql statement
 func (c *Connection) Exec(stmt string, args ...interface{}) error {
 	err := c.adminDb.Exec(stmt, args...)
@@ -115,6 +115,24 @@ func updateSchema(cli *cli.Context) error {
 	return nil
 }
 
+// rollbackSchema executes the rollbackSchemaTask
+// using the given command line args as input
+func rollbackSchema(cli *cli.Context) error {
+	cfg, err := parseConnectConfig(cli)
+	if err != nil {
+		return handleErr(schema.NewConfigError(err.Error()))
+	}
+	conn, err := NewConnection(cfg)
+	if err != nil {
+		return handleErr(err)
+	}
+	defer conn.Close()
+	if err := schema.Rollback(cli, conn); err != nil {
+		return handleErr(err)
+	}
+	return nil
+}
+
 // createDatabase creates a sql database
 func createDatabase(cli *cli.Context) error {
 	cfg, err := parseConnectConfig(cli)